@@ -132,6 +132,46 @@ export const useCreateDocument = (clientId: string) =>
 			createDocument(clientId, params),
 	})
 
+interface InstantiateDocumentTemplateInputParams {
+	templateId: string
+	title?: string
+	lease_id?: string
+	tenant_application_id?: string
+	lease_termination_id?: string
+}
+
+export const instantiateDocumentTemplate = async (
+	clientId: string,
+	{ templateId, ...params }: InstantiateDocumentTemplateInputParams,
+) => {
+	try {
+		const response = await fetchClient<ApiResponse<RentloopDocument>>(
+			`/v1/admin/clients/${clientId}/documents/${templateId}/instantiate`,
+			{
+				method: 'POST',
+				body: JSON.stringify(params),
+			},
+		)
+
+		return response.parsedBody.data
+	} catch (error: unknown) {
+		if (error instanceof Response) {
+			const response = await error.json()
+			throw new Error(response.errors?.message || 'Unknown error')
+		}
+
+		if (error instanceof Error) {
+			throw error
+		}
+	}
+}
+
+export const useInstantiateDocumentTemplate = (clientId: string) =>
+	useMutation({
+		mutationFn: (params: InstantiateDocumentTemplateInputParams) =>
+			instantiateDocumentTemplate(clientId, params),
+	})
+
 interface AdminUpdateDocumentInputParams {
 	clientId: string
 	id: string
@@ -26,6 +26,12 @@ const TEMPLATE_FIELDS = [
 	{ label: 'Landlord Email', value: 'LandlordEmail' },
 	{ label: 'Landlord Phone Number', value: 'LandlordPhoneNumber' },
 
+	{ label: 'Client Name', value: 'ClientName' },
+	{ label: 'Client Address', value: 'ClientAddress' },
+	{ label: 'Client Email', value: 'ClientEmail' },
+	{ label: 'Client Phone Number', value: 'ClientPhoneNumber' },
+	{ label: 'Client Registration Number', value: 'ClientRegistrationNumber' },
+
 	{ label: 'Tenant Name', value: 'TenantName' },
 	{ label: 'Tenant Address', value: 'TenantAddress' },
 	{ label: 'Tenant Email', value: 'TenantEmail' },
@@ -57,7 +63,10 @@ const TEMPLATE_FIELDS = [
 	{ label: 'Lease Start Date', value: 'LeaseStartDate' },
 	{ label: 'Lease End Date', value: 'LeaseEndDate' },
 	{ label: 'Lease Duration', value: 'LeaseDuration' },
+	{ label: 'Move In Date', value: 'MoveInDate' },
+	{ label: 'Move Out Date', value: 'MoveOutDate' },
 	{ label: 'Rent Amount', value: 'RentAmount' },
+	{ label: 'Rent Amount With Currency', value: 'RentAmountWithCurrency' },
 	{ label: 'Rent Amount In Words', value: 'RentAmountInWords' },
 	{ label: 'Rent Frequency', value: 'RentFrequency' },
 	{ label: 'Security Deposit', value: 'SecurityDeposit' },
@@ -67,6 +76,11 @@ const TEMPLATE_FIELDS = [
 	{ label: 'Payment Account Details', value: 'PaymentAccountDetails' },
 	{ label: 'Agreement Date', value: 'AgreementDate' },
 
+	{ label: 'Termination Code', value: 'TerminationCode' },
+	{ label: 'Termination Reason', value: 'TerminationReason' },
+	{ label: 'Notice Date', value: 'NoticeDate' },
+	{ label: 'Early Termination Fee', value: 'EarlyTerminationFee' },
+
 	{ label: 'Landlord Signed On', value: 'LandlordSignedOn' },
 	{ label: 'Tenant Signed On', value: 'TenantSignedOn' },
 	{ label: 'Landlord Witness Name', value: 'LandlordWitnessName' },
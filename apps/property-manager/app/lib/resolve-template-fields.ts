@@ -8,6 +8,12 @@ export type DocumentTemplateFieldMap = {
 	LandlordName?: string
 	LandlordEmail?: string
 	LandlordPhoneNumber?: string
+	// Client
+	ClientName?: string
+	ClientAddress?: string
+	ClientEmail?: string
+	ClientPhoneNumber?: string
+	ClientRegistrationNumber?: string
 	// Tenant
 	TenantName?: string
 	TenantAddress?: string
@@ -35,11 +41,19 @@ export type DocumentTemplateFieldMap = {
 	LeaseStartDate?: string
 	LeaseDuration?: string
 	LeaseEndDate?: string
+	MoveInDate?: string
+	MoveOutDate?: string
 	RentAmount?: string
+	RentAmountWithCurrency?: string
 	RentAmountInWords?: string
 	RentFrequency?: string
 	SecurityDeposit?: string
 	InitialDeposit?: string
+	// Lease termination
+	TerminationCode?: string
+	TerminationReason?: string
+	NoticeDate?: string
+	EarlyTerminationFee?: string
 	// Signing timestamps
 	LandlordSignedOn?: string
 	TenantSignedOn?: string
@@ -108,6 +122,7 @@ export function buildLeaseFieldMap(
 	set('ApplicationCode', lease.tenant_application?.code)
 	if (lease.move_in_date) {
 		set('LeaseStartDate', dayjs(lease.move_in_date).format('MMM D, YYYY'))
+		set('MoveInDate', dayjs(lease.move_in_date).format('MMM D, YYYY'))
 	}
 	if (lease.stay_duration && lease.stay_duration_frequency) {
 		set(
@@ -116,16 +131,19 @@ export function buildLeaseFieldMap(
 		)
 		const unit = frequencyToDayjsUnit(lease.stay_duration_frequency)
 		if (unit && lease.move_in_date) {
-			set(
-				'LeaseEndDate',
-				dayjs(lease.move_in_date)
-					.add(lease.stay_duration, unit)
-					.format('MMM D, YYYY'),
-			)
+			const leaseEndDate = dayjs(lease.move_in_date)
+				.add(lease.stay_duration, unit)
+				.format('MMM D, YYYY')
+			set('LeaseEndDate', leaseEndDate)
+			set('MoveOutDate', leaseEndDate)
 		}
 	}
 	if (lease.rent_fee) {
 		set('RentAmount', formatAmountWithoutCurrency(lease.rent_fee))
+		set(
+			'RentAmountWithCurrency',
+			formatAmount(lease.rent_fee, lease.rent_fee_currency),
+		)
 		set('RentAmountInWords', numberToWords(lease.rent_fee))
 	}
 	if (lease.payment_frequency) {
@@ -218,6 +236,10 @@ export function buildTenantApplicationFieldMap(
 	set('ApplicationCode', app.code)
 	if (app.desired_move_in_date) {
 		set('LeaseStartDate', dayjs(app.desired_move_in_date).format('MMM D, YYYY'))
+		set(
+			'MoveInDate',
+			dayjs(app.desired_move_in_date).format('MMM D, YYYY'),
+		)
 	}
 	if (app.stay_duration && app.stay_duration_frequency) {
 		set(
@@ -232,16 +254,19 @@ export function buildTenantApplicationFieldMap(
 	) {
 		const unit = frequencyToDayjsUnit(app.stay_duration_frequency)
 		if (unit) {
-			set(
-				'LeaseEndDate',
-				dayjs(app.desired_move_in_date)
-					.add(app.stay_duration, unit)
-					.format('MMM D, YYYY'),
-			)
+			const leaseEndDate = dayjs(app.desired_move_in_date)
+				.add(app.stay_duration, unit)
+				.format('MMM D, YYYY')
+			set('LeaseEndDate', leaseEndDate)
+			set('MoveOutDate', leaseEndDate)
 		}
 	}
 	if (app.rent_fee) {
 		set('RentAmount', formatAmountWithoutCurrency(app.rent_fee))
+		set(
+			'RentAmountWithCurrency',
+			formatAmount(app.rent_fee, app.rent_fee_currency),
+		)
 		set('RentAmountInWords', numberToWords(app.rent_fee))
 	}
 	if (app.payment_frequency) {
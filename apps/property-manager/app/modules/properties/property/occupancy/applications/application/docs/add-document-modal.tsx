@@ -3,7 +3,10 @@ import { useState } from 'react'
 import { useRevalidator } from 'react-router'
 import { DocumentList } from './document-list'
 import type { AttachedDocument, DocMode } from './types'
-import { useCreateDocument } from '~/api/documents'
+import {
+	useCreateDocument,
+	useInstantiateDocumentTemplate,
+} from '~/api/documents'
 import { useAdminUpdateTenantApplication } from '~/api/tenant-applications'
 import { Alert, AlertDescription, AlertTitle } from '~/components/ui/alert'
 import { Button } from '~/components/ui/button'
@@ -53,10 +56,12 @@ export function AddDocumentModal({
 
 	const { mutateAsync: createDocument, isPending: isCreating } =
 		useCreateDocument(safeString(clientUser?.client_id))
+	const { mutateAsync: instantiateTemplate, isPending: isInstantiating } =
+		useInstantiateDocumentTemplate(safeString(clientUser?.client_id))
 	const { mutateAsync: updateTenantApplication, isPending: isUpdating } =
 		useAdminUpdateTenantApplication()
 
-	const isSaving = isCreating || isUpdating
+	const isSaving = isCreating || isInstantiating || isUpdating
 
 	const canSave =
 		mode === 'online' ? Boolean(selectedDocument) : Boolean(uploadedUrl)
@@ -81,14 +86,24 @@ export function AddDocumentModal({
 		} else {
 			if (!selectedDocument) return
 
-			const newDoc = await createDocument({
-				title: `${application.code} - Lease Agreement`,
-				content: selectedDocument.content,
-				size: selectedDocument.size,
-				tags: selectedDocument.tags,
-				property_id: propertyId,
-				type: 'DOCUMENT',
-			})
+			const title = `${application.code} - Lease Agreement`
+			// Templates are instantiated server-side so their merge fields are
+			// filled in from the application; the empty document has none.
+			const newDoc =
+				selectedDocument.type === 'TEMPLATE'
+					? await instantiateTemplate({
+							templateId: selectedDocument.id,
+							title,
+							tenant_application_id: application.id,
+						})
+					: await createDocument({
+							title,
+							content: selectedDocument.content,
+							size: selectedDocument.size,
+							tags: selectedDocument.tags,
+							property_id: propertyId,
+							type: 'DOCUMENT',
+						})
 
 			if (!newDoc) return
 
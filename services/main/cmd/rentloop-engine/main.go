@@ -71,7 +71,7 @@ func main() {
 	})
 	handlers := handlers.NewHandlers(appCtx, services)
 
-	queue.RegisterWorkers(cfg.RedisDB.Url, appCtx, repository, services)
+	queue.RegisterWorkers(cfg.RedisDB.Url, queueClient, appCtx, repository, services)
 	queue.RegisterScheduler(cfg.RedisDB.Url)
 
 	r := router.New(appCtx, handlers)
@@ -0,0 +1,45 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddBookingModificationRefunds lets a booking have refunds for credits left
+// by changes to its stay besides the one for cancelling it. The full unique
+// index on booking_refunds.booking_id is replaced by a partial one over the
+// refunds that belong to no modification.
+func AddBookingModificationRefunds() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190007_ADD_BOOKING_MODIFICATION_REFUNDS",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.Exec(`DROP INDEX IF EXISTS idx_booking_refunds_booking_id`).Error; err != nil {
+				return err
+			}
+			if err := db.Exec(`
+				CREATE INDEX IF NOT EXISTS idx_booking_refunds_booking_id
+				ON booking_refunds (booking_id)
+			`).Error; err != nil {
+				return err
+			}
+			return db.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_refunds_one_cancellation_per_booking
+				ON booking_refunds (booking_id)
+				WHERE booking_modification_id IS NULL
+				  AND deleted_at IS NULL
+			`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			if err := db.Exec(`DROP INDEX IF EXISTS idx_booking_refunds_one_cancellation_per_booking`).Error; err != nil {
+				return err
+			}
+			if err := db.Exec(`DROP INDEX IF EXISTS idx_booking_refunds_booking_id`).Error; err != nil {
+				return err
+			}
+			return db.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_booking_refunds_booking_id
+				ON booking_refunds (booking_id)
+			`).Error
+		},
+	}
+}
@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+func AddChargeInstancePendingDueDateIndex() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190001_ADD_CHARGE_INSTANCE_PENDING_DUE_DATE_INDEX",
+		Migrate: func(db *gorm.DB) error {
+			// Partial index: only charges still waiting to be billed. The
+			// short-cadence scheduler asks "what is the next due date" after
+			// every run, and settled or voided history must not be in its way.
+			return db.Exec(`
+				CREATE INDEX IF NOT EXISTS idx_charge_instances_pending_due_date
+				ON charge_instances (due_date, financial_account_id)
+				WHERE voided_at IS NULL
+				  AND deleted_at IS NULL
+				  AND invoiced_amount <> amount
+				  AND settled_amount <> amount
+			`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`DROP INDEX IF EXISTS idx_charge_instances_pending_due_date`).Error
+		},
+	}
+}
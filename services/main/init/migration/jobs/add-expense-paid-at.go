@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddExpensePaidAt records when an expense was paid, so a scheduled one can be
+// settled later. Every existing unscheduled expense was paid, and posted, when
+// it was recorded.
+func AddExpensePaidAt() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190008_ADD_EXPENSE_PAID_AT",
+		Migrate: func(db *gorm.DB) error {
+			statements := []string{
+				`ALTER TABLE expenses ADD COLUMN IF NOT EXISTS paid_at TIMESTAMPTZ`,
+				`UPDATE expenses SET paid_at = created_at WHERE scheduled_for IS NULL AND paid_at IS NULL`,
+			}
+
+			for _, statement := range statements {
+				if err := db.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`ALTER TABLE expenses DROP COLUMN IF EXISTS paid_at`).Error
+		},
+	}
+}
@@ -0,0 +1,32 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddExpenseScheduledFor lets an expense be recorded before it is paid, so the
+// cash-flow forecast has outflows to set against expected rent. Existing rows
+// stay NULL: every one of them has already been paid.
+func AddExpenseScheduledFor() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190002_ADD_EXPENSE_SCHEDULED_FOR",
+		Migrate: func(db *gorm.DB) error {
+			statements := []string{
+				`ALTER TABLE expenses ADD COLUMN IF NOT EXISTS scheduled_for TIMESTAMPTZ`,
+				`CREATE INDEX IF NOT EXISTS idx_expenses_scheduled_for ON expenses(scheduled_for)`,
+			}
+
+			for _, statement := range statements {
+				if err := db.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`ALTER TABLE expenses DROP COLUMN IF EXISTS scheduled_for`).Error
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddLeaseAmendmentDraftUniqueIndex allows one open amendment per lease. Two
+// drafts executed one after the other would each rebuild the schedule from
+// terms the other had already replaced.
+func AddLeaseAmendmentDraftUniqueIndex() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190003_ADD_LEASE_AMENDMENT_DRAFT_UNIQUE_INDEX",
+		Migrate: func(db *gorm.DB) error {
+			return db.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_lease_amendments_one_draft_per_lease
+				ON lease_amendments (lease_id)
+				WHERE status = 'LeaseAmendment.Status.Draft'
+				  AND deleted_at IS NULL
+			`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`DROP INDEX IF EXISTS idx_lease_amendments_one_draft_per_lease`).Error
+		},
+	}
+}
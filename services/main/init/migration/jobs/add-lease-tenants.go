@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddLeaseTenants backfills a PRIMARY lease_tenants row for every existing
+// lease from leases.tenant_id, then allows a tenant on a lease only once.
+func AddLeaseTenants() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190004_ADD_LEASE_TENANTS",
+		Migrate: func(db *gorm.DB) error {
+			if err := db.Exec(`
+				INSERT INTO lease_tenants (lease_id, tenant_id, role)
+				SELECT l.id, l.tenant_id, 'PRIMARY'
+				FROM leases l
+				WHERE l.deleted_at IS NULL
+				  AND NOT EXISTS (
+					SELECT 1 FROM lease_tenants lt
+					WHERE lt.lease_id = l.id AND lt.tenant_id = l.tenant_id AND lt.deleted_at IS NULL
+				  )
+			`).Error; err != nil {
+				return err
+			}
+
+			return db.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_lease_tenants_lease_tenant
+				ON lease_tenants (lease_id, tenant_id)
+				WHERE deleted_at IS NULL
+			`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`DROP INDEX IF EXISTS idx_lease_tenants_lease_tenant`).Error
+		},
+	}
+}
@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddRenewalOfferOpenUniqueIndex allows one open renewal offer per lease. Two
+// open at once would let the tenant accept both, and the second acceptance
+// would be refused only after the first had already renewed the lease.
+func AddRenewalOfferOpenUniqueIndex() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190005_ADD_RENEWAL_OFFER_OPEN_UNIQUE_INDEX",
+		Migrate: func(db *gorm.DB) error {
+			return db.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_renewal_offers_one_open_per_lease
+				ON renewal_offers (lease_id)
+				WHERE status = 'RenewalOffer.Status.Sent'
+				  AND deleted_at IS NULL
+			`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`DROP INDEX IF EXISTS idx_renewal_offers_one_open_per_lease`).Error
+		},
+	}
+}
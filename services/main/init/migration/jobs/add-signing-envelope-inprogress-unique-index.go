@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddSigningEnvelopeInProgressUniqueIndex allows one in-progress signing
+// envelope per document. Two at once would invite the same signers twice, in
+// two different orders.
+func AddSigningEnvelopeInProgressUniqueIndex() *gormigrate.Migration {
+	return &gormigrate.Migration{
+		ID: "202610190006_ADD_SIGNING_ENVELOPE_INPROGRESS_UNIQUE_INDEX",
+		Migrate: func(db *gorm.DB) error {
+			return db.Exec(`
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_signing_envelopes_one_in_progress_per_document
+				ON signing_envelopes (document_id)
+				WHERE status = 'SigningEnvelope.Status.InProgress'
+				  AND deleted_at IS NULL
+			`).Error
+		},
+		Rollback: func(db *gorm.DB) error {
+			return db.Exec(`DROP INDEX IF EXISTS idx_signing_envelopes_one_in_progress_per_document`).Error
+		},
+	}
+}
@@ -25,6 +25,7 @@ func updateMigration(db *gorm.DB) error {
 		&models.Property{},
 		&models.ClientUserProperty{},
 		&models.Document{},
+		&models.DocumentRevision{},
 		&models.PropertyBlock{},
 		&models.Unit{},
 		&models.Tenant{},
@@ -46,6 +47,7 @@ func updateMigration(db *gorm.DB) error {
 		&models.PaymentAllocation{},
 		&models.DocumentSignature{},
 		&models.SigningToken{},
+		&models.SigningEnvelope{},
 		&models.FcmToken{},
 		&models.Announcement{},
 		&models.AnnouncementRead{},
@@ -57,8 +59,21 @@ func updateMigration(db *gorm.DB) error {
 		&models.Agreement{},
 		&models.AgreementAcceptance{},
 		&models.Booking{},
+		&models.BookingRefund{},
+		&models.BookingModification{},
+		&models.UnitCalendarSubscription{},
 		&models.UnitDateBlock{},
+		&models.TurnoverTask{},
+		&models.BookingGuest{},
+		&models.WaitlistEntry{},
+		&models.ViewingSlot{},
+		&models.Viewing{},
+		&models.BookingPricingRule{},
 		&models.LeaseTermination{},
+		&models.LeaseAmendment{},
+		&models.LeaseTenant{},
+		&models.Guarantor{},
+		&models.RenewalOffer{},
 		&models.LeaseAgreementDocument{},
 		&models.ExchangeRate{},
 		&models.Notification{},
@@ -135,6 +150,14 @@ func ServiceAutoMigration(db *gorm.DB) error {
 		jobs.AddMaintenanceRequestAssets(),
 		jobs.AddUserProfilePhotoUrl(),
 		jobs.AddTenantCode(),
+		jobs.AddChargeInstancePendingDueDateIndex(),
+		jobs.AddExpenseScheduledFor(),
+		jobs.AddExpensePaidAt(),
+		jobs.AddLeaseAmendmentDraftUniqueIndex(),
+		jobs.AddLeaseTenants(),
+		jobs.AddRenewalOfferOpenUniqueIndex(),
+		jobs.AddSigningEnvelopeInProgressUniqueIndex(),
+		jobs.AddBookingModificationRefunds(),
 	}
 
 	m = gormigrate.New(db, gormigrate.DefaultOptions, migrations)
@@ -0,0 +1,121 @@
+package calendarfeed
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+)
+
+// maxFeedBytes caps what one fetch will read. A year of daily events is a
+// few hundred kilobytes; anything much larger is not a unit's calendar.
+const maxFeedBytes = 5 << 20
+
+type Client interface {
+	// Fetch returns the raw iCalendar document published at feedURL.
+	Fetch(ctx context.Context, feedURL string) ([]byte, error)
+}
+
+type httpClient struct {
+	httpClient *http.Client
+}
+
+// NewClient fetches feeds over HTTP(S). webcal:// URLs, which channels hand
+// out so calendar apps subscribe to them, are fetched as https://.
+//
+// Feed URLs are typed in by managers, so the client only connects to public
+// addresses: a URL naming a host on our own network is refused when it is
+// dialled, after DNS, which also covers redirects and names that resolve
+// somewhere private.
+func NewClient() Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			if err := lib.RefusePrivateAddresses(network, address, conn); err != nil {
+				return fmt.Errorf("calendarfeed: %w", err)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &httpClient{
+		httpClient: &http.Client{
+			Timeout:   30 * time.Second,
+			Transport: transport,
+		},
+	}
+}
+
+func (c *httpClient) Fetch(ctx context.Context, feedURL string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(feedURL, "webcal://"); ok {
+		feedURL = "https://" + rest
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, feedURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("calendarfeed: create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/calendar")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calendarfeed: execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFeedBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("calendarfeed: read response: %w", err)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("calendarfeed: feed returned %d", resp.StatusCode)
+	}
+	if len(body) > maxFeedBytes {
+		return nil, fmt.Errorf("calendarfeed: feed is larger than %d bytes", maxFeedBytes)
+	}
+
+	return body, nil
+}
+
+type fileClient struct {
+	root string
+}
+
+// NewFileClient reads feeds from files under root instead of the network,
+// for tests and local development. A feed URL is a file:// URL or a path,
+// either taken relative to root; nothing outside root can be read.
+func NewFileClient(root string) Client {
+	return &fileClient{root: root}
+}
+
+func (c *fileClient) Fetch(ctx context.Context, feedURL string) ([]byte, error) {
+	name := feedURL
+	if parsed, err := url.Parse(feedURL); err == nil && parsed.Scheme == "file" {
+		name = parsed.Host + parsed.Path
+	}
+
+	local, err := filepath.Localize(strings.TrimPrefix(filepath.ToSlash(name), "/"))
+	if err != nil {
+		return nil, fmt.Errorf("calendarfeed: %q is not a path under the feed root", feedURL)
+	}
+
+	body, err := os.ReadFile(filepath.Join(c.root, local))
+	if err != nil {
+		return nil, fmt.Errorf("calendarfeed: read file: %w", err)
+	}
+
+	return body, nil
+}
@@ -2,8 +2,10 @@ package clients
 
 import (
 	"github.com/Bendomey/rent-loop/services/main/internal/clients/accounting"
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/calendarfeed"
 	"github.com/Bendomey/rent-loop/services/main/internal/clients/fcm"
 	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/objectstorage"
 	"github.com/Bendomey/rent-loop/services/main/internal/clients/openexchangerates"
 	"github.com/Bendomey/rent-loop/services/main/internal/config"
 	log "github.com/sirupsen/logrus"
@@ -14,6 +16,8 @@ type Clients struct {
 	GatekeeperAPI        gatekeeper.Client
 	FCM                  fcm.Client
 	OpenExchangeRatesAPI openexchangerates.Client
+	ObjectStorage        objectstorage.Client
+	CalendarFeed         calendarfeed.Client
 }
 
 func NewClients(cfg config.Config) Clients {
@@ -35,10 +39,25 @@ func NewClients(cfg config.Config) Clients {
 		cfg.Clients.OpenExchangeRatesAPI.AppID,
 	)
 
+	objectStorageClient := objectstorage.NewClient(objectstorage.ClientConfig{
+		AccountID:       cfg.Clients.ObjectStorage.AccountID,
+		AccessKeyID:     cfg.Clients.ObjectStorage.AccessKeyID,
+		SecretAccessKey: cfg.Clients.ObjectStorage.SecretAccessKey,
+		BucketName:      cfg.Clients.ObjectStorage.BucketName,
+		PublicBaseURL:   cfg.Clients.ObjectStorage.PublicBaseURL,
+	})
+
+	calendarFeedClient := calendarfeed.NewClient()
+	if cfg.Clients.CalendarFeed.LocalDir != "" {
+		calendarFeedClient = calendarfeed.NewFileClient(cfg.Clients.CalendarFeed.LocalDir)
+	}
+
 	return Clients{
 		AccountingAPI:        accountingClient,
 		GatekeeperAPI:        gatekeeperClient,
 		FCM:                  fcmClient,
 		OpenExchangeRatesAPI: oxrClient,
+		ObjectStorage:        objectStorageClient,
+		CalendarFeed:         calendarFeedClient,
 	}
 }
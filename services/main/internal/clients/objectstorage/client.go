@@ -0,0 +1,278 @@
+package objectstorage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+)
+
+type Client interface {
+	// PutObject uploads body under key and returns the object's public URL.
+	//
+	// # Usage:
+	//
+	// url, err := appCtx.Clients.ObjectStorage.PutObject(
+	// ctx,
+	//
+	//	PutObjectInput{
+	//		Key:         "lease-agreements/LS123-2026-10-19T08:00:00Z.pdf",
+	//		ContentType: "application/pdf",
+	//		Body:        pdfBytes,
+	//	}
+	//
+	// )
+	PutObject(ctx context.Context, input PutObjectInput) (string, error)
+
+	// GetObject reads back an object by its public URL, as PutObject or the
+	// portals' uploads returned it. A URL anywhere else returns
+	// ErrNotStoredObject, so a URL a user supplied cannot make the server
+	// fetch from arbitrary hosts. Objects over MaxObjectBytes are refused.
+	GetObject(ctx context.Context, objectURL string) ([]byte, error)
+}
+
+// MaxObjectBytes caps what GetObject reads, and what callers accept inline
+// as a data URL. Signature images and agreement PDFs are well under it.
+const MaxObjectBytes = 20 << 20
+
+var ErrNotStoredObject = errors.New("objectstorage: url is not an object in the bucket")
+
+type PutObjectInput struct {
+	Key         string
+	ContentType string
+	Body        []byte
+}
+
+type ClientConfig struct {
+	// AccountID is the Cloudflare account the R2 bucket lives in.
+	AccountID       string
+	AccessKeyID     string
+	SecretAccessKey string
+	BucketName      string
+	// PublicBaseURL is where the bucket is served from. It is the same base the
+	// portals' own uploads return, so URLs from either source look alike.
+	PublicBaseURL string
+}
+
+// r2Client speaks the S3 API to Cloudflare R2. Only single-request uploads are
+// needed, which is small enough to sign by hand rather than pull in an SDK.
+type r2Client struct {
+	config     ClientConfig
+	endpoint   string
+	httpClient *http.Client
+	// publicClient reads objects from PublicBaseURL. It only dials public
+	// addresses, in case the base is misconfigured to somewhere internal.
+	publicClient *http.Client
+}
+
+func NewClient(config ClientConfig) Client {
+	return &r2Client{
+		config:   config,
+		endpoint: fmt.Sprintf("https://%s.r2.cloudflarestorage.com", config.AccountID),
+		httpClient: &http.Client{
+			Timeout: 60 * time.Second,
+		},
+		publicClient: newPublicClient(),
+	}
+}
+
+func newPublicClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			if err := lib.RefusePrivateAddresses(network, address, conn); err != nil {
+				return fmt.Errorf("objectstorage: %w", err)
+			}
+			return nil
+		},
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: transport,
+		// A redirect could leave the bucket's host.
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+const (
+	signingRegion    = "auto"
+	signingService   = "s3"
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+)
+
+func (c *r2Client) PutObject(ctx context.Context, input PutObjectInput) (string, error) {
+	objectPath := "/" + c.config.BucketName + "/" + encodeKey(input.Key)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, c.endpoint+objectPath, bytes.NewReader(input.Body))
+	if err != nil {
+		return "", fmt.Errorf("objectstorage: create request: %w", err)
+	}
+	req.Header.Set("Content-Type", input.ContentType)
+	c.sign(req, objectPath, input.Body, time.Now().UTC())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("objectstorage: execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("objectstorage: API error %d: %s", resp.StatusCode, string(body))
+	}
+
+	return strings.TrimRight(c.config.PublicBaseURL, "/") + "/" + input.Key, nil
+}
+
+func (c *r2Client) GetObject(ctx context.Context, objectURL string) ([]byte, error) {
+	if !c.isObjectURL(objectURL) {
+		return nil, ErrNotStoredObject
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, objectURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("objectstorage: create request: %w", err)
+	}
+
+	resp, err := c.publicClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("objectstorage: execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("objectstorage: object returned %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, MaxObjectBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("objectstorage: read response: %w", err)
+	}
+	if len(body) > MaxObjectBytes {
+		return nil, fmt.Errorf("objectstorage: object is larger than %d bytes", MaxObjectBytes)
+	}
+
+	return body, nil
+}
+
+// isObjectURL reports whether objectURL is under PublicBaseURL: same scheme
+// and host, and a path inside the base's.
+func (c *r2Client) isObjectURL(objectURL string) bool {
+	base, err := url.Parse(strings.TrimRight(c.config.PublicBaseURL, "/") + "/")
+	if err != nil || base.Host == "" {
+		return false
+	}
+	target, err := url.Parse(objectURL)
+	if err != nil || target.User != nil {
+		return false
+	}
+
+	return target.Scheme == base.Scheme &&
+		strings.EqualFold(target.Host, base.Host) &&
+		strings.HasPrefix(target.Path, base.Path) &&
+		!strings.Contains(target.Path, "..")
+}
+
+// sign adds an AWS Signature Version 4 Authorization header to req.
+func (c *r2Client) sign(req *http.Request, canonicalPath string, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{
+		"host":                 req.URL.Host,
+		"content-type":         req.Header.Get("Content-Type"),
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath,
+		"",
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := day + "/" + signingRegion + "/" + signingService + "/aws4_request"
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.config.SecretAccessKey), day)
+	key = hmacSHA256(key, signingRegion)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, c.config.AccessKeyID, scope, signedHeaders, signature,
+	))
+}
+
+// encodeKey escapes an object key the way SigV4 canonicalises it: everything
+// but RFC 3986 unreserved characters, keeping the slashes between segments.
+// url.PathEscape leaves characters such as ':' alone, which would sign a
+// different path from the one sent.
+func encodeKey(key string) string {
+	var encoded strings.Builder
+	for _, b := range []byte(key) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~', b == '/':
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+	return encoded.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
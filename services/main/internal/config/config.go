@@ -103,10 +103,29 @@ type IOpenExchangeRatesAPI struct {
 	AppID   string
 }
 
+// IObjectStorage is the Cloudflare R2 bucket the portals upload to. The
+// variable names match the portals' so one set of values serves both.
+type IObjectStorage struct {
+	AccountID       string
+	AccessKeyID     string
+	SecretAccessKey string
+	BucketName      string
+	PublicBaseURL   string
+}
+
+// ICalendarFeed configures how unit calendar subscriptions are fetched.
+// With LocalDir set, feed URLs are read as files under it instead of over
+// the network, so channel sync can be exercised without live listings.
+type ICalendarFeed struct {
+	LocalDir string
+}
+
 type IClients struct {
 	AccountingAPI        IAccountingAPI
 	GatekeeperAPI        IGatekeeperAPI
 	OpenExchangeRatesAPI IOpenExchangeRatesAPI
+	ObjectStorage        IObjectStorage
+	CalendarFeed         ICalendarFeed
 }
 
 type IFirebase struct {
@@ -213,6 +232,16 @@ func Load() Config {
 				BaseURL: getEnv("OPENEXCHANGERATES_BASE_URL", "https://openexchangerates.org/api"),
 				AppID:   getEnv("OPENEXCHANGERATES_APP_ID", ""),
 			},
+			ObjectStorage: IObjectStorage{
+				AccountID:       getEnv("CF_ACCOUNT_ID", ""),
+				AccessKeyID:     getEnv("R2_ACCESS_KEY_ID", ""),
+				SecretAccessKey: getEnv("R2_SECRET_ACCESS_KEY", ""),
+				BucketName:      getEnv("BUCKET_NAME", ""),
+				PublicBaseURL:   getEnv("RENTLOOP_IMAGES_BASE_URL", ""),
+			},
+			CalendarFeed: ICalendarFeed{
+				LocalDir: getEnv("CALENDAR_FEED_DIR", ""),
+			},
 		},
 		CubeApiSecret: getEnv("CUBEJS_API_SECRET", "superdupercubeapisecret"),
 		TestOTP: ITestOTP{
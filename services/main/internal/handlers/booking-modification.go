@@ -0,0 +1,101 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/go-chi/chi/v5"
+)
+
+type ModifyBookingRequest struct {
+	UnitID       *string    `json:"unit_id,omitempty"        validate:"omitempty,uuid4" example:"660e8400-e29b-41d4-a716-446655440000"    description:"Another unit in the same property to move the stay to"`
+	CheckInDate  *time.Time `json:"check_in_date,omitempty"  validate:"omitempty"       example:"2026-10-22T14:00:00Z"`
+	CheckOutDate *time.Time `json:"check_out_date,omitempty" validate:"omitempty"       example:"2026-10-27T11:00:00Z"`
+	Reason       string     `json:"reason"                   validate:"required"        example:"Guest extended their stay by two nights"`
+}
+
+// ModifyBooking godoc
+//
+//	@Summary		Modify a booking's stay
+//	@Description	Extend, shorten or move a stay to new dates or another unit in the same property. The new dates must be free of other blocks. An unpaid draft invoice is repriced; otherwise a higher price is billed on a supplementary invoice and a lower one leaves the guest a credit, recorded as a pending refund. The guest is told of the change. A checked-in booking can only have its check-out date changed.
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string					true	"Client ID"
+//	@Param			property_id	path		string					true	"Property ID"
+//	@Param			booking_id	path		string					true	"Booking ID"
+//	@Param			body		body		ModifyBookingRequest	true	"Booking modification"
+//	@Success		201			{object}	object{data=transformations.OutputBookingModification}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		409			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/bookings/{booking_id}/modifications [post]
+func (h *BookingHandler) ModifyBooking(w http.ResponseWriter, r *http.Request) {
+	clientUser, _ := lib.ClientUserFromContext(r.Context())
+
+	var body ModifyBookingRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	modification, err := h.bookingService.ModifyBooking(r.Context(), services.ModifyBookingInput{
+		BookingID:    chi.URLParam(r, "booking_id"),
+		ClientUserID: clientUser.ID,
+		Reason:       body.Reason,
+		UnitID:       body.UnitID,
+		CheckInDate:  body.CheckInDate,
+		CheckOutDate: body.CheckOutDate,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBBookingModificationToRest(modification)})
+}
+
+// ListBookingModifications godoc
+//
+//	@Summary		List a booking's modifications
+//	@Description	The booking's history of changes to its dates and unit, oldest first
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string	true	"Client ID"
+//	@Param			property_id	path		string	true	"Property ID"
+//	@Param			booking_id	path		string	true	"Booking ID"
+//	@Param			populate	query		string	false	"Relations to load, e.g. Unit,Invoice,Refund,CreatedByClientUser"
+//	@Success		200			{object}	object{data=[]transformations.OutputBookingModification}
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/bookings/{booking_id}/modifications [get]
+func (h *BookingHandler) ListBookingModifications(w http.ResponseWriter, r *http.Request) {
+	modifications, err := h.bookingService.ListBookingModifications(
+		r.Context(),
+		chi.URLParam(r, "booking_id"),
+		GetPopulateFields(r),
+	)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	out := make([]any, len(modifications))
+	for i := range modifications {
+		out[i] = transformations.DBBookingModificationToRest(&modifications[i])
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": out})
+}
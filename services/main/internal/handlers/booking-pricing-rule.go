@@ -0,0 +1,410 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateBookingPricingRuleRequest struct {
+	UnitID            *string    `json:"unit_id"            validate:"omitempty,uuid4"                                            example:"660e8400-e29b-41d4-a716-446655440000" description:"Unit the rule is for; every unit in the property when omitted"`
+	Name              string     `json:"name"               validate:"required"                                                   example:"Christmas peak"`
+	Type              string     `json:"type"               validate:"required,oneof=RATE ADJUSTMENT STAY_LENGTH LENGTH_DISCOUNT" example:"RATE"`
+	Priority          int64      `json:"priority"           validate:"omitempty"                                                  example:"0"                                    description:"Higher wins where rules of a type overlap"`
+	StartDate         *time.Time `json:"start_date"         validate:"omitempty"                                                  example:"2026-12-20T00:00:00Z"                 description:"First night the rule covers"`
+	EndDate           *time.Time `json:"end_date"           validate:"omitempty"                                                  example:"2027-01-02T00:00:00Z"                 description:"Last night the rule covers"`
+	DaysOfWeek        []string   `json:"days_of_week"       validate:"omitempty,dive,oneof=MON TUE WED THU FRI SAT SUN"           example:"FRI,SAT"`
+	NightlyRate       *int64     `json:"nightly_rate"       validate:"omitempty,gt=0"                                             example:"90000"                                description:"RATE: the night's rate in pesewas"`
+	AdjustmentPercent *int64     `json:"adjustment_percent" validate:"omitempty,gte=-100,lte=1000"                                example:"20"                                   description:"ADJUSTMENT: percent on the night's rate, negative for less"`
+	MinNights         *int64     `json:"min_nights"         validate:"omitempty,gte=1"                                            example:"3"                                    description:"STAY_LENGTH, LENGTH_DISCOUNT"`
+	MaxNights         *int64     `json:"max_nights"         validate:"omitempty,gte=1"                                            example:"28"                                   description:"STAY_LENGTH"`
+	DiscountPercent   *int64     `json:"discount_percent"   validate:"omitempty,gte=1,lte=100"                                    example:"10"                                   description:"LENGTH_DISCOUNT"`
+}
+
+// CreateBookingPricingRule godoc
+//
+//	@Summary		Create a booking pricing rule (Admin)
+//	@Description	Add a rule to how a property's short-stay units are priced (Admin). RATE sets the nightly rate and ADJUSTMENT moves it by a percentage on the nights the rule covers; STAY_LENGTH sets minimum and maximum nights and LENGTH_DISCOUNT takes a percentage off stays of at least min_nights, both for stays checking in on a night the rule covers. Rules only apply to units let by the day, and one for a unit let by another period is rejected.
+//	@Tags			Booking
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string							true	"Property ID"
+//	@Param			body		body		CreateBookingPricingRuleRequest	true	"Pricing rule"
+//	@Success		201			{object}	object{data=transformations.OutputBookingPricingRule}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/booking-pricing-rules [post]
+func (h *BookingHandler) CreateBookingPricingRule(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CreateBookingPricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	rule, err := h.pricingService.CreateRule(r.Context(), services.CreateBookingPricingRuleInput{
+		PropertyID:            chi.URLParam(r, "property_id"),
+		UnitID:                body.UnitID,
+		Name:                  body.Name,
+		Type:                  body.Type,
+		Priority:              body.Priority,
+		StartDate:             body.StartDate,
+		EndDate:               body.EndDate,
+		DaysOfWeek:            body.DaysOfWeek,
+		NightlyRate:           body.NightlyRate,
+		AdjustmentPercent:     body.AdjustmentPercent,
+		MinNights:             body.MinNights,
+		MaxNights:             body.MaxNights,
+		DiscountPercent:       body.DiscountPercent,
+		CreatedByClientUserID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBBookingPricingRuleToRest(rule)})
+}
+
+type ListBookingPricingRulesFilterRequest struct {
+	lib.FilterQueryInput
+	UnitID *string `json:"unit_id,omitempty" validate:"omitempty,uuid4"                                             example:"660e8400-e29b-41d4-a716-446655440000" description:"Only the rules that price this unit: its own and the property's"`
+	Type   *string `json:"type,omitempty"    validate:"omitempty,oneof=RATE ADJUSTMENT STAY_LENGTH LENGTH_DISCOUNT" example:"RATE"`
+}
+
+// ListBookingPricingRules godoc
+//
+//	@Summary		List booking pricing rules (Admin)
+//	@Description	List the rules pricing a property's short-stay units (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string									true	"Property ID"
+//	@Param			q			query		ListBookingPricingRulesFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputBookingPricingRule,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/booking-pricing-rules [get]
+func (h *BookingHandler) ListBookingPricingRules(w http.ResponseWriter, r *http.Request) {
+	filters := ListBookingPricingRulesFilterRequest{
+		UnitID: lib.NullOrString(r.URL.Query().Get("unit_id")),
+		Type:   lib.NullOrString(r.URL.Query().Get("type")),
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	input := repository.ListBookingPricingRulesFilter{
+		FilterQuery: *filterQuery,
+		PropertyID:  chi.URLParam(r, "property_id"),
+		UnitID:      filters.UnitID,
+		Type:        filters.Type,
+	}
+
+	rules, listErr := h.pricingService.ListRules(r.Context(), input)
+	if listErr != nil {
+		HandleErrorResponse(w, listErr)
+		return
+	}
+
+	count, countErr := h.pricingService.CountRules(r.Context(), input)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, 0, len(rules))
+	for i := range rules {
+		rows = append(rows, transformations.DBBookingPricingRuleToRest(&rules[i]))
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+// GetBookingPricingRule godoc
+//
+//	@Summary		Get a booking pricing rule (Admin)
+//	@Description	Get a booking pricing rule (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string	true	"Property ID"
+//	@Param			rule_id		path		string	true	"Pricing rule ID"
+//	@Param			populate	query		string	false	"Relations to load, e.g. Unit"
+//	@Success		200			{object}	object{data=transformations.OutputBookingPricingRule}
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/booking-pricing-rules/{rule_id} [get]
+func (h *BookingHandler) GetBookingPricingRule(w http.ResponseWriter, r *http.Request) {
+	rule, err := h.pricingService.GetRule(r.Context(), repository.GetBookingPricingRuleQuery{
+		ID:         chi.URLParam(r, "rule_id"),
+		PropertyID: chi.URLParam(r, "property_id"),
+		Populate:   GetPopulateFields(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBBookingPricingRuleToRest(rule)})
+}
+
+type UpdateBookingPricingRuleRequest struct {
+	UnitID            lib.Optional[string]    `json:"unit_id"                validate:"omitempty,uuid4"                                             swaggertype:"string"  description:"null applies the rule to every unit in the property"`
+	Name              *string                 `json:"name,omitempty"         validate:"omitempty,min=1"`
+	Type              *string                 `json:"type,omitempty"         validate:"omitempty,oneof=RATE ADJUSTMENT STAY_LENGTH LENGTH_DISCOUNT"`
+	Priority          *int64                  `json:"priority,omitempty"     validate:"omitempty"`
+	StartDate         lib.Optional[time.Time] `json:"start_date"             validate:"omitempty"                                                   swaggertype:"string"`
+	EndDate           lib.Optional[time.Time] `json:"end_date"               validate:"omitempty"                                                   swaggertype:"string"`
+	DaysOfWeek        *[]string               `json:"days_of_week,omitempty" validate:"omitempty,dive,oneof=MON TUE WED THU FRI SAT SUN"`
+	NightlyRate       lib.Optional[int64]     `json:"nightly_rate"           validate:"omitempty,gt=0"                                              swaggertype:"integer"`
+	AdjustmentPercent lib.Optional[int64]     `json:"adjustment_percent"     validate:"omitempty,gte=-100,lte=1000"                                 swaggertype:"integer"`
+	MinNights         lib.Optional[int64]     `json:"min_nights"             validate:"omitempty,gte=1"                                             swaggertype:"integer"`
+	MaxNights         lib.Optional[int64]     `json:"max_nights"             validate:"omitempty,gte=1"                                             swaggertype:"integer"`
+	DiscountPercent   lib.Optional[int64]     `json:"discount_percent"       validate:"omitempty,gte=1,lte=100"                                     swaggertype:"integer"`
+}
+
+// UpdateBookingPricingRule godoc
+//
+//	@Summary		Update a booking pricing rule (Admin)
+//	@Description	Update a booking pricing rule (Admin). Bookings already made keep their price until their dates change.
+//	@Tags			Booking
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string							true	"Property ID"
+//	@Param			rule_id		path		string							true	"Pricing rule ID"
+//	@Param			body		body		UpdateBookingPricingRuleRequest	true	"Fields to update"
+//	@Success		200			{object}	object{data=transformations.OutputBookingPricingRule}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/booking-pricing-rules/{rule_id} [patch]
+func (h *BookingHandler) UpdateBookingPricingRule(w http.ResponseWriter, r *http.Request) {
+	var body UpdateBookingPricingRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	rule, err := h.pricingService.UpdateRule(r.Context(), services.UpdateBookingPricingRuleInput{
+		ID:                chi.URLParam(r, "rule_id"),
+		PropertyID:        chi.URLParam(r, "property_id"),
+		UnitID:            body.UnitID,
+		Name:              body.Name,
+		Type:              body.Type,
+		Priority:          body.Priority,
+		StartDate:         body.StartDate,
+		EndDate:           body.EndDate,
+		DaysOfWeek:        body.DaysOfWeek,
+		NightlyRate:       body.NightlyRate,
+		AdjustmentPercent: body.AdjustmentPercent,
+		MinNights:         body.MinNights,
+		MaxNights:         body.MaxNights,
+		DiscountPercent:   body.DiscountPercent,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBBookingPricingRuleToRest(rule)})
+}
+
+// DeleteBookingPricingRule godoc
+//
+//	@Summary		Delete a booking pricing rule (Admin)
+//	@Description	Delete a booking pricing rule (Admin). Bookings already made keep their price until their dates change.
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string	true	"Property ID"
+//	@Param			rule_id		path		string	true	"Pricing rule ID"
+//	@Success		204			{object}	nil
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/booking-pricing-rules/{rule_id} [delete]
+func (h *BookingHandler) DeleteBookingPricingRule(w http.ResponseWriter, r *http.Request) {
+	err := h.pricingService.DeleteRule(r.Context(), repository.DeleteBookingPricingRuleInput{
+		ID:         chi.URLParam(r, "rule_id"),
+		PropertyID: chi.URLParam(r, "property_id"),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseBookingQuoteDates reads the stay to quote from the check_in_date and
+// check_out_date query params.
+func parseBookingQuoteDates(r *http.Request) (time.Time, time.Time, error) {
+	checkIn, checkInErr := ParseDateParam(r.URL.Query().Get("check_in_date"))
+	if checkInErr != nil || checkIn == nil {
+		return time.Time{}, time.Time{}, pkg.BadRequestError("InvalidCheckInDate", nil)
+	}
+	checkOut, checkOutErr := ParseDateParam(r.URL.Query().Get("check_out_date"))
+	if checkOutErr != nil || checkOut == nil {
+		return time.Time{}, time.Time{}, pkg.BadRequestError("InvalidCheckOutDate", nil)
+	}
+	return *checkIn, *checkOut, nil
+}
+
+// parseBookingQuoteGuests reads how many are staying from the adults and
+// children query parameters: one adult and no children when left out.
+func parseBookingQuoteGuests(r *http.Request) (int64, error) {
+	counts := map[string]int64{"adults": 1, "children": 0}
+	for param := range counts {
+		value := r.URL.Query().Get(param)
+		if value == "" {
+			continue
+		}
+		count, err := lib.ConvertStringToInt64(value)
+		if err != nil || count < 0 {
+			return 0, pkg.BadRequestError("InvalidGuestCount", nil)
+		}
+		counts[param] = count
+	}
+	return counts["adults"] + counts["children"], nil
+}
+
+// QuoteBooking godoc
+//
+//	@Summary		Quote a stay at a unit (Admin)
+//	@Description	Price a stay at the unit's rent fee under its pricing rules, night by night for units let by the day, as a booking made without an agreed rate would be charged (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id		path		string	true	"Property ID"
+//	@Param			unit_id			path		string	true	"Unit ID"
+//	@Param			check_in_date	query		string	true	"Check-in (RFC3339 or YYYY-MM-DD)"
+//	@Param			check_out_date	query		string	true	"Check-out (RFC3339 or YYYY-MM-DD)"
+//	@Param			adults			query		int		false	"Adults staying (default 1)"
+//	@Param			children		query		int		false	"Children staying (default 0)"
+//	@Success		200				{object}	object{data=transformations.OutputBookingQuote}
+//	@Failure		400				{object}	lib.HTTPError
+//	@Failure		401				{object}	string
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/quote [get]
+func (h *BookingHandler) QuoteBooking(w http.ResponseWriter, r *http.Request) {
+	checkIn, checkOut, dateErr := parseBookingQuoteDates(r)
+	if dateErr != nil {
+		HandleErrorResponse(w, dateErr)
+		return
+	}
+	guests, guestsErr := parseBookingQuoteGuests(r)
+	if guestsErr != nil {
+		HandleErrorResponse(w, guestsErr)
+		return
+	}
+
+	unit, unitErr := h.unitService.GetUnit(r.Context(), repository.GetUnitQuery{
+		PropertyID: chi.URLParam(r, "property_id"),
+		UnitID:     chi.URLParam(r, "unit_id"),
+	})
+	if unitErr != nil {
+		HandleErrorResponse(w, unitErr)
+		return
+	}
+
+	quote, err := h.pricingService.QuoteStay(r.Context(), services.QuoteBookingStayInput{
+		Unit:         unit,
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		Guests:       guests,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.BookingQuoteToRest(quote)})
+}
+
+// PublicQuoteBooking godoc
+//
+//	@Summary		Quote a stay at a unit (public)
+//	@Description	Price a stay the way booking it would be charged, night by night for units let by the day, with the cancellation policy it would be booked under
+//	@Tags			Public
+//	@Produce		json
+//	@Param			unit_slug		path		string	true	"Unit Slug"
+//	@Param			check_in_date	query		string	true	"Check-in (RFC3339 or YYYY-MM-DD)"
+//	@Param			check_out_date	query		string	true	"Check-out (RFC3339 or YYYY-MM-DD)"
+//	@Param			adults			query		int		false	"Adults staying (default 1)"
+//	@Param			children		query		int		false	"Children staying (default 0)"
+//	@Success		200				{object}	object{data=transformations.PublicOutputBookingQuote}
+//	@Failure		400				{object}	lib.HTTPError
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/units/{unit_slug}/quote [get]
+func (h *BookingHandler) PublicQuoteBooking(w http.ResponseWriter, r *http.Request) {
+	checkIn, checkOut, dateErr := parseBookingQuoteDates(r)
+	if dateErr != nil {
+		HandleErrorResponse(w, dateErr)
+		return
+	}
+	guests, guestsErr := parseBookingQuoteGuests(r)
+	if guestsErr != nil {
+		HandleErrorResponse(w, guestsErr)
+		return
+	}
+
+	unit, unitErr := h.unitService.GetUnitBySlug(r.Context(), chi.URLParam(r, "unit_slug"))
+	if unitErr != nil {
+		HandleErrorResponse(w, unitErr)
+		return
+	}
+
+	quote, err := h.pricingService.QuoteStay(r.Context(), services.QuoteBookingStayInput{
+		Unit:         unit,
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		Guests:       guests,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	property, propErr := h.propertyService.GetProperty(r.Context(), repository.GetPropertyQuery{ID: unit.PropertyID})
+	if propErr != nil {
+		HandleErrorResponse(w, propErr)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.PublicBookingQuoteToRest(quote, property)})
+}
@@ -22,6 +22,8 @@ type BookingHandler struct {
 	unitDateBlockService services.UnitDateBlockService
 	propertyService      services.PropertyService
 	unitService          services.UnitService
+	pricingService       services.BookingPricingService
+	calendarService      services.UnitCalendarService
 }
 
 func NewBookingHandler(appCtx pkg.AppContext, svcs services.Services) BookingHandler {
@@ -31,24 +33,42 @@ func NewBookingHandler(appCtx pkg.AppContext, svcs services.Services) BookingHan
 		unitDateBlockService: svcs.UnitDateBlockService,
 		propertyService:      svcs.PropertyService,
 		unitService:          svcs.UnitService,
+		pricingService:       svcs.BookingPricingService,
+		calendarService:      svcs.UnitCalendarService,
 	}
 }
 
 // ---- Request bodies ----
 
 type CreateBookingRequest struct {
-	UnitID         string    `json:"unit_id"          validate:"required,uuid4"`
-	CheckInDate    time.Time `json:"check_in_date"    validate:"required"`
-	CheckOutDate   time.Time `json:"check_out_date"   validate:"required"`
-	Rate           int64     `json:"rate"             validate:"required,gt=0"`
-	Notes          string    `json:"notes"`
-	GuestFirstName string    `json:"guest_first_name" validate:"required"`
-	GuestLastName  string    `json:"guest_last_name"  validate:"required"`
-	GuestPhone     string    `json:"guest_phone"      validate:"required"`
-	GuestEmail     *string   `json:"guest_email"      validate:"omitempty,email"`
-	GuestGender    string    `json:"guest_gender"     validate:"required,oneof=MALE FEMALE"`
-	GuestIDType    *string   `json:"guest_id_type"    validate:"omitempty"`
-	GuestIDNumber  *string   `json:"guest_id_number"  validate:"omitempty"`
+	UnitID         string                `json:"unit_id"          validate:"required,uuid4"`
+	CheckInDate    time.Time             `json:"check_in_date"    validate:"required"`
+	CheckOutDate   time.Time             `json:"check_out_date"   validate:"required"`
+	Rate           *int64                `json:"rate"             validate:"omitempty,gt=0"`
+	Notes          string                `json:"notes"`
+	GuestFirstName string                `json:"guest_first_name" validate:"required"`
+	GuestLastName  string                `json:"guest_last_name"  validate:"required"`
+	GuestPhone     string                `json:"guest_phone"      validate:"required"`
+	GuestEmail     *string               `json:"guest_email"      validate:"omitempty,email"`
+	GuestGender    string                `json:"guest_gender"     validate:"required,oneof=MALE FEMALE"`
+	GuestIDType    *string               `json:"guest_id_type"    validate:"omitempty"`
+	GuestIDNumber  *string               `json:"guest_id_number"  validate:"omitempty"`
+	Adults         int64                 `json:"adults"           validate:"omitempty,min=1"`
+	Children       int64                 `json:"children"         validate:"omitempty,min=0"`
+	Guests         []BookingGuestRequest `json:"guests"           validate:"omitempty,dive"`
+}
+
+type BookingGuestRequest struct {
+	FirstName   string  `json:"first_name"  validate:"required"                                                       example:"Ama"`
+	LastName    string  `json:"last_name"   validate:"required"                                                       example:"Mensah"`
+	IsChild     bool    `json:"is_child"                                                                              example:"false"`
+	Nationality *string `json:"nationality" validate:"omitempty"                                                      example:"Ghanaian"`
+	IDType      *string `json:"id_type"     validate:"omitempty,oneof=GHANA_CARD NATIONAL_ID PASSPORT DRIVER_LICENSE" example:"PASSPORT"`
+	IDNumber    *string `json:"id_number"   validate:"omitempty"                                                      example:"G1234567"`
+}
+
+type SetBookingGuestsRequest struct {
+	Guests []BookingGuestRequest `json:"guests" validate:"omitempty,dive" description:"Everyone registered for the stay; replaces the current list"`
 }
 
 type UpdateBookingRequest struct {
@@ -56,6 +76,8 @@ type UpdateBookingRequest struct {
 	RequiresUpfrontPayment lib.Optional[bool]           `json:"requires_upfront_payment" validate:"omitempty" swaggertype:"boolean"`
 	CheckInDate            lib.Optional[time.Time]      `json:"check_in_date"            validate:"omitempty" swaggertype:"string"`
 	CheckOutDate           lib.Optional[time.Time]      `json:"check_out_date"           validate:"omitempty" swaggertype:"string"`
+	Adults                 lib.Optional[int64]          `json:"adults"                   validate:"omitempty" swaggertype:"integer"`
+	Children               lib.Optional[int64]          `json:"children"                 validate:"omitempty" swaggertype:"integer"`
 	Meta                   lib.Optional[datatypes.JSON] `json:"meta"                     validate:"omitempty" swaggertype:"object"`
 }
 
@@ -63,6 +85,15 @@ type CancelBookingRequest struct {
 	Reason string `json:"reason" validate:"required"`
 }
 
+type MarkBookingRefundPaidRequest struct {
+	RefundID         *string `json:"refund_id,omitempty"         validate:"omitempty,uuid4"   example:"0b6f8a52-3c1d-4e7a-9f20-5d8c1e4b7a93" description:"Refund of a credit left by a change to the stay. Omit for the cancellation refund"`
+	PaymentReference *string `json:"payment_reference,omitempty" validate:"omitempty,max=100" example:"MP261020.1000.A12345"                 description:"Reference of the transfer that sent the refund, e.g. a MoMo transaction ID"`
+}
+
+type ExtendBookingHoldRequest struct {
+	HoldExpiresAt time.Time `json:"hold_expires_at" validate:"required" example:"2026-10-22T18:00:00Z" description:"New time the booking is cancelled at if still unpaid"`
+}
+
 type CreateDateBlockRequest struct {
 	StartDate time.Time `json:"start_date" validate:"required"`
 	EndDate   time.Time `json:"end_date"   validate:"required"`
@@ -71,15 +102,18 @@ type CreateDateBlockRequest struct {
 }
 
 type PublicCreateBookingRequest struct {
-	CheckInDate  time.Time `json:"check_in_date"  validate:"required"`
-	CheckOutDate time.Time `json:"check_out_date" validate:"required"`
-	FirstName    string    `json:"first_name"     validate:"required"`
-	LastName     string    `json:"last_name"      validate:"required"`
-	Phone        string    `json:"phone"          validate:"required"`
-	Email        *string   `json:"email"          validate:"omitempty,email"`
-	Gender       string    `json:"gender"         validate:"required,oneof=MALE FEMALE"`
-	IDType       *string   `json:"id_type"        validate:"omitempty"`
-	IDNumber     *string   `json:"id_number"      validate:"omitempty"`
+	CheckInDate  time.Time             `json:"check_in_date"  validate:"required"`
+	CheckOutDate time.Time             `json:"check_out_date" validate:"required"`
+	FirstName    string                `json:"first_name"     validate:"required"`
+	LastName     string                `json:"last_name"      validate:"required"`
+	Phone        string                `json:"phone"          validate:"required"`
+	Email        *string               `json:"email"          validate:"omitempty,email"`
+	Gender       string                `json:"gender"         validate:"required,oneof=MALE FEMALE"`
+	IDType       *string               `json:"id_type"        validate:"omitempty"`
+	IDNumber     *string               `json:"id_number"      validate:"omitempty"`
+	Adults       int64                 `json:"adults"         validate:"omitempty,min=1"`
+	Children     int64                 `json:"children"       validate:"omitempty,min=0"`
+	Guests       []BookingGuestRequest `json:"guests"         validate:"omitempty,dive"`
 }
 
 // ---- Manager handlers ----
@@ -156,6 +190,9 @@ func (h *BookingHandler) CreateBooking(w http.ResponseWriter, r *http.Request) {
 		GuestIDNumber:         body.GuestIDNumber,
 		GuestIDType:           body.GuestIDType,
 		GuestGender:           body.GuestGender,
+		Adults:                body.Adults,
+		Children:              body.Children,
+		Guests:                bookingGuestInputs(body.Guests),
 	})
 	if err != nil {
 		HandleErrorResponse(w, err)
@@ -368,6 +405,8 @@ func (h *BookingHandler) UpdateBooking(w http.ResponseWriter, r *http.Request) {
 		RequiresUpfrontPayment: body.RequiresUpfrontPayment,
 		CheckInDate:            body.CheckInDate,
 		CheckOutDate:           body.CheckOutDate,
+		Adults:                 body.Adults,
+		Children:               body.Children,
 		Meta:                   body.Meta,
 	})
 	if err != nil {
@@ -505,6 +544,150 @@ func (h *BookingHandler) CancelBooking(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBBookingToRest(booking)})
 }
 
+// MarkBookingRefundPaid godoc
+//
+//	@Summary		Mark a booking refund as paid
+//	@Description	Record that the refund owed on a cancelled booking, or for a credit left by a change to its stay, has been sent back to the guest
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string							true	"Client ID"
+//	@Param			property_id	path		string							true	"Property ID"
+//	@Param			booking_id	path		string							true	"Booking ID"
+//	@Param			body		body		MarkBookingRefundPaidRequest	true	"Refund payment"
+//	@Success		200			{object}	object{data=transformations.OutputBookingRefund}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/bookings/{booking_id}/refund/paid [patch]
+func (h *BookingHandler) MarkBookingRefundPaid(w http.ResponseWriter, r *http.Request) {
+	clientUser, _ := lib.ClientUserFromContext(r.Context())
+
+	var body MarkBookingRefundPaidRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	refund, err := h.bookingService.MarkBookingRefundPaid(r.Context(), services.MarkBookingRefundPaidInput{
+		BookingID:        chi.URLParam(r, "booking_id"),
+		RefundID:         body.RefundID,
+		ClientUserID:     clientUser.ID,
+		PaymentReference: body.PaymentReference,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBBookingRefundToRest(refund)})
+}
+
+// ExtendBookingHold godoc
+//
+//	@Summary		Extend a booking's hold
+//	@Description	Give the guest of a pending booking on hold more time to pay before it is cancelled
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string						true	"Client ID"
+//	@Param			property_id	path		string						true	"Property ID"
+//	@Param			booking_id	path		string						true	"Booking ID"
+//	@Param			body		body		ExtendBookingHoldRequest	true	"New hold expiry"
+//	@Success		200			{object}	object{data=transformations.AdminOutputBooking}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/bookings/{booking_id}/hold [patch]
+func (h *BookingHandler) ExtendBookingHold(w http.ResponseWriter, r *http.Request) {
+	var body ExtendBookingHoldRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	booking, err := h.bookingService.ExtendBookingHold(r.Context(), services.ExtendBookingHoldInput{
+		BookingID: chi.URLParam(r, "booking_id"),
+		ExpiresAt: body.HoldExpiresAt,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBBookingToRest(booking)})
+}
+
+// SetBookingGuests godoc
+//
+//	@Summary		Register a booking's guests
+//	@Description	Replace the guests registered on a booking with who is staying. A property that requires guest registration checks no booking in until everyone is named.
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string					true	"Client ID"
+//	@Param			property_id	path		string					true	"Property ID"
+//	@Param			booking_id	path		string					true	"Booking ID"
+//	@Param			body		body		SetBookingGuestsRequest	true	"Guest list"
+//	@Success		200			{object}	object{data=[]transformations.OutputBookingGuest}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/bookings/{booking_id}/guests [put]
+func (h *BookingHandler) SetBookingGuests(w http.ResponseWriter, r *http.Request) {
+	var body SetBookingGuestsRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	guests, err := h.bookingService.SetBookingGuests(r.Context(), services.SetBookingGuestsInput{
+		BookingID: chi.URLParam(r, "booking_id"),
+		Guests:    bookingGuestInputs(body.Guests),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	guestsTransformed := make([]any, 0, len(guests))
+	for i := range guests {
+		guestsTransformed = append(guestsTransformed, transformations.DBBookingGuestToRest(&guests[i]))
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": guestsTransformed})
+}
+
+func bookingGuestInputs(guests []BookingGuestRequest) []services.BookingGuestInput {
+	inputs := make([]services.BookingGuestInput, 0, len(guests))
+	for _, guest := range guests {
+		inputs = append(inputs, services.BookingGuestInput{
+			FirstName:   guest.FirstName,
+			LastName:    guest.LastName,
+			IsChild:     guest.IsChild,
+			Nationality: guest.Nationality,
+			IDType:      guest.IDType,
+			IDNumber:    guest.IDNumber,
+		})
+	}
+	return inputs
+}
+
 type GetAvailabilityFilterRequest struct {
 	From string `json:"from" validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
 	To   string `json:"to"   validate:"required,datetime=2006-01-02T15:04:05Z07:00"`
@@ -683,7 +866,7 @@ func (h *BookingHandler) PublicGetAvailability(w http.ResponseWriter, r *http.Re
 
 	out := make([]any, len(blocks))
 	for i := range blocks {
-		out[i] = transformations.DBUnitDateBlockToRest(&blocks[i])
+		out[i] = transformations.DBUnitDateBlockToPublicRest(&blocks[i])
 	}
 	json.NewEncoder(w).Encode(map[string]any{"data": out})
 }
@@ -736,7 +919,6 @@ func (h *BookingHandler) PublicCreateBooking(w http.ResponseWriter, r *http.Requ
 		PropertyID:     unit.PropertyID,
 		CheckInDate:    body.CheckInDate,
 		CheckOutDate:   body.CheckOutDate,
-		Rate:           unit.RentFee,
 		Currency:       unit.RentFeeCurrency,
 		StayFrequency:  unit.PaymentFrequency,
 		BookingSource:  "GUEST_LINK",
@@ -747,6 +929,9 @@ func (h *BookingHandler) PublicCreateBooking(w http.ResponseWriter, r *http.Requ
 		GuestIDType:    body.IDType,
 		GuestIDNumber:  body.IDNumber,
 		GuestGender:    body.Gender,
+		Adults:         body.Adults,
+		Children:       body.Children,
+		Guests:         bookingGuestInputs(body.Guests),
 	})
 	if err != nil {
 		HandleErrorResponse(w, err)
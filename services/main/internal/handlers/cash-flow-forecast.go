@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+)
+
+type CashFlowForecastHandler struct {
+	appCtx  pkg.AppContext
+	service services.CashFlowForecastService
+}
+
+func NewCashFlowForecastHandler(
+	appCtx pkg.AppContext,
+	service services.CashFlowForecastService,
+) CashFlowForecastHandler {
+	return CashFlowForecastHandler{appCtx: appCtx, service: service}
+}
+
+// GetForecast godoc
+//
+//	@Summary		Cash-flow forecast
+//	@Description	Buckets expected inflows by week or month from every unsettled charge due before end_date (overdue ones land in the first bucket), weights each by its tenant's historical on-time payment rate, and subtracts expenses scheduled in the window. Amounts are converted to the client's reporting currency at the latest synced rates. Scoped to the property when called under one; otherwise to the caller's properties, optionally narrowed by property_id or to one owner's portfolio with owner_id. format=csv returns the buckets as a CSV download.
+//	@Tags			Forecasts
+//	@Produce		json
+//	@Produce		text/csv
+//	@Security		BearerAuth
+//	@Param			property_id	path		string											false	"Property ID (property-scoped route only)"
+//	@Param			property_id	query		[]string										false	"Property ID(s) to narrow the forecast to (client-level route)"	collectionFormat(multi)
+//	@Param			owner_id	query		string											false	"Client user whose assigned properties to forecast"
+//	@Param			interval	query		string											false	"WEEK or MONTH (default MONTH)"
+//	@Param			start_date	query		string											false	"RFC3339 or YYYY-MM-DD (default today)"
+//	@Param			end_date	query		string											false	"RFC3339 or YYYY-MM-DD, exclusive (default three months after start_date)"
+//	@Param			format		query		string											false	"json (default) or csv"
+//	@Success		200			{object}	object{data=transformations.OutputCashFlowForecast}	"Forecast"
+//	@Failure		400			{object}	lib.HTTPError									"Invalid interval, dates or missing exchange rate"
+//	@Failure		401			{object}	string											"Invalid or absent authentication token"
+//	@Failure		403			{object}	string											"Requested property_id is outside the caller's access scope"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/cash-flow-forecast [get]
+//	@Router			/api/v1/admin/clients/{client_id}/cash-flow-forecast [get]
+func (h *CashFlowForecastHandler) GetForecast(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	query := r.URL.Query()
+
+	startDate, err := ParseDateParam(query.Get("start_date"))
+	if err != nil {
+		HandleErrorResponse(w, pkg.BadRequestError("InvalidStartDate", nil))
+		return
+	}
+	endDate, err := ParseDateParam(query.Get("end_date"))
+	if err != nil {
+		HandleErrorResponse(w, pkg.BadRequestError("InvalidEndDate", nil))
+		return
+	}
+
+	now := time.Now().UTC()
+	if startDate == nil {
+		today := now.Truncate(24 * time.Hour)
+		startDate = &today
+	}
+	if endDate == nil {
+		defaultEnd := startDate.AddDate(0, 3, 0)
+		endDate = &defaultEnd
+	}
+
+	interval := query.Get("interval")
+	if interval == "" {
+		interval = services.ForecastIntervalMonth
+	}
+
+	scope := repository.CashFlowForecastScope{
+		ClientID:          clientUser.ClientID,
+		OwnerClientUserID: lib.NullOrString(query.Get("owner_id")),
+	}
+	if propertyID := chi.URLParam(r, "property_id"); propertyID != "" {
+		scope.PropertyIDs = &[]string{propertyID}
+	} else {
+		propertyIDs, currentUserID, scopeOk := ValidateRequestedPropertyAccess(w, r, h.appCtx)
+		if !scopeOk {
+			return
+		}
+		scope.PropertyIDs = propertyIDs
+		scope.ClientUserID = &currentUserID
+	}
+
+	forecast, err := h.service.Forecast(r.Context(), services.CashFlowForecastInput{
+		Scope:     scope,
+		Interval:  interval,
+		StartDate: *startDate,
+		EndDate:   *endDate,
+		AsOf:      now,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	if query.Get("format") == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set(
+			"Content-Disposition",
+			fmt.Sprintf(`attachment; filename="cash-flow-forecast-%s.csv"`, startDate.Format("2006-01-02")),
+		)
+		if csvErr := transformations.WriteCashFlowForecastCSV(w, forecast); csvErr != nil {
+			logrus.WithError(csvErr).Error("failed to write cash-flow forecast csv")
+		}
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.CashFlowForecastToRest(forecast)})
+}
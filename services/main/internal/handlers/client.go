@@ -46,6 +46,11 @@ type UpdateClientRequest struct {
 	IDNumber      lib.Optional[string] `json:"id_number"                                                                                                                                                                                        swaggertype:"string"`
 	IDExpiry      lib.Optional[string] `json:"id_expiry"                                                                                                                                                                                        swaggertype:"string"`
 	IDDocumentURL lib.Optional[string] `json:"id_document_url"     validate:"omitempty,url"                                                                                                                                                     swaggertype:"string"`
+	// signing settings
+	SigningOtpRequired     *bool     `json:"signing_otp_required"      validate:"omitempty"      example:"false"                             description:"Require a one-time code before anyone signs the org's documents by link"`
+	SigningOtpDocumentTags *[]string `json:"signing_otp_document_tags" validate:"omitempty,dive" example:"LEASE_AGREEMENT,INSPECTION_REPORT" description:"Require a one-time code before signing documents with any of these tags"`
+	// listing settings
+	PublicListingEnabled *bool `json:"public_listing_enabled" validate:"omitempty" example:"true" description:"List the org's vacant and bookable units in the public unit search"`
 }
 
 // UpdateClient godoc
@@ -113,6 +118,11 @@ func (h *clientHandler) UpdateClient(w http.ResponseWriter, r *http.Request) {
 		IDNumber:           body.IDNumber,
 		IDExpiry:           body.IDExpiry,
 		IDDocumentURL:      body.IDDocumentURL,
+		// signing settings
+		SigningOtpRequired:     body.SigningOtpRequired,
+		SigningOtpDocumentTags: body.SigningOtpDocumentTags,
+		// listing settings
+		PublicListingEnabled: body.PublicListingEnabled,
 	}
 
 	client, err := h.service.UpdateClient(r.Context(), input)
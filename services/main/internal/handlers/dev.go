@@ -120,6 +120,7 @@ type RunLeaseLifecycleBody struct {
 type RunLeaseLifecycleResponse struct {
 	Activated int `json:"activated" example:"1"`
 	Completed int `json:"completed" example:"1"`
+	HeldOver  int `json:"held_over" example:"0"`
 	Failed    int `json:"failed"    example:"0"`
 }
 
@@ -168,7 +169,7 @@ func (h *DevHandler) RunLeaseLifecycle(w http.ResponseWriter, r *http.Request) {
 	// pair cannot rely on this ordering — see the note in RegisterScheduler —
 	// which is why dueForActivationScope excludes leases already past move-out
 	// rather than depending on who runs first.
-	completed, completionFailures, err := h.leaseService.CompleteDueLeases(r.Context(), onlyLeaseID)
+	completed, heldOver, completionFailures, err := h.leaseService.CompleteDueLeases(r.Context(), onlyLeaseID)
 	if err != nil {
 		HandleErrorResponse(w, err)
 		return
@@ -178,6 +179,7 @@ func (h *DevHandler) RunLeaseLifecycle(w http.ResponseWriter, r *http.Request) {
 		"data": RunLeaseLifecycleResponse{
 			Activated: activated,
 			Completed: completed,
+			HeldOver:  heldOver,
 			Failed:    activationFailures + completionFailures,
 		},
 	})
@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/go-chi/chi/v5"
+)
+
+type ListDocumentRevisionsFilterRequest struct {
+	lib.FilterQueryInput
+}
+
+// ListDocumentRevisions godoc
+//
+//	@Summary		List a document's revisions (Admin)
+//	@Description	List the revisions recorded each time a document's title or content changed, newest first (Admin)
+//	@Tags			Documents
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			document_id	path		string								true	"Document ID"	format(uuid4)
+//	@Param			q			query		ListDocumentRevisionsFilterRequest	true	"Filter query"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputAdminDocumentRevision,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/documents/{document_id}/revisions [get]
+func (h *DocumentHandler) ListDocumentRevisions(w http.ResponseWriter, r *http.Request) {
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filterQuery, w) {
+		return
+	}
+
+	input := repository.ListDocumentRevisionsFilter{
+		FilterQuery: *filterQuery,
+		DocumentID:  chi.URLParam(r, "document_id"),
+	}
+
+	revisions, revisionsErr := h.service.ListRevisions(r.Context(), input)
+	if revisionsErr != nil {
+		HandleErrorResponse(w, revisionsErr)
+		return
+	}
+
+	count, countErr := h.service.CountRevisions(r.Context(), input)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, 0, len(revisions))
+	for _, revision := range revisions {
+		rows = append(rows, transformations.DBDocumentRevisionToRest(&revision))
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+type GetDocumentRevisionQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetDocumentRevision godoc
+//
+//	@Summary		Get a document revision (Admin)
+//	@Description	Get one revision of a document, with its content as it stood (Admin)
+//	@Tags			Documents
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			document_id	path		string														true	"Document ID"	format(uuid4)
+//	@Param			revision_id	path		string														true	"Revision ID"	format(uuid4)
+//	@Param			q			query		GetDocumentRevisionQuery									true	"Query parameters"
+//	@Success		200			{object}	object{data=transformations.OutputAdminDocumentRevision}
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/documents/{document_id}/revisions/{revision_id} [get]
+func (h *DocumentHandler) GetDocumentRevision(w http.ResponseWriter, r *http.Request) {
+	revision, err := h.service.GetRevision(r.Context(), repository.GetDocumentRevisionQuery{
+		ID:         chi.URLParam(r, "revision_id"),
+		DocumentID: chi.URLParam(r, "document_id"),
+		Populate:   GetPopulateFields(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBDocumentRevisionToRest(revision),
+	})
+}
+
+type DiffDocumentRevisionsRequest struct {
+	From string  `json:"from" validate:"required,uuid4"  example:"550e8400-e29b-41d4-a716-446655440000" description:"Revision to compare from"`
+	To   *string `json:"to"   validate:"omitempty,uuid4" example:"660e8400-e29b-41d4-a716-446655440000" description:"Revision to compare to; the current revision when omitted"`
+}
+
+// DiffDocumentRevisions godoc
+//
+//	@Summary		Compare two document revisions (Admin)
+//	@Description	List what changed between two revisions of a document, node by node of the editor's tree (Admin). Each change is ADDED, REMOVED or CHANGED, located by its path of child indexes from the root in each revision, with the node's text before and after. Compares to the current revision when to is omitted.
+//	@Tags			Documents
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			document_id	path		string							true	"Document ID"	format(uuid4)
+//	@Param			q			query		DiffDocumentRevisionsRequest	true	"Revisions to compare"
+//	@Success		200			{object}	object{data=transformations.OutputDocumentRevisionDiff}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/documents/{document_id}/revisions/diff [get]
+func (h *DocumentHandler) DiffDocumentRevisions(w http.ResponseWriter, r *http.Request) {
+	query := DiffDocumentRevisionsRequest{
+		From: r.URL.Query().Get("from"),
+		To:   lib.NullOrString(r.URL.Query().Get("to")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, query, w) {
+		return
+	}
+
+	diff, err := h.service.DiffRevisions(r.Context(), services.DiffDocumentRevisionsInput{
+		DocumentID:     chi.URLParam(r, "document_id"),
+		FromRevisionID: query.From,
+		ToRevisionID:   query.To,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DocumentRevisionDiffToRest(diff),
+	})
+}
+
+// RestoreDocumentRevision godoc
+//
+//	@Summary		Restore a document revision (Admin)
+//	@Description	Bring back a revision's title and content (Admin). The restored content is recorded as a new revision, so the revisions after the one restored are kept.
+//	@Tags			Documents
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			document_id	path		string												true	"Document ID"	format(uuid4)
+//	@Param			revision_id	path		string												true	"Revision ID"	format(uuid4)
+//	@Success		200			{object}	object{data=transformations.OutputAdminDocument}	"Revision restored successfully"
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/documents/{document_id}/revisions/{revision_id}/restore [post]
+func (h *DocumentHandler) RestoreDocumentRevision(w http.ResponseWriter, r *http.Request) {
+	currentUser, currentUserOk := lib.ClientUserFromContext(r.Context())
+	if !currentUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	document, err := h.service.RestoreRevision(r.Context(), services.RestoreDocumentRevisionInput{
+		DocumentID:   chi.URLParam(r, "document_id"),
+		RevisionID:   chi.URLParam(r, "revision_id"),
+		ClientUserID: currentUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminDocumentToRestDocument(document),
+	})
+}
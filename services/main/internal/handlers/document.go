@@ -166,6 +166,69 @@ func (h *DocumentHandler) AdminUpdateDocument(w http.ResponseWriter, r *http.Req
 	})
 }
 
+type InstantiateDocumentTemplateRequest struct {
+	Title               *string `json:"title,omitempty"                 validate:"omitempty"                                                       example:"Lease Agreement - Unit A4"`
+	LeaseID             *string `json:"lease_id,omitempty"              validate:"required_with=LeaseTerminationID,omitempty,uuid4"                example:"550e8400-e29b-41d4-a716-446655440000" description:"Lease to resolve merge fields from; also the lease of lease_termination_id"`
+	TenantApplicationID *string `json:"tenant_application_id,omitempty" validate:"required_without_all=LeaseID LeaseTerminationID,omitempty,uuid4" example:"550e8400-e29b-41d4-a716-446655440000" description:"Tenant application to resolve merge fields from"`
+	LeaseTerminationID  *string `json:"lease_termination_id,omitempty"  validate:"omitempty,uuid4"                                                 example:"550e8400-e29b-41d4-a716-446655440000" description:"Lease termination to resolve merge fields from"`
+}
+
+// InstantiateDocumentTemplate godoc
+//
+//	@Summary		Create a document from a template (Admin)
+//	@Description	Create a DOCUMENT from a TEMPLATE with its merge fields resolved from a lease, tenant application or lease termination. Fields the record has no value for are left as #Field for the PM to fill in; finalizing the document is refused until they are.
+//	@Tags			Documents
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			document_id	path		string												true	"Template document ID"	format(uuid4)
+//	@Param			body		body		InstantiateDocumentTemplateRequest					true	"Record to resolve merge fields from"
+//	@Success		201			{object}	object{data=transformations.OutputAdminDocument}	"Document created successfully"
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/documents/{document_id}/instantiate [post]
+func (h *DocumentHandler) InstantiateDocumentTemplate(w http.ResponseWriter, r *http.Request) {
+	currentUser, currentUserOk := lib.ClientUserFromContext(r.Context())
+
+	if !currentUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body InstantiateDocumentTemplateRequest
+
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	isPassedValidation := lib.ValidateRequest(h.appCtx.Validator, body, w)
+
+	if !isPassedValidation {
+		return
+	}
+
+	document, err := h.service.InstantiateTemplate(r.Context(), services.InstantiateDocumentTemplateInput{
+		TemplateID:          chi.URLParam(r, "document_id"),
+		Title:               body.Title,
+		LeaseID:             body.LeaseID,
+		TenantApplicationID: body.TenantApplicationID,
+		LeaseTerminationID:  body.LeaseTerminationID,
+		ClientUserID:        currentUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminDocumentToRestDocument(document),
+	})
+}
+
 type UpdateDocumentRequest struct {
 	Content *string `json:"content,omitempty" validate:"omitempty,json"`
 }
@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
@@ -28,11 +29,12 @@ type ListExpensesQuery struct {
 }
 
 type AddExpenseBody struct {
-	ContextType                 string  `json:"context_type"                   validate:"required,oneof=MAINTENANCE"`
-	ContextMaintenanceRequestID *string `json:"context_maintenance_request_id" validate:"omitempty,uuid4"`
-	Description                 string  `json:"description"                    validate:"required"`
-	Amount                      int64   `json:"amount"                         validate:"required,gt=0"`
-	Currency                    string  `json:"currency"                       validate:"omitempty"`
+	ContextType                 string     `json:"context_type"                   validate:"required,oneof=MAINTENANCE"`
+	ContextMaintenanceRequestID *string    `json:"context_maintenance_request_id" validate:"omitempty,uuid4"`
+	Description                 string     `json:"description"                    validate:"required"`
+	Amount                      int64      `json:"amount"                         validate:"required,gt=0"`
+	Currency                    string     `json:"currency"                       validate:"omitempty"`
+	ScheduledFor                *time.Time `json:"scheduled_for,omitempty"        validate:"omitempty"                  example:"2026-11-01T00:00:00Z"`
 }
 
 // ─── Handlers ─────────────────────────────────────────────────────────────────
@@ -40,7 +42,7 @@ type AddExpenseBody struct {
 // AddExpense godoc
 //
 //	@Summary		Add an expense to a property
-//	@Description	Create a new expense scoped to a property (context_type determines lease or maintenance) (Admin)
+//	@Description	Create a new expense scoped to a property (context_type determines lease or maintenance). Set scheduled_for for a cost that is committed but not yet paid; the cash-flow forecast subtracts it in the period it falls in, and it is posted once settled (Admin)
 //	@Tags			Expenses
 //	@Accept			json
 //	@Produce		json
@@ -75,6 +77,7 @@ func (h *ExpenseHandler) AddExpense(w http.ResponseWriter, r *http.Request) {
 		Description:                 body.Description,
 		Amount:                      body.Amount,
 		Currency:                    body.Currency,
+		ScheduledFor:                body.ScheduledFor,
 		ClientUserID:                currentUser.ID,
 	})
 	if err != nil {
@@ -121,6 +124,43 @@ func (h *ExpenseHandler) GetExpense(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// SettleExpense godoc
+//
+//	@Summary		Settle a scheduled expense
+//	@Description	Record a scheduled expense as paid. It is posted to the books and no longer subtracted by the cash-flow forecast (Admin)
+//	@Tags			Expenses
+//	@Accept			json
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			property_id	path		string										true	"Property ID"
+//	@Param			expense_id	path		string										true	"Expense ID"
+//	@Success		200			{object}	object{data=transformations.OutputExpense}	"Settled expense"
+//	@Failure		400			{object}	lib.HTTPError								"Expense already paid"
+//	@Failure		401			{object}	string										"Invalid or absent authentication token"
+//	@Failure		404			{object}	lib.HTTPError								"Expense not found"
+//	@Failure		500			{object}	string										"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/expenses/{expense_id}/settle [post]
+func (h *ExpenseHandler) SettleExpense(w http.ResponseWriter, r *http.Request) {
+	_, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	expense, err := h.service.SettleExpense(r.Context(), services.SettleExpenseInput{
+		ExpenseID:  chi.URLParam(r, "expense_id"),
+		PropertyID: chi.URLParam(r, "property_id"),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBExpenseToRest(expense),
+	})
+}
+
 // DeleteExpense godoc
 //
 //	@Summary		Delete an expense
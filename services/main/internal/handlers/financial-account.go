@@ -15,10 +15,11 @@ import (
 )
 
 type FinancialAccountHandler struct {
-	financials     *financials.Financials
-	invoiceService services.InvoiceService
-	leaseService   services.LeaseService
-	appCtx         pkg.AppContext
+	financials         *financials.Financials
+	invoiceService     services.InvoiceService
+	leaseService       services.LeaseService
+	leaseTenantService services.LeaseTenantService
+	appCtx             pkg.AppContext
 }
 
 func NewFinancialAccountHandler(
@@ -26,12 +27,14 @@ func NewFinancialAccountHandler(
 	financialsFacade *financials.Financials,
 	invoiceService services.InvoiceService,
 	leaseService services.LeaseService,
+	leaseTenantService services.LeaseTenantService,
 ) FinancialAccountHandler {
 	return FinancialAccountHandler{
-		appCtx:         appCtx,
-		financials:     financialsFacade,
-		invoiceService: invoiceService,
-		leaseService:   leaseService,
+		appCtx:             appCtx,
+		financials:         financialsFacade,
+		invoiceService:     invoiceService,
+		leaseService:       leaseService,
+		leaseTenantService: leaseTenantService,
 	}
 }
 
@@ -98,6 +101,9 @@ type accountSummaryResponse struct {
 	TotalSettled      int64                                   `json:"total_settled"`
 	OutstandingAmount int64                                   `json:"outstanding_amount"`
 	AvailableCredit   int64                                   `json:"available_credit"`
+	// PaymentsByPayer attributes successful payments to the tenant who made
+	// them, for a lease several tenants share. It does not change the totals.
+	PaymentsByPayer []transformations.OutputPayerPayment `json:"payments_by_payer"`
 	// ClosureEligibility is the PM's closure checklist: every gate with its
 	// blocking reason, so the UI can render the panel without a second call.
 	ClosureEligibility *financials.ClosureEligibility `json:"closure_eligibility,omitempty"`
@@ -438,6 +444,56 @@ func (h *FinancialAccountHandler) ComposeInvoice(w http.ResponseWriter, r *http.
 	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBInvoiceToRest(invoice)})
 }
 
+// PreviewIssuance godoc
+//
+//	@Summary		Preview the next invoice issuance sweep
+//	@Description	Dry run of the nightly issuance sweep at as_of (default now): for every billable account in scope, the invoice that would be issued, its line items, total and who would be notified. Uses the sweep's own selection, so what is shown is what would go out. Nothing is reserved, created or sent. Scoped to the property when called under one, otherwise to the whole client.
+//	@Tags			FinancialAccounts
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			property_id	path		string													false	"Property ID (property-scoped route only)"
+//	@Param			as_of		query		string													false	"RFC3339 instant to preview the sweep at"
+//	@Success		200			{object}	object{data=[]transformations.OutputIssuancePreview}	"Invoices that would be issued"
+//	@Failure		400			{object}	lib.HTTPError											"as_of is not a valid RFC3339 timestamp"
+//	@Failure		401			{object}	string													"Invalid or absent authentication token"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/financial-accounts/issuance:preview [get]
+//	@Router			/api/v1/admin/clients/{client_id}/financial-accounts/issuance:preview [get]
+func (h *FinancialAccountHandler) PreviewIssuance(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	asOf := time.Now()
+	if raw := r.URL.Query().Get("as_of"); raw != "" {
+		parsed, parseErr := time.Parse(time.RFC3339, raw)
+		if parseErr != nil {
+			HandleErrorResponse(w, pkg.BadRequestError("InvalidAsOf", nil))
+			return
+		}
+		asOf = parsed
+	}
+
+	clientID := clientUser.ClientID
+	previews, err := h.invoiceService.PreviewIssuance(r.Context(), services.PreviewIssuanceInput{
+		ClientID:   &clientID,
+		PropertyID: lib.NullOrString(chi.URLParam(r, "property_id")),
+		AsOf:       asOf,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	data := make([]transformations.OutputIssuancePreview, 0, len(previews))
+	for _, preview := range previews {
+		data = append(data, transformations.IssuancePreviewToRest(preview))
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": data})
+}
+
 // ─── Tenant-facing (read-only) ────────────────────────────────────────────────
 
 // TenantGetAccount godoc
@@ -500,9 +556,10 @@ func (h *FinancialAccountHandler) TenantListCharges(w http.ResponseWriter, r *ht
 	json.NewEncoder(w).Encode(map[string]any{"data": payload.Charges})
 }
 
-// tenantAccountSummary resolves the lease's account after confirming the lease
-// belongs to the authenticated tenant. Without the ownership check any tenant
-// could read any other tenant's balance by guessing a lease ID.
+// tenantAccountSummary resolves the lease's account after confirming the
+// authenticated tenant is on the lease. Without the ownership check any tenant
+// could read any other tenant's balance by guessing a lease ID. Co-tenants and
+// occupants share the primary's account, so they see the same balance.
 func (h *FinancialAccountHandler) tenantAccountSummary(
 	r *http.Request,
 ) (*financials.AccountSummary, error) {
@@ -512,16 +569,16 @@ func (h *FinancialAccountHandler) tenantAccountSummary(
 	}
 
 	leaseID := chi.URLParam(r, "lease_id")
-	populate := []string{"Tenant.TenantAccount"}
-	lease, leaseErr := h.leaseService.GetByIDWithPopulate(r.Context(), repository.GetLeaseQuery{
-		ID:       leaseID,
-		Populate: &populate,
-	})
+	lease, leaseErr := h.leaseService.GetByIDWithPopulate(r.Context(), repository.GetLeaseQuery{ID: leaseID})
 	if leaseErr != nil {
 		return nil, leaseErr
 	}
 
-	if lease.Tenant.TenantAccount == nil || lease.Tenant.TenantAccount.ID.String() != tenantAccount.ID {
+	onLease, onLeaseErr := h.leaseTenantService.HasTenantAccount(r.Context(), leaseID, tenantAccount.ID)
+	if onLeaseErr != nil {
+		return nil, onLeaseErr
+	}
+	if !onLease {
 		return nil, pkg.ForbiddenError("LeaseDoesNotBelongToTenant", nil)
 	}
 
@@ -566,6 +623,14 @@ func (h *FinancialAccountHandler) summaryToRest(
 		charges = append(charges, transformations.DBChargeInstanceToRest(&instances[i]))
 	}
 
+	byPayer := make([]transformations.OutputPayerPayment, 0, len(summary.PaymentsByPayer))
+	for _, total := range summary.PaymentsByPayer {
+		byPayer = append(byPayer, transformations.OutputPayerPayment{
+			PayerTenantID: total.PayerTenantID,
+			Amount:        total.Amount,
+		})
+	}
+
 	response := accountSummaryResponse{
 		Account:           transformations.DBFinancialAccountToRest(summary.Account),
 		Charges:           charges,
@@ -573,6 +638,7 @@ func (h *FinancialAccountHandler) summaryToRest(
 		TotalSettled:      summary.TotalSettled,
 		OutstandingAmount: summary.OutstandingAmount,
 		AvailableCredit:   summary.AvailableCredit,
+		PaymentsByPayer:   byPayer,
 	}
 
 	// Advisory: a failure here must not fail the read the caller asked for.
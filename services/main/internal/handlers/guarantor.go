@@ -0,0 +1,270 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type GuarantorHandler struct {
+	appCtx  pkg.AppContext
+	service services.GuarantorService
+}
+
+func NewGuarantorHandler(appCtx pkg.AppContext, service services.GuarantorService) GuarantorHandler {
+	return GuarantorHandler{appCtx: appCtx, service: service}
+}
+
+// guarantorOwner reads which application or lease the request is about. The
+// same handlers serve both route trees, so exactly one of the two is set.
+func guarantorOwner(r *http.Request) repository.ListGuarantorsFilter {
+	if leaseID := chi.URLParam(r, "lease_id"); leaseID != "" {
+		return repository.ListGuarantorsFilter{LeaseID: &leaseID}
+	}
+
+	tenantApplicationID := chi.URLParam(r, "tenant_application_id")
+	return repository.ListGuarantorsFilter{TenantApplicationID: &tenantApplicationID}
+}
+
+// ListGuarantors godoc
+//
+//	@Summary		List guarantors (Admin)
+//	@Description	List the guarantors on a tenant application or a lease
+//	@Tags			Guarantor
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id				path		string											true	"Client ID"
+//	@Param			property_id				path		string											true	"Property ID"
+//	@Param			tenant_application_id	path		string											false	"Tenant application ID"
+//	@Param			lease_id				path		string											false	"Lease ID"
+//	@Success		200						{object}	object{data=[]transformations.OutputGuarantor}	"Guarantors"
+//	@Failure		401						{object}	string											"Invalid or absent authentication token"
+//	@Failure		500						{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/tenant-applications/{tenant_application_id}/guarantors [get]
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/guarantors [get]
+func (h *GuarantorHandler) ListGuarantors(w http.ResponseWriter, r *http.Request) {
+	guarantors, err := h.service.List(r.Context(), guarantorOwner(r))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	rows := make([]any, 0, len(*guarantors))
+	for i := range *guarantors {
+		rows = append(rows, transformations.DBGuarantorToRest(&(*guarantors)[i]))
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": rows,
+	})
+}
+
+type CreateGuarantorRequest struct {
+	FirstName             string  `json:"first_name"                        validate:"required"                                          example:"Kwame"                            description:"First name"`
+	LastName              string  `json:"last_name"                         validate:"required"                                          example:"Mensah"                           description:"Last name"`
+	Email                 *string `json:"email,omitempty"                   validate:"omitempty,email"                                   example:"kwame@example.com"                description:"Email address"`
+	Phone                 string  `json:"phone"                             validate:"required,e164"                                     example:"+233281234569"                    description:"Phone number"`
+	RelationshipToTenant  *string `json:"relationship_to_tenant,omitempty"                                                               example:"Parent"                           description:"How the guarantor knows the tenant"`
+	IDType                *string `json:"id_type,omitempty"                 validate:"omitempty,oneof=NationalID Passport DriverLicense" example:"NationalID"                       description:"Type of ID"`
+	IDNumber              *string `json:"id_number,omitempty"                                                                            example:"GHA-123456789-0"                  description:"ID number"`
+	IDFrontUrl            *string `json:"id_front_url,omitempty"            validate:"omitempty,url"                                     example:"https://example.com/id-front.jpg" description:"Front of the ID"`
+	IDBackUrl             *string `json:"id_back_url,omitempty"             validate:"omitempty,url"                                     example:"https://example.com/id-back.jpg"  description:"Back of the ID"`
+	Occupation            *string `json:"occupation,omitempty"                                                                           example:"Civil Servant"                    description:"Occupation"`
+	Employer              *string `json:"employer,omitempty"                                                                             example:"Ghana Education Service"          description:"Employer"`
+	ProofOfIncomeUrl      *string `json:"proof_of_income_url,omitempty"     validate:"omitempty,url"                                     example:"https://example.com/payslip.pdf"  description:"Payslip, bank statement or similar"`
+	LiabilityCap          *int64  `json:"liability_cap,omitempty"           validate:"omitempty,gt=0"                                    example:"1200000"                          description:"Most the guarantor will cover, in the smallest currency unit. Omit for an unlimited guarantee."`
+	LiabilityCapCurrency  *string `json:"liability_cap_currency,omitempty"  validate:"required_with=LiabilityCap"                        example:"GHS"                              description:"Currency of the liability cap"`
+	ArrearsEscalationDays *int64  `json:"arrears_escalation_days,omitempty" validate:"omitempty,min=1"                                   example:"7"                                description:"Days a rent invoice may be overdue before reminders also go to the guarantor. Omit to never escalate."`
+}
+
+// CreateGuarantor godoc
+//
+//	@Summary		Add guarantor (Admin)
+//	@Description	Add a guarantor to a tenant application or a lease. An application's guarantors move to its lease on approval.
+//	@Tags			Guarantor
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id				path		string											true	"Client ID"
+//	@Param			property_id				path		string											true	"Property ID"
+//	@Param			tenant_application_id	path		string											false	"Tenant application ID"
+//	@Param			lease_id				path		string											false	"Lease ID"
+//	@Param			body					body		CreateGuarantorRequest							true	"Create guarantor request body"
+//	@Success		201						{object}	object{data=transformations.OutputGuarantor}	"Guarantor added"
+//	@Failure		400						{object}	lib.HTTPError									"Application or lease no longer editable"
+//	@Failure		401						{object}	string											"Invalid or absent authentication token"
+//	@Failure		404						{object}	lib.HTTPError									"Application or lease not found"
+//	@Failure		422						{object}	lib.HTTPError									"Validation error"
+//	@Failure		500						{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/tenant-applications/{tenant_application_id}/guarantors [post]
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/guarantors [post]
+func (h *GuarantorHandler) CreateGuarantor(w http.ResponseWriter, r *http.Request) {
+	clientUser, clientUserOk := lib.ClientUserFromContext(r.Context())
+	if !clientUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CreateGuarantorRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	owner := guarantorOwner(r)
+	guarantor, err := h.service.Create(r.Context(), services.CreateGuarantorInput{
+		TenantApplicationID:   owner.TenantApplicationID,
+		LeaseID:               owner.LeaseID,
+		FirstName:             body.FirstName,
+		LastName:              body.LastName,
+		Email:                 body.Email,
+		Phone:                 body.Phone,
+		RelationshipToTenant:  body.RelationshipToTenant,
+		IDType:                body.IDType,
+		IDNumber:              body.IDNumber,
+		IDFrontUrl:            body.IDFrontUrl,
+		IDBackUrl:             body.IDBackUrl,
+		Occupation:            body.Occupation,
+		Employer:              body.Employer,
+		ProofOfIncomeUrl:      body.ProofOfIncomeUrl,
+		LiabilityCap:          body.LiabilityCap,
+		LiabilityCapCurrency:  body.LiabilityCapCurrency,
+		ArrearsEscalationDays: body.ArrearsEscalationDays,
+		CreatedById:           clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBGuarantorToRest(guarantor),
+	})
+}
+
+type UpdateGuarantorRequest struct {
+	FirstName             *string              `json:"first_name,omitempty"              validate:"omitempty,min=1" example:"Kwame"         description:"First name"`
+	LastName              *string              `json:"last_name,omitempty"               validate:"omitempty,min=1" example:"Mensah"        description:"Last name"`
+	Phone                 *string              `json:"phone,omitempty"                   validate:"omitempty,e164"  example:"+233281234569" description:"Phone number"`
+	Email                 lib.Optional[string] `json:"email,omitempty"                                                                      description:"Email address"                                     swaggertype:"string"`
+	RelationshipToTenant  lib.Optional[string] `json:"relationship_to_tenant,omitempty"                                                     description:"How the guarantor knows the tenant"                swaggertype:"string"`
+	IDType                lib.Optional[string] `json:"id_type,omitempty"                                                                    description:"Type of ID"                                        swaggertype:"string"`
+	IDNumber              lib.Optional[string] `json:"id_number,omitempty"                                                                  description:"ID number"                                         swaggertype:"string"`
+	IDFrontUrl            lib.Optional[string] `json:"id_front_url,omitempty"                                                               description:"Front of the ID"                                   swaggertype:"string"`
+	IDBackUrl             lib.Optional[string] `json:"id_back_url,omitempty"                                                                description:"Back of the ID"                                    swaggertype:"string"`
+	Occupation            lib.Optional[string] `json:"occupation,omitempty"                                                                 description:"Occupation"                                        swaggertype:"string"`
+	Employer              lib.Optional[string] `json:"employer,omitempty"                                                                   description:"Employer"                                          swaggertype:"string"`
+	ProofOfIncomeUrl      lib.Optional[string] `json:"proof_of_income_url,omitempty"                                                        description:"Payslip, bank statement or similar"                swaggertype:"string"`
+	LiabilityCap          lib.Optional[int64]  `json:"liability_cap,omitempty"                                                              description:"Most the guarantor will cover; null for unlimited" swaggertype:"integer"`
+	LiabilityCapCurrency  lib.Optional[string] `json:"liability_cap_currency,omitempty"                                                     description:"Currency of the liability cap"                     swaggertype:"string"`
+	ArrearsEscalationDays lib.Optional[int64]  `json:"arrears_escalation_days,omitempty"                                                    description:"Days overdue before escalation; null to never"     swaggertype:"integer"`
+}
+
+// UpdateGuarantor godoc
+//
+//	@Summary		Update guarantor (Admin)
+//	@Description	Update a guarantor. Once the guarantor has signed, only arrears_escalation_days can change — the rest is what they signed.
+//	@Tags			Guarantor
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id				path		string											true	"Client ID"
+//	@Param			property_id				path		string											true	"Property ID"
+//	@Param			tenant_application_id	path		string											false	"Tenant application ID"
+//	@Param			lease_id				path		string											false	"Lease ID"
+//	@Param			guarantor_id			path		string											true	"Guarantor ID"
+//	@Param			body					body		UpdateGuarantorRequest							true	"Update guarantor request body"
+//	@Success		200						{object}	object{data=transformations.OutputGuarantor}	"Guarantor updated"
+//	@Failure		400						{object}	lib.HTTPError									"Guarantor already signed, or owner no longer editable"
+//	@Failure		401						{object}	string											"Invalid or absent authentication token"
+//	@Failure		404						{object}	lib.HTTPError									"Not found"
+//	@Failure		422						{object}	lib.HTTPError									"Validation error"
+//	@Failure		500						{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/tenant-applications/{tenant_application_id}/guarantors/{guarantor_id} [patch]
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/guarantors/{guarantor_id} [patch]
+func (h *GuarantorHandler) UpdateGuarantor(w http.ResponseWriter, r *http.Request) {
+	var body UpdateGuarantorRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	guarantor, err := h.service.Update(r.Context(), services.UpdateGuarantorInput{
+		Query: repository.GetGuarantorQuery{
+			ID:                   chi.URLParam(r, "guarantor_id"),
+			ListGuarantorsFilter: guarantorOwner(r),
+		},
+		FirstName:             body.FirstName,
+		LastName:              body.LastName,
+		Phone:                 body.Phone,
+		Email:                 body.Email,
+		RelationshipToTenant:  body.RelationshipToTenant,
+		IDType:                body.IDType,
+		IDNumber:              body.IDNumber,
+		IDFrontUrl:            body.IDFrontUrl,
+		IDBackUrl:             body.IDBackUrl,
+		Occupation:            body.Occupation,
+		Employer:              body.Employer,
+		ProofOfIncomeUrl:      body.ProofOfIncomeUrl,
+		LiabilityCap:          body.LiabilityCap,
+		LiabilityCapCurrency:  body.LiabilityCapCurrency,
+		ArrearsEscalationDays: body.ArrearsEscalationDays,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBGuarantorToRest(guarantor),
+	})
+}
+
+// DeleteGuarantor godoc
+//
+//	@Summary		Remove guarantor (Admin)
+//	@Description	Remove a guarantor who has not signed anything yet
+//	@Tags			Guarantor
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id				path		string			true	"Client ID"
+//	@Param			property_id				path		string			true	"Property ID"
+//	@Param			tenant_application_id	path		string			false	"Tenant application ID"
+//	@Param			lease_id				path		string			false	"Lease ID"
+//	@Param			guarantor_id			path		string			true	"Guarantor ID"
+//	@Success		204						{object}	nil				"Removed"
+//	@Failure		400						{object}	lib.HTTPError	"Guarantor already signed, or owner no longer editable"
+//	@Failure		401						{object}	string			"Invalid or absent authentication token"
+//	@Failure		404						{object}	lib.HTTPError	"Not found"
+//	@Failure		500						{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/tenant-applications/{tenant_application_id}/guarantors/{guarantor_id} [delete]
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/guarantors/{guarantor_id} [delete]
+func (h *GuarantorHandler) DeleteGuarantor(w http.ResponseWriter, r *http.Request) {
+	err := h.service.Delete(r.Context(), repository.GetGuarantorQuery{
+		ID:                   chi.URLParam(r, "guarantor_id"),
+		ListGuarantorsFilter: guarantorOwner(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -14,18 +14,16 @@ import (
 )
 
 type InvoiceHandler struct {
-	appCtx               pkg.AppContext
-	service              services.InvoiceService
-	services             services.Services
-	tenantAccountService services.TenantAccountService
+	appCtx   pkg.AppContext
+	service  services.InvoiceService
+	services services.Services
 }
 
 func NewInvoiceHandler(appCtx pkg.AppContext, services services.Services) InvoiceHandler {
 	return InvoiceHandler{
-		appCtx:               appCtx,
-		service:              services.InvoiceService,
-		services:             services,
-		tenantAccountService: services.TenantAccountService,
+		appCtx:   appCtx,
+		service:  services.InvoiceService,
+		services: services,
 	}
 }
 
@@ -199,7 +197,7 @@ type VoidInvoiceBody struct {
 // VoidInvoice godoc
 //
 //	@Summary		Void invoice (Admin)
-//	@Description	Void an existing invoice (Admin)
+//	@Description	Void a draft or issued invoice (Admin)
 //	@Tags			Invoice
 //	@Accept			json
 //	@Security		BearerAuth
@@ -727,6 +725,7 @@ type TenantListInvoicesQuery struct {
 //	@Success		200			{object}	object{data=object{rows=[]transformations.OutputInvoice,meta=lib.HTTPReturnPaginatedMetaResponse}}	"Invoices"
 //	@Failure		400			{object}	lib.HTTPError																						"Invalid query parameters"
 //	@Failure		401			{object}	string																								"Invalid or absent authentication token"
+//	@Failure		403			{object}	string																								"Forbidden"
 //	@Failure		500			{object}	string																								"An unexpected error occurred"
 //	@Router			/api/v1/leases/{lease_id}/invoices [get]
 func (h *InvoiceHandler) TenantListInvoices(w http.ResponseWriter, r *http.Request) {
@@ -736,11 +735,18 @@ func (h *InvoiceHandler) TenantListInvoices(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	account, err := h.tenantAccountService.GetMe(r.Context(), tenantAccount.ID)
+	leaseID := chi.URLParam(r, "lease_id")
+
+	// Verify the authenticated tenant is on this lease, as primary or co-tenant
+	onLease, err := h.services.LeaseTenantService.HasTenantAccount(r.Context(), leaseID, tenantAccount.ID)
 	if err != nil {
 		HandleErrorResponse(w, err)
 		return
 	}
+	if !onLease {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
 
 	query := TenantListInvoicesQuery{
 		ContextTenantApplicationID: lib.NullOrString(r.URL.Query().Get("context_tenant_application_id")),
@@ -762,11 +768,8 @@ func (h *InvoiceHandler) TenantListInvoices(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	leaseID := chi.URLParam(r, "lease_id")
-
 	input := repository.TenantListInvoicesFilter{
 		FilterQuery:         *filterQuery,
-		TenantID:            account.TenantId,
 		LeaseID:             leaseID,
 		TenantApplicationID: query.ContextTenantApplicationID,
 		Status:              query.Status,
@@ -798,6 +801,7 @@ func (h *InvoiceHandler) TenantListInvoices(w http.ResponseWriter, r *http.Reque
 //	@Param			lease_id	path		string												true	"Lease ID"
 //	@Success		200			{object}	object{data=transformations.InvoiceStatsResponse}	"Invoice stats"
 //	@Failure		401			{object}	string												"Invalid or absent authentication token"
+//	@Failure		403			{object}	string												"Forbidden"
 //	@Failure		500			{object}	string												"An unexpected error occurred"
 //	@Router			/api/v1/leases/{lease_id}/invoices/stats [get]
 func (h *InvoiceHandler) TenantInvoiceStats(w http.ResponseWriter, r *http.Request) {
@@ -807,12 +811,6 @@ func (h *InvoiceHandler) TenantInvoiceStats(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	account, err := h.tenantAccountService.GetMe(r.Context(), tenantAccount.ID)
-	if err != nil {
-		HandleErrorResponse(w, err)
-		return
-	}
-
 	leaseID := chi.URLParam(r, "lease_id")
 	lease, err := h.services.LeaseService.GetByIDWithPopulate(r.Context(), repository.GetLeaseQuery{
 		ID: leaseID,
@@ -822,7 +820,13 @@ func (h *InvoiceHandler) TenantInvoiceStats(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	if lease.TenantId != account.TenantId {
+	// Verify the authenticated tenant is on this lease, as primary or co-tenant
+	onLease, err := h.services.LeaseTenantService.HasTenantAccount(r.Context(), leaseID, tenantAccount.ID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+	if !onLease {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
@@ -879,12 +883,6 @@ func (h *InvoiceHandler) TenantGetInvoice(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	account, err := h.tenantAccountService.GetMe(r.Context(), tenantAccount.ID)
-	if err != nil {
-		HandleErrorResponse(w, err)
-		return
-	}
-
 	leaseID := chi.URLParam(r, "lease_id")
 	lease, err := h.services.LeaseService.GetByIDWithPopulate(r.Context(), repository.GetLeaseQuery{
 		ID: leaseID,
@@ -894,7 +892,13 @@ func (h *InvoiceHandler) TenantGetInvoice(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if lease.TenantId != account.TenantId {
+	// Verify the authenticated tenant is on this lease, as primary or co-tenant
+	onLease, err := h.services.LeaseTenantService.HasTenantAccount(r.Context(), leaseID, tenantAccount.ID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+	if !onLease {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
@@ -955,12 +959,6 @@ func (h *InvoiceHandler) TenantListPaymentAccounts(w http.ResponseWriter, r *htt
 		return
 	}
 
-	account, err := h.tenantAccountService.GetMe(r.Context(), tenantAccount.ID)
-	if err != nil {
-		HandleErrorResponse(w, err)
-		return
-	}
-
 	leaseID := chi.URLParam(r, "lease_id")
 
 	// Fetch lease with unit and property to resolve the client (property manager)
@@ -973,8 +971,13 @@ func (h *InvoiceHandler) TenantListPaymentAccounts(w http.ResponseWriter, r *htt
 		return
 	}
 
-	// Verify the authenticated tenant owns this lease
-	if lease.TenantId != account.TenantId {
+	// Verify the authenticated tenant is on this lease, as primary or co-tenant
+	onLease, err := h.services.LeaseTenantService.HasTenantAccount(r.Context(), leaseID, tenantAccount.ID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+	if !onLease {
 		http.Error(w, "Forbidden", http.StatusForbidden)
 		return
 	}
@@ -1007,11 +1010,12 @@ func (h *InvoiceHandler) TenantListPaymentAccounts(w http.ResponseWriter, r *htt
 }
 
 type ManagerPayInvoiceRequest struct {
-	PaymentAccountID string          `json:"payment_account_id"  validate:"required,uuid4"                                                example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" description:"ID of the payment account used"`
-	Amount           int64           `json:"amount"              validate:"required"                                                      example:"1000"                                 description:"Amount to pay for the invoice"`
-	Provider         string          `json:"provider"            validate:"required,oneof=MTN VODAFONE AIRTELTIGO PAYSTACK BANK_API CASH" example:"CASH"                                 description:"Offline payment provider/method"`
-	Reference        *string         `json:"reference,omitempty"                                                                          example:"RCP-2024-001"                         description:"Optional reference number for the payment"`
-	Metadata         *map[string]any `json:"metadata,omitempty"                                                                                                                          description:"Additional metadata for the payment"`
+	PaymentAccountID string          `json:"payment_account_id"        validate:"required,uuid4"                                                example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" description:"ID of the payment account used"`
+	Amount           int64           `json:"amount"                    validate:"required"                                                      example:"1000"                                 description:"Amount to pay for the invoice"`
+	Provider         string          `json:"provider"                  validate:"required,oneof=MTN VODAFONE AIRTELTIGO PAYSTACK BANK_API CASH" example:"CASH"                                 description:"Offline payment provider/method"`
+	Reference        *string         `json:"reference,omitempty"                                                                                example:"RCP-2024-001"                         description:"Optional reference number for the payment"`
+	Metadata         *map[string]any `json:"metadata,omitempty"                                                                                                                                description:"Additional metadata for the payment"`
+	PayerTenantID    *string         `json:"payer_tenant_id,omitempty" validate:"omitempty,uuid4"                                               example:"b50874ee-1a70-436e-ba24-572078895982" description:"The lease tenant who paid, when a lease has several. Ignored on the public pay link."`
 }
 
 // PayInvoice godoc
@@ -1068,6 +1072,7 @@ func (h *InvoiceHandler) ManagerPayInvoice(w http.ResponseWriter, r *http.Reques
 			Reference:               body.Reference,
 			Metadata:                body.Metadata,
 			InitiatedByClientUserID: &clientUser.ID,
+			PayerTenantID:           body.PayerTenantID,
 		},
 	)
 	if createErr != nil {
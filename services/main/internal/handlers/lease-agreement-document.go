@@ -224,7 +224,7 @@ func (h *LeaseAgreementDocumentHandler) RevertLeaseAgreementDocumentToDraft(w ht
 // FinalizeLeaseAgreementDocument godoc
 //
 //	@Summary		Finalize lease agreement document
-//	@Description	Lock the document content and advance status from DRAFT to FINALIZED.
+//	@Description	Lock the document content and advance status from DRAFT to FINALIZED. ONLINE documents are rendered to an unsigned PDF, returned as document_url; the signed PDF replaces it once every party has signed.
 //	@Tags			Lease
 //	@Accept			json
 //	@Security		BearerAuth
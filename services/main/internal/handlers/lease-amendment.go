@@ -0,0 +1,371 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type LeaseAmendmentHandler struct {
+	appCtx  pkg.AppContext
+	service services.LeaseAmendmentService
+}
+
+func NewLeaseAmendmentHandler(appCtx pkg.AppContext, service services.LeaseAmendmentService) LeaseAmendmentHandler {
+	return LeaseAmendmentHandler{appCtx: appCtx, service: service}
+}
+
+type LeaseAmendmentChargeRequest struct {
+	Name     string `json:"name"     validate:"required"                                example:"Parking"`
+	Category string `json:"category" validate:"required,oneof=AGENCY_FEE VAT UTILITY OTHER" example:"OTHER"`
+	Amount   int64  `json:"amount"   validate:"required,gt=0"                           example:"15000"`
+}
+
+func leaseAmendmentChargesFromRequest(charges []LeaseAmendmentChargeRequest) []models.LeaseAmendmentCharge {
+	result := make([]models.LeaseAmendmentCharge, 0, len(charges))
+	for _, charge := range charges {
+		result = append(result, models.LeaseAmendmentCharge{
+			Name:     charge.Name,
+			Category: charge.Category,
+			Amount:   charge.Amount,
+		})
+	}
+	return result
+}
+
+type CreateLeaseAmendmentRequest struct {
+	Reason                string                        `json:"reason"                            validate:"required"                            example:"Parking bay added"     description:"Why the terms are changing"`
+	EffectiveDate         time.Time                     `json:"effective_date"                    validate:"required"                            example:"2026-11-01T00:00:00Z"  description:"Date the new terms take effect (RFC3339 format)"`
+	RentFee               *int64                        `json:"rent_fee,omitempty"                validate:"omitempty,gte=0"                     example:"450000"                description:"Amended rent per payment period"`
+	StayDuration          *int64                        `json:"stay_duration,omitempty"           validate:"omitempty,gt=0"                      example:"18"                    description:"Amended length of the whole term, counted from move-in"`
+	StayDurationFrequency *string                       `json:"stay_duration_frequency,omitempty" validate:"omitempty,oneof=Hours Days Months"   example:"Months"                description:"Unit of stay duration"`
+	AddedCharges          []LeaseAmendmentChargeRequest `json:"added_charges,omitempty"           validate:"omitempty,dive"                                                      description:"Recurring charges billed alongside the rent from the effective date"`
+}
+
+// CreateLeaseAmendment godoc
+//
+//	@Summary		Create lease amendment (Admin)
+//	@Description	Draft a mid-term change to an active lease's rent, term or recurring charges
+//	@Tags			LeaseAmendment
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string											true	"Client ID"
+//	@Param			property_id	path		string											true	"Property ID"
+//	@Param			lease_id	path		string											true	"Lease ID"
+//	@Param			body		body		CreateLeaseAmendmentRequest						true	"Create amendment request body"
+//	@Success		201			{object}	object{data=transformations.OutputLeaseAmendment}	"Amendment Created"
+//	@Failure		400			{object}	lib.HTTPError									"Lease not Active, amendment changes nothing or a draft already exists"
+//	@Failure		401			{object}	string											"Invalid or absent authentication token"
+//	@Failure		422			{object}	lib.HTTPError									"Validation error"
+//	@Failure		500			{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/amendments [post]
+func (h *LeaseAmendmentHandler) CreateLeaseAmendment(w http.ResponseWriter, r *http.Request) {
+	clientUser, clientUserOk := lib.ClientUserFromContext(r.Context())
+	if !clientUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CreateLeaseAmendmentRequest
+	leaseID := chi.URLParam(r, "lease_id")
+
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	amendment, err := h.service.Create(r.Context(), services.CreateLeaseAmendmentInput{
+		LeaseID:               leaseID,
+		Reason:                body.Reason,
+		EffectiveDate:         body.EffectiveDate,
+		RentFee:               body.RentFee,
+		StayDuration:          body.StayDuration,
+		StayDurationFrequency: body.StayDurationFrequency,
+		AddedCharges:          leaseAmendmentChargesFromRequest(body.AddedCharges),
+		InitiatedById:         clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminLeaseAmendmentToRest(amendment),
+	})
+}
+
+type ListLeaseAmendmentsQuery struct {
+	lib.FilterQueryInput
+	Status *string `json:"status,omitempty" validate:"omitempty,oneof=LeaseAmendment.Status.Draft LeaseAmendment.Status.Executed LeaseAmendment.Status.Cancelled" example:"LeaseAmendment.Status.Executed" description:"Amendment status"`
+}
+
+// ListLeaseAmendments godoc
+//
+//	@Summary		List lease amendments (Admin)
+//	@Description	List amendments for a lease. The executed ones, ordered by version, are the lease's terms history.
+//	@Tags			LeaseAmendment
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string						true	"Client ID"
+//	@Param			property_id	path		string						true	"Property ID"
+//	@Param			lease_id	path		string						true	"Lease ID"
+//	@Param			q			query		ListLeaseAmendmentsQuery	true	"Query parameters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputLeaseAmendment,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError	"Error occurred"
+//	@Failure		401			{object}	string			"Invalid or absent authentication token"
+//	@Failure		500			{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/amendments [get]
+func (h *LeaseAmendmentHandler) ListLeaseAmendments(w http.ResponseWriter, r *http.Request) {
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filterQuery, w) {
+		return
+	}
+
+	leaseID := chi.URLParam(r, "lease_id")
+
+	filter := repository.ListLeaseAmendmentsFilter{
+		FilterQuery: *filterQuery,
+		LeaseID:     &leaseID,
+		Status:      lib.NullOrString(r.URL.Query().Get("status")),
+	}
+
+	amendments, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	count, countErr := h.service.Count(r.Context(), filter)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, len(amendments))
+	for i := range amendments {
+		rows[i] = transformations.DBAdminLeaseAmendmentToRest(&amendments[i])
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+type GetLeaseAmendmentQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetLeaseAmendment godoc
+//
+//	@Summary		Get lease amendment (Admin)
+//	@Description	Get a single lease amendment by ID
+//	@Tags			LeaseAmendment
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id		path		string												true	"Client ID"
+//	@Param			property_id		path		string												true	"Property ID"
+//	@Param			lease_id		path		string												true	"Lease ID"
+//	@Param			amendment_id	path		string												true	"Amendment ID"
+//	@Param			q				query		GetLeaseAmendmentQuery								true	"LeaseAmendments query parameters"
+//	@Success		200				{object}	object{data=transformations.OutputLeaseAmendment}	"Amendment"
+//	@Failure		401				{object}	string												"Invalid or absent authentication token"
+//	@Failure		404				{object}	lib.HTTPError										"Not found"
+//	@Failure		500				{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/amendments/{amendment_id} [get]
+func (h *LeaseAmendmentHandler) GetLeaseAmendment(w http.ResponseWriter, r *http.Request) {
+	leaseID := chi.URLParam(r, "lease_id")
+	amendmentID := chi.URLParam(r, "amendment_id")
+	populate := GetPopulateFields(r)
+
+	amendment, err := h.service.GetOne(r.Context(), repository.GetLeaseAmendmentQuery{
+		ID:       amendmentID,
+		LeaseID:  leaseID,
+		Populate: populate,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminLeaseAmendmentToRest(amendment),
+	})
+}
+
+type UpdateLeaseAmendmentRequest struct {
+	Reason                *string                        `json:"reason,omitempty"                  validate:"omitempty,min=1"  example:"Parking bay added"    description:"Why the terms are changing"`
+	EffectiveDate         *time.Time                     `json:"effective_date,omitempty"                                      example:"2026-11-01T00:00:00Z" description:"Date the new terms take effect (RFC3339 format)"`
+	RentFee               lib.Optional[int64]            `json:"rent_fee,omitempty"                                                                           description:"Amended rent per payment period"                        swaggertype:"integer"`
+	StayDuration          lib.Optional[int64]            `json:"stay_duration,omitempty"                                                                      description:"Amended length of the whole term, counted from move-in" swaggertype:"integer"`
+	StayDurationFrequency lib.Optional[string]           `json:"stay_duration_frequency,omitempty"                                                            description:"Unit of stay duration"                                  swaggertype:"string"`
+	AddedCharges          *[]LeaseAmendmentChargeRequest `json:"added_charges,omitempty"           validate:"omitempty,dive"                                  description:"Replaces the recurring charges the amendment adds"`
+	DocumentMode          lib.Optional[string]           `json:"document_mode,omitempty"                                                                      description:"MANUAL or ONLINE"                                       swaggertype:"string"`
+	DocumentUrl           lib.Optional[string]           `json:"document_url,omitempty"                                                                       description:"External amendment document URL (MANUAL mode)"          swaggertype:"string"`
+	DocumentId            lib.Optional[string]           `json:"document_id,omitempty"                                                                        description:"Library document ID (ONLINE mode)"                      swaggertype:"string"`
+}
+
+// UpdateLeaseAmendment godoc
+//
+//	@Summary		Update lease amendment (Admin)
+//	@Description	Update fields on a draft lease amendment
+//	@Tags			LeaseAmendment
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id		path		string												true	"Client ID"
+//	@Param			property_id		path		string												true	"Property ID"
+//	@Param			lease_id		path		string												true	"Lease ID"
+//	@Param			amendment_id	path		string												true	"Amendment ID"
+//	@Param			body			body		UpdateLeaseAmendmentRequest							true	"Update request body"
+//	@Success		200				{object}	object{data=transformations.OutputLeaseAmendment}	"Updated"
+//	@Failure		400				{object}	lib.HTTPError										"Amendment not Draft"
+//	@Failure		401				{object}	string												"Invalid or absent authentication token"
+//	@Failure		404				{object}	lib.HTTPError										"Not found"
+//	@Failure		422				{object}	lib.HTTPError										"Validation error"
+//	@Failure		500				{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/amendments/{amendment_id} [patch]
+func (h *LeaseAmendmentHandler) UpdateLeaseAmendment(w http.ResponseWriter, r *http.Request) {
+	var body UpdateLeaseAmendmentRequest
+	leaseID := chi.URLParam(r, "lease_id")
+	amendmentID := chi.URLParam(r, "amendment_id")
+
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	var addedCharges *[]models.LeaseAmendmentCharge
+	if body.AddedCharges != nil {
+		charges := leaseAmendmentChargesFromRequest(*body.AddedCharges)
+		addedCharges = &charges
+	}
+
+	amendment, err := h.service.Update(r.Context(), services.UpdateLeaseAmendmentInput{
+		ID:                    amendmentID,
+		LeaseID:               leaseID,
+		Reason:                body.Reason,
+		EffectiveDate:         body.EffectiveDate,
+		RentFee:               body.RentFee,
+		StayDuration:          body.StayDuration,
+		StayDurationFrequency: body.StayDurationFrequency,
+		AddedCharges:          addedCharges,
+		DocumentMode:          body.DocumentMode,
+		DocumentUrl:           body.DocumentUrl,
+		DocumentId:            body.DocumentId,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminLeaseAmendmentToRest(amendment),
+	})
+}
+
+// ExecuteLeaseAmendment godoc
+//
+//	@Summary		Execute lease amendment (Admin)
+//	@Description	Apply a signed amendment — rebuilds the charge schedule from the effective date, recomputes the move-out date and bumps the lease version (transactional)
+//	@Tags			LeaseAmendment
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id		path		string												true	"Client ID"
+//	@Param			property_id		path		string												true	"Property ID"
+//	@Param			lease_id		path		string												true	"Lease ID"
+//	@Param			amendment_id	path		string												true	"Amendment ID"
+//	@Success		200				{object}	object{data=transformations.OutputLeaseAmendment}	"Executed"
+//	@Failure		400				{object}	lib.HTTPError										"Amendment not Draft, document not signed, or amended periods already billed"
+//	@Failure		401				{object}	string												"Invalid or absent authentication token"
+//	@Failure		404				{object}	lib.HTTPError										"Not found"
+//	@Failure		500				{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/amendments/{amendment_id}/execute [patch]
+func (h *LeaseAmendmentHandler) ExecuteLeaseAmendment(w http.ResponseWriter, r *http.Request) {
+	clientUser, clientUserOk := lib.ClientUserFromContext(r.Context())
+	if !clientUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	leaseID := chi.URLParam(r, "lease_id")
+	amendmentID := chi.URLParam(r, "amendment_id")
+
+	amendment, err := h.service.Execute(r.Context(), services.ExecuteLeaseAmendmentInput{
+		ID:           amendmentID,
+		LeaseID:      leaseID,
+		ClientUserID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminLeaseAmendmentToRest(amendment),
+	})
+}
+
+// CancelLeaseAmendment godoc
+//
+//	@Summary		Cancel lease amendment (Admin)
+//	@Description	Cancel a draft lease amendment — the lease keeps its current terms
+//	@Tags			LeaseAmendment
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id		path		string			true	"Client ID"
+//	@Param			property_id		path		string			true	"Property ID"
+//	@Param			lease_id		path		string			true	"Lease ID"
+//	@Param			amendment_id	path		string			true	"Amendment ID"
+//	@Success		204				{object}	nil				"Cancelled"
+//	@Failure		400				{object}	lib.HTTPError	"Amendment not Draft"
+//	@Failure		401				{object}	string			"Invalid or absent authentication token"
+//	@Failure		404				{object}	lib.HTTPError	"Not found"
+//	@Failure		500				{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/amendments/{amendment_id}/cancel [patch]
+func (h *LeaseAmendmentHandler) CancelLeaseAmendment(w http.ResponseWriter, r *http.Request) {
+	clientUser, clientUserOk := lib.ClientUserFromContext(r.Context())
+	if !clientUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	leaseID := chi.URLParam(r, "lease_id")
+	amendmentID := chi.URLParam(r, "amendment_id")
+
+	err := h.service.Cancel(r.Context(), services.CancelLeaseAmendmentInput{
+		ID:           amendmentID,
+		LeaseID:      leaseID,
+		ClientUserID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
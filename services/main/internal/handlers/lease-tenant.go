@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type LeaseTenantHandler struct {
+	appCtx  pkg.AppContext
+	service services.LeaseTenantService
+}
+
+func NewLeaseTenantHandler(appCtx pkg.AppContext, service services.LeaseTenantService) LeaseTenantHandler {
+	return LeaseTenantHandler{appCtx: appCtx, service: service}
+}
+
+// ListLeaseTenants godoc
+//
+//	@Summary		List lease tenants (Admin)
+//	@Description	List everyone on a lease — the primary tenant first, then co-tenants and occupants
+//	@Tags			LeaseTenant
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string											true	"Client ID"
+//	@Param			property_id	path		string											true	"Property ID"
+//	@Param			lease_id	path		string											true	"Lease ID"
+//	@Success		200			{object}	object{data=[]transformations.OutputLeaseTenant}	"Lease tenants"
+//	@Failure		401			{object}	string											"Invalid or absent authentication token"
+//	@Failure		500			{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/tenants [get]
+func (h *LeaseTenantHandler) ListLeaseTenants(w http.ResponseWriter, r *http.Request) {
+	leaseID := chi.URLParam(r, "lease_id")
+
+	leaseTenants, err := h.service.List(r.Context(), leaseID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	rows := make([]any, 0, len(*leaseTenants))
+	for i := range *leaseTenants {
+		rows = append(rows, transformations.DBLeaseTenantToRest(&(*leaseTenants)[i]))
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": rows,
+	})
+}
+
+type AddLeaseTenantRequest struct {
+	Role      string  `json:"role"            validate:"required,oneof=CO_TENANT OCCUPANT" example:"CO_TENANT"       description:"CO_TENANT signs the agreement and shares liability; OCCUPANT only lives in the unit"`
+	FirstName string  `json:"first_name"      validate:"required"                          example:"Ama"             description:"First name"`
+	LastName  string  `json:"last_name"       validate:"required"                          example:"Mensah"          description:"Last name"`
+	Phone     string  `json:"phone"           validate:"required,e164"                     example:"+233281234569"   description:"Phone number — an existing tenant with this number is reused"`
+	Email     *string `json:"email,omitempty" validate:"omitempty,email"                   example:"ama@example.com" description:"Email address"`
+	Gender    string  `json:"gender"          validate:"required,oneof=MALE FEMALE"        example:"FEMALE"          description:"Gender"`
+}
+
+// AddLeaseTenant godoc
+//
+//	@Summary		Add lease tenant (Admin)
+//	@Description	Put a co-tenant or occupant on a lease. Co-tenants can only be added while the lease is Pending.
+//	@Tags			LeaseTenant
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string											true	"Client ID"
+//	@Param			property_id	path		string											true	"Property ID"
+//	@Param			lease_id	path		string											true	"Lease ID"
+//	@Param			body		body		AddLeaseTenantRequest							true	"Add lease tenant request body"
+//	@Success		201			{object}	object{data=transformations.OutputLeaseTenant}	"Lease tenant added"
+//	@Failure		400			{object}	lib.HTTPError									"Lease not editable, or tenant already on the lease"
+//	@Failure		401			{object}	string											"Invalid or absent authentication token"
+//	@Failure		404			{object}	lib.HTTPError									"Lease not found"
+//	@Failure		422			{object}	lib.HTTPError									"Validation error"
+//	@Failure		500			{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/tenants [post]
+func (h *LeaseTenantHandler) AddLeaseTenant(w http.ResponseWriter, r *http.Request) {
+	clientUser, clientUserOk := lib.ClientUserFromContext(r.Context())
+	if !clientUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body AddLeaseTenantRequest
+	leaseID := chi.URLParam(r, "lease_id")
+
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	leaseTenant, err := h.service.Add(r.Context(), services.AddLeaseTenantInput{
+		LeaseID:     leaseID,
+		Role:        body.Role,
+		FirstName:   body.FirstName,
+		LastName:    body.LastName,
+		Phone:       body.Phone,
+		Email:       body.Email,
+		Gender:      body.Gender,
+		CreatedById: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBLeaseTenantToRest(leaseTenant),
+	})
+}
+
+type UpdateLeaseTenantRoleRequest struct {
+	Role string `json:"role" validate:"required,oneof=CO_TENANT OCCUPANT" example:"OCCUPANT" description:"New role on the lease"`
+}
+
+// UpdateLeaseTenantRole godoc
+//
+//	@Summary		Change lease tenant role (Admin)
+//	@Description	Switch someone between co-tenant and occupant. Promotion to co-tenant is only allowed while the lease is Pending.
+//	@Tags			LeaseTenant
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id		path		string											true	"Client ID"
+//	@Param			property_id		path		string											true	"Property ID"
+//	@Param			lease_id		path		string											true	"Lease ID"
+//	@Param			lease_tenant_id	path		string											true	"Lease tenant ID"
+//	@Param			body			body		UpdateLeaseTenantRoleRequest					true	"Update role request body"
+//	@Success		200				{object}	object{data=transformations.OutputLeaseTenant}	"Lease tenant updated"
+//	@Failure		400				{object}	lib.HTTPError									"Lease not editable, or the primary tenant's role cannot change"
+//	@Failure		401				{object}	string											"Invalid or absent authentication token"
+//	@Failure		404				{object}	lib.HTTPError									"Not found"
+//	@Failure		422				{object}	lib.HTTPError									"Validation error"
+//	@Failure		500				{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/tenants/{lease_tenant_id} [patch]
+func (h *LeaseTenantHandler) UpdateLeaseTenantRole(w http.ResponseWriter, r *http.Request) {
+	var body UpdateLeaseTenantRoleRequest
+	leaseID := chi.URLParam(r, "lease_id")
+	leaseTenantID := chi.URLParam(r, "lease_tenant_id")
+
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	leaseTenant, err := h.service.UpdateRole(r.Context(), services.UpdateLeaseTenantRoleInput{
+		LeaseID:       leaseID,
+		LeaseTenantID: leaseTenantID,
+		Role:          body.Role,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBLeaseTenantToRest(leaseTenant),
+	})
+}
+
+// RemoveLeaseTenant godoc
+//
+//	@Summary		Remove lease tenant (Admin)
+//	@Description	Take a co-tenant or occupant off a lease. The primary tenant cannot be removed.
+//	@Tags			LeaseTenant
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id		path		string			true	"Client ID"
+//	@Param			property_id		path		string			true	"Property ID"
+//	@Param			lease_id		path		string			true	"Lease ID"
+//	@Param			lease_tenant_id	path		string			true	"Lease tenant ID"
+//	@Success		204				{object}	nil				"Removed"
+//	@Failure		400				{object}	lib.HTTPError	"Lease not editable, or the primary tenant"
+//	@Failure		401				{object}	string			"Invalid or absent authentication token"
+//	@Failure		404				{object}	lib.HTTPError	"Not found"
+//	@Failure		500				{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/tenants/{lease_tenant_id} [delete]
+func (h *LeaseTenantHandler) RemoveLeaseTenant(w http.ResponseWriter, r *http.Request) {
+	leaseID := chi.URLParam(r, "lease_id")
+	leaseTenantID := chi.URLParam(r, "lease_tenant_id")
+
+	if err := h.service.Remove(r.Context(), leaseID, leaseTenantID); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
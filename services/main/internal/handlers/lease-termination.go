@@ -3,6 +3,7 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
+	"time"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
@@ -13,17 +14,21 @@ import (
 )
 
 type LeaseTerminationHandler struct {
-	appCtx         pkg.AppContext
-	service        services.LeaseTerminationService
-	invoiceService services.InvoiceService
+	appCtx               pkg.AppContext
+	service              services.LeaseTerminationService
+	invoiceService       services.InvoiceService
+	tenantAccountService services.TenantAccountService
+	leaseTenantService   services.LeaseTenantService
 }
 
-func NewLeaseTerminationHandler(
-	appCtx pkg.AppContext,
-	service services.LeaseTerminationService,
-	invoiceService services.InvoiceService,
-) LeaseTerminationHandler {
-	return LeaseTerminationHandler{appCtx: appCtx, service: service, invoiceService: invoiceService}
+func NewLeaseTerminationHandler(appCtx pkg.AppContext, services services.Services) LeaseTerminationHandler {
+	return LeaseTerminationHandler{
+		appCtx:               appCtx,
+		service:              services.LeaseTerminationService,
+		invoiceService:       services.InvoiceService,
+		tenantAccountService: services.TenantAccountService,
+		leaseTenantService:   services.LeaseTenantService,
+	}
 }
 
 type CreateLeaseTerminationRequest struct {
@@ -332,3 +337,178 @@ func (h *LeaseTerminationHandler) CancelLeaseTermination(w http.ResponseWriter,
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+type GiveNoticeToVacateRequest struct {
+	IntendedMoveOutDate time.Time `json:"intended_move_out_date" validate:"required"  example:"2026-12-31T00:00:00Z"    description:"Day the tenant will hand the unit back"`
+	Reason              *string   `json:"reason,omitempty"       validate:"omitempty" example:"Relocating for work" description:"Why the tenant is leaving"`
+}
+
+// TenantGiveNoticeToVacate godoc
+//
+//	@Summary		Tenant: give notice to vacate
+//	@Description	Give notice to leave an active lease on a set date. The date must respect the notice period set on the lease or, failing that, its property. Leaving before the term ends may carry an early termination fee under the lease's policy; it is shown on the termination and billed when the property manager completes it. Only a primary tenant or co-tenant may give notice.
+//	@Tags			LeaseTermination
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id	path		string												true	"Lease ID"
+//	@Param			body		body		GiveNoticeToVacateRequest							true	"Notice to vacate"
+//	@Success		201			{object}	object{data=transformations.OutputLeaseTermination}	"Notice given"
+//	@Failure		400			{object}	lib.HTTPError										"Lease not Active, notice period not met, date after lease end, or termination already in progress"
+//	@Failure		401			{object}	string												"Invalid or absent authentication token"
+//	@Failure		403			{object}	lib.HTTPError										"Not a signatory on the lease"
+//	@Failure		404			{object}	lib.HTTPError										"Not found"
+//	@Failure		422			{object}	lib.HTTPError										"Validation error"
+//	@Failure		500			{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/notice-to-vacate [post]
+func (h *LeaseTerminationHandler) TenantGiveNoticeToVacate(w http.ResponseWriter, r *http.Request) {
+	tenantAccount, ok := lib.TenantAccountFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body GiveNoticeToVacateRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	account, err := h.tenantAccountService.GetMe(r.Context(), tenantAccount.ID)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	termination, err := h.service.GiveNotice(r.Context(), services.GiveNoticeToVacateInput{
+		LeaseID:             chi.URLParam(r, "lease_id"),
+		TenantID:            account.TenantId,
+		IntendedMoveOutDate: body.IntendedMoveOutDate,
+		Reason:              body.Reason,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBLeaseTerminationToRest(termination),
+	})
+}
+
+// TenantListLeaseTerminations godoc
+//
+//	@Summary		Tenant: list lease terminations
+//	@Description	List terminations on a lease the authenticated tenant is on, including notices they have given
+//	@Tags			LeaseTermination
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id	path		string						true	"Lease ID"
+//	@Param			q			query		ListLeaseTerminationsQuery	true	"Query parameters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputLeaseTermination,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		401			{object}	string			"Invalid or absent authentication token"
+//	@Failure		403			{object}	lib.HTTPError	"Lease does not belong to tenant"
+//	@Failure		500			{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/terminations [get]
+func (h *LeaseTerminationHandler) TenantListLeaseTerminations(w http.ResponseWriter, r *http.Request) {
+	if err := h.assertTenantOnLease(r); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filterQuery, w) {
+		return
+	}
+
+	leaseID := chi.URLParam(r, "lease_id")
+
+	filter := repository.ListLeaseTerminationsFilter{
+		FilterQuery: *filterQuery,
+		LeaseID:     &leaseID,
+		Status:      lib.NullOrString(r.URL.Query().Get("status")),
+	}
+
+	terminations, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	count, countErr := h.service.Count(r.Context(), filter)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, len(terminations))
+	for i, t := range terminations {
+		rows[i] = transformations.DBLeaseTerminationToRest(&t)
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+// TenantGetLeaseTermination godoc
+//
+//	@Summary		Tenant: get lease termination
+//	@Description	Get a single termination on a lease the authenticated tenant is on
+//	@Tags			LeaseTermination
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id		path		string												true	"Lease ID"
+//	@Param			termination_id	path		string												true	"Termination ID"
+//	@Success		200				{object}	object{data=transformations.OutputLeaseTermination}	"Termination"
+//	@Failure		401				{object}	string												"Invalid or absent authentication token"
+//	@Failure		403				{object}	lib.HTTPError										"Lease does not belong to tenant"
+//	@Failure		404				{object}	lib.HTTPError										"Not found"
+//	@Failure		500				{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/terminations/{termination_id} [get]
+func (h *LeaseTerminationHandler) TenantGetLeaseTermination(w http.ResponseWriter, r *http.Request) {
+	if err := h.assertTenantOnLease(r); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	termination, err := h.service.GetOne(r.Context(), repository.GetTerminatedLeaseQuery{
+		ID:      chi.URLParam(r, "termination_id"),
+		LeaseID: chi.URLParam(r, "lease_id"),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBLeaseTerminationToRest(termination),
+	})
+}
+
+func (h *LeaseTerminationHandler) assertTenantOnLease(r *http.Request) error {
+	tenantAccount, ok := lib.TenantAccountFromContext(r.Context())
+	if !ok {
+		return pkg.ForbiddenError("Unauthorized", nil)
+	}
+
+	onLease, err := h.leaseTenantService.HasTenantAccount(r.Context(), chi.URLParam(r, "lease_id"), tenantAccount.ID)
+	if err != nil {
+		return err
+	}
+	if !onLease {
+		return pkg.ForbiddenError("LeaseDoesNotBelongToTenant", nil)
+	}
+
+	return nil
+}
@@ -54,14 +54,18 @@ func NewLeaseHandler(appCtx pkg.AppContext, services services.Services) LeaseHan
 }
 
 type UpdateLeaseRequest struct {
-	PaymentFrequency          lib.Optional[string]    `json:"payment_frequency,omitempty"            swaggertype:"string" description:"Frequency of rent payments"`
-	MoveInDate                *time.Time              `json:"move_in_date,omitempty"                                      description:"Tenant move-in date (RFC3339 format)"            validate:"omitempty"                         example:"2024-07-01T00:00:00Z"`
-	StayDurationFrequency     *string                 `json:"stay_duration_frequency,omitempty"                           description:"Unit of stay duration (e.g., months, years)"     validate:"omitempty,oneof=Hours Days Months" example:"Hours"`
-	StayDuration              *int64                  `json:"stay_duration,omitempty"                                     description:"Length of stay in specified frequency"           validate:"omitempty,gte=0"                   example:"12"`
-	KeyHandoverDate           lib.Optional[time.Time] `json:"key_handover_date,omitempty"            swaggertype:"string" description:"Date and time for key handover (RFC3339 format)"`
-	UtilityTransfersDate      lib.Optional[time.Time] `json:"utility_transfers_date,omitempty"       swaggertype:"string" description:"Date for utility transfers (RFC3339 format)"`
-	PropertyInspectionDate    lib.Optional[time.Time] `json:"property_inspection_date,omitempty"     swaggertype:"string" description:"Date for property inspection (RFC3339 format)"`
-	LeaseAgreementDocumentUrl *string                 `json:"lease_agreement_document_url,omitempty"                      description:"URL to the lease agreement document"             validate:"omitempty,url"                     example:"https://example.com/lease.pdf"`
+	PaymentFrequency               lib.Optional[string]    `json:"payment_frequency,omitempty"                  swaggertype:"string"  description:"Frequency of rent payments"`
+	MoveInDate                     *time.Time              `json:"move_in_date,omitempty"                                             description:"Tenant move-in date (RFC3339 format)"                                                             validate:"omitempty"                               example:"2024-07-01T00:00:00Z"`
+	StayDurationFrequency          *string                 `json:"stay_duration_frequency,omitempty"                                  description:"Unit of stay duration (e.g., months, years)"                                                      validate:"omitempty,oneof=Hours Days Months"       example:"Hours"`
+	StayDuration                   *int64                  `json:"stay_duration,omitempty"                                            description:"Length of stay in specified frequency"                                                            validate:"omitempty,gte=0"                         example:"12"`
+	KeyHandoverDate                lib.Optional[time.Time] `json:"key_handover_date,omitempty"                  swaggertype:"string"  description:"Date and time for key handover (RFC3339 format)"`
+	UtilityTransfersDate           lib.Optional[time.Time] `json:"utility_transfers_date,omitempty"             swaggertype:"string"  description:"Date for utility transfers (RFC3339 format)"`
+	PropertyInspectionDate         lib.Optional[time.Time] `json:"property_inspection_date,omitempty"           swaggertype:"string"  description:"Date for property inspection (RFC3339 format)"`
+	LeaseAgreementDocumentUrl      *string                 `json:"lease_agreement_document_url,omitempty"                             description:"URL to the lease agreement document"                                                              validate:"omitempty,url"                           example:"https://example.com/lease.pdf"`
+	NoticePeriodDays               lib.Optional[int64]     `json:"notice_period_days,omitempty"                 swaggertype:"integer" description:"Days of notice the tenant must give to vacate. Null falls back to the property's notice period."`
+	EarlyTerminationFeePolicy      *string                 `json:"early_termination_fee_policy,omitempty"                             description:"How the fee for leaving before the term ends is worked out. Options: NONE | FIXED | RENT_PERIODS" validate:"omitempty,oneof=NONE FIXED RENT_PERIODS" example:"RENT_PERIODS"`
+	EarlyTerminationFeeAmount      *int64                  `json:"early_termination_fee_amount,omitempty"                             description:"The fee, for the FIXED policy"                                                                    validate:"omitempty,gte=0"                         example:"100000"`
+	EarlyTerminationFeeRentPeriods *int64                  `json:"early_termination_fee_rent_periods,omitempty"                       description:"Periods of rent charged, for the RENT_PERIODS policy"                                             validate:"omitempty,gte=0"                         example:"2"`
 }
 
 // UpdateLease godoc
@@ -97,15 +101,19 @@ func (h *LeaseHandler) UpdateLease(w http.ResponseWriter, r *http.Request) {
 	}
 
 	input := services.UpdateLeaseInput{
-		LeaseID:                   leaseID,
-		PaymentFrequency:          body.PaymentFrequency,
-		MoveInDate:                body.MoveInDate,
-		StayDurationFrequency:     body.StayDurationFrequency,
-		StayDuration:              body.StayDuration,
-		KeyHandoverDate:           body.KeyHandoverDate,
-		UtilityTransfersDate:      body.UtilityTransfersDate,
-		PropertyInspectionDate:    body.PropertyInspectionDate,
-		LeaseAgreementDocumentUrl: body.LeaseAgreementDocumentUrl,
+		LeaseID:                        leaseID,
+		PaymentFrequency:               body.PaymentFrequency,
+		MoveInDate:                     body.MoveInDate,
+		StayDurationFrequency:          body.StayDurationFrequency,
+		StayDuration:                   body.StayDuration,
+		KeyHandoverDate:                body.KeyHandoverDate,
+		UtilityTransfersDate:           body.UtilityTransfersDate,
+		PropertyInspectionDate:         body.PropertyInspectionDate,
+		LeaseAgreementDocumentUrl:      body.LeaseAgreementDocumentUrl,
+		NoticePeriodDays:               body.NoticePeriodDays,
+		EarlyTerminationFeePolicy:      body.EarlyTerminationFeePolicy,
+		EarlyTerminationFeeAmount:      body.EarlyTerminationFeeAmount,
+		EarlyTerminationFeeRentPeriods: body.EarlyTerminationFeeRentPeriods,
 	}
 
 	lease, err := h.service.UpdateLease(r.Context(), input)
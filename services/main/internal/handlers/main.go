@@ -37,8 +37,17 @@ type Handlers struct {
 	DevHandler                    DevHandler
 	AgreementHandler              AgreementHandler
 	BookingHandler                BookingHandler
+	TurnoverTaskHandler           TurnoverTaskHandler
 	LeaseTerminationHandler       LeaseTerminationHandler
+	LeaseAmendmentHandler         LeaseAmendmentHandler
+	LeaseTenantHandler            LeaseTenantHandler
+	GuarantorHandler              GuarantorHandler
+	RenewalOfferHandler           RenewalOfferHandler
 	LeaseAgreementDocumentHandler LeaseAgreementDocumentHandler
+	CashFlowForecastHandler       CashFlowForecastHandler
+	WaitlistHandler               WaitlistHandler
+	ViewingSlotHandler            ViewingSlotHandler
+	ViewingHandler                ViewingHandler
 }
 
 func NewHandlers(appCtx pkg.AppContext, services services.Services) Handlers {
@@ -86,16 +95,22 @@ func NewHandlers(appCtx pkg.AppContext, services services.Services) Handlers {
 		services.Financials,
 		services.InvoiceService,
 		services.LeaseService,
+		services.LeaseTenantService,
 	)
 	devHandler := NewDevHandler(appCtx, services.Financials, services.LeaseService)
 	agreementHandler := NewAgreementHandler(appCtx, services.AgreementService)
 	bookingHandler := NewBookingHandler(appCtx, services)
-	leaseTerminationHandler := NewLeaseTerminationHandler(
-		appCtx,
-		services.LeaseTerminationService,
-		services.InvoiceService,
-	)
+	turnoverTaskHandler := NewTurnoverTaskHandler(appCtx, services.TurnoverTaskService)
+	leaseTerminationHandler := NewLeaseTerminationHandler(appCtx, services)
+	leaseAmendmentHandler := NewLeaseAmendmentHandler(appCtx, services.LeaseAmendmentService)
+	leaseTenantHandler := NewLeaseTenantHandler(appCtx, services.LeaseTenantService)
+	guarantorHandler := NewGuarantorHandler(appCtx, services.GuarantorService)
+	renewalOfferHandler := NewRenewalOfferHandler(appCtx, services)
 	leaseAgreementDocumentHandler := NewLeaseAgreementDocumentHandler(appCtx, services.LeaseAgreementDocumentService)
+	cashFlowForecastHandler := NewCashFlowForecastHandler(appCtx, services.CashFlowForecastService)
+	waitlistHandler := NewWaitlistHandler(appCtx, services.WaitlistService)
+	viewingSlotHandler := NewViewingSlotHandler(appCtx, services.ViewingSlotService)
+	viewingHandler := NewViewingHandler(appCtx, services.ViewingService)
 
 	return Handlers{
 		NotificationHandler:           notificationHandler,
@@ -129,7 +144,16 @@ func NewHandlers(appCtx pkg.AppContext, services services.Services) Handlers {
 		DevHandler:                    devHandler,
 		AgreementHandler:              agreementHandler,
 		BookingHandler:                bookingHandler,
+		TurnoverTaskHandler:           turnoverTaskHandler,
 		LeaseTerminationHandler:       leaseTerminationHandler,
+		LeaseAmendmentHandler:         leaseAmendmentHandler,
+		LeaseTenantHandler:            leaseTenantHandler,
+		GuarantorHandler:              guarantorHandler,
+		RenewalOfferHandler:           renewalOfferHandler,
 		LeaseAgreementDocumentHandler: leaseAgreementDocumentHandler,
+		CashFlowForecastHandler:       cashFlowForecastHandler,
+		WaitlistHandler:               waitlistHandler,
+		ViewingSlotHandler:            viewingSlotHandler,
+		ViewingHandler:                viewingHandler,
 	}
 }
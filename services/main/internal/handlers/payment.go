@@ -59,6 +59,20 @@ func (h *PaymentHandler) CreateOfflinePayment(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	// The signed-in tenant is the payer. On a shared lease that is what
+	// attributes the payment to them on the account's statement.
+	tenantAccount, ok := lib.TenantAccountFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	account, accountErr := h.services.TenantAccountService.GetMe(r.Context(), tenantAccount.ID)
+	if accountErr != nil {
+		HandleErrorResponse(w, accountErr)
+		return
+	}
+
 	payment, err := h.service.CreateOfflinePayment(r.Context(), services.CreateOfflinePaymentInput{
 		PaymentAccountID: body.PaymentAccountID,
 		InvoiceID:        body.InvoiceID,
@@ -66,6 +80,7 @@ func (h *PaymentHandler) CreateOfflinePayment(w http.ResponseWriter, r *http.Req
 		Amount:           body.Amount,
 		Reference:        body.Reference,
 		Metadata:         body.Metadata,
+		PayerTenantID:    &account.TenantId,
 	})
 	if err != nil {
 		HandleErrorResponse(w, err)
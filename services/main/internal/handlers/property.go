@@ -276,21 +276,28 @@ func (h *PropertyHandler) GetPropertyBySlug(w http.ResponseWriter, r *http.Reque
 }
 
 type UpdatePropertyRequest struct {
-	Name        *string                `json:"name"        validate:"omitempty,min=3,max=100"                                                                     example:"Oceanview Apartment"                                   description:"Human-readable name of the property."`
-	Currency    *string                `json:"currency"    validate:"omitempty"                                                                                   example:"GHS"                                                   description:"Transaction currency. Must be a supported currency code."`
-	Description lib.Optional[string]   `json:"description" validate:"omitempty"                                                                                   example:"A luxurious apartment overlooking the Atlantic Ocean." description:"Brief description of the property."                           swaggertype:"string"`
-	Images      lib.Optional[[]string] `json:"images"      validate:"omitempty,dive,url"                                                                          example:"https://example.com/images/1.jpg"                      description:"Array of image URLs associated with the property."            swaggertype:"array,string"`
-	Tags        lib.Optional[[]string] `json:"tags"        validate:"omitempty,dive,min=1,max=30"                                                                 example:"beachfront,furnished"                                  description:"Tags for categorizing the property."                          swaggertype:"array,string"`
-	Modes       lib.Optional[[]string] `json:"modes"       validate:"omitempty,dive,oneof=LEASE BOOKING"                                                          example:"LEASE,BOOKING"                                         description:"Rental modes for the property. Options: LEASE | BOOKING."     swaggertype:"array,string"`
-	Latitude    *float64               `json:"latitude"    validate:"omitempty,latitude"                                                                          example:"5.6037"                                                description:"Latitude coordinate of the property."`
-	Longitude   *float64               `json:"longitude"   validate:"omitempty,longitude"                                                                         example:"-0.1870"                                               description:"Longitude coordinate of the property."`
-	Address     *string                `json:"address"     validate:"omitempty,min=5,max=200"                                                                     example:"12 Labone Crescent"                                    description:"Physical address of the property."`
-	Country     *string                `json:"country"     validate:"omitempty,min=2,max=100"                                                                     example:"Ghana"                                                 description:"Country where the property is located."`
-	Region      *string                `json:"region"      validate:"omitempty,min=2,max=100"                                                                     example:"Greater Accra"                                         description:"Region or administrative area where the property is located."`
-	City        *string                `json:"city"        validate:"omitempty,min=2,max=100"                                                                     example:"Accra"                                                 description:"City where the property is located."`
-	GPSAddress  lib.Optional[string]   `json:"gps_address" validate:"omitempty"                                                                                   example:"GA-123-4567"                                           description:"GPS or digital address reference."                            swaggertype:"string"`
-	Type        *string                `json:"type"        validate:"omitempty,oneof=SINGLE MULTI"                                                                example:"SINGLE"                                                description:"Type of the property. Options: SINGLE | MULTI."`
-	Status      *string                `json:"status"      validate:"omitempty,oneof=Property.Status.Active Property.Status.Maintenance Property.Status.Inactive" example:"Property.Status.Active"                                description:"Current operational status of the property"`
+	Name                          *string                `json:"name"                             validate:"omitempty,min=3,max=100"                                                                     example:"Oceanview Apartment"                                   description:"Human-readable name of the property."`
+	Currency                      *string                `json:"currency"                         validate:"omitempty"                                                                                   example:"GHS"                                                   description:"Transaction currency. Must be a supported currency code."`
+	Description                   lib.Optional[string]   `json:"description"                      validate:"omitempty"                                                                                   example:"A luxurious apartment overlooking the Atlantic Ocean." description:"Brief description of the property."                                                                                swaggertype:"string"`
+	Images                        lib.Optional[[]string] `json:"images"                           validate:"omitempty,dive,url"                                                                          example:"https://example.com/images/1.jpg"                      description:"Array of image URLs associated with the property."                                                                 swaggertype:"array,string"`
+	Tags                          lib.Optional[[]string] `json:"tags"                             validate:"omitempty,dive,min=1,max=30"                                                                 example:"beachfront,furnished"                                  description:"Tags for categorizing the property."                                                                               swaggertype:"array,string"`
+	Modes                         lib.Optional[[]string] `json:"modes"                            validate:"omitempty,dive,oneof=LEASE BOOKING"                                                          example:"LEASE,BOOKING"                                         description:"Rental modes for the property. Options: LEASE | BOOKING."                                                          swaggertype:"array,string"`
+	Latitude                      *float64               `json:"latitude"                         validate:"omitempty,latitude"                                                                          example:"5.6037"                                                description:"Latitude coordinate of the property."`
+	Longitude                     *float64               `json:"longitude"                        validate:"omitempty,longitude"                                                                         example:"-0.1870"                                               description:"Longitude coordinate of the property."`
+	Address                       *string                `json:"address"                          validate:"omitempty,min=5,max=200"                                                                     example:"12 Labone Crescent"                                    description:"Physical address of the property."`
+	Country                       *string                `json:"country"                          validate:"omitempty,min=2,max=100"                                                                     example:"Ghana"                                                 description:"Country where the property is located."`
+	Region                        *string                `json:"region"                           validate:"omitempty,min=2,max=100"                                                                     example:"Greater Accra"                                         description:"Region or administrative area where the property is located."`
+	City                          *string                `json:"city"                             validate:"omitempty,min=2,max=100"                                                                     example:"Accra"                                                 description:"City where the property is located."`
+	GPSAddress                    lib.Optional[string]   `json:"gps_address"                      validate:"omitempty"                                                                                   example:"GA-123-4567"                                           description:"GPS or digital address reference."                                                                                 swaggertype:"string"`
+	Type                          *string                `json:"type"                             validate:"omitempty,oneof=SINGLE MULTI"                                                                example:"SINGLE"                                                description:"Type of the property. Options: SINGLE | MULTI."`
+	Status                        *string                `json:"status"                           validate:"omitempty,oneof=Property.Status.Active Property.Status.Maintenance Property.Status.Inactive" example:"Property.Status.Active"                                description:"Current operational status of the property"`
+	HoldoverPolicy                *string                `json:"holdover_policy"                  validate:"omitempty,oneof=COMPLETE MONTH_TO_MONTH"                                                     example:"MONTH_TO_MONTH"                                        description:"What happens to an active lease whose move-out passes without a renewal. Options: COMPLETE | MONTH_TO_MONTH."`
+	HoldoverRentPremiumPercent    *int64                 `json:"holdover_rent_premium_percent"    validate:"omitempty,min=0,max=100"                                                                     example:"10"                                                    description:"Percentage added to the rent when a lease first rolls over month to month. 0 keeps the current rent."`
+	NoticePeriodDays              *int64                 `json:"notice_period_days"               validate:"omitempty,min=0,max=365"                                                                     example:"30"                                                    description:"Days of notice a tenant must give to vacate, unless their lease sets its own."`
+	BookingCancellationPolicy     *string                `json:"booking_cancellation_policy"      validate:"omitempty,oneof=FLEXIBLE MODERATE STRICT"                                                    example:"MODERATE"                                              description:"Refund policy new bookings are taken under; existing bookings keep theirs. Options: FLEXIBLE | MODERATE | STRICT."`
+	BookingRequiresUpfrontPayment *bool                  `json:"booking_requires_upfront_payment" validate:"omitempty"                                                                                   example:"true"                                                  description:"Hold new bookings only until the guest pays, cancelling them if unpaid after booking_hold_hours."`
+	BookingHoldHours              *int64                 `json:"booking_hold_hours"               validate:"omitempty,min=0,max=168"                                                                     example:"24"                                                    description:"Hours a guest has to pay for a booking held for upfront payment. 0 holds it until a manager acts."`
+	BookingRequiresGuestList      *bool                  `json:"booking_requires_guest_list"      validate:"omitempty"                                                                                   example:"true"                                                  description:"Check no booking in until every guest staying has been registered by name."`
 }
 
 // UpdateProperty godoc
@@ -333,23 +340,30 @@ func (h *PropertyHandler) UpdateProperty(w http.ResponseWriter, r *http.Request)
 	propertyID := chi.URLParam(r, "property_id")
 
 	input := services.UpdatePropertyInput{
-		PropertyID:  propertyID,
-		ClientID:    currentClientUser.ClientID,
-		Name:        body.Name,
-		Currency:    body.Currency,
-		Description: body.Description,
-		Images:      body.Images,
-		Tags:        body.Tags,
-		Modes:       body.Modes,
-		Latitude:    body.Latitude,
-		Longitude:   body.Longitude,
-		Address:     body.Address,
-		Country:     body.Country,
-		Region:      body.Region,
-		City:        body.City,
-		GPSAddress:  body.GPSAddress,
-		Type:        body.Type,
-		Status:      body.Status,
+		PropertyID:                    propertyID,
+		ClientID:                      currentClientUser.ClientID,
+		Name:                          body.Name,
+		Currency:                      body.Currency,
+		Description:                   body.Description,
+		Images:                        body.Images,
+		Tags:                          body.Tags,
+		Modes:                         body.Modes,
+		Latitude:                      body.Latitude,
+		Longitude:                     body.Longitude,
+		Address:                       body.Address,
+		Country:                       body.Country,
+		Region:                        body.Region,
+		City:                          body.City,
+		GPSAddress:                    body.GPSAddress,
+		Type:                          body.Type,
+		Status:                        body.Status,
+		HoldoverPolicy:                body.HoldoverPolicy,
+		HoldoverRentPremiumPercent:    body.HoldoverRentPremiumPercent,
+		NoticePeriodDays:              body.NoticePeriodDays,
+		BookingCancellationPolicy:     body.BookingCancellationPolicy,
+		BookingRequiresUpfrontPayment: body.BookingRequiresUpfrontPayment,
+		BookingHoldHours:              body.BookingHoldHours,
+		BookingRequiresGuestList:      body.BookingRequiresGuestList,
 	}
 
 	property, updateErr := h.service.UpdateProperty(r.Context(), input)
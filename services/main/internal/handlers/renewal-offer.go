@@ -0,0 +1,536 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type RenewalOfferHandler struct {
+	appCtx               pkg.AppContext
+	service              services.RenewalOfferService
+	tenantAccountService services.TenantAccountService
+	leaseTenantService   services.LeaseTenantService
+}
+
+func NewRenewalOfferHandler(appCtx pkg.AppContext, services services.Services) RenewalOfferHandler {
+	return RenewalOfferHandler{
+		appCtx:               appCtx,
+		service:              services.RenewalOfferService,
+		tenantAccountService: services.TenantAccountService,
+		leaseTenantService:   services.LeaseTenantService,
+	}
+}
+
+type RenewalOfferFeeRequest struct {
+	Category string `json:"category" validate:"required,oneof=SECURITY_DEPOSIT AGENCY_FEE VAT UTILITY DAMAGE_CHARGE EARLY_TERMINATION_FEE OTHER" example:"SECURITY_DEPOSIT"`
+	Name     string `json:"name"     validate:"required"                                                                                         example:"Deposit top-up"`
+	Amount   int64  `json:"amount"   validate:"required,gt=0"                                                                                    example:"15000"`
+}
+
+type RenewalOfferOptionRequest struct {
+	RentFee               int64                    `json:"rent_fee"                validate:"gte=0"                            example:"450000" description:"Rent per payment period for the new term"`
+	StayDuration          int64                    `json:"stay_duration"           validate:"required,gt=0"                    example:"12"     description:"Length of the new term"`
+	StayDurationFrequency string                   `json:"stay_duration_frequency" validate:"required,oneof=Hours Days Months" example:"Months" description:"Unit of stay duration"`
+	Fees                  []RenewalOfferFeeRequest `json:"fees,omitempty"          validate:"omitempty,dive"                                    description:"One-off charges due at the start of the new term"`
+}
+
+func renewalOfferOptionsFromRequest(options []RenewalOfferOptionRequest) []models.RenewalOfferOption {
+	result := make([]models.RenewalOfferOption, 0, len(options))
+	for _, option := range options {
+		fees := make([]models.RenewalOfferFee, 0, len(option.Fees))
+		for _, fee := range option.Fees {
+			fees = append(fees, models.RenewalOfferFee{Category: fee.Category, Name: fee.Name, Amount: fee.Amount})
+		}
+
+		result = append(result, models.RenewalOfferOption{
+			RentFee:               option.RentFee,
+			StayDuration:          option.StayDuration,
+			StayDurationFrequency: option.StayDurationFrequency,
+			Fees:                  fees,
+		})
+	}
+	return result
+}
+
+type CreateRenewalOfferRequest struct {
+	Message   *string                     `json:"message,omitempty" validate:"omitempty"                 example:"We'd love to have you stay another year." description:"Note shown to the tenant with the offer"`
+	Options   []RenewalOfferOptionRequest `json:"options"           validate:"required,min=1,max=5,dive"                                                    description:"Terms the tenant may choose between"`
+	ExpiresAt time.Time                   `json:"expires_at"        validate:"required"                  example:"2026-12-01T00:00:00Z"                     description:"When the offer lapses; no later than the lease's move-out date"`
+}
+
+// CreateRenewalOffer godoc
+//
+//	@Summary		Send a renewal offer (Admin)
+//	@Description	Offer the tenant of an active lease one or more sets of renewal terms. The tenant accepts, declines or counters from the tenant app; accepting renews the lease on the chosen terms from its move-out date. An offer lapses at expires_at, which may not be after move-out. One offer may be open per lease.
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string												true	"Client ID"
+//	@Param			property_id	path		string												true	"Property ID"
+//	@Param			lease_id	path		string												true	"Lease ID"
+//	@Param			body		body		CreateRenewalOfferRequest							true	"Create renewal offer request body"
+//	@Success		201			{object}	object{data=transformations.OutputAdminRenewalOffer}	"Offer sent"
+//	@Failure		400			{object}	lib.HTTPError										"Lease not Active or already renewed, expiry out of range, invalid fee, or an offer is already open"
+//	@Failure		401			{object}	string												"Invalid or absent authentication token"
+//	@Failure		404			{object}	lib.HTTPError										"Lease not found"
+//	@Failure		422			{object}	lib.HTTPError										"Validation error"
+//	@Failure		500			{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/renewal-offers [post]
+func (h *RenewalOfferHandler) CreateRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	clientUser, clientUserOk := lib.ClientUserFromContext(r.Context())
+	if !clientUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CreateRenewalOfferRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	offer, err := h.service.Create(r.Context(), services.CreateRenewalOfferInput{
+		LeaseID:     chi.URLParam(r, "lease_id"),
+		Message:     body.Message,
+		Options:     renewalOfferOptionsFromRequest(body.Options),
+		ExpiresAt:   body.ExpiresAt,
+		CreatedById: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminRenewalOfferToRest(offer),
+	})
+}
+
+type ListRenewalOffersQuery struct {
+	lib.FilterQueryInput
+	Status *string `json:"status,omitempty" validate:"omitempty,oneof=RenewalOffer.Status.Sent RenewalOffer.Status.Accepted RenewalOffer.Status.Declined RenewalOffer.Status.Countered RenewalOffer.Status.Expired RenewalOffer.Status.Withdrawn" example:"RenewalOffer.Status.Sent" description:"Offer status"`
+}
+
+// ListRenewalOffers godoc
+//
+//	@Summary		List renewal offers (Admin)
+//	@Description	List the renewal offers made on a lease, including the tenant's answer to each
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string					true	"Client ID"
+//	@Param			property_id	path		string					true	"Property ID"
+//	@Param			lease_id	path		string					true	"Lease ID"
+//	@Param			q			query		ListRenewalOffersQuery	true	"Query parameters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputAdminRenewalOffer,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError	"Error occurred"
+//	@Failure		401			{object}	string			"Invalid or absent authentication token"
+//	@Failure		500			{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/renewal-offers [get]
+func (h *RenewalOfferHandler) ListRenewalOffers(w http.ResponseWriter, r *http.Request) {
+	h.listRenewalOffers(w, r, transformations.DBAdminRenewalOfferToRest)
+}
+
+type GetRenewalOfferQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetRenewalOffer godoc
+//
+//	@Summary		Get renewal offer (Admin)
+//	@Description	Get a single renewal offer by ID
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id			path		string												true	"Client ID"
+//	@Param			property_id			path		string												true	"Property ID"
+//	@Param			lease_id			path		string												true	"Lease ID"
+//	@Param			renewal_offer_id	path		string												true	"Renewal offer ID"
+//	@Param			q					query		GetRenewalOfferQuery								true	"Renewal offer query parameters"
+//	@Success		200					{object}	object{data=transformations.OutputAdminRenewalOffer}	"Renewal offer"
+//	@Failure		401					{object}	string												"Invalid or absent authentication token"
+//	@Failure		404					{object}	lib.HTTPError										"Not found"
+//	@Failure		500					{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/renewal-offers/{renewal_offer_id} [get]
+func (h *RenewalOfferHandler) GetRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	offer, err := h.service.GetOne(r.Context(), repository.GetRenewalOfferQuery{
+		ID:       chi.URLParam(r, "renewal_offer_id"),
+		LeaseID:  chi.URLParam(r, "lease_id"),
+		Populate: GetPopulateFields(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminRenewalOfferToRest(offer),
+	})
+}
+
+// WithdrawRenewalOffer godoc
+//
+//	@Summary		Withdraw renewal offer (Admin)
+//	@Description	Withdraw an open renewal offer so the tenant can no longer accept it
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id			path		string												true	"Client ID"
+//	@Param			property_id			path		string												true	"Property ID"
+//	@Param			lease_id			path		string												true	"Lease ID"
+//	@Param			renewal_offer_id	path		string												true	"Renewal offer ID"
+//	@Success		200					{object}	object{data=transformations.OutputAdminRenewalOffer}	"Withdrawn"
+//	@Failure		400					{object}	lib.HTTPError										"Offer not open"
+//	@Failure		401					{object}	string												"Invalid or absent authentication token"
+//	@Failure		404					{object}	lib.HTTPError										"Not found"
+//	@Failure		500					{object}	string												"An unexpected error occurred"
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/leases/{lease_id}/renewal-offers/{renewal_offer_id}/withdraw [patch]
+func (h *RenewalOfferHandler) WithdrawRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	clientUser, clientUserOk := lib.ClientUserFromContext(r.Context())
+	if !clientUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	offer, err := h.service.Withdraw(r.Context(), services.WithdrawRenewalOfferInput{
+		ID:           chi.URLParam(r, "renewal_offer_id"),
+		LeaseID:      chi.URLParam(r, "lease_id"),
+		ClientUserID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminRenewalOfferToRest(offer),
+	})
+}
+
+// TenantListRenewalOffers godoc
+//
+//	@Summary		Tenant: list renewal offers
+//	@Description	List renewal offers made on a lease the authenticated tenant is on
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id	path		string					true	"Lease ID"
+//	@Param			q			query		ListRenewalOffersQuery	true	"Query parameters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputRenewalOffer,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		401			{object}	string			"Invalid or absent authentication token"
+//	@Failure		403			{object}	lib.HTTPError	"Lease does not belong to tenant"
+//	@Failure		500			{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/renewal-offers [get]
+func (h *RenewalOfferHandler) TenantListRenewalOffers(w http.ResponseWriter, r *http.Request) {
+	if err := h.assertTenantOnLease(r); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	h.listRenewalOffers(w, r, transformations.DBRenewalOfferToRest)
+}
+
+// TenantGetRenewalOffer godoc
+//
+//	@Summary		Tenant: get renewal offer
+//	@Description	Get a single renewal offer on a lease the authenticated tenant is on
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id			path		string											true	"Lease ID"
+//	@Param			renewal_offer_id	path		string											true	"Renewal offer ID"
+//	@Success		200					{object}	object{data=transformations.OutputRenewalOffer}	"Renewal offer"
+//	@Failure		401					{object}	string											"Invalid or absent authentication token"
+//	@Failure		403					{object}	lib.HTTPError									"Lease does not belong to tenant"
+//	@Failure		404					{object}	lib.HTTPError									"Not found"
+//	@Failure		500					{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id} [get]
+func (h *RenewalOfferHandler) TenantGetRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	if err := h.assertTenantOnLease(r); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	offer, err := h.service.GetOne(r.Context(), repository.GetRenewalOfferQuery{
+		ID:      chi.URLParam(r, "renewal_offer_id"),
+		LeaseID: chi.URLParam(r, "lease_id"),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBRenewalOfferToRest(offer),
+	})
+}
+
+type AcceptRenewalOfferRequest struct {
+	OptionIndex *int `json:"option_index" validate:"required,gte=0" example:"0" description:"Position of the chosen option in the offer's options"`
+}
+
+// TenantAcceptRenewalOffer godoc
+//
+//	@Summary		Tenant: accept renewal offer
+//	@Description	Accept one of an open offer's options. The lease is renewed on those terms from its move-out date; the response carries the new lease's ID as renewal_lease_id. Only a primary tenant or co-tenant may answer an offer.
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id			path		string											true	"Lease ID"
+//	@Param			renewal_offer_id	path		string											true	"Renewal offer ID"
+//	@Param			body				body		AcceptRenewalOfferRequest						true	"Chosen option"
+//	@Success		200					{object}	object{data=transformations.OutputRenewalOffer}	"Accepted"
+//	@Failure		400					{object}	lib.HTTPError									"Offer not open, expired, option not found, or the lease can no longer be renewed"
+//	@Failure		401					{object}	string											"Invalid or absent authentication token"
+//	@Failure		403					{object}	lib.HTTPError									"Not a signatory on the lease"
+//	@Failure		404					{object}	lib.HTTPError									"Not found"
+//	@Failure		422					{object}	lib.HTTPError									"Validation error"
+//	@Failure		500					{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id}/accept [post]
+func (h *RenewalOfferHandler) TenantAcceptRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	var body AcceptRenewalOfferRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	respond, err := h.respondInput(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	offer, err := h.service.Accept(r.Context(), services.AcceptRenewalOfferInput{
+		RespondToRenewalOfferInput: respond,
+		OptionIndex:                *body.OptionIndex,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBRenewalOfferToRest(offer),
+	})
+}
+
+type DeclineRenewalOfferRequest struct {
+	Reason *string `json:"reason,omitempty" validate:"omitempty" example:"Moving to another city"`
+}
+
+// TenantDeclineRenewalOffer godoc
+//
+//	@Summary		Tenant: decline renewal offer
+//	@Description	Decline an open renewal offer. Only a primary tenant or co-tenant may answer an offer.
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id			path		string											true	"Lease ID"
+//	@Param			renewal_offer_id	path		string											true	"Renewal offer ID"
+//	@Param			body				body		DeclineRenewalOfferRequest						true	"Decline reason"
+//	@Success		200					{object}	object{data=transformations.OutputRenewalOffer}	"Declined"
+//	@Failure		400					{object}	lib.HTTPError									"Offer not open or expired"
+//	@Failure		401					{object}	string											"Invalid or absent authentication token"
+//	@Failure		403					{object}	lib.HTTPError									"Not a signatory on the lease"
+//	@Failure		404					{object}	lib.HTTPError									"Not found"
+//	@Failure		422					{object}	lib.HTTPError									"Validation error"
+//	@Failure		500					{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id}/decline [post]
+func (h *RenewalOfferHandler) TenantDeclineRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	var body DeclineRenewalOfferRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	respond, err := h.respondInput(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	offer, err := h.service.Decline(r.Context(), services.DeclineRenewalOfferInput{
+		RespondToRenewalOfferInput: respond,
+		Reason:                     body.Reason,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBRenewalOfferToRest(offer),
+	})
+}
+
+type CounterRenewalOfferRequest struct {
+	RentFee               *int64  `json:"rent_fee,omitempty"                validate:"omitempty,gte=0"                                              example:"420000"                          description:"Rent the tenant proposes instead"`
+	StayDuration          *int64  `json:"stay_duration,omitempty"           validate:"omitempty,gt=0,required_with=StayDurationFrequency"           example:"12"                              description:"Term length the tenant proposes instead"`
+	StayDurationFrequency *string `json:"stay_duration_frequency,omitempty" validate:"omitempty,oneof=Hours Days Months,required_with=StayDuration" example:"Months"                          description:"Unit of the proposed stay duration"`
+	Message               *string `json:"message,omitempty"                 validate:"omitempty"                                                    example:"Could we keep the current rent?" description:"Note to the property manager"`
+}
+
+// TenantCounterRenewalOffer godoc
+//
+//	@Summary		Tenant: counter renewal offer
+//	@Description	Answer an open offer with different terms. This closes the offer; the property manager replies by sending a new one. Only a primary tenant or co-tenant may answer an offer.
+//	@Tags			RenewalOffer
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			lease_id			path		string											true	"Lease ID"
+//	@Param			renewal_offer_id	path		string											true	"Renewal offer ID"
+//	@Param			body				body		CounterRenewalOfferRequest						true	"Proposed terms"
+//	@Success		200					{object}	object{data=transformations.OutputRenewalOffer}	"Countered"
+//	@Failure		400					{object}	lib.HTTPError									"Offer not open or expired, or the counter proposes nothing"
+//	@Failure		401					{object}	string											"Invalid or absent authentication token"
+//	@Failure		403					{object}	lib.HTTPError									"Not a signatory on the lease"
+//	@Failure		404					{object}	lib.HTTPError									"Not found"
+//	@Failure		422					{object}	lib.HTTPError									"Validation error"
+//	@Failure		500					{object}	string											"An unexpected error occurred"
+//	@Router			/api/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id}/counter [post]
+func (h *RenewalOfferHandler) TenantCounterRenewalOffer(w http.ResponseWriter, r *http.Request) {
+	var body CounterRenewalOfferRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	respond, err := h.respondInput(r)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	offer, err := h.service.Counter(r.Context(), services.CounterRenewalOfferInput{
+		RespondToRenewalOfferInput: respond,
+		RentFee:                    body.RentFee,
+		StayDuration:               body.StayDuration,
+		StayDurationFrequency:      body.StayDurationFrequency,
+		Message:                    body.Message,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBRenewalOfferToRest(offer),
+	})
+}
+
+// listRenewalOffers serves both audiences; only the shape of each row differs.
+func (h *RenewalOfferHandler) listRenewalOffers(
+	w http.ResponseWriter,
+	r *http.Request,
+	toRest func(*models.RenewalOffer) any,
+) {
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filterQuery, w) {
+		return
+	}
+
+	leaseID := chi.URLParam(r, "lease_id")
+
+	filter := repository.ListRenewalOffersFilter{
+		FilterQuery: *filterQuery,
+		LeaseID:     &leaseID,
+		Status:      lib.NullOrString(r.URL.Query().Get("status")),
+	}
+
+	offers, err := h.service.List(r.Context(), filter)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	count, countErr := h.service.Count(r.Context(), filter)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, len(offers))
+	for i := range offers {
+		rows[i] = toRest(&offers[i])
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+// assertTenantOnLease lets any tenant on the lease, occupants included, see
+// its offers. Answering one is narrower and is checked by the service.
+func (h *RenewalOfferHandler) assertTenantOnLease(r *http.Request) error {
+	tenantAccount, ok := lib.TenantAccountFromContext(r.Context())
+	if !ok {
+		return pkg.ForbiddenError("Unauthorized", nil)
+	}
+
+	onLease, err := h.leaseTenantService.HasTenantAccount(r.Context(), chi.URLParam(r, "lease_id"), tenantAccount.ID)
+	if err != nil {
+		return err
+	}
+	if !onLease {
+		return pkg.ForbiddenError("LeaseDoesNotBelongToTenant", nil)
+	}
+
+	return nil
+}
+
+// respondInput resolves the authenticated account to the tenant answering.
+func (h *RenewalOfferHandler) respondInput(r *http.Request) (services.RespondToRenewalOfferInput, error) {
+	tenantAccount, ok := lib.TenantAccountFromContext(r.Context())
+	if !ok {
+		return services.RespondToRenewalOfferInput{}, pkg.ForbiddenError("Unauthorized", nil)
+	}
+
+	account, err := h.tenantAccountService.GetMe(r.Context(), tenantAccount.ID)
+	if err != nil {
+		return services.RespondToRenewalOfferInput{}, err
+	}
+
+	return services.RespondToRenewalOfferInput{
+		ID:       chi.URLParam(r, "renewal_offer_id"),
+		LeaseID:  chi.URLParam(r, "lease_id"),
+		TenantID: account.TenantId,
+	}, nil
+}
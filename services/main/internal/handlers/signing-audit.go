@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/go-chi/chi/v5"
+)
+
+type VerifyDocumentSignaturesRequest struct {
+	Content *string `json:"content" validate:"omitempty,json"`
+	PdfUrl  *string `json:"pdf_url" validate:"omitempty,url"  example:"https://example.com/lease.pdf"`
+}
+
+// VerifyDocumentSignatures godoc
+//
+//	@Summary		Verify a document's signatures (Admin)
+//	@Description	Check each signature on a document against its recorded hashes and the hash chain linking them (Admin). Pass content to check a version of the document against what each signer signed; the document's current content is checked otherwise. Pass pdf_url to check a PDF against the signed agreement and audit certificates produced for the document; it must be a file already uploaded to Rentloop's storage.
+//	@Tags			Signing
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string																	true	"Property ID"
+//	@Param			document_id	path		string																	true	"Document ID"	format(uuid4)
+//	@Param			body		body		VerifyDocumentSignaturesRequest											true	"What to verify"
+//	@Success		200			{object}	object{data=transformations.OutputDocumentSignatureVerification}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/signing/documents/{document_id}/verify [post]
+func (h *SigningHandler) VerifyDocumentSignatures(w http.ResponseWriter, r *http.Request) {
+	var body VerifyDocumentSignaturesRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	input := services.VerifyDocumentSignaturesInput{
+		DocumentID: chi.URLParam(r, "document_id"),
+		PdfUrl:     body.PdfUrl,
+	}
+	if body.Content != nil {
+		input.Content = []byte(*body.Content)
+	}
+
+	verification, err := h.service.VerifyDocumentSignatures(r.Context(), input)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DocumentSignatureVerificationToRest(verification),
+	})
+}
@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/go-chi/chi/v5"
+)
+
+type SigningEnvelopeSignerRequest struct {
+	Role         string  `json:"role"          validate:"required,oneof=TENANT GUARANTOR PM_WITNESS TENANT_WITNESS"`
+	SigningOrder int64   `json:"signing_order" validate:"required,min=1"                                            example:"1"`
+	TenantID     *string `json:"tenant_id"     validate:"omitempty,uuid4"`
+	GuarantorID  *string `json:"guarantor_id"  validate:"omitempty,uuid4"`
+	SignerName   *string `json:"signer_name"   validate:"omitempty"`
+	SignerEmail  *string `json:"signer_email"  validate:"omitempty,email"`
+	SignerPhone  *string `json:"signer_phone"  validate:"omitempty"`
+}
+
+type CreateSigningEnvelopeRequest struct {
+	DocumentID           string                         `json:"document_id"            validate:"required,uuid4"`
+	TenantApplicationID  *string                        `json:"tenant_application_id"  validate:"omitempty,uuid4"`
+	LeaseID              *string                        `json:"lease_id"               validate:"omitempty,uuid4"`
+	LeaseTerminationID   *string                        `json:"lease_termination_id"   validate:"omitempty,uuid4"`
+	LeaseAmendmentID     *string                        `json:"lease_amendment_id"     validate:"omitempty,uuid4"`
+	Signers              []SigningEnvelopeSignerRequest `json:"signers"                validate:"required,min=1,dive"`
+	PMSigningOrder       *int64                         `json:"pm_signing_order"       validate:"omitempty,min=1"                   example:"2"`
+	ExpiryPolicy         *string                        `json:"expiry_policy"          validate:"omitempty,oneof=REGENERATE CANCEL" example:"REGENERATE"`
+	TokenValidityDays    *int64                         `json:"token_validity_days"    validate:"omitempty,min=1,max=30"            example:"7"`
+	ReminderIntervalDays *int64                         `json:"reminder_interval_days" validate:"omitempty,min=0,max=30"            example:"2"`
+}
+
+// CreateSigningEnvelope godoc
+//
+//	@Summary		Send a document for ordered signing (Admin)
+//	@Description	Issue a signing token for each signer and have them sign in order (Admin). Signers sharing a signing_order sign in parallel; the next order is invited only once everyone before it has signed. Set pm_signing_order to place the PM's own signature in the order. Signers who have not signed are reminded every reminder_interval_days (0 turns reminders off). A link that lapses unsigned is renewed and sent again under the REGENERATE expiry policy, or cancels the envelope under CANCEL.
+//	@Tags			Signing
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string														true	"Property ID"
+//	@Param			body		body		CreateSigningEnvelopeRequest								true	"Envelope details"
+//	@Success		201			{object}	object{data=transformations.OutputAdminSigningEnvelope}	"Envelope created"
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/signing-envelopes [post]
+func (h *SigningHandler) CreateSigningEnvelope(w http.ResponseWriter, r *http.Request) {
+	currentUser, currentUserOk := lib.ClientUserFromContext(r.Context())
+	if !currentUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CreateSigningEnvelopeRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	signers := make([]services.SigningEnvelopeSignerInput, 0, len(body.Signers))
+	for _, signer := range body.Signers {
+		signers = append(signers, services.SigningEnvelopeSignerInput{
+			Role:         signer.Role,
+			SigningOrder: signer.SigningOrder,
+			TenantID:     signer.TenantID,
+			GuarantorID:  signer.GuarantorID,
+			SignerName:   signer.SignerName,
+			SignerEmail:  signer.SignerEmail,
+			SignerPhone:  signer.SignerPhone,
+		})
+	}
+
+	envelope, err := h.service.CreateSigningEnvelope(r.Context(), services.CreateSigningEnvelopeInput{
+		DocumentID:           body.DocumentID,
+		TenantApplicationID:  body.TenantApplicationID,
+		LeaseID:              body.LeaseID,
+		LeaseTerminationID:   body.LeaseTerminationID,
+		LeaseAmendmentID:     body.LeaseAmendmentID,
+		Signers:              signers,
+		PMSigningOrder:       body.PMSigningOrder,
+		ExpiryPolicy:         body.ExpiryPolicy,
+		TokenValidityDays:    body.TokenValidityDays,
+		ReminderIntervalDays: body.ReminderIntervalDays,
+		CreatedByID:          currentUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminSigningEnvelopeToRest(envelope),
+	})
+}
+
+type ListSigningEnvelopesFilterRequest struct {
+	lib.FilterQueryInput
+	DocumentID          *string `json:"document_id"           validate:"omitempty,uuid4"`
+	TenantApplicationID *string `json:"tenant_application_id" validate:"omitempty,uuid4"`
+	LeaseID             *string `json:"lease_id"              validate:"omitempty,uuid4"`
+	LeaseTerminationID  *string `json:"lease_termination_id"  validate:"omitempty,uuid4"`
+	LeaseAmendmentID    *string `json:"lease_amendment_id"    validate:"omitempty,uuid4"`
+	Status              *string `json:"status"                validate:"omitempty,oneof=SigningEnvelope.Status.InProgress SigningEnvelope.Status.Completed SigningEnvelope.Status.Cancelled"`
+}
+
+// ListSigningEnvelopes godoc
+//
+//	@Summary		List signing envelopes (Admin)
+//	@Description	List signing envelopes with their tokens, each token's status and the step currently being waited on (Admin)
+//	@Tags			Signing
+//	@Produce		json
+//	@Security		BearerAuth
+//	@Param			property_id	path		string								true	"Property ID"
+//	@Param			q			query		ListSigningEnvelopesFilterRequest	true	"Filter query"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputAdminSigningEnvelope,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/signing-envelopes [get]
+func (h *SigningHandler) ListSigningEnvelopes(w http.ResponseWriter, r *http.Request) {
+	_, currentUserOk := lib.ClientUserFromContext(r.Context())
+	if !currentUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	q := r.URL.Query()
+	filters := ListSigningEnvelopesFilterRequest{
+		DocumentID:          lib.NullOrString(q.Get("document_id")),
+		TenantApplicationID: lib.NullOrString(q.Get("tenant_application_id")),
+		LeaseID:             lib.NullOrString(q.Get("lease_id")),
+		LeaseTerminationID:  lib.NullOrString(q.Get("lease_termination_id")),
+		LeaseAmendmentID:    lib.NullOrString(q.Get("lease_amendment_id")),
+		Status:              lib.NullOrString(q.Get("status")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(q)
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filterQuery, w) {
+		return
+	}
+
+	input := repository.ListSigningEnvelopesFilter{
+		FilterQuery:         *filterQuery,
+		DocumentID:          filters.DocumentID,
+		TenantApplicationID: filters.TenantApplicationID,
+		LeaseID:             filters.LeaseID,
+		LeaseTerminationID:  filters.LeaseTerminationID,
+		LeaseAmendmentID:    filters.LeaseAmendmentID,
+		Status:              filters.Status,
+	}
+
+	envelopes, envelopesErr := h.service.ListSigningEnvelopes(r.Context(), input)
+	if envelopesErr != nil {
+		HandleErrorResponse(w, envelopesErr)
+		return
+	}
+
+	count, countErr := h.service.CountSigningEnvelopes(r.Context(), input)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, 0, len(*envelopes))
+	for i := range *envelopes {
+		rows = append(rows, transformations.DBAdminSigningEnvelopeToRest(&(*envelopes)[i]))
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+type GetSigningEnvelopeQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetSigningEnvelope godoc
+//
+//	@Summary		Get a signing envelope (Admin)
+//	@Description	Get a signing envelope with its tokens, each token's status and the step currently being waited on (Admin)
+//	@Tags			Signing
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id			path		string														true	"Property ID"
+//	@Param			signing_envelope_id	path		string														true	"Signing envelope ID"	format(uuid4)
+//	@Param			q					query		GetSigningEnvelopeQuery										true	"Query parameters"
+//	@Success		200					{object}	object{data=transformations.OutputAdminSigningEnvelope}
+//	@Failure		401					{object}	string
+//	@Failure		404					{object}	lib.HTTPError
+//	@Failure		500					{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/signing-envelopes/{signing_envelope_id} [get]
+func (h *SigningHandler) GetSigningEnvelope(w http.ResponseWriter, r *http.Request) {
+	envelope, err := h.service.GetSigningEnvelope(
+		r.Context(),
+		chi.URLParam(r, "signing_envelope_id"),
+		GetPopulateFields(r),
+	)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminSigningEnvelopeToRest(envelope),
+	})
+}
+
+type CancelSigningEnvelopeRequest struct {
+	Reason string `json:"reason" validate:"required" example:"Tenant withdrew"`
+}
+
+// CancelSigningEnvelope godoc
+//
+//	@Summary		Cancel a signing envelope (Admin)
+//	@Description	Cancel an in-progress signing envelope (Admin). Signatures already given stand; the links of everyone who has not signed stop working.
+//	@Tags			Signing
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id			path		string														true	"Property ID"
+//	@Param			signing_envelope_id	path		string														true	"Signing envelope ID"	format(uuid4)
+//	@Param			body				body		CancelSigningEnvelopeRequest								true	"Cancellation details"
+//	@Success		200					{object}	object{data=transformations.OutputAdminSigningEnvelope}
+//	@Failure		400					{object}	lib.HTTPError
+//	@Failure		401					{object}	string
+//	@Failure		404					{object}	lib.HTTPError
+//	@Failure		500					{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/signing-envelopes/{signing_envelope_id}/cancel [post]
+func (h *SigningHandler) CancelSigningEnvelope(w http.ResponseWriter, r *http.Request) {
+	currentUser, currentUserOk := lib.ClientUserFromContext(r.Context())
+	if !currentUserOk {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CancelSigningEnvelopeRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	envelope, err := h.service.CancelSigningEnvelope(r.Context(), services.CancelSigningEnvelopeInput{
+		EnvelopeID:    chi.URLParam(r, "signing_envelope_id"),
+		Reason:        body.Reason,
+		CancelledByID: currentUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBAdminSigningEnvelopeToRest(envelope),
+	})
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
@@ -25,10 +24,13 @@ func NewSigningHandler(appCtx pkg.AppContext, services services.Services) Signin
 
 type GenerateTokenRequest struct {
 	DocumentID          string  `json:"document_id"           validate:"required,uuid4"`
-	Role                string  `json:"role"                  validate:"required,oneof=TENANT PM_WITNESS TENANT_WITNESS"`
+	Role                string  `json:"role"                  validate:"required,oneof=TENANT GUARANTOR PM_WITNESS TENANT_WITNESS"`
 	TenantApplicationID *string `json:"tenant_application_id" validate:"omitempty,uuid4"`
 	LeaseID             *string `json:"lease_id"              validate:"omitempty,uuid4"`
 	LeaseTerminationID  *string `json:"lease_termination_id"  validate:"omitempty,uuid4"`
+	LeaseAmendmentID    *string `json:"lease_amendment_id"    validate:"omitempty,uuid4"`
+	TenantID            *string `json:"tenant_id"             validate:"omitempty,uuid4"`
+	GuarantorID         *string `json:"guarantor_id"          validate:"omitempty,uuid4"`
 	SignerName          *string `json:"signer_name"           validate:"omitempty"`
 	SignerEmail         *string `json:"signer_email"          validate:"omitempty,email"`
 	SignerPhone         *string `json:"signer_phone"          validate:"omitempty"`
@@ -37,7 +39,7 @@ type GenerateTokenRequest struct {
 // GenerateToken godoc
 //
 //	@Summary		Generate a signing token (Admin)
-//	@Description	Generate a signing token for a document signer (Admin)
+//	@Description	Generate a signing token for a document signer (Admin). On a lease with co-tenants, pass tenant_id so each signatory signs with their own link — the agreement is signed only once all of them have. A guarantor signs with role GUARANTOR and their guarantor_id; the invitation goes to the guarantor's contact details unless signer details are given.
 //	@Tags			Signing
 //	@Accept			json
 //	@Security		BearerAuth
@@ -71,6 +73,9 @@ func (h *SigningHandler) GenerateToken(w http.ResponseWriter, r *http.Request) {
 		TenantApplicationID: body.TenantApplicationID,
 		LeaseID:             body.LeaseID,
 		LeaseTerminationID:  body.LeaseTerminationID,
+		LeaseAmendmentID:    body.LeaseAmendmentID,
+		TenantID:            body.TenantID,
+		GuarantorID:         body.GuarantorID,
 		Role:                body.Role,
 		SignerName:          body.SignerName,
 		SignerEmail:         body.SignerEmail,
@@ -120,6 +125,70 @@ func (h *SigningHandler) VerifyToken(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// SendSigningOtp godoc
+//
+//	@Summary		Send a signing code
+//	@Description	Send the signer a one-time code, to their phone when the link has one and their email otherwise. Where the org requires it (otp_required on the verified token), the document is shown and can be signed only once a code is confirmed.
+//	@Tags			Signing
+//	@Produce		json
+//	@Param			token	path		string								true	"Signing token"
+//	@Success		200		{object}	object{data=transformations.OutputSigningOtpSent}	"Code sent"
+//	@Failure		400		{object}	lib.HTTPError
+//	@Failure		404		{object}	lib.HTTPError
+//	@Failure		500		{object}	string
+//	@Router			/api/v1/signing/{token}/otp [post]
+func (h *SigningHandler) SendSigningOtp(w http.ResponseWriter, r *http.Request) {
+	sent, err := h.service.SendSigningOtp(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.SigningOtpSentToRest(sent),
+	})
+}
+
+type VerifySigningOtpRequest struct {
+	Code string `json:"code" validate:"required" example:"123456"`
+}
+
+// VerifySigningOtp godoc
+//
+//	@Summary		Confirm a signing code
+//	@Description	Confirm the code sent to the signer. The link then shows the document and accepts a signature for 30 minutes, and the signature records that the signer confirmed a code.
+//	@Tags			Signing
+//	@Accept			json
+//	@Produce		json
+//	@Param			token	path		string											true	"Signing token"
+//	@Param			body	body		VerifySigningOtpRequest							true	"Code"
+//	@Success		200		{object}	object{data=transformations.OutputSigningToken}	"Code confirmed"
+//	@Failure		400		{object}	lib.HTTPError
+//	@Failure		404		{object}	lib.HTTPError
+//	@Failure		500		{object}	string
+//	@Router			/api/v1/signing/{token}/otp/verify [post]
+func (h *SigningHandler) VerifySigningOtp(w http.ResponseWriter, r *http.Request) {
+	var body VerifySigningOtpRequest
+	if decodeErr := json.NewDecoder(r.Body).Decode(&body); decodeErr != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	token, err := h.service.VerifySigningOtp(r.Context(), chi.URLParam(r, "token"), body.Code)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{
+		"data": transformations.DBSigningTokenToRest(token),
+	})
+}
+
 type SignDocumentRequest struct {
 	SignatureUrl string  `json:"signature_url" validate:"required,url"`
 	SignerName   *string `json:"signer_name"   validate:"omitempty"`
@@ -152,21 +221,12 @@ func (h *SigningHandler) SignDocument(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Extract IP address from X-Forwarded-For or fallback to RemoteAddr
-	ipAddress := r.Header.Get("X-Forwarded-For")
-	if ipAddress != "" {
-		// X-Forwarded-For can contain multiple IPs; take the first one
-		ipAddress = strings.Split(ipAddress, ",")[0]
-		ipAddress = strings.TrimSpace(ipAddress)
-	} else {
-		ipAddress = r.RemoteAddr
-	}
-
 	sig, err := h.service.SignDocument(r.Context(), services.SignDocumentInput{
 		TokenStr:     tokenStr,
 		SignatureUrl: body.SignatureUrl,
 		SignerName:   body.SignerName,
-		IPAddress:    ipAddress,
+		IPAddress:    lib.SafeString(ClientIPFromRequest(r)),
+		UserAgent:    UserAgentFromRequest(r),
 	})
 	if err != nil {
 		HandleErrorResponse(w, err)
@@ -185,7 +245,8 @@ type ListSigningTokensFilterRequest struct {
 	TenantApplicationID *string `json:"tenant_application_id" validate:"omitempty,uuid4"`
 	LeaseID             *string `json:"lease_id"              validate:"omitempty,uuid4"`
 	LeaseTerminationID  *string `json:"lease_termination_id"  validate:"omitempty,uuid4"`
-	Role                *string `json:"role"                  validate:"omitempty,oneof=TENANT PM_WITNESS TENANT_WITNESS"`
+	LeaseAmendmentID    *string `json:"lease_amendment_id"    validate:"omitempty,uuid4"`
+	Role                *string `json:"role"                  validate:"omitempty,oneof=TENANT GUARANTOR PM_WITNESS TENANT_WITNESS"`
 	CreatedByID         *string `json:"created_by_id"         validate:"omitempty,uuid4"`
 }
 
@@ -215,6 +276,7 @@ func (h *SigningHandler) ListSigningTokens(w http.ResponseWriter, r *http.Reques
 	tenantApplicationID := q.Get("tenant_application_id")
 	leaseID := q.Get("lease_id")
 	leaseTerminationID := q.Get("lease_termination_id")
+	leaseAmendmentID := q.Get("lease_amendment_id")
 	role := q.Get("role")
 	createdByID := q.Get("created_by_id")
 
@@ -231,6 +293,9 @@ func (h *SigningHandler) ListSigningTokens(w http.ResponseWriter, r *http.Reques
 	if leaseTerminationID != "" {
 		filters.LeaseTerminationID = &leaseTerminationID
 	}
+	if leaseAmendmentID != "" {
+		filters.LeaseAmendmentID = &leaseAmendmentID
+	}
 	if role != "" {
 		filters.Role = &role
 	}
@@ -257,6 +322,7 @@ func (h *SigningHandler) ListSigningTokens(w http.ResponseWriter, r *http.Reques
 		TenantApplicationID: filters.TenantApplicationID,
 		LeaseID:             filters.LeaseID,
 		LeaseTerminationID:  filters.LeaseTerminationID,
+		LeaseAmendmentID:    filters.LeaseAmendmentID,
 		Role:                filters.Role,
 		CreatedByID:         filters.CreatedByID,
 	}
@@ -379,6 +445,7 @@ type SignDocumentPMRequest struct {
 	TenantApplicationID *string `json:"tenant_application_id" validate:"omitempty,uuid4"`
 	LeaseID             *string `json:"lease_id"              validate:"omitempty,uuid4"`
 	LeaseTerminationID  *string `json:"lease_termination_id"  validate:"omitempty,uuid4"`
+	LeaseAmendmentID    *string `json:"lease_amendment_id"    validate:"omitempty,uuid4"`
 }
 
 // SignDocumentPM godoc
@@ -418,7 +485,10 @@ func (h *SigningHandler) SignDocumentPM(w http.ResponseWriter, r *http.Request)
 		TenantApplicationID: body.TenantApplicationID,
 		LeaseID:             body.LeaseID,
 		LeaseTerminationID:  body.LeaseTerminationID,
+		LeaseAmendmentID:    body.LeaseAmendmentID,
 		SignedByID:          currentUser.ID,
+		IPAddress:           lib.SafeString(ClientIPFromRequest(r)),
+		UserAgent:           UserAgentFromRequest(r),
 	})
 	if err != nil {
 		HandleErrorResponse(w, err)
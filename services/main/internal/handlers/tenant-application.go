@@ -160,6 +160,7 @@ type CreateTenantApplicationRequest struct {
 	OccupationAddress              *string    `json:"occupation_address"                validate:"omitempty"                                                      example:"456 Tech Ave, Accra"                     description:"Occupation address"`
 	ProfilePhotoUrl                *string    `json:"profile_photo_url,omitempty"       validate:"omitempty,url"                                                  example:"https://example.com/photo.jpg"           description:"Profile photo URL"`
 	CreatedById                    string     `json:"created_by_id"                     validate:"required,uuid"                                                  example:"72432ce6-5620-4ecf-a862-4bf2140556a1"    description:"ID of the user who created the lease application"`
+	WaitlistToken                  *string    `json:"waitlist_token"                    validate:"omitempty"                                                      example:"pJ3x0cQm8yKf2N7aQe5wZt1VbR4sLh9D"        description:"Waitlist invite the application is started through"`
 }
 
 // CreateTenantApplication godoc
@@ -215,6 +216,7 @@ func (h *TenantApplicationHandler) CreateTenantApplication(w http.ResponseWriter
 		OccupationAddress:              body.OccupationAddress,
 		ProfilePhotoUrl:                body.ProfilePhotoUrl,
 		CreatedById:                    body.CreatedById,
+		WaitlistToken:                  body.WaitlistToken,
 	}
 
 	tenantApplication, createTenantApplicationErr := h.service.CreateTenantApplication(r.Context(), input)
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type TurnoverTaskHandler struct {
+	appCtx  pkg.AppContext
+	service services.TurnoverTaskService
+}
+
+func NewTurnoverTaskHandler(appCtx pkg.AppContext, service services.TurnoverTaskService) TurnoverTaskHandler {
+	return TurnoverTaskHandler{appCtx: appCtx, service: service}
+}
+
+type ListTurnoverTasksFilterRequest struct {
+	lib.FilterQueryInput
+	Status                 *string `json:"status,omitempty"                     validate:"omitempty,oneof=PENDING DONE CANCELLED"    example:"PENDING"`
+	Type                   *string `json:"type,omitempty"                       validate:"omitempty,oneof=CLEANING LINEN INSPECTION" example:"CLEANING"`
+	UnitID                 *string `json:"unit_id,omitempty"                    validate:"omitempty,uuid4"                           example:"660e8400-e29b-41d4-a716-446655440000"`
+	AssignedToClientUserID *string `json:"assigned_to_client_user_id,omitempty" validate:"omitempty,uuid4"                           example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+}
+
+// ListTurnoverTasks godoc
+//
+//	@Summary		List turnover tasks for a property
+//	@Description	Cleaning, linen and inspection tasks generated when bookings and leases end, most pressing first.
+//	@Tags			TurnoverTasks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string							true	"Client ID"
+//	@Param			property_id	path		string							true	"Property ID"
+//	@Param			q			query		ListTurnoverTasksFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputTurnoverTask,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/turnover-tasks [get]
+func (h *TurnoverTaskHandler) ListTurnoverTasks(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	filters := ListTurnoverTasksFilterRequest{
+		Status:                 lib.NullOrString(r.URL.Query().Get("status")),
+		Type:                   lib.NullOrString(r.URL.Query().Get("type")),
+		UnitID:                 lib.NullOrString(r.URL.Query().Get("unit_id")),
+		AssignedToClientUserID: lib.NullOrString(r.URL.Query().Get("assigned_to_client_user_id")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	h.list(w, r, repository.ListTurnoverTasksFilter{
+		PropertyID:             &propertyID,
+		UnitID:                 filters.UnitID,
+		AssignedToClientUserID: filters.AssignedToClientUserID,
+		Status:                 filters.Status,
+		Type:                   filters.Type,
+	})
+}
+
+type ListMyTurnoverTasksFilterRequest struct {
+	lib.FilterQueryInput
+	Status *string `json:"status,omitempty" validate:"omitempty,oneof=PENDING DONE CANCELLED" example:"PENDING"`
+}
+
+// ListMyTurnoverTasks godoc
+//
+//	@Summary		List my turnover tasks
+//	@Description	The signed-in staff member's turnover tasks across their properties, most pressing first, with each task's unit and property. Only PENDING tasks unless another status is asked for.
+//	@Tags			TurnoverTasks
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string								true	"Client ID"
+//	@Param			q			query		ListMyTurnoverTasksFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputTurnoverTask,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/turnover-tasks/me [get]
+func (h *TurnoverTaskHandler) ListMyTurnoverTasks(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListMyTurnoverTasksFilterRequest{
+		Status: lib.NullOrString(r.URL.Query().Get("status")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	status := "PENDING"
+	if filters.Status != nil {
+		status = *filters.Status
+	}
+
+	h.list(w, r, repository.ListTurnoverTasksFilter{
+		AssignedToClientUserID: &clientUser.ID,
+		Status:                 &status,
+	})
+}
+
+func (h *TurnoverTaskHandler) list(w http.ResponseWriter, r *http.Request, filters repository.ListTurnoverTasksFilter) {
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	// the task list is read on a phone between units, so each row carries
+	// where the task is without another request
+	if filterQuery.Populate == nil {
+		filterQuery.Populate = &[]string{"Unit", "Property"}
+	}
+
+	tasks, listErr := h.service.ListTurnoverTasks(r.Context(), *filterQuery, filters)
+	if listErr != nil {
+		HandleErrorResponse(w, listErr)
+		return
+	}
+
+	count, countErr := h.service.CountTurnoverTasks(r.Context(), *filterQuery, filters)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, len(tasks))
+	for i := range tasks {
+		rows[i] = transformations.DBTurnoverTaskToRest(&tasks[i])
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+type GetTurnoverTaskQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetTurnoverTask godoc
+//
+//	@Summary	Get a turnover task
+//	@Tags		TurnoverTasks
+//	@Security	BearerAuth
+//	@Produce	json
+//	@Param		client_id			path		string					true	"Client ID"
+//	@Param		property_id			path		string					true	"Property ID"
+//	@Param		turnover_task_id	path		string					true	"Turnover task ID"
+//	@Param		q					query		GetTurnoverTaskQuery	true	"Query parameters"
+//	@Success	200					{object}	object{data=transformations.OutputTurnoverTask}
+//	@Failure	401					{object}	string
+//	@Failure	404					{object}	lib.HTTPError
+//	@Failure	500					{object}	string
+//	@Router		/api/v1/admin/clients/{client_id}/properties/{property_id}/turnover-tasks/{turnover_task_id} [get]
+func (h *TurnoverTaskHandler) GetTurnoverTask(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	task, err := h.service.GetTurnoverTask(r.Context(), repository.GetTurnoverTaskQuery{
+		ID:         chi.URLParam(r, "turnover_task_id"),
+		PropertyID: &propertyID,
+		Populate:   GetPopulateFields(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBTurnoverTaskToRest(task)})
+}
+
+type AssignTurnoverTaskRequest struct {
+	ClientUserID string `json:"client_user_id" validate:"required,uuid4" example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+}
+
+// AssignTurnoverTask godoc
+//
+//	@Summary		Assign a turnover task
+//	@Description	Hand a pending turnover task to another member of the property's team
+//	@Tags			TurnoverTasks
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id			path		string						true	"Client ID"
+//	@Param			property_id			path		string						true	"Property ID"
+//	@Param			turnover_task_id	path		string						true	"Turnover task ID"
+//	@Param			body				body		AssignTurnoverTaskRequest	true	"Assignee"
+//	@Success		200					{object}	object{data=transformations.OutputTurnoverTask}
+//	@Failure		400					{object}	lib.HTTPError
+//	@Failure		401					{object}	string
+//	@Failure		404					{object}	lib.HTTPError
+//	@Failure		500					{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/turnover-tasks/{turnover_task_id}/assign [patch]
+func (h *TurnoverTaskHandler) AssignTurnoverTask(w http.ResponseWriter, r *http.Request) {
+	var body AssignTurnoverTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	task, err := h.service.AssignTurnoverTask(r.Context(), services.AssignTurnoverTaskInput{
+		TaskID:       chi.URLParam(r, "turnover_task_id"),
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: body.ClientUserID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBTurnoverTaskToRest(task)})
+}
+
+type CompleteTurnoverTaskRequest struct {
+	Notes *string `json:"notes,omitempty" validate:"omitempty,max=2000" example:"Replaced a broken lamp shade"`
+}
+
+// CompleteTurnoverTask godoc
+//
+//	@Summary		Complete a turnover task
+//	@Description	Mark a pending turnover task done. The unit is released for booking once none of its turnover tasks is pending.
+//	@Tags			TurnoverTasks
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id			path		string						true	"Client ID"
+//	@Param			property_id			path		string						true	"Property ID"
+//	@Param			turnover_task_id	path		string						true	"Turnover task ID"
+//	@Param			body				body		CompleteTurnoverTaskRequest	false	"Completion notes"
+//	@Success		200					{object}	object{data=transformations.OutputTurnoverTask}
+//	@Failure		400					{object}	lib.HTTPError
+//	@Failure		401					{object}	string
+//	@Failure		404					{object}	lib.HTTPError
+//	@Failure		500					{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/turnover-tasks/{turnover_task_id}/complete [patch]
+func (h *TurnoverTaskHandler) CompleteTurnoverTask(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CompleteTurnoverTaskRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	task, err := h.service.CompleteTurnoverTask(r.Context(), services.CompleteTurnoverTaskInput{
+		TaskID:       chi.URLParam(r, "turnover_task_id"),
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: clientUser.ID,
+		Notes:        body.Notes,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBTurnoverTaskToRest(task)})
+}
+
+type CancelTurnoverTaskRequest struct {
+	Reason string `json:"reason" validate:"required,max=500" example:"Unit was not slept in"`
+}
+
+// CancelTurnoverTask godoc
+//
+//	@Summary		Cancel a turnover task
+//	@Description	Drop a pending turnover task that does not need doing. The unit is released for booking once none of its turnover tasks is pending.
+//	@Tags			TurnoverTasks
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id			path		string						true	"Client ID"
+//	@Param			property_id			path		string						true	"Property ID"
+//	@Param			turnover_task_id	path		string						true	"Turnover task ID"
+//	@Param			body				body		CancelTurnoverTaskRequest	true	"Cancellation reason"
+//	@Success		200					{object}	object{data=transformations.OutputTurnoverTask}
+//	@Failure		400					{object}	lib.HTTPError
+//	@Failure		401					{object}	string
+//	@Failure		404					{object}	lib.HTTPError
+//	@Failure		500					{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/turnover-tasks/{turnover_task_id}/cancel [patch]
+func (h *TurnoverTaskHandler) CancelTurnoverTask(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CancelTurnoverTaskRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	task, err := h.service.CancelTurnoverTask(r.Context(), services.CancelTurnoverTaskInput{
+		TaskID:       chi.URLParam(r, "turnover_task_id"),
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: clientUser.ID,
+		Reason:       body.Reason,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBTurnoverTaskToRest(task)})
+}
@@ -0,0 +1,366 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/go-chi/chi/v5"
+)
+
+type CreateUnitCalendarSubscriptionRequest struct {
+	Name string `json:"name" validate:"required"     example:"Airbnb"`
+	URL  string `json:"url"  validate:"required,url" example:"https://www.airbnb.com/calendar/ical/12345.ics?s=abc" description:"The iCal export URL the other channel gives for this listing"`
+}
+
+// CreateUnitCalendarSubscription godoc
+//
+//	@Summary		Subscribe a unit to an external calendar (Admin)
+//	@Description	Import the unit's calendar from another channel it is listed on, such as Airbnb or Booking.com (Admin). The feed is synced at once and then hourly; its events block the unit's dates as EXTERNAL blocks, and the manager is emailed when one lands on a confirmed booking.
+//	@Tags			Booking
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string									true	"Property ID"
+//	@Param			unit_id		path		string									true	"Unit ID"
+//	@Param			body		body		CreateUnitCalendarSubscriptionRequest	true	"Calendar subscription"
+//	@Success		201			{object}	object{data=transformations.OutputUnitCalendarSubscription}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-subscriptions [post]
+func (h *BookingHandler) CreateUnitCalendarSubscription(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CreateUnitCalendarSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	subscription, err := h.calendarService.CreateSubscription(r.Context(), services.CreateUnitCalendarSubscriptionInput{
+		PropertyID:            chi.URLParam(r, "property_id"),
+		UnitID:                chi.URLParam(r, "unit_id"),
+		Name:                  body.Name,
+		URL:                   body.URL,
+		CreatedByClientUserID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBUnitCalendarSubscriptionToRest(subscription)})
+}
+
+type ListUnitCalendarSubscriptionsFilterRequest struct {
+	lib.FilterQueryInput
+	LastSyncStatus *string `json:"last_sync_status,omitempty" validate:"omitempty,oneof=PENDING SUCCEEDED FAILED" example:"FAILED"`
+}
+
+// ListUnitCalendarSubscriptions godoc
+//
+//	@Summary		List a unit's calendar subscriptions (Admin)
+//	@Description	List the external calendars a unit imports (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string										true	"Property ID"
+//	@Param			unit_id		path		string										true	"Unit ID"
+//	@Param			q			query		ListUnitCalendarSubscriptionsFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputUnitCalendarSubscription,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-subscriptions [get]
+func (h *BookingHandler) ListUnitCalendarSubscriptions(w http.ResponseWriter, r *http.Request) {
+	filters := ListUnitCalendarSubscriptionsFilterRequest{
+		LastSyncStatus: lib.NullOrString(r.URL.Query().Get("last_sync_status")),
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	input := repository.ListUnitCalendarSubscriptionsFilter{
+		FilterQuery:    *filterQuery,
+		PropertyID:     chi.URLParam(r, "property_id"),
+		UnitID:         chi.URLParam(r, "unit_id"),
+		LastSyncStatus: filters.LastSyncStatus,
+	}
+
+	subscriptions, listErr := h.calendarService.ListSubscriptions(r.Context(), input)
+	if listErr != nil {
+		HandleErrorResponse(w, listErr)
+		return
+	}
+
+	count, countErr := h.calendarService.CountSubscriptions(r.Context(), input)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, 0, len(subscriptions))
+	for i := range subscriptions {
+		rows = append(rows, transformations.DBUnitCalendarSubscriptionToRest(&subscriptions[i]))
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+func unitCalendarSubscriptionQuery(r *http.Request) repository.GetUnitCalendarSubscriptionQuery {
+	propertyID := chi.URLParam(r, "property_id")
+	unitID := chi.URLParam(r, "unit_id")
+
+	return repository.GetUnitCalendarSubscriptionQuery{
+		ID:         chi.URLParam(r, "subscription_id"),
+		PropertyID: &propertyID,
+		UnitID:     &unitID,
+	}
+}
+
+// GetUnitCalendarSubscription godoc
+//
+//	@Summary		Get a unit calendar subscription (Admin)
+//	@Description	Get an external calendar a unit imports, with how its last sync went (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id		path		string	true	"Property ID"
+//	@Param			unit_id			path		string	true	"Unit ID"
+//	@Param			subscription_id	path		string	true	"Calendar subscription ID"
+//	@Success		200				{object}	object{data=transformations.OutputUnitCalendarSubscription}
+//	@Failure		401				{object}	string
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-subscriptions/{subscription_id} [get]
+func (h *BookingHandler) GetUnitCalendarSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, err := h.calendarService.GetSubscription(r.Context(), unitCalendarSubscriptionQuery(r))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBUnitCalendarSubscriptionToRest(subscription)})
+}
+
+type UpdateUnitCalendarSubscriptionRequest struct {
+	Name *string `json:"name,omitempty" validate:"omitempty,min=1" example:"Booking.com"`
+	URL  *string `json:"url,omitempty"  validate:"omitempty,url"   example:"https://admin.booking.com/hotel/hoteladmin/ical.html?t=abc" description:"Changing the URL syncs the new feed at once"`
+}
+
+// UpdateUnitCalendarSubscription godoc
+//
+//	@Summary		Update a unit calendar subscription (Admin)
+//	@Description	Rename a calendar subscription or point it at a new feed (Admin)
+//	@Tags			Booking
+//	@Accept			json
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id		path		string									true	"Property ID"
+//	@Param			unit_id			path		string									true	"Unit ID"
+//	@Param			subscription_id	path		string									true	"Calendar subscription ID"
+//	@Param			body			body		UpdateUnitCalendarSubscriptionRequest	true	"Changes"
+//	@Success		200				{object}	object{data=transformations.OutputUnitCalendarSubscription}
+//	@Failure		400				{object}	lib.HTTPError
+//	@Failure		401				{object}	string
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-subscriptions/{subscription_id} [patch]
+func (h *BookingHandler) UpdateUnitCalendarSubscription(w http.ResponseWriter, r *http.Request) {
+	var body UpdateUnitCalendarSubscriptionRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	subscription, err := h.calendarService.UpdateSubscription(r.Context(), services.UpdateUnitCalendarSubscriptionInput{
+		ID:         chi.URLParam(r, "subscription_id"),
+		PropertyID: chi.URLParam(r, "property_id"),
+		UnitID:     chi.URLParam(r, "unit_id"),
+		Name:       body.Name,
+		URL:        body.URL,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBUnitCalendarSubscriptionToRest(subscription)})
+}
+
+// DeleteUnitCalendarSubscription godoc
+//
+//	@Summary		Delete a unit calendar subscription (Admin)
+//	@Description	Stop importing a calendar and release the dates its events blocked (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id		path		string	true	"Property ID"
+//	@Param			unit_id			path		string	true	"Unit ID"
+//	@Param			subscription_id	path		string	true	"Calendar subscription ID"
+//	@Success		204				{object}	nil
+//	@Failure		401				{object}	string
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-subscriptions/{subscription_id} [delete]
+func (h *BookingHandler) DeleteUnitCalendarSubscription(w http.ResponseWriter, r *http.Request) {
+	err := h.calendarService.DeleteSubscription(r.Context(), repository.DeleteUnitCalendarSubscriptionInput{
+		ID:         chi.URLParam(r, "subscription_id"),
+		PropertyID: chi.URLParam(r, "property_id"),
+		UnitID:     chi.URLParam(r, "unit_id"),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SyncUnitCalendarSubscription godoc
+//
+//	@Summary		Sync a unit calendar subscription now (Admin)
+//	@Description	Fetch the subscription's feed now rather than at the next hourly sync (Admin). A feed that cannot be read is reported in last_sync_status and last_sync_error.
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id		path		string	true	"Property ID"
+//	@Param			unit_id			path		string	true	"Unit ID"
+//	@Param			subscription_id	path		string	true	"Calendar subscription ID"
+//	@Success		200				{object}	object{data=transformations.OutputUnitCalendarSubscription}
+//	@Failure		401				{object}	string
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-subscriptions/{subscription_id}/sync [post]
+func (h *BookingHandler) SyncUnitCalendarSubscription(w http.ResponseWriter, r *http.Request) {
+	subscription, err := h.calendarService.SyncSubscription(r.Context(), unitCalendarSubscriptionQuery(r))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBUnitCalendarSubscriptionToRest(subscription)})
+}
+
+func unitQuery(r *http.Request) repository.GetUnitQuery {
+	return repository.GetUnitQuery{
+		PropertyID: chi.URLParam(r, "property_id"),
+		UnitID:     chi.URLParam(r, "unit_id"),
+	}
+}
+
+// GetUnitCalendarExport godoc
+//
+//	@Summary		Get a unit's calendar export (Admin)
+//	@Description	Whether the unit publishes its bookings and blocks as an iCal feed, and the feed's path (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string	true	"Property ID"
+//	@Param			unit_id		path		string	true	"Unit ID"
+//	@Success		200			{object}	object{data=transformations.OutputUnitCalendarExport}
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-export [get]
+func (h *BookingHandler) GetUnitCalendarExport(w http.ResponseWriter, r *http.Request) {
+	unit, err := h.unitService.GetUnit(r.Context(), unitQuery(r))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBUnitToCalendarExportRest(unit)})
+}
+
+// EnableUnitCalendarExport godoc
+//
+//	@Summary		Turn on or renew a unit's calendar export (Admin)
+//	@Description	Give the unit a new iCal feed URL for other channels to import (Admin). Any earlier URL stops working, so call this again if a feed URL leaks.
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string	true	"Property ID"
+//	@Param			unit_id		path		string	true	"Unit ID"
+//	@Success		200			{object}	object{data=transformations.OutputUnitCalendarExport}
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-export [post]
+func (h *BookingHandler) EnableUnitCalendarExport(w http.ResponseWriter, r *http.Request) {
+	unit, err := h.calendarService.EnableExport(r.Context(), unitQuery(r))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBUnitToCalendarExportRest(unit)})
+}
+
+// DisableUnitCalendarExport godoc
+//
+//	@Summary		Turn off a unit's calendar export (Admin)
+//	@Description	Stop publishing the unit's iCal feed; its URL stops working (Admin)
+//	@Tags			Booking
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			property_id	path		string	true	"Property ID"
+//	@Param			unit_id		path		string	true	"Unit ID"
+//	@Success		204			{object}	nil
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/units/{unit_id}/calendar-export [delete]
+func (h *BookingHandler) DisableUnitCalendarExport(w http.ResponseWriter, r *http.Request) {
+	if err := h.calendarService.DisableExport(r.Context(), unitQuery(r)); err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// PublicGetUnitCalendar godoc
+//
+//	@Summary		Get a unit's iCal feed (public)
+//	@Description	The unit's bookings and blocks as an iCalendar document, for other channels to import. The token in the path is the only credential.
+//	@Tags			Public
+//	@Produce		text/calendar
+//	@Param			token	path		string	true	"Calendar export token"
+//	@Success		200		{string}	string
+//	@Failure		404		{object}	lib.HTTPError
+//	@Failure		500		{object}	string
+//	@Router			/api/v1/unit-calendars/{token}.ics [get]
+func (h *BookingHandler) PublicGetUnitCalendar(w http.ResponseWriter, r *http.Request) {
+	feed, err := h.calendarService.RenderExport(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(feed)
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+// defaultListingRadiusKm is how far around lat/lng a search reaches when no
+// radius_km is given.
+const defaultListingRadiusKm = 10
+
+// maxListingPageSize caps a page of the public search, so one request cannot
+// pull every listed unit at once.
+const maxListingPageSize = 50
+
+type ListUnitListingsRequest struct {
+	Page         int     `json:"page"           validate:"gte=0"                                                example:"1"`
+	PageSize     int     `json:"page_size"      validate:"gte=0,lte=50"                                         example:"20"               description:"At most 50; larger sizes are cut to 50"`
+	Order        string  `json:"order"          validate:"omitempty,oneof=asc desc"                             example:"asc"`
+	OrderBy      string  `json:"order_by"       validate:"omitempty,oneof=created_at rent_fee distance"         example:"rent_fee"         description:"created_at, rent_fee, or distance when lat and lng are given"`
+	Query        string  `json:"query"          validate:"omitempty"                                            example:"studio"           description:"Matched against unit names and descriptions"`
+	Mode         string  `json:"mode"           validate:"omitempty,oneof=LEASE BOOKING"                        example:"BOOKING"          description:"Only units to lease or only units to book"`
+	City         string  `json:"city"           validate:"omitempty"                                            example:"Accra"`
+	Region       string  `json:"region"         validate:"omitempty"                                            example:"Greater Accra"`
+	Lat          float64 `json:"lat"            validate:"omitempty,latitude"                                   example:"5.6037"           description:"Search around this point; requires lng"`
+	Lng          float64 `json:"lng"            validate:"omitempty,longitude"                                  example:"-0.1870"          description:"Search around this point; requires lat"`
+	RadiusKm     float64 `json:"radius_km"      validate:"omitempty,gt=0,lte=500"                               example:"10"               description:"Distance from lat/lng in km; defaults to 10"`
+	MinRentFee   int64   `json:"min_rent_fee"   validate:"omitempty,min=0"                                      example:"100000"`
+	MaxRentFee   int64   `json:"max_rent_fee"   validate:"omitempty,min=0"                                      example:"500000"`
+	Type         string  `json:"type"           validate:"omitempty,oneof=APARTMENT HOUSE STUDIO OFFICE RETAIL" example:"APARTMENT"`
+	Features     string  `json:"features"       validate:"omitempty,json"                                       example:"{\"bedrooms\":2}" description:"JSON object the unit's features must contain"`
+	Guests       int64   `json:"guests"         validate:"omitempty,min=1"                                      example:"2"                description:"Units that sleep at least this many"`
+	CheckInDate  string  `json:"check_in_date"  validate:"omitempty"                                            example:"2026-03-01"       description:"Units free to book from this date; requires check_out_date"`
+	CheckOutDate string  `json:"check_out_date" validate:"omitempty"                                            example:"2026-03-04"       description:"Units free to book until this date; requires check_in_date"`
+}
+
+// parseUnitListingsFilter reads the public search parameters. Paging and
+// sorting follow the usual list params, with page_size capped, but populate,
+// ids and search_fields are not honoured: a public caller only searches names
+// and descriptions.
+func parseUnitListingsFilter(query url.Values) (*repository.ListUnitListingsFilter, error) {
+	filterQuery, filterErr := lib.GenerateQuery(query)
+	if filterErr != nil {
+		return nil, pkg.BadRequestError("InvalidListingQuery", &pkg.RentLoopErrorParams{Err: filterErr})
+	}
+	if filterQuery.Order != "" && filterQuery.Order != "asc" && filterQuery.Order != "desc" {
+		return nil, pkg.BadRequestError("InvalidOrder", nil)
+	}
+	filterQuery.PageSize = min(filterQuery.PageSize, maxListingPageSize)
+	filterQuery.Populate = nil
+	filterQuery.IDs = nil
+	filterQuery.DateRange = nil
+	filterQuery.Search = nil
+	if search := query.Get("query"); search != "" {
+		filterQuery.Search = &lib.Search{Query: search, SearchFields: []string{"name", "description"}}
+	}
+
+	filter := repository.ListUnitListingsFilter{
+		FilterQuery: *filterQuery,
+		Mode:        lib.NullOrString(query.Get("mode")),
+		City:        lib.NullOrString(query.Get("city")),
+		Region:      lib.NullOrString(query.Get("region")),
+		Type:        lib.NullOrString(query.Get("type")),
+		Features:    lib.NullOrString(query.Get("features")),
+	}
+
+	if filter.Mode != nil && *filter.Mode != "LEASE" && *filter.Mode != "BOOKING" {
+		return nil, pkg.BadRequestError("InvalidListingMode", nil)
+	}
+	unitTypes := []string{"APARTMENT", "HOUSE", "STUDIO", "OFFICE", "RETAIL"}
+	if filter.Type != nil && !slices.Contains(unitTypes, *filter.Type) {
+		return nil, pkg.BadRequestError("InvalidUnitType", nil)
+	}
+
+	if filter.Features != nil {
+		var features map[string]any
+		if err := json.Unmarshal([]byte(*filter.Features), &features); err != nil || features == nil {
+			return nil, pkg.BadRequestError("InvalidFeatures", nil)
+		}
+	}
+
+	near, nearErr := parseListingNear(query)
+	if nearErr != nil {
+		return nil, nearErr
+	}
+	filter.Near = near
+
+	switch filterQuery.OrderBy {
+	case "", "created_at", "rent_fee":
+	case "distance":
+		if near == nil {
+			return nil, pkg.BadRequestError("DistanceOrderRequiresLocation", nil)
+		}
+	default:
+		return nil, pkg.BadRequestError("InvalidOrderBy", nil)
+	}
+
+	for param, target := range map[string]**int64{
+		"min_rent_fee": &filter.MinRentFee,
+		"max_rent_fee": &filter.MaxRentFee,
+		"guests":       &filter.Guests,
+	} {
+		value := query.Get(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := lib.ConvertStringToInt64(value)
+		if err != nil || parsed < 0 {
+			return nil, pkg.BadRequestError("InvalidListingQuery", &pkg.RentLoopErrorParams{
+				Metadata: map[string]string{"param": param},
+			})
+		}
+		*target = &parsed
+	}
+	if filter.MinRentFee != nil && filter.MaxRentFee != nil && *filter.MinRentFee > *filter.MaxRentFee {
+		return nil, pkg.BadRequestError("InvalidRentFeeRange", nil)
+	}
+
+	checkIn, checkInErr := ParseDateParam(query.Get("check_in_date"))
+	if checkInErr != nil {
+		return nil, pkg.BadRequestError("InvalidCheckInDate", nil)
+	}
+	checkOut, checkOutErr := ParseDateParam(query.Get("check_out_date"))
+	if checkOutErr != nil {
+		return nil, pkg.BadRequestError("InvalidCheckOutDate", nil)
+	}
+	if (checkIn == nil) != (checkOut == nil) || (checkIn != nil && !checkOut.After(*checkIn)) {
+		return nil, pkg.BadRequestError("InvalidStayDates", nil)
+	}
+	if checkIn != nil {
+		// Availability only means anything for units that are booked by date.
+		if filter.Mode != nil && *filter.Mode == "LEASE" {
+			return nil, pkg.BadRequestError("StayDatesRequireBookingMode", nil)
+		}
+		bookingMode := "BOOKING"
+		filter.Mode = &bookingMode
+		filter.CheckIn = checkIn
+		filter.CheckOut = checkOut
+	}
+
+	return &filter, nil
+}
+
+// parseListingNear reads lat, lng and radius_km into a search radius, or
+// nil when no point is given.
+func parseListingNear(query url.Values) (*repository.GeoRadius, error) {
+	lat, lng, radius := query.Get("lat"), query.Get("lng"), query.Get("radius_km")
+	if lat == "" && lng == "" {
+		if radius != "" {
+			return nil, pkg.BadRequestError("RadiusRequiresLocation", nil)
+		}
+		return nil, nil
+	}
+
+	latitude, latErr := lib.ConvertStringToFloat64(lat)
+	longitude, lngErr := lib.ConvertStringToFloat64(lng)
+	if latErr != nil || lngErr != nil || latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return nil, pkg.BadRequestError("InvalidLocation", nil)
+	}
+
+	near := repository.GeoRadius{Latitude: latitude, Longitude: longitude, RadiusKm: defaultListingRadiusKm}
+	if radius != "" {
+		radiusKm, radiusErr := lib.ConvertStringToFloat64(radius)
+		if radiusErr != nil || radiusKm <= 0 || radiusKm > 500 {
+			return nil, pkg.BadRequestError("InvalidRadius", nil)
+		}
+		near.RadiusKm = radiusKm
+	}
+
+	return &near, nil
+}
+
+// ListUnitListings godoc
+//
+//	@Summary		Search listed units
+//	@Description	Search the units to lease or book across every org that lists its units publicly
+//	@Tags			Units
+//	@Accept			json
+//	@Produce		json
+//	@Param			q	query		ListUnitListingsRequest	true	"Search"
+//	@Success		200	{object}	object{data=object{rows=[]transformations.OutputUnitListing,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400	{object}	lib.HTTPError	"Invalid search parameters"
+//	@Failure		500	{object}	string			"An unexpected error occurred"
+//	@Router			/api/v1/units [get]
+func (h *UnitHandler) ListUnitListings(w http.ResponseWriter, r *http.Request) {
+	filter, filterErr := parseUnitListingsFilter(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	units, unitsErr := h.service.ListUnitListings(r.Context(), *filter)
+	if unitsErr != nil {
+		HandleErrorResponse(w, unitsErr)
+		return
+	}
+
+	count, countErr := h.service.CountUnitListings(r.Context(), *filter)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	unitsTransformed := make([]any, 0)
+	for _, unit := range units {
+		unitsTransformed = append(unitsTransformed, transformations.DBUnitListingToRest(&unit))
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(&filter.FilterQuery, unitsTransformed, count))
+}
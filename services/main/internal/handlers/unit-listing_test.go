@@ -0,0 +1,105 @@
+package handlers
+
+import (
+	"net/url"
+	"testing"
+)
+
+// The search is public, so anything that would reach SQL or widen what a
+// caller sees must come from a fixed set, never the query string as given.
+func TestParseUnitListingsFilterIgnoresPrivateListParams(t *testing.T) {
+	filter, err := parseUnitListingsFilter(url.Values{
+		"query":         {"studio"},
+		"search_fields": {"calendar_export_token"},
+		"populate":      {"CreatedBy"},
+		"ids":           {"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if filter.Populate != nil || filter.IDs != nil {
+		t.Errorf("populate or ids carried into a public search: %+v", filter.FilterQuery)
+	}
+	if filter.Search == nil || len(filter.Search.SearchFields) != 2 ||
+		filter.Search.SearchFields[0] != "name" || filter.Search.SearchFields[1] != "description" {
+		t.Errorf("search fields = %+v, want name and description", filter.Search)
+	}
+}
+
+func TestParseUnitListingsFilterCapsPageSize(t *testing.T) {
+	tests := map[string]int{"": 10, "20": 20, "50": 50, "10000": maxListingPageSize}
+
+	for pageSize, want := range tests {
+		filter, err := parseUnitListingsFilter(url.Values{"page_size": {pageSize}})
+		if err != nil {
+			t.Fatalf("page_size %q: unexpected error: %v", pageSize, err)
+		}
+		if filter.PageSize != want {
+			t.Errorf("page_size %q gave %d, want %d", pageSize, filter.PageSize, want)
+		}
+	}
+}
+
+func TestParseUnitListingsFilterStayDatesMeanBookings(t *testing.T) {
+	filter, err := parseUnitListingsFilter(url.Values{
+		"check_in_date":  {"2026-03-01"},
+		"check_out_date": {"2026-03-04"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filter.Mode == nil || *filter.Mode != "BOOKING" {
+		t.Errorf("mode = %v, want BOOKING", filter.Mode)
+	}
+	if filter.CheckIn == nil || filter.CheckOut == nil {
+		t.Error("stay dates were dropped")
+	}
+}
+
+func TestParseUnitListingsFilterNearDefaultsRadius(t *testing.T) {
+	filter, err := parseUnitListingsFilter(url.Values{"lat": {"5.6037"}, "lng": {"-0.1870"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if filter.Near == nil || filter.Near.RadiusKm != defaultListingRadiusKm {
+		t.Errorf("near = %+v, want a %d km radius", filter.Near, defaultListingRadiusKm)
+	}
+}
+
+func TestParseUnitListingsFilterRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query url.Values
+	}{
+		{name: "unknown mode", query: url.Values{"mode": {"RENT"}}},
+		{name: "unknown order_by", query: url.Values{"order_by": {"id; DROP TABLE units"}}},
+		{name: "distance without a point", query: url.Values{"order_by": {"distance"}}},
+		{name: "lat without lng", query: url.Values{"lat": {"5.6"}}},
+		{name: "latitude out of range", query: url.Values{"lat": {"95"}, "lng": {"0"}}},
+		{name: "radius without a point", query: url.Values{"radius_km": {"5"}}},
+		{name: "features not an object", query: url.Values{"features": {"[1,2]"}}},
+		{name: "inverted price range", query: url.Values{"min_rent_fee": {"500"}, "max_rent_fee": {"100"}}},
+		{name: "check in only", query: url.Values{"check_in_date": {"2026-03-01"}}},
+		{
+			name:  "check out before check in",
+			query: url.Values{"check_in_date": {"2026-03-04"}, "check_out_date": {"2026-03-01"}},
+		},
+		{
+			name: "stay dates on a lease search",
+			query: url.Values{
+				"mode":           {"LEASE"},
+				"check_in_date":  {"2026-03-01"},
+				"check_out_date": {"2026-03-04"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := parseUnitListingsFilter(test.query); err == nil {
+				t.Errorf("%v was accepted", test.query)
+			}
+		})
+	}
+}
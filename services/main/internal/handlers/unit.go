@@ -34,6 +34,8 @@ type CreateUnitRequest struct {
 	PaymentFrequency    string          `json:"payment_frequency"     validate:"required,oneof=WEEKLY DAILY MONTHLY QUARTERLY BIANNUALLY ANNUALLY"                                   example:"WEEKLY"                          description:"Payment frequency (e.g., WEEKLY, DAILY, MONTHLY, QUARTERLY, BIANNUALLY, ANNUALLY)"`
 	Features            *map[string]any `json:"features"              validate:"omitempty"                                                                                                                                     description:"Additional metadata in JSON format"`
 	MaxOccupantsAllowed int             `json:"max_occupants_allowed" validate:"required"                                                                                            example:"4"                               description:"Maximum number of occupants allowed"`
+	BaseOccupancy       int             `json:"base_occupancy"        validate:"omitempty,min=0,ltefield=MaxOccupantsAllowed"                                                        example:"2"                               description:"Guests included in the rate; 0 charges no one extra"`
+	ExtraGuestFee       int64           `json:"extra_guest_fee"       validate:"omitempty,min=0"                                                                                     example:"5000"                            description:"Charged per guest above base_occupancy per period of a booking"`
 }
 
 // CreateUnit godoc
@@ -91,6 +93,8 @@ func (h *UnitHandler) CreateUnit(w http.ResponseWriter, r *http.Request) {
 		PaymentFrequency:    body.PaymentFrequency,
 		Features:            body.Features,
 		MaxOccupantsAllowed: body.MaxOccupantsAllowed,
+		BaseOccupancy:       body.BaseOccupancy,
+		ExtraGuestFee:       body.ExtraGuestFee,
 		CreatedByID:         currentClientUser.ID,
 	}
 
@@ -290,6 +294,8 @@ type UpdateUnitRequest struct {
 	PaymentFrequency    *string               `json:"payment_frequency"     validate:"omitempty,oneof=WEEKLY DAILY MONTHLY QUARTERLY BIANNUALLY ANNUALLY" example:"WEEKLY"                               description:"Payment frequency (e.g., WEEKLY, DAILY, MONTHLY, QUARTERLY, BIANNUALLY, ANNUALLY)"`
 	Features            *map[string]any       `json:"features"              validate:"omitempty"                                                                                                         description:"Additional metadata in JSON format"`
 	MaxOccupantsAllowed *int                  `json:"max_occupants_allowed" validate:"omitempty"                                                          example:"4"                                    description:"Maximum number of occupants allowed"`
+	BaseOccupancy       *int                  `json:"base_occupancy"        validate:"omitempty,min=0"                                                    example:"2"                                    description:"Guests included in the rate; 0 charges no one extra"`
+	ExtraGuestFee       *int64                `json:"extra_guest_fee"       validate:"omitempty,min=0"                                                    example:"5000"                                 description:"Charged per guest above base_occupancy per period of a booking"`
 	PropertyBlockID     *string               `json:"property_block_id"     validate:"omitempty,uuid4"                                                    example:"a8098c1a-f86e-11da-bd1a-00112444be1e" description:"Moves the unit to a different block within the same property"`
 }
 
@@ -342,6 +348,8 @@ func (h *UnitHandler) UpdateUnit(w http.ResponseWriter, r *http.Request) {
 		PaymentFrequency:    body.PaymentFrequency,
 		Features:            body.Features,
 		MaxOccupantsAllowed: body.MaxOccupantsAllowed,
+		BaseOccupancy:       body.BaseOccupancy,
+		ExtraGuestFee:       body.ExtraGuestFee,
 		PropertyBlockID:     body.PropertyBlockID,
 	}
 
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type ViewingSlotHandler struct {
+	appCtx  pkg.AppContext
+	service services.ViewingSlotService
+}
+
+func NewViewingSlotHandler(appCtx pkg.AppContext, service services.ViewingSlotService) ViewingSlotHandler {
+	return ViewingSlotHandler{appCtx: appCtx, service: service}
+}
+
+type CreateViewingSlotRequest struct {
+	ClientUserID *string   `json:"client_user_id,omitempty" validate:"omitempty,uuid4"        example:"d290f1ee-6c54-4b01-90e6-d701748f0851" description:"Staff member who runs the viewings; defaults to you"`
+	StartsAt     time.Time `json:"starts_at"                validate:"required"               example:"2026-10-24T10:00:00Z"`
+	EndsAt       time.Time `json:"ends_at"                  validate:"required"               example:"2026-10-24T11:00:00Z"`
+	Capacity     *int64    `json:"capacity,omitempty"       validate:"omitempty,min=1,max=50" example:"3"                                    description:"How many viewings can be booked into the slot; defaults to 1"`
+}
+
+// CreateViewingSlot godoc
+//
+//	@Summary		Open a viewing slot
+//	@Description	Open a window in which prospects can book to view any available unit at the property. A staff member's open slots may not overlap.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string						true	"Client ID"
+//	@Param			property_id	path		string						true	"Property ID"
+//	@Param			body		body		CreateViewingSlotRequest	true	"Slot"
+//	@Success		201			{object}	object{data=transformations.OutputViewingSlot}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		409			{object}	lib.HTTPError	"Overlaps another of the staff member's slots"
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewing-slots [post]
+func (h *ViewingSlotHandler) CreateViewingSlot(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CreateViewingSlotRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	hostID := clientUser.ID
+	if body.ClientUserID != nil {
+		hostID = *body.ClientUserID
+	}
+	capacity := int64(1)
+	if body.Capacity != nil {
+		capacity = *body.Capacity
+	}
+
+	slot, err := h.service.CreateViewingSlot(r.Context(), services.CreateViewingSlotInput{
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: hostID,
+		StartsAt:     body.StartsAt,
+		EndsAt:       body.EndsAt,
+		Capacity:     capacity,
+		CreatedByID:  clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBViewingSlotToRest(slot)})
+}
+
+type ListViewingSlotsFilterRequest struct {
+	lib.FilterQueryInput
+	Status       *string `json:"status,omitempty"         validate:"omitempty,oneof=OPEN CANCELLED"               example:"OPEN"`
+	ClientUserID *string `json:"client_user_id,omitempty" validate:"omitempty,uuid4"                              example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	StartsFrom   *string `json:"starts_from,omitempty"    validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2026-10-19T00:00:00Z"                 description:"Slots starting at or after this time"`
+	StartsTo     *string `json:"starts_to,omitempty"      validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00" example:"2026-11-02T00:00:00Z"                 description:"Slots starting before this time"`
+}
+
+// ListViewingSlots godoc
+//
+//	@Summary		List a property's viewing slots
+//	@Description	Slots soonest first.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string							true	"Client ID"
+//	@Param			property_id	path		string							true	"Property ID"
+//	@Param			q			query		ListViewingSlotsFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputViewingSlot,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewing-slots [get]
+func (h *ViewingSlotHandler) ListViewingSlots(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	filters := ListViewingSlotsFilterRequest{
+		Status:       lib.NullOrString(r.URL.Query().Get("status")),
+		ClientUserID: lib.NullOrString(r.URL.Query().Get("client_user_id")),
+		StartsFrom:   lib.NullOrString(r.URL.Query().Get("starts_from")),
+		StartsTo:     lib.NullOrString(r.URL.Query().Get("starts_to")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	repoFilters := repository.ListViewingSlotsFilter{
+		PropertyID:   &propertyID,
+		ClientUserID: filters.ClientUserID,
+		Status:       filters.Status,
+		StartsFrom:   parseViewingTime(filters.StartsFrom),
+		StartsTo:     parseViewingTime(filters.StartsTo),
+	}
+
+	slots, listErr := h.service.ListViewingSlots(r.Context(), *filterQuery, repoFilters)
+	if listErr != nil {
+		HandleErrorResponse(w, listErr)
+		return
+	}
+
+	count, countErr := h.service.CountViewingSlots(r.Context(), *filterQuery, repoFilters)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, len(slots))
+	for i := range slots {
+		rows[i] = transformations.DBViewingSlotToRest(&slots[i])
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+// parseViewingTime reads an RFC3339 time the request validation has already
+// checked.
+func parseViewingTime(value *string) *time.Time {
+	if value == nil {
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, *value)
+	if err != nil {
+		return nil
+	}
+	return &parsed
+}
+
+type GetViewingSlotQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetViewingSlot godoc
+//
+//	@Summary	Get a viewing slot
+//	@Tags		Viewings
+//	@Security	BearerAuth
+//	@Produce	json
+//	@Param		client_id		path		string				true	"Client ID"
+//	@Param		property_id		path		string				true	"Property ID"
+//	@Param		viewing_slot_id	path		string				true	"Viewing slot ID"
+//	@Param		q				query		GetViewingSlotQuery	true	"Query parameters"
+//	@Success	200				{object}	object{data=transformations.OutputViewingSlot}
+//	@Failure	401				{object}	string
+//	@Failure	404				{object}	lib.HTTPError
+//	@Failure	500				{object}	string
+//	@Router		/api/v1/admin/clients/{client_id}/properties/{property_id}/viewing-slots/{viewing_slot_id} [get]
+func (h *ViewingSlotHandler) GetViewingSlot(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	slot, err := h.service.GetViewingSlot(r.Context(), repository.GetViewingSlotQuery{
+		ID:         chi.URLParam(r, "viewing_slot_id"),
+		PropertyID: &propertyID,
+		Populate:   GetPopulateFields(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBViewingSlotToRest(slot)})
+}
+
+// CancelViewingSlot godoc
+//
+//	@Summary		Cancel a viewing slot
+//	@Description	Withdraw an open slot. Viewings booked into it must be cancelled first so each prospect is told.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id		path		string	true	"Client ID"
+//	@Param			property_id		path		string	true	"Property ID"
+//	@Param			viewing_slot_id	path		string	true	"Viewing slot ID"
+//	@Success		200				{object}	object{data=transformations.OutputViewingSlot}
+//	@Failure		400				{object}	lib.HTTPError
+//	@Failure		401				{object}	string
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewing-slots/{viewing_slot_id}/cancel [patch]
+func (h *ViewingSlotHandler) CancelViewingSlot(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	slot, err := h.service.CancelViewingSlot(r.Context(), services.CancelViewingSlotInput{
+		ID:           chi.URLParam(r, "viewing_slot_id"),
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBViewingSlotToRest(slot)})
+}
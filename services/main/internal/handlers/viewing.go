@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+// viewingSlotsWindow is how far ahead prospects are shown slots unless they
+// ask for another range.
+const viewingSlotsWindow = 14 * 24 * time.Hour
+
+type ViewingHandler struct {
+	appCtx  pkg.AppContext
+	service services.ViewingService
+}
+
+func NewViewingHandler(appCtx pkg.AppContext, service services.ViewingService) ViewingHandler {
+	return ViewingHandler{appCtx: appCtx, service: service}
+}
+
+// ---- Public handlers (no auth) ----
+
+// ListBookableViewingSlots godoc
+//
+//	@Summary		List viewing slots for a unit (public)
+//	@Description	Slots at the unit's property that still have a place, soonest first. Defaults to the next 14 days.
+//	@Tags			Public
+//	@Produce		json
+//	@Param			unit_slug	path		string	true	"Unit Slug"
+//	@Param			from		query		string	false	"Start of range (RFC3339)"
+//	@Param			to			query		string	false	"End of range (RFC3339)"
+//	@Success		200			{object}	object{data=[]transformations.PublicOutputViewingSlot}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/units/{unit_slug}/viewing-slots [get]
+func (h *ViewingHandler) ListBookableViewingSlots(w http.ResponseWriter, r *http.Request) {
+	from, _ := time.Parse(time.RFC3339, r.URL.Query().Get("from"))
+	to, _ := time.Parse(time.RFC3339, r.URL.Query().Get("to"))
+	if from.IsZero() {
+		from = time.Now()
+	}
+	if to.IsZero() {
+		to = from.Add(viewingSlotsWindow)
+	}
+
+	slots, err := h.service.ListBookableSlots(r.Context(), chi.URLParam(r, "unit_slug"), from, to)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	out := make([]any, len(slots))
+	for i := range slots {
+		out[i] = transformations.DBPublicViewingSlotToRest(&slots[i])
+	}
+	json.NewEncoder(w).Encode(map[string]any{"data": out})
+}
+
+type BookViewingRequest struct {
+	ViewingSlotID string  `json:"viewing_slot_id"   validate:"required,uuid4"     example:"9c1d2e3f-4a5b-4c6d-8e7f-0a1b2c3d4e5f"`
+	FirstName     string  `json:"first_name"        validate:"required,max=100"   example:"Ama"`
+	LastName      string  `json:"last_name"         validate:"required,max=100"   example:"Mensah"`
+	Email         *string `json:"email,omitempty"   validate:"omitempty,email"    example:"ama@example.com"`
+	Phone         string  `json:"phone"             validate:"required,e164"      example:"+233241234567"`
+	Message       *string `json:"message,omitempty" validate:"omitempty,max=1000" example:"Is parking included?"`
+}
+
+// BookViewing godoc
+//
+//	@Summary		Book a viewing of a unit (public)
+//	@Description	Take a place in one of the property's viewing slots. The prospect is sent a confirmation with a code to follow or cancel the viewing, and a reminder before it.
+//	@Tags			Public
+//	@Accept			json
+//	@Produce		json
+//	@Param			unit_slug	path		string				true	"Unit Slug"
+//	@Param			body		body		BookViewingRequest	true	"Viewing"
+//	@Success		201			{object}	object{data=transformations.PublicOutputViewing}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		403			{object}	lib.HTTPError	"Phone number has missed too many viewings at the property"
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		409			{object}	lib.HTTPError	"Slot is full, or the unit is already booked for this phone number"
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/units/{unit_slug}/viewings [post]
+func (h *ViewingHandler) BookViewing(w http.ResponseWriter, r *http.Request) {
+	var body BookViewingRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	viewing, err := h.service.BookViewing(r.Context(), services.BookViewingInput{
+		UnitSlug:      chi.URLParam(r, "unit_slug"),
+		ViewingSlotID: body.ViewingSlotID,
+		FirstName:     body.FirstName,
+		LastName:      body.LastName,
+		Email:         body.Email,
+		Phone:         body.Phone,
+		Message:       body.Message,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBPublicViewingToRest(viewing)})
+}
+
+// GetViewingByCode godoc
+//
+//	@Summary	Get a viewing by code (phone-verified)
+//	@Tags		Public
+//	@Produce	json
+//	@Param		code	path		string	true	"Viewing code"
+//	@Param		phone	query		string	true	"Phone number the viewing was booked with"
+//	@Success	200		{object}	object{data=transformations.PublicOutputViewing}
+//	@Failure	403		{object}	lib.HTTPError
+//	@Failure	404		{object}	lib.HTTPError
+//	@Failure	500		{object}	string
+//	@Router		/api/v1/viewings/{code} [get]
+func (h *ViewingHandler) GetViewingByCode(w http.ResponseWriter, r *http.Request) {
+	viewing, err := h.service.GetViewingByCode(r.Context(), chi.URLParam(r, "code"), r.URL.Query().Get("phone"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBPublicViewingToRest(viewing)})
+}
+
+type CancelViewingByCodeRequest struct {
+	Phone string `json:"phone" validate:"required,e164" example:"+233241234567" description:"Phone number the viewing was booked with"`
+}
+
+// CancelViewingByCode godoc
+//
+//	@Summary		Cancel a viewing by code (phone-verified)
+//	@Description	Call off a viewing that has not started yet, freeing the place for someone else.
+//	@Tags			Public
+//	@Accept			json
+//	@Produce		json
+//	@Param			code	path		string						true	"Viewing code"
+//	@Param			body	body		CancelViewingByCodeRequest	true	"Phone verification"
+//	@Success		200		{object}	object{data=transformations.PublicOutputViewing}
+//	@Failure		400		{object}	lib.HTTPError
+//	@Failure		403		{object}	lib.HTTPError
+//	@Failure		404		{object}	lib.HTTPError
+//	@Failure		500		{object}	string
+//	@Router			/api/v1/viewings/{code}/cancel [post]
+func (h *ViewingHandler) CancelViewingByCode(w http.ResponseWriter, r *http.Request) {
+	var body CancelViewingByCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	viewing, err := h.service.CancelViewingByCode(r.Context(), chi.URLParam(r, "code"), body.Phone)
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBPublicViewingToRest(viewing)})
+}
+
+// ---- Admin handlers ----
+
+type ListViewingsFilterRequest struct {
+	lib.FilterQueryInput
+	Status        *string `json:"status,omitempty"          validate:"omitempty,oneof=SCHEDULED COMPLETED NO_SHOW CANCELLED" example:"SCHEDULED"`
+	UnitID        *string `json:"unit_id,omitempty"         validate:"omitempty,uuid4"                                       example:"660e8400-e29b-41d4-a716-446655440000"`
+	ViewingSlotID *string `json:"viewing_slot_id,omitempty" validate:"omitempty,uuid4"                                       example:"9c1d2e3f-4a5b-4c6d-8e7f-0a1b2c3d4e5f"`
+	ClientUserID  *string `json:"client_user_id,omitempty"  validate:"omitempty,uuid4"                                       example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	Phone         *string `json:"phone,omitempty"           validate:"omitempty,e164"                                        example:"+233241234567"`
+}
+
+// ListViewings godoc
+//
+//	@Summary		List a property's viewings
+//	@Description	Viewings soonest first.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string						true	"Client ID"
+//	@Param			property_id	path		string						true	"Property ID"
+//	@Param			q			query		ListViewingsFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputViewing,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewings [get]
+func (h *ViewingHandler) ListViewings(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	filters := ListViewingsFilterRequest{
+		Status:        lib.NullOrString(r.URL.Query().Get("status")),
+		UnitID:        lib.NullOrString(r.URL.Query().Get("unit_id")),
+		ViewingSlotID: lib.NullOrString(r.URL.Query().Get("viewing_slot_id")),
+		ClientUserID:  lib.NullOrString(r.URL.Query().Get("client_user_id")),
+		Phone:         lib.NullOrString(r.URL.Query().Get("phone")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	h.list(w, r, repository.ListViewingsFilter{
+		PropertyID:    &propertyID,
+		UnitID:        filters.UnitID,
+		ViewingSlotID: filters.ViewingSlotID,
+		ClientUserID:  filters.ClientUserID,
+		Status:        filters.Status,
+		Phone:         filters.Phone,
+	})
+}
+
+type ListMyViewingsFilterRequest struct {
+	lib.FilterQueryInput
+	Status *string `json:"status,omitempty" validate:"omitempty,oneof=SCHEDULED COMPLETED NO_SHOW CANCELLED" example:"SCHEDULED"`
+}
+
+// ListMyViewings godoc
+//
+//	@Summary		List my viewings
+//	@Description	Viewings in the signed-in staff member's slots across their properties, soonest first, with each viewing's unit and property. Only SCHEDULED viewings unless another status is asked for.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string						true	"Client ID"
+//	@Param			q			query		ListMyViewingsFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputViewing,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/viewings/me [get]
+func (h *ViewingHandler) ListMyViewings(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filters := ListMyViewingsFilterRequest{
+		Status: lib.NullOrString(r.URL.Query().Get("status")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	status := "SCHEDULED"
+	if filters.Status != nil {
+		status = *filters.Status
+	}
+
+	h.list(w, r, repository.ListViewingsFilter{
+		ClientUserID: &clientUser.ID,
+		Status:       &status,
+	})
+}
+
+func (h *ViewingHandler) list(w http.ResponseWriter, r *http.Request, filters repository.ListViewingsFilter) {
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	// staff check the list on the way to a viewing, so each row carries the
+	// unit and property without another request
+	if filterQuery.Populate == nil {
+		filterQuery.Populate = &[]string{"Unit", "Property"}
+	}
+
+	viewings, listErr := h.service.ListViewings(r.Context(), *filterQuery, filters)
+	if listErr != nil {
+		HandleErrorResponse(w, listErr)
+		return
+	}
+
+	count, countErr := h.service.CountViewings(r.Context(), *filterQuery, filters)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, len(viewings))
+	for i := range viewings {
+		rows[i] = transformations.DBViewingToRest(&viewings[i])
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+type GetViewingQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetViewing godoc
+//
+//	@Summary	Get a viewing
+//	@Tags		Viewings
+//	@Security	BearerAuth
+//	@Produce	json
+//	@Param		client_id	path		string			true	"Client ID"
+//	@Param		property_id	path		string			true	"Property ID"
+//	@Param		viewing_id	path		string			true	"Viewing ID"
+//	@Param		q			query		GetViewingQuery	true	"Query parameters"
+//	@Success	200			{object}	object{data=transformations.OutputViewing}
+//	@Failure	401			{object}	string
+//	@Failure	404			{object}	lib.HTTPError
+//	@Failure	500			{object}	string
+//	@Router		/api/v1/admin/clients/{client_id}/properties/{property_id}/viewings/{viewing_id} [get]
+func (h *ViewingHandler) GetViewing(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	viewing, err := h.service.GetViewing(r.Context(), repository.GetViewingQuery{
+		ID:         chi.URLParam(r, "viewing_id"),
+		PropertyID: &propertyID,
+		Populate:   GetPopulateFields(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBViewingToRest(viewing)})
+}
+
+type ViewingOutcomeRequest struct {
+	Notes *string `json:"notes,omitempty" validate:"omitempty,max=2000" example:"Liked the unit, asked about a longer lease"`
+}
+
+// CompleteViewing godoc
+//
+//	@Summary		Mark a viewing completed
+//	@Description	Record that the prospect came to a viewing that has started.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string					true	"Client ID"
+//	@Param			property_id	path		string					true	"Property ID"
+//	@Param			viewing_id	path		string					true	"Viewing ID"
+//	@Param			body		body		ViewingOutcomeRequest	false	"Notes"
+//	@Success		200			{object}	object{data=transformations.OutputViewing}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewings/{viewing_id}/complete [patch]
+func (h *ViewingHandler) CompleteViewing(w http.ResponseWriter, r *http.Request) {
+	h.recordOutcome(w, r, h.service.CompleteViewing)
+}
+
+// MarkViewingNoShow godoc
+//
+//	@Summary		Mark a viewing as a no-show
+//	@Description	Record that the prospect did not come. A phone number with two no-shows at a property can no longer book viewings there.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string					true	"Client ID"
+//	@Param			property_id	path		string					true	"Property ID"
+//	@Param			viewing_id	path		string					true	"Viewing ID"
+//	@Param			body		body		ViewingOutcomeRequest	false	"Notes"
+//	@Success		200			{object}	object{data=transformations.OutputViewing}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewings/{viewing_id}/no-show [patch]
+func (h *ViewingHandler) MarkViewingNoShow(w http.ResponseWriter, r *http.Request) {
+	h.recordOutcome(w, r, h.service.MarkViewingNoShow)
+}
+
+func (h *ViewingHandler) recordOutcome(
+	w http.ResponseWriter,
+	r *http.Request,
+	record func(ctx context.Context, input services.ViewingOutcomeInput) (*models.Viewing, error),
+) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body ViewingOutcomeRequest
+	if r.Body != nil && r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+			return
+		}
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	viewing, err := record(r.Context(), services.ViewingOutcomeInput{
+		ID:           chi.URLParam(r, "viewing_id"),
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: clientUser.ID,
+		Notes:        body.Notes,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBViewingToRest(viewing)})
+}
+
+type CancelViewingRequest struct {
+	Reason string `json:"reason" validate:"required,max=500" example:"The unit has been let"`
+}
+
+// CancelViewing godoc
+//
+//	@Summary		Cancel a viewing
+//	@Description	Call off a scheduled viewing. The prospect is told why by SMS and email.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id	path		string					true	"Client ID"
+//	@Param			property_id	path		string					true	"Property ID"
+//	@Param			viewing_id	path		string					true	"Viewing ID"
+//	@Param			body		body		CancelViewingRequest	true	"Reason"
+//	@Success		200			{object}	object{data=transformations.OutputViewing}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewings/{viewing_id}/cancel [patch]
+func (h *ViewingHandler) CancelViewing(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	var body CancelViewingRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	viewing, err := h.service.CancelViewing(r.Context(), services.CancelViewingInput{
+		ID:           chi.URLParam(r, "viewing_id"),
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: clientUser.ID,
+		Reason:       body.Reason,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBViewingToRest(viewing)})
+}
+
+// ConvertViewingToApplication godoc
+//
+//	@Summary		Start a tenant application from a viewing
+//	@Description	Create a tenant application for the unit from a completed viewing, prefilled with the prospect's details. The prospect is sent the application code to finish it.
+//	@Tags			Viewings
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string	true	"Client ID"
+//	@Param			property_id	path		string	true	"Property ID"
+//	@Param			viewing_id	path		string	true	"Viewing ID"
+//	@Success		201			{object}	object{data=transformations.OutputViewing}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		404			{object}	lib.HTTPError
+//	@Failure		409			{object}	lib.HTTPError	"Viewing already has an application"
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/viewings/{viewing_id}/application [post]
+func (h *ViewingHandler) ConvertViewingToApplication(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	viewing, err := h.service.ConvertViewingToApplication(r.Context(), services.ConvertViewingToApplicationInput{
+		ID:           chi.URLParam(r, "viewing_id"),
+		PropertyID:   chi.URLParam(r, "property_id"),
+		ClientUserID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBViewingToRest(viewing)})
+}
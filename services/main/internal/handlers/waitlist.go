@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/internal/transformations"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/go-chi/chi/v5"
+)
+
+type WaitlistHandler struct {
+	appCtx  pkg.AppContext
+	service services.WaitlistService
+}
+
+func NewWaitlistHandler(appCtx pkg.AppContext, service services.WaitlistService) WaitlistHandler {
+	return WaitlistHandler{appCtx: appCtx, service: service}
+}
+
+type JoinWaitlistRequest struct {
+	UnitID            *string    `json:"unit_id,omitempty"              validate:"omitempty,uuid4"                                      example:"660e8400-e29b-41d4-a716-446655440000" description:"Wait for this unit only"`
+	UnitType          *string    `json:"unit_type,omitempty"            validate:"omitempty,oneof=APARTMENT HOUSE STUDIO OFFICE RETAIL" example:"APARTMENT"                            description:"Wait for any unit of this type"`
+	FirstName         string     `json:"first_name"                     validate:"required,max=100"                                     example:"Ama"`
+	LastName          string     `json:"last_name"                      validate:"required,max=100"                                     example:"Mensah"`
+	Email             *string    `json:"email,omitempty"                validate:"omitempty,email"                                      example:"ama@example.com"`
+	Phone             string     `json:"phone"                          validate:"required,e164"                                        example:"+233241234567"`
+	DesiredMoveInFrom *time.Time `json:"desired_move_in_from,omitempty" validate:"omitempty"                                            example:"2026-11-01T00:00:00Z"`
+	DesiredMoveInTo   *time.Time `json:"desired_move_in_to,omitempty"   validate:"omitempty"                                            example:"2026-12-31T00:00:00Z"                 description:"Units coming free after this date are not offered"`
+	MaxRentFee        *int64     `json:"max_rent_fee,omitempty"         validate:"omitempty,min=0"                                      example:"300000"                               description:"Units renting for more are not offered"`
+}
+
+// JoinWaitlist godoc
+//
+//	@Summary		Join a property's waitlist
+//	@Description	Wait for any unit at a property, a unit type, or one specific unit. When a matching unit comes free, waiting prospects are invited one at a time in priority order with a link to apply.
+//	@Tags			Public
+//	@Accept			json
+//	@Produce		json
+//	@Param			property_slug	path		string				true	"Property slug"
+//	@Param			body			body		JoinWaitlistRequest	true	"Waitlist entry"
+//	@Success		201				{object}	object{data=transformations.PublicOutputWaitlistEntry}
+//	@Failure		400				{object}	lib.HTTPError
+//	@Failure		404				{object}	lib.HTTPError
+//	@Failure		409				{object}	lib.HTTPError	"Phone number is already on this waitlist"
+//	@Failure		500				{object}	string
+//	@Router			/api/v1/properties/{property_slug}/waitlist [post]
+func (h *WaitlistHandler) JoinWaitlist(w http.ResponseWriter, r *http.Request) {
+	var body JoinWaitlistRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	entry, err := h.service.Join(r.Context(), services.JoinWaitlistInput{
+		PropertySlug:      chi.URLParam(r, "property_slug"),
+		UnitID:            body.UnitID,
+		UnitType:          body.UnitType,
+		FirstName:         body.FirstName,
+		LastName:          body.LastName,
+		Email:             body.Email,
+		Phone:             body.Phone,
+		DesiredMoveInFrom: body.DesiredMoveInFrom,
+		DesiredMoveInTo:   body.DesiredMoveInTo,
+		MaxRentFee:        body.MaxRentFee,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBPublicWaitlistEntryToRest(entry)})
+}
+
+// GetWaitlistInvite godoc
+//
+//	@Summary		Get a waitlist invite
+//	@Description	The entry and unit an invite link was sent for, while the invite is still open. Pass the token as waitlist_token when creating the tenant application.
+//	@Tags			Public
+//	@Produce		json
+//	@Param			token	path		string	true	"Invite token"
+//	@Success		200		{object}	object{data=transformations.PublicOutputWaitlistEntry}
+//	@Failure		400		{object}	lib.HTTPError	"Invite has run out"
+//	@Failure		404		{object}	lib.HTTPError
+//	@Failure		500		{object}	string
+//	@Router			/api/v1/waitlist-invites/{token} [get]
+func (h *WaitlistHandler) GetWaitlistInvite(w http.ResponseWriter, r *http.Request) {
+	entry, err := h.service.GetInvite(r.Context(), chi.URLParam(r, "token"))
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBPublicWaitlistEntryToRest(entry)})
+}
+
+type ListWaitlistEntriesFilterRequest struct {
+	lib.FilterQueryInput
+	Status   *string `json:"status,omitempty"    validate:"omitempty,oneof=WAITING INVITED APPLIED EXPIRED REMOVED" example:"WAITING"`
+	UnitID   *string `json:"unit_id,omitempty"   validate:"omitempty,uuid4"                                         example:"660e8400-e29b-41d4-a716-446655440000"`
+	UnitType *string `json:"unit_type,omitempty" validate:"omitempty,oneof=APARTMENT HOUSE STUDIO OFFICE RETAIL"    example:"APARTMENT"`
+}
+
+// ListWaitlistEntries godoc
+//
+//	@Summary		List a property's waitlist
+//	@Description	Entries in the order they would be invited: highest priority first, then whoever joined first.
+//	@Tags			Waitlist
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id	path		string								true	"Client ID"
+//	@Param			property_id	path		string								true	"Property ID"
+//	@Param			q			query		ListWaitlistEntriesFilterRequest	false	"Filters"
+//	@Success		200			{object}	object{data=object{rows=[]transformations.OutputWaitlistEntry,meta=lib.HTTPReturnPaginatedMetaResponse}}
+//	@Failure		400			{object}	lib.HTTPError
+//	@Failure		401			{object}	string
+//	@Failure		500			{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/waitlist [get]
+func (h *WaitlistHandler) ListWaitlistEntries(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	filterQuery, filterErr := lib.GenerateQuery(r.URL.Query())
+	if filterErr != nil {
+		HandleErrorResponse(w, filterErr)
+		return
+	}
+
+	filters := ListWaitlistEntriesFilterRequest{
+		Status:   lib.NullOrString(r.URL.Query().Get("status")),
+		UnitID:   lib.NullOrString(r.URL.Query().Get("unit_id")),
+		UnitType: lib.NullOrString(r.URL.Query().Get("unit_type")),
+	}
+
+	if !lib.ValidateRequest(h.appCtx.Validator, filters, w) {
+		return
+	}
+
+	repoFilters := repository.ListWaitlistEntriesFilter{
+		PropertyID: &propertyID,
+		UnitID:     filters.UnitID,
+		UnitType:   filters.UnitType,
+		Status:     filters.Status,
+	}
+
+	entries, listErr := h.service.List(r.Context(), *filterQuery, repoFilters)
+	if listErr != nil {
+		HandleErrorResponse(w, listErr)
+		return
+	}
+
+	count, countErr := h.service.Count(r.Context(), *filterQuery, repoFilters)
+	if countErr != nil {
+		HandleErrorResponse(w, countErr)
+		return
+	}
+
+	rows := make([]any, len(entries))
+	for i := range entries {
+		rows[i] = transformations.DBWaitlistEntryToRest(&entries[i])
+	}
+
+	json.NewEncoder(w).Encode(lib.ReturnListResponse(filterQuery, rows, count))
+}
+
+type GetWaitlistEntryQuery struct {
+	lib.GetOneQueryInput
+}
+
+// GetWaitlistEntry godoc
+//
+//	@Summary	Get a waitlist entry
+//	@Tags		Waitlist
+//	@Security	BearerAuth
+//	@Produce	json
+//	@Param		client_id			path		string					true	"Client ID"
+//	@Param		property_id			path		string					true	"Property ID"
+//	@Param		waitlist_entry_id	path		string					true	"Waitlist entry ID"
+//	@Param		q					query		GetWaitlistEntryQuery	true	"Query parameters"
+//	@Success	200					{object}	object{data=transformations.OutputWaitlistEntry}
+//	@Failure	401					{object}	string
+//	@Failure	404					{object}	lib.HTTPError
+//	@Failure	500					{object}	string
+//	@Router		/api/v1/admin/clients/{client_id}/properties/{property_id}/waitlist/{waitlist_entry_id} [get]
+func (h *WaitlistHandler) GetWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	propertyID := chi.URLParam(r, "property_id")
+
+	entry, err := h.service.GetByID(r.Context(), repository.GetWaitlistEntryQuery{
+		ID:         chi.URLParam(r, "waitlist_entry_id"),
+		PropertyID: &propertyID,
+		Populate:   GetPopulateFields(r),
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBWaitlistEntryToRest(entry)})
+}
+
+type UpdateWaitlistEntryRequest struct {
+	Priority *int64  `json:"priority,omitempty" validate:"omitempty"          example:"10"                          description:"Higher is invited first"`
+	Notes    *string `json:"notes,omitempty"    validate:"omitempty,max=2000" example:"Prefers a ground floor unit"`
+}
+
+// UpdateWaitlistEntry godoc
+//
+//	@Summary		Update a waitlist entry
+//	@Description	Move an entry up or down the waitlist, or keep notes on it
+//	@Tags			Waitlist
+//	@Security		BearerAuth
+//	@Accept			json
+//	@Produce		json
+//	@Param			client_id			path		string						true	"Client ID"
+//	@Param			property_id			path		string						true	"Property ID"
+//	@Param			waitlist_entry_id	path		string						true	"Waitlist entry ID"
+//	@Param			body				body		UpdateWaitlistEntryRequest	true	"Changes"
+//	@Success		200					{object}	object{data=transformations.OutputWaitlistEntry}
+//	@Failure		400					{object}	lib.HTTPError
+//	@Failure		401					{object}	string
+//	@Failure		404					{object}	lib.HTTPError
+//	@Failure		500					{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/waitlist/{waitlist_entry_id} [patch]
+func (h *WaitlistHandler) UpdateWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	var body UpdateWaitlistEntryRequest
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, "Invalid JSON body", http.StatusUnprocessableEntity)
+		return
+	}
+	if !lib.ValidateRequest(h.appCtx.Validator, body, w) {
+		return
+	}
+
+	entry, err := h.service.Update(r.Context(), services.UpdateWaitlistEntryInput{
+		ID:         chi.URLParam(r, "waitlist_entry_id"),
+		PropertyID: chi.URLParam(r, "property_id"),
+		Priority:   body.Priority,
+		Notes:      body.Notes,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBWaitlistEntryToRest(entry)})
+}
+
+// RemoveWaitlistEntry godoc
+//
+//	@Summary		Remove a waitlist entry
+//	@Description	Take a waiting or invited entry off the waitlist. An invite it held passes to the next entry.
+//	@Tags			Waitlist
+//	@Security		BearerAuth
+//	@Produce		json
+//	@Param			client_id			path		string	true	"Client ID"
+//	@Param			property_id			path		string	true	"Property ID"
+//	@Param			waitlist_entry_id	path		string	true	"Waitlist entry ID"
+//	@Success		200					{object}	object{data=transformations.OutputWaitlistEntry}
+//	@Failure		400					{object}	lib.HTTPError
+//	@Failure		401					{object}	string
+//	@Failure		404					{object}	lib.HTTPError
+//	@Failure		500					{object}	string
+//	@Router			/api/v1/admin/clients/{client_id}/properties/{property_id}/waitlist/{waitlist_entry_id}/remove [patch]
+func (h *WaitlistHandler) RemoveWaitlistEntry(w http.ResponseWriter, r *http.Request) {
+	clientUser, ok := lib.ClientUserFromContext(r.Context())
+	if !ok {
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	entry, err := h.service.Remove(r.Context(), services.RemoveWaitlistEntryInput{
+		ID:          chi.URLParam(r, "waitlist_entry_id"),
+		PropertyID:  chi.URLParam(r, "property_id"),
+		RemovedByID: clientUser.ID,
+	})
+	if err != nil {
+		HandleErrorResponse(w, err)
+		return
+	}
+
+	json.NewEncoder(w).Encode(map[string]any{"data": transformations.DBWaitlistEntryToRest(entry)})
+}
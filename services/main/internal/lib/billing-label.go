@@ -18,22 +18,29 @@ import (
 //	BiAnnually → "Rent – H1 2026 (Jan–Jun)"
 //	Annually   → "Rent – 2026"
 func RentInvoiceLabel(frequency string, billingDate time.Time) string {
+	return ChargeInvoiceLabel("Rent", frequency, billingDate)
+}
+
+// ChargeInvoiceLabel is RentInvoiceLabel for any recurring charge, e.g.
+// "Parking – March 2026".
+func ChargeInvoiceLabel(name string, frequency string, billingDate time.Time) string {
 	d := billingDate
 	switch frequency {
 	case "Hourly", "HOURLY":
-		return fmt.Sprintf("Rent \u2013 %s", d.Format("2 Jan 2006, 15:04"))
+		return fmt.Sprintf("%s \u2013 %s", name, d.Format("2 Jan 2006, 15:04"))
 	case "Daily", "DAILY":
-		return fmt.Sprintf("Rent \u2013 %s", d.Format("2 Jan 2006"))
+		return fmt.Sprintf("%s \u2013 %s", name, d.Format("2 Jan 2006"))
 	case "Weekly", "WEEKLY":
-		return fmt.Sprintf("Rent \u2013 Week of %s", d.Format("2 Jan 2006"))
+		return fmt.Sprintf("%s \u2013 Week of %s", name, d.Format("2 Jan 2006"))
 	case "Monthly", "MONTHLY":
-		return fmt.Sprintf("Rent \u2013 %s", d.Format("January 2006"))
+		return fmt.Sprintf("%s \u2013 %s", name, d.Format("January 2006"))
 	case "Quarterly", "QUARTERLY":
 		quarter := (int(d.Month())-1)/3 + 1
 		qStart := time.Date(d.Year(), time.Month(((quarter-1)*3)+1), 1, 0, 0, 0, 0, d.Location())
 		qEnd := qStart.AddDate(0, 3, -1)
 		return fmt.Sprintf(
-			"Rent \u2013 Q%d %d (%s\u2013%s)",
+			"%s \u2013 Q%d %d (%s\u2013%s)",
+			name,
 			quarter,
 			d.Year(),
 			qStart.Format("Jan"),
@@ -46,11 +53,18 @@ func RentInvoiceLabel(frequency string, billingDate time.Time) string {
 		}
 		hStart := time.Date(d.Year(), time.Month(((half-1)*6)+1), 1, 0, 0, 0, 0, d.Location())
 		hEnd := hStart.AddDate(0, 6, -1)
-		return fmt.Sprintf("Rent \u2013 H%d %d (%s\u2013%s)", half, d.Year(), hStart.Format("Jan"), hEnd.Format("Jan"))
+		return fmt.Sprintf(
+			"%s \u2013 H%d %d (%s\u2013%s)",
+			name,
+			half,
+			d.Year(),
+			hStart.Format("Jan"),
+			hEnd.Format("Jan"),
+		)
 	case "Annually", "ANNUALLY":
-		return fmt.Sprintf("Rent \u2013 %d", d.Year())
+		return fmt.Sprintf("%s \u2013 %d", name, d.Year())
 	default:
-		return fmt.Sprintf("Rent \u2013 %s", d.Format("January 2006"))
+		return fmt.Sprintf("%s \u2013 %s", name, d.Format("January 2006"))
 	}
 }
 
@@ -0,0 +1,165 @@
+package documentpdf
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Node is one node of a serialized Lexical editor state, as the portal's
+// editor saves it to Document.Content. Only the fields the renderer reads are
+// kept; anything else in the JSON is ignored.
+type Node struct {
+	Type     string `json:"type"`
+	Children []Node `json:"children"`
+
+	// Text nodes (text, hashtag, mention, keyword, emoji).
+	Text string `json:"text"`
+	// Format is a style bitmask on text nodes and an alignment string on
+	// element nodes, so it is decoded per node type.
+	Format json.RawMessage `json:"format"`
+
+	// Headings.
+	Tag string `json:"tag"`
+
+	// Lists.
+	ListType string `json:"listType"`
+	Start    int    `json:"start"`
+
+	// Table cells. Non-zero marks a header cell.
+	HeaderState int `json:"headerState"`
+
+	// Images.
+	Src     string `json:"src"`
+	AltText string `json:"altText"`
+
+	// Signature placeholders.
+	Role         string  `json:"role"`
+	Label        string  `json:"label"`
+	SignedByName *string `json:"signedByName"`
+}
+
+type editorState struct {
+	Root Node `json:"root"`
+}
+
+// Text format bits, as Lexical stores them.
+const (
+	formatBold          = 1
+	formatItalic        = 1 << 1
+	formatStrikethrough = 1 << 2
+	formatUnderline     = 1 << 3
+)
+
+// ParseContent decodes Document.Content.
+func ParseContent(content []byte) (*Node, error) {
+	var state editorState
+	if err := json.Unmarshal(content, &state); err != nil {
+		return nil, fmt.Errorf("documentpdf: parse content: %w", err)
+	}
+
+	return &state.Root, nil
+}
+
+func (n *Node) textFormat() int {
+	var format int
+	if err := json.Unmarshal(n.Format, &format); err != nil {
+		return 0
+	}
+	return format
+}
+
+func (n *Node) alignment() string {
+	var align string
+	if err := json.Unmarshal(n.Format, &align); err != nil {
+		return ""
+	}
+	return align
+}
+
+// isText reports whether n carries inline text of its own.
+func (n *Node) isText() bool {
+	switch n.Type {
+	case "text", "hashtag", "mention", "keyword", "emoji", "autocomplete":
+		return true
+	}
+	return false
+}
+
+// plainText flattens n and its descendants into a single string.
+func (n *Node) plainText() string {
+	if n.isText() {
+		return n.Text
+	}
+	if n.Type == "linebreak" {
+		return "\n"
+	}
+	if n.Type == "tab" {
+		return "\t"
+	}
+
+	var text strings.Builder
+	for i := range n.Children {
+		text.WriteString(n.Children[i].plainText())
+	}
+	return text.String()
+}
+
+// MergeFieldName is the field a hashtag node stands for: the editor's merge
+// fields are written as #FieldName.
+func (n *Node) MergeFieldName() (string, bool) {
+	if n.Type != "hashtag" {
+		return "", false
+	}
+	return strings.TrimPrefix(n.Text, "#"), true
+}
+
+// ResolveMergeFields replaces every merge field that has a value in fields
+// with plain text. Fields without a value are left as they are written, the
+// same as the portal's own preview.
+func ResolveMergeFields(root *Node, fields map[string]string) {
+	if name, ok := root.MergeFieldName(); ok {
+		if value, found := fields[name]; found && value != "" {
+			root.Type = "text"
+			root.Text = value
+		}
+	}
+
+	for i := range root.Children {
+		ResolveMergeFields(&root.Children[i], fields)
+	}
+}
+
+// MergeFieldNames lists every merge field still written into the document
+// under root, in document order and without repeats.
+func MergeFieldNames(root *Node) []string {
+	seen := map[string]bool{}
+	var names []string
+
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if name, ok := n.MergeFieldName(); ok && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+		for i := range n.Children {
+			walk(&n.Children[i])
+		}
+	}
+	walk(root)
+
+	return names
+}
+
+// ImageSources lists the URLs of every image node under root, so the caller
+// can fetch them before rendering.
+func ImageSources(root *Node) []string {
+	var sources []string
+	if root.Type == "image" && root.Src != "" {
+		sources = append(sources, root.Src)
+	}
+	for i := range root.Children {
+		sources = append(sources, ImageSources(&root.Children[i])...)
+	}
+	return sources
+}
@@ -0,0 +1,552 @@
+package documentpdf
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// Image is an image the caller has already fetched.
+type Image struct {
+	Data []byte
+	// Type is "PNG" or "JPG", as DetectImageType reports it.
+	Type string
+}
+
+// Signature is one DocumentSignature, ready to be drawn.
+type Signature struct {
+	// Role is the DocumentSignature role: PROPERTY_MANAGER, TENANT, GUARANTOR,
+	// PM_WITNESS or TENANT_WITNESS.
+	Role      string
+	Name      string
+	SignedAt  time.Time
+	IPAddress string
+	UserAgent string
+	// VerificationMethod is how the signer proved who they were, as the
+	// DocumentSignature records it: LINK, OTP_SMS, OTP_EMAIL or PORTAL.
+	VerificationMethod string
+	// ContentHash and EventHash are the DocumentSignature's hashes, printed
+	// on the audit certificate. Empty for signatures made before hashing.
+	ContentHash string
+	EventHash   string
+	Image       Image
+}
+
+type RenderInput struct {
+	Title   string
+	Content []byte
+	// Fields are the merge field values, keyed by field name without the #.
+	Fields map[string]string
+	// Images are the document's inline images, keyed by source URL. An image
+	// missing here is drawn as its alt text.
+	Images     map[string]Image
+	Signatures []Signature
+}
+
+const (
+	pageMargin = 20.0
+	lineHeight = 5.5
+	bodySize   = 11.0
+	listIndent = 7.0
+
+	signatureWidth  = 50.0
+	signatureHeight = 20.0
+)
+
+var headingSizes = map[string]float64{"h1": 18, "h2": 15, "h3": 13, "h4": 12, "h5": 11, "h6": 11}
+
+// roleLabels names each signature role on the page.
+var roleLabels = map[string]string{
+	"PROPERTY_MANAGER": "Property Manager",
+	"TENANT":           "Tenant",
+	"GUARANTOR":        "Guarantor",
+	"PM_WITNESS":       "Property Manager Witness",
+	"TENANT_WITNESS":   "Tenant Witness",
+}
+
+// verificationLabels describes each verification method on the certificate.
+var verificationLabels = map[string]string{
+	"LINK":      "Signing link",
+	"OTP_SMS":   "Signing link and one-time code sent by SMS",
+	"OTP_EMAIL": "Signing link and one-time code sent by email",
+	"PORTAL":    "Signed in to the Rentloop portal",
+}
+
+type renderer struct {
+	pdf        *gofpdf.Fpdf
+	tr         func(string) string
+	images     map[string]Image
+	signatures map[string][]Signature
+	imageCount int
+}
+
+// Render draws a Lexical document as an A4 PDF.
+//
+// Signature placeholders in the document are filled with the signatures of
+// their role. Every signature is then listed again on a closing audit
+// certificate with the signer's name, role, time, IP address, user agent and
+// hashes, so one that has no placeholder of its own — a guarantor's, say —
+// still appears on the PDF.
+//
+// Text is set in the PDF core fonts, which cover Windows-1252; characters
+// outside it are dropped.
+func Render(input RenderInput) ([]byte, error) {
+	root, err := ParseContent(input.Content)
+	if err != nil {
+		return nil, err
+	}
+	ResolveMergeFields(root, input.Fields)
+
+	r := newRenderer(input.Title, input.Images)
+	for _, signature := range input.Signatures {
+		r.signatures[signature.Role] = append(r.signatures[signature.Role], signature)
+	}
+
+	r.pdf.AddPage()
+	r.setBodyFont("")
+	r.blocks(root.Children)
+
+	if len(input.Signatures) > 0 {
+		r.pdf.AddPage()
+		r.auditCertificate(input.Signatures)
+	}
+
+	return r.output()
+}
+
+type AuditCertificateInput struct {
+	// Title is the signed document's title.
+	Title      string
+	DocumentID string
+	// CompletedAt is when the last signature was made.
+	CompletedAt time.Time
+	Signatures  []Signature
+}
+
+// RenderAuditCertificate draws the audit certificate on its own: every
+// signature on a document, in the order they were made, with the evidence
+// captured when each was made.
+func RenderAuditCertificate(input AuditCertificateInput) ([]byte, error) {
+	r := newRenderer("Audit certificate - "+input.Title, nil)
+
+	r.pdf.AddPage()
+	r.pdf.SetFont("Helvetica", "", 9)
+	r.pdf.SetTextColor(100, 100, 100)
+	r.pdf.CellFormat(0, 4.5, r.tr("Document: "+input.Title), "", 1, "L", false, 0, "")
+	r.pdf.CellFormat(0, 4.5, r.tr("Document ID: "+input.DocumentID), "", 1, "L", false, 0, "")
+	completedAt := input.CompletedAt.UTC().Format("2 Jan 2006 15:04 MST")
+	r.pdf.CellFormat(0, 4.5, r.tr("Completed: "+completedAt), "", 1, "L", false, 0, "")
+	r.pdf.SetTextColor(0, 0, 0)
+	r.pdf.Ln(4)
+
+	r.auditCertificate(input.Signatures)
+
+	return r.output()
+}
+
+func newRenderer(title string, images map[string]Image) *renderer {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(true, pageMargin)
+	pdf.SetTitle(title, true)
+	pdf.SetCreator("Rentloop", false)
+	pdf.AliasNbPages("")
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pageMargin + 5)
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(120, 120, 120)
+		pdf.CellFormat(0, 5, fmt.Sprintf("Page %d of {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+
+	return &renderer{
+		pdf:        pdf,
+		tr:         pdf.UnicodeTranslatorFromDescriptor(""),
+		images:     images,
+		signatures: map[string][]Signature{},
+	}
+}
+
+func (r *renderer) output() ([]byte, error) {
+	var out bytes.Buffer
+	if outputErr := r.pdf.Output(&out); outputErr != nil {
+		return nil, fmt.Errorf("documentpdf: render: %w", outputErr)
+	}
+
+	return out.Bytes(), nil
+}
+
+// DetectImageType reports the gofpdf image type of data, or "" when it is
+// neither PNG nor JPEG.
+func DetectImageType(data []byte) string {
+	switch http.DetectContentType(data) {
+	case "image/png":
+		return "PNG"
+	case "image/jpeg":
+		return "JPG"
+	default:
+		return ""
+	}
+}
+
+func (r *renderer) setBodyFont(style string) {
+	r.pdf.SetFont("Helvetica", style, bodySize)
+}
+
+func (r *renderer) blocks(nodes []Node) {
+	for i := range nodes {
+		r.block(&nodes[i])
+	}
+}
+
+func (r *renderer) block(node *Node) {
+	switch node.Type {
+	case "paragraph":
+		r.paragraph(node, "")
+		r.pdf.Ln(2)
+	case "heading":
+		size := headingSizes[node.Tag]
+		if size == 0 {
+			size = bodySize
+		}
+		r.pdf.Ln(2)
+		r.pdf.SetFont("Helvetica", "B", size)
+		r.pdf.MultiCell(0, size*0.5, r.tr(node.plainText()), "", alignCode(node.alignment()), false)
+		r.setBodyFont("")
+		r.pdf.Ln(2)
+	case "quote":
+		left, _, _, _ := r.pdf.GetMargins()
+		r.pdf.SetLeftMargin(left + listIndent)
+		r.pdf.SetX(left + listIndent)
+		r.pdf.SetTextColor(85, 85, 85)
+		r.paragraph(node, "I")
+		r.pdf.SetTextColor(0, 0, 0)
+		r.pdf.SetLeftMargin(left)
+		r.pdf.Ln(2)
+	case "code":
+		r.pdf.SetFont("Courier", "", bodySize-1)
+		r.pdf.MultiCell(0, lineHeight, r.tr(node.plainText()), "", "L", false)
+		r.setBodyFont("")
+		r.pdf.Ln(2)
+	case "list":
+		r.list(node, 0)
+		r.pdf.Ln(2)
+	case "table":
+		r.table(node)
+		r.pdf.Ln(2)
+	case "horizontalrule":
+		left, _, right, _ := r.pdf.GetMargins()
+		width, _ := r.pdf.GetPageSize()
+		y := r.pdf.GetY() + 2
+		r.pdf.SetDrawColor(200, 200, 200)
+		r.pdf.Line(left, y, width-right, y)
+		r.pdf.SetDrawColor(0, 0, 0)
+		r.pdf.SetY(y + 3)
+	case "image":
+		r.image(node)
+	case "signature":
+		r.signaturePlaceholder(node)
+	default:
+		// Layout containers, columns and anything else that only groups
+		// blocks: draw what is inside, top to bottom.
+		if len(node.Children) > 0 && !node.Children[0].isText() && node.Children[0].Type != "linebreak" {
+			r.blocks(node.Children)
+			return
+		}
+		if len(node.Children) > 0 {
+			r.paragraph(node, "")
+			r.pdf.Ln(2)
+		}
+	}
+}
+
+// paragraph writes inline children in their own styles. Write only flows
+// left-aligned, so centred, right-aligned and justified paragraphs are set
+// as one block in the paragraph's base style instead.
+func (r *renderer) paragraph(node *Node, baseStyle string) {
+	align := node.alignment()
+	if align == "center" || align == "right" || align == "justify" {
+		r.setBodyFont(baseStyle)
+		r.pdf.MultiCell(0, lineHeight, r.tr(node.plainText()), "", alignCode(align), false)
+		r.setBodyFont("")
+		return
+	}
+
+	r.inline(node.Children, baseStyle)
+	r.setBodyFont("")
+	r.pdf.Ln(lineHeight)
+}
+
+func (r *renderer) inline(nodes []Node, baseStyle string) {
+	for i := range nodes {
+		node := &nodes[i]
+		switch {
+		case node.isText():
+			r.setBodyFont(baseStyle + fontStyle(node.textFormat()))
+			r.pdf.Write(lineHeight, r.tr(node.Text))
+		case node.Type == "linebreak":
+			r.pdf.Ln(lineHeight)
+		case node.Type == "tab":
+			r.pdf.Write(lineHeight, "    ")
+		case node.Type == "signature":
+			r.pdf.Ln(lineHeight)
+			r.signaturePlaceholder(node)
+		default:
+			// Links and other inline wrappers.
+			r.inline(node.Children, baseStyle)
+		}
+	}
+}
+
+func (r *renderer) list(node *Node, depth int) {
+	left, _, _, _ := r.pdf.GetMargins()
+	number := node.Start
+	if number == 0 {
+		number = 1
+	}
+
+	for i := range node.Children {
+		item := &node.Children[i]
+
+		// A nested list is a list item holding only the inner list.
+		if len(item.Children) == 1 && item.Children[0].Type == "list" {
+			r.list(&item.Children[0], depth+1)
+			continue
+		}
+
+		indent := left + float64(depth+1)*listIndent
+		marker := "-"
+		if node.ListType == "number" {
+			marker = fmt.Sprintf("%d.", number)
+			number++
+		}
+
+		r.pdf.SetX(indent - listIndent + 1)
+		r.setBodyFont("")
+		r.pdf.CellFormat(listIndent-1, lineHeight, marker, "", 0, "L", false, 0, "")
+		r.pdf.SetLeftMargin(indent)
+		r.inline(item.Children, "")
+		r.pdf.SetLeftMargin(left)
+		r.setBodyFont("")
+		r.pdf.Ln(lineHeight)
+	}
+}
+
+// table draws a table with equal-width columns. Each row is as tall as its
+// tallest cell, and moves to a new page whole rather than splitting.
+func (r *renderer) table(node *Node) {
+	left, _, right, bottom := r.pdf.GetMargins()
+	pageWidth, pageHeight := r.pdf.GetPageSize()
+
+	columns := 0
+	for i := range node.Children {
+		columns = max(columns, len(node.Children[i].Children))
+	}
+	if columns == 0 {
+		return
+	}
+	columnWidth := (pageWidth - left - right) / float64(columns)
+
+	for i := range node.Children {
+		row := &node.Children[i]
+
+		height := lineHeight
+		for j := range row.Children {
+			lines := r.pdf.SplitText(r.tr(row.Children[j].plainText()), columnWidth-2)
+			height = max(height, float64(len(lines))*lineHeight)
+		}
+		height += 2
+
+		if r.pdf.GetY()+height > pageHeight-bottom {
+			r.pdf.AddPage()
+		}
+
+		y := r.pdf.GetY()
+		for j := range row.Children {
+			cell := &row.Children[j]
+			x := left + float64(j)*columnWidth
+			style := ""
+			if cell.HeaderState > 0 {
+				style = "B"
+			}
+			r.pdf.Rect(x, y, columnWidth, height, "D")
+			r.pdf.SetXY(x+1, y+1)
+			r.setBodyFont(style)
+			r.pdf.MultiCell(columnWidth-2, lineHeight, r.tr(cell.plainText()), "", "L", false)
+		}
+		r.setBodyFont("")
+		r.pdf.SetXY(left, y+height)
+	}
+}
+
+func (r *renderer) image(node *Node) {
+	image, ok := r.images[node.Src]
+	if !ok || image.Type == "" {
+		if node.AltText != "" {
+			r.setBodyFont("I")
+			r.pdf.MultiCell(0, lineHeight, r.tr("["+node.AltText+"]"), "", "L", false)
+			r.setBodyFont("")
+		}
+		return
+	}
+
+	left, _, right, _ := r.pdf.GetMargins()
+	pageWidth, _ := r.pdf.GetPageSize()
+	info := r.register(image)
+	if info == nil {
+		return
+	}
+
+	// Images are sized at 96 dpi, as the editor shows them, but never
+	// wider than the page.
+	width := min(info.Width()*25.4/96, pageWidth-left-right)
+	r.pdf.ImageOptions(
+		r.imageName(), left, r.pdf.GetY(), width, 0, true,
+		gofpdf.ImageOptions{ImageType: image.Type}, 0, "",
+	)
+	r.pdf.Ln(2)
+}
+
+// signaturePlaceholder draws the signatures collected for the placeholder's
+// role, or a blank line to sign on when there are none yet.
+func (r *renderer) signaturePlaceholder(node *Node) {
+	role := strings.ToUpper(node.Role)
+	label := node.Label
+	if label == "" {
+		label = roleLabels[role]
+	}
+
+	signatures := r.signatures[role]
+	if len(signatures) == 0 {
+		r.pdf.Ln(signatureHeight - lineHeight)
+		left, _, _, _ := r.pdf.GetMargins()
+		y := r.pdf.GetY()
+		r.pdf.Line(left, y, left+signatureWidth, y)
+		r.pdf.SetFont("Helvetica", "", 9)
+		r.pdf.CellFormat(signatureWidth, lineHeight, r.tr(label), "", 1, "L", false, 0, "")
+		r.setBodyFont("")
+		r.pdf.Ln(2)
+		return
+	}
+
+	for _, signature := range signatures {
+		r.drawSignature(signature, label)
+	}
+}
+
+func (r *renderer) drawSignature(signature Signature, label string) {
+	_, pageHeight := r.pdf.GetPageSize()
+	_, _, _, bottom := r.pdf.GetMargins()
+	if r.pdf.GetY()+signatureHeight+3*lineHeight > pageHeight-bottom {
+		r.pdf.AddPage()
+	}
+
+	left, _, _, _ := r.pdf.GetMargins()
+	y := r.pdf.GetY()
+	if info := r.register(signature.Image); info != nil {
+		r.pdf.ImageOptions(
+			r.imageName(), left, y, 0, signatureHeight, false,
+			gofpdf.ImageOptions{ImageType: signature.Image.Type}, 0, "",
+		)
+	}
+	r.pdf.SetY(y + signatureHeight)
+	r.pdf.Line(left, r.pdf.GetY(), left+signatureWidth, r.pdf.GetY())
+
+	r.pdf.SetFont("Helvetica", "B", 9)
+	r.pdf.CellFormat(0, 4.5, r.tr(signature.Name), "", 1, "L", false, 0, "")
+	r.pdf.SetFont("Helvetica", "", 9)
+	signedAt := signature.SignedAt.UTC().Format("2 Jan 2006 15:04 MST")
+	r.pdf.CellFormat(0, 4.5, r.tr(label+" - "+signedAt), "", 1, "L", false, 0, "")
+	r.setBodyFont("")
+	r.pdf.Ln(3)
+}
+
+// auditCertificate lists every signature with the evidence captured when it
+// was made, starting on the current page.
+func (r *renderer) auditCertificate(signatures []Signature) {
+	r.pdf.SetFont("Helvetica", "B", 14)
+	r.pdf.CellFormat(0, 8, "Audit certificate", "", 1, "L", false, 0, "")
+	r.setBodyFont("")
+	r.pdf.Ln(2)
+
+	for _, signature := range signatures {
+		r.drawSignature(signature, roleLabels[signature.Role])
+		r.pdf.SetFont("Helvetica", "", 8)
+		r.pdf.SetTextColor(100, 100, 100)
+		r.evidenceLine("IP address", signature.IPAddress)
+		r.evidenceLine("User agent", signature.UserAgent)
+		r.evidenceLine("Verified by", verificationLabels[signature.VerificationMethod])
+		r.evidenceLine("Content hash (SHA-256)", signature.ContentHash)
+		r.evidenceLine("Event hash (SHA-256)", signature.EventHash)
+		r.pdf.SetTextColor(0, 0, 0)
+		r.setBodyFont("")
+		r.pdf.Ln(4)
+	}
+}
+
+// evidenceLine prints one labelled value under a signature, wrapping long
+// ones, and skips values that were not captured.
+func (r *renderer) evidenceLine(label, value string) {
+	if value == "" {
+		return
+	}
+	r.pdf.MultiCell(0, 4, r.tr(label+": "+value), "", "L", false)
+}
+
+// register adds image to the PDF under a fresh name, returning nil when the
+// bytes are not an image gofpdf can read.
+func (r *renderer) register(image Image) *gofpdf.ImageInfoType {
+	if image.Type == "" || len(image.Data) == 0 {
+		return nil
+	}
+
+	r.imageCount++
+	info := r.pdf.RegisterImageOptionsReader(
+		r.imageName(), gofpdf.ImageOptions{ImageType: image.Type}, bytes.NewReader(image.Data),
+	)
+	if r.pdf.Err() {
+		// A broken image should not sink the whole document.
+		r.pdf.ClearError()
+		return nil
+	}
+	return info
+}
+
+func (r *renderer) imageName() string {
+	return fmt.Sprintf("image-%d", r.imageCount)
+}
+
+func fontStyle(format int) string {
+	var style strings.Builder
+	if format&formatBold != 0 {
+		style.WriteString("B")
+	}
+	if format&formatItalic != 0 {
+		style.WriteString("I")
+	}
+	if format&formatUnderline != 0 {
+		style.WriteString("U")
+	}
+	if format&formatStrikethrough != 0 {
+		style.WriteString("S")
+	}
+	return style.String()
+}
+
+func alignCode(align string) string {
+	switch align {
+	case "center":
+		return "C"
+	case "right", "end":
+		return "R"
+	case "justify":
+		return "J"
+	default:
+		return "L"
+	}
+}
@@ -0,0 +1,123 @@
+package documentpdf
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+)
+
+const leaseContent = `{"root":{"type":"root","children":[
+	{"type":"heading","tag":"h1","format":"center","children":[{"type":"text","text":"Tenancy Agreement","format":0}]},
+	{"type":"paragraph","format":"","children":[
+		{"type":"text","text":"This agreement is between ","format":0},
+		{"type":"hashtag","text":"#LandlordName","format":0},
+		{"type":"text","text":" and ","format":0},
+		{"type":"hashtag","text":"#TenantName","format":1}
+	]},
+	{"type":"list","listType":"number","start":1,"children":[
+		{"type":"listitem","children":[{"type":"text","text":"Rent is due monthly.","format":0}]},
+		{"type":"listitem","children":[{"type":"list","listType":"bullet","children":[
+			{"type":"listitem","children":[{"type":"text","text":"Late fees apply.","format":2}]}
+		]}]}
+	]},
+	{"type":"table","children":[
+		{"type":"tablerow","children":[
+			{"type":"tablecell","headerState":1,"children":[
+				{"type":"paragraph","children":[{"type":"text","text":"Item"}]}
+			]},
+			{"type":"tablecell","headerState":1,"children":[
+				{"type":"paragraph","children":[{"type":"text","text":"Amount"}]}
+			]}
+		]},
+		{"type":"tablerow","children":[
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"text","text":"Rent"}]}]},
+			{"type":"tablecell","children":[{"type":"paragraph","children":[{"type":"hashtag","text":"#RentAmount"}]}]}
+		]}
+	]},
+	{"type":"horizontalrule"},
+	{"type":"signature","role":"property_manager","label":"Landlord","signatureUrl":null},
+	{"type":"signature","role":"tenant","label":"Tenant","signatureUrl":null}
+]}}`
+
+// A merge field with a value becomes plain text; one without stays as
+// written, so a missing value is visible on the page rather than blank.
+func TestResolveMergeFields(t *testing.T) {
+	root, err := ParseContent([]byte(leaseContent))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ResolveMergeFields(root, map[string]string{"TenantName": "Ama Mensah", "LandlordName": ""})
+
+	paragraph := root.Children[1]
+	if got := paragraph.plainText(); got != "This agreement is between #LandlordName and Ama Mensah" {
+		t.Errorf("got %q", got)
+	}
+	if paragraph.Children[3].Type != "text" || paragraph.Children[1].Type != "hashtag" {
+		t.Errorf("got node types %q and %q", paragraph.Children[3].Type, paragraph.Children[1].Type)
+	}
+}
+
+// Signatures were missing from the portal-generated PDF. Every signature is
+// embedded, including a guarantor's, which has no placeholder in the document
+// and only appears on the audit certificate.
+func TestRenderEmbedsSignatures(t *testing.T) {
+	signature := Signature{
+		Role:      "TENANT",
+		Name:      "Ama Mensah",
+		SignedAt:  time.Date(2026, 10, 1, 9, 30, 0, 0, time.UTC),
+		IPAddress: "197.251.1.10",
+		Image:     Image{Data: signaturePNG(t), Type: "PNG"},
+	}
+
+	input := RenderInput{
+		Title:   "Tenancy Agreement",
+		Content: []byte(leaseContent),
+		Fields:  map[string]string{"TenantName": "Ama Mensah", "RentAmount": "4,500.00"},
+	}
+
+	unsigned, err := Render(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.HasPrefix(unsigned, []byte("%PDF-")) {
+		t.Fatalf("output is not a PDF")
+	}
+
+	hasImage := func(pdf []byte) bool { return bytes.Contains(pdf, []byte("/Subtype /Image")) }
+	if hasImage(unsigned) {
+		t.Errorf("unsigned document has an image")
+	}
+
+	for _, role := range []string{"TENANT", "GUARANTOR"} {
+		signature.Role = role
+		input.Signatures = []Signature{signature}
+
+		signed, renderErr := Render(input)
+		if renderErr != nil {
+			t.Fatal(renderErr)
+		}
+		if !hasImage(signed) {
+			t.Errorf("%s signature is not embedded", role)
+		}
+	}
+}
+
+func signaturePNG(t *testing.T) []byte {
+	t.Helper()
+
+	img := image.NewNRGBA(image.Rect(0, 0, 120, 40))
+	for x := 10; x < 110; x++ {
+		img.Set(x, 20, color.NRGBA{A: 255})
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
@@ -128,11 +128,16 @@ type LeaseTerminatedData struct {
 
 // LeaseMoveOutReminderData is shared by every reminder threshold (30/14/7/1
 // days out) — only DaysRemaining and MoveOutDate change between sends.
+//
+// HoldsOver says the lease will roll over month to month at HoldoverRent
+// (already formatted with its currency) instead of ending.
 type LeaseMoveOutReminderData struct {
 	TenantName    string
 	UnitName      string
 	DaysRemaining int
 	MoveOutDate   string
+	HoldsOver     bool
+	HoldoverRent  string
 }
 
 // LeaseMoveOutReminderManagerData is the manager-facing counterpart, includes
@@ -144,6 +149,19 @@ type LeaseMoveOutReminderManagerData struct {
 	DaysRemaining       int
 	MoveOutDate         string
 	MoveOutReportStatus string
+	HoldsOver           bool
+	HoldoverRent        string
+}
+
+// LeaseNoticeToVacateManagerData tells the manager a tenant has given notice.
+// EarlyTerminationFee is formatted with its currency, or empty when none is due.
+type LeaseNoticeToVacateManagerData struct {
+	ManagerName         string
+	TenantName          string
+	UnitName            string
+	IntendedMoveOutDate string
+	Reason              string
+	EarlyTerminationFee string
 }
 
 type LeaseCompletedData struct {
@@ -197,6 +215,20 @@ type InvoiceReminderData struct {
 	DueDate     string
 }
 
+// GuarantorEscalationData tells a guarantor that the rent they guarantee is
+// overdue. LiabilityCap is empty for an unlimited guarantee.
+type GuarantorEscalationData struct {
+	GuarantorName string
+	TenantName    string
+	InvoiceCode   string
+	UnitName      string
+	Currency      string
+	Amount        string
+	DueDate       string
+	DaysOverdue   int
+	LiabilityCap  string
+}
+
 // ─── Document Signing ─────────────────────────────────────────────────────────
 
 type SigningTokenData struct {
@@ -205,6 +237,21 @@ type SigningTokenData struct {
 	ExpiresAt  string
 }
 
+// SigningTurnData tells the manager an envelope has reached their signature.
+type SigningTurnData struct {
+	ManagerName   string
+	DocumentTitle string
+	IsReminder    bool
+}
+
+// SigningEnvelopeCancelledData tells the manager an envelope was cancelled
+// before everyone had signed.
+type SigningEnvelopeCancelledData struct {
+	ManagerName   string
+	DocumentTitle string
+	Reason        string
+}
+
 // ─── Announcement ─────────────────────────────────────────────────────────────
 
 type AnnouncementData struct {
@@ -268,4 +315,123 @@ type BookingCancelledData struct {
 	CheckOutDate       string
 	TrackingCode       string
 	CancellationReason string
+	RefundAmount       string // empty when nothing is refunded
+	Currency           string
+}
+
+// BookingModifiedData tells a guest their stay has changed, and what the
+// change costs them or gives them back.
+type BookingModifiedData struct {
+	GuestName        string
+	UnitName         string
+	CheckInDate      string
+	CheckOutDate     string
+	TrackingCode     string
+	AmountDue        string // empty when the change costs nothing more
+	BalanceReduction string // empty when nothing comes off what is still owed
+	RefundAmount     string // empty when nothing is refunded
+	Currency         string
+}
+
+// BookingHoldReminderData reminds a guest that a booking waiting on payment
+// is cancelled at ExpiresAt if it is not paid.
+type BookingHoldReminderData struct {
+	GuestName    string
+	UnitName     string
+	ExpiresAt    string
+	Amount       string
+	Currency     string
+	TrackingCode string
+}
+
+// WaitlistInvitedData invites a waitlisted prospect to apply for a unit
+// before the invite runs out at ExpiresAt.
+type WaitlistInvitedData struct {
+	FirstName    string
+	UnitID       string
+	UnitName     string
+	PropertyName string
+	InviteToken  string
+	ExpiresAt    string
+}
+
+// WaitlistVacancyData tells a waitlisted prospect a unit is expected to come
+// free on AvailableOn.
+type WaitlistVacancyData struct {
+	FirstName    string
+	UnitName     string
+	PropertyName string
+	AvailableOn  string
+}
+
+// ViewingBookedData confirms a prospect's viewing of a unit.
+type ViewingBookedData struct {
+	FirstName       string
+	UnitName        string
+	PropertyName    string
+	PropertyAddress string
+	ScheduledAt     string
+	ViewingCode     string
+}
+
+// ViewingReminderData reminds a prospect of a viewing coming up.
+type ViewingReminderData struct {
+	FirstName       string
+	UnitName        string
+	PropertyName    string
+	PropertyAddress string
+	ScheduledAt     string
+	ViewingCode     string
+}
+
+// ViewingCancelledData tells a prospect the property called off their
+// viewing.
+type ViewingCancelledData struct {
+	FirstName    string
+	UnitName     string
+	PropertyName string
+	ScheduledAt  string
+	Reason       string
+}
+
+// ViewingStaffBookedData tells a staff member a prospect booked a viewing in
+// one of their slots.
+type ViewingStaffBookedData struct {
+	StaffName     string
+	ProspectName  string
+	ProspectPhone string
+	UnitName      string
+	PropertyName  string
+	ScheduledAt   string
+}
+
+// BookingCalendarConflictData tells the manager that a calendar subscription
+// imported events overlapping confirmed bookings.
+type BookingCalendarConflictData struct {
+	ManagerName  string
+	UnitName     string
+	CalendarName string
+	Conflicts    []BookingCalendarConflict
+}
+
+type BookingCalendarConflict struct {
+	EventDates   string
+	EventSummary string
+	BookingCode  string
+	BookingDates string
+}
+
+// TurnoverOverdueData tells a property manager which turnover tasks were not
+// done by the time they were due.
+type TurnoverOverdueData struct {
+	ManagerName  string
+	PropertyName string
+	Tasks        []TurnoverOverdueTask
+}
+
+type TurnoverOverdueTask struct {
+	UnitName     string
+	Type         string
+	DueAt        string
+	AssigneeName string
 }
@@ -0,0 +1,294 @@
+// Package ical reads and writes the small part of iCalendar (RFC 5545) that
+// channel calendars use: VEVENTs with a UID, a start, an end and a summary.
+// Airbnb, Booking.com and most other channels publish availability this way.
+package ical
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Event is one VEVENT. An all-day event starts and ends at midnight UTC on
+// its dates, and like every event its End is exclusive: a stay from the 1st
+// to the 3rd ends on the 3rd.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Status      string // TENTATIVE | CONFIRMED | CANCELLED, or empty
+	Stamp       time.Time
+}
+
+// Calendar is a VCALENDAR to render.
+type Calendar struct {
+	ProductID string
+	Name      string
+	Events    []Event
+}
+
+var ErrNotACalendar = errors.New("ical: document is not a VCALENDAR")
+
+const (
+	dateLayout        = "20060102"
+	dateTimeLayout    = "20060102T150405"
+	dateTimeUTCLayout = "20060102T150405Z"
+)
+
+// Parse reads every VEVENT in r. Events without a UID or a start cannot be
+// tracked from one fetch to the next and are skipped; anything else it does
+// not understand is ignored.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		events     []Event
+		current    *Event
+		duration   string
+		inCalendar bool
+		depth      int // components nested inside the VEVENT, such as VALARM
+	)
+	for _, line := range lines {
+		name, params, value := splitContentLine(line)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VCALENDAR"):
+			inCalendar = true
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT") && current == nil:
+			current = &Event{}
+			duration = ""
+		case name == "BEGIN" && current != nil:
+			depth++
+		case name == "END" && current != nil && depth > 0:
+			depth--
+		case name == "END" && strings.EqualFold(value, "VEVENT") && current != nil:
+			if current.End.IsZero() {
+				current.End = endFromDuration(current, duration)
+			}
+			if current.UID != "" && !current.Start.IsZero() {
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil || depth > 0:
+			continue
+		case name == "UID":
+			current.UID = value
+		case name == "SUMMARY":
+			current.Summary = unescapeText(value)
+		case name == "DESCRIPTION":
+			current.Description = unescapeText(value)
+		case name == "STATUS":
+			current.Status = strings.ToUpper(value)
+		case name == "DTSTART":
+			current.Start, current.AllDay = parseDateValue(value, params)
+		case name == "DTEND":
+			current.End, _ = parseDateValue(value, params)
+		case name == "DTSTAMP":
+			current.Stamp, _ = parseDateValue(value, params)
+		case name == "DURATION":
+			duration = value
+		}
+	}
+
+	if !inCalendar {
+		return nil, ErrNotACalendar
+	}
+
+	return events, nil
+}
+
+// unfold joins the continuation lines of r, which start with a space or a
+// tab, onto the line before them.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []string
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("ical: read: %w", err)
+	}
+
+	return lines, nil
+}
+
+// splitContentLine splits `NAME;PARAM=x;PARAM="y:z":value`. A colon inside
+// a quoted parameter value does not end the parameters.
+func splitContentLine(line string) (string, map[string]string, string) {
+	inQuotes := false
+	colon := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		}
+		if c == ':' && !inQuotes {
+			colon = i
+			break
+		}
+	}
+	if colon < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+
+	parts := strings.Split(line[:colon], ";")
+	params := make(map[string]string, len(parts)-1)
+	for _, param := range parts[1:] {
+		key, value, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+	}
+
+	return strings.ToUpper(parts[0]), params, line[colon+1:]
+}
+
+// parseDateValue reads a DATE or DATE-TIME. A DATE-TIME in a zone we cannot
+// load, or with no zone at all, is read as UTC.
+func parseDateValue(value string, params map[string]string) (time.Time, bool) {
+	if params["VALUE"] == "DATE" || len(value) == len(dateLayout) {
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return date, true
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, _ := time.Parse(dateTimeUTCLayout, value)
+		return t, false
+	}
+
+	location := time.UTC
+	if tzid := params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, _ := time.ParseInLocation(dateTimeLayout, value, location)
+
+	return t, false
+}
+
+var durationPattern = regexp.MustCompile(`^P(?:(\d+)W)?(?:(\d+)D)?(?:T(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?)?$`)
+
+// endFromDuration is the end of an event that gives a DURATION instead of a
+// DTEND. With neither, an all-day event lasts its one day and any other
+// event ends as it starts.
+func endFromDuration(event *Event, duration string) time.Time {
+	match := durationPattern.FindStringSubmatch(strings.TrimPrefix(duration, "+"))
+	if match == nil {
+		if event.AllDay {
+			return event.Start.AddDate(0, 0, 1)
+		}
+		return event.Start
+	}
+
+	part := func(i int) int {
+		n, _ := strconv.Atoi(match[i])
+		return n
+	}
+
+	return event.Start.
+		AddDate(0, 0, part(1)*7+part(2)).
+		Add(time.Duration(part(3))*time.Hour + time.Duration(part(4))*time.Minute + time.Duration(part(5))*time.Second)
+}
+
+var textUnescaper = strings.NewReplacer(`\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescapeText(value string) string {
+	return textUnescaper.Replace(value)
+}
+
+var textEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`, "\r", "")
+
+// Render writes c as an iCalendar document with CRLF line endings and long
+// lines folded at 75 octets, as RFC 5545 asks.
+func Render(w io.Writer, c Calendar) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + c.ProductID,
+		"CALSCALE:GREGORIAN",
+		"METHOD:PUBLISH",
+	}
+	if c.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+textEscaper.Replace(c.Name))
+	}
+
+	for _, event := range c.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+event.UID,
+			"DTSTAMP:"+event.Stamp.UTC().Format(dateTimeUTCLayout),
+		)
+		if event.AllDay {
+			lines = append(lines,
+				"DTSTART;VALUE=DATE:"+event.Start.Format(dateLayout),
+				"DTEND;VALUE=DATE:"+event.End.Format(dateLayout),
+			)
+		} else {
+			lines = append(lines,
+				"DTSTART:"+event.Start.UTC().Format(dateTimeUTCLayout),
+				"DTEND:"+event.End.UTC().Format(dateTimeUTCLayout),
+			)
+		}
+		if event.Summary != "" {
+			lines = append(lines, "SUMMARY:"+textEscaper.Replace(event.Summary))
+		}
+		if event.Description != "" {
+			lines = append(lines, "DESCRIPTION:"+textEscaper.Replace(event.Description))
+		}
+		if event.Status != "" {
+			lines = append(lines, "STATUS:"+event.Status)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, fold(line)); err != nil {
+			return fmt.Errorf("ical: write: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// fold breaks line into CRLF-terminated pieces of at most 75 octets, each
+// continuation starting with a space, without splitting a UTF-8 character.
+func fold(line string) string {
+	var b strings.Builder
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space counts toward the next line's 75
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+
+	return b.String()
+}
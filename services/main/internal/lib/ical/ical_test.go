@@ -0,0 +1,153 @@
+package ical
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Channel feeds fold long lines, mix all-day and timed events, and sometimes
+// give a DURATION instead of an end. Events without a UID cannot be matched
+// on the next sync, so they are dropped; alarms inside an event are not
+// mistaken for the event's own properties.
+func TestParse(t *testing.T) {
+	const feed = "BEGIN:VCALENDAR\r\n" +
+		"VERSION:2.0\r\n" +
+		"PRODID:-//Airbnb Inc//Hosting Calendar 1.0//EN\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTAMP:20261019T080000Z\r\n" +
+		"DTSTART;VALUE=DATE:20261101\r\n" +
+		"DTEND;VALUE=DATE:20261104\r\n" +
+		"SUMMARY:Reserved\r\n" +
+		"UID:1418fb94e984-2a1b8d4e@airbnb.com\r\n" +
+		"DESCRIPTION:Reservation URL: https://www.airbnb.com/hosting/reservations/\r\n" +
+		" details/HMABCDEF\\nPhone Number (Last 4 Digits): 1234\r\n" +
+		"BEGIN:VALARM\r\n" +
+		"SUMMARY:Reminder\r\n" +
+		"END:VALARM\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:bdc-77\r\n" +
+		"DTSTART;TZID=Africa/Accra:20261110T140000\r\n" +
+		"DTEND:20261112T110000Z\r\n" +
+		"SUMMARY:CLOSED - Not available\\, owner stay\r\n" +
+		"STATUS:confirmed\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:vrbo-9\r\n" +
+		"DTSTART;VALUE=DATE:20261120\r\n" +
+		"DURATION:P1W\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"UID:single-day\r\n" +
+		"DTSTART;VALUE=DATE:20261201\r\n" +
+		"END:VEVENT\r\n" +
+		"BEGIN:VEVENT\r\n" +
+		"DTSTART;VALUE=DATE:20261205\r\n" +
+		"DTEND;VALUE=DATE:20261206\r\n" +
+		"END:VEVENT\r\n" +
+		"END:VCALENDAR\r\n"
+
+	events, err := Parse(strings.NewReader(feed))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	want := []Event{
+		{
+			UID:     "1418fb94e984-2a1b8d4e@airbnb.com",
+			Summary: "Reserved",
+			Description: "Reservation URL: https://www.airbnb.com/hosting/reservations/details/HMABCDEF\n" +
+				"Phone Number (Last 4 Digits): 1234",
+			Start:  date("2026-11-01"),
+			End:    date("2026-11-04"),
+			AllDay: true,
+			Stamp:  time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			UID:     "bdc-77",
+			Summary: "CLOSED - Not available, owner stay",
+			Start:   time.Date(2026, 11, 10, 14, 0, 0, 0, time.UTC), // Accra is UTC+0
+			End:     time.Date(2026, 11, 12, 11, 0, 0, 0, time.UTC),
+			Status:  "CONFIRMED",
+		},
+		{UID: "vrbo-9", Start: date("2026-11-20"), End: date("2026-11-27"), AllDay: true},
+		{UID: "single-day", Start: date("2026-12-01"), End: date("2026-12-02"), AllDay: true},
+	}
+
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i := range want {
+		got := events[i]
+		if got.UID != want[i].UID || got.Summary != want[i].Summary || got.Description != want[i].Description ||
+			!got.Start.Equal(want[i].Start) || !got.End.Equal(want[i].End) || got.AllDay != want[i].AllDay ||
+			got.Status != want[i].Status || !got.Stamp.Equal(want[i].Stamp) {
+			t.Errorf("event %d = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+// A URL that returns an HTML error page or JSON is not a calendar, and must
+// not be read as an empty one: that would clear every imported block.
+func TestParseRejectsNonCalendar(t *testing.T) {
+	_, err := Parse(strings.NewReader("<html><body>Not found</body></html>"))
+	if !errors.Is(err, ErrNotACalendar) {
+		t.Fatalf("err = %v, want ErrNotACalendar", err)
+	}
+}
+
+// What we render, a channel must be able to read back: lines end in CRLF,
+// none is longer than 75 octets, and text survives escaping.
+func TestRenderRoundTrip(t *testing.T) {
+	calendar := Calendar{
+		ProductID: "-//Rentloop//Unit Calendar//EN",
+		Name:      "Unit 4B, East Legon",
+		Events: []Event{
+			{
+				UID:     "4fce5dc8-8114-4ab2-a94b-b4536c27f43b@rentloop",
+				Summary: "Reserved; " + strings.Repeat("long summary, ", 10),
+				Start:   time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC),
+				End:     time.Date(2026, 11, 4, 0, 0, 0, 0, time.UTC),
+				AllDay:  true,
+				Stamp:   time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+
+	var out bytes.Buffer
+	if err := Render(&out, calendar); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	for _, line := range strings.SplitAfter(out.String(), "\r\n") {
+		if line == "" {
+			continue
+		}
+		if !strings.HasSuffix(line, "\r\n") {
+			t.Fatalf("line %q does not end in CRLF", line)
+		}
+		if len(line)-2 > 75 {
+			t.Errorf("line %q is %d octets", line, len(line)-2)
+		}
+	}
+
+	events, err := Parse(&out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	got, want := events[0], calendar.Events[0]
+	if got.UID != want.UID || got.Summary != want.Summary || !got.Start.Equal(want.Start) ||
+		!got.End.Equal(want.End) || !got.AllDay {
+		t.Errorf("round trip = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,32 @@
+package lib
+
+import (
+	"fmt"
+	"net"
+	"net/netip"
+	"syscall"
+)
+
+// RefusePrivateAddresses is a net.Dialer Control that only lets connections
+// through to public addresses. Clients that fetch URLs someone else supplied
+// dial with it, so the URL cannot reach a host on our own network. It runs
+// after DNS, which also covers redirects and names that resolve somewhere
+// private.
+func RefusePrivateAddresses(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("%s is not a public address", ip)
+	}
+
+	return nil
+}
@@ -41,13 +41,20 @@ const (
 	LEASE_TERMINATED_SUBJECT       = "Your Rentloop Lease Has Been Terminated"
 	LEASE_MOVEOUT_REMINDER_SUBJECT = "Your Lease Move-Out Date Is Approaching"
 	LEASE_COMPLETED_SUBJECT        = "Your Rentloop Lease Has Ended"
+	LEASE_RENEWAL_OFFER_SUBJECT    = "You Have a Lease Renewal Offer"
+	LEASE_HOLDOVER_SUBJECT         = "Your Lease Has Moved to Month-to-Month"
 )
 
 const (
 	PM_LEASE_MOVEOUT_REMINDER_SUBJECT = "Upcoming Lease Move-Out"
 	PM_LEASE_COMPLETED_SUBJECT        = "Lease Completed - Unit Released"
+	PM_LEASE_NOTICE_TO_VACATE_SUBJECT = "Tenant Notice to Vacate"
 )
 
+const PM_BOOKING_CALENDAR_CONFLICT_SUBJECT = "Possible Double Booking From a Synced Calendar"
+
+const PM_TURNOVER_OVERDUE_SUBJECT = "Turnover Tasks Are Overdue"
+
 const RENT_INVOICE_GENERATED_SUBJECT = "Your Rent Invoice is Ready"
 
 const (
@@ -56,6 +63,8 @@ const (
 	INVOICE_OVERDUE_3D_SUBJECT  = "Payment reminder: your rent is 3 days overdue"
 	INVOICE_OVERDUE_7D_SUBJECT  = "Urgent: your rent is 7 days overdue"
 	INVOICE_OVERDUE_14D_SUBJECT = "Final notice: your rent is 14 days overdue"
+
+	INVOICE_GUARANTOR_ESCALATION_SUBJECT = "Rent you guarantee is overdue"
 )
 
 const (
@@ -67,6 +76,10 @@ const (
 const (
 	SIGNING_TOKEN_INVITE_SUBJECT = "You have a document to sign on Rentloop"
 	SIGNING_TOKEN_RESENT_SUBJECT = "Reminder: You have a document to sign on Rentloop"
+
+	SIGNING_ENVELOPE_PM_TURN_SUBJECT     = "It's your turn to sign a document on Rentloop"
+	SIGNING_ENVELOPE_PM_REMINDER_SUBJECT = "Reminder: A document is waiting for your signature"
+	SIGNING_ENVELOPE_CANCELLED_SUBJECT   = "A signing request has been cancelled"
 )
 
 const ANNOUNCEMENT_EMAIL_SUBJECT = "New Announcement from Your Property Manager"
@@ -111,6 +124,10 @@ const (
 	LEASE_TERMINATED_SMS_BODY       = `Hi {{tenant_name}}, your lease for {{unit_name}} has been terminated. Reason: {{termination_reason}}`
 	LEASE_MOVEOUT_REMINDER_SMS_BODY = `Hi {{tenant_name}}, your lease for {{unit_name}} ends in {{days_remaining}} day(s) on {{move_out_date}}. Please prepare for move-out.`
 	LEASE_COMPLETED_SMS_BODY        = `Hi {{tenant_name}}, your lease for {{unit_name}} has ended. Thank you for staying with us.`
+	LEASE_RENEWAL_OFFER_SMS_BODY    = `Hi {{tenant_name}}, you have an offer to renew your lease for {{unit_name}}. Review it in the Rentloop app before {{expiry_date}}.`
+	LEASE_HOLDOVER_SMS_BODY         = `Hi {{tenant_name}}, your lease for {{unit_name}} has moved to month-to-month at {{currency}} {{rent}} per month. The current month ends on {{move_out_date}}.`
+
+	LEASE_MOVEOUT_REMINDER_HOLDOVER_SMS_BODY = `Hi {{tenant_name}}, your lease for {{unit_name}} reaches its end date in {{days_remaining}} day(s) on {{move_out_date}}. Unless it is renewed, it will continue month-to-month at {{currency}} {{rent}} per month.`
 )
 
 const (
@@ -121,6 +138,8 @@ const (
 	INVOICE_OVERDUE_7D_SMS_BODY     = `Urgent: Invoice {{invoice_code}} is 7 days overdue. Amount: {{currency}} {{amount}}. Pay immediately.`
 	INVOICE_OVERDUE_14D_SMS_BODY    = `Final notice: Invoice {{invoice_code}} is 14 days overdue. Amount: {{currency}} {{amount}}. Pay now to avoid action.`
 	INVOICE_PAID_SMS_BODY           = `Payment received for invoice {{invoice_code}}. Amount: {{currency}} {{amount}}. Thank you!`
+
+	INVOICE_GUARANTOR_ESCALATION_SMS_BODY = `Hi {{guarantor_name}}, rent you guarantee for {{tenant_name}} at {{unit_name}} is {{days_overdue}} day(s) overdue. Invoice {{invoice_code}}, amount: {{currency}} {{amount}}.`
 )
 
 const (
@@ -148,4 +167,25 @@ const (
 	BOOKING_CHECKIN_REMINDER_SMS_BODY  = `Hi {{tenant_name}}, this is a reminder that your check-in for {{unit_name}} is tomorrow. Check-in time: {{check_in_time}}.`
 	BOOKING_CHECKOUT_REMINDER_SUBJECT  = "Reminder: Your Check-Out is Tomorrow"
 	BOOKING_CHECKOUT_REMINDER_SMS_BODY = `Hi {{tenant_name}}, this is a reminder that your check-out for {{unit_name}} is tomorrow. Check-out time: {{check_out_time}}.`
+	BOOKING_HOLD_REMINDER_SUBJECT      = "Reminder: Pay to Keep Your Booking"
+	BOOKING_HOLD_REMINDER_SMS_BODY     = `Hi {{tenant_name}}, your booking for {{unit_name}} is held until {{hold_expires_at}}. Pay {{amount}} before then or it will be cancelled: {{website_url}}/bookings/track/{{booking_code}}`
+	BOOKING_MODIFIED_SUBJECT           = "Your Booking Has Changed"
+	BOOKING_MODIFIED_SMS_BODY          = `Hi {{tenant_name}}, your booking is now for {{unit_name}} from {{check_in_date}} to {{check_out_date}}.{{settlement}} Details: {{website_url}}/bookings/track/{{booking_code}}`
+)
+
+const (
+	WAITLIST_INVITED_SUBJECT  = "A Unit You're Waiting For Is Available"
+	WAITLIST_INVITED_SMS_BODY = `Hi {{first_name}}, {{unit_name}} at {{property_name}} is available. Apply before {{expires_at}} to keep your place: {{website_url}}/tenants/apply?unit={{unit_id}}&waitlist={{invite_token}}`
+	WAITLIST_VACANCY_SUBJECT  = "A Unit You're Waiting For Is Coming Free"
+	WAITLIST_VACANCY_SMS_BODY = `Hi {{first_name}}, {{unit_name}} at {{property_name}} is expected to be free from {{available_on}}. We'll send you a link to apply as soon as it is.`
+)
+
+const (
+	VIEWING_BOOKED_SUBJECT       = "Your Viewing Is Booked"
+	VIEWING_BOOKED_SMS_BODY      = `Hi {{first_name}}, your viewing of {{unit_name}} at {{property_name}} is booked for {{scheduled_at}}. Address: {{property_address}}. Details or cancel: {{website_url}}/viewings/{{viewing_code}}`
+	VIEWING_REMINDER_SUBJECT     = "Reminder: Your Viewing Is Coming Up"
+	VIEWING_REMINDER_SMS_BODY    = `Hi {{first_name}}, a reminder of your viewing of {{unit_name}} at {{property_name}} on {{scheduled_at}}. Address: {{property_address}}. Can't make it? Cancel here: {{website_url}}/viewings/{{viewing_code}}`
+	VIEWING_CANCELLED_SUBJECT    = "Your Viewing Has Been Cancelled"
+	VIEWING_CANCELLED_SMS_BODY   = `Hi {{first_name}}, your viewing of {{unit_name}} at {{property_name}} on {{scheduled_at}} has been cancelled. Reason: {{reason}}`
+	VIEWING_STAFF_BOOKED_SUBJECT = "New Viewing Booked"
 )
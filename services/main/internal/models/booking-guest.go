@@ -0,0 +1,18 @@
+package models
+
+// BookingGuest is a person staying under a booking, registered by name for
+// check-in as short-let regulations require. The tenant who booked is not
+// listed unless they are staying too.
+type BookingGuest struct {
+	BaseModelSoftDelete
+
+	BookingID string `gorm:"not null;index;"`
+	Booking   Booking
+
+	FirstName   string  `gorm:"not null;"`
+	LastName    string  `gorm:"not null;"`
+	IsChild     bool    `gorm:"not null;default:false"`
+	Nationality *string // e.g. Ghanaian
+	IDType      *string // GHANA_CARD | NATIONAL_ID | PASSPORT | DRIVER_LICENSE
+	IDNumber    *string
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// BookingModification is a change to a booking's stay after it was made:
+// new dates, a move to another unit in the same property, or both.
+// Modifications are the booking's history. Each records the stay it replaced
+// alongside the one it set, and how the change in price was settled.
+//
+// A booking whose invoice is still an unpaid draft has that invoice repriced.
+// Otherwise the invoice is left as it is, and a stay that now costs more is
+// billed on a supplementary invoice while one that costs less leaves the
+// guest a credit.
+type BookingModification struct {
+	BaseModelSoftDelete
+
+	BookingID string `gorm:"not null;index;"`
+	Booking   Booking
+
+	Reason string `gorm:"not null;default:''"`
+
+	PreviousUnitID       string    `gorm:"not null;"`
+	PreviousCheckInDate  time.Time `gorm:"not null;"`
+	PreviousCheckOutDate time.Time `gorm:"not null;"`
+
+	UnitID       string    `gorm:"not null;"`
+	Unit         Unit      `gorm:"foreignKey:UnitID"`
+	CheckInDate  time.Time `gorm:"not null;"`
+	CheckOutDate time.Time `gorm:"not null;"`
+
+	// what the stay is priced at before and after the change, in the smallest
+	// currency unit
+	PreviousTotal int64  `gorm:"not null;"`
+	Total         int64  `gorm:"not null;"`
+	Currency      string `gorm:"not null;default:'GHS'"`
+
+	// InvoiceID is the supplementary invoice billing a higher price, and
+	// CreditAmount what the guest is owed for a lower one, paid back through
+	// Refund. None is set when the booking's own invoice was repriced.
+	InvoiceID    *string `gorm:"index;"`
+	Invoice      *Invoice
+	CreditAmount int64 `gorm:"not null;default:0"`
+	Refund       *BookingRefund
+
+	CreatedByClientUserID string     `gorm:"not null;"`
+	CreatedByClientUser   ClientUser `gorm:"foreignKey:CreatedByClientUserID"`
+}
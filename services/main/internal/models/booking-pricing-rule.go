@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// BookingPricingRule adjusts how a property's short-stay units are priced.
+// Rules price nights, so they only apply to units let by the day. Types:
+//
+//	RATE            — NightlyRate replaces the unit's rent fee on matching nights
+//	ADJUSTMENT      — AdjustmentPercent moves the night's rate up or down
+//	STAY_LENGTH     — MinNights and/or MaxNights for stays checking in on a matching night
+//	LENGTH_DISCOUNT — DiscountPercent off stays of at least MinNights checking in on a matching night
+//
+// A night matches when it falls within StartDate–EndDate (both inclusive, either
+// open) and on one of DaysOfWeek (any day when empty). Where several rules of a
+// type match, the highest Priority wins, then a unit's own rule over the
+// property's, then the newest — except that a stay gets the discount for the
+// longest stay it reaches, so a monthly discount beats a weekly one.
+type BookingPricingRule struct {
+	BaseModelSoftDelete
+
+	PropertyID string `gorm:"not null;index;"`
+	Property   Property
+
+	// UnitID narrows the rule to one unit. Null means every unit in the property.
+	UnitID *string `gorm:"index;"`
+	Unit   *Unit
+
+	Name     string `gorm:"not null;"` // "Christmas peak", "Weekend rate"
+	Type     string `gorm:"not null;index;"`
+	Priority int64  `gorm:"not null;default:0"`
+
+	StartDate  *time.Time     `gorm:"type:date;"`
+	EndDate    *time.Time     `gorm:"type:date;"`
+	DaysOfWeek pq.StringArray `gorm:"type:text[];default:'{}'"` // MON | TUE | WED | THU | FRI | SAT | SUN
+
+	NightlyRate       *int64 // RATE, in the unit's currency
+	AdjustmentPercent *int64 // ADJUSTMENT, signed: 20 is 20% more, -15 is 15% less
+	MinNights         *int64 // STAY_LENGTH, LENGTH_DISCOUNT
+	MaxNights         *int64 // STAY_LENGTH
+	DiscountPercent   *int64 // LENGTH_DISCOUNT
+
+	CreatedByClientUserID *string `gorm:"index;"`
+	CreatedByClientUser   *ClientUser
+}
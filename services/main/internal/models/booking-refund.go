@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// BookingRefund is what a guest is owed back from their booking invoice when
+// the booking is cancelled after they paid, worked out from the booking's
+// cancellation policy. The money goes back outside the platform, so the
+// refund stays PENDING until a manager records it as paid.
+//
+// A change that lowers the price of the stay leaves the guest a credit, which
+// is refunded the same way on a refund linked to the BookingModification. A
+// booking has at most one cancellation refund, the one with no modification.
+//
+// Status: PENDING → PAID
+type BookingRefund struct {
+	BaseModelSoftDelete
+
+	BookingID             string `gorm:"not null;index;"`
+	Booking               Booking
+	BookingModificationID *string `gorm:"uniqueIndex;"`
+	BookingModification   *BookingModification
+	InvoiceID             string `gorm:"not null;index;"`
+	Invoice               Invoice
+
+	// how the amount was worked out
+	CancellationPolicy string `gorm:"not null;"`
+	DaysBeforeCheckIn  int64  `gorm:"not null;"` // negative when cancelled after the check-in date
+	RefundPercent      int64  `gorm:"not null;"`
+	AmountPaid         int64  `gorm:"not null;"` // received against the invoice when it was cancelled
+
+	Amount   int64  `gorm:"not null;"` // in smallest currency unit, e.g., pesewas
+	Currency string `gorm:"not null;default:'GHS'"`
+
+	Status             string `gorm:"not null;default:'PENDING';index;"` // PENDING | PAID
+	PaidAt             *time.Time
+	PaidByClientUserID *string
+	PaidByClientUser   *ClientUser
+	PaymentReference   *string // e.g. the MoMo or bank transfer reference
+}
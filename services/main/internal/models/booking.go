@@ -27,6 +27,12 @@ type Booking struct {
 	CheckInDate  time.Time `gorm:"not null;"`
 	CheckOutDate time.Time `gorm:"not null;"`
 
+	// Adults and Children together may not exceed the unit's
+	// MaxOccupantsAllowed. Guests is who they are, registered for check-in.
+	Adults   int64 `gorm:"not null;default:1"`
+	Children int64 `gorm:"not null;default:0"`
+	Guests   []BookingGuest
+
 	ConfirmedAt   *time.Time
 	ConfirmedByID *string
 	ConfirmedBy   *ClientUser
@@ -40,6 +46,10 @@ type Booking struct {
 	CheckedOutBy   *ClientUser
 
 	StayFrequency string
+	// FLAT stays are charged one rate per period, the one on the booking's
+	// invoice line. RULES stays are priced night by night from the unit's
+	// BookingPricingRules and are quoted again when their dates change.
+	PricingMethod string `gorm:"not null;default:'FLAT'"` // FLAT | RULES
 
 	Status string `gorm:"not null;default:'PENDING';index;"` // PENDING → CONFIRMED → CHECKED_IN → COMPLETED | CANCELLED
 
@@ -47,6 +57,12 @@ type Booking struct {
 	CanceledByID       *string
 	CanceledBy         *ClientUser
 	CancellationReason string `gorm:"not null;default:''"`
+	// CancellationPolicy is the property's policy when the booking was made,
+	// and decides the refund if it is cancelled.
+	CancellationPolicy string `gorm:"not null;default:'FLEXIBLE'"` // FLEXIBLE | MODERATE | STRICT
+	// Refund is what cancelling the booking gave back. Credits for changes to
+	// the stay are refunds of their own, on the modifications.
+	Refund *BookingRefund
 
 	Notes string `gorm:"not null;default:''"`
 
@@ -55,7 +71,15 @@ type Booking struct {
 	CreatedByClientUserID  *string `gorm:"index;"`
 	CreatedByClientUser    *ClientUser
 
-	Invoice *Invoice `gorm:"foreignKey:ContextBookingID;references:ID"`
+	// HoldExpiresAt is when a PENDING booking that requires upfront payment
+	// is cancelled if nothing has been paid. Nil when it is not on hold.
+	HoldExpiresAt *time.Time `gorm:"index;"`
+
+	// Invoice is the booking's own BOOKING_FEE invoice, and
+	// SupplementaryInvoices bill later changes to the stay. Both hang off
+	// ContextBookingID, so they are preloaded by context type.
+	Invoice               *Invoice  `gorm:"foreignKey:ContextBookingID;references:ID"`
+	SupplementaryInvoices []Invoice `gorm:"foreignKey:ContextBookingID;references:ID"`
 
 	Meta datatypes.JSON `gorm:"type:jsonb;"`
 }
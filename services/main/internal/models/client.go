@@ -1,5 +1,7 @@
 package models
 
+import "github.com/lib/pq"
+
 // Client represents a property owner (landlord/developer/etc) in the system
 type Client struct {
 	BaseModelSoftDelete
@@ -29,8 +31,19 @@ type Client struct {
 
 	Currency string `gorm:"not null;default:'GHS'"` // reporting currency for the org
 
+	// SigningOtpRequired makes everyone signing the org's documents by link
+	// confirm a one-time code first. SigningOtpDocumentTags requires it only
+	// for documents with one of the tags, LEASE_AGREEMENT say.
+	SigningOtpRequired     bool           `gorm:"not null;default:false"`
+	SigningOtpDocumentTags pq.StringArray `gorm:"type:text[];default:'{}'"`
+
+	// PublicListingEnabled puts the org's vacant and bookable units in the
+	// public unit search. Units are still reachable by link when it is off.
+	PublicListingEnabled bool `gorm:"not null;default:false"`
+
 	ClientApplicationId string `gorm:"not null;"`
 	ClientApplication   ClientApplication
 
-	Properties []Property
+	Properties      []Property
+	PaymentAccounts []PaymentAccount
 }
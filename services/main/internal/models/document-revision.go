@@ -0,0 +1,33 @@
+package models
+
+import "gorm.io/datatypes"
+
+// DocumentRevision is a snapshot of a document's title and content, recorded
+// each time either changes. Revisions are never edited: restoring an old one
+// records a new revision with its content.
+type DocumentRevision struct {
+	BaseModel
+
+	DocumentID string `gorm:"not null;uniqueIndex:idx_document_revisions_document_number;"`
+	Document   *Document
+
+	// Number counts a document's revisions from 1.
+	Number int64 `gorm:"not null;uniqueIndex:idx_document_revisions_document_number;"`
+
+	Title   string         `gorm:"not null"`
+	Content datatypes.JSON `gorm:"type:jsonb;not null;"`
+	// ContentHash is the SHA-256 of Content, as signatures record it.
+	ContentHash string `gorm:"not null"`
+
+	// Source is what recorded the revision:
+	// "CREATED" | "UPDATED" | "INSTANTIATED" | "RESTORED" | "BACKFILLED"
+	Source string `gorm:"not null"`
+	// RestoredFromID is the revision a RESTORED revision copies.
+	RestoredFromID *string
+	RestoredFrom   *DocumentRevision
+
+	// CreatedByID is nil for revisions not made by a property manager: edits
+	// through the public document endpoint, and backfilled revisions.
+	CreatedByID *string
+	CreatedBy   *ClientUser
+}
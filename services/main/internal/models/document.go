@@ -17,6 +17,10 @@ type Document struct {
 	Content datatypes.JSON `gorm:"type:jsonb;not null;"`
 	Tags    pq.StringArray `gorm:"type:text[];default:'{}'"` // LEASE_AGREEMENT | LEASE_RENEWAL | LEASE_EXTENSION | LEASE_EXTENSION | INSPECTION_REPORT | OTHER
 
+	// CurrentRevisionID is the DocumentRevision holding Title and Content as
+	// they are now.
+	CurrentRevisionID *string
+
 	PropertyID *string `gorm:"index;"`
 	Property   *Property
 
@@ -13,13 +13,39 @@ type DocumentSignature struct {
 	Lease                    *Lease
 	LeaseTerminationID       *string // nullable — links to the lease termination process
 	LeaseTermination         *LeaseTermination
+	LeaseAmendmentID         *string // nullable — links to the lease amendment process
+	LeaseAmendment           *LeaseAmendment
 	LeaseAgreementDocumentID *string // nullable — links to the LeaseAgreementDocument pipeline
 	LeaseAgreementDocument   *LeaseAgreementDocument
-	Role                     string // "PROPERTY_MANAGER" | "TENANT" | "PM_WITNESS" | "TENANT_WITNESS"
+	TenantID                 *string // nullable — which lease tenant signed, for TENANT signatures
+	Tenant                   *Tenant
+	GuarantorID              *string // nullable — which guarantor signed, for GUARANTOR signatures
+	Guarantor                *Guarantor
+	Role                     string // "PROPERTY_MANAGER" | "TENANT" | "GUARANTOR" | "PM_WITNESS" | "TENANT_WITNESS"
 	SignatureUrl             string // S3 URL of the drawn signature image
 	SignedByName             *string
 	SignedByID               *string //
 	SignedBy                 *ClientUser
 
+	// DocumentRevisionID is the revision that was signed. Nil on signatures
+	// made before documents had revisions.
+	DocumentRevisionID *string
+	DocumentRevision   *DocumentRevision
+
 	IPAddress string
+	UserAgent *string
+	// VerificationMethod is how the signer proved who they were:
+	// "LINK" (holding the signing link) | "OTP_SMS" | "OTP_EMAIL" | "PORTAL"
+	// (a PM signed in to the portal).
+	VerificationMethod string `gorm:"not null;default:'LINK'"`
+
+	// ContentHash is the SHA-256 of the document's content as it stood when
+	// it was signed.
+	ContentHash string
+	// PreviousEventHash is the EventHash of the signature before this one on
+	// the same document, nil for the first. Together they chain a document's
+	// signatures, so one cannot be edited or removed without breaking every
+	// hash after it.
+	PreviousEventHash *string
+	EventHash         string `gorm:"index;"`
 }
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 type Expense struct {
 	BaseModelSoftDelete
 
@@ -18,6 +20,14 @@ type Expense struct {
 	Amount      int64  `gorm:"not null;"`
 	Currency    string `gorm:"not null;default:'GHS'"`
 
+	// ScheduledFor marks a cost the landlord has committed to but not yet paid
+	// (a contractor booked for next month). The cash-flow forecast subtracts
+	// these; an expense without it has already left the account.
+	ScheduledFor *time.Time `gorm:"index;"`
+	// PaidAt is when the money left the account and the expense was posted to
+	// the books. Settling a scheduled expense sets it and clears ScheduledFor.
+	PaidAt *time.Time
+
 	CreatedByClientUserID string `gorm:"not null;"`
 	CreatedByClientUser   ClientUser
 }
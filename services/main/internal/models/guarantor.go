@@ -0,0 +1,61 @@
+package models
+
+// Guarantor is someone who undertakes to pay a tenant's rent if the tenant
+// does not — typically a parent for a student or first-time renter.
+//
+// A guarantor is collected on the TenantApplication and carried to the Lease
+// on approval: the same row gains a LeaseID rather than being copied, so the
+// signature given during the application still belongs to it.
+type Guarantor struct {
+	BaseModelSoftDelete
+
+	TenantApplicationID *string `gorm:"index;"`
+	TenantApplication   *TenantApplication
+
+	LeaseID *string `gorm:"index;"`
+	Lease   *Lease
+
+	FirstName            string `gorm:"not null;"`
+	LastName             string `gorm:"not null;"`
+	Email                *string
+	Phone                string  `gorm:"not null;"`
+	RelationshipToTenant *string // Parent, Sibling, Employer, Sponsor, ...
+
+	IDType     *string // NationalID, Passport, DriverLicense
+	IDNumber   *string
+	IDFrontUrl *string
+	IDBackUrl  *string
+
+	Occupation       *string
+	Employer         *string
+	ProofOfIncomeUrl *string
+
+	// LiabilityCap is the most the guarantor has agreed to cover, in the
+	// smallest currency unit. Nil means the guarantee is unlimited.
+	LiabilityCap         *int64
+	LiabilityCapCurrency *string
+
+	// ArrearsEscalationDays is how many days a rent invoice may sit overdue
+	// before its reminders are also sent to the guarantor. Nil means the
+	// guarantor is never chased.
+	ArrearsEscalationDays *int64
+
+	CreatedById string
+	CreatedBy   ClientUser
+}
+
+func (g Guarantor) FullName() string {
+	return g.FirstName + " " + g.LastName
+}
+
+// EscalationDue reports whether reminders for a rent invoice this many days
+// overdue should also go to the guarantor.
+func (g Guarantor) EscalationDue(daysPastDue int) bool {
+	return g.ArrearsEscalationDays != nil && int64(daysPastDue) >= *g.ArrearsEscalationDays
+}
+
+// EscalationReminderKey is what Invoice.RemindersSent records once the
+// guarantor has been told about that invoice, so they are told once.
+func (g Guarantor) EscalationReminderKey() string {
+	return "guarantor_" + g.ID.String()
+}
@@ -37,8 +37,10 @@ type Invoice struct {
 	PayeeTenantID *string
 	PayeeTenant   *Tenant
 
-	ContextType string `gorm:"not null;"` // 'TENANT_APPLICATION' | 'LEASE_RENT' | 'MAINTENANCE' | 'SAAS_FEE' | 'BOOKING_FEE' | 'LEASE_TERMINATION'
+	ContextType string `gorm:"not null;"` // 'TENANT_APPLICATION' | 'LEASE_RENT' | 'MAINTENANCE' | 'SAAS_FEE' | 'BOOKING_FEE' | 'BOOKING_MODIFICATION' | 'LEASE_TERMINATION'
 
+	// ContextBookingID is set on a booking's own invoice (BOOKING_FEE) and on
+	// the supplementary invoices billing changes to it (BOOKING_MODIFICATION).
 	ContextBookingID *string
 	ContextBooking   *Booking
 
@@ -70,6 +72,13 @@ type Invoice struct {
 	VoidedByClientUserID *string
 	VoidedByClientUser   *ClientUser
 
+	// WrittenOffAmount is an unpaid balance the payee gave up on, settling the
+	// invoice for what was paid, e.g. the rest of a cancelled booking's bill.
+	WrittenOffAmount         int64 `gorm:"not null;default:0"`
+	WrittenOffAt             *time.Time
+	WrittenOffReason         *string
+	WrittenOffByClientUserID *string
+
 	// for now let's default to what we support
 	AllowedPaymentRails pq.StringArray `gorm:"type:text[];not null;default:'{OFFLINE}'"` // ['MOMO', 'BANK_TRANSFER', 'OFFLINE', 'CARD']. Based on the payment accounts for the payee type, filter and fetch for UI
 	RemindersSent       pq.StringArray `gorm:"type:text[];not null;default:'{}'"`        // tracks which reminders have been sent, e.g. ["pre_due_1d", "overdue_1d"]
@@ -93,12 +102,12 @@ type InvoiceLineItem struct {
 	Label string `gorm:"not null;"` // "January Rent", "Security Deposit"
 
 	// Category mirrors ChargeInstance.Category for account-backed lines, plus
-	// the three categories that belong to invoices with no financial account
-	// behind them:
+	// the categories that belong to invoices with no financial account behind
+	// them:
 	//
 	//	tenant charges  RENT, SECURITY_DEPOSIT, AGENCY_FEE, VAT, UTILITY,
 	//	                DAMAGE_CHARGE, EARLY_TERMINATION_FEE, OTHER
-	//	non-account     MAINTENANCE_FEE, SAAS_FEE, BOOKING_FEE
+	//	non-account     MAINTENANCE_FEE, SAAS_FEE, BOOKING_FEE, EXTRA_GUEST_FEE
 	//
 	// Historical rows may still carry INITIAL_DEPOSIT, EXPENSE, DEPOSIT_REFUND
 	// or RENT_REFUND. None can be written any more: the initial deposit is a
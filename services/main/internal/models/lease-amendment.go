@@ -0,0 +1,85 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/getsentry/raven-go"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// LeaseAmendment is a mid-term change to an active lease's terms: a rent
+// change, an extended or shortened end date, or an added recurring charge.
+// Status: LeaseAmendment.Status.Draft → .Executed | .Cancelled
+//
+// Executed amendments are the lease's version history. Each records the terms
+// it replaced alongside the ones it set, so any earlier version can be read
+// back without replaying the chain.
+type LeaseAmendment struct {
+	BaseModelSoftDelete
+
+	Code   string `gorm:"not null;uniqueIndex;"`
+	Status string `gorm:"not null;default:'LeaseAmendment.Status.Draft';index;"`
+	Reason string `gorm:"not null;"`
+
+	LeaseID string `gorm:"not null;index;"`
+	Lease   Lease
+
+	// The new terms take effect from the first billing period starting on or
+	// after this date.
+	EffectiveDate time.Time `gorm:"not null;"`
+
+	// changed terms — nil means unchanged
+	RentFee               *int64
+	StayDuration          *int64 // the whole term from move-in, not an addition to it
+	StayDurationFrequency *string
+	AddedCharges          datatypes.JSONSlice[LeaseAmendmentCharge] `gorm:"type:jsonb;"`
+
+	// amendment document
+	DocumentMode *string // MANUAL | ONLINE
+	DocumentUrl  *string // for MANUAL mode
+	DocumentID   *string // FK to library Document for ONLINE mode
+	Document     *Document
+
+	// set on execution
+	Version                       *int64 // the lease version this amendment produced
+	PreviousRentFee               *int64
+	PreviousStayDuration          *int64
+	PreviousStayDurationFrequency *string
+	PreviousMoveOutDate           *time.Time
+
+	// process tracking
+	InitiatedById string     `gorm:"not null;"`
+	InitiatedBy   ClientUser `gorm:"foreignKey:InitiatedById"`
+
+	ExecutedAt   *time.Time
+	ExecutedById *string
+	ExecutedBy   *ClientUser `gorm:"foreignKey:ExecutedById"`
+
+	CancelledAt   *time.Time
+	CancelledById *string
+	CancelledBy   *ClientUser `gorm:"foreignKey:CancelledById"`
+}
+
+// LeaseAmendmentCharge is a recurring charge an amendment adds, billed every
+// payment period alongside the rent — parking, a service charge.
+type LeaseAmendmentCharge struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Amount   int64  `json:"amount"`
+}
+
+func (t *LeaseAmendment) BeforeCreate(tx *gorm.DB) error {
+	uniqueCode, genErr := lib.GenerateCode(tx, &LeaseAmendment{})
+	if genErr != nil {
+		raven.CaptureError(genErr, map[string]string{
+			"function": "BeforeCreateLeaseAmendmentHook",
+			"action":   "Generating a unique code",
+		})
+		return genErr
+	}
+
+	t.Code = *uniqueCode
+	return nil
+}
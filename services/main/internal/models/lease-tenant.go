@@ -0,0 +1,30 @@
+package models
+
+// Lease tenant roles.
+const (
+	LeaseTenantRolePrimary  = "PRIMARY"
+	LeaseTenantRoleCoTenant = "CO_TENANT"
+	LeaseTenantRoleOccupant = "OCCUPANT"
+)
+
+// LeaseTenant puts a tenant on a lease. Every lease has exactly one PRIMARY —
+// the same tenant as Lease.TenantId, which stays the lease's contact for
+// notices and the account holder on its financial account. CO_TENANTs sign
+// the agreement and are jointly liable for it; OCCUPANTs live in the unit and
+// can see the lease but are not party to it.
+type LeaseTenant struct {
+	BaseModelSoftDelete
+
+	LeaseID string `gorm:"not null;index;"`
+	Lease   Lease
+
+	TenantID string `gorm:"not null;index;"`
+	Tenant   Tenant
+
+	Role string `gorm:"not null;"` // PRIMARY | CO_TENANT | OCCUPANT
+}
+
+// IsSignatory reports whether the tenant must sign the lease agreement.
+func (t LeaseTenant) IsSignatory() bool {
+	return t.Role == LeaseTenantRolePrimary || t.Role == LeaseTenantRoleCoTenant
+}
@@ -32,9 +32,22 @@ type LeaseTermination struct {
 	DocumentID   *string // FK to library Document for ONLINE mode
 	Document     *Document
 
-	// process tracking
-	InitiatedById string     `gorm:"not null;"`
-	InitiatedBy   ClientUser `gorm:"foreignKey:InitiatedById"`
+	// process tracking — a termination is started by a client user, or by a
+	// tenant serving notice to vacate, never both.
+	InitiatedById *string
+	InitiatedBy   *ClientUser `gorm:"foreignKey:InitiatedById"`
+
+	InitiatedByTenantID *string
+	InitiatedByTenant   *Tenant `gorm:"foreignKey:InitiatedByTenantID"`
+
+	// notice to vacate — set when the tenant gave notice
+	IntendedMoveOutDate *time.Time
+	NoticePeriodDays    *int64 // the notice period the notice was checked against
+
+	// EarlyTerminationFee is worked out from the lease's policy when notice is
+	// given and charged when the termination completes. 0 when none is due.
+	EarlyTerminationFee      int64 `gorm:"not null;default:0"`
+	EarlyTerminationChargeID *string
 
 	CompletedAt   *time.Time
 	CompletedById *string
@@ -17,6 +17,13 @@ import (
 // Completed means lease ran its full duration and ended.
 // Cancelled means lease was never activated after being created.
 
+// Early termination fee policies.
+const (
+	EarlyTerminationFeePolicyNone        = "NONE"
+	EarlyTerminationFeePolicyFixed       = "FIXED"
+	EarlyTerminationFeePolicyRentPeriods = "RENT_PERIODS"
+)
+
 // Lease types.
 const (
 	LeaseTypeOriginal = "ORIGINAL"
@@ -32,9 +39,15 @@ type Lease struct {
 	UnitId string `gorm:"not null;"`
 	Unit   Unit
 
-	TenantId string `gorm:"not null;"`
+	TenantId string `gorm:"not null;"` // the PRIMARY lease tenant
 	Tenant   Tenant
 
+	// Tenants lists everyone on the lease, the primary included.
+	Tenants []LeaseTenant `gorm:"foreignKey:LeaseID"`
+
+	// Guarantors came with the application or were added to the lease since.
+	Guarantors []Guarantor `gorm:"foreignKey:LeaseID"`
+
 	TenantApplicationId string `gorm:"not null;"`
 	TenantApplication   TenantApplication
 
@@ -55,6 +68,17 @@ type Lease struct {
 	MoveOutDate   *time.Time     // computed from MoveInDate + StayDuration/StayDurationFrequency; 2099-01-01 sentinel for open-ended leases
 	RemindersSent pq.StringArray `gorm:"type:text[];not null;default:'{}'"` // tracks which move-out reminder thresholds have fired, e.g. ["moveout_30d", "moveout_7d"]
 
+	// HoldoverStartedAt is set the first time the lease rolls over month to
+	// month past its fixed term. MoveOutDate then marks the end of the current
+	// holdover month, and StayDuration is restated to end there with it.
+	HoldoverStartedAt *time.Time
+
+	// notice to vacate and early termination
+	NoticePeriodDays               *int64 // overrides Property.NoticePeriodDays when set
+	EarlyTerminationFeePolicy      string `gorm:"not null;default:'NONE'"` // NONE | FIXED | RENT_PERIODS
+	EarlyTerminationFeeAmount      int64  `gorm:"not null;default:0"`      // FIXED: the fee itself
+	EarlyTerminationFeeRentPeriods int64  `gorm:"not null;default:0"`      // RENT_PERIODS: how many periods of rent
+
 	KeyHandoverDate        *time.Time // when keys were handed over to tenant
 	UtilityTransfersDate   *time.Time // when utilities were transferred to tenant name
 	PropertyInspectionDate *time.Time // a move-in checklist can be created in the process.
@@ -65,6 +89,11 @@ type Lease struct {
 
 	TerminationAgreementDocumentUrl *string
 
+	// Version starts at 1 and is bumped by every executed amendment; the
+	// amendments themselves are the history.
+	Version    int64            `gorm:"not null;default:1;"`
+	Amendments []LeaseAmendment `gorm:"foreignKey:LeaseID"`
+
 	ActivatedAt   *time.Time
 	ActivatedById *string
 	ActivatedBy   *ClientUser
@@ -1,5 +1,7 @@
 package models
 
+import "time"
+
 // LeaseAgreementDocument manages the document pipeline for a lease agreement.
 // Status machine: "DRAFT" -> "FINALIZED" -> "SIGNING" -> "SIGNED"
 // FINALIZED is set explicitly by the PM to lock content.
@@ -7,12 +9,23 @@ package models
 // SIGNED is set automatically when the last required party submits their signature.
 type LeaseAgreementDocument struct {
 	BaseModelSoftDelete
-	LeaseID     string `gorm:"not null"` // one active pipeline record per lease (enforced via partial unique index)
-	Lease       *Lease
-	Mode        string  `gorm:"not null"` // "MANUAL" | "ONLINE"
-	DocumentID  *string // FK to Document (ONLINE mode only)
-	Document    *Document
-	DocumentUrl *string             // final PDF URL; set on MANUAL attach or after ONLINE PDF generation
-	Status      string              `gorm:"not null;default:'DRAFT'"` // "DRAFT" | "FINALIZED" | "SIGNING" | "SIGNED"
-	Signatures  []DocumentSignature `gorm:"foreignKey:LeaseAgreementDocumentID"`
+	LeaseID      string `gorm:"not null"` // one active pipeline record per lease (enforced via partial unique index)
+	Lease        *Lease
+	Mode         string  `gorm:"not null"` // "MANUAL" | "ONLINE"
+	DocumentID   *string // FK to Document (ONLINE mode only)
+	Document     *Document
+	DocumentUrl  *string             // final PDF URL; set on MANUAL attach or after ONLINE PDF generation
+	DocumentHash *string             // SHA-256 of the PDF at DocumentUrl, for ONLINE renders
+	Status       string              `gorm:"not null;default:'DRAFT'"` // "DRAFT" | "FINALIZED" | "SIGNING" | "SIGNED"
+	Signatures   []DocumentSignature `gorm:"foreignKey:LeaseAgreementDocumentID"`
+
+	// DocumentRevisionID pins the revision of Document that was finalized, so
+	// the parties sign, and the PDF shows, that content even if the document
+	// is edited afterwards (ONLINE mode only).
+	DocumentRevisionID *string
+	DocumentRevision   *DocumentRevision
+
+	// FinalizedAt dates the agreement: it is the #AgreementDate every render
+	// of the finalized content shows. Cleared when reverted to a draft.
+	FinalizedAt *time.Time
 }
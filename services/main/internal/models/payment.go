@@ -13,6 +13,11 @@ type Payment struct {
 	InvoiceID string
 	Invoice   Invoice
 
+	// PayerTenantID attributes the payment to the tenant who made it when a
+	// lease has several. It settles the shared account either way.
+	PayerTenantID *string `gorm:"index;"`
+	PayerTenant   *Tenant
+
 	Rail     string  `gorm:"not null;"` // MOMO | BANK_TRANSFER | CARD | OFFLINE
 	Provider *string // MTN | VODAFONE | AIRTELTIGO | PAYSTACK | BANK_API | CASH
 
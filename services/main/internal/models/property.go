@@ -7,6 +7,20 @@ import (
 	"gorm.io/gorm"
 )
 
+// Holdover policies.
+const (
+	PropertyHoldoverPolicyComplete     = "COMPLETE"
+	PropertyHoldoverPolicyMonthToMonth = "MONTH_TO_MONTH"
+)
+
+// Booking cancellation policies. The refund each allows, by how long before
+// check-in a booking is cancelled, is set out in services.
+const (
+	BookingCancellationPolicyFlexible = "FLEXIBLE"
+	BookingCancellationPolicyModerate = "MODERATE"
+	BookingCancellationPolicyStrict   = "STRICT"
+)
+
 // Property represents a property under a client in the system
 type Property struct {
 	BaseModelSoftDelete
@@ -34,6 +48,30 @@ type Property struct {
 
 	Modes pq.StringArray `gorm:"type:text[];default:'{}'"` // LEASE | BOOKING
 
+	// HoldoverPolicy decides what the nightly sweep does with an Active lease
+	// whose move-out has passed without a renewal: complete it, or roll it
+	// over month to month. The premium is applied once, on the first rollover.
+	HoldoverPolicy             string `gorm:"not null;default:'COMPLETE'"` // COMPLETE | MONTH_TO_MONTH
+	HoldoverRentPremiumPercent int64  `gorm:"not null;default:0"`          // 0 keeps the current rent
+
+	// NoticePeriodDays is how far ahead a tenant must give notice to vacate.
+	// A lease may override it.
+	NoticePeriodDays int64 `gorm:"not null;default:30"`
+
+	// BookingCancellationPolicy is the policy new bookings are taken under.
+	// Each booking keeps the one it was made with.
+	BookingCancellationPolicy string `gorm:"not null;default:'FLEXIBLE'"` // FLEXIBLE | MODERATE | STRICT
+
+	// BookingRequiresUpfrontPayment starts new bookings unpaid-and-held: the
+	// guest has BookingHoldHours to pay before the booking is cancelled. 0
+	// hours holds them until a manager acts.
+	BookingRequiresUpfrontPayment bool  `gorm:"not null;default:false"`
+	BookingHoldHours              int64 `gorm:"not null;default:24"`
+
+	// BookingRequiresGuestList keeps a booking from being checked in
+	// until every one of its guests has been registered by name.
+	BookingRequiresGuestList bool `gorm:"not null;default:false"`
+
 	CreatedByID string `gorm:"not null;"`
 	CreatedBy   ClientUser
 
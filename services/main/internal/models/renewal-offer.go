@@ -0,0 +1,88 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/getsentry/raven-go"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// RenewalOffer is a PM's proposal to continue a tenancy, sent to the tenant
+// before the lease's move-out date. It carries one or more options so the
+// tenant can choose between, say, a year at one rent and six months at
+// another.
+// Status: RenewalOffer.Status.Sent → .Accepted | .Declined | .Countered | .Expired | .Withdrawn
+//
+// Only Sent is open. A counter closes the offer: the PM answers it by sending
+// a new one, so every set of terms the tenant was shown stays on record.
+type RenewalOffer struct {
+	BaseModelSoftDelete
+
+	Code   string `gorm:"not null;uniqueIndex;"`
+	Status string `gorm:"not null;default:'RenewalOffer.Status.Sent';index;"`
+
+	LeaseID string `gorm:"not null;index;"`
+	Lease   Lease  `gorm:"foreignKey:LeaseID"`
+
+	Message   *string
+	Options   datatypes.JSONSlice[RenewalOfferOption] `gorm:"type:jsonb;not null;"`
+	ExpiresAt time.Time                               `gorm:"not null;index;"`
+
+	// set when the tenant accepts
+	AcceptedOptionIndex *int
+	RenewalLeaseID      *string
+	RenewalLease        *Lease `gorm:"foreignKey:RenewalLeaseID"`
+
+	// set when the tenant declines
+	DeclineReason *string
+
+	// set when the tenant counters
+	CounterRentFee               *int64
+	CounterStayDuration          *int64
+	CounterStayDurationFrequency *string
+	CounterMessage               *string
+
+	RespondedAt         *time.Time
+	RespondedByTenantID *string
+	RespondedByTenant   *Tenant `gorm:"foreignKey:RespondedByTenantID"`
+
+	CreatedById string     `gorm:"not null;"`
+	CreatedBy   ClientUser `gorm:"foreignKey:CreatedById"`
+
+	WithdrawnAt   *time.Time
+	WithdrawnById *string
+	WithdrawnBy   *ClientUser `gorm:"foreignKey:WithdrawnById"`
+}
+
+// RenewalOfferOption is one set of terms the tenant may accept. The new term
+// starts on the current lease's move-out date, so only its length is offered.
+type RenewalOfferOption struct {
+	RentFee               int64             `json:"rent_fee"`
+	StayDuration          int64             `json:"stay_duration"`
+	StayDurationFrequency string            `json:"stay_duration_frequency"`
+	Fees                  []RenewalOfferFee `json:"fees"`
+}
+
+// RenewalOfferFee is a one-off charge due at the start of the renewed term if
+// its option is accepted — a deposit top-up, a renewal fee.
+type RenewalOfferFee struct {
+	Category string `json:"category"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"`
+}
+
+func (t *RenewalOffer) BeforeCreate(tx *gorm.DB) error {
+	uniqueCode, genErr := lib.GenerateCode(tx, &RenewalOffer{})
+	if genErr != nil {
+		raven.CaptureError(genErr, map[string]string{
+			"function": "BeforeCreateRenewalOfferHook",
+			"action":   "Generating a unique code",
+		})
+		return genErr
+	}
+
+	t.Code = *uniqueCode
+	return nil
+}
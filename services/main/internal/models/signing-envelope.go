@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// SigningEnvelope groups the signing tokens for one document so they are
+// signed in order — tenant, then guarantor, then the PM, then witnesses, say.
+// Each token carries a SigningOrder; signers sharing an order sign in
+// parallel, and the next order is only invited once everyone before it has
+// signed.
+// Status: SigningEnvelope.Status.InProgress → .Completed | .Cancelled
+type SigningEnvelope struct {
+	BaseModelSoftDelete
+
+	Status string `gorm:"not null;default:'SigningEnvelope.Status.InProgress';index;"`
+
+	DocumentID string `gorm:"not null;index;"`
+	Document   Document
+
+	// DocumentRevisionID is the revision every token in the envelope is for.
+	DocumentRevisionID *string
+	DocumentRevision   *DocumentRevision
+
+	TenantApplicationID *string
+	TenantApplication   *TenantApplication
+
+	LeaseID *string
+	Lease   *Lease
+
+	LeaseTerminationID *string
+	LeaseTermination   *LeaseTermination
+
+	LeaseAmendmentID *string
+	LeaseAmendment   *LeaseAmendment
+
+	Tokens []SigningToken `gorm:"foreignKey:SigningEnvelopeID"`
+
+	// PMSigningOrder is the PM's place in the order. Nil when the PM's
+	// signature is not part of the envelope.
+	PMSigningOrder   *int64
+	PMNotifiedAt     *time.Time
+	PMLastRemindedAt *time.Time
+	PMSignedAt       *time.Time
+
+	// ExpiryPolicy decides what happens to a link that lapses unsigned:
+	// "REGENERATE" renews it and sends it again, "CANCEL" cancels the envelope.
+	ExpiryPolicy string `gorm:"not null;default:'REGENERATE';"`
+	// TokenValidityDays is how long a link stays valid from when its signer
+	// is invited.
+	TokenValidityDays int64 `gorm:"not null;default:7;"`
+	// ReminderIntervalDays is how often a signer who has not signed is
+	// reminded. Zero turns reminders off.
+	ReminderIntervalDays int64 `gorm:"not null;default:2;"`
+
+	// CurrentStep is the signing order being waited on, filled in by the
+	// service. Nil once everyone has signed.
+	CurrentStep *int64 `gorm:"-"`
+
+	CreatedByID string     `gorm:"not null;"`
+	CreatedBy   ClientUser `gorm:"foreignKey:CreatedByID"`
+
+	CompletedAt *time.Time
+	// AuditCertificateUrl is the certificate of every signature, rendered once
+	// the envelope completes; AuditCertificateHash is the SHA-256 of that PDF.
+	AuditCertificateUrl  *string
+	AuditCertificateHash *string
+
+	CancelledAt        *time.Time
+	CancelledByID      *string
+	CancelledBy        *ClientUser `gorm:"foreignKey:CancelledByID"`
+	CancellationReason *string
+}
@@ -22,6 +22,11 @@ type SigningToken struct {
 	DocumentID string `gorm:"not null;"`
 	Document   Document
 
+	// DocumentRevisionID is the revision of the document the signer is shown
+	// and signs. Nil on tokens issued before documents had revisions.
+	DocumentRevisionID *string
+	DocumentRevision   *DocumentRevision
+
 	TenantApplicationID *string
 	TenantApplication   *TenantApplication
 
@@ -31,7 +36,26 @@ type SigningToken struct {
 	LeaseTerminationID *string
 	LeaseTermination   *LeaseTermination
 
-	// Role this token authorizes: "TENANT" | "PM_WITNESS" | "TENANT_WITNESS"
+	LeaseAmendmentID *string
+	LeaseAmendment   *LeaseAmendment
+
+	// SigningEnvelopeID is set when the token is one of an ordered set. Its
+	// signer is only invited, and the token only usable, once SigningOrder
+	// comes up; until then NotifiedAt is nil.
+	SigningEnvelopeID *string `gorm:"index;"`
+	SigningOrder      int64   `gorm:"not null;default:0;"`
+
+	// TenantID names which of the lease's tenants a TENANT token is for, so
+	// each co-tenant signs with their own link. Nil on older tokens, which
+	// stand for the primary.
+	TenantID *string
+	Tenant   *Tenant
+
+	// GuarantorID names the guarantor a GUARANTOR token is for.
+	GuarantorID *string
+	Guarantor   *Guarantor
+
+	// Role this token authorizes: "TENANT" | "GUARANTOR" | "PM_WITNESS" | "TENANT_WITNESS"
 	// Property managers sign via the authenticated portal, not via tokens.
 	Role string `gorm:"not null"`
 
@@ -49,6 +73,18 @@ type SigningToken struct {
 	SignedAt       *time.Time // set when the signee completes signing — prevents re-signing
 	LastAccessedAt *time.Time // updated each time the signee opens the link
 	ExpiresAt      time.Time  `gorm:"not null"` // token expiry, e.g. 7 days from creation
+	NotifiedAt     *time.Time // set when the signee is first sent the link
+	LastRemindedAt *time.Time // set each time the link is sent again
+	CancelledAt    *time.Time // set when the token's envelope is cancelled before it was signed
+
+	// OtpRequired is whether the signer must confirm a one-time code before
+	// the document is shown or signed, filled in by the service from the
+	// client's signing settings.
+	OtpRequired bool `gorm:"-"`
+	// OtpVerifiedAt is when the signer last confirmed a one-time code, sent
+	// by OtpVerifiedVia: "SMS" | "EMAIL".
+	OtpVerifiedAt  *time.Time
+	OtpVerifiedVia *string
 
 	// Links back to the signature record created when this token is used
 	DocumentSignatureID *string
@@ -78,6 +114,13 @@ func (s *SigningToken) BeforeCreate(tx *gorm.DB) error {
 		}
 	}
 
+	if s.LeaseAmendmentID != nil {
+		var amendment LeaseAmendment
+		if err := tx.Select("code").First(&amendment, "id = ?", s.LeaseAmendmentID).Error; err == nil {
+			appCode = amendment.Code
+		}
+	}
+
 	if appCode == "" {
 		return errors.New("unable to determine application code for signing token")
 	}
@@ -101,14 +144,41 @@ func (s *SigningToken) IsUsed() bool {
 	return s.SignedAt != nil
 }
 
+// IsCancelled returns true if the token was withdrawn before it was signed.
+func (s *SigningToken) IsCancelled() bool {
+	return s.CancelledAt != nil
+}
+
+// IsAwaitingTurn returns true if the token belongs to an envelope that has
+// not reached its signer yet.
+func (s *SigningToken) IsAwaitingTurn() bool {
+	return s.SigningEnvelopeID != nil && s.NotifiedAt == nil
+}
+
 // IsValid returns true if the signing link is accessible.
 // A signed token is always accessible (regardless of expiry).
-// An unsigned token is only accessible if it has not expired.
+// An unsigned token is only accessible if it has not expired or been cancelled.
 func (s *SigningToken) IsValid() bool {
 	if s.IsUsed() {
 		return true
 	}
-	return !s.IsExpired()
+	return !s.IsCancelled() && !s.IsExpired()
+}
+
+// SigningStatus summarizes where the token's signer is:
+// "SIGNED" | "CANCELLED" | "WAITING" (for their turn) | "EXPIRED" | "PENDING".
+func (s *SigningToken) SigningStatus() string {
+	switch {
+	case s.IsUsed():
+		return "SIGNED"
+	case s.IsCancelled():
+		return "CANCELLED"
+	case s.IsAwaitingTurn():
+		return "WAITING"
+	case s.IsExpired():
+		return "EXPIRED"
+	}
+	return "PENDING"
 }
 
 // generateSigningToken creates a unique token in the format "{appCode}-{random}".
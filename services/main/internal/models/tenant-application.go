@@ -24,7 +24,8 @@ type TenantApplication struct {
 	CancelledBy   *ClientUser
 
 	// Source tracks how the application was created:
-	// "SELF" = tenant applied themselves, "ADMIN" = landlord created it, "CSV_BULK" = created via CSV/Excel upload
+	// "SELF" = tenant applied themselves, "ADMIN" = landlord created it, "CSV_BULK" = created via CSV/Excel upload,
+	// "VIEWING" = converted from a completed viewing
 	Source *string
 
 	PropertyId *string
@@ -100,6 +101,9 @@ type TenantApplication struct {
 	OccupationAddress *string // or school address
 	ProofOfIncomeUrl  *string // or admission letter url
 
+	// Guarantors move onto the lease when the application is approved.
+	Guarantors []Guarantor `gorm:"foreignKey:TenantApplicationID"`
+
 	CreatedById string
 	CreatedBy   ClientUser
 }
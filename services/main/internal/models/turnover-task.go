@@ -0,0 +1,43 @@
+package models
+
+import "time"
+
+// TurnoverTask is a job that readies a unit for its next occupant once a stay
+// ends: cleaning it, changing the linen, or inspecting it. Completing a
+// booking or a lease generates the unit's tasks, due before the next check-in,
+// and blocks the unit with a TURNOVER UnitDateBlock until none is PENDING or
+// they fall due, whichever is first.
+//
+// Status: PENDING → DONE | CANCELLED
+type TurnoverTask struct {
+	BaseModelSoftDelete
+
+	PropertyID string `gorm:"not null;index;"`
+	Property   Property
+	UnitID     string `gorm:"not null;index;"`
+	Unit       Unit
+
+	// the stay whose end generated the task; exactly one is set
+	BookingID *string `gorm:"index;"`
+	Booking   *Booking
+	LeaseID   *string `gorm:"index;"`
+	Lease     *Lease
+
+	Type   string    `gorm:"not null;"`                         // CLEANING | LINEN | INSPECTION
+	Status string    `gorm:"not null;default:'PENDING';index;"` // PENDING | DONE | CANCELLED
+	DueAt  time.Time `gorm:"not null;index;"`
+
+	AssignedToClientUserID *string `gorm:"index;"`
+	AssignedToClientUser   *ClientUser
+
+	Notes string `gorm:"not null;default:''"`
+
+	CompletedAt             *time.Time
+	CompletedByClientUserID *string
+	CompletedByClientUser   *ClientUser
+
+	CanceledAt         *time.Time
+	CanceledByID       *string
+	CanceledBy         *ClientUser
+	CancellationReason string `gorm:"not null;default:''"`
+}
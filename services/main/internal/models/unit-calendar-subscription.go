@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+// UnitCalendarSubscription pulls a unit's availability from a calendar it is
+// listed on elsewhere, such as its Airbnb or Booking.com iCal export. Each
+// sync mirrors the feed's events as EXTERNAL UnitDateBlocks, so a night booked
+// on another channel cannot be booked here too.
+type UnitCalendarSubscription struct {
+	BaseModelSoftDelete
+
+	UnitID     string `gorm:"not null;index;"`
+	Unit       Unit
+	PropertyID string `gorm:"not null;index;"`
+	Property   Property
+
+	Name string `gorm:"not null;"` // e.g. "Airbnb", "Booking.com"
+	URL  string `gorm:"not null;"`
+
+	LastSyncedAt   *time.Time
+	LastSyncStatus string `gorm:"not null;default:'PENDING'"` // PENDING | SUCCEEDED | FAILED
+	LastSyncError  string `gorm:"not null;default:''"`
+	// LastEventCount is how many current and upcoming events the last
+	// successful sync found in the feed.
+	LastEventCount int64 `gorm:"not null;default:0"`
+
+	CreatedByClientUserID string `gorm:"not null;index;"`
+	CreatedByClientUser   ClientUser
+}
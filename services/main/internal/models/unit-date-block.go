@@ -7,9 +7,14 @@ import "time"
 //
 //	BOOKING     — auto-created when a booking is CONFIRMED
 //	LEASE       — auto-created when a lease is ACTIVATED
+//	TURNOVER    — auto-created when a booking or lease ends; runs until its
+//	              TurnoverTasks fall due, and is lifted early once none is
+//	              PENDING
 //	MAINTENANCE — manually created by a manager
 //	PERSONAL    — manually created by a manager
 //	OTHER       — manually created by a manager
+//	EXTERNAL    — imported from a UnitCalendarSubscription; kept in step with
+//	              the feed by each sync, not edited by hand
 type UnitDateBlock struct {
 	BaseModelSoftDelete
 
@@ -27,6 +32,12 @@ type UnitDateBlock struct {
 	LeaseID *string `gorm:"index;"`
 	Lease   *Lease
 
+	// CalendarSubscriptionID and ExternalUID identify the feed event an
+	// EXTERNAL block mirrors.
+	CalendarSubscriptionID *string `gorm:"index;"`
+	CalendarSubscription   *UnitCalendarSubscription
+	ExternalUID            *string
+
 	Reason string `gorm:"not null;default:''"`
 
 	CreatedByClientUserID *string `gorm:"index;"`
@@ -37,6 +37,16 @@ type Unit struct {
 	Features datatypes.JSON `gorm:"not null;type:jsonb;"` // additional metadata in json format {bedrooms: 2, bathrooms: 1, hasBalcony: true, ...}
 
 	MaxOccupantsAllowed int `gorm:"not null; default:1"` // maximum number of occupants allowed
+
+	// Bookings for more than BaseOccupancy guests are charged ExtraGuestFee
+	// per extra guest per period of the stay. 0 charges no one extra.
+	BaseOccupancy int   `gorm:"not null;default:0"`
+	ExtraGuestFee int64 `gorm:"not null;default:0"` // in smallest currency unit, e.g., pesewas
+
+	// CalendarExportToken is the secret in the unit's public iCal feed URL,
+	// which channels poll for its bookings and blocks. Null until a manager
+	// turns the feed on; replacing it revokes every copy of the old URL.
+	CalendarExportToken *string `gorm:"uniqueIndex;"`
 }
 
 func (u *Unit) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// ViewingSlot is a window in which a staff member can show a property's units
+// to prospects. Each SCHEDULED Viewing booked into it takes one place, up to
+// Capacity; prospects book the places left from the public unit page.
+//
+// Status: OPEN → CANCELLED
+type ViewingSlot struct {
+	BaseModelSoftDelete
+
+	PropertyID string `gorm:"not null;index;"`
+	Property   Property
+
+	// ClientUserID is the staff member who runs the viewings in the slot.
+	ClientUserID string `gorm:"not null;index;"`
+	ClientUser   ClientUser
+
+	StartsAt time.Time `gorm:"not null;index;"`
+	EndsAt   time.Time `gorm:"not null;"`
+	Capacity int64     `gorm:"not null;default:1;"`
+
+	Status string `gorm:"not null;default:'OPEN';index;"` // OPEN | CANCELLED
+
+	CreatedByID string `gorm:"not null;"`
+	CreatedBy   ClientUser
+
+	CanceledAt   *time.Time
+	CanceledByID *string
+	CanceledBy   *ClientUser
+}
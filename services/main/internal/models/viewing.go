@@ -0,0 +1,74 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/getsentry/raven-go"
+	"gorm.io/gorm"
+)
+
+// Viewing is a prospect's booked showing of a unit in one of the property's
+// ViewingSlots. The prospect follows and cancels it by Code. Once the viewing
+// has taken place, staff mark it COMPLETED or NO_SHOW; a COMPLETED viewing can
+// be turned into a TenantApplication for the unit.
+//
+// Status: SCHEDULED → COMPLETED | NO_SHOW | CANCELLED
+type Viewing struct {
+	BaseModelSoftDelete
+	Code string `gorm:"uniqueIndex"`
+
+	PropertyID    string `gorm:"not null;index;"`
+	Property      Property
+	UnitID        string `gorm:"not null;index;"`
+	Unit          Unit
+	ViewingSlotID string `gorm:"not null;index;"`
+	ViewingSlot   ViewingSlot
+
+	// copied from the slot so a staff member's viewings list without a join
+	ClientUserID string `gorm:"not null;index;"`
+	ClientUser   ClientUser
+	ScheduledAt  time.Time `gorm:"not null;index;"`
+	EndsAt       time.Time `gorm:"not null;"`
+
+	FirstName string `gorm:"not null;"`
+	LastName  string `gorm:"not null;"`
+	Email     *string
+	Phone     string `gorm:"not null;index;"`
+	// Message is what the prospect wrote when booking.
+	Message string `gorm:"not null;default:''"`
+
+	Status string `gorm:"not null;default:'SCHEDULED';index;"` // SCHEDULED | COMPLETED | NO_SHOW | CANCELLED
+	Notes  string `gorm:"not null;default:''"`
+
+	CompletedAt   *time.Time
+	CompletedByID *string
+	CompletedBy   *ClientUser
+
+	NoShowAt         *time.Time
+	NoShowMarkedByID *string
+	NoShowMarkedBy   *ClientUser
+
+	// CanceledByID is empty when the prospect cancelled.
+	CanceledAt         *time.Time
+	CanceledByID       *string
+	CanceledBy         *ClientUser
+	CancellationReason string `gorm:"not null;default:''"`
+
+	TenantApplicationID *string
+	TenantApplication   *TenantApplication
+}
+
+func (v *Viewing) BeforeCreate(tx *gorm.DB) error {
+	uniqueCode, genErr := lib.GenerateCode(tx, &Viewing{})
+	if genErr != nil {
+		raven.CaptureError(genErr, map[string]string{
+			"function": "BeforeCreateViewingHook",
+			"action":   "Generating a unique code",
+		})
+		return genErr
+	}
+	v.Code = *uniqueCode
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// WaitlistEntry is a prospect waiting for a unit at a full property: any unit
+// there, a unit type, or one specific unit. When a matching unit becomes
+// available the waiting entries are invited in priority order, one at a time,
+// each with a link to start a TenantApplication that runs out at
+// InviteExpiresAt. An invite that runs out moves on to the next entry.
+//
+// Status: WAITING → INVITED → APPLIED | EXPIRED; WAITING | INVITED → REMOVED
+type WaitlistEntry struct {
+	BaseModelSoftDelete
+
+	PropertyID string `gorm:"not null;index;"`
+	Property   Property
+	UnitType   *string // APARTMENT | HOUSE | STUDIO | OFFICE | RETAIL
+	UnitID     *string `gorm:"index;"`
+	Unit       *Unit
+
+	FirstName string `gorm:"not null;"`
+	LastName  string `gorm:"not null;"`
+	Email     *string
+	Phone     string `gorm:"not null;"`
+
+	// desired move-in window and budget, both optional; the budget is compared
+	// with the unit's rent fee
+	DesiredMoveInFrom *time.Time `gorm:"type:date;"`
+	DesiredMoveInTo   *time.Time `gorm:"type:date;"`
+	MaxRentFee        *int64
+
+	// Priority orders entries ahead of the time they joined; higher goes first.
+	Priority int64  `gorm:"not null;default:0;"`
+	Status   string `gorm:"not null;default:'WAITING';index;"` // WAITING | INVITED | APPLIED | EXPIRED | REMOVED
+	Notes    string `gorm:"not null;default:''"`
+
+	// VacancyNotifiedAt is when the entry was last told a matching unit is
+	// about to come free.
+	VacancyNotifiedAt *time.Time
+
+	InvitedUnitID   *string `gorm:"index;"`
+	InvitedUnit     *Unit
+	InviteToken     *string `gorm:"uniqueIndex;"`
+	InvitedAt       *time.Time
+	InviteExpiresAt *time.Time
+
+	TenantApplicationID *string
+	TenantApplication   *TenantApplication
+	AppliedAt           *time.Time
+
+	RemovedAt   *time.Time
+	RemovedByID *string
+	RemovedBy   *ClientUser
+}
@@ -0,0 +1,108 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/hibiken/asynq"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TypeBookingHoldReminder = "booking:hold-reminder"
+	TypeBookingHoldExpire   = "booking:hold-expire"
+)
+
+// BookingHoldPayload names the hold a task was scheduled for by its expiry,
+// so a task left over from before the hold was extended does nothing.
+type BookingHoldPayload struct {
+	BookingID string    `json:"booking_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func bookingHoldReminderTaskID(bookingID string) string {
+	return TypeBookingHoldReminder + ":" + bookingID
+}
+
+func bookingHoldExpireTaskID(bookingID string) string {
+	return TypeBookingHoldExpire + ":" + bookingID
+}
+
+func (c *Client) ScheduleBookingHold(ctx context.Context, bookingID string, remindAt, expiresAt time.Time) error {
+	for _, taskID := range []string{bookingHoldReminderTaskID(bookingID), bookingHoldExpireTaskID(bookingID)} {
+		if err := c.inspector.DeleteTask("default", taskID); err != nil && !errors.Is(err, asynq.ErrTaskNotFound) {
+			return fmt.Errorf("queue: remove old booking hold task: %w", err)
+		}
+	}
+
+	payload, err := json.Marshal(BookingHoldPayload{BookingID: bookingID, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+
+	if remindAt.After(time.Now()) {
+		if _, err := c.c.EnqueueContext(ctx,
+			asynq.NewTask(TypeBookingHoldReminder, payload),
+			asynq.ProcessAt(remindAt),
+			asynq.MaxRetry(1),
+			asynq.TaskID(bookingHoldReminderTaskID(bookingID)),
+		); err != nil {
+			return err
+		}
+	}
+
+	_, err = c.c.EnqueueContext(ctx,
+		asynq.NewTask(TypeBookingHoldExpire, payload),
+		asynq.ProcessAt(expiresAt),
+		asynq.MaxRetry(3),
+		asynq.TaskID(bookingHoldExpireTaskID(bookingID)),
+	)
+	return err
+}
+
+func BookingHoldHandlers(svc services.BookingService) HandlerRegistrar {
+	return func(mux *asynq.ServeMux) {
+		mux.HandleFunc(TypeBookingHoldReminder, handleBookingHoldReminder(svc))
+		mux.HandleFunc(TypeBookingHoldExpire, handleBookingHoldExpire(svc))
+	}
+}
+
+func handleBookingHoldReminder(svc services.BookingService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var p BookingHoldPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("%w: %w", asynq.SkipRetry, err)
+		}
+
+		return skipHandledBookingHold(svc.RemindBookingHold(ctx, p.BookingID, p.ExpiresAt), p.BookingID)
+	}
+}
+
+func handleBookingHoldExpire(svc services.BookingService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var p BookingHoldPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("%w: %w", asynq.SkipRetry, err)
+		}
+
+		return skipHandledBookingHold(svc.ExpireBookingHold(ctx, p.BookingID, p.ExpiresAt), p.BookingID)
+	}
+}
+
+// skipHandledBookingHold drops a task for a booking that has been deleted;
+// any other error is retried.
+func skipHandledBookingHold(err error, bookingID string) error {
+	var rlErr *pkg.IRentLoopError
+	if errors.As(err, &rlErr) && rlErr.Code == http.StatusNotFound {
+		log.WithError(err).WithField("booking_id", bookingID).
+			Warn("[Queue] skipping booking hold task — booking no longer exists")
+		return nil
+	}
+	return err
+}
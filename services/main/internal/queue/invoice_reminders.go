@@ -3,12 +3,14 @@ package queue
 import (
 	"context"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
 	"github.com/Bendomey/rent-loop/services/main/internal/services"
 	"github.com/Bendomey/rent-loop/services/main/pkg"
@@ -65,13 +67,7 @@ func handleInvoiceReminder(
 				continue
 			}
 
-			due := invoice.DueDate
-			dueLoc := due.Location()
-			now := time.Now().In(dueLoc)
-			// Normalize to calendar dates (start of day) in the due date's location.
-			nowDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, dueLoc)
-			dueDate := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, dueLoc)
-			daysUntilDue := int(dueDate.Sub(nowDate).Hours() / 24)
+			daysUntilDue := invoiceDaysUntilDue(*invoice.DueDate, time.Now())
 
 			var reminderKey string
 			var subject, emailTemplateName, smsBodyTemplate string
@@ -207,10 +203,138 @@ func handleInvoiceReminder(
 		}
 
 		log.Infof("[Cron] invoice reminders complete: %d sent, %d failed", successCount, failCount)
+
+		// Guarantors are chased after the tenant's reminders have been
+		// recorded, from a fresh read, so neither pass overwrites what the
+		// other added to reminders_sent.
+		overdue, err := repo.ListForGuarantorEscalation(ctx)
+		if err != nil {
+			log.WithError(err).Error("[Cron] failed to list invoices for guarantor escalation")
+			return err
+		}
+		for i := range *overdue {
+			invoice := &(*overdue)[i]
+			escalateToGuarantors(ctx, repo, appCtx, invoice, -invoiceDaysUntilDue(*invoice.DueDate, time.Now()))
+		}
+
 		return nil
 	}
 }
 
+// invoiceDaysUntilDue is how many calendar days from now the due date is, in
+// the due date's location; negative once it has passed.
+func invoiceDaysUntilDue(due, now time.Time) int {
+	dueLoc := due.Location()
+	now = now.In(dueLoc)
+	nowDate := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, dueLoc)
+	dueDate := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, dueLoc)
+	return int(dueDate.Sub(nowDate).Hours() / 24)
+}
+
+// escalateToGuarantors tells each guarantor of the invoice's lease whose
+// escalation threshold has passed, once per invoice. It runs after the
+// tenant's reminders rather than instead of them, and records what it sent
+// itself so a failure in the tenant's reminder cannot make it repeat.
+func escalateToGuarantors(
+	ctx context.Context,
+	repo repository.InvoiceRepository,
+	appCtx pkg.AppContext,
+	invoice *models.Invoice,
+	daysPastDue int,
+) {
+	if invoice.PayerLease == nil || len(invoice.PayerLease.Guarantors) == 0 {
+		return
+	}
+
+	lease := invoice.PayerLease
+	amount := lib.FormatAmount(lib.PesewasToCedis(int64(invoice.TotalAmount)))
+	dueDate := invoice.DueDate.Format("2 Jan 2006")
+
+	escalated := false
+	for _, guarantor := range lease.Guarantors {
+		key := guarantor.EscalationReminderKey()
+		if !guarantor.EscalationDue(daysPastDue) || slices.Contains(invoice.RemindersSent, key) {
+			continue
+		}
+
+		liabilityCap := ""
+		if guarantor.LiabilityCap != nil && guarantor.LiabilityCapCurrency != nil {
+			liabilityCap = *guarantor.LiabilityCapCurrency + " " +
+				lib.FormatAmount(lib.PesewasToCedis(*guarantor.LiabilityCap))
+		}
+
+		smsMessage := strings.NewReplacer(
+			"{{guarantor_name}}", guarantor.FirstName,
+			"{{tenant_name}}", lease.Tenant.FirstName,
+			"{{unit_name}}", lease.Unit.Name,
+			"{{days_overdue}}", strconv.Itoa(daysPastDue),
+			"{{invoice_code}}", invoice.Code,
+			"{{currency}}", invoice.Currency,
+			"{{amount}}", amount,
+		).Replace(lib.INVOICE_GUARANTOR_ESCALATION_SMS_BODY)
+
+		channelSucceeded := false
+
+		if guarantor.Email != nil {
+			htmlBody, textBody, renderErr := appCtx.EmailEngine.Render(
+				"invoice/guarantor-escalation",
+				emailtemplates.GuarantorEscalationData{
+					GuarantorName: guarantor.FirstName,
+					TenantName:    lease.Tenant.FirstName,
+					InvoiceCode:   invoice.Code,
+					UnitName:      lease.Unit.Name,
+					Currency:      invoice.Currency,
+					Amount:        amount,
+					DueDate:       dueDate,
+					DaysOverdue:   daysPastDue,
+					LiabilityCap:  liabilityCap,
+				},
+			)
+			if renderErr != nil {
+				log.WithError(renderErr).
+					WithField("invoice_id", invoice.ID.String()).
+					Error("[Cron] failed to render guarantor escalation email template")
+			} else if err := pkg.SendEmail(appCtx.Config, pkg.SendEmailInput{
+				Recipient: *guarantor.Email,
+				Subject:   lib.INVOICE_GUARANTOR_ESCALATION_SUBJECT,
+				HtmlBody:  htmlBody,
+				TextBody:  textBody,
+			}); err != nil {
+				log.WithError(err).
+					WithField("invoice_id", invoice.ID.String()).
+					Error("[Cron] failed to send guarantor escalation email")
+			} else {
+				channelSucceeded = true
+			}
+		}
+
+		if err := appCtx.Clients.GatekeeperAPI.SendSMS(ctx, gatekeeper.SendSMSInput{
+			Recipient: guarantor.Phone,
+			Message:   smsMessage,
+		}); err != nil {
+			log.WithError(err).
+				WithField("invoice_id", invoice.ID.String()).
+				Error("[Cron] failed to send guarantor escalation SMS")
+		} else {
+			channelSucceeded = true
+		}
+
+		if channelSucceeded {
+			invoice.RemindersSent = append(invoice.RemindersSent, key)
+			escalated = true
+		}
+	}
+
+	if !escalated {
+		return
+	}
+
+	if err := repo.Update(ctx, invoice); err != nil {
+		log.WithError(err).WithField("invoice_id", invoice.ID.String()).
+			Error("[Cron] failed to record guarantor escalation")
+	}
+}
+
 func overdueTemplate(key string) (subject, emailTemplateName, smsBody string) {
 	switch key {
 	case "overdue_1d":
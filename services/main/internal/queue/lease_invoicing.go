@@ -2,6 +2,8 @@ package queue
 
 import (
 	"context"
+	"errors"
+	"strconv"
 	"time"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/services/financials"
@@ -19,18 +21,67 @@ import (
 // candidates and the sweep resumes at the right period with nothing to cancel.
 const TypeFinancialAccountInvoiceIssuance = "financial-account:invoice-issuance"
 
+// TypeShortCadenceInvoiceIssuance bills hourly and daily accounts at their own
+// granularity. It is not a cron: each run enqueues the next one for the moment
+// the earliest pending short-cadence charge enters its lead window, so it
+// wakes when there is work rather than every minute.
+const TypeShortCadenceInvoiceIssuance = "financial-account:short-cadence-issuance"
+
+// shortCadenceMaxSleep caps how long the chain sleeps when nothing is pending.
+// A lease signed mid-afternoon materialises charges the chain has not seen;
+// this bounds how late its first hourly invoice can be.
+const shortCadenceMaxSleep = 15 * time.Minute
+
+// ─── Deterministic task IDs ───────────────────────────────────────────────────
+
+// shortCadenceTaskID keys the task on its wake-up minute. Every path that arms
+// the chain — boot, the midnight sweep, the chain itself — computes the same
+// ID for the same minute, so they collapse into one task instead of forking
+// parallel chains.
+func shortCadenceTaskID(at time.Time) string {
+	return TypeShortCadenceInvoiceIssuance + ":" + strconv.FormatInt(at.Unix(), 10)
+}
+
+// ─── Client methods ───────────────────────────────────────────────────────────
+
+// EnqueueShortCadenceIssuance schedules the short-cadence sweep for the first
+// whole minute at or after `at`. Scheduling a minute that is already taken is
+// not an error: the chain is armed either way.
+func (c *Client) EnqueueShortCadenceIssuance(ctx context.Context, at time.Time) error {
+	wake := at.Truncate(time.Minute)
+	if wake.Before(at) {
+		wake = wake.Add(time.Minute)
+	}
+
+	_, err := c.c.EnqueueContext(ctx,
+		asynq.NewTask(TypeShortCadenceInvoiceIssuance, nil),
+		asynq.ProcessAt(wake),
+		asynq.MaxRetry(0),
+		asynq.TaskID(shortCadenceTaskID(wake)),
+	)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
 // ─── Worker handlers ──────────────────────────────────────────────────────────
 
 // FinancialAccountInvoicingHandlers returns a HandlerRegistrar that wires up
-// the invoice issuance sweep onto the serve mux.
-func FinancialAccountInvoicingHandlers(svc financials.IssuanceService) HandlerRegistrar {
+// the invoice issuance sweeps onto the serve mux.
+func FinancialAccountInvoicingHandlers(svc financials.IssuanceService, client *Client) HandlerRegistrar {
 	return func(mux *asynq.ServeMux) {
-		mux.HandleFunc(TypeFinancialAccountInvoiceIssuance, handleInvoiceIssuance(svc))
+		mux.HandleFunc(TypeFinancialAccountInvoiceIssuance, handleInvoiceIssuance(svc, client))
+		mux.HandleFunc(TypeShortCadenceInvoiceIssuance, handleShortCadenceInvoiceIssuance(svc, client))
 	}
 }
 
-func handleInvoiceIssuance(svc financials.IssuanceService) asynq.HandlerFunc {
+func handleInvoiceIssuance(svc financials.IssuanceService, client *Client) asynq.HandlerFunc {
 	return func(ctx context.Context, t *asynq.Task) error {
+		// Re-arm the short-cadence chain first. It is normally already armed;
+		// this is what brings it back if a run was lost to a Redis restart.
+		ArmShortCadenceIssuance(ctx, svc, client, time.Now())
+
 		issued, failed, err := svc.IssueDueInvoices(ctx, time.Now())
 		if err != nil {
 			log.WithError(err).Error("[Cron] invoice issuance sweep failed")
@@ -41,3 +92,48 @@ func handleInvoiceIssuance(svc financials.IssuanceService) asynq.HandlerFunc {
 		return nil
 	}
 }
+
+func handleShortCadenceInvoiceIssuance(svc financials.IssuanceService, client *Client) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		now := time.Now()
+
+		issued, failed, err := svc.IssueDueShortCadenceInvoices(ctx, now)
+
+		// Schedule the next run whatever happened to this one. The task does
+		// not retry; the chain is its own retry, and a failure must not stop it.
+		ArmShortCadenceIssuance(ctx, svc, client, now)
+
+		if err != nil {
+			log.WithError(err).Error("[Cron] short-cadence invoice issuance failed")
+			return err
+		}
+
+		if issued > 0 || failed > 0 {
+			log.Infof("[Cron] short-cadence invoice issuance complete: %d issued, %d failed", issued, failed)
+		}
+		return nil
+	}
+}
+
+// ArmShortCadenceIssuance enqueues the next short-cadence run: when the
+// earliest pending hourly or daily charge enters its lead window, never sooner
+// than a minute from now and never later than shortCadenceMaxSleep. Failures
+// are logged rather than returned; the midnight sweep re-arms the chain.
+func ArmShortCadenceIssuance(ctx context.Context, svc financials.IssuanceService, client *Client, now time.Time) {
+	wake := now.Add(shortCadenceMaxSleep)
+
+	next, err := svc.NextShortCadenceIssuanceAt(ctx, now)
+	if err != nil {
+		log.WithError(err).Error("[Cron] failed to find next short-cadence issuance")
+	} else if next != nil && next.Before(wake) {
+		wake = *next
+	}
+
+	if earliest := now.Add(time.Minute); wake.Before(earliest) {
+		wake = earliest
+	}
+
+	if err := client.EnqueueShortCadenceIssuance(ctx, wake); err != nil {
+		log.WithError(err).Error("[Cron] failed to schedule short-cadence issuance")
+	}
+}
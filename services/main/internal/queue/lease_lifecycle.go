@@ -217,12 +217,32 @@ func sendMoveOutReminder(
 	moveOutDateStr := lease.MoveOutDate.Format("2 Jan 2006")
 	daysStr := fmt.Sprintf("%d", daysUntilMoveOut)
 
+	// Both sides are told what happens at move-out, so a tenant on a holdover
+	// property is not told to pack and a manager is not surprised by a unit
+	// that stays occupied. A failed lookup falls back to the completion
+	// wording rather than skipping the reminder.
+	outcome, outcomeErr := deps.leaseService.ResolveMoveOutOutcome(ctx, lease)
+	if outcomeErr != nil {
+		log.WithError(outcomeErr).WithField("lease_id", lease.ID.String()).
+			Error("[Cron] failed to resolve move-out outcome for reminder")
+	}
+
+	rentStr := lib.FormatAmount(lib.PesewasToCedis(outcome.Rent))
+	holdoverRent := ""
+	smsBody := lib.LEASE_MOVEOUT_REMINDER_SMS_BODY
+	if outcome.HoldsOver {
+		holdoverRent = fmt.Sprintf("%s %s", lease.RentFeeCurrency, rentStr)
+		smsBody = lib.LEASE_MOVEOUT_REMINDER_HOLDOVER_SMS_BODY
+	}
+
 	smsMessage := strings.NewReplacer(
 		"{{tenant_name}}", lease.Tenant.FirstName,
 		"{{unit_name}}", unitName,
 		"{{days_remaining}}", daysStr,
 		"{{move_out_date}}", moveOutDateStr,
-	).Replace(lib.LEASE_MOVEOUT_REMINDER_SMS_BODY)
+		"{{currency}}", lease.RentFeeCurrency,
+		"{{rent}}", rentStr,
+	).Replace(smsBody)
 
 	if lease.Tenant.Email != nil {
 		htmlBody, textBody, renderErr := deps.appCtx.EmailEngine.Render(
@@ -232,6 +252,8 @@ func sendMoveOutReminder(
 				UnitName:      unitName,
 				DaysRemaining: daysUntilMoveOut,
 				MoveOutDate:   moveOutDateStr,
+				HoldsOver:     outcome.HoldsOver,
+				HoldoverRent:  holdoverRent,
 			},
 		)
 		if renderErr != nil {
@@ -308,6 +330,8 @@ func sendMoveOutReminder(
 				DaysRemaining:       daysUntilMoveOut,
 				MoveOutDate:         moveOutDateStr,
 				MoveOutReportStatus: moveOutReportStatus,
+				HoldsOver:           outcome.HoldsOver,
+				HoldoverRent:        holdoverRent,
 			},
 		)
 		if renderErr != nil {
@@ -338,13 +362,16 @@ func sendMoveOutReminder(
 // reachable from the dev trigger without a handler touching a repository.
 func handleLeaseCompletion(deps leaseLifecycleDeps) asynq.HandlerFunc {
 	return func(ctx context.Context, t *asynq.Task) error {
-		completed, failed, err := deps.leaseService.CompleteDueLeases(ctx, "")
+		completed, heldOver, failed, err := deps.leaseService.CompleteDueLeases(ctx, "")
 		if err != nil {
 			log.WithError(err).Error("[Cron] failed to list leases due for completion")
 			return err
 		}
 
-		log.Infof("[Cron] lease completion complete: %d completed, %d failed", completed, failed)
+		log.Infof(
+			"[Cron] lease completion complete: %d completed, %d held over, %d failed",
+			completed, heldOver, failed,
+		)
 		return nil
 	}
 }
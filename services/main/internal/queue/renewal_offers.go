@@ -0,0 +1,35 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/hibiken/asynq"
+	log "github.com/sirupsen/logrus"
+)
+
+const TypeRenewalOfferExpiry = "lease:renewal-offer-expire"
+
+func RenewalOfferHandlers(svc services.RenewalOfferService) HandlerRegistrar {
+	return func(mux *asynq.ServeMux) {
+		mux.HandleFunc(TypeRenewalOfferExpiry, handleRenewalOfferExpiry(svc))
+	}
+}
+
+// handleRenewalOfferExpiry closes offers the tenant let lapse, so the PM's
+// list shows them for what they are and a new offer can be sent. Answering an
+// offer checks expiry itself, so nothing depends on this running on time.
+func handleRenewalOfferExpiry(svc services.RenewalOfferService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		expired, err := svc.ExpireDueOffers(ctx)
+		if err != nil {
+			log.WithError(err).Error("[Cron] renewal offer expiry sweep failed")
+
+			return err
+		}
+
+		log.WithField("expired", expired).Info("[Cron] renewal offer expiry sweep complete")
+
+		return nil
+	}
+}
@@ -0,0 +1,41 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/hibiken/asynq"
+	log "github.com/sirupsen/logrus"
+)
+
+const TypeSigningEnvelopeSweep = "document:signing-envelope-sweep"
+
+func SigningEnvelopeHandlers(svc services.SigningService) HandlerRegistrar {
+	return func(mux *asynq.ServeMux) {
+		mux.HandleFunc(TypeSigningEnvelopeSweep, handleSigningEnvelopeSweep(svc))
+	}
+}
+
+// handleSigningEnvelopeSweep reminds signers who are holding up an envelope
+// and renews or cancels links that lapsed unsigned. Moving an envelope on to
+// its next step happens as each signature lands; the sweep only catches a
+// step whose invitations failed to go out.
+func handleSigningEnvelopeSweep(svc services.SigningService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		result, err := svc.SweepSigningEnvelopes(ctx, time.Now())
+		if err != nil {
+			log.WithError(err).Error("[Cron] signing envelope sweep failed")
+
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"reminded":  result.Reminded,
+			"renewed":   result.Renewed,
+			"cancelled": result.Cancelled,
+		}).Info("[Cron] signing envelope sweep complete")
+
+		return nil
+	}
+}
@@ -0,0 +1,33 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/hibiken/asynq"
+	log "github.com/sirupsen/logrus"
+)
+
+const TypeTurnoverOverdueAlert = "turnover:overdue-alert"
+
+func TurnoverTaskHandlers(svc services.TurnoverTaskService) HandlerRegistrar {
+	return func(mux *asynq.ServeMux) {
+		mux.HandleFunc(TypeTurnoverOverdueAlert, handleTurnoverOverdueAlert(svc))
+	}
+}
+
+// handleTurnoverOverdueAlert tells managers about the tasks that fell due in
+// the hour before the one this run starts in, so each task is alerted on once.
+func handleTurnoverOverdueAlert(svc services.TurnoverTaskService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		until := time.Now().UTC().Truncate(time.Hour)
+		if err := svc.AlertOverdueTurnoverTasks(ctx, until.Add(-time.Hour), until); err != nil {
+			log.WithError(err).Error("[Cron] overdue turnover alert failed")
+
+			return err
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,38 @@
+package queue
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/hibiken/asynq"
+	log "github.com/sirupsen/logrus"
+)
+
+const TypeUnitCalendarSync = "booking:unit-calendar-sync"
+
+func UnitCalendarSyncHandlers(svc services.UnitCalendarService) HandlerRegistrar {
+	return func(mux *asynq.ServeMux) {
+		mux.HandleFunc(TypeUnitCalendarSync, handleUnitCalendarSync(svc))
+	}
+}
+
+// handleUnitCalendarSync re-reads every imported calendar. A feed that cannot
+// be read is recorded on its subscription and does not fail the task, so one
+// broken channel does not hold up the rest.
+func handleUnitCalendarSync(svc services.UnitCalendarService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		result, err := svc.SyncAllSubscriptions(ctx)
+		if err != nil {
+			log.WithError(err).Error("[Cron] unit calendar sync failed")
+
+			return err
+		}
+
+		log.WithFields(log.Fields{
+			"synced": result.Synced,
+			"failed": result.Failed,
+		}).Info("[Cron] unit calendar sync complete")
+
+		return nil
+	}
+}
@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/hibiken/asynq"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TypeViewingConfirmation = "viewing:confirmation"
+	TypeViewingReminder     = "viewing:reminder"
+)
+
+// ViewingPayload names the viewing a task is for. A reminder also carries the
+// time it was scheduled for, so a reminder for a viewing that no longer takes
+// place then does nothing.
+type ViewingPayload struct {
+	ViewingID   string    `json:"viewing_id"`
+	ScheduledAt time.Time `json:"scheduled_at,omitempty"`
+}
+
+func (c *Client) EnqueueViewingConfirmation(ctx context.Context, viewingID string) error {
+	payload, err := json.Marshal(ViewingPayload{ViewingID: viewingID})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.c.EnqueueContext(ctx,
+		asynq.NewTask(TypeViewingConfirmation, payload),
+		asynq.MaxRetry(3),
+	)
+	return err
+}
+
+func (c *Client) ScheduleViewingReminder(ctx context.Context, viewingID string, remindAt, scheduledAt time.Time) error {
+	payload, err := json.Marshal(ViewingPayload{ViewingID: viewingID, ScheduledAt: scheduledAt})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.c.EnqueueContext(ctx,
+		asynq.NewTask(TypeViewingReminder, payload),
+		asynq.ProcessAt(remindAt),
+		asynq.MaxRetry(1),
+		asynq.TaskID(TypeViewingReminder+":"+viewingID),
+	)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+func ViewingHandlers(svc services.ViewingService) HandlerRegistrar {
+	return func(mux *asynq.ServeMux) {
+		mux.HandleFunc(TypeViewingConfirmation, handleViewingConfirmation(svc))
+		mux.HandleFunc(TypeViewingReminder, handleViewingReminder(svc))
+	}
+}
+
+func handleViewingConfirmation(svc services.ViewingService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var p ViewingPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("%w: %w", asynq.SkipRetry, err)
+		}
+
+		return skipGoneViewing(svc.SendViewingConfirmation(ctx, p.ViewingID), p.ViewingID)
+	}
+}
+
+func handleViewingReminder(svc services.ViewingService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var p ViewingPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("%w: %w", asynq.SkipRetry, err)
+		}
+
+		return skipGoneViewing(svc.RemindViewing(ctx, p.ViewingID, p.ScheduledAt), p.ViewingID)
+	}
+}
+
+// skipGoneViewing drops a task for a viewing that has been deleted; any
+// other error is retried.
+func skipGoneViewing(err error, viewingID string) error {
+	var rlErr *pkg.IRentLoopError
+	if errors.As(err, &rlErr) && rlErr.Code == http.StatusNotFound {
+		log.WithError(err).WithField("viewing_id", viewingID).
+			Warn("[Queue] skipping viewing task — viewing no longer exists")
+		return nil
+	}
+	return err
+}
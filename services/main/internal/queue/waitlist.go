@@ -0,0 +1,142 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/hibiken/asynq"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	TypeWaitlistUnitReleased = "waitlist:unit-released"
+	TypeWaitlistUnitVacating = "waitlist:unit-vacating"
+	TypeWaitlistInviteExpire = "waitlist:invite-expire"
+)
+
+// waitlistUnitReleasedDelay holds back inviting the waitlist to a unit that
+// has just become available. The status is often set inside the caller's
+// transaction, and the task must not read the unit before it commits.
+const waitlistUnitReleasedDelay = time.Minute
+
+type WaitlistUnitPayload struct {
+	UnitID        string    `json:"unit_id"`
+	AvailableFrom time.Time `json:"available_from"`
+}
+
+// WaitlistInvitePayload names the invite a task was scheduled for by its
+// expiry, so a task left over from an earlier invite does nothing.
+type WaitlistInvitePayload struct {
+	EntryID   string    `json:"entry_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (c *Client) EnqueueWaitlistUnitReleased(ctx context.Context, unitID string) error {
+	payload, err := json.Marshal(WaitlistUnitPayload{UnitID: unitID})
+	if err != nil {
+		return err
+	}
+
+	// One task per unit at a time: a unit flipping status twice in the delay
+	// still invites once.
+	_, err = c.c.EnqueueContext(ctx,
+		asynq.NewTask(TypeWaitlistUnitReleased, payload),
+		asynq.ProcessIn(waitlistUnitReleasedDelay),
+		asynq.MaxRetry(3),
+		asynq.TaskID(TypeWaitlistUnitReleased+":"+unitID),
+	)
+	if errors.Is(err, asynq.ErrTaskIDConflict) {
+		return nil
+	}
+	return err
+}
+
+func (c *Client) EnqueueWaitlistUnitVacating(ctx context.Context, unitID string, availableFrom time.Time) error {
+	payload, err := json.Marshal(WaitlistUnitPayload{UnitID: unitID, AvailableFrom: availableFrom})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.c.EnqueueContext(ctx,
+		asynq.NewTask(TypeWaitlistUnitVacating, payload),
+		asynq.MaxRetry(3),
+	)
+	return err
+}
+
+func (c *Client) ScheduleWaitlistInviteExpiry(ctx context.Context, entryID string, expiresAt time.Time) error {
+	payload, err := json.Marshal(WaitlistInvitePayload{EntryID: entryID, ExpiresAt: expiresAt})
+	if err != nil {
+		return err
+	}
+
+	_, err = c.c.EnqueueContext(ctx,
+		asynq.NewTask(TypeWaitlistInviteExpire, payload),
+		asynq.ProcessAt(expiresAt),
+		asynq.MaxRetry(3),
+	)
+	return err
+}
+
+func WaitlistHandlers(svc services.WaitlistService) HandlerRegistrar {
+	return func(mux *asynq.ServeMux) {
+		mux.HandleFunc(TypeWaitlistUnitReleased, handleWaitlistUnitReleased(svc))
+		mux.HandleFunc(TypeWaitlistUnitVacating, handleWaitlistUnitVacating(svc))
+		mux.HandleFunc(TypeWaitlistInviteExpire, handleWaitlistInviteExpire(svc))
+	}
+}
+
+func handleWaitlistUnitReleased(svc services.WaitlistService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var p WaitlistUnitPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("%w: %w", asynq.SkipRetry, err)
+		}
+
+		return skipGoneWaitlistTask(svc.InviteNextForUnit(ctx, p.UnitID), "unit_id", p.UnitID)
+	}
+}
+
+func handleWaitlistUnitVacating(svc services.WaitlistService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var p WaitlistUnitPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("%w: %w", asynq.SkipRetry, err)
+		}
+
+		return skipGoneWaitlistTask(
+			svc.NotifyUnitVacating(ctx, p.UnitID, p.AvailableFrom),
+			"unit_id",
+			p.UnitID,
+		)
+	}
+}
+
+func handleWaitlistInviteExpire(svc services.WaitlistService) asynq.HandlerFunc {
+	return func(ctx context.Context, t *asynq.Task) error {
+		var p WaitlistInvitePayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("%w: %w", asynq.SkipRetry, err)
+		}
+
+		return skipGoneWaitlistTask(svc.ExpireInvite(ctx, p.EntryID, p.ExpiresAt), "entry_id", p.EntryID)
+	}
+}
+
+// skipGoneWaitlistTask drops a task for a unit or entry that has been
+// deleted; any other error is retried.
+func skipGoneWaitlistTask(err error, field, id string) error {
+	var rlErr *pkg.IRentLoopError
+	if errors.As(err, &rlErr) && rlErr.Code == http.StatusNotFound {
+		log.WithError(err).WithField(field, id).
+			Warn("[Queue] skipping waitlist task — record no longer exists")
+		return nil
+	}
+	return err
+}
@@ -46,7 +46,13 @@ func NewServeMux(registrars ...HandlerRegistrar) *asynq.ServeMux {
 	return mux
 }
 
-func RegisterWorkers(redisURL string, appCtx pkg.AppContext, repo repository.Repository, svcs services.Services) {
+func RegisterWorkers(
+	redisURL string,
+	queueClient *Client,
+	appCtx pkg.AppContext,
+	repo repository.Repository,
+	svcs services.Services,
+) {
 	queueServer, err := NewServer(redisURL)
 	if err != nil {
 		raven.CaptureError(err, nil)
@@ -56,10 +62,17 @@ func RegisterWorkers(redisURL string, appCtx pkg.AppContext, repo repository.Rep
 	go func() {
 		mux := NewServeMux(
 			AnnouncementHandlers(svcs.AnnouncementService),
-			FinancialAccountInvoicingHandlers(svcs.Financials.Issuance),
+			FinancialAccountInvoicingHandlers(svcs.Financials.Issuance, queueClient),
 			InvoiceReminderHandlers(repo.InvoiceRepository, appCtx, svcs.NotificationService),
 			ForexSyncHandlers(svcs.ExchangeRateService),
 			AccountClosureHandlers(svcs.Financials.Closure),
+			RenewalOfferHandlers(svcs.RenewalOfferService),
+			SigningEnvelopeHandlers(svcs.SigningService),
+			UnitCalendarSyncHandlers(svcs.UnitCalendarService),
+			BookingHoldHandlers(svcs.BookingService),
+			WaitlistHandlers(svcs.WaitlistService),
+			ViewingHandlers(svcs.ViewingService),
+			TurnoverTaskHandlers(svcs.TurnoverTaskService),
 			LeaseLifecycleHandlers(
 				repo.LeaseRepository,
 				repo.LeaseChecklistRepository,
@@ -74,6 +87,11 @@ func RegisterWorkers(redisURL string, appCtx pkg.AppContext, repo repository.Rep
 		}
 	}()
 
+	// The short-cadence issuance chain schedules itself after each run, so it
+	// only needs arming once per boot. Deterministic task IDs make this a
+	// no-op when the chain is already pending.
+	ArmShortCadenceIssuance(context.Background(), svcs.Financials.Issuance, queueClient, time.Now())
+
 	log.Info("Queue worker started")
 }
 
@@ -86,10 +104,12 @@ func RegisterScheduler(redisURL string) {
 
 	scheduler := asynq.NewScheduler(opt, &asynq.SchedulerOpts{Location: time.UTC})
 
-	// TODO:  Hourly — catches Hourly leases on time;(bring this back when our redis resources support it)
-	// Every day at midnight. Accounts with nothing due inside their lead
-	// window are skipped naturally — the sweep reads charge state rather than
-	// a cursor, so running it more often than necessary is harmless.
+	// Every day at midnight. Hourly and daily accounts are billed on time by
+	// the self-scheduling TypeShortCadenceInvoiceIssuance chain, which this
+	// sweep also re-arms; this one catches everything else. Accounts with
+	// nothing due inside their lead window are skipped naturally — the sweep
+	// reads charge state rather than a cursor, so running it more often than
+	// necessary is harmless.
 	if _, err = scheduler.Register(
 		"0 0 * * *",
 		asynq.NewTask(TypeFinancialAccountInvoiceIssuance, nil),
@@ -162,6 +182,50 @@ func RegisterScheduler(redisURL string) {
 		log.Fatal("failed to register account closure schedule:", err)
 	}
 
+	// Hourly — an offer's expiry is a moment, not a day, and the PM cannot send
+	// a new offer until the lapsed one is closed.
+	if _, err = scheduler.Register(
+		"0 * * * *",
+		asynq.NewTask(TypeRenewalOfferExpiry, nil),
+		asynq.MaxRetry(1),
+	); err != nil {
+		raven.CaptureError(err, nil)
+		log.Fatal("failed to register renewal offer expiry schedule:", err)
+	}
+
+	// Hourly — reminder intervals and link expiries are counted from when each
+	// signer was invited, so they come due at any hour of the day.
+	if _, err = scheduler.Register(
+		"0 * * * *",
+		asynq.NewTask(TypeSigningEnvelopeSweep, nil),
+		asynq.MaxRetry(1),
+	); err != nil {
+		raven.CaptureError(err, nil)
+		log.Fatal("failed to register signing envelope sweep schedule:", err)
+	}
+
+	// Hourly — channels take a booking at any hour, and until its dates are
+	// imported here they can still be booked twice.
+	if _, err = scheduler.Register(
+		"0 * * * *",
+		asynq.NewTask(TypeUnitCalendarSync, nil),
+		asynq.MaxRetry(1),
+	); err != nil {
+		raven.CaptureError(err, nil)
+		log.Fatal("failed to register unit calendar sync schedule:", err)
+	}
+
+	// Hourly — turnover tasks fall due at any hour, and once one has, its unit
+	// can be booked again whether or not it is ready.
+	if _, err = scheduler.Register(
+		"0 * * * *",
+		asynq.NewTask(TypeTurnoverOverdueAlert, nil),
+		asynq.MaxRetry(1),
+	); err != nil {
+		raven.CaptureError(err, nil)
+		log.Fatal("failed to register overdue turnover alert schedule:", err)
+	}
+
 	go func() {
 		if err := scheduler.Run(); err != nil {
 			raven.CaptureError(err, nil)
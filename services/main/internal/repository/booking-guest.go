@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type BookingGuestRepository interface {
+	// ReplaceForBooking removes the booking's registered guests and registers
+	// guests in their place. Must be called within a transaction context.
+	ReplaceForBooking(ctx context.Context, bookingID string, guests *[]models.BookingGuest) error
+	// ListByBookingID returns the booking's guests in the order they were
+	// registered.
+	ListByBookingID(ctx context.Context, bookingID string) ([]models.BookingGuest, error)
+	CountByBookingID(ctx context.Context, bookingID string) (int64, error)
+}
+
+type bookingGuestRepository struct {
+	DB *gorm.DB
+}
+
+func NewBookingGuestRepository(db *gorm.DB) BookingGuestRepository {
+	return &bookingGuestRepository{DB: db}
+}
+
+func (r *bookingGuestRepository) ReplaceForBooking(
+	ctx context.Context,
+	bookingID string,
+	guests *[]models.BookingGuest,
+) error {
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx)
+
+	if err := db.Where("booking_id = ?", bookingID).Delete(&models.BookingGuest{}).Error; err != nil {
+		return err
+	}
+	if len(*guests) == 0 {
+		return nil
+	}
+
+	return db.Create(guests).Error
+}
+
+func (r *bookingGuestRepository) ListByBookingID(ctx context.Context, bookingID string) ([]models.BookingGuest, error) {
+	var guests []models.BookingGuest
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("booking_id = ?", bookingID).
+		Order("created_at ASC").
+		Find(&guests).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return guests, nil
+}
+
+func (r *bookingGuestRepository) CountByBookingID(ctx context.Context, bookingID string) (int64, error) {
+	var count int64
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.BookingGuest{}).
+		Where("booking_id = ?", bookingID).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
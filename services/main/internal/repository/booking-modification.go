@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type BookingModificationRepository interface {
+	Create(ctx context.Context, modification *models.BookingModification) error
+	// GetLatest returns the booking's most recent modification.
+	GetLatest(ctx context.Context, bookingID string) (*models.BookingModification, error)
+	// ListByBookingID returns the booking's modifications, oldest first.
+	ListByBookingID(ctx context.Context, bookingID string, populate *[]string) ([]models.BookingModification, error)
+}
+
+type bookingModificationRepository struct {
+	DB *gorm.DB
+}
+
+func NewBookingModificationRepository(db *gorm.DB) BookingModificationRepository {
+	return &bookingModificationRepository{DB: db}
+}
+
+func (r *bookingModificationRepository) Create(ctx context.Context, modification *models.BookingModification) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(modification).Error
+}
+
+func (r *bookingModificationRepository) GetLatest(
+	ctx context.Context,
+	bookingID string,
+) (*models.BookingModification, error) {
+	var modification models.BookingModification
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("booking_id = ?", bookingID).
+		Order("created_at DESC").
+		First(&modification).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &modification, nil
+}
+
+func (r *bookingModificationRepository) ListByBookingID(
+	ctx context.Context,
+	bookingID string,
+	populate *[]string,
+) ([]models.BookingModification, error) {
+	var modifications []models.BookingModification
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).Where("booking_id = ?", bookingID)
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.Order("created_at ASC").Find(&modifications).Error; err != nil {
+		return nil, err
+	}
+
+	return modifications, nil
+}
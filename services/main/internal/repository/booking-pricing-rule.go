@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type BookingPricingRuleRepository interface {
+	Create(ctx context.Context, rule *models.BookingPricingRule) error
+	GetOne(ctx context.Context, query GetBookingPricingRuleQuery) (*models.BookingPricingRule, error)
+	Update(ctx context.Context, rule *models.BookingPricingRule) error
+	Delete(ctx context.Context, input DeleteBookingPricingRuleInput) error
+	List(ctx context.Context, filter ListBookingPricingRulesFilter) (*[]models.BookingPricingRule, error)
+	Count(ctx context.Context, filter ListBookingPricingRulesFilter) (int64, error)
+	// ListForUnit returns every rule that prices the unit: its own and its
+	// property's.
+	ListForUnit(ctx context.Context, propertyID string, unitID string) ([]models.BookingPricingRule, error)
+}
+
+type bookingPricingRuleRepository struct {
+	DB *gorm.DB
+}
+
+func NewBookingPricingRuleRepository(db *gorm.DB) BookingPricingRuleRepository {
+	return &bookingPricingRuleRepository{DB: db}
+}
+
+func (r *bookingPricingRuleRepository) Create(ctx context.Context, rule *models.BookingPricingRule) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(rule).Error
+}
+
+type GetBookingPricingRuleQuery struct {
+	ID         string
+	PropertyID string
+	Populate   *[]string
+}
+
+func (r *bookingPricingRuleRepository) GetOne(
+	ctx context.Context,
+	query GetBookingPricingRuleQuery,
+) (*models.BookingPricingRule, error) {
+	var rule models.BookingPricingRule
+
+	db := r.DB.WithContext(ctx).
+		Where("booking_pricing_rules.id = ? AND booking_pricing_rules.property_id = ?", query.ID, query.PropertyID)
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.First(&rule); result.Error != nil {
+		return nil, result.Error
+	}
+	return &rule, nil
+}
+
+func (r *bookingPricingRuleRepository) Update(ctx context.Context, rule *models.BookingPricingRule) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Save(rule).Error
+}
+
+type DeleteBookingPricingRuleInput struct {
+	ID         string
+	PropertyID string
+}
+
+func (r *bookingPricingRuleRepository) Delete(ctx context.Context, input DeleteBookingPricingRuleInput) error {
+	db := lib.ResolveDB(ctx, r.DB)
+
+	return db.WithContext(ctx).
+		Where("id = ? AND property_id = ?", input.ID, input.PropertyID).
+		Delete(&models.BookingPricingRule{}).
+		Error
+}
+
+type ListBookingPricingRulesFilter struct {
+	lib.FilterQuery
+	PropertyID string
+	// UnitID keeps the rules that price the unit: its own and the property's.
+	UnitID *string
+	Type   *string
+}
+
+func (r *bookingPricingRuleRepository) List(
+	ctx context.Context,
+	filter ListBookingPricingRulesFilter,
+) (*[]models.BookingPricingRule, error) {
+	var rules []models.BookingPricingRule
+
+	db := r.DB.WithContext(ctx).
+		Where("booking_pricing_rules.property_id = ?", filter.PropertyID).
+		Scopes(
+			IDsFilterScope("booking_pricing_rules", filter.IDs),
+			bookingPricingRuleUnitScope(filter.UnitID),
+			bookingPricingRuleTypeScope(filter.Type),
+			DateRangeScope("booking_pricing_rules", filter.DateRange),
+			SearchScope("booking_pricing_rules", filter.Search),
+			PaginationScope(filter.Page, filter.PageSize),
+			OrderScope("booking_pricing_rules", filter.OrderBy, filter.Order),
+		)
+
+	if filter.Populate != nil {
+		for _, field := range *filter.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&rules); result.Error != nil {
+		return nil, result.Error
+	}
+	return &rules, nil
+}
+
+func (r *bookingPricingRuleRepository) Count(ctx context.Context, filter ListBookingPricingRulesFilter) (int64, error) {
+	var count int64
+
+	result := r.DB.WithContext(ctx).
+		Model(&models.BookingPricingRule{}).
+		Where("booking_pricing_rules.property_id = ?", filter.PropertyID).
+		Scopes(
+			IDsFilterScope("booking_pricing_rules", filter.IDs),
+			bookingPricingRuleUnitScope(filter.UnitID),
+			bookingPricingRuleTypeScope(filter.Type),
+			DateRangeScope("booking_pricing_rules", filter.DateRange),
+			SearchScope("booking_pricing_rules", filter.Search),
+		).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *bookingPricingRuleRepository) ListForUnit(
+	ctx context.Context,
+	propertyID string,
+	unitID string,
+) ([]models.BookingPricingRule, error) {
+	var rules []models.BookingPricingRule
+
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("booking_pricing_rules.property_id = ?", propertyID).
+		Scopes(bookingPricingRuleUnitScope(&unitID)).
+		Find(&rules)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rules, nil
+}
+
+func bookingPricingRuleUnitScope(unitID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID == nil {
+			return db
+		}
+		return db.Where(
+			"booking_pricing_rules.unit_id IS NULL OR booking_pricing_rules.unit_id = ?",
+			*unitID,
+		)
+	}
+}
+
+func bookingPricingRuleTypeScope(ruleType *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if ruleType == nil {
+			return db
+		}
+		return db.Where("booking_pricing_rules.type = ?", *ruleType)
+	}
+}
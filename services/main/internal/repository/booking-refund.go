@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookingRefundRepository interface {
+	Create(ctx context.Context, refund *models.BookingRefund) error
+	Update(ctx context.Context, refund *models.BookingRefund) error
+	GetByID(ctx context.Context, id string) (*models.BookingRefund, error)
+	// GetByBookingID returns the refund for cancelling the booking.
+	GetByBookingID(ctx context.Context, bookingID string) (*models.BookingRefund, error)
+	// ListForModifications returns the refunds of credits left by changes to
+	// the booking's stay.
+	ListForModifications(ctx context.Context, bookingID string) ([]models.BookingRefund, error)
+}
+
+type bookingRefundRepository struct {
+	DB *gorm.DB
+}
+
+func NewBookingRefundRepository(db *gorm.DB) BookingRefundRepository {
+	return &bookingRefundRepository{DB: db}
+}
+
+func (r *bookingRefundRepository) Create(ctx context.Context, refund *models.BookingRefund) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(refund).Error
+}
+
+func (r *bookingRefundRepository) Update(ctx context.Context, refund *models.BookingRefund) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Omit(clause.Associations).Save(refund).Error
+}
+
+func (r *bookingRefundRepository) GetByID(ctx context.Context, id string) (*models.BookingRefund, error) {
+	var refund models.BookingRefund
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).Where("id = ?", id).First(&refund).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+func (r *bookingRefundRepository) GetByBookingID(ctx context.Context, bookingID string) (*models.BookingRefund, error) {
+	var refund models.BookingRefund
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("booking_id = ? AND booking_modification_id IS NULL", bookingID).
+		First(&refund).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &refund, nil
+}
+
+func (r *bookingRefundRepository) ListForModifications(
+	ctx context.Context,
+	bookingID string,
+) ([]models.BookingRefund, error) {
+	var refunds []models.BookingRefund
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("booking_id = ? AND booking_modification_id IS NOT NULL", bookingID).
+		Find(&refunds).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
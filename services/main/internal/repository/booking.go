@@ -2,22 +2,43 @@ package repository
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type BookingRepository interface {
 	Create(ctx context.Context, booking *models.Booking) error
 	Update(ctx context.Context, booking *models.Booking) error
 	GetByIDWithPopulate(ctx context.Context, query GetBookingQuery) (*models.Booking, error)
+	// GetByIDForUpdate is GetByIDWithPopulate locking the booking's row until
+	// the caller's transaction ends.
+	GetByIDForUpdate(ctx context.Context, query GetBookingQuery) (*models.Booking, error)
 	GetByTrackingCode(ctx context.Context, trackingCode string, populate []string) (*models.Booking, error)
 	List(ctx context.Context, filterQuery lib.FilterQuery, filters ListBookingsFilter) (*[]models.Booking, error)
 	Count(ctx context.Context, filterQuery lib.FilterQuery, filter ListBookingsFilter) (int64, error)
 	CountNonBlockingByPropertyID(ctx context.Context, propertyID string) (int64, error)
 	DeleteNonBlockingByPropertyID(ctx context.Context, propertyID string) error
 	HasOverlappingBlock(ctx context.Context, unitID string, startDate, endDate interface{}) (bool, error)
+	// HasOverlappingOtherBlock is HasOverlappingBlock ignoring the booking's
+	// own block, for checking the dates a booking is moving to.
+	HasOverlappingOtherBlock(
+		ctx context.Context,
+		unitID string,
+		startDate, endDate time.Time,
+		bookingID string,
+	) (bool, error)
+	// ListStayingOverlapping returns the unit's CONFIRMED and CHECKED_IN
+	// bookings with a night in [startDate, endDate).
+	ListStayingOverlapping(ctx context.Context, unitID string, startDate, endDate time.Time) ([]models.Booking, error)
+	// GetNextCheckIn returns the unit's earliest PENDING or CONFIRMED booking
+	// checking in on the day of after or later. Check-ins are dates, so a
+	// guest arriving later that same day still counts.
+	GetNextCheckIn(ctx context.Context, unitID string, after time.Time) (*models.Booking, error)
 }
 
 type bookingRepository struct {
@@ -36,6 +57,32 @@ type ListBookingsFilter struct {
 	lib.FilterQuery
 }
 
+// bookingRelationConditions narrows the booking relations that share a
+// foreign key to their own rows: the invoices by context type, and Refund to
+// the cancellation refund rather than those for modifications.
+var bookingRelationConditions = map[string][]any{
+	"Invoice":               {"context_type = ?", "BOOKING_FEE"},
+	"SupplementaryInvoices": {"context_type = ?", "BOOKING_MODIFICATION"},
+	"Refund":                {"booking_modification_id IS NULL"},
+}
+
+// preloadBookingFields preloads fields onto a booking query. A nested field
+// such as Invoice.Payments loads its root as well, so the root is preloaded
+// with its conditions alongside it.
+func preloadBookingFields(db *gorm.DB, fields []string) *gorm.DB {
+	for _, field := range fields {
+		root, _, _ := strings.Cut(field, ".")
+		if conditions, ok := bookingRelationConditions[root]; ok {
+			db = db.Preload(root, conditions...)
+		}
+		if _, ok := bookingRelationConditions[field]; !ok {
+			db = db.Preload(field)
+		}
+	}
+
+	return db
+}
+
 func (r *bookingRepository) Create(ctx context.Context, booking *models.Booking) error {
 	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(booking).Error
 }
@@ -54,9 +101,7 @@ func (r *bookingRepository) GetByIDWithPopulate(ctx context.Context, query GetBo
 	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).Where("id = ?", query.ID)
 
 	if query.Populate != nil {
-		for _, field := range *query.Populate {
-			db = db.Preload(field)
-		}
+		db = preloadBookingFields(db, *query.Populate)
 	}
 
 	result := db.First(&booking)
@@ -66,6 +111,22 @@ func (r *bookingRepository) GetByIDWithPopulate(ctx context.Context, query GetBo
 	return &booking, nil
 }
 
+func (r *bookingRepository) GetByIDForUpdate(ctx context.Context, query GetBookingQuery) (*models.Booking, error) {
+	var booking models.Booking
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", query.ID)
+
+	if query.Populate != nil {
+		db = preloadBookingFields(db, *query.Populate)
+	}
+
+	if err := db.First(&booking).Error; err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
 func (r *bookingRepository) GetByTrackingCode(
 	ctx context.Context,
 	trackingCode string,
@@ -74,9 +135,7 @@ func (r *bookingRepository) GetByTrackingCode(
 	var booking models.Booking
 	db := r.DB.WithContext(ctx).Where("code = ?", trackingCode)
 
-	for _, field := range populate {
-		db = db.Preload(field)
-	}
+	db = preloadBookingFields(db, populate)
 
 	if err := db.First(&booking).Error; err != nil {
 		return nil, err
@@ -106,9 +165,7 @@ func (r *bookingRepository) List(
 		)
 
 	if filterQuery.Populate != nil {
-		for _, field := range *filterQuery.Populate {
-			db = db.Preload(field)
-		}
+		db = preloadBookingFields(db, *filterQuery.Populate)
 	}
 
 	if err := db.Find(&bookings).Error; err != nil {
@@ -176,6 +233,58 @@ func (r *bookingRepository) HasOverlappingBlock(
 	return count > 0, err
 }
 
+func (r *bookingRepository) HasOverlappingOtherBlock(
+	ctx context.Context,
+	unitID string,
+	startDate, endDate time.Time,
+	bookingID string,
+) (bool, error) {
+	var count int64
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.UnitDateBlock{}).
+		Where("unit_id = ? AND start_date < ? AND end_date > ?", unitID, endDate, startDate).
+		Where("booking_id IS NULL OR booking_id <> ?", bookingID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ListStayingOverlapping compares calendar dates, not times, so a guest
+// checking out on the morning another stay begins does not overlap it.
+func (r *bookingRepository) ListStayingOverlapping(
+	ctx context.Context,
+	unitID string,
+	startDate, endDate time.Time,
+) ([]models.Booking, error) {
+	var bookings []models.Booking
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("unit_id = ? AND status IN ?", unitID, []string{"CONFIRMED", "CHECKED_IN"}).
+		Where(
+			"check_in_date::date < ? AND check_out_date::date > ?",
+			endDate.Format(time.DateOnly),
+			startDate.Format(time.DateOnly),
+		).
+		Order("check_in_date ASC").
+		Find(&bookings).Error
+	return bookings, err
+}
+
+func (r *bookingRepository) GetNextCheckIn(
+	ctx context.Context,
+	unitID string,
+	after time.Time,
+) (*models.Booking, error) {
+	var booking models.Booking
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("unit_id = ? AND status IN ?", unitID, []string{"PENDING", "CONFIRMED"}).
+		Where("check_in_date >= ?", time.Date(after.Year(), after.Month(), after.Day(), 0, 0, 0, 0, after.Location())).
+		Order("check_in_date ASC").
+		First(&booking).Error
+	if err != nil {
+		return nil, err
+	}
+	return &booking, nil
+}
+
 func bookingPropertyIDScope(propertyID *string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if propertyID != nil {
@@ -0,0 +1,208 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+// CashFlowForecastScope decides which properties a forecast covers. ClientID
+// is always set; the other fields only ever narrow it.
+type CashFlowForecastScope struct {
+	ClientID    string
+	PropertyIDs *[]string
+	// ClientUserID is the caller; the forecast never reaches past the
+	// properties they have been granted.
+	ClientUserID *string
+	// OwnerClientUserID forecasts one person's portfolio: the properties
+	// assigned to them, still intersected with what the caller may see.
+	OwnerClientUserID *string
+}
+
+// ForecastChargeRow is one charge still expected to be paid, reduced to what
+// bucketing needs.
+type ForecastChargeRow struct {
+	TenantID    string
+	DueDate     time.Time
+	Outstanding int64
+	Currency    string
+}
+
+// ForecastExpenseRow is one committed-but-unpaid expense.
+type ForecastExpenseRow struct {
+	ScheduledFor time.Time
+	Amount       int64
+	Currency     string
+}
+
+// TenantPaymentPunctuality is a tenant's record on invoices that have already
+// fallen due: how many there were, and how many were paid by their due date.
+type TenantPaymentPunctuality struct {
+	TenantID string
+	Due      int64
+	OnTime   int64
+}
+
+type CashFlowForecastRepository interface {
+	// ListPendingCharges returns every live charge due before `until` that is
+	// not yet fully settled, overdue ones included — an unpaid January charge
+	// is still money the landlord expects.
+	ListPendingCharges(ctx context.Context, scope CashFlowForecastScope, until time.Time) ([]ForecastChargeRow, error)
+	ListScheduledExpenses(
+		ctx context.Context,
+		scope CashFlowForecastScope,
+		from time.Time,
+		until time.Time,
+	) ([]ForecastExpenseRow, error)
+	// ListTenantPunctuality aggregates account-backed invoices due before asOf.
+	// Drafts and voided invoices were never a request for money, so neither
+	// counts for or against a tenant.
+	ListTenantPunctuality(
+		ctx context.Context,
+		tenantIDs []string,
+		asOf time.Time,
+	) ([]TenantPaymentPunctuality, error)
+}
+
+type cashFlowForecastRepository struct {
+	DB *gorm.DB
+}
+
+func NewCashFlowForecastRepository(db *gorm.DB) CashFlowForecastRepository {
+	return &cashFlowForecastRepository{DB: db}
+}
+
+// forecastPropertyScope applies a CashFlowForecastScope to whichever column
+// carries the property id in the query at hand.
+func forecastPropertyScope(column string, scope CashFlowForecastScope) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where(
+			column+" IN (?)",
+			db.Session(&gorm.Session{NewDB: true}).
+				Model(&models.Property{}).
+				Select("id").
+				Where("client_id = ?", scope.ClientID),
+		)
+		if scope.PropertyIDs != nil {
+			db = db.Where(column+" IN (?)", *scope.PropertyIDs)
+		}
+		if scope.ClientUserID != nil {
+			db = db.Where(column+" IN (?)", accessiblePropertyIDsSubQuery(db, *scope.ClientUserID))
+		}
+		if scope.OwnerClientUserID != nil {
+			db = db.Where(column+" IN (?)", accessiblePropertyIDsSubQuery(db, *scope.OwnerClientUserID))
+		}
+		return db
+	}
+}
+
+func pendingForecastChargesScope(scope CashFlowForecastScope, until time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("JOIN financial_accounts ON financial_accounts.id = charge_instances.financial_account_id").
+			Scopes(forecastPropertyScope("financial_accounts.property_id", scope)).
+			Where("charge_instances.voided_at IS NULL").
+			Where("charge_instances.settled_amount <> charge_instances.amount").
+			Where("charge_instances.due_date < ?", until)
+	}
+}
+
+func (r *cashFlowForecastRepository) ListPendingCharges(
+	ctx context.Context,
+	scope CashFlowForecastScope,
+	until time.Time,
+) ([]ForecastChargeRow, error) {
+	var rows []ForecastChargeRow
+
+	err := lib.ResolveDB(ctx, r.DB).
+		Model(&models.ChargeInstance{}).
+		Scopes(pendingForecastChargesScope(scope, until)).
+		Select(
+			"financial_accounts.tenant_id, charge_instances.due_date, " +
+				"charge_instances.amount - charge_instances.settled_amount AS outstanding, " +
+				"charge_instances.currency",
+		).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (r *cashFlowForecastRepository) ListScheduledExpenses(
+	ctx context.Context,
+	scope CashFlowForecastScope,
+	from time.Time,
+	until time.Time,
+) ([]ForecastExpenseRow, error) {
+	var rows []ForecastExpenseRow
+
+	err := lib.ResolveDB(ctx, r.DB).
+		Model(&models.Expense{}).
+		Scopes(scheduledForecastExpensesScope(scope, from, until)).
+		Select("expenses.scheduled_for, expenses.amount, expenses.currency").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// scheduledForecastExpensesScope is the committed, still unpaid expenses
+// falling in [from, until). A settled expense has already left the account,
+// so it is not subtracted again.
+func scheduledForecastExpensesScope(
+	scope CashFlowForecastScope,
+	from time.Time,
+	until time.Time,
+) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Scopes(forecastPropertyScope("expenses.property_id", scope)).
+			Where("expenses.scheduled_for >= ? AND expenses.scheduled_for < ?", from, until).
+			Where("expenses.paid_at IS NULL")
+	}
+}
+
+func tenantPunctualityScope(tenantIDs []string, asOf time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("JOIN financial_accounts ON financial_accounts.id = invoices.financial_account_id").
+			Where("financial_accounts.tenant_id IN ?", tenantIDs).
+			Where("invoices.status IN ?", []string{"ISSUED", "PARTIALLY_PAID", "PAID"}).
+			Where("invoices.due_date < ?", asOf).
+			Select(
+				"financial_accounts.tenant_id, COUNT(*) AS due, " +
+					"COUNT(*) FILTER (WHERE invoices.paid_at IS NOT NULL AND invoices.paid_at <= invoices.due_date) " +
+					"AS on_time",
+			).
+			Group("financial_accounts.tenant_id")
+	}
+}
+
+func (r *cashFlowForecastRepository) ListTenantPunctuality(
+	ctx context.Context,
+	tenantIDs []string,
+	asOf time.Time,
+) ([]TenantPaymentPunctuality, error) {
+	if len(tenantIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []TenantPaymentPunctuality
+
+	err := lib.ResolveDB(ctx, r.DB).
+		Model(&models.Invoice{}).
+		Scopes(tenantPunctualityScope(tenantIDs, asOf)).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
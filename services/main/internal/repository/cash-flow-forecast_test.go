@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// Every forecast is pinned to the caller's client and, on the client-level
+// route, to the caller's own property grants — an owner filter may only
+// narrow that further.
+func TestPendingForecastChargesScopeStaysInsideGrants(t *testing.T) {
+	caller := "11111111-1111-1111-1111-111111111111"
+	owner := "22222222-2222-2222-2222-222222222222"
+
+	var instances []models.ChargeInstance
+	sql := dryRunDB(t).
+		Model(&models.ChargeInstance{}).
+		Scopes(pendingForecastChargesScope(CashFlowForecastScope{
+			ClientID:          "client",
+			ClientUserID:      &caller,
+			OwnerClientUserID: &owner,
+		}, time.Now())).
+		Find(&instances).Statement.SQL.String()
+
+	for _, want := range []string{
+		"client_id = ",
+		"charge_instances.voided_at IS NULL",
+		"charge_instances.settled_amount <> charge_instances.amount",
+		"charge_instances.due_date < ",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in query, got: %s", want, sql)
+		}
+	}
+	if strings.Count(sql, "FROM \"client_user_properties\"") != 2 {
+		t.Errorf("expected both caller and owner grant subqueries, got: %s", sql)
+	}
+}
+
+// Drafts and voided invoices never asked the tenant for money, so they must
+// not count for or against them.
+func TestTenantPunctualityScopeCountsOnlyIssuedInvoices(t *testing.T) {
+	var rows []TenantPaymentPunctuality
+	sql := dryRunDB(t).
+		Model(&models.Invoice{}).
+		Scopes(tenantPunctualityScope([]string{"tenant"}, time.Now())).
+		Find(&rows).Statement.SQL.String()
+
+	if !strings.Contains(sql, "invoices.status IN ") {
+		t.Errorf("expected a status filter, got: %s", sql)
+	}
+	if !strings.Contains(sql, "invoices.paid_at <= invoices.due_date") {
+		t.Errorf("expected on-time to mean paid by the due date, got: %s", sql)
+	}
+}
+
+// A settled expense has already left the account; subtracting it again
+// would count the same cost twice.
+func TestScheduledForecastExpensesScopeSkipsSettled(t *testing.T) {
+	var expenses []models.Expense
+	sql := dryRunDB(t).
+		Model(&models.Expense{}).
+		Scopes(scheduledForecastExpensesScope(CashFlowForecastScope{ClientID: "client"}, time.Now(), time.Now())).
+		Find(&expenses).Statement.SQL.String()
+
+	if !strings.Contains(sql, "expenses.paid_at IS NULL") {
+		t.Errorf("expected settled expenses to be excluded, got: %s", sql)
+	}
+}
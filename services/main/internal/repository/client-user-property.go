@@ -25,6 +25,9 @@ type ClientUserPropertyRepository interface {
 	BulkCreate(ctx context.Context, clientUserProperty *[]models.ClientUserProperty) error
 	UnlinkPropertyFromClientUsers(context context.Context, input UnlinkPropertyFromClientUsersQuery) error
 	GetWithPopulate(ctx context.Context, query ClientUserPropertyWithPopulateQuery) (*models.ClientUserProperty, error)
+	// ListClientUserIDs returns the IDs of the property's client users with
+	// role, in the order they were linked to it.
+	ListClientUserIDs(ctx context.Context, propertyID string, role string) ([]string, error)
 }
 
 type clientUserPropertyRepository struct {
@@ -213,6 +216,25 @@ func (r *clientUserPropertyRepository) Count(
 	return count, nil
 }
 
+func (r *clientUserPropertyRepository) ListClientUserIDs(
+	ctx context.Context,
+	propertyID string,
+	role string,
+) ([]string, error) {
+	var clientUserIDs []string
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.ClientUserProperty{}).
+		Where("property_id = ? AND role = ?", propertyID, role).
+		Order("created_at ASC").
+		Pluck("client_user_id", &clientUserIDs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return clientUserIDs, nil
+}
+
 func clientUserIDScope(clientUserID *string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if clientUserID == nil {
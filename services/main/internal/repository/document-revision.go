@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type DocumentRevisionRepository interface {
+	Create(ctx context.Context, revision *models.DocumentRevision) error
+	GetOne(ctx context.Context, query GetDocumentRevisionQuery) (*models.DocumentRevision, error)
+	// GetLatest returns the document's highest-numbered revision.
+	GetLatest(ctx context.Context, documentID string) (*models.DocumentRevision, error)
+	List(ctx context.Context, filter ListDocumentRevisionsFilter) (*[]models.DocumentRevision, error)
+	Count(ctx context.Context, filter ListDocumentRevisionsFilter) (int64, error)
+}
+
+type documentRevisionRepository struct {
+	DB *gorm.DB
+}
+
+func NewDocumentRevisionRepository(db *gorm.DB) DocumentRevisionRepository {
+	return &documentRevisionRepository{DB: db}
+}
+
+func (r *documentRevisionRepository) Create(ctx context.Context, revision *models.DocumentRevision) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(revision).Error
+}
+
+type GetDocumentRevisionQuery struct {
+	ID         string
+	DocumentID string
+	Populate   *[]string
+}
+
+func (r *documentRevisionRepository) GetOne(
+	ctx context.Context,
+	query GetDocumentRevisionQuery,
+) (*models.DocumentRevision, error) {
+	var revision models.DocumentRevision
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("document_revisions.id = ? AND document_revisions.document_id = ?", query.ID, query.DocumentID)
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.First(&revision); result.Error != nil {
+		return nil, result.Error
+	}
+	return &revision, nil
+}
+
+func (r *documentRevisionRepository) GetLatest(
+	ctx context.Context,
+	documentID string,
+) (*models.DocumentRevision, error) {
+	var revision models.DocumentRevision
+
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("document_id = ?", documentID).
+		Order("number DESC").
+		First(&revision)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &revision, nil
+}
+
+type ListDocumentRevisionsFilter struct {
+	lib.FilterQuery
+	DocumentID string
+}
+
+func (r *documentRevisionRepository) List(
+	ctx context.Context,
+	filter ListDocumentRevisionsFilter,
+) (*[]models.DocumentRevision, error) {
+	var revisions []models.DocumentRevision
+
+	db := r.DB.WithContext(ctx).
+		Where("document_revisions.document_id = ?", filter.DocumentID).
+		Scopes(
+			IDsFilterScope("document_revisions", filter.IDs),
+			DateRangeScope("document_revisions", filter.DateRange),
+			PaginationScope(filter.Page, filter.PageSize),
+			OrderScope("document_revisions", filter.OrderBy, filter.Order),
+		)
+
+	if filter.Populate != nil {
+		for _, field := range *filter.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&revisions); result.Error != nil {
+		return nil, result.Error
+	}
+	return &revisions, nil
+}
+
+func (r *documentRevisionRepository) Count(ctx context.Context, filter ListDocumentRevisionsFilter) (int64, error) {
+	var count int64
+
+	result := r.DB.WithContext(ctx).
+		Model(&models.DocumentRevision{}).
+		Where("document_revisions.document_id = ?", filter.DocumentID).
+		Scopes(
+			IDsFilterScope("document_revisions", filter.IDs),
+			DateRangeScope("document_revisions", filter.DateRange),
+		).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
@@ -7,10 +7,14 @@ import (
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"github.com/lib/pq"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type DocumentRepository interface {
 	GetByID(context context.Context, id string) (*models.Document, error)
+	// GetByIDForUpdate fetches the document and locks its row until the
+	// transaction in ctx ends.
+	GetByIDForUpdate(context context.Context, id string) (*models.Document, error)
 	Create(context context.Context, document *models.Document) error
 	Update(context context.Context, document *models.Document) error
 	Delete(context context.Context, documentID string) error
@@ -47,6 +51,18 @@ func (r *documentRepository) GetByID(ctx context.Context, id string) (*models.Do
 	return &document, nil
 }
 
+func (r *documentRepository) GetByIDForUpdate(ctx context.Context, id string) (*models.Document, error) {
+	var document models.Document
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&document)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &document, nil
+}
+
 type GetDocumentWithPopulateFilter struct {
 	ID       string
 	Populate *[]string
@@ -74,11 +90,13 @@ func (r *documentRepository) GetByIDWithPopulate(
 }
 
 func (r *documentRepository) Create(ctx context.Context, document *models.Document) error {
-	return r.DB.WithContext(ctx).Create(document).Error
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(document).Error
 }
 
 func (r *documentRepository) Update(ctx context.Context, document *models.Document) error {
-	return r.DB.WithContext(ctx).Save(document).Error
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Save(document).Error
 }
 
 func (r *documentRepository) Delete(ctx context.Context, documentID string) error {
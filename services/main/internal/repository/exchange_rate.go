@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
@@ -11,6 +12,9 @@ import (
 
 type ExchangeRateRepository interface {
 	BulkUpsert(ctx context.Context, rates []models.ExchangeRate) error
+	// ListLatest returns the most recent rate per quote currency effective on
+	// or before asOf. A currency missing from the result has never been synced.
+	ListLatest(ctx context.Context, asOf time.Time) ([]models.ExchangeRate, error)
 }
 
 type exchangeRateRepository struct {
@@ -34,3 +38,26 @@ func (r *exchangeRateRepository) BulkUpsert(ctx context.Context, rates []models.
 		}).
 		Create(&rates).Error
 }
+
+func latestExchangeRatesScope(asOf time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Select("DISTINCT ON (exchange_rates.quote_currency) exchange_rates.*").
+			Where("exchange_rates.effective_date <= ?", asOf).
+			Order("exchange_rates.quote_currency, exchange_rates.effective_date DESC")
+	}
+}
+
+func (r *exchangeRateRepository) ListLatest(ctx context.Context, asOf time.Time) ([]models.ExchangeRate, error) {
+	var rates []models.ExchangeRate
+
+	err := lib.ResolveDB(ctx, r.db).
+		Model(&models.ExchangeRate{}).
+		Scopes(latestExchangeRatesScope(asOf)).
+		Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rates, nil
+}
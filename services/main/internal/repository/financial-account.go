@@ -30,12 +30,28 @@ type FinancialAccountRepository interface {
 	// pre-filter by due date or the cadence quantity would be capped by the
 	// lead window.
 	ListActiveForBilling(ctx context.Context) (*[]models.FinancialAccount, error)
+	// ListDueForIssuance narrows ListActiveForBilling to accounts billed at one
+	// of the given rent frequencies that have a pending charge inside their
+	// lead window at asOf. It decides WHICH accounts to sweep, never which
+	// charges: the sweep still reads every charge on each account it returns.
+	ListDueForIssuance(
+		ctx context.Context,
+		asOf time.Time,
+		frequencies []string,
+	) (*[]models.FinancialAccount, error)
+	// NextIssuanceAt is the earliest instant after `after` at which a pending
+	// charge on an account billed at one of the given frequencies enters its
+	// lead window. Nil when nothing is waiting.
+	NextIssuanceAt(ctx context.Context, after time.Time, frequencies []string) (*time.Time, error)
 	ListDueForClosure(ctx context.Context, eligibleBefore time.Time) (*[]models.FinancialAccount, error)
 	// SumSuccessfulPayments totals every successful payment made against this
 	// account's invoices. It must go through invoices rather than through
 	// allocations: a fully unallocated overpayment has no allocation rows at
 	// all, and that residue is precisely what account credit is.
 	SumSuccessfulPayments(ctx context.Context, financialAccountID string) (int64, error)
+	// SumSuccessfulPaymentsByPayer splits the same total by the tenant who
+	// paid, for statements on a lease several tenants share.
+	SumSuccessfulPaymentsByPayer(ctx context.Context, financialAccountID string) ([]PayerPaymentTotal, error)
 }
 
 type financialAccountRepository struct {
@@ -119,6 +135,34 @@ func (r *financialAccountRepository) SumSuccessfulPayments(
 	return *total, nil
 }
 
+// PayerPaymentTotal is what one tenant has paid into an account. A nil
+// PayerTenantID collects payments nobody was attributed to — those recorded
+// before attribution existed, or taken through the public pay link.
+type PayerPaymentTotal struct {
+	PayerTenantID *string
+	Amount        int64
+}
+
+func (r *financialAccountRepository) SumSuccessfulPaymentsByPayer(
+	ctx context.Context,
+	financialAccountID string,
+) ([]PayerPaymentTotal, error) {
+	var totals []PayerPaymentTotal
+	err := lib.ResolveDB(ctx, r.DB).
+		Model(&models.Payment{}).
+		Joins("JOIN invoices i ON i.id = payments.invoice_id").
+		Where("i.financial_account_id = ?", financialAccountID).
+		Where("payments.status = ?", "SUCCESSFUL").
+		Where("payments.deleted_at IS NULL").
+		Select("payments.payer_tenant_id, COALESCE(SUM(payments.amount), 0) AS amount").
+		Group("payments.payer_tenant_id").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
 // ListDueForClosure returns accounts whose leases have all ended and which
 // have sat eligible for at least the grace period.
 //
@@ -159,3 +203,66 @@ func (r *financialAccountRepository) ListActiveForBilling(
 
 	return &accounts, nil
 }
+
+// pendingChargeIssuanceScope joins each billable account to its pending
+// charges on definitions of the given frequencies. "Pending" is exactly the
+// predicate the partial index idx_charge_instances_pending_due_date covers, so
+// neither query below walks settled or fully invoiced history.
+func pendingChargeIssuanceScope(frequencies []string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Joins("JOIN charge_instances ci ON ci.financial_account_id = financial_accounts.id").
+			Joins("JOIN charge_definitions cd ON cd.id = ci.charge_definition_id").
+			Where("financial_accounts.status IN ?", []string{"ACTIVE", "CLOSURE_ELIGIBLE"}).
+			Where("financial_accounts.rent_billing_cadence != ?", "MANUAL").
+			Where("cd.frequency IN ?", frequencies).
+			Where("ci.voided_at IS NULL").
+			Where("ci.deleted_at IS NULL").
+			Where("ci.invoiced_amount <> ci.amount").
+			Where("ci.settled_amount <> ci.amount")
+	}
+}
+
+func (r *financialAccountRepository) ListDueForIssuance(
+	ctx context.Context,
+	asOf time.Time,
+	frequencies []string,
+) (*[]models.FinancialAccount, error) {
+	var accounts []models.FinancialAccount
+
+	err := lib.ResolveDB(ctx, r.DB).
+		Model(&models.FinancialAccount{}).
+		Scopes(pendingChargeIssuanceScope(frequencies)).
+		Where("ci.due_date <= ? + financial_accounts.auto_issue_days_before * INTERVAL '1 day'", asOf).
+		Distinct("financial_accounts.*").
+		Find(&accounts).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &accounts, nil
+}
+
+func (r *financialAccountRepository) NextIssuanceAt(
+	ctx context.Context,
+	after time.Time,
+	frequencies []string,
+) (*time.Time, error) {
+	var next *time.Time
+
+	// ci.due_date > after is implied by the trigger predicate, because the
+	// lead is never negative. It is stated anyway so the planner can range-scan
+	// the due-date index rather than evaluate the expression on every row.
+	err := lib.ResolveDB(ctx, r.DB).
+		Model(&models.FinancialAccount{}).
+		Scopes(pendingChargeIssuanceScope(frequencies)).
+		Where("ci.due_date > ?", after).
+		Where("ci.due_date - financial_accounts.auto_issue_days_before * INTERVAL '1 day' > ?", after).
+		Select("MIN(ci.due_date - financial_accounts.auto_issue_days_before * INTERVAL '1 day')").
+		Scan(&next).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return next, nil
+}
@@ -43,3 +43,26 @@ func TestGetFinancialAccountFiltersByStatusSet(t *testing.T) {
 		t.Errorf("expected a status IN predicate, got: %s", sql)
 	}
 }
+
+// The short-cadence sweep must only ever touch pending charges, on the listed
+// frequencies, inside each account's own lead window.
+func TestPendingChargeIssuanceScopeFiltersPendingCharges(t *testing.T) {
+	var accounts []models.FinancialAccount
+	sql := dryRunDB(t).
+		Model(&models.FinancialAccount{}).
+		Scopes(pendingChargeIssuanceScope([]string{"Hourly", "Daily"})).
+		Find(&accounts).Statement.SQL.String()
+
+	for _, want := range []string{
+		"JOIN charge_instances ci",
+		"cd.frequency IN ",
+		"ci.voided_at IS NULL",
+		"ci.invoiced_amount <> ci.amount",
+		"ci.settled_amount <> ci.amount",
+		"financial_accounts.rent_billing_cadence != ",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("expected %q in query, got: %s", want, sql)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type GuarantorRepository interface {
+	Create(ctx context.Context, guarantor *models.Guarantor) error
+	GetOne(ctx context.Context, query GetGuarantorQuery) (*models.Guarantor, error)
+	List(ctx context.Context, filter ListGuarantorsFilter) (*[]models.Guarantor, error)
+	Update(ctx context.Context, guarantor *models.Guarantor) error
+	Delete(ctx context.Context, guarantor *models.Guarantor) error
+	// CarryToLease points every guarantor on an application at the lease the
+	// application was approved into.
+	CarryToLease(ctx context.Context, tenantApplicationID string, leaseID string) error
+}
+
+type guarantorRepository struct {
+	DB *gorm.DB
+}
+
+func NewGuarantorRepository(db *gorm.DB) GuarantorRepository {
+	return &guarantorRepository{DB: db}
+}
+
+func (r *guarantorRepository) Create(ctx context.Context, guarantor *models.Guarantor) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(guarantor).Error
+}
+
+// ListGuarantorsFilter scopes guarantors to an application or a lease. At
+// least one of the two is always set by callers.
+type ListGuarantorsFilter struct {
+	TenantApplicationID *string
+	LeaseID             *string
+	Populate            *[]string
+}
+
+func guarantorScope(filter ListGuarantorsFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.TenantApplicationID != nil {
+			db = db.Where("guarantors.tenant_application_id = ?", *filter.TenantApplicationID)
+		}
+		if filter.LeaseID != nil {
+			db = db.Where("guarantors.lease_id = ?", *filter.LeaseID)
+		}
+		if filter.Populate != nil {
+			for _, field := range *filter.Populate {
+				db = db.Preload(field)
+			}
+		}
+		return db
+	}
+}
+
+type GetGuarantorQuery struct {
+	ID string
+	ListGuarantorsFilter
+}
+
+func (r *guarantorRepository) GetOne(ctx context.Context, query GetGuarantorQuery) (*models.Guarantor, error) {
+	var guarantor models.Guarantor
+
+	result := r.DB.WithContext(ctx).
+		Scopes(guarantorScope(query.ListGuarantorsFilter)).
+		Where("guarantors.id = ?", query.ID).
+		First(&guarantor)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &guarantor, nil
+}
+
+// List is not paginated: an application or lease has one or two guarantors.
+func (r *guarantorRepository) List(ctx context.Context, filter ListGuarantorsFilter) (*[]models.Guarantor, error) {
+	var guarantors []models.Guarantor
+
+	result := r.DB.WithContext(ctx).
+		Scopes(guarantorScope(filter)).
+		Order("guarantors.created_at ASC").
+		Find(&guarantors)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &guarantors, nil
+}
+
+func (r *guarantorRepository) Update(ctx context.Context, guarantor *models.Guarantor) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Save(guarantor).Error
+}
+
+func (r *guarantorRepository) Delete(ctx context.Context, guarantor *models.Guarantor) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Delete(guarantor).Error
+}
+
+func (r *guarantorRepository) CarryToLease(ctx context.Context, tenantApplicationID string, leaseID string) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).
+		Model(&models.Guarantor{}).
+		Where("tenant_application_id = ?", tenantApplicationID).
+		Update("lease_id", leaseID).Error
+}
@@ -29,6 +29,10 @@ type InvoiceRepository interface {
 	UpdateLineItem(context context.Context, lineItem *models.InvoiceLineItem) error
 	DeleteLineItem(context context.Context, lineItemID string) error
 	ListForReminders(ctx context.Context) (*[]models.Invoice, error)
+	// ListForGuarantorEscalation returns overdue, unpaid LEASE_RENT invoices
+	// with a guarantor on their lease who asked to hear of arrears and has not
+	// yet been told of that invoice.
+	ListForGuarantorEscalation(ctx context.Context) (*[]models.Invoice, error)
 }
 
 // InvoiceStatusStat holds the count and total amount for a single invoice status.
@@ -189,7 +193,6 @@ func (r *invoiceRepository) Count(ctx context.Context, filterQuery ListInvoicesF
 // than two independent AND conditions.
 type TenantListInvoicesFilter struct {
 	lib.FilterQuery
-	TenantID            string
 	LeaseID             string
 	TenantApplicationID *string
 	Status              *[]string
@@ -292,7 +295,7 @@ func invoicePayerLeaseIDScope(payerLeaseID *string) func(db *gorm.DB) *gorm.DB {
 
 // invoicePayerTenantIDScope filters invoices paid by any of a tenant's leases
 // in this property (a tenant can have more than one lease over time, e.g.
-// renewals or a unit change), mirroring invoiceTenantOwnerContextScope's
+// renewals or a unit change, and may be a co-tenant on someone else's), mirroring invoiceTenantOwnerContextScope's
 // payer_lease_id / context_tenant_application_id fallback for application-stage
 // invoices (a tenant_application has no tenant_id of its own until it's
 // approved into a lease).
@@ -305,7 +308,9 @@ func invoicePayerTenantIDScope(payerTenantID *string) func(db *gorm.DB) *gorm.DB
 		// fallback now resolves through the financial account, which carries
 		// both the application and the tenant.
 		return db.Where(
-			`invoices.payer_lease_id IN (SELECT id FROM leases WHERE tenant_id = ?)
+			`invoices.payer_lease_id IN (
+				SELECT lease_id FROM lease_tenants WHERE deleted_at IS NULL AND tenant_id = ?
+			 )
 			 OR invoices.financial_account_id IN (
 				SELECT id FROM financial_accounts WHERE deleted_at IS NULL AND tenant_id = ?
 			 )`,
@@ -556,7 +561,8 @@ func (r *invoiceRepository) ListForReminders(ctx context.Context) (*[]models.Inv
 	//   - due tomorrow (pre-due), or
 	//   - already overdue within the supported reminder horizon (≤ 14 days).
 	// We also exclude invoices where the terminal reminder ("overdue_14d") has already
-	// been sent — there is nothing left to do for those.
+	// been sent — there is nothing left to do for those. Guarantor escalation
+	// has a query of its own, ListForGuarantorEscalation, with no horizon.
 	now := time.Now()
 	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
 	startOfTomorrow := startOfToday.Add(24 * time.Hour) // start of tomorrow
@@ -576,6 +582,38 @@ func (r *invoiceRepository) ListForReminders(ctx context.Context) (*[]models.Inv
 		).
 		Preload("PayerLease.Tenant.TenantAccount").
 		Preload("PayerLease.Unit").
+		Preload("PayerLease.Guarantors").
+		Find(&invoices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &invoices, nil
+}
+
+func (r *invoiceRepository) ListForGuarantorEscalation(ctx context.Context) (*[]models.Invoice, error) {
+	var invoices []models.Invoice
+	// A guarantor may ask to be told after any number of days overdue, so
+	// unlike the tenant's reminders there is no horizon here; what stops an
+	// invoice coming back is each guarantor's key in reminders_sent.
+	now := time.Now()
+	startOfToday := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	result := r.DB.WithContext(ctx).
+		Where(
+			"context_type = ? AND status IN ? AND due_date IS NOT NULL AND due_date < ?",
+			"LEASE_RENT",
+			[]string{"ISSUED", "PARTIALLY_PAID"},
+			startOfToday,
+		).
+		Where(`EXISTS (
+			SELECT 1 FROM guarantors
+			WHERE guarantors.lease_id = invoices.payer_lease_id
+			  AND guarantors.arrears_escalation_days IS NOT NULL
+			  AND guarantors.deleted_at IS NULL
+			  AND NOT ('guarantor_' || guarantors.id::text) = ANY(invoices.reminders_sent)
+		)`).
+		Preload("PayerLease.Tenant").
+		Preload("PayerLease.Unit").
+		Preload("PayerLease.Guarantors").
 		Find(&invoices)
 	if result.Error != nil {
 		return nil, result.Error
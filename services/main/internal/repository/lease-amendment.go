@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type LeaseAmendmentRepository interface {
+	Create(ctx context.Context, amendment *models.LeaseAmendment) error
+	GetOne(ctx context.Context, query GetLeaseAmendmentQuery) (*models.LeaseAmendment, error)
+	List(ctx context.Context, filter ListLeaseAmendmentsFilter) (*[]models.LeaseAmendment, error)
+	Count(ctx context.Context, filter ListLeaseAmendmentsFilter) (int64, error)
+	Update(ctx context.Context, amendment *models.LeaseAmendment) error
+}
+
+type leaseAmendmentRepository struct {
+	DB *gorm.DB
+}
+
+func NewLeaseAmendmentRepository(db *gorm.DB) LeaseAmendmentRepository {
+	return &leaseAmendmentRepository{DB: db}
+}
+
+func (r *leaseAmendmentRepository) Create(ctx context.Context, amendment *models.LeaseAmendment) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(amendment).Error
+}
+
+type GetLeaseAmendmentQuery struct {
+	ID       string
+	LeaseID  string
+	Populate *[]string
+}
+
+func (r *leaseAmendmentRepository) GetOne(
+	ctx context.Context,
+	query GetLeaseAmendmentQuery,
+) (*models.LeaseAmendment, error) {
+	var amendment models.LeaseAmendment
+
+	db := r.DB.WithContext(ctx).Where("id = ? AND lease_id = ?", query.ID, query.LeaseID)
+
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.First(&amendment); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &amendment, nil
+}
+
+type ListLeaseAmendmentsFilter struct {
+	lib.FilterQuery
+	LeaseID *string
+	Status  *string
+}
+
+func (r *leaseAmendmentRepository) List(
+	ctx context.Context,
+	filter ListLeaseAmendmentsFilter,
+) (*[]models.LeaseAmendment, error) {
+	var amendments []models.LeaseAmendment
+
+	db := r.DB.WithContext(ctx).Scopes(
+		IDsFilterScope("lease_amendments", filter.IDs),
+		leaseAmendmentFilterScope("lease_id", filter.LeaseID),
+		leaseAmendmentFilterScope("status", filter.Status),
+		DateRangeScope("lease_amendments", filter.DateRange),
+		SearchScope("lease_amendments", filter.Search),
+
+		PaginationScope(filter.Page, filter.PageSize),
+		OrderScope("lease_amendments", filter.OrderBy, filter.Order),
+	)
+
+	if filter.Populate != nil {
+		for _, field := range *filter.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&amendments); result.Error != nil {
+		return nil, result.Error
+	}
+	return &amendments, nil
+}
+
+func (r *leaseAmendmentRepository) Count(ctx context.Context, filter ListLeaseAmendmentsFilter) (int64, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).Model(&models.LeaseAmendment{}).Scopes(
+		IDsFilterScope("lease_amendments", filter.IDs),
+		leaseAmendmentFilterScope("lease_id", filter.LeaseID),
+		leaseAmendmentFilterScope("status", filter.Status),
+		DateRangeScope("lease_amendments", filter.DateRange),
+		SearchScope("lease_amendments", filter.Search),
+	).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *leaseAmendmentRepository) Update(ctx context.Context, amendment *models.LeaseAmendment) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Save(amendment).Error
+}
+
+func leaseAmendmentFilterScope(field string, value *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if value == nil {
+			return db
+		}
+		return db.Where("lease_amendments."+field+" = ?", *value)
+	}
+}
@@ -0,0 +1,123 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type LeaseTenantRepository interface {
+	Create(ctx context.Context, leaseTenant *models.LeaseTenant) error
+	GetOne(ctx context.Context, query GetLeaseTenantQuery) (*models.LeaseTenant, error)
+	ListByLease(ctx context.Context, leaseID string, populate *[]string) (*[]models.LeaseTenant, error)
+	Update(ctx context.Context, leaseTenant *models.LeaseTenant) error
+	Delete(ctx context.Context, leaseTenant *models.LeaseTenant) error
+	// ExistsForTenantAccount reports whether the account's tenant is on the
+	// lease in any role.
+	ExistsForTenantAccount(ctx context.Context, leaseID, tenantAccountID string) (bool, error)
+}
+
+type leaseTenantRepository struct {
+	DB *gorm.DB
+}
+
+func NewLeaseTenantRepository(db *gorm.DB) LeaseTenantRepository {
+	return &leaseTenantRepository{DB: db}
+}
+
+func (r *leaseTenantRepository) Create(ctx context.Context, leaseTenant *models.LeaseTenant) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(leaseTenant).Error
+}
+
+// GetLeaseTenantQuery finds a lease tenant by its own ID or, with TenantID,
+// by the tenant it puts on the lease. LeaseID is always required.
+type GetLeaseTenantQuery struct {
+	ID       *string
+	LeaseID  string
+	TenantID *string
+	Populate *[]string
+}
+
+func (r *leaseTenantRepository) GetOne(
+	ctx context.Context,
+	query GetLeaseTenantQuery,
+) (*models.LeaseTenant, error) {
+	var leaseTenant models.LeaseTenant
+
+	db := r.DB.WithContext(ctx).Where("lease_tenants.lease_id = ?", query.LeaseID)
+	if query.ID != nil {
+		db = db.Where("lease_tenants.id = ?", *query.ID)
+	}
+	if query.TenantID != nil {
+		db = db.Where("lease_tenants.tenant_id = ?", *query.TenantID)
+	}
+
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.First(&leaseTenant); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &leaseTenant, nil
+}
+
+// ListByLease returns everyone on a lease, the primary first. A lease has a
+// handful of tenants at most, so the list is not paginated.
+func (r *leaseTenantRepository) ListByLease(
+	ctx context.Context,
+	leaseID string,
+	populate *[]string,
+) (*[]models.LeaseTenant, error) {
+	var leaseTenants []models.LeaseTenant
+
+	db := r.DB.WithContext(ctx).
+		Where("lease_tenants.lease_id = ?", leaseID).
+		Order("lease_tenants.role = 'PRIMARY' DESC, lease_tenants.created_at ASC")
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&leaseTenants); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &leaseTenants, nil
+}
+
+func (r *leaseTenantRepository) Update(ctx context.Context, leaseTenant *models.LeaseTenant) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Save(leaseTenant).Error
+}
+
+func (r *leaseTenantRepository) Delete(ctx context.Context, leaseTenant *models.LeaseTenant) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Delete(leaseTenant).Error
+}
+
+func (r *leaseTenantRepository) ExistsForTenantAccount(
+	ctx context.Context,
+	leaseID string,
+	tenantAccountID string,
+) (bool, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).
+		Model(&models.LeaseTenant{}).
+		Joins("JOIN tenant_accounts ON tenant_accounts.tenant_id = lease_tenants.tenant_id").
+		Where("lease_tenants.lease_id = ?", leaseID).
+		Where("tenant_accounts.id = ? AND tenant_accounts.deleted_at IS NULL", tenantAccountID).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
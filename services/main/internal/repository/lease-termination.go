@@ -14,6 +14,9 @@ type LeaseTerminationRepository interface {
 	List(ctx context.Context, filter ListLeaseTerminationsFilter) (*[]models.LeaseTermination, error)
 	Count(ctx context.Context, filter ListLeaseTerminationsFilter) (int64, error)
 	Update(ctx context.Context, termination *models.LeaseTermination) error
+	// GetInProgressForLease returns the lease's open termination. A partial
+	// unique index allows at most one.
+	GetInProgressForLease(ctx context.Context, leaseID string) (*models.LeaseTermination, error)
 }
 
 type leaseTerminationRepository struct {
@@ -111,6 +114,20 @@ func (r *leaseTerminationRepository) Update(ctx context.Context, termination *mo
 	return db.WithContext(ctx).Save(termination).Error
 }
 
+func (r *leaseTerminationRepository) GetInProgressForLease(
+	ctx context.Context,
+	leaseID string,
+) (*models.LeaseTermination, error) {
+	var termination models.LeaseTermination
+	result := r.DB.WithContext(ctx).
+		Where("lease_id = ? AND status = ?", leaseID, "LeaseTermination.Status.InProgress").
+		First(&termination)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &termination, nil
+}
+
 func leaseTerminationFilterScope(field string, value *string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if value == nil {
@@ -44,6 +44,10 @@ type LeaseRepository interface {
 	// the lease, and GetOneWithPopulate reads the base connection rather than
 	// the transaction — so a re-read would not find the row yet.
 	SetFinancialAccount(context context.Context, leaseID, financialAccountID string) error
+	// SetAgreementDocumentUrl writes only lease_agreement_document_url. The
+	// signed PDF is stored in the background, so a full save could overwrite
+	// changes made to the lease in the meantime.
+	SetAgreementDocumentUrl(context context.Context, leaseID, url string) error
 	// HasMoveOutEvidenceForAccount reports whether any lease on the account has
 	// a completed termination or a check-out checklist. Advisory only — it
 	// warns at closure, it never blocks, because a lease that simply runs to
@@ -106,6 +110,13 @@ func (r *leaseRepository) SetFinancialAccount(ctx context.Context, leaseID, fina
 		Update("financial_account_id", financialAccountID).Error
 }
 
+func (r *leaseRepository) SetAgreementDocumentUrl(ctx context.Context, leaseID, url string) error {
+	return lib.ResolveDB(ctx, r.DB).
+		Model(&models.Lease{}).
+		Where("id = ?", leaseID).
+		Update("lease_agreement_document_url", url).Error
+}
+
 // applyOccupancyForTermScope is extracted so the query and its tests render
 // the same predicates.
 func applyOccupancyForTermScope(
@@ -420,6 +431,8 @@ func leaseArrayFilterScope(field string, value *[]string) func(db *gorm.DB) *gor
 	}
 }
 
+// tenantAccountLeasesScope matches every lease the account's tenant is on, in
+// any role — a co-tenant or occupant sees the lease as the primary does.
 func tenantAccountLeasesScope(tenantAccountID *string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if tenantAccountID == nil {
@@ -427,7 +440,11 @@ func tenantAccountLeasesScope(tenantAccountID *string) func(db *gorm.DB) *gorm.D
 		}
 
 		return db.Where(
-			"leases.tenant_id IN (SELECT tenant_id FROM tenant_accounts WHERE id = ? AND deleted_at IS NULL)",
+			`leases.id IN (
+				SELECT lease_tenants.lease_id FROM lease_tenants
+				JOIN tenant_accounts ON tenant_accounts.tenant_id = lease_tenants.tenant_id
+				WHERE tenant_accounts.id = ? AND tenant_accounts.deleted_at IS NULL AND lease_tenants.deleted_at IS NULL
+			)`,
 			*tenantAccountID,
 		)
 	}
@@ -13,6 +13,7 @@ type Repository struct {
 	PropertyRepository                     PropertyRepository
 	ClientUserPropertyRepository           ClientUserPropertyRepository
 	DocumentRepository                     DocumentRepository
+	DocumentRevisionRepository             DocumentRevisionRepository
 	UnitRepository                         UnitRepository
 	PropertyBlockRepository                PropertyBlockRepository
 	TenantApplicationRepository            TenantApplicationRepository
@@ -27,6 +28,7 @@ type Repository struct {
 	ChargeRepository                       ChargeRepository
 	PaymentAllocationRepository            PaymentAllocationRepository
 	SigningRepository                      SigningRepository
+	SigningEnvelopeRepository              SigningEnvelopeRepository
 	LeaseChecklistRepository               LeaseChecklistRepository
 	LeaseChecklistItemRepository           LeaseChecklistItemRepository
 	LeaseChecklistAcknowledgmentRepository LeaseChecklistAcknowledgmentRepository
@@ -37,11 +39,25 @@ type Repository struct {
 	ExpenseRepository                      ExpenseRepository
 	AgreementRepository                    AgreementRepository
 	BookingRepository                      BookingRepository
+	BookingRefundRepository                BookingRefundRepository
+	BookingModificationRepository          BookingModificationRepository
 	UnitDateBlockRepository                UnitDateBlockRepository
+	TurnoverTaskRepository                 TurnoverTaskRepository
+	BookingGuestRepository                 BookingGuestRepository
+	WaitlistEntryRepository                WaitlistEntryRepository
+	ViewingSlotRepository                  ViewingSlotRepository
+	ViewingRepository                      ViewingRepository
+	UnitCalendarSubscriptionRepository     UnitCalendarSubscriptionRepository
+	BookingPricingRuleRepository           BookingPricingRuleRepository
 	LeaseTerminationRepository             LeaseTerminationRepository
+	LeaseAmendmentRepository               LeaseAmendmentRepository
+	LeaseTenantRepository                  LeaseTenantRepository
+	GuarantorRepository                    GuarantorRepository
+	RenewalOfferRepository                 RenewalOfferRepository
 	ExchangeRateRepository                 ExchangeRateRepository
 	LeaseAgreementDocumentRepository       LeaseAgreementDocumentRepository
 	NotificationRepository                 NotificationRepository
+	CashFlowForecastRepository             CashFlowForecastRepository
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -55,6 +71,7 @@ func NewRepository(db *gorm.DB) Repository {
 	propertyRepository := NewPropertyRepository(db)
 	clientUserPropertyRepository := NewClientUserPropertyRepository(db)
 	documentRepository := NewDocumentRepository(db)
+	documentRevisionRepository := NewDocumentRevisionRepository(db)
 	unitRepository := NewUnitRepository(db)
 	propertyBlockRepository := NewPropertyBlockRepository(db)
 	tenantApplicationRepository := NewTenantApplicationRepository(db)
@@ -69,6 +86,7 @@ func NewRepository(db *gorm.DB) Repository {
 	chargeRepository := NewChargeRepository(db)
 	paymentAllocationRepository := NewPaymentAllocationRepository(db)
 	signingRepository := NewSigningRepository(db)
+	signingEnvelopeRepository := NewSigningEnvelopeRepository(db)
 	leaseChecklistRepository := NewLeaseChecklistRepository(db)
 	leaseChecklistItemRepository := NewLeaseChecklistItemRepository(db)
 	leaseChecklistAcknowledgmentRepository := NewLeaseChecklistAcknowledgmentRepository(db)
@@ -79,11 +97,25 @@ func NewRepository(db *gorm.DB) Repository {
 	expenseRepository := NewExpenseRepository(db)
 	agreementRepository := NewAgreementRepository(db)
 	bookingRepo := NewBookingRepository(db)
+	bookingRefundRepo := NewBookingRefundRepository(db)
+	bookingModificationRepo := NewBookingModificationRepository(db)
 	unitDateBlockRepo := NewUnitDateBlockRepository(db)
+	turnoverTaskRepo := NewTurnoverTaskRepository(db)
+	bookingGuestRepo := NewBookingGuestRepository(db)
+	waitlistEntryRepo := NewWaitlistEntryRepository(db)
+	viewingSlotRepo := NewViewingSlotRepository(db)
+	viewingRepo := NewViewingRepository(db)
+	unitCalendarSubscriptionRepo := NewUnitCalendarSubscriptionRepository(db)
+	bookingPricingRuleRepo := NewBookingPricingRuleRepository(db)
 	leaseTerminationRepo := NewLeaseTerminationRepository(db)
+	leaseAmendmentRepo := NewLeaseAmendmentRepository(db)
+	leaseTenantRepo := NewLeaseTenantRepository(db)
+	guarantorRepo := NewGuarantorRepository(db)
+	renewalOfferRepo := NewRenewalOfferRepository(db)
 	exchangeRateRepository := NewExchangeRateRepository(db)
 	leaseAgreementDocumentRepository := NewLeaseAgreementDocumentRepository(db)
 	notificationRepository := NewNotificationRepository(db)
+	cashFlowForecastRepository := NewCashFlowForecastRepository(db)
 
 	return Repository{
 		AdminRepository:                        adminRepository,
@@ -96,6 +128,7 @@ func NewRepository(db *gorm.DB) Repository {
 		PropertyRepository:                     propertyRepository,
 		ClientUserPropertyRepository:           clientUserPropertyRepository,
 		DocumentRepository:                     documentRepository,
+		DocumentRevisionRepository:             documentRevisionRepository,
 		UnitRepository:                         unitRepository,
 		PropertyBlockRepository:                propertyBlockRepository,
 		TenantApplicationRepository:            tenantApplicationRepository,
@@ -110,6 +143,7 @@ func NewRepository(db *gorm.DB) Repository {
 		ChargeRepository:                       chargeRepository,
 		PaymentAllocationRepository:            paymentAllocationRepository,
 		SigningRepository:                      signingRepository,
+		SigningEnvelopeRepository:              signingEnvelopeRepository,
 		LeaseChecklistRepository:               leaseChecklistRepository,
 		LeaseChecklistItemRepository:           leaseChecklistItemRepository,
 		LeaseChecklistAcknowledgmentRepository: leaseChecklistAcknowledgmentRepository,
@@ -120,10 +154,24 @@ func NewRepository(db *gorm.DB) Repository {
 		ExpenseRepository:                      expenseRepository,
 		AgreementRepository:                    agreementRepository,
 		BookingRepository:                      bookingRepo,
+		BookingRefundRepository:                bookingRefundRepo,
+		BookingModificationRepository:          bookingModificationRepo,
 		UnitDateBlockRepository:                unitDateBlockRepo,
+		TurnoverTaskRepository:                 turnoverTaskRepo,
+		BookingGuestRepository:                 bookingGuestRepo,
+		WaitlistEntryRepository:                waitlistEntryRepo,
+		ViewingSlotRepository:                  viewingSlotRepo,
+		ViewingRepository:                      viewingRepo,
+		UnitCalendarSubscriptionRepository:     unitCalendarSubscriptionRepo,
+		BookingPricingRuleRepository:           bookingPricingRuleRepo,
 		LeaseTerminationRepository:             leaseTerminationRepo,
+		LeaseAmendmentRepository:               leaseAmendmentRepo,
+		LeaseTenantRepository:                  leaseTenantRepo,
+		GuarantorRepository:                    guarantorRepo,
+		RenewalOfferRepository:                 renewalOfferRepo,
 		ExchangeRateRepository:                 exchangeRateRepository,
 		LeaseAgreementDocumentRepository:       leaseAgreementDocumentRepository,
 		NotificationRepository:                 notificationRepository,
+		CashFlowForecastRepository:             cashFlowForecastRepository,
 	}
 }
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type RenewalOfferRepository interface {
+	Create(ctx context.Context, offer *models.RenewalOffer) error
+	GetOne(ctx context.Context, query GetRenewalOfferQuery) (*models.RenewalOffer, error)
+	List(ctx context.Context, filter ListRenewalOffersFilter) (*[]models.RenewalOffer, error)
+	Count(ctx context.Context, filter ListRenewalOffersFilter) (int64, error)
+	Update(ctx context.Context, offer *models.RenewalOffer) error
+	// ExpireDue closes every Sent offer whose expiry has passed and returns
+	// how many it closed.
+	ExpireDue(ctx context.Context, now time.Time) (int64, error)
+}
+
+type renewalOfferRepository struct {
+	DB *gorm.DB
+}
+
+func NewRenewalOfferRepository(db *gorm.DB) RenewalOfferRepository {
+	return &renewalOfferRepository{DB: db}
+}
+
+func (r *renewalOfferRepository) Create(ctx context.Context, offer *models.RenewalOffer) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(offer).Error
+}
+
+type GetRenewalOfferQuery struct {
+	ID       string
+	LeaseID  string
+	Populate *[]string
+}
+
+func (r *renewalOfferRepository) GetOne(
+	ctx context.Context,
+	query GetRenewalOfferQuery,
+) (*models.RenewalOffer, error) {
+	var offer models.RenewalOffer
+
+	db := r.DB.WithContext(ctx).Where("id = ? AND lease_id = ?", query.ID, query.LeaseID)
+
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.First(&offer); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &offer, nil
+}
+
+type ListRenewalOffersFilter struct {
+	lib.FilterQuery
+	LeaseID *string
+	Status  *string
+}
+
+func (r *renewalOfferRepository) List(
+	ctx context.Context,
+	filter ListRenewalOffersFilter,
+) (*[]models.RenewalOffer, error) {
+	var offers []models.RenewalOffer
+
+	db := r.DB.WithContext(ctx).Scopes(
+		IDsFilterScope("renewal_offers", filter.IDs),
+		renewalOfferFilterScope("lease_id", filter.LeaseID),
+		renewalOfferFilterScope("status", filter.Status),
+		DateRangeScope("renewal_offers", filter.DateRange),
+		SearchScope("renewal_offers", filter.Search),
+
+		PaginationScope(filter.Page, filter.PageSize),
+		OrderScope("renewal_offers", filter.OrderBy, filter.Order),
+	)
+
+	if filter.Populate != nil {
+		for _, field := range *filter.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&offers); result.Error != nil {
+		return nil, result.Error
+	}
+	return &offers, nil
+}
+
+func (r *renewalOfferRepository) Count(ctx context.Context, filter ListRenewalOffersFilter) (int64, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).Model(&models.RenewalOffer{}).Scopes(
+		IDsFilterScope("renewal_offers", filter.IDs),
+		renewalOfferFilterScope("lease_id", filter.LeaseID),
+		renewalOfferFilterScope("status", filter.Status),
+		DateRangeScope("renewal_offers", filter.DateRange),
+		SearchScope("renewal_offers", filter.Search),
+	).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *renewalOfferRepository) Update(ctx context.Context, offer *models.RenewalOffer) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Save(offer).Error
+}
+
+func (r *renewalOfferRepository) ExpireDue(ctx context.Context, now time.Time) (int64, error) {
+	result := r.DB.WithContext(ctx).
+		Model(&models.RenewalOffer{}).
+		Where("status = ? AND expires_at <= ?", "RenewalOffer.Status.Sent", now).
+		Update("status", "RenewalOffer.Status.Expired")
+
+	return result.RowsAffected, result.Error
+}
+
+func renewalOfferFilterScope(field string, value *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if value == nil {
+			return db
+		}
+		return db.Where("renewal_offers."+field+" = ?", *value)
+	}
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SigningEnvelopeRepository interface {
+	// Create saves the envelope together with its tokens.
+	Create(ctx context.Context, envelope *models.SigningEnvelope) error
+	GetOne(ctx context.Context, query GetSigningEnvelopeQuery) (*models.SigningEnvelope, error)
+	List(ctx context.Context, filter ListSigningEnvelopesFilter) (*[]models.SigningEnvelope, error)
+	Count(ctx context.Context, filter ListSigningEnvelopesFilter) (int64, error)
+	// Update saves the envelope's own columns; its tokens are saved through
+	// the SigningRepository.
+	Update(ctx context.Context, envelope *models.SigningEnvelope) error
+	// ListInProgress returns every in-progress envelope with its tokens, for
+	// the reminder sweep.
+	ListInProgress(ctx context.Context, populate *[]string) (*[]models.SigningEnvelope, error)
+}
+
+type signingEnvelopeRepository struct {
+	DB *gorm.DB
+}
+
+func NewSigningEnvelopeRepository(db *gorm.DB) SigningEnvelopeRepository {
+	return &signingEnvelopeRepository{DB: db}
+}
+
+func (r *signingEnvelopeRepository) Create(ctx context.Context, envelope *models.SigningEnvelope) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(envelope).Error
+}
+
+// signingEnvelopeTokensPreload loads an envelope's tokens in signing order.
+func signingEnvelopeTokensPreload(db *gorm.DB) *gorm.DB {
+	return db.Order("signing_tokens.signing_order ASC, signing_tokens.created_at ASC")
+}
+
+type GetSigningEnvelopeQuery struct {
+	ID         string
+	DocumentID *string
+	Status     *string
+	Populate   *[]string
+}
+
+// GetOne finds an envelope by ID, or by document when ID is empty. Its tokens
+// are always loaded.
+func (r *signingEnvelopeRepository) GetOne(
+	ctx context.Context,
+	query GetSigningEnvelopeQuery,
+) (*models.SigningEnvelope, error) {
+	var envelope models.SigningEnvelope
+
+	db := r.DB.WithContext(ctx).Scopes(
+		signingEnvelopeFilterScope("document_id", query.DocumentID),
+		signingEnvelopeFilterScope("status", query.Status),
+	)
+	if query.ID != "" {
+		db = db.Where("signing_envelopes.id = ?", query.ID)
+	}
+
+	db = db.Preload("Tokens", signingEnvelopeTokensPreload)
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.First(&envelope); result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &envelope, nil
+}
+
+type ListSigningEnvelopesFilter struct {
+	lib.FilterQuery
+	DocumentID          *string
+	TenantApplicationID *string
+	LeaseID             *string
+	LeaseTerminationID  *string
+	LeaseAmendmentID    *string
+	Status              *string
+}
+
+func (r *signingEnvelopeRepository) List(
+	ctx context.Context,
+	filter ListSigningEnvelopesFilter,
+) (*[]models.SigningEnvelope, error) {
+	var envelopes []models.SigningEnvelope
+
+	db := r.DB.WithContext(ctx).Scopes(
+		IDsFilterScope("signing_envelopes", filter.IDs),
+		signingEnvelopeFilterScope("document_id", filter.DocumentID),
+		signingEnvelopeFilterScope("tenant_application_id", filter.TenantApplicationID),
+		signingEnvelopeFilterScope("lease_id", filter.LeaseID),
+		signingEnvelopeFilterScope("lease_termination_id", filter.LeaseTerminationID),
+		signingEnvelopeFilterScope("lease_amendment_id", filter.LeaseAmendmentID),
+		signingEnvelopeFilterScope("status", filter.Status),
+		DateRangeScope("signing_envelopes", filter.DateRange),
+
+		PaginationScope(filter.Page, filter.PageSize),
+		OrderScope("signing_envelopes", filter.OrderBy, filter.Order),
+	)
+
+	db = db.Preload("Tokens", signingEnvelopeTokensPreload)
+	if filter.Populate != nil {
+		for _, field := range *filter.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&envelopes); result.Error != nil {
+		return nil, result.Error
+	}
+	return &envelopes, nil
+}
+
+func (r *signingEnvelopeRepository) Count(ctx context.Context, filter ListSigningEnvelopesFilter) (int64, error) {
+	var count int64
+	result := r.DB.WithContext(ctx).Model(&models.SigningEnvelope{}).Scopes(
+		IDsFilterScope("signing_envelopes", filter.IDs),
+		signingEnvelopeFilterScope("document_id", filter.DocumentID),
+		signingEnvelopeFilterScope("tenant_application_id", filter.TenantApplicationID),
+		signingEnvelopeFilterScope("lease_id", filter.LeaseID),
+		signingEnvelopeFilterScope("lease_termination_id", filter.LeaseTerminationID),
+		signingEnvelopeFilterScope("lease_amendment_id", filter.LeaseAmendmentID),
+		signingEnvelopeFilterScope("status", filter.Status),
+		DateRangeScope("signing_envelopes", filter.DateRange),
+	).Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *signingEnvelopeRepository) Update(ctx context.Context, envelope *models.SigningEnvelope) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Omit(clause.Associations).Save(envelope).Error
+}
+
+func (r *signingEnvelopeRepository) ListInProgress(
+	ctx context.Context,
+	populate *[]string,
+) (*[]models.SigningEnvelope, error) {
+	var envelopes []models.SigningEnvelope
+
+	db := r.DB.WithContext(ctx).
+		Where("status = ?", "SigningEnvelope.Status.InProgress").
+		Preload("Tokens", signingEnvelopeTokensPreload)
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&envelopes); result.Error != nil {
+		return nil, result.Error
+	}
+	return &envelopes, nil
+}
+
+func signingEnvelopeFilterScope(field string, value *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if value == nil {
+			return db
+		}
+		return db.Where("signing_envelopes."+field+" = ?", *value)
+	}
+}
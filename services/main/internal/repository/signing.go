@@ -6,6 +6,7 @@ import (
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type SigningRepository interface {
@@ -24,6 +25,12 @@ type SigningRepository interface {
 		filters ListSigningTokensFilter,
 	) (int64, error)
 	CreateDocumentSignature(ctx context.Context, sig *models.DocumentSignature) error
+	// GetDocumentForSigningUpdate locks the document row, so signatures on the
+	// same document chain onto each other one at a time.
+	GetDocumentForSigningUpdate(ctx context.Context, documentID string) (*models.Document, error)
+	// GetLatestChainedDocumentSignature is the most recent signature on the
+	// document that carries an event hash.
+	GetLatestChainedDocumentSignature(ctx context.Context, documentID string) (*models.DocumentSignature, error)
 	GetDocumentSignatureByQuery(ctx context.Context, query map[string]any) (*models.DocumentSignature, error)
 	ListDocumentSignatures(
 		ctx context.Context,
@@ -100,6 +107,36 @@ func (r *signingRepository) CreateDocumentSignature(
 	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(sig).Error
 }
 
+func (r *signingRepository) GetDocumentForSigningUpdate(
+	ctx context.Context,
+	documentID string,
+) (*models.Document, error) {
+	var document models.Document
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", documentID).
+		First(&document)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &document, nil
+}
+
+func (r *signingRepository) GetLatestChainedDocumentSignature(
+	ctx context.Context,
+	documentID string,
+) (*models.DocumentSignature, error) {
+	var sig models.DocumentSignature
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("document_id = ? AND event_hash <> ''", documentID).
+		Order("created_at desc").
+		First(&sig)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &sig, nil
+}
+
 func (r *signingRepository) GetDocumentSignatureByQuery(
 	ctx context.Context,
 	query map[string]any,
@@ -119,6 +156,7 @@ type ListSigningTokensFilter struct {
 	TenantApplicationID *string
 	LeaseID             *string
 	LeaseTerminationID  *string
+	LeaseAmendmentID    *string
 	Role                *string
 	CreatedByID         *string
 }
@@ -159,6 +197,15 @@ func SigningTokenLeaseTerminationIDScope(leaseID *string) func(db *gorm.DB) *gor
 	}
 }
 
+func SigningTokenLeaseAmendmentIDScope(leaseAmendmentID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if leaseAmendmentID == nil {
+			return db
+		}
+		return db.Where("signing_tokens.lease_amendment_id = ?", *leaseAmendmentID)
+	}
+}
+
 func SigningTokenRoleScope(role *string) func(db *gorm.DB) *gorm.DB {
 	return func(db *gorm.DB) *gorm.DB {
 		if role == nil {
@@ -192,6 +239,7 @@ func (r *signingRepository) ListSigningTokens(
 			SigningTokenTenantApplicationIDScope(filters.TenantApplicationID),
 			SigningTokenLeaseIDScope(filters.LeaseID),
 			SigningTokenLeaseTerminationIDScope(filters.LeaseTerminationID),
+			SigningTokenLeaseAmendmentIDScope(filters.LeaseAmendmentID),
 			SigningTokenRoleScope(filters.Role),
 			SigningTokenCreatedByIDScope(filters.CreatedByID),
 			PaginationScope(filterQuery.Page, filterQuery.PageSize),
@@ -228,6 +276,8 @@ func (r *signingRepository) CountSigningTokens(
 			SigningTokenDocumentIDScope(filters.DocumentID),
 			SigningTokenTenantApplicationIDScope(filters.TenantApplicationID),
 			SigningTokenLeaseIDScope(filters.LeaseID),
+			SigningTokenLeaseTerminationIDScope(filters.LeaseTerminationID),
+			SigningTokenLeaseAmendmentIDScope(filters.LeaseAmendmentID),
 			SigningTokenRoleScope(filters.Role),
 			SigningTokenCreatedByIDScope(filters.CreatedByID),
 		).
@@ -246,6 +296,7 @@ type ListDocumentSignaturesFilter struct {
 	LeaseID             *string
 	Role                *string
 	SignedByID          *string
+	GuarantorID         *string
 	IDs                 *[]string
 }
 
@@ -294,6 +345,15 @@ func DocumentSignatureSignedByIDScope(signedByID *string) func(db *gorm.DB) *gor
 	}
 }
 
+func DocumentSignatureGuarantorIDScope(guarantorID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if guarantorID == nil {
+			return db
+		}
+		return db.Where("document_signatures.guarantor_id = ?", *guarantorID)
+	}
+}
+
 func (r *signingRepository) ListDocumentSignatures(
 	ctx context.Context,
 	filterQuery lib.FilterQuery,
@@ -310,6 +370,7 @@ func (r *signingRepository) ListDocumentSignatures(
 			DocumentSignatureLeaseIDScope(filters.LeaseID),
 			DocumentSignatureRoleScope(filters.Role),
 			DocumentSignatureSignedByIDScope(filters.SignedByID),
+			DocumentSignatureGuarantorIDScope(filters.GuarantorID),
 			PaginationScope(filterQuery.Page, filterQuery.PageSize),
 			OrderScope("document_signatures", filterQuery.OrderBy, filterQuery.Order),
 		)
@@ -346,6 +407,7 @@ func (r *signingRepository) CountDocumentSignatures(
 			DocumentSignatureLeaseIDScope(filters.LeaseID),
 			DocumentSignatureRoleScope(filters.Role),
 			DocumentSignatureSignedByIDScope(filters.SignedByID),
+			DocumentSignatureGuarantorIDScope(filters.GuarantorID),
 		).
 		Count(&count)
 
@@ -68,7 +68,8 @@ func (r *tenantAccountRepository) GetByPropertyID(
 	var accounts []models.TenantAccount
 	result := r.DB.WithContext(ctx).
 		Joins("JOIN tenants ON tenant_accounts.tenant_id = tenants.id").
-		Joins("JOIN leases ON leases.tenant_id = tenants.id").
+		Joins("JOIN lease_tenants ON lease_tenants.tenant_id = tenants.id AND lease_tenants.deleted_at IS NULL").
+		Joins("JOIN leases ON leases.id = lease_tenants.lease_id").
 		Joins("JOIN units ON leases.unit_id = units.id").
 		Where("units.property_id = ? AND leases.status IN ? AND leases.deleted_at IS NULL AND units.deleted_at IS NULL", propertyID, activeLeasesStatuses).
 		Distinct("tenant_accounts.*").
@@ -86,7 +87,8 @@ func (r *tenantAccountRepository) GetByBlockID(
 	var accounts []models.TenantAccount
 	result := r.DB.WithContext(ctx).
 		Joins("JOIN tenants ON tenant_accounts.tenant_id = tenants.id").
-		Joins("JOIN leases ON leases.tenant_id = tenants.id").
+		Joins("JOIN lease_tenants ON lease_tenants.tenant_id = tenants.id AND lease_tenants.deleted_at IS NULL").
+		Joins("JOIN leases ON leases.id = lease_tenants.lease_id").
 		Joins("JOIN units ON leases.unit_id = units.id").
 		Where("units.property_block_id = ? AND leases.status IN ? AND leases.deleted_at IS NULL AND units.deleted_at IS NULL", blockID, activeLeasesStatuses).
 		Distinct("tenant_accounts.*").
@@ -104,7 +106,8 @@ func (r *tenantAccountRepository) GetByUnitIDs(
 	var accounts []models.TenantAccount
 	result := r.DB.WithContext(ctx).
 		Joins("JOIN tenants ON tenant_accounts.tenant_id = tenants.id").
-		Joins("JOIN leases ON leases.tenant_id = tenants.id").
+		Joins("JOIN lease_tenants ON lease_tenants.tenant_id = tenants.id AND lease_tenants.deleted_at IS NULL").
+		Joins("JOIN leases ON leases.id = lease_tenants.lease_id").
 		Where("leases.unit_id IN ? AND leases.status IN ? AND leases.deleted_at IS NULL", unitIDs, activeLeasesStatuses).
 		Distinct("tenant_accounts.*").
 		Find(&accounts)
@@ -121,7 +124,8 @@ func (r *tenantAccountRepository) GetByClientID(
 	var accounts []models.TenantAccount
 	result := r.DB.WithContext(ctx).
 		Joins("JOIN tenants ON tenant_accounts.tenant_id = tenants.id").
-		Joins("JOIN leases ON leases.tenant_id = tenants.id").
+		Joins("JOIN lease_tenants ON lease_tenants.tenant_id = tenants.id AND lease_tenants.deleted_at IS NULL").
+		Joins("JOIN leases ON leases.id = lease_tenants.lease_id").
 		Joins("JOIN units ON leases.unit_id = units.id").
 		Joins("JOIN properties ON units.property_id = properties.id").
 		Where("properties.client_id = ? AND leases.status IN ? AND leases.deleted_at IS NULL AND units.deleted_at IS NULL AND properties.deleted_at IS NULL", clientID, activeLeasesStatuses).
@@ -22,6 +22,10 @@ type TenantRepository interface {
 	ListTenantsByProperty(context context.Context, filterQuery ListTenantsByPropertyFilter) (*[]models.Tenant, error)
 	CountTenantsByProperty(context context.Context, filterQuery ListTenantsByPropertyFilter) (int64, error)
 	GetOneByProperty(context context.Context, query GetTenantByPropertyQuery) (*models.Tenant, error)
+
+	// ListOnCurrentLeases returns every tenant on a Pending or Active lease,
+	// co-tenants and occupants included, whether or not they have an account.
+	ListOnCurrentLeases(context context.Context, filter ListTenantsOnCurrentLeasesFilter) (*[]models.Tenant, error)
 }
 
 type tenantRepository struct {
@@ -336,3 +340,42 @@ func recentBookingPreloadScope(propertyIDs *[]string, clientUserID *string) func
 		return db.Table("(?) AS bookings", sub).Where("bookings.rn = 1")
 	}
 }
+
+// ListTenantsOnCurrentLeasesFilter narrows the leases to the given units,
+// block or property, in that order of precedence, and to the client's whole
+// portfolio when none is set.
+type ListTenantsOnCurrentLeasesFilter struct {
+	ClientID        string
+	PropertyID      *string
+	PropertyBlockID *string
+	UnitIDs         []string
+}
+
+func (r *tenantRepository) ListOnCurrentLeases(
+	ctx context.Context,
+	filter ListTenantsOnCurrentLeasesFilter,
+) (*[]models.Tenant, error) {
+	db := r.DB.WithContext(ctx).
+		Joins("JOIN lease_tenants ON lease_tenants.tenant_id = tenants.id AND lease_tenants.deleted_at IS NULL").
+		Joins("JOIN leases ON leases.id = lease_tenants.lease_id AND leases.deleted_at IS NULL").
+		Joins("JOIN units ON units.id = leases.unit_id AND units.deleted_at IS NULL").
+		Where("leases.status IN ?", activeLeasesStatuses)
+
+	switch {
+	case len(filter.UnitIDs) > 0:
+		db = db.Where("leases.unit_id IN ?", filter.UnitIDs)
+	case filter.PropertyBlockID != nil:
+		db = db.Where("units.property_block_id = ?", *filter.PropertyBlockID)
+	case filter.PropertyID != nil:
+		db = db.Where("units.property_id = ?", *filter.PropertyID)
+	default:
+		db = db.Joins("JOIN properties ON properties.id = units.property_id AND properties.deleted_at IS NULL").
+			Where("properties.client_id = ?", filter.ClientID)
+	}
+
+	var tenants []models.Tenant
+	if err := db.Distinct("tenants.*").Find(&tenants).Error; err != nil {
+		return nil, err
+	}
+	return &tenants, nil
+}
@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type TurnoverTaskRepository interface {
+	BulkCreate(ctx context.Context, tasks *[]models.TurnoverTask) error
+	Update(ctx context.Context, task *models.TurnoverTask) error
+	GetByIDWithPopulate(ctx context.Context, query GetTurnoverTaskQuery) (*models.TurnoverTask, error)
+	List(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListTurnoverTasksFilter,
+	) ([]models.TurnoverTask, error)
+	Count(ctx context.Context, filterQuery lib.FilterQuery, filters ListTurnoverTasksFilter) (int64, error)
+	// CountPendingByAssignee returns how many PENDING tasks each of the client
+	// users is assigned. Client users with none are left out.
+	CountPendingByAssignee(ctx context.Context, clientUserIDs []string) (map[string]int64, error)
+	// CountPendingForStay counts the PENDING tasks generated by the end of a
+	// booking or of a lease, whichever ID is set.
+	CountPendingForStay(ctx context.Context, bookingID, leaseID *string) (int64, error)
+	// ListPendingDueBetween returns the tasks still PENDING that fell due in
+	// [from, until), with their property, unit and assignee.
+	ListPendingDueBetween(ctx context.Context, from, until time.Time) ([]models.TurnoverTask, error)
+}
+
+type turnoverTaskRepository struct {
+	DB *gorm.DB
+}
+
+func NewTurnoverTaskRepository(db *gorm.DB) TurnoverTaskRepository {
+	return &turnoverTaskRepository{DB: db}
+}
+
+type ListTurnoverTasksFilter struct {
+	PropertyID             *string
+	UnitID                 *string
+	AssignedToClientUserID *string
+	Status                 *string
+	Type                   *string
+}
+
+type GetTurnoverTaskQuery struct {
+	ID         string
+	PropertyID *string
+	Populate   *[]string
+}
+
+func (r *turnoverTaskRepository) BulkCreate(ctx context.Context, tasks *[]models.TurnoverTask) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(tasks).Error
+}
+
+func (r *turnoverTaskRepository) Update(ctx context.Context, task *models.TurnoverTask) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Save(task).Error
+}
+
+func (r *turnoverTaskRepository) GetByIDWithPopulate(
+	ctx context.Context,
+	query GetTurnoverTaskQuery,
+) (*models.TurnoverTask, error) {
+	var task models.TurnoverTask
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("id = ?", query.ID).
+		Scopes(turnoverTaskPropertyIDScope(query.PropertyID))
+
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.First(&task).Error; err != nil {
+		return nil, err
+	}
+
+	return &task, nil
+}
+
+func (r *turnoverTaskRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListTurnoverTasksFilter,
+) ([]models.TurnoverTask, error) {
+	var tasks []models.TurnoverTask
+
+	db := r.DB.WithContext(ctx).
+		Scopes(
+			IDsFilterScope("turnover_tasks", filterQuery.IDs),
+			DateRangeScope("turnover_tasks", filterQuery.DateRange),
+			turnoverTaskPropertyIDScope(filters.PropertyID),
+			turnoverTaskUnitIDScope(filters.UnitID),
+			turnoverTaskAssigneeScope(filters.AssignedToClientUserID),
+			turnoverTaskStatusScope(filters.Status),
+			turnoverTaskTypeScope(filters.Type),
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			turnoverTaskOrderScope(filterQuery.OrderBy, filterQuery.Order),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func (r *turnoverTaskRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListTurnoverTasksFilter,
+) (int64, error) {
+	var count int64
+
+	err := r.DB.WithContext(ctx).
+		Model(&models.TurnoverTask{}).
+		Scopes(
+			IDsFilterScope("turnover_tasks", filterQuery.IDs),
+			DateRangeScope("turnover_tasks", filterQuery.DateRange),
+			turnoverTaskPropertyIDScope(filters.PropertyID),
+			turnoverTaskUnitIDScope(filters.UnitID),
+			turnoverTaskAssigneeScope(filters.AssignedToClientUserID),
+			turnoverTaskStatusScope(filters.Status),
+			turnoverTaskTypeScope(filters.Type),
+		).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *turnoverTaskRepository) CountPendingByAssignee(
+	ctx context.Context,
+	clientUserIDs []string,
+) (map[string]int64, error) {
+	var rows []struct {
+		AssignedToClientUserID string
+		Count                  int64
+	}
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.TurnoverTask{}).
+		Select("assigned_to_client_user_id, COUNT(*) AS count").
+		Where("status = ? AND assigned_to_client_user_id IN ?", "PENDING", clientUserIDs).
+		Group("assigned_to_client_user_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.AssignedToClientUserID] = row.Count
+	}
+
+	return counts, nil
+}
+
+func (r *turnoverTaskRepository) CountPendingForStay(
+	ctx context.Context,
+	bookingID, leaseID *string,
+) (int64, error) {
+	var count int64
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.TurnoverTask{}).
+		Where("status = ?", "PENDING")
+	if bookingID != nil {
+		db = db.Where("booking_id = ?", *bookingID)
+	} else {
+		db = db.Where("lease_id = ?", leaseID)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *turnoverTaskRepository) ListPendingDueBetween(
+	ctx context.Context,
+	from, until time.Time,
+) ([]models.TurnoverTask, error) {
+	var tasks []models.TurnoverTask
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("status = ? AND due_at >= ? AND due_at < ?", "PENDING", from, until).
+		Preload("Property").
+		Preload("Unit").
+		Preload("AssignedToClientUser.User").
+		Order("property_id, due_at").
+		Find(&tasks).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tasks, nil
+}
+
+func turnoverTaskPropertyIDScope(propertyID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if propertyID != nil {
+			return db.Where("turnover_tasks.property_id = ?", *propertyID)
+		}
+		return db
+	}
+}
+
+func turnoverTaskUnitIDScope(unitID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID != nil {
+			return db.Where("turnover_tasks.unit_id = ?", *unitID)
+		}
+		return db
+	}
+}
+
+func turnoverTaskAssigneeScope(clientUserID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if clientUserID != nil {
+			return db.Where("turnover_tasks.assigned_to_client_user_id = ?", *clientUserID)
+		}
+		return db
+	}
+}
+
+func turnoverTaskStatusScope(status *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if status != nil {
+			return db.Where("turnover_tasks.status = ?", *status)
+		}
+		return db
+	}
+}
+
+func turnoverTaskTypeScope(taskType *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if taskType != nil {
+			return db.Where("turnover_tasks.type = ?", *taskType)
+		}
+		return db
+	}
+}
+
+// turnoverTaskOrderScope lists the most pressing tasks first unless the
+// caller asks for another order: a task list is worked through by due time.
+func turnoverTaskOrderScope(orderBy string, order string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orderBy == "" || order == "" {
+			return db.Order("turnover_tasks.due_at asc")
+		}
+		return OrderScope("turnover_tasks", orderBy, order)(db)
+	}
+}
@@ -0,0 +1,204 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type UnitCalendarSubscriptionRepository interface {
+	Create(ctx context.Context, subscription *models.UnitCalendarSubscription) error
+	GetOne(ctx context.Context, query GetUnitCalendarSubscriptionQuery) (*models.UnitCalendarSubscription, error)
+	// GetByIDForUpdate locks the subscription for the rest of the
+	// transaction, so two syncs of one feed take turns.
+	GetByIDForUpdate(ctx context.Context, id string) (*models.UnitCalendarSubscription, error)
+	Update(ctx context.Context, subscription *models.UnitCalendarSubscription) error
+	Delete(ctx context.Context, input DeleteUnitCalendarSubscriptionInput) error
+	List(ctx context.Context, filter ListUnitCalendarSubscriptionsFilter) (*[]models.UnitCalendarSubscription, error)
+	Count(ctx context.Context, filter ListUnitCalendarSubscriptionsFilter) (int64, error)
+	// ListIDs returns the ID of every subscription, for the periodic sync.
+	ListIDs(ctx context.Context) ([]string, error)
+}
+
+type unitCalendarSubscriptionRepository struct {
+	DB *gorm.DB
+}
+
+func NewUnitCalendarSubscriptionRepository(db *gorm.DB) UnitCalendarSubscriptionRepository {
+	return &unitCalendarSubscriptionRepository{DB: db}
+}
+
+func (r *unitCalendarSubscriptionRepository) Create(
+	ctx context.Context,
+	subscription *models.UnitCalendarSubscription,
+) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Create(subscription).Error
+}
+
+type GetUnitCalendarSubscriptionQuery struct {
+	ID string
+	// PropertyID and UnitID, when set, require the subscription to belong to
+	// them.
+	PropertyID *string
+	UnitID     *string
+	Populate   *[]string
+}
+
+func (r *unitCalendarSubscriptionRepository) GetOne(
+	ctx context.Context,
+	query GetUnitCalendarSubscriptionQuery,
+) (*models.UnitCalendarSubscription, error) {
+	var subscription models.UnitCalendarSubscription
+
+	db := r.DB.WithContext(ctx).Where("unit_calendar_subscriptions.id = ?", query.ID)
+	if query.PropertyID != nil {
+		db = db.Where("unit_calendar_subscriptions.property_id = ?", *query.PropertyID)
+	}
+	if query.UnitID != nil {
+		db = db.Where("unit_calendar_subscriptions.unit_id = ?", *query.UnitID)
+	}
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.First(&subscription); result.Error != nil {
+		return nil, result.Error
+	}
+	return &subscription, nil
+}
+
+func (r *unitCalendarSubscriptionRepository) GetByIDForUpdate(
+	ctx context.Context,
+	id string,
+) (*models.UnitCalendarSubscription, error) {
+	var subscription models.UnitCalendarSubscription
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&subscription)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &subscription, nil
+}
+
+func (r *unitCalendarSubscriptionRepository) Update(
+	ctx context.Context,
+	subscription *models.UnitCalendarSubscription,
+) error {
+	db := lib.ResolveDB(ctx, r.DB)
+	return db.WithContext(ctx).Omit(clause.Associations).Save(subscription).Error
+}
+
+type DeleteUnitCalendarSubscriptionInput struct {
+	ID         string
+	PropertyID string
+	UnitID     string
+}
+
+func (r *unitCalendarSubscriptionRepository) Delete(
+	ctx context.Context,
+	input DeleteUnitCalendarSubscriptionInput,
+) error {
+	db := lib.ResolveDB(ctx, r.DB)
+
+	return db.WithContext(ctx).
+		Where("id = ? AND property_id = ? AND unit_id = ?", input.ID, input.PropertyID, input.UnitID).
+		Delete(&models.UnitCalendarSubscription{}).
+		Error
+}
+
+type ListUnitCalendarSubscriptionsFilter struct {
+	lib.FilterQuery
+	PropertyID string
+	UnitID     string
+	// LastSyncStatus narrows to PENDING, SUCCEEDED or FAILED subscriptions.
+	LastSyncStatus *string
+}
+
+func (r *unitCalendarSubscriptionRepository) List(
+	ctx context.Context,
+	filter ListUnitCalendarSubscriptionsFilter,
+) (*[]models.UnitCalendarSubscription, error) {
+	var subscriptions []models.UnitCalendarSubscription
+
+	db := r.DB.WithContext(ctx).
+		Where(
+			"unit_calendar_subscriptions.property_id = ? AND unit_calendar_subscriptions.unit_id = ?",
+			filter.PropertyID,
+			filter.UnitID,
+		).
+		Scopes(
+			IDsFilterScope("unit_calendar_subscriptions", filter.IDs),
+			unitCalendarSubscriptionStatusScope(filter.LastSyncStatus),
+			DateRangeScope("unit_calendar_subscriptions", filter.DateRange),
+			SearchScope("unit_calendar_subscriptions", filter.Search),
+			PaginationScope(filter.Page, filter.PageSize),
+			OrderScope("unit_calendar_subscriptions", filter.OrderBy, filter.Order),
+		)
+
+	if filter.Populate != nil {
+		for _, field := range *filter.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if result := db.Find(&subscriptions); result.Error != nil {
+		return nil, result.Error
+	}
+	return &subscriptions, nil
+}
+
+func (r *unitCalendarSubscriptionRepository) Count(
+	ctx context.Context,
+	filter ListUnitCalendarSubscriptionsFilter,
+) (int64, error) {
+	var count int64
+
+	result := r.DB.WithContext(ctx).
+		Model(&models.UnitCalendarSubscription{}).
+		Where(
+			"unit_calendar_subscriptions.property_id = ? AND unit_calendar_subscriptions.unit_id = ?",
+			filter.PropertyID,
+			filter.UnitID,
+		).
+		Scopes(
+			IDsFilterScope("unit_calendar_subscriptions", filter.IDs),
+			unitCalendarSubscriptionStatusScope(filter.LastSyncStatus),
+			DateRangeScope("unit_calendar_subscriptions", filter.DateRange),
+			SearchScope("unit_calendar_subscriptions", filter.Search),
+		).
+		Count(&count)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return count, nil
+}
+
+func (r *unitCalendarSubscriptionRepository) ListIDs(ctx context.Context) ([]string, error) {
+	var ids []string
+
+	result := r.DB.WithContext(ctx).
+		Model(&models.UnitCalendarSubscription{}).
+		Order("unit_calendar_subscriptions.created_at ASC").
+		Pluck("unit_calendar_subscriptions.id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+func unitCalendarSubscriptionStatusScope(status *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if status == nil {
+			return db
+		}
+		return db.Where("unit_calendar_subscriptions.last_sync_status = ?", *status)
+	}
+}
@@ -7,14 +7,21 @@ import (
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UnitDateBlockRepository interface {
 	Create(ctx context.Context, block *models.UnitDateBlock) error
 	Delete(ctx context.Context, id string) error
 	DeleteByBookingID(ctx context.Context, bookingID string) error
+	// DeleteTurnoverBlock lifts the TURNOVER block placed when a booking or a
+	// lease ended, whichever ID is set.
+	DeleteTurnoverBlock(ctx context.Context, bookingID, leaseID *string) error
 	GetByID(ctx context.Context, id string) (*models.UnitDateBlock, error)
 	ListByUnit(ctx context.Context, unitID string, from, to time.Time) (*[]models.UnitDateBlock, error)
+	Update(ctx context.Context, block *models.UnitDateBlock) error
+	ListBySubscriptionID(ctx context.Context, subscriptionID string) ([]models.UnitDateBlock, error)
+	DeleteBySubscriptionID(ctx context.Context, subscriptionID string) error
 }
 
 type unitDateBlockRepository struct {
@@ -41,6 +48,17 @@ func (r *unitDateBlockRepository) DeleteByBookingID(ctx context.Context, booking
 		Error
 }
 
+func (r *unitDateBlockRepository) DeleteTurnoverBlock(ctx context.Context, bookingID, leaseID *string) error {
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).Where("block_type = ?", "TURNOVER")
+	if bookingID != nil {
+		db = db.Where("booking_id = ?", *bookingID)
+	} else {
+		db = db.Where("lease_id = ?", leaseID)
+	}
+
+	return db.Delete(&models.UnitDateBlock{}).Error
+}
+
 func (r *unitDateBlockRepository) GetByID(ctx context.Context, id string) (*models.UnitDateBlock, error) {
 	var block models.UnitDateBlock
 	if err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).Where("id = ?", id).First(&block).Error; err != nil {
@@ -61,3 +79,26 @@ func (r *unitDateBlockRepository) ListByUnit(
 		Find(&blocks).Error
 	return &blocks, err
 }
+
+func (r *unitDateBlockRepository) Update(ctx context.Context, block *models.UnitDateBlock) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Omit(clause.Associations).Save(block).Error
+}
+
+func (r *unitDateBlockRepository) ListBySubscriptionID(
+	ctx context.Context,
+	subscriptionID string,
+) ([]models.UnitDateBlock, error) {
+	var blocks []models.UnitDateBlock
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("calendar_subscription_id = ?", subscriptionID).
+		Find(&blocks).Error
+	return blocks, err
+}
+
+func (r *unitDateBlockRepository) DeleteBySubscriptionID(ctx context.Context, subscriptionID string) error {
+	return lib.ResolveDB(ctx, r.DB).
+		WithContext(ctx).
+		Where("calendar_subscription_id = ?", subscriptionID).
+		Delete(&models.UnitDateBlock{}).
+		Error
+}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GeoRadius narrows a search to properties within RadiusKm of a point.
+type GeoRadius struct {
+	Latitude  float64
+	Longitude float64
+	RadiusKm  float64
+}
+
+// ListUnitListingsFilter narrows the public unit search. Only units at
+// active properties of clients that opted in to public listing are ever
+// considered.
+type ListUnitListingsFilter struct {
+	lib.FilterQuery
+	Mode       *string // LEASE | BOOKING
+	City       *string
+	Region     *string
+	Near       *GeoRadius
+	MinRentFee *int64
+	MaxRentFee *int64
+	Type       *string
+	Features   *string // json object the unit's features must contain
+	Guests     *int64
+	CheckIn    *time.Time // booking mode only, with CheckOut
+	CheckOut   *time.Time
+}
+
+// distanceKmSQL is the great-circle distance in km from a point (latitude,
+// longitude, latitude) to a property. acos is clamped so rounding on
+// identical points never takes it out of domain.
+const distanceKmSQL = "6371 * acos(least(1, greatest(-1, " +
+	"cos(radians(?)) * cos(radians(properties.latitude)) * cos(radians(properties.longitude) - radians(?)) + " +
+	"sin(radians(?)) * sin(radians(properties.latitude)))))"
+
+func (r *unitRepository) ListListings(ctx context.Context, filterQuery ListUnitListingsFilter) (*[]models.Unit, error) {
+	var units []models.Unit
+
+	db := r.DB.WithContext(ctx).
+		Scopes(unitListingFilterScopes(filterQuery)...).
+		Scopes(
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			unitListingOrderScope(filterQuery),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	results := db.Find(&units)
+
+	if results.Error != nil {
+		return nil, results.Error
+	}
+	return &units, nil
+}
+
+func (r *unitRepository) CountListings(ctx context.Context, filterQuery ListUnitListingsFilter) (int64, error) {
+	var count int64
+
+	result := r.DB.WithContext(ctx).
+		Model(&models.Unit{}).
+		Scopes(unitListingFilterScopes(filterQuery)...).
+		Count(&count)
+
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return count, nil
+}
+
+// unitListingFilterScopes are the scopes ListListings and CountListings
+// share, so a page and its total always agree.
+func unitListingFilterScopes(filterQuery ListUnitListingsFilter) []func(db *gorm.DB) *gorm.DB {
+	return []func(db *gorm.DB) *gorm.DB{
+		unitListedScope(filterQuery.Mode),
+		unitListingLocationScope(filterQuery.City, filterQuery.Region),
+		unitListingNearScope(filterQuery.Near),
+		unitRentFeeRangeScope(filterQuery.MinRentFee, filterQuery.MaxRentFee),
+		unitTypeScope(filterQuery.Type),
+		unitFeaturesScope(filterQuery.Features),
+		unitGuestsScope(filterQuery.Guests),
+		unitFreeBetweenScope(filterQuery.CheckIn, filterQuery.CheckOut),
+		SearchScope("units", filterQuery.Search),
+	}
+}
+
+// unitListedScope keeps units a guest could take up right now: vacant units
+// at leasing properties, and any unit not in draft or maintenance at booking
+// properties. mode restricts to one of the two.
+func unitListedScope(mode *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.
+			Joins("JOIN properties ON properties.id = units.property_id AND properties.deleted_at IS NULL").
+			Joins("JOIN clients ON clients.id = properties.client_id AND clients.deleted_at IS NULL").
+			Where("clients.public_listing_enabled = ?", true).
+			Where("properties.status = ?", "Property.Status.Active")
+
+		leasable := db.Session(&gorm.Session{NewDB: true}).
+			Where("? = ANY(properties.modes)", "LEASE").
+			Where("units.status IN ?", []string{"Unit.Status.Available", "Unit.Status.PartiallyOccupied"})
+		bookable := db.Session(&gorm.Session{NewDB: true}).
+			Where("? = ANY(properties.modes)", "BOOKING").
+			Where("units.status NOT IN ?", []string{"Unit.Status.Draft", "Unit.Status.Maintenance"})
+
+		switch {
+		case mode == nil:
+			return db.Where(leasable.Or(bookable))
+		case *mode == "LEASE":
+			return db.Where(leasable)
+		default:
+			return db.Where(bookable)
+		}
+	}
+}
+
+func unitListingLocationScope(city, region *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if city != nil {
+			db = db.Where("properties.city ILIKE ?", *city)
+		}
+		if region != nil {
+			db = db.Where("properties.region ILIKE ?", *region)
+		}
+		return db
+	}
+}
+
+func unitListingNearScope(near *GeoRadius) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if near == nil {
+			return db
+		}
+
+		return db.Where(
+			distanceKmSQL+" <= ?",
+			near.Latitude, near.Longitude, near.Latitude, near.RadiusKm,
+		)
+	}
+}
+
+func unitRentFeeRangeScope(minRentFee, maxRentFee *int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if minRentFee != nil {
+			db = db.Where("units.rent_fee >= ?", *minRentFee)
+		}
+		if maxRentFee != nil {
+			db = db.Where("units.rent_fee <= ?", *maxRentFee)
+		}
+		return db
+	}
+}
+
+func unitFeaturesScope(features *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if features == nil {
+			return db
+		}
+
+		return db.Where("units.features @> ?::jsonb", *features)
+	}
+}
+
+func unitGuestsScope(guests *int64) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if guests == nil {
+			return db
+		}
+
+		return db.Where("units.max_occupants_allowed >= ?", *guests)
+	}
+}
+
+// unitFreeBetweenScope drops units with a date block on any night between
+// checkIn and checkOut, the same overlap HasOverlappingBlock refuses a
+// booking for.
+func unitFreeBetweenScope(checkIn, checkOut *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if checkIn == nil || checkOut == nil {
+			return db
+		}
+
+		blocked := db.Session(&gorm.Session{NewDB: true}).
+			Table("unit_date_blocks").
+			Select("1").
+			Where("unit_date_blocks.unit_id = units.id AND unit_date_blocks.deleted_at IS NULL").
+			Where("unit_date_blocks.start_date < ? AND unit_date_blocks.end_date > ?", *checkOut, *checkIn)
+
+		return db.Where("NOT EXISTS (?)", blocked)
+	}
+}
+
+// unitListingOrderScope orders by created_at, rent_fee or, for a search
+// near a point, distance. order_by is matched rather than interpolated as
+// OrderScope does, because it comes from an unauthenticated caller.
+func unitListingOrderScope(filterQuery ListUnitListingsFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		direction := "DESC"
+		if filterQuery.Order == "asc" {
+			direction = "ASC"
+		}
+
+		switch {
+		case filterQuery.OrderBy == "rent_fee":
+			return db.Order(fmt.Sprintf("units.rent_fee %s, units.id", direction))
+		case filterQuery.OrderBy == "distance" && filterQuery.Near != nil:
+			near := filterQuery.Near
+			if filterQuery.Order == "" {
+				direction = "ASC"
+			}
+			return db.Order(clause.OrderBy{Expression: clause.Expr{
+				SQL:                distanceKmSQL + " " + direction + ", units.id",
+				Vars:               []any{near.Latitude, near.Longitude, near.Latitude},
+				WithoutParentheses: true,
+			}})
+		default:
+			return db.Order(fmt.Sprintf("units.created_at %s, units.id", direction))
+		}
+	}
+}
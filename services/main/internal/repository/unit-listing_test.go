@@ -0,0 +1,127 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// listUnitListingsSQL renders the statement ListListings sends for a filter.
+func listUnitListingsSQL(t *testing.T, filterQuery ListUnitListingsFilter) string {
+	t.Helper()
+
+	var units []models.Unit
+	statement := dryRunDB(t).
+		Scopes(unitListingFilterScopes(filterQuery)...).
+		Scopes(unitListingOrderScope(filterQuery)).
+		Find(&units).
+		Statement
+
+	return statement.SQL.String()
+}
+
+// The search is public, so only units of opted-in clients at active
+// properties may show up, whatever else is asked for.
+func TestListUnitListingsOnlyListedUnits(t *testing.T) {
+	sql := listUnitListingsSQL(t, ListUnitListingsFilter{})
+
+	for _, want := range []string{
+		"clients.public_listing_enabled = $1",
+		"properties.status = $2",
+		"properties.deleted_at IS NULL",
+		"clients.deleted_at IS NULL",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("listing search is missing %q:\n%s", want, sql)
+		}
+	}
+
+	// Both modes are one grouped OR, so neither branch escapes the filters
+	// around it.
+	if !strings.Contains(sql, "= ANY(properties.modes) AND units.status IN") ||
+		!strings.Contains(sql, ") OR (") {
+		t.Errorf("lease and booking listings are not grouped:\n%s", sql)
+	}
+}
+
+func TestListUnitListingsMode(t *testing.T) {
+	lease := "LEASE"
+	sql := listUnitListingsSQL(t, ListUnitListingsFilter{Mode: &lease})
+
+	if strings.Contains(sql, "units.status NOT IN") {
+		t.Errorf("lease search still includes bookable units:\n%s", sql)
+	}
+	if !strings.Contains(sql, "units.status IN") {
+		t.Errorf("lease search does not require a vacant unit:\n%s", sql)
+	}
+}
+
+func TestListUnitListingsFilters(t *testing.T) {
+	city := "Accra"
+	minRentFee, maxRentFee := int64(1000), int64(5000)
+	features := `{"bedrooms":2}`
+	guests := int64(3)
+	checkIn := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	checkOut := checkIn.AddDate(0, 0, 3)
+
+	sql := listUnitListingsSQL(t, ListUnitListingsFilter{
+		City:       &city,
+		Near:       &GeoRadius{Latitude: 5.6037, Longitude: -0.187, RadiusKm: 10},
+		MinRentFee: &minRentFee,
+		MaxRentFee: &maxRentFee,
+		Features:   &features,
+		Guests:     &guests,
+		CheckIn:    &checkIn,
+		CheckOut:   &checkOut,
+	})
+
+	for _, want := range []string{
+		"properties.city ILIKE",
+		"6371 * acos(",
+		"units.rent_fee >=",
+		"units.rent_fee <=",
+		"units.features @>",
+		"units.max_occupants_allowed >=",
+		"NOT EXISTS (SELECT 1 FROM \"unit_date_blocks\"",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("listing search is missing %q:\n%s", want, sql)
+		}
+	}
+}
+
+// order_by comes off an unauthenticated query string; anything but the known
+// sort keys must fall back to newest first instead of reaching the SQL.
+func TestListUnitListingsOrder(t *testing.T) {
+	cases := []struct {
+		name    string
+		orderBy string
+		near    *GeoRadius
+		want    string
+	}{
+		{"default", "", nil, "ORDER BY units.created_at DESC"},
+		{"rent fee", "rent_fee", nil, "ORDER BY units.rent_fee DESC"},
+		{"distance", "distance", &GeoRadius{Latitude: 5.6, Longitude: -0.18, RadiusKm: 5}, "ORDER BY 6371 * acos("},
+		{"distance without a point", "distance", nil, "ORDER BY units.created_at DESC"},
+		{"injection", "id; DROP TABLE units", nil, "ORDER BY units.created_at DESC"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			sql := listUnitListingsSQL(t, ListUnitListingsFilter{
+				FilterQuery: lib.FilterQuery{OrderBy: tc.orderBy},
+				Near:        tc.near,
+			})
+
+			if !strings.Contains(sql, tc.want) {
+				t.Errorf("want %q in:\n%s", tc.want, sql)
+			}
+			if strings.Contains(sql, "DROP TABLE") {
+				t.Errorf("order_by reached the SQL:\n%s", sql)
+			}
+		})
+	}
+}
@@ -6,15 +6,21 @@ import (
 	"github.com/Bendomey/rent-loop/services/main/internal/lib"
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UnitRepository interface {
 	List(context context.Context, filterQuery ListUnitsFilter) (*[]models.Unit, error)
 	Count(context context.Context, filterQuery ListUnitsFilter) (int64, error)
+	ListListings(context context.Context, filterQuery ListUnitListingsFilter) (*[]models.Unit, error)
+	CountListings(context context.Context, filterQuery ListUnitListingsFilter) (int64, error)
 	Create(context context.Context, unit *models.Unit) error
 	GetOneWithQuery(context context.Context, query GetUnitQuery) (*models.Unit, error)
 	GetOneWithQuerySlug(context context.Context, query GetUnitQuerySlug) (*models.Unit, error)
 	GetOne(context context.Context, query map[string]any) (*models.Unit, error)
+	// GetByIDForUpdate reads the unit, locking its row until the caller's
+	// transaction ends.
+	GetByIDForUpdate(context context.Context, unitID string) (*models.Unit, error)
 	Update(context context.Context, unit *models.Unit) error
 	Delete(context context.Context, input DeleteUnitInput) error
 }
@@ -99,6 +105,19 @@ func (r *unitRepository) GetOne(ctx context.Context, query map[string]any) (*mod
 	return &unit, nil
 }
 
+func (r *unitRepository) GetByIDForUpdate(ctx context.Context, unitID string) (*models.Unit, error) {
+	var unit models.Unit
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", unitID).
+		First(&unit)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &unit, nil
+}
+
 func (r *unitRepository) Update(ctx context.Context, unit *models.Unit) error {
 	db := lib.ResolveDB(ctx, r.DB)
 	return db.WithContext(ctx).Save(unit).Error
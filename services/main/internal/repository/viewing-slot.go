@@ -0,0 +1,246 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ViewingSlotRepository interface {
+	Create(ctx context.Context, slot *models.ViewingSlot) error
+	Update(ctx context.Context, slot *models.ViewingSlot) error
+	GetByIDWithPopulate(ctx context.Context, query GetViewingSlotQuery) (*models.ViewingSlot, error)
+	// GetForUpdate locks the slot until the transaction ends, so two
+	// prospects cannot both take its last place.
+	GetForUpdate(ctx context.Context, id string) (*models.ViewingSlot, error)
+	List(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListViewingSlotsFilter,
+	) ([]models.ViewingSlot, error)
+	Count(ctx context.Context, filterQuery lib.FilterQuery, filters ListViewingSlotsFilter) (int64, error)
+	// CountOverlapping counts the staff member's OPEN slots that overlap
+	// startsAt to endsAt.
+	CountOverlapping(ctx context.Context, clientUserID string, startsAt, endsAt time.Time) (int64, error)
+}
+
+type viewingSlotRepository struct {
+	DB *gorm.DB
+}
+
+func NewViewingSlotRepository(db *gorm.DB) ViewingSlotRepository {
+	return &viewingSlotRepository{DB: db}
+}
+
+type ListViewingSlotsFilter struct {
+	PropertyID   *string
+	ClientUserID *string
+	Status       *string
+	StartsFrom   *time.Time
+	StartsTo     *time.Time
+	// BookableAt keeps the OPEN slots that have not started by then and
+	// still have a place left.
+	BookableAt *time.Time
+}
+
+type GetViewingSlotQuery struct {
+	ID         string
+	PropertyID *string
+	Populate   *[]string
+}
+
+func (r *viewingSlotRepository) Create(ctx context.Context, slot *models.ViewingSlot) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(slot).Error
+}
+
+func (r *viewingSlotRepository) Update(ctx context.Context, slot *models.ViewingSlot) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Save(slot).Error
+}
+
+func (r *viewingSlotRepository) GetByIDWithPopulate(
+	ctx context.Context,
+	query GetViewingSlotQuery,
+) (*models.ViewingSlot, error) {
+	var slot models.ViewingSlot
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("id = ?", query.ID).
+		Scopes(viewingSlotPropertyIDScope(query.PropertyID))
+
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.First(&slot).Error; err != nil {
+		return nil, err
+	}
+
+	return &slot, nil
+}
+
+func (r *viewingSlotRepository) GetForUpdate(ctx context.Context, id string) (*models.ViewingSlot, error) {
+	var slot models.ViewingSlot
+
+	result := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&slot)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	return &slot, nil
+}
+
+func (r *viewingSlotRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListViewingSlotsFilter,
+) ([]models.ViewingSlot, error) {
+	var slots []models.ViewingSlot
+
+	db := r.DB.WithContext(ctx).
+		Scopes(
+			IDsFilterScope("viewing_slots", filterQuery.IDs),
+			DateRangeScope("viewing_slots", filterQuery.DateRange),
+			viewingSlotPropertyIDScope(filters.PropertyID),
+			viewingSlotClientUserIDScope(filters.ClientUserID),
+			viewingSlotStatusScope(filters.Status),
+			viewingSlotStartsBetweenScope(filters.StartsFrom, filters.StartsTo),
+			viewingSlotBookableScope(filters.BookableAt),
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			viewingSlotOrderScope(filterQuery.OrderBy, filterQuery.Order),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.Find(&slots).Error; err != nil {
+		return nil, err
+	}
+
+	return slots, nil
+}
+
+func (r *viewingSlotRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListViewingSlotsFilter,
+) (int64, error) {
+	var count int64
+
+	err := r.DB.WithContext(ctx).
+		Model(&models.ViewingSlot{}).
+		Scopes(
+			IDsFilterScope("viewing_slots", filterQuery.IDs),
+			DateRangeScope("viewing_slots", filterQuery.DateRange),
+			viewingSlotPropertyIDScope(filters.PropertyID),
+			viewingSlotClientUserIDScope(filters.ClientUserID),
+			viewingSlotStatusScope(filters.Status),
+			viewingSlotStartsBetweenScope(filters.StartsFrom, filters.StartsTo),
+			viewingSlotBookableScope(filters.BookableAt),
+		).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *viewingSlotRepository) CountOverlapping(
+	ctx context.Context,
+	clientUserID string,
+	startsAt, endsAt time.Time,
+) (int64, error) {
+	var count int64
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.ViewingSlot{}).
+		Where("client_user_id = ? AND status = ?", clientUserID, "OPEN").
+		Where("starts_at < ? AND ends_at > ?", endsAt, startsAt).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func viewingSlotPropertyIDScope(propertyID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if propertyID != nil {
+			return db.Where("viewing_slots.property_id = ?", *propertyID)
+		}
+		return db
+	}
+}
+
+func viewingSlotClientUserIDScope(clientUserID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if clientUserID != nil {
+			return db.Where("viewing_slots.client_user_id = ?", *clientUserID)
+		}
+		return db
+	}
+}
+
+func viewingSlotStatusScope(status *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if status != nil {
+			return db.Where("viewing_slots.status = ?", *status)
+		}
+		return db
+	}
+}
+
+func viewingSlotStartsBetweenScope(from, to *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if from != nil {
+			db = db.Where("viewing_slots.starts_at >= ?", *from)
+		}
+		if to != nil {
+			db = db.Where("viewing_slots.starts_at < ?", *to)
+		}
+		return db
+	}
+}
+
+// viewingSlotBookableScope keeps the OPEN slots that start after at and hold
+// fewer SCHEDULED viewings than their capacity.
+func viewingSlotBookableScope(at *time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if at == nil {
+			return db
+		}
+		return db.
+			Where("viewing_slots.status = ?", "OPEN").
+			Where("viewing_slots.starts_at > ?", *at).
+			Where(`(
+				SELECT COUNT(*) FROM viewings v
+				WHERE v.viewing_slot_id = viewing_slots.id::text
+				  AND v.status = 'SCHEDULED'
+				  AND v.deleted_at IS NULL
+			) < viewing_slots.capacity`)
+	}
+}
+
+// viewingSlotOrderScope lists slots soonest first unless the caller asks for
+// another order.
+func viewingSlotOrderScope(orderBy string, order string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orderBy == "" || order == "" {
+			return db.Order("viewing_slots.starts_at asc")
+		}
+		return OrderScope("viewing_slots", orderBy, order)(db)
+	}
+}
@@ -0,0 +1,43 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// Prospects are only offered slots they can still take: open, not yet
+// started, and with fewer viewings booked than the slot has places. A
+// cancelled viewing frees its place.
+func TestViewingSlotBookableScope(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	var slots []models.ViewingSlot
+	sql := dryRunDB(t).
+		Scopes(viewingSlotBookableScope(&at)).
+		Find(&slots).
+		Statement.SQL.String()
+
+	for _, want := range []string{
+		"viewing_slots.status = $1",
+		"viewing_slots.starts_at > $2",
+		"v.viewing_slot_id = viewing_slots.id::text",
+		"v.status = 'SCHEDULED'",
+		"v.deleted_at IS NULL",
+		") < viewing_slots.capacity",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("bookable slots are missing %q:\n%s", want, sql)
+		}
+	}
+
+	unfiltered := dryRunDB(t).
+		Scopes(viewingSlotBookableScope(nil)).
+		Find(&slots).
+		Statement.SQL.String()
+	if strings.Contains(unfiltered, "capacity") {
+		t.Errorf("no bookable time should leave every slot in:\n%s", unfiltered)
+	}
+}
@@ -0,0 +1,253 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type ViewingRepository interface {
+	Create(ctx context.Context, viewing *models.Viewing) error
+	Update(ctx context.Context, viewing *models.Viewing) error
+	GetByIDWithPopulate(ctx context.Context, query GetViewingQuery) (*models.Viewing, error)
+	GetByCode(ctx context.Context, code string, populate *[]string) (*models.Viewing, error)
+	List(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListViewingsFilter,
+	) ([]models.Viewing, error)
+	Count(ctx context.Context, filterQuery lib.FilterQuery, filters ListViewingsFilter) (int64, error)
+	// CountScheduledForSlot counts the places taken in a slot.
+	CountScheduledForSlot(ctx context.Context, slotID string) (int64, error)
+	// CountScheduledForUnitByPhone counts the viewings of a unit a phone
+	// number has coming up.
+	CountScheduledForUnitByPhone(ctx context.Context, unitID, phone string) (int64, error)
+	// CountNoShowsByPhone counts the viewings at a property a phone number
+	// booked and did not turn up to.
+	CountNoShowsByPhone(ctx context.Context, propertyID, phone string) (int64, error)
+}
+
+type viewingRepository struct {
+	DB *gorm.DB
+}
+
+func NewViewingRepository(db *gorm.DB) ViewingRepository {
+	return &viewingRepository{DB: db}
+}
+
+type ListViewingsFilter struct {
+	PropertyID    *string
+	UnitID        *string
+	ViewingSlotID *string
+	ClientUserID  *string
+	Status        *string
+	Phone         *string
+}
+
+type GetViewingQuery struct {
+	ID         string
+	PropertyID *string
+	Populate   *[]string
+}
+
+func (r *viewingRepository) Create(ctx context.Context, viewing *models.Viewing) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(viewing).Error
+}
+
+func (r *viewingRepository) Update(ctx context.Context, viewing *models.Viewing) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Save(viewing).Error
+}
+
+func (r *viewingRepository) GetByIDWithPopulate(ctx context.Context, query GetViewingQuery) (*models.Viewing, error) {
+	var viewing models.Viewing
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("id = ?", query.ID).
+		Scopes(viewingPropertyIDScope(query.PropertyID))
+
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.First(&viewing).Error; err != nil {
+		return nil, err
+	}
+
+	return &viewing, nil
+}
+
+func (r *viewingRepository) GetByCode(ctx context.Context, code string, populate *[]string) (*models.Viewing, error) {
+	var viewing models.Viewing
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).Where("code = ?", code)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.First(&viewing).Error; err != nil {
+		return nil, err
+	}
+
+	return &viewing, nil
+}
+
+func (r *viewingRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListViewingsFilter,
+) ([]models.Viewing, error) {
+	var viewings []models.Viewing
+
+	db := r.DB.WithContext(ctx).
+		Scopes(
+			IDsFilterScope("viewings", filterQuery.IDs),
+			DateRangeScope("viewings", filterQuery.DateRange),
+			SearchScope("viewings", filterQuery.Search),
+			viewingPropertyIDScope(filters.PropertyID),
+			viewingUnitIDScope(filters.UnitID),
+			viewingViewingSlotIDScope(filters.ViewingSlotID),
+			viewingClientUserIDScope(filters.ClientUserID),
+			viewingStatusScope(filters.Status),
+			viewingPhoneScope(filters.Phone),
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			viewingOrderScope(filterQuery.OrderBy, filterQuery.Order),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.Find(&viewings).Error; err != nil {
+		return nil, err
+	}
+
+	return viewings, nil
+}
+
+func (r *viewingRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListViewingsFilter,
+) (int64, error) {
+	var count int64
+
+	err := r.DB.WithContext(ctx).
+		Model(&models.Viewing{}).
+		Scopes(
+			IDsFilterScope("viewings", filterQuery.IDs),
+			DateRangeScope("viewings", filterQuery.DateRange),
+			SearchScope("viewings", filterQuery.Search),
+			viewingPropertyIDScope(filters.PropertyID),
+			viewingUnitIDScope(filters.UnitID),
+			viewingViewingSlotIDScope(filters.ViewingSlotID),
+			viewingClientUserIDScope(filters.ClientUserID),
+			viewingStatusScope(filters.Status),
+			viewingPhoneScope(filters.Phone),
+		).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *viewingRepository) CountScheduledForSlot(ctx context.Context, slotID string) (int64, error) {
+	return r.countWhere(ctx, "viewing_slot_id = ? AND status = ?", slotID, "SCHEDULED")
+}
+
+func (r *viewingRepository) CountScheduledForUnitByPhone(ctx context.Context, unitID, phone string) (int64, error) {
+	return r.countWhere(ctx, "unit_id = ? AND phone = ? AND status = ?", unitID, phone, "SCHEDULED")
+}
+
+func (r *viewingRepository) CountNoShowsByPhone(ctx context.Context, propertyID, phone string) (int64, error) {
+	return r.countWhere(ctx, "property_id = ? AND phone = ? AND status = ?", propertyID, phone, "NO_SHOW")
+}
+
+func (r *viewingRepository) countWhere(ctx context.Context, query string, args ...any) (int64, error) {
+	var count int64
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.Viewing{}).
+		Where(query, args...).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func viewingPropertyIDScope(propertyID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if propertyID != nil {
+			return db.Where("viewings.property_id = ?", *propertyID)
+		}
+		return db
+	}
+}
+
+func viewingUnitIDScope(unitID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID != nil {
+			return db.Where("viewings.unit_id = ?", *unitID)
+		}
+		return db
+	}
+}
+
+func viewingViewingSlotIDScope(viewingSlotID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if viewingSlotID != nil {
+			return db.Where("viewings.viewing_slot_id = ?", *viewingSlotID)
+		}
+		return db
+	}
+}
+
+func viewingClientUserIDScope(clientUserID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if clientUserID != nil {
+			return db.Where("viewings.client_user_id = ?", *clientUserID)
+		}
+		return db
+	}
+}
+
+func viewingStatusScope(status *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if status != nil {
+			return db.Where("viewings.status = ?", *status)
+		}
+		return db
+	}
+}
+
+func viewingPhoneScope(phone *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if phone != nil {
+			return db.Where("viewings.phone = ?", *phone)
+		}
+		return db
+	}
+}
+
+// viewingOrderScope lists viewings soonest first unless the caller asks for
+// another order.
+func viewingOrderScope(orderBy string, order string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orderBy == "" || order == "" {
+			return db.Order("viewings.scheduled_at asc")
+		}
+		return OrderScope("viewings", orderBy, order)(db)
+	}
+}
@@ -0,0 +1,283 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"gorm.io/gorm"
+)
+
+type WaitlistEntryRepository interface {
+	Create(ctx context.Context, entry *models.WaitlistEntry) error
+	Update(ctx context.Context, entry *models.WaitlistEntry) error
+	GetByIDWithPopulate(ctx context.Context, query GetWaitlistEntryQuery) (*models.WaitlistEntry, error)
+	GetByInviteToken(ctx context.Context, token string, populate *[]string) (*models.WaitlistEntry, error)
+	List(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters ListWaitlistEntriesFilter,
+	) ([]models.WaitlistEntry, error)
+	Count(ctx context.Context, filterQuery lib.FilterQuery, filters ListWaitlistEntriesFilter) (int64, error)
+	// CountOpenByPhone counts the WAITING and INVITED entries a phone number
+	// holds at a property.
+	CountOpenByPhone(ctx context.Context, propertyID, phone string) (int64, error)
+	// ListWaitingForUnit returns up to limit WAITING entries that would take
+	// unit from availableFrom, in the order they are invited.
+	ListWaitingForUnit(
+		ctx context.Context,
+		unit *models.Unit,
+		availableFrom time.Time,
+		limit int,
+	) ([]models.WaitlistEntry, error)
+	// HasLiveInvite reports whether an entry holds an invite to unitID that
+	// has not run out by now.
+	HasLiveInvite(ctx context.Context, unitID string, now time.Time) (bool, error)
+}
+
+type waitlistEntryRepository struct {
+	DB *gorm.DB
+}
+
+func NewWaitlistEntryRepository(db *gorm.DB) WaitlistEntryRepository {
+	return &waitlistEntryRepository{DB: db}
+}
+
+type ListWaitlistEntriesFilter struct {
+	PropertyID *string
+	UnitID     *string
+	UnitType   *string
+	Status     *string
+}
+
+type GetWaitlistEntryQuery struct {
+	ID         string
+	PropertyID *string
+	Populate   *[]string
+}
+
+func (r *waitlistEntryRepository) Create(ctx context.Context, entry *models.WaitlistEntry) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Create(entry).Error
+}
+
+func (r *waitlistEntryRepository) Update(ctx context.Context, entry *models.WaitlistEntry) error {
+	return lib.ResolveDB(ctx, r.DB).WithContext(ctx).Save(entry).Error
+}
+
+func (r *waitlistEntryRepository) GetByIDWithPopulate(
+	ctx context.Context,
+	query GetWaitlistEntryQuery,
+) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Where("id = ?", query.ID).
+		Scopes(waitlistEntryPropertyIDScope(query.PropertyID))
+
+	if query.Populate != nil {
+		for _, field := range *query.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.First(&entry).Error; err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *waitlistEntryRepository) GetByInviteToken(
+	ctx context.Context,
+	token string,
+	populate *[]string,
+) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+
+	db := lib.ResolveDB(ctx, r.DB).WithContext(ctx).Where("invite_token = ?", token)
+
+	if populate != nil {
+		for _, field := range *populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.First(&entry).Error; err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+func (r *waitlistEntryRepository) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListWaitlistEntriesFilter,
+) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+
+	db := r.DB.WithContext(ctx).
+		Scopes(
+			IDsFilterScope("waitlist_entries", filterQuery.IDs),
+			DateRangeScope("waitlist_entries", filterQuery.DateRange),
+			SearchScope("waitlist_entries", filterQuery.Search),
+			waitlistEntryPropertyIDScope(filters.PropertyID),
+			waitlistEntryUnitIDScope(filters.UnitID),
+			waitlistEntryUnitTypeScope(filters.UnitType),
+			waitlistEntryStatusScope(filters.Status),
+			PaginationScope(filterQuery.Page, filterQuery.PageSize),
+			waitlistEntryOrderScope(filterQuery.OrderBy, filterQuery.Order),
+		)
+
+	if filterQuery.Populate != nil {
+		for _, field := range *filterQuery.Populate {
+			db = db.Preload(field)
+		}
+	}
+
+	if err := db.Find(&entries).Error; err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *waitlistEntryRepository) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters ListWaitlistEntriesFilter,
+) (int64, error) {
+	var count int64
+
+	err := r.DB.WithContext(ctx).
+		Model(&models.WaitlistEntry{}).
+		Scopes(
+			IDsFilterScope("waitlist_entries", filterQuery.IDs),
+			DateRangeScope("waitlist_entries", filterQuery.DateRange),
+			SearchScope("waitlist_entries", filterQuery.Search),
+			waitlistEntryPropertyIDScope(filters.PropertyID),
+			waitlistEntryUnitIDScope(filters.UnitID),
+			waitlistEntryUnitTypeScope(filters.UnitType),
+			waitlistEntryStatusScope(filters.Status),
+		).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *waitlistEntryRepository) CountOpenByPhone(ctx context.Context, propertyID, phone string) (int64, error) {
+	var count int64
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.WaitlistEntry{}).
+		Where("property_id = ? AND phone = ? AND status IN ?", propertyID, phone, []string{"WAITING", "INVITED"}).
+		Count(&count).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *waitlistEntryRepository) ListWaitingForUnit(
+	ctx context.Context,
+	unit *models.Unit,
+	availableFrom time.Time,
+	limit int,
+) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Scopes(waitlistEntryWaitingForUnitScope(unit, availableFrom)).
+		Order("waitlist_entries.priority desc, waitlist_entries.created_at asc").
+		Limit(limit).
+		Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+func (r *waitlistEntryRepository) HasLiveInvite(ctx context.Context, unitID string, now time.Time) (bool, error) {
+	var count int64
+
+	err := lib.ResolveDB(ctx, r.DB).WithContext(ctx).
+		Model(&models.WaitlistEntry{}).
+		Where("invited_unit_id = ? AND status = ? AND invite_expires_at > ?", unitID, "INVITED", now).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// waitlistEntryWaitingForUnitScope keeps the WAITING entries unit suits: at
+// its property, for it or its type or any unit, within budget, and with a
+// move-in window that has not closed by availableFrom.
+func waitlistEntryWaitingForUnitScope(unit *models.Unit, availableFrom time.Time) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.
+			Where("waitlist_entries.status = ?", "WAITING").
+			Where("waitlist_entries.property_id = ?", unit.PropertyID).
+			Where("(waitlist_entries.unit_id IS NULL OR waitlist_entries.unit_id = ?)", unit.ID.String()).
+			Where("(waitlist_entries.unit_type IS NULL OR waitlist_entries.unit_type = ?)", unit.Type).
+			Where("(waitlist_entries.max_rent_fee IS NULL OR waitlist_entries.max_rent_fee >= ?)", unit.RentFee).
+			Where(
+				"(waitlist_entries.desired_move_in_to IS NULL OR waitlist_entries.desired_move_in_to >= ?)",
+				availableFrom.Format(time.DateOnly),
+			)
+	}
+}
+
+func waitlistEntryPropertyIDScope(propertyID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if propertyID != nil {
+			return db.Where("waitlist_entries.property_id = ?", *propertyID)
+		}
+		return db
+	}
+}
+
+func waitlistEntryUnitIDScope(unitID *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitID != nil {
+			return db.Where("waitlist_entries.unit_id = ?", *unitID)
+		}
+		return db
+	}
+}
+
+func waitlistEntryUnitTypeScope(unitType *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if unitType != nil {
+			return db.Where("waitlist_entries.unit_type = ?", *unitType)
+		}
+		return db
+	}
+}
+
+func waitlistEntryStatusScope(status *string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if status != nil {
+			return db.Where("waitlist_entries.status = ?", *status)
+		}
+		return db
+	}
+}
+
+// waitlistEntryOrderScope lists entries in the order they would be invited
+// unless the caller asks for another order.
+func waitlistEntryOrderScope(orderBy string, order string) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if orderBy == "" || order == "" {
+			return db.Order("waitlist_entries.priority desc, waitlist_entries.created_at asc")
+		}
+		return OrderScope("waitlist_entries", orderBy, order)(db)
+	}
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+// A freed unit is offered to the waitlist in priority order, so the match
+// must narrow to the unit's property and leave open every preference a
+// prospect did not state -- an entry with no unit, type or budget waits for
+// any unit there.
+func TestWaitlistEntryWaitingForUnitScope(t *testing.T) {
+	unit := &models.Unit{
+		PropertyID: "b7d2f5c1-0000-4000-8000-000000000000",
+		Type:       "APARTMENT",
+		RentFee:    250000,
+	}
+	unit.ID = uuid.FromStringOrNil("a7d2f5c1-0000-4000-8000-000000000000")
+
+	var entries []models.WaitlistEntry
+	sql := dryRunDB(t).
+		Scopes(waitlistEntryWaitingForUnitScope(unit, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC))).
+		Find(&entries).
+		Statement.SQL.String()
+
+	for _, want := range []string{
+		"waitlist_entries.status = $1",
+		"waitlist_entries.property_id = $2",
+		"(waitlist_entries.unit_id IS NULL OR waitlist_entries.unit_id = $3)",
+		"(waitlist_entries.unit_type IS NULL OR waitlist_entries.unit_type = $4)",
+		"(waitlist_entries.max_rent_fee IS NULL OR waitlist_entries.max_rent_fee >= $5)",
+		"(waitlist_entries.desired_move_in_to IS NULL OR waitlist_entries.desired_move_in_to >= $6)",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("waitlist match is missing %q:\n%s", want, sql)
+		}
+	}
+}
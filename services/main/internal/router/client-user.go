@@ -24,6 +24,8 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 			// signing (token-based auth, no JWT required)
 			r.Get("/v1/signing/{token}/verify", handlers.SigningHandler.VerifyToken)
 			r.Post("/v1/signing/{token}/sign", handlers.SigningHandler.SignDocument)
+			r.Post("/v1/signing/{token}/otp", handlers.SigningHandler.SendSigningOtp)
+			r.Post("/v1/signing/{token}/otp/verify", handlers.SigningHandler.VerifySigningOtp)
 
 			r.Post(
 				"/v1/tenant-applications",
@@ -179,12 +181,46 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
 									Patch("/status:available", handlers.UnitHandler.UpdateUnitToAvailableStatus)
 								r.Get("/availability", handlers.BookingHandler.GetAvailability)
+								r.Get("/quote", handlers.BookingHandler.QuoteBooking)
 								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
 									Post("/date-blocks", handlers.BookingHandler.CreateDateBlock)
 								r.Route("/date-blocks/{block_id}", func(r chi.Router) {
 									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
 										Delete("/", handlers.BookingHandler.DeleteDateBlock)
 								})
+								r.Route("/calendar-subscriptions", func(r chi.Router) {
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Post("/", handlers.BookingHandler.CreateUnitCalendarSubscription)
+									r.Get("/", handlers.BookingHandler.ListUnitCalendarSubscriptions)
+									r.Route("/{subscription_id}", func(r chi.Router) {
+										r.Get("/", handlers.BookingHandler.GetUnitCalendarSubscription)
+										r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+											Patch("/", handlers.BookingHandler.UpdateUnitCalendarSubscription)
+										r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+											Delete("/", handlers.BookingHandler.DeleteUnitCalendarSubscription)
+										r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+											Post("/sync", handlers.BookingHandler.SyncUnitCalendarSubscription)
+									})
+								})
+								r.Get("/calendar-export", handlers.BookingHandler.GetUnitCalendarExport)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/calendar-export", handlers.BookingHandler.EnableUnitCalendarExport)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Delete("/calendar-export", handlers.BookingHandler.DisableUnitCalendarExport)
+							})
+						})
+
+						// short-stay pricing rules
+						r.Route("/booking-pricing-rules", func(r chi.Router) {
+							r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+								Post("/", handlers.BookingHandler.CreateBookingPricingRule)
+							r.Get("/", handlers.BookingHandler.ListBookingPricingRules)
+							r.Route("/{rule_id}", func(r chi.Router) {
+								r.Get("/", handlers.BookingHandler.GetBookingPricingRule)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/", handlers.BookingHandler.UpdateBookingPricingRule)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Delete("/", handlers.BookingHandler.DeleteBookingPricingRule)
 							})
 						})
 
@@ -205,6 +241,64 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 									Patch("/complete", handlers.BookingHandler.CompleteBooking)
 								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
 									Patch("/cancel", handlers.BookingHandler.CancelBooking)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/refund/paid", handlers.BookingHandler.MarkBookingRefundPaid)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/hold", handlers.BookingHandler.ExtendBookingHold)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Put("/guests", handlers.BookingHandler.SetBookingGuests)
+								r.Get("/modifications", handlers.BookingHandler.ListBookingModifications)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/modifications", handlers.BookingHandler.ModifyBooking)
+							})
+						})
+
+						// turnover tasks
+						r.Route("/turnover-tasks", func(r chi.Router) {
+							r.Get("/", handlers.TurnoverTaskHandler.ListTurnoverTasks)
+							r.Route("/{turnover_task_id}", func(r chi.Router) {
+								r.Get("/", handlers.TurnoverTaskHandler.GetTurnoverTask)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/assign", handlers.TurnoverTaskHandler.AssignTurnoverTask)
+								r.Patch("/complete", handlers.TurnoverTaskHandler.CompleteTurnoverTask)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/cancel", handlers.TurnoverTaskHandler.CancelTurnoverTask)
+							})
+						})
+
+						// waitlist
+						r.Route("/waitlist", func(r chi.Router) {
+							r.Get("/", handlers.WaitlistHandler.ListWaitlistEntries)
+							r.Route("/{waitlist_entry_id}", func(r chi.Router) {
+								r.Get("/", handlers.WaitlistHandler.GetWaitlistEntry)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/", handlers.WaitlistHandler.UpdateWaitlistEntry)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/remove", handlers.WaitlistHandler.RemoveWaitlistEntry)
+							})
+						})
+
+						// viewings
+						r.Route("/viewing-slots", func(r chi.Router) {
+							r.Get("/", handlers.ViewingSlotHandler.ListViewingSlots)
+							r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+								Post("/", handlers.ViewingSlotHandler.CreateViewingSlot)
+							r.Route("/{viewing_slot_id}", func(r chi.Router) {
+								r.Get("/", handlers.ViewingSlotHandler.GetViewingSlot)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/cancel", handlers.ViewingSlotHandler.CancelViewingSlot)
+							})
+						})
+						r.Route("/viewings", func(r chi.Router) {
+							r.Get("/", handlers.ViewingHandler.ListViewings)
+							r.Route("/{viewing_id}", func(r chi.Router) {
+								r.Get("/", handlers.ViewingHandler.GetViewing)
+								r.Patch("/complete", handlers.ViewingHandler.CompleteViewing)
+								r.Patch("/no-show", handlers.ViewingHandler.MarkViewingNoShow)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Patch("/cancel", handlers.ViewingHandler.CancelViewing)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/application", handlers.ViewingHandler.ConvertViewingToApplication)
 							})
 						})
 
@@ -217,6 +311,8 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 								r.Get("/", handlers.ExpenseHandler.GetExpense)
 								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
 									Delete("/", handlers.ExpenseHandler.DeleteExpense)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/settle", handlers.ExpenseHandler.SettleExpense)
 							})
 						})
 
@@ -267,11 +363,24 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 								)
 							r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
 								Patch("/{tenant_application_id}/approve", handlers.TenantApplicationHandler.ApproveTenantApplication)
+
+							r.Route("/{tenant_application_id}/guarantors", func(r chi.Router) {
+								r.Get("/", handlers.GuarantorHandler.ListGuarantors)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/", handlers.GuarantorHandler.CreateGuarantor)
+								r.Route("/{guarantor_id}", func(r chi.Router) {
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Patch("/", handlers.GuarantorHandler.UpdateGuarantor)
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Delete("/", handlers.GuarantorHandler.DeleteGuarantor)
+								})
+							})
 						})
 
 						r.Route("/signing", func(r chi.Router) {
 							r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
 								Post("/", handlers.SigningHandler.SignDocumentPM)
+							r.Post("/documents/{document_id}/verify", handlers.SigningHandler.VerifyDocumentSignatures)
 						})
 
 						r.Route("/signing-tokens", func(r chi.Router) {
@@ -286,6 +395,17 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 							})
 						})
 
+						r.Route("/signing-envelopes", func(r chi.Router) {
+							r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+								Post("/", handlers.SigningHandler.CreateSigningEnvelope)
+							r.Get("/", handlers.SigningHandler.ListSigningEnvelopes)
+							r.Route("/{signing_envelope_id}", func(r chi.Router) {
+								r.Get("/", handlers.SigningHandler.GetSigningEnvelope)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/cancel", handlers.SigningHandler.CancelSigningEnvelope)
+							})
+						})
+
 						r.Route("/leases/{lease_id}", func(r chi.Router) {
 							r.Get("/", handlers.LeaseHandler.GetLeaseByID)
 							r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
@@ -338,6 +458,56 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 								})
 							})
 
+							r.Route("/amendments", func(r chi.Router) {
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/", handlers.LeaseAmendmentHandler.CreateLeaseAmendment)
+								r.Get("/", handlers.LeaseAmendmentHandler.ListLeaseAmendments)
+								r.Route("/{amendment_id}", func(r chi.Router) {
+									r.Get("/", handlers.LeaseAmendmentHandler.GetLeaseAmendment)
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Patch("/", handlers.LeaseAmendmentHandler.UpdateLeaseAmendment)
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Patch("/execute", handlers.LeaseAmendmentHandler.ExecuteLeaseAmendment)
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Patch("/cancel", handlers.LeaseAmendmentHandler.CancelLeaseAmendment)
+								})
+							})
+
+							r.Route("/renewal-offers", func(r chi.Router) {
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/", handlers.RenewalOfferHandler.CreateRenewalOffer)
+								r.Get("/", handlers.RenewalOfferHandler.ListRenewalOffers)
+								r.Route("/{renewal_offer_id}", func(r chi.Router) {
+									r.Get("/", handlers.RenewalOfferHandler.GetRenewalOffer)
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Patch("/withdraw", handlers.RenewalOfferHandler.WithdrawRenewalOffer)
+								})
+							})
+
+							r.Route("/tenants", func(r chi.Router) {
+								r.Get("/", handlers.LeaseTenantHandler.ListLeaseTenants)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/", handlers.LeaseTenantHandler.AddLeaseTenant)
+								r.Route("/{lease_tenant_id}", func(r chi.Router) {
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Patch("/", handlers.LeaseTenantHandler.UpdateLeaseTenantRole)
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Delete("/", handlers.LeaseTenantHandler.RemoveLeaseTenant)
+								})
+							})
+
+							r.Route("/guarantors", func(r chi.Router) {
+								r.Get("/", handlers.GuarantorHandler.ListGuarantors)
+								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+									Post("/", handlers.GuarantorHandler.CreateGuarantor)
+								r.Route("/{guarantor_id}", func(r chi.Router) {
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Patch("/", handlers.GuarantorHandler.UpdateGuarantor)
+									r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
+										Delete("/", handlers.GuarantorHandler.DeleteGuarantor)
+								})
+							})
+
 							r.Route("/agreement-documents", func(r chi.Router) {
 								r.Get("/", handlers.LeaseAgreementDocumentHandler.GetLeaseAgreementDocument)
 								r.With(middlewares.ValidateRoleClientUserPropertyMiddleware(appCtx, "MANAGER")).
@@ -385,6 +555,8 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 							})
 						})
 
+						r.Get("/financial-accounts/issuance:preview", handlers.FinancialAccountHandler.PreviewIssuance)
+						r.Get("/cash-flow-forecast", handlers.CashFlowForecastHandler.GetForecast)
 						r.Route("/financial-accounts/{account_id}", func(r chi.Router) {
 							r.Get("/", handlers.FinancialAccountHandler.GetAccount)
 							r.Get("/charges", handlers.FinancialAccountHandler.ListCharges)
@@ -440,8 +612,13 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 				r.Get("/tenants", handlers.TenantHandler.ListTenantsAcrossProperties)
 				r.Get("/invoices", handlers.InvoiceHandler.ListInvoicesAcrossProperties)
 				r.Get("/maintenance-requests", handlers.MaintenanceRequestHandler.ListAcrossProperties)
+				r.Get("/turnover-tasks/me", handlers.TurnoverTaskHandler.ListMyTurnoverTasks)
+				r.Get("/viewings/me", handlers.ViewingHandler.ListMyViewings)
 				r.Get("/expenses", handlers.ExpenseHandler.ListExpensesAcrossProperties)
 				r.Get("/units", handlers.UnitHandler.ListUnitsAcrossProperties)
+				r.With(middlewares.ValidateRoleClientUserMiddleware(appCtx, "ADMIN", "OWNER")).
+					Get("/financial-accounts/issuance:preview", handlers.FinancialAccountHandler.PreviewIssuance)
+				r.Get("/cash-flow-forecast", handlers.CashFlowForecastHandler.GetForecast)
 				r.Get(
 					"/tenant-applications",
 					handlers.TenantApplicationHandler.ListTenantApplicationsAcrossProperties,
@@ -482,6 +659,14 @@ func NewClientUserRouter(appCtx pkg.AppContext, handlers handlers.Handlers) func
 						r.Get("/", handlers.DocumentHandler.GetDocumentById)
 						r.Patch("/", handlers.DocumentHandler.AdminUpdateDocument)
 						r.Delete("/", handlers.DocumentHandler.DeleteDocument)
+						r.Post("/instantiate", handlers.DocumentHandler.InstantiateDocumentTemplate)
+
+						r.Route("/revisions", func(r chi.Router) {
+							r.Get("/", handlers.DocumentHandler.ListDocumentRevisions)
+							r.Get("/diff", handlers.DocumentHandler.DiffDocumentRevisions)
+							r.Get("/{revision_id}", handlers.DocumentHandler.GetDocumentRevision)
+							r.Post("/{revision_id}/restore", handlers.DocumentHandler.RestoreDocumentRevision)
+						})
 					})
 				})
 
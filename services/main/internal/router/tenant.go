@@ -19,11 +19,24 @@ func NewTenantAccountRouter(appCtx pkg.AppContext, handlers handlers.Handlers) f
 
 			// Public booking routes (no auth required)
 			r.Get("/v1/properties/{property_slug}/units/{unit_slug}", handlers.UnitHandler.FetchClientUnitBySlug)
+			r.Get("/v1/units", handlers.UnitHandler.ListUnitListings)
 			r.Get("/v1/units/{unit_id}", handlers.UnitHandler.FetchClientUnit)
 			r.Get("/v1/units/{unit_slug}/availability", handlers.BookingHandler.PublicGetAvailability)
+			r.Get("/v1/units/{unit_slug}/quote", handlers.BookingHandler.PublicQuoteBooking)
 			r.Post("/v1/units/{unit_slug}/bookings", handlers.BookingHandler.PublicCreateBooking)
+			r.Get("/v1/unit-calendars/{token}.ics", handlers.BookingHandler.PublicGetUnitCalendar)
 			r.Get("/v1/bookings/{tracking_code}", handlers.BookingHandler.PublicGetBookingTracking)
 
+			// Public waitlist routes (no auth required)
+			r.Post("/v1/properties/{property_slug}/waitlist", handlers.WaitlistHandler.JoinWaitlist)
+			r.Get("/v1/waitlist-invites/{token}", handlers.WaitlistHandler.GetWaitlistInvite)
+
+			// Public viewing routes (no auth required)
+			r.Get("/v1/units/{unit_slug}/viewing-slots", handlers.ViewingHandler.ListBookableViewingSlots)
+			r.Post("/v1/units/{unit_slug}/viewings", handlers.ViewingHandler.BookViewing)
+			r.Get("/v1/viewings/{code}", handlers.ViewingHandler.GetViewingByCode)
+			r.Post("/v1/viewings/{code}/cancel", handlers.ViewingHandler.CancelViewingByCode)
+
 			// Public tracking routes (no auth required)
 			r.Get("/v1/tenant-applications/code/{code}", handlers.TenantApplicationHandler.GetTenantApplicationByCode)
 			r.Patch(
@@ -84,6 +97,37 @@ func NewTenantAccountRouter(appCtx pkg.AppContext, handlers handlers.Handlers) f
 				handlers.LeaseChecklistHandler.TenantAcknowledgeChecklist,
 			)
 
+			// tenant renewal offers
+			// Any tenant on the lease can see its offers; only a signatory can
+			// answer one, which the service enforces.
+			r.Get("/v1/leases/{lease_id}/renewal-offers", handlers.RenewalOfferHandler.TenantListRenewalOffers)
+			r.Get(
+				"/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id}",
+				handlers.RenewalOfferHandler.TenantGetRenewalOffer,
+			)
+			r.Post(
+				"/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id}/accept",
+				handlers.RenewalOfferHandler.TenantAcceptRenewalOffer,
+			)
+			r.Post(
+				"/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id}/decline",
+				handlers.RenewalOfferHandler.TenantDeclineRenewalOffer,
+			)
+			r.Post(
+				"/v1/leases/{lease_id}/renewal-offers/{renewal_offer_id}/counter",
+				handlers.RenewalOfferHandler.TenantCounterRenewalOffer,
+			)
+
+			// tenant notice to vacate
+			// Giving notice is limited to signatories by the service, like
+			// answering a renewal offer.
+			r.Post("/v1/leases/{lease_id}/notice-to-vacate", handlers.LeaseTerminationHandler.TenantGiveNoticeToVacate)
+			r.Get("/v1/leases/{lease_id}/terminations", handlers.LeaseTerminationHandler.TenantListLeaseTerminations)
+			r.Get(
+				"/v1/leases/{lease_id}/terminations/{termination_id}",
+				handlers.LeaseTerminationHandler.TenantGetLeaseTermination,
+			)
+
 			// tenant maintenance requests
 			r.Post("/v1/leases/{lease_id}/maintenance-requests", handlers.MaintenanceRequestHandler.TenantCreate)
 			r.Get("/v1/leases/{lease_id}/maintenance-requests", handlers.MaintenanceRequestHandler.TenantList)
@@ -23,6 +23,18 @@ type RentloopQueue interface {
 	EnqueueAnnouncementExpire(ctx context.Context, announcementID string, at time.Time) error
 	CancelAnnouncementPublish(ctx context.Context, announcementID string) error
 	RescheduleAnnouncementExpire(ctx context.Context, announcementID string, at time.Time) error
+	// ScheduleBookingHold replaces any reminder and expiry already scheduled
+	// for the booking's hold. A remindAt that has passed schedules no reminder.
+	ScheduleBookingHold(ctx context.Context, bookingID string, remindAt, expiresAt time.Time) error
+	// EnqueueWaitlistUnitReleased invites the unit's waitlist once the status
+	// change that freed it has committed.
+	EnqueueWaitlistUnitReleased(ctx context.Context, unitID string) error
+	EnqueueWaitlistUnitVacating(ctx context.Context, unitID string, availableFrom time.Time) error
+	ScheduleWaitlistInviteExpiry(ctx context.Context, entryID string, expiresAt time.Time) error
+	EnqueueViewingConfirmation(ctx context.Context, viewingID string) error
+	// ScheduleViewingReminder reminds the prospect at remindAt of the viewing
+	// scheduledAt.
+	ScheduleViewingReminder(ctx context.Context, viewingID string, remindAt, scheduledAt time.Time) error
 }
 
 type AnnouncementService interface {
@@ -48,6 +60,7 @@ type announcementService struct {
 	appCtx              pkg.AppContext
 	repo                repository.AnnouncementRepository
 	tenantAccountRepo   repository.TenantAccountRepository
+	tenantRepo          repository.TenantRepository
 	notificationService NotificationService
 	enqueuer            RentloopQueue
 }
@@ -56,6 +69,7 @@ type AnnouncementServiceDeps struct {
 	AppCtx              pkg.AppContext
 	Repo                repository.AnnouncementRepository
 	TenantAccountRepo   repository.TenantAccountRepository
+	TenantRepo          repository.TenantRepository
 	NotificationService NotificationService
 	RentloopQueue       RentloopQueue
 }
@@ -65,6 +79,7 @@ func NewAnnouncementService(deps AnnouncementServiceDeps) AnnouncementService {
 		appCtx:              deps.AppCtx,
 		repo:                deps.Repo,
 		tenantAccountRepo:   deps.TenantAccountRepo,
+		tenantRepo:          deps.TenantRepo,
 		notificationService: deps.NotificationService,
 		enqueuer:            deps.RentloopQueue,
 	}
@@ -369,11 +384,23 @@ func (s *announcementService) fanOutNotifications(ctx context.Context, a *models
 		return
 	}
 
-	if len(*accounts) == 0 {
+	// Email and SMS reach every tenant on the targeted leases, including
+	// co-tenants and occupants who never set up an account.
+	tenants, err := s.tenantRepo.ListOnCurrentLeases(ctx, repository.ListTenantsOnCurrentLeasesFilter{
+		ClientID:        a.ClientID,
+		PropertyID:      a.PropertyID,
+		PropertyBlockID: a.PropertyBlockID,
+		UnitIDs:         a.TargetUnitIDs,
+	})
+	if err != nil {
+		log.WithError(err).WithField("announcementID", a.ID.String()).
+			Error("[Announcement] failed to resolve target tenants")
 		return
 	}
 
-	tenantsByID := s.bulkFetchTenants(ctx, *accounts)
+	if len(*accounts) == 0 && len(*tenants) == 0 {
+		return
+	}
 
 	title := a.Title
 	body := a.Content
@@ -405,22 +432,17 @@ func (s *announcementService) fanOutNotifications(ctx context.Context, a *models
 		"{{announcement_content}}", a.Content,
 	).Replace(lib.ANNOUNCEMENT_SMS_BODY)
 
+	// All priorities → push notification, for tenants with the app
 	for _, account := range *accounts {
-		accountID := account.ID.String()
-		tenant := tenantsByID[account.TenantId]
-
-		// All priorities → push notification
 		go func(id string) {
 			if sendErr := s.notificationService.SendToTenantAccount(ctx, id, title, body, data); sendErr != nil {
 				log.WithError(sendErr).WithField("tenantAccountID", id).
 					Warn("[Announcement] push notification failed")
 			}
-		}(accountID)
-
-		if tenant == nil {
-			continue
-		}
+		}(account.ID.String())
+	}
 
+	for _, tenant := range *tenants {
 		// URGENT → collect for bulk email
 		if a.Priority == "URGENT" && tenant.Email != nil {
 			emailRecipients = append(emailRecipients, pkg.BulkEmailRecipient{
@@ -479,28 +501,6 @@ func (s *announcementService) resolveTargetAccounts(
 	return s.tenantAccountRepo.GetByClientID(ctx, a.ClientID)
 }
 
-func (s *announcementService) bulkFetchTenants(
-	ctx context.Context,
-	accounts []models.TenantAccount,
-) map[string]*models.Tenant {
-	tenantIDs := make([]string, 0, len(accounts))
-	for _, a := range accounts {
-		tenantIDs = append(tenantIDs, a.TenantId)
-	}
-
-	var tenants []models.Tenant
-	if err := s.appCtx.DB.WithContext(ctx).Where("id IN ?", tenantIDs).Find(&tenants).Error; err != nil {
-		log.WithError(err).Error("[Announcement] failed to bulk fetch tenants")
-		return nil
-	}
-
-	m := make(map[string]*models.Tenant, len(tenants))
-	for i := range tenants {
-		m[tenants[i].ID.String()] = &tenants[i]
-	}
-	return m
-}
-
 func (s *announcementService) CancelSchedule(ctx context.Context, id string) error {
 	announcement, err := s.repo.GetByIDWithPopulate(ctx, repository.GetAnnouncementQuery{ID: id})
 	if err != nil {
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// BookingCancellationPolicy is how much of a stay's price a guest gets back
+// when their booking is cancelled, by how many days before the check-in date
+// it is cancelled.
+type BookingCancellationPolicy struct {
+	Name string
+	// Tiers run from the longest notice to the shortest. Cancelling with less
+	// notice than the last tier asks for refunds nothing.
+	Tiers []BookingCancellationTier
+}
+
+type BookingCancellationTier struct {
+	MinDaysBeforeCheckIn int64
+	RefundPercent        int64
+}
+
+var bookingCancellationPolicies = map[string]BookingCancellationPolicy{
+	models.BookingCancellationPolicyFlexible: {
+		Name:  models.BookingCancellationPolicyFlexible,
+		Tiers: []BookingCancellationTier{{MinDaysBeforeCheckIn: 1, RefundPercent: 100}},
+	},
+	models.BookingCancellationPolicyModerate: {
+		Name: models.BookingCancellationPolicyModerate,
+		Tiers: []BookingCancellationTier{
+			{MinDaysBeforeCheckIn: 5, RefundPercent: 100},
+			{MinDaysBeforeCheckIn: 1, RefundPercent: 50},
+		},
+	},
+	models.BookingCancellationPolicyStrict: {
+		Name: models.BookingCancellationPolicyStrict,
+		Tiers: []BookingCancellationTier{
+			{MinDaysBeforeCheckIn: 14, RefundPercent: 100},
+			{MinDaysBeforeCheckIn: 7, RefundPercent: 50},
+		},
+	},
+}
+
+// GetBookingCancellationPolicy returns the policy called name, or FLEXIBLE,
+// the default, for a name it does not know.
+func GetBookingCancellationPolicy(name string) BookingCancellationPolicy {
+	if policy, ok := bookingCancellationPolicies[name]; ok {
+		return policy
+	}
+	return bookingCancellationPolicies[models.BookingCancellationPolicyFlexible]
+}
+
+// RefundPercent is the share of the stay's price refunded for cancelling
+// daysBeforeCheckIn days before the check-in date.
+func (p BookingCancellationPolicy) RefundPercent(daysBeforeCheckIn int64) int64 {
+	for _, tier := range p.Tiers {
+		if daysBeforeCheckIn >= tier.MinDaysBeforeCheckIn {
+			return tier.RefundPercent
+		}
+	}
+	return 0
+}
+
+// Description is the policy in words, as guests are shown it before they book.
+func (p BookingCancellationPolicy) Description() string {
+	sentences := make([]string, 0, len(p.Tiers)+1)
+	for _, tier := range p.Tiers {
+		refund := fmt.Sprintf("%d%% refund", tier.RefundPercent)
+		if tier.RefundPercent == 100 {
+			refund = "Full refund"
+		}
+		days := "days"
+		if tier.MinDaysBeforeCheckIn == 1 {
+			days = "day"
+		}
+		sentences = append(sentences, fmt.Sprintf(
+			"%s if cancelled at least %d %s before check-in.", refund, tier.MinDaysBeforeCheckIn, days,
+		))
+	}
+	sentences = append(sentences, "No refund after that.")
+
+	return strings.Join(sentences, " ")
+}
+
+type bookingRefundQuote struct {
+	DaysBeforeCheckIn int64
+	RefundPercent     int64
+	AmountPaid        int64
+	Amount            int64
+}
+
+// quoteBookingRefund works out what a guest who paid paid towards a stay
+// priced at total gets back for cancelling at now. The policy's percentage
+// is of the price: the property keeps the rest, and whatever was paid beyond
+// that goes back. A guest who paid less than the property keeps gets nothing
+// back and is not asked for the difference.
+func quoteBookingRefund(
+	policy BookingCancellationPolicy,
+	total, paid int64,
+	checkIn, now time.Time,
+) bookingRefundQuote {
+	days := int64(calendarDate(checkIn).Sub(calendarDate(now)).Hours() / 24)
+	percent := policy.RefundPercent(days)
+	kept := total - percentOf(total, percent)
+
+	return bookingRefundQuote{
+		DaysBeforeCheckIn: days,
+		RefundPercent:     percent,
+		AmountPaid:        paid,
+		Amount:            max(paid-kept, 0),
+	}
+}
+
+// bookingTotalAndPaid is what booking's stay is billed at across its own and
+// supplementary invoices, and what the guest has paid towards them, both less
+// credits already refunded for changes to the stay. Credits taken off a
+// balance come off the total only, as amounts written off. The booking needs
+// its Invoice, SupplementaryInvoices and their Payments loaded.
+func bookingTotalAndPaid(booking *models.Booking, credits int64) (int64, int64) {
+	total := booking.Invoice.TotalAmount - booking.Invoice.WrittenOffAmount - credits
+	paid := invoiceAmountPaid(booking.Invoice) - credits
+	for i := range booking.SupplementaryInvoices {
+		total += booking.SupplementaryInvoices[i].TotalAmount - booking.SupplementaryInvoices[i].WrittenOffAmount
+		paid += invoiceAmountPaid(&booking.SupplementaryInvoices[i])
+	}
+
+	return total, paid
+}
+
+// invoiceAmountPaid is what has been received against invoice, which needs
+// its Payments loaded.
+func invoiceAmountPaid(invoice *models.Invoice) int64 {
+	var paid int64
+	for _, payment := range invoice.Payments {
+		if payment.Status == "SUCCESSFUL" {
+			paid += payment.Amount
+		}
+	}
+	return paid
+}
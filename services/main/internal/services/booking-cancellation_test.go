@@ -0,0 +1,108 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// Notice is counted in calendar days to the check-in date, whatever the time
+// of day. The property keeps the share of the price the policy does not
+// refund, so only what was paid beyond that share goes back.
+func TestQuoteBookingRefund(t *testing.T) {
+	checkIn := time.Date(2026, 11, 20, 14, 0, 0, 0, time.UTC)
+	moderate := GetBookingCancellationPolicy(models.BookingCancellationPolicyModerate)
+	strict := GetBookingCancellationPolicy(models.BookingCancellationPolicyStrict)
+
+	cases := []struct {
+		name        string
+		policy      BookingCancellationPolicy
+		paid        int64
+		now         time.Time
+		wantDays    int64
+		wantPercent int64
+		wantAmount  int64
+	}{
+		{"well ahead, paid in full", moderate, 10000, time.Date(2026, 11, 1, 9, 0, 0, 0, time.UTC), 19, 100, 10000},
+		{"exactly on the tier", moderate, 10000, time.Date(2026, 11, 15, 23, 0, 0, 0, time.UTC), 5, 100, 10000},
+		{"inside the half tier", moderate, 10000, time.Date(2026, 11, 16, 0, 30, 0, 0, time.UTC), 4, 50, 5000},
+		{"half tier, paid a deposit", moderate, 3000, time.Date(2026, 11, 17, 9, 0, 0, 0, time.UTC), 3, 50, 0},
+		{"half tier, paid more than kept", moderate, 7000, time.Date(2026, 11, 17, 9, 0, 0, 0, time.UTC), 3, 50, 2000},
+		{"check-in day", moderate, 10000, time.Date(2026, 11, 20, 8, 0, 0, 0, time.UTC), 0, 0, 0},
+		{"after check-in day", moderate, 10000, time.Date(2026, 11, 22, 8, 0, 0, 0, time.UTC), -2, 0, 0},
+		{"strict, a week out", strict, 10000, time.Date(2026, 11, 13, 8, 0, 0, 0, time.UTC), 7, 50, 5000},
+		{"nothing paid", strict, 0, time.Date(2026, 10, 1, 8, 0, 0, 0, time.UTC), 50, 100, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := quoteBookingRefund(tc.policy, 10000, tc.paid, checkIn, tc.now)
+			if got.DaysBeforeCheckIn != tc.wantDays || got.RefundPercent != tc.wantPercent ||
+				got.Amount != tc.wantAmount || got.AmountPaid != tc.paid {
+				t.Errorf("got %+v, want %d days, %d%%, refund %d", got, tc.wantDays, tc.wantPercent, tc.wantAmount)
+			}
+		})
+	}
+}
+
+// Guests read the policy before they book, so its wording follows its tiers.
+func TestBookingCancellationPolicyDescription(t *testing.T) {
+	cases := map[string]string{
+		models.BookingCancellationPolicyFlexible: "Full refund if cancelled at least 1 day before check-in. " +
+			"No refund after that.",
+		models.BookingCancellationPolicyModerate: "Full refund if cancelled at least 5 days before check-in. " +
+			"50% refund if cancelled at least 1 day before check-in. No refund after that.",
+		"UNKNOWN": "Full refund if cancelled at least 1 day before check-in. No refund after that.",
+	}
+
+	for name, want := range cases {
+		if got := GetBookingCancellationPolicy(name).Description(); got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+}
+
+// A cancelled booking's refund is worked out over everything it was billed,
+// supplementary invoices included, less credits already refunded for changes
+// to the stay, which would otherwise be paid back twice. A credit taken off
+// an unpaid balance was never paid, so it comes off the total alone.
+func TestBookingTotalAndPaid(t *testing.T) {
+	paidInvoice := func(total int64, payments ...int64) models.Invoice {
+		invoice := models.Invoice{TotalAmount: total}
+		for _, amount := range payments {
+			invoice.Payments = append(invoice.Payments, models.Payment{Amount: amount, Status: "SUCCESSFUL"})
+		}
+		invoice.Payments = append(invoice.Payments, models.Payment{Amount: 999, Status: "FAILED"})
+		return invoice
+	}
+	writtenOff := func(invoice models.Invoice, amount int64) models.Invoice {
+		invoice.WrittenOffAmount = amount
+		return invoice
+	}
+
+	cases := []struct {
+		name          string
+		invoice       models.Invoice
+		supplementary []models.Invoice
+		credits       int64
+		wantTotal     int64
+		wantPaid      int64
+	}{
+		{"booking invoice only", paidInvoice(10000, 4000), nil, 0, 10000, 4000},
+		{"extended and paid", paidInvoice(10000, 10000), []models.Invoice{paidInvoice(2000, 2000)}, 0, 12000, 12000},
+		{"extension unpaid", paidInvoice(10000, 10000), []models.Invoice{paidInvoice(2000)}, 0, 12000, 10000},
+		{"shortened after paying", paidInvoice(10000, 10000), nil, 3000, 7000, 7000},
+		{"shortened before paying in full", writtenOff(paidInvoice(10000, 4000), 3000), nil, 0, 7000, 4000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			booking := &models.Booking{Invoice: &tc.invoice, SupplementaryInvoices: tc.supplementary}
+			total, paid := bookingTotalAndPaid(booking, tc.credits)
+			if total != tc.wantTotal || paid != tc.wantPaid {
+				t.Errorf("got total %d, paid %d, want %d, %d", total, paid, tc.wantTotal, tc.wantPaid)
+			}
+		})
+	}
+}
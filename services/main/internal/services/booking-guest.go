@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"gorm.io/gorm"
+)
+
+type BookingGuestInput struct {
+	FirstName   string
+	LastName    string
+	IsChild     bool
+	Nationality *string
+	IDType      *string
+	IDNumber    *string
+}
+
+// validateBookingGuests checks a booking for adults and children can be
+// registered with guests: at least one adult, and no more adults or children
+// named than are staying.
+func validateBookingGuests(adults, children int64, guests []BookingGuestInput) error {
+	if adults < 1 {
+		return pkg.BadRequestError("BookingRequiresAnAdult", nil)
+	}
+	if children < 0 {
+		return pkg.BadRequestError("BookingChildrenCannotBeNegative", nil)
+	}
+
+	var namedAdults, namedChildren int64
+	for _, guest := range guests {
+		if guest.IsChild {
+			namedChildren++
+		} else {
+			namedAdults++
+		}
+	}
+	if namedAdults > adults || namedChildren > children {
+		return pkg.BadRequestError("BookingGuestsExceedGuestCount", nil)
+	}
+
+	return nil
+}
+
+func bookingGuestModels(bookingID string, guests []BookingGuestInput) []models.BookingGuest {
+	registered := make([]models.BookingGuest, 0, len(guests))
+	for _, guest := range guests {
+		registered = append(registered, models.BookingGuest{
+			BookingID:   bookingID,
+			FirstName:   guest.FirstName,
+			LastName:    guest.LastName,
+			IsChild:     guest.IsChild,
+			Nationality: guest.Nationality,
+			IDType:      guest.IDType,
+			IDNumber:    guest.IDNumber,
+		})
+	}
+	return registered
+}
+
+func bookingGuestInputs(guests []models.BookingGuest) []BookingGuestInput {
+	inputs := make([]BookingGuestInput, 0, len(guests))
+	for _, guest := range guests {
+		inputs = append(inputs, BookingGuestInput{
+			FirstName:   guest.FirstName,
+			LastName:    guest.LastName,
+			IsChild:     guest.IsChild,
+			Nationality: guest.Nationality,
+			IDType:      guest.IDType,
+			IDNumber:    guest.IDNumber,
+		})
+	}
+	return inputs
+}
+
+type SetBookingGuestsInput struct {
+	BookingID string
+	Guests    []BookingGuestInput
+}
+
+// SetBookingGuests replaces the guests registered on a booking, up to as
+// many adults and children as it is for.
+func (s *bookingService) SetBookingGuests(
+	ctx context.Context,
+	input SetBookingGuestsInput,
+) ([]models.BookingGuest, error) {
+	booking, err := s.repo.GetByIDWithPopulate(ctx, repository.GetBookingQuery{ID: input.BookingID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("BookingNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "SetBookingGuests", "action": "fetching booking"},
+		})
+	}
+
+	if booking.Status == "COMPLETED" || booking.Status == "CANCELLED" {
+		return nil, pkg.BadRequestError(
+			"cannot register guests on a completed or cancelled booking",
+			&pkg.RentLoopErrorParams{
+				Err:      errors.New("booking is in a terminal status"),
+				Metadata: map[string]string{"function": "SetBookingGuests"},
+			},
+		)
+	}
+
+	if err := validateBookingGuests(booking.Adults, booking.Children, input.Guests); err != nil {
+		return nil, err
+	}
+
+	guests := bookingGuestModels(booking.ID.String(), input.Guests)
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.bookingGuestRepo.ReplaceForBooking(transCtx, booking.ID.String(), &guests); err != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "SetBookingGuests", "action": "replacing booking guests"},
+		})
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      commitErr,
+			Metadata: map[string]string{"function": "SetBookingGuests", "action": "committing transaction"},
+		})
+	}
+
+	return guests, nil
+}
+
+// assertBookingGuestsRegistered stops a booking at a property that requires
+// guest registration from checking in before everyone staying is named.
+func (s *bookingService) assertBookingGuestsRegistered(ctx context.Context, booking *models.Booking) error {
+	if !booking.Property.BookingRequiresGuestList {
+		return nil
+	}
+
+	registered, err := s.bookingGuestRepo.CountByBookingID(ctx, booking.ID.String())
+	if err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "assertBookingGuestsRegistered",
+				"action":   "counting booking guests",
+			},
+		})
+	}
+
+	if registered < booking.Adults+booking.Children {
+		return pkg.BadRequestError("BookingGuestsNotRegistered", &pkg.RentLoopErrorParams{
+			Err: fmt.Errorf(
+				"%d of %d guests registered",
+				registered,
+				booking.Adults+booking.Children,
+			),
+			Metadata: map[string]string{"function": "assertBookingGuestsRegistered"},
+		})
+	}
+
+	return nil
+}
+
+// bookingExtraGuestLine bills the guests above the unit's base occupancy
+// in quote, per guest per period of the stay.
+func bookingExtraGuestLine(quote *BookingQuote) LineItemInput {
+	return LineItemInput{
+		Label:       fmt.Sprintf("Extra guests (%d) for %d %s", quote.ExtraGuests, quote.Quantity, quote.PeriodLabel),
+		Category:    "EXTRA_GUEST_FEE",
+		Quantity:    quote.ExtraGuests * quote.Quantity,
+		UnitAmount:  quote.ExtraGuestFee,
+		TotalAmount: quote.ExtraGuestTotal,
+		Currency:    quote.Currency,
+	}
+}
+
+// bookingExtraGuestLineItem is the EXTRA_GUEST_FEE line on a booking's
+// invoice, or nil when its guests are all within the base occupancy.
+func (s *bookingService) bookingExtraGuestLineItem(
+	ctx context.Context,
+	invoiceID string,
+) (*models.InvoiceLineItem, error) {
+	lineItems, err := s.invoiceService.GetLineItems(ctx, invoiceID)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "bookingExtraGuestLineItem", "action": "fetching line items"},
+		})
+	}
+
+	for i := range lineItems {
+		if lineItems[i].Category == "EXTRA_GUEST_FEE" {
+			return &lineItems[i], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// syncBookingExtraGuestLine adds, updates or removes the EXTRA_GUEST_FEE
+// line on a booking's draft invoice so it bills what quote charges for
+// extra guests. Must be called within a transaction context.
+func (s *bookingService) syncBookingExtraGuestLine(
+	ctx context.Context,
+	invoiceID string,
+	quote *BookingQuote,
+) error {
+	existing, err := s.bookingExtraGuestLineItem(ctx, invoiceID)
+	if err != nil {
+		return err
+	}
+
+	line := bookingExtraGuestLine(quote)
+	switch {
+	case existing == nil && quote.ExtraGuestTotal == 0:
+		return nil
+	case existing == nil:
+		_, err = s.invoiceService.AddLineItem(ctx, AddLineItemInput{
+			InvoiceID:   invoiceID,
+			Label:       line.Label,
+			Category:    line.Category,
+			Quantity:    line.Quantity,
+			UnitAmount:  line.UnitAmount,
+			TotalAmount: line.TotalAmount,
+			Currency:    line.Currency,
+		})
+	case quote.ExtraGuestTotal == 0:
+		err = s.invoiceService.RemoveLineItem(ctx, RemoveLineItemInput{
+			InvoiceID:  invoiceID,
+			LineItemID: existing.ID.String(),
+		})
+	default:
+		_, err = s.invoiceService.UpdateLineItem(ctx, UpdateLineItemInput{
+			InvoiceID:   invoiceID,
+			LineItemID:  existing.ID.String(),
+			Label:       &line.Label,
+			Quantity:    &line.Quantity,
+			UnitAmount:  &line.UnitAmount,
+			TotalAmount: &line.TotalAmount,
+			Currency:    &existing.Currency,
+		})
+	}
+
+	return err
+}
+
+// bookingInvoiceStayTotal is what a booking's invoice charges for the stay:
+// its fee line and any extra guests.
+func (s *bookingService) bookingInvoiceStayTotal(
+	ctx context.Context,
+	invoiceID string,
+	feeLine *models.InvoiceLineItem,
+) (int64, error) {
+	extraGuestLine, err := s.bookingExtraGuestLineItem(ctx, invoiceID)
+	if err != nil {
+		return 0, err
+	}
+	if extraGuestLine == nil {
+		return feeLine.TotalAmount, nil
+	}
+	return feeLine.TotalAmount + extraGuestLine.TotalAmount, nil
+}
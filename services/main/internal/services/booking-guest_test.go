@@ -0,0 +1,44 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+// Someone must be responsible for the stay, and a guest list may name fewer
+// people than are staying but never more, counting adults and children apart.
+func TestValidateBookingGuests(t *testing.T) {
+	adult := BookingGuestInput{FirstName: "Ama", LastName: "Mensah"}
+	child := BookingGuestInput{FirstName: "Kofi", LastName: "Mensah", IsChild: true}
+
+	cases := []struct {
+		name     string
+		adults   int64
+		children int64
+		guests   []BookingGuestInput
+		wantErr  string
+	}{
+		{"no guests named", 2, 1, nil, ""},
+		{"everyone named", 1, 1, []BookingGuestInput{adult, child}, ""},
+		{"some named", 2, 2, []BookingGuestInput{adult}, ""},
+		{"no adult", 0, 2, nil, "BookingRequiresAnAdult"},
+		{"too many adults named", 1, 2, []BookingGuestInput{adult, adult}, "BookingGuestsExceedGuestCount"},
+		{"child named without children", 2, 0, []BookingGuestInput{child}, "BookingGuestsExceedGuestCount"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateBookingGuests(tc.adults, tc.children, tc.guests)
+
+			var rentLoopErr *pkg.IRentLoopError
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("got %v, want no error", err)
+			case tc.wantErr != "" && (!errors.As(err, &rentLoopErr) || rentLoopErr.Message != tc.wantErr):
+				t.Errorf("got %v, want %s", err, tc.wantErr)
+			}
+		})
+	}
+}
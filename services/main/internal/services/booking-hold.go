@@ -0,0 +1,288 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// bookingHoldReminderLead is how long before a hold expires the guest is
+// reminded to pay.
+const bookingHoldReminderLead = time.Hour
+
+const bookingHoldExpiredReason = "Payment was not received before the hold expired"
+
+// bookingHoldExpiry is when a hold of holdHours placed at now runs out, or
+// nil for a property that holds bookings until a manager acts. It is kept to
+// the second so the time read back from the database matches the one the
+// queued tasks carry.
+func bookingHoldExpiry(now time.Time, holdHours int64) *time.Time {
+	if holdHours <= 0 {
+		return nil
+	}
+	expiresAt := now.Add(time.Duration(holdHours) * time.Hour).Truncate(time.Second)
+	return &expiresAt
+}
+
+// bookingHoldReminderAt is when a guest whose hold runs out at expiresAt is
+// reminded to pay. A hold shorter than twice the lead is reminded halfway.
+func bookingHoldReminderAt(now, expiresAt time.Time) time.Time {
+	lead := min(bookingHoldReminderLead, expiresAt.Sub(now)/2)
+	return expiresAt.Add(-lead)
+}
+
+// scheduleBookingHold queues the reminder and expiry for booking's hold.
+// The booking is saved by then, so a failure is logged rather than returned;
+// the hold can be rescheduled by extending it.
+func (s *bookingService) scheduleBookingHold(ctx context.Context, booking *models.Booking) {
+	if booking.HoldExpiresAt == nil {
+		return
+	}
+
+	expiresAt := *booking.HoldExpiresAt
+	remindAt := bookingHoldReminderAt(time.Now(), expiresAt)
+	if err := s.queue.ScheduleBookingHold(ctx, booking.ID.String(), remindAt, expiresAt); err != nil {
+		log.WithError(err).
+			WithField("booking_id", booking.ID.String()).
+			Error("failed to schedule booking hold")
+	}
+}
+
+// isCurrentBookingHold reports whether a task scheduled for a hold expiring
+// at expiresAt is still for booking's hold, which it is not once the booking
+// has left PENDING or its hold was extended or lifted.
+func isCurrentBookingHold(booking *models.Booking, expiresAt time.Time) bool {
+	return booking.Status == "PENDING" &&
+		booking.HoldExpiresAt != nil &&
+		booking.HoldExpiresAt.Equal(expiresAt)
+}
+
+type ExtendBookingHoldInput struct {
+	BookingID string
+	ExpiresAt time.Time
+}
+
+// ExtendBookingHold gives the guest of a held booking until input.ExpiresAt
+// to pay.
+func (s *bookingService) ExtendBookingHold(ctx context.Context, input ExtendBookingHoldInput) (*models.Booking, error) {
+	booking, err := s.repo.GetByIDWithPopulate(ctx, repository.GetBookingQuery{ID: input.BookingID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("BookingNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ExtendBookingHold",
+				"action":   "fetching booking",
+			},
+		})
+	}
+
+	if booking.Status != "PENDING" || booking.HoldExpiresAt == nil {
+		return nil, pkg.BadRequestError("BookingNotOnHold", &pkg.RentLoopErrorParams{
+			Err: errors.New("booking is not a PENDING booking on hold"),
+			Metadata: map[string]string{
+				"function": "ExtendBookingHold",
+				"action":   "validating booking hold",
+			},
+		})
+	}
+
+	expiresAt := input.ExpiresAt.Truncate(time.Second)
+	if !expiresAt.After(time.Now()) || !expiresAt.After(*booking.HoldExpiresAt) {
+		return nil, pkg.BadRequestError("hold_expires_at must be later than the current hold", &pkg.RentLoopErrorParams{
+			Err: errors.New("hold extension is not later than the current hold"),
+			Metadata: map[string]string{
+				"function": "ExtendBookingHold",
+				"action":   "validating hold expiry",
+			},
+		})
+	}
+
+	booking.HoldExpiresAt = &expiresAt
+	if err := s.repo.Update(ctx, booking); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ExtendBookingHold",
+				"action":   "updating booking",
+			},
+		})
+	}
+
+	s.scheduleBookingHold(ctx, booking)
+
+	return booking, nil
+}
+
+func (s *bookingService) getHeldBooking(
+	ctx context.Context,
+	bookingID string,
+	function string,
+) (*models.Booking, error) {
+	booking, err := s.repo.GetByIDWithPopulate(ctx, repository.GetBookingQuery{
+		ID:       bookingID,
+		Populate: &[]string{"Tenant", "Unit", "Invoice", "Invoice.Payments", "SupplementaryInvoices.Payments"},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("BookingNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": function,
+				"action":   "fetching booking",
+			},
+		})
+	}
+
+	return booking, nil
+}
+
+// RemindBookingHold reminds the guest of a booking held until expiresAt to
+// pay before then. Nothing is sent once anything has been paid.
+func (s *bookingService) RemindBookingHold(ctx context.Context, bookingID string, expiresAt time.Time) error {
+	booking, err := s.getHeldBooking(ctx, bookingID, "RemindBookingHold")
+	if err != nil {
+		return err
+	}
+
+	if !isCurrentBookingHold(booking, expiresAt) || booking.Invoice == nil {
+		return nil
+	}
+	if paid := invoiceAmountPaid(booking.Invoice); paid > 0 {
+		return nil
+	}
+
+	s.sendBookingHoldReminderNotification(*booking)
+
+	return nil
+}
+
+// ExpireBookingHold cancels a booking held until expiresAt that nothing was
+// paid against by then. A booking paid towards stays PENDING for a manager
+// to confirm, without its hold. The payments are checked and the booking
+// cancelled in one transaction holding a lock on its row.
+func (s *bookingService) ExpireBookingHold(ctx context.Context, bookingID string, expiresAt time.Time) error {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	booking, err := s.repo.GetByIDForUpdate(transCtx, repository.GetBookingQuery{
+		ID:       bookingID,
+		Populate: &[]string{"Tenant", "Unit", "Invoice", "Invoice.Payments", "SupplementaryInvoices.Payments"},
+	})
+	if err != nil {
+		transaction.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkg.NotFoundError("BookingNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ExpireBookingHold",
+				"action":   "locking booking",
+			},
+		})
+	}
+
+	if !isCurrentBookingHold(booking, expiresAt) {
+		transaction.Rollback()
+		return nil
+	}
+
+	paid := booking.Invoice != nil && invoiceAmountPaid(booking.Invoice) > 0
+	if paid {
+		booking.HoldExpiresAt = nil
+		if err := s.repo.Update(transCtx, booking); err != nil {
+			transaction.Rollback()
+			return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err: err,
+				Metadata: map[string]string{
+					"function": "ExpireBookingHold",
+					"action":   "lifting hold on paid booking",
+				},
+			})
+		}
+	} else if err := s.recordBookingCancellation(transCtx, booking, bookingHoldExpiredReason, nil); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit().Error; err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ExpireBookingHold",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	if !paid {
+		s.settleCancelledBooking(ctx, booking, bookingHoldExpiredReason, nil)
+	}
+
+	return nil
+}
+
+func (s *bookingService) sendBookingHoldReminderNotification(booking models.Booking) {
+	expiresAt := booking.HoldExpiresAt.Format("January 2, 2006 3:04pm")
+	amount := lib.FormatAmount(lib.PesewasToCedis(booking.Invoice.TotalAmount))
+
+	emailData := emailtemplates.BookingHoldReminderData{
+		GuestName:    booking.Tenant.FirstName,
+		UnitName:     booking.Unit.Name,
+		ExpiresAt:    expiresAt,
+		Amount:       amount,
+		Currency:     booking.Invoice.Currency,
+		TrackingCode: booking.Code,
+	}
+
+	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render("booking/hold-reminder", emailData)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render booking hold reminder email template")
+	}
+
+	if booking.Tenant.Email != nil {
+		go pkg.SendEmail(
+			s.appCtx.Config,
+			pkg.SendEmailInput{
+				Recipient: *booking.Tenant.Email,
+				Subject:   lib.BOOKING_HOLD_REMINDER_SUBJECT,
+				HtmlBody:  htmlBody,
+				TextBody:  textBody,
+			},
+		)
+	}
+
+	smsBody := strings.NewReplacer(
+		"{{tenant_name}}", booking.Tenant.FirstName,
+		"{{unit_name}}", booking.Unit.Name,
+		"{{hold_expires_at}}", expiresAt,
+		"{{amount}}", booking.Invoice.Currency+" "+amount,
+		"{{booking_code}}", booking.Code,
+	).Replace(lib.BOOKING_HOLD_REMINDER_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: booking.Tenant.Phone,
+			Message:   smsBody,
+		},
+	)
+}
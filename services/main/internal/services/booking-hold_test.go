@@ -0,0 +1,45 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// Guests are reminded an hour before their hold runs out, or halfway through
+// a hold too short for that, so the reminder never comes before the booking.
+func TestBookingHoldReminderAt(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		expiresAt time.Time
+		want      time.Time
+	}{
+		{"a day's hold", now.Add(24 * time.Hour), now.Add(23 * time.Hour)},
+		{"exactly two hours", now.Add(2 * time.Hour), now.Add(time.Hour)},
+		{"shorter than two hours", now.Add(90 * time.Minute), now.Add(45 * time.Minute)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := bookingHoldReminderAt(now, tc.expiresAt); !got.Equal(tc.want) {
+				t.Errorf("got %s, want %s", got, tc.want)
+			}
+		})
+	}
+}
+
+// A hold window of 0 leaves the booking for a manager; any other window is
+// kept to the second so queued tasks can match it against the saved booking.
+func TestBookingHoldExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 123456789, time.UTC)
+
+	if got := bookingHoldExpiry(now, 0); got != nil {
+		t.Errorf("0 hours: got %s, want nil", got)
+	}
+
+	want := time.Date(2026, 10, 20, 9, 0, 0, 0, time.UTC)
+	if got := bookingHoldExpiry(now, 24); got == nil || !got.Equal(want) {
+		t.Errorf("24 hours: got %v, want %s", got, want)
+	}
+}
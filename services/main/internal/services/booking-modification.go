@@ -0,0 +1,639 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type ModifyBookingInput struct {
+	BookingID    string
+	ClientUserID string
+	Reason       string
+
+	// nil keeps the booking's current unit or date
+	UnitID       *string
+	CheckInDate  *time.Time
+	CheckOutDate *time.Time
+}
+
+// validateBookingModification checks that booking can move to unitID from
+// checkIn to checkOut. A guest who has checked in can only have their
+// check-out moved, and not to a time already past.
+func validateBookingModification(
+	booking *models.Booking,
+	unitID string,
+	checkIn, checkOut, now time.Time,
+) error {
+	if booking.Status != "PENDING" && booking.Status != "CONFIRMED" && booking.Status != "CHECKED_IN" {
+		return pkg.BadRequestError("only PENDING, CONFIRMED or CHECKED_IN bookings can be modified", nil)
+	}
+
+	if unitID == booking.UnitID && checkIn.Equal(booking.CheckInDate) && checkOut.Equal(booking.CheckOutDate) {
+		return pkg.BadRequestError("BookingModificationChangesNothing", nil)
+	}
+
+	if !checkOut.After(checkIn) {
+		return pkg.BadRequestError("check_out_date must be after check_in_date", nil)
+	}
+
+	if booking.Status == "CHECKED_IN" {
+		if unitID != booking.UnitID || !checkIn.Equal(booking.CheckInDate) {
+			return pkg.BadRequestError("only the check_out_date of a checked-in booking can change", nil)
+		}
+		if !checkOut.After(now) {
+			return pkg.BadRequestError("check_out_date of a checked-in booking must be in the future", nil)
+		}
+	}
+
+	return nil
+}
+
+type bookingModificationSettlement struct {
+	Total        int64
+	Charge       int64 // billed on a supplementary invoice
+	CreditAmount int64 // owed to the guest
+}
+
+// settleBookingModification settles a change to a stay that has been
+// invoiced. Only the difference between the old and new stay, both priced
+// now, changes hands, so nights the change leaves alone keep the price the
+// guest agreed to even if the unit's rates have moved since.
+func settleBookingModification(previousTotal, oldStayPrice, newStayPrice int64) bookingModificationSettlement {
+	difference := newStayPrice - oldStayPrice
+
+	return bookingModificationSettlement{
+		Total:        previousTotal + difference,
+		Charge:       max(difference, 0),
+		CreditAmount: max(-difference, 0),
+	}
+}
+
+// bookingCreditSplit is how the credit for a cheaper stay comes back to the
+// guest.
+type bookingCreditSplit struct {
+	WriteOffs []int64 // off each open balance, in the order they were given
+	Refund    int64
+}
+
+// splitBookingCredit takes credit off what the guest still owes first, in
+// the order of balances, and refunds only what is left — never more than
+// paid. So a guest is not refunded money they never paid, nor still billed
+// for nights they no longer have.
+func splitBookingCredit(credit, paid int64, balances []int64) bookingCreditSplit {
+	split := bookingCreditSplit{WriteOffs: make([]int64, len(balances))}
+	for i, balance := range balances {
+		split.WriteOffs[i] = min(credit, max(balance, 0))
+		credit -= split.WriteOffs[i]
+	}
+	split.Refund = min(credit, max(paid, 0))
+
+	return split
+}
+
+// bookingOpenInvoices are booking's invoices still waiting on money, the
+// booking's own first, with what is owed on each. The booking needs its
+// Invoice, SupplementaryInvoices and their Payments loaded.
+func bookingOpenInvoices(booking *models.Booking) ([]*models.Invoice, []int64) {
+	invoices := []*models.Invoice{booking.Invoice}
+	for i := range booking.SupplementaryInvoices {
+		invoices = append(invoices, &booking.SupplementaryInvoices[i])
+	}
+
+	open := make([]*models.Invoice, 0, len(invoices))
+	balances := make([]int64, 0, len(invoices))
+	for _, invoice := range invoices {
+		if invoice.Status != "ISSUED" && invoice.Status != "PARTIALLY_PAID" {
+			continue
+		}
+		open = append(open, invoice)
+		balances = append(balances, invoice.TotalAmount-invoice.WrittenOffAmount-invoiceAmountPaid(invoice))
+	}
+
+	return open, balances
+}
+
+// ModifyBooking moves a booking to new dates, another unit in its property,
+// or both, and records the change in the booking's history.
+func (s *bookingService) ModifyBooking(
+	ctx context.Context,
+	input ModifyBookingInput,
+) (*models.BookingModification, error) {
+	booking, err := s.repo.GetByIDWithPopulate(ctx, repository.GetBookingQuery{
+		ID:       input.BookingID,
+		Populate: &[]string{"Tenant", "Unit", "Invoice", "Invoice.Payments", "SupplementaryInvoices.Payments"},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("BookingNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ModifyBooking",
+				"action":   "fetching booking",
+			},
+		})
+	}
+
+	unitID, checkIn, checkOut := booking.UnitID, booking.CheckInDate, booking.CheckOutDate
+	if input.UnitID != nil {
+		unitID = *input.UnitID
+	}
+	if input.CheckInDate != nil {
+		checkIn = *input.CheckInDate
+	}
+	if input.CheckOutDate != nil {
+		checkOut = *input.CheckOutDate
+	}
+
+	if err := validateBookingModification(booking, unitID, checkIn, checkOut, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if booking.Invoice == nil {
+		return nil, pkg.BadRequestError("BookingHasNoInvoice", &pkg.RentLoopErrorParams{
+			Err: errors.New("booking has no invoice to reprice"),
+			Metadata: map[string]string{
+				"function": "ModifyBooking",
+				"action":   "validating booking invoice",
+			},
+		})
+	}
+
+	newUnit := &booking.Unit
+	if unitID != booking.UnitID {
+		newUnit, err = s.unitService.GetUnit(ctx, repository.GetUnitQuery{
+			PropertyID: booking.PropertyID,
+			UnitID:     unitID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	hasOverlap, err := s.repo.HasOverlappingOtherBlock(ctx, unitID, checkIn, checkOut, booking.ID.String())
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ModifyBooking",
+				"action":   "checking for overlapping blocks",
+			},
+		})
+	}
+	if hasOverlap {
+		return nil, pkg.ConflictError("dates are not available: overlapping block exists", &pkg.RentLoopErrorParams{
+			Err: errors.New("overlapping block exists"),
+			Metadata: map[string]string{
+				"function": "ModifyBooking",
+				"action":   "checking for overlapping blocks",
+			},
+		})
+	}
+
+	feeLine, err := s.bookingFeeLineItem(ctx, booking.Invoice.ID.String())
+	if err != nil {
+		return nil, err
+	}
+
+	modification := &models.BookingModification{
+		BookingID:             booking.ID.String(),
+		Reason:                input.Reason,
+		PreviousUnitID:        booking.UnitID,
+		PreviousCheckInDate:   booking.CheckInDate,
+		PreviousCheckOutDate:  booking.CheckOutDate,
+		UnitID:                unitID,
+		CheckInDate:           checkIn,
+		CheckOutDate:          checkOut,
+		Currency:              booking.Invoice.Currency,
+		CreatedByClientUserID: input.ClientUserID,
+	}
+
+	// An invoice nothing has been paid against is repriced in place, as a
+	// date change before the booking is confirmed always has been; an issued
+	// one is reopened for it and issued again. Once the guest has paid, what
+	// they paid against stands and the change is settled beside it.
+	repriceInvoice := (booking.Invoice.Status == "DRAFT" || booking.Invoice.Status == "ISSUED") &&
+		invoiceAmountPaid(booking.Invoice) == 0
+	reissueInvoice := repriceInvoice && booking.Invoice.Status == "ISSUED"
+
+	var settlement bookingModificationSettlement
+	if repriceInvoice {
+		previousTotal, totalErr := s.bookingInvoiceStayTotal(ctx, booking.Invoice.ID.String(), feeLine)
+		if totalErr != nil {
+			return nil, totalErr
+		}
+		modification.PreviousTotal = previousTotal
+	} else {
+		previousTotal, totalErr := s.bookingStayTotal(ctx, booking, feeLine)
+		if totalErr != nil {
+			return nil, totalErr
+		}
+
+		oldQuote, quoteErr := s.pricingService.QuoteStay(ctx, bookingStayQuoteInput(
+			booking, &booking.Unit, feeLine, booking.CheckInDate, booking.CheckOutDate,
+		))
+		if quoteErr != nil {
+			return nil, quoteErr
+		}
+		newQuote, quoteErr := s.pricingService.QuoteStay(ctx, bookingStayQuoteInput(
+			booking, newUnit, feeLine, checkIn, checkOut,
+		))
+		if quoteErr != nil {
+			return nil, quoteErr
+		}
+
+		settlement = settleBookingModification(previousTotal, oldQuote.Total, newQuote.Total)
+		modification.PreviousTotal = previousTotal
+		modification.Total = settlement.Total
+		modification.CreditAmount = settlement.CreditAmount
+	}
+
+	var creditInvoices []*models.Invoice
+	var credit bookingCreditSplit
+	if settlement.CreditAmount > 0 {
+		paid, paidErr := s.bookingAmountPaid(ctx, booking)
+		if paidErr != nil {
+			return nil, paidErr
+		}
+
+		var balances []int64
+		creditInvoices, balances = bookingOpenInvoices(booking)
+		credit = splitBookingCredit(settlement.CreditAmount, paid, balances)
+	}
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	booking.UnitID = unitID
+	booking.Unit = *newUnit
+	booking.CheckInDate = checkIn
+	booking.CheckOutDate = checkOut
+
+	if repriceInvoice {
+		invoiceID := booking.Invoice.ID.String()
+		if reissueInvoice {
+			if _, reopenErr := s.invoiceService.ReopenInvoice(transCtx, ReopenInvoiceInput{
+				InvoiceID: invoiceID,
+				Reason:    fmt.Sprintf("Booking #%s was changed", booking.Code),
+			}); reopenErr != nil {
+				transaction.Rollback()
+				return nil, reopenErr
+			}
+		}
+
+		quote, repriceErr := s.recalculateBookingInvoice(transCtx, booking)
+		if repriceErr != nil {
+			transaction.Rollback()
+			return nil, repriceErr
+		}
+		modification.Total = quote.Total
+
+		if reissueInvoice {
+			issued := "ISSUED"
+			if _, issueErr := s.invoiceService.UpdateInvoice(transCtx, UpdateInvoiceInput{
+				InvoiceID: invoiceID,
+				Status:    &issued,
+			}); issueErr != nil {
+				transaction.Rollback()
+				return nil, issueErr
+			}
+		}
+	}
+
+	if err := s.repo.Update(transCtx, booking); err != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ModifyBooking",
+				"action":   "updating booking",
+			},
+		})
+	}
+
+	if booking.Status == "CONFIRMED" || booking.Status == "CHECKED_IN" {
+		if err := s.moveBookingDateBlock(transCtx, booking); err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+	}
+
+	var invoice *models.Invoice
+	if settlement.Charge > 0 {
+		invoice, err = s.createBookingModificationInvoice(transCtx, booking, settlement.Charge)
+		if err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+		invoiceID := invoice.ID.String()
+		modification.InvoiceID = &invoiceID
+	}
+
+	if err := s.bookingModificationRepo.Create(transCtx, modification); err != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ModifyBooking",
+				"action":   "recording booking modification",
+			},
+		})
+	}
+
+	for i, amount := range credit.WriteOffs {
+		if amount == 0 {
+			continue
+		}
+		if _, err := s.invoiceService.WriteOffInvoiceBalance(transCtx, WriteOffInvoiceBalanceInput{
+			InvoiceID:                creditInvoices[i].ID.String(),
+			Amount:                   amount,
+			Reason:                   fmt.Sprintf("Booking #%s was changed", booking.Code),
+			WrittenOffByClientUserID: &input.ClientUserID,
+		}); err != nil {
+			transaction.Rollback()
+			return nil, err
+		}
+	}
+
+	if credit.Refund > 0 {
+		modification.Refund = bookingModificationRefund(booking, modification, credit.Refund, time.Now())
+		if err := s.bookingRefundRepo.Create(transCtx, modification.Refund); err != nil {
+			transaction.Rollback()
+			return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err: err,
+				Metadata: map[string]string{
+					"function": "ModifyBooking",
+					"action":   "creating booking modification refund",
+				},
+			})
+		}
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err: commitErr,
+			Metadata: map[string]string{
+				"function": "ModifyBooking",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	modification.Unit = *newUnit
+	modification.Invoice = invoice
+
+	go s.sendBookingModifiedNotification(*booking, *modification)
+
+	return modification, nil
+}
+
+// bookingModificationRefund refunds amount, the part of modification's credit
+// that did not come off what the guest still owed.
+func bookingModificationRefund(
+	booking *models.Booking,
+	modification *models.BookingModification,
+	amount int64,
+	now time.Time,
+) *models.BookingRefund {
+	modificationID := modification.ID.String()
+
+	return &models.BookingRefund{
+		BookingID:             booking.ID.String(),
+		BookingModificationID: &modificationID,
+		InvoiceID:             booking.Invoice.ID.String(),
+		CancellationPolicy:    booking.CancellationPolicy,
+		DaysBeforeCheckIn:     int64(calendarDate(booking.CheckInDate).Sub(calendarDate(now)).Hours() / 24),
+		RefundPercent:         100,
+		AmountPaid:            invoiceAmountPaid(booking.Invoice),
+		Amount:                amount,
+		Currency:              modification.Currency,
+		Status:                "PENDING",
+	}
+}
+
+// bookingAmountPaid is what the guest has paid towards booking and not had
+// back for an earlier change to the stay.
+func (s *bookingService) bookingAmountPaid(ctx context.Context, booking *models.Booking) (int64, error) {
+	modificationRefunds, err := s.bookingRefundRepo.ListForModifications(ctx, booking.ID.String())
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "bookingAmountPaid",
+				"action":   "listing booking modification refunds",
+			},
+		})
+	}
+	var credits int64
+	for _, modificationRefund := range modificationRefunds {
+		credits += modificationRefund.Amount
+	}
+
+	_, paid := bookingTotalAndPaid(booking, credits)
+	return paid, nil
+}
+
+// bookingStayTotal is what booking's stay is priced at now: the last
+// modification's total, or what its invoice charges for the stay if it has
+// none.
+func (s *bookingService) bookingStayTotal(
+	ctx context.Context,
+	booking *models.Booking,
+	feeLine *models.InvoiceLineItem,
+) (int64, error) {
+	latest, err := s.bookingModificationRepo.GetLatest(ctx, booking.ID.String())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return s.bookingInvoiceStayTotal(ctx, booking.Invoice.ID.String(), feeLine)
+		}
+
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "bookingStayTotal",
+				"action":   "fetching latest booking modification",
+			},
+		})
+	}
+
+	return latest.Total, nil
+}
+
+// moveBookingDateBlock replaces booking's date block with one for its new
+// unit and dates. Must be called within a transaction context.
+func (s *bookingService) moveBookingDateBlock(ctx context.Context, booking *models.Booking) error {
+	if err := s.unitDateBlockRepo.DeleteByBookingID(ctx, booking.ID.String()); err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "moveBookingDateBlock",
+				"action":   "removing booking date block",
+			},
+		})
+	}
+
+	bookingID := booking.ID.String()
+	if _, err := s.unitDateBlockService.CreateSystemBlock(ctx, CreateSystemBlockInput{
+		UnitID:    booking.UnitID,
+		StartDate: booking.CheckInDate,
+		EndDate:   booking.CheckOutDate,
+		BlockType: "BOOKING",
+		BookingID: &bookingID,
+		Reason:    fmt.Sprintf("System block for booking #%s", booking.Code),
+	}); err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "moveBookingDateBlock",
+				"action":   "creating system block",
+			},
+		})
+	}
+
+	return nil
+}
+
+// createBookingModificationInvoice bills charge for a change to booking on an
+// issued invoice of its own. It carries the booking as its context, under
+// BOOKING_MODIFICATION so it is not mistaken for the booking's own invoice.
+// Must be called within a transaction context.
+func (s *bookingService) createBookingModificationInvoice(
+	ctx context.Context,
+	booking *models.Booking,
+	charge int64,
+) (*models.Invoice, error) {
+	propertyID := booking.PropertyID
+	bookingID := booking.ID.String()
+	currency := booking.Invoice.Currency
+
+	return s.invoiceService.CreateInvoice(ctx, CreateInvoiceInput{
+		ClientID:         booking.Invoice.ClientID,
+		PropertyID:       &propertyID,
+		PayerType:        "GUEST",
+		PayeeType:        "PROPERTY_OWNER",
+		PayeeClientID:    booking.Invoice.ClientID,
+		ContextType:      "BOOKING_MODIFICATION",
+		ContextBookingID: &bookingID,
+		TotalAmount:      charge,
+		SubTotal:         charge,
+		Currency:         currency,
+		Status:           "ISSUED",
+		LineItems: []LineItemInput{{
+			Label: fmt.Sprintf(
+				"Change to booking %s: %s, %s to %s",
+				booking.Code,
+				booking.Unit.Name,
+				booking.CheckInDate.Format(time.DateOnly),
+				booking.CheckOutDate.Format(time.DateOnly),
+			),
+			Category:    "BOOKING_FEE",
+			Quantity:    1,
+			UnitAmount:  charge,
+			TotalAmount: charge,
+			Currency:    currency,
+		}},
+	})
+}
+
+// sendBookingModifiedNotification tells the guest their stay has changed,
+// with any supplementary invoice to pay or credit coming back to them.
+func (s *bookingService) sendBookingModifiedNotification(
+	booking models.Booking,
+	modification models.BookingModification,
+) {
+	emailData := emailtemplates.BookingModifiedData{
+		GuestName:    booking.Tenant.FirstName,
+		UnitName:     booking.Unit.Name,
+		CheckInDate:  booking.CheckInDate.Format("January 2, 2006 3:04pm"),
+		CheckOutDate: booking.CheckOutDate.Format("January 2, 2006 3:04pm"),
+		TrackingCode: booking.Code,
+		Currency:     modification.Currency,
+	}
+	settlement := ""
+	if modification.Invoice != nil {
+		emailData.AmountDue = lib.FormatAmount(lib.PesewasToCedis(modification.Invoice.TotalAmount))
+		settlement = fmt.Sprintf(" Please pay the extra %s %s.", modification.Currency, emailData.AmountDue)
+	}
+	writtenOff := modification.CreditAmount
+	if modification.Refund != nil {
+		writtenOff -= modification.Refund.Amount
+	}
+	if writtenOff > 0 {
+		emailData.BalanceReduction = lib.FormatAmount(lib.PesewasToCedis(writtenOff))
+		settlement = fmt.Sprintf(
+			" What you owe is reduced by %s %s.", modification.Currency, emailData.BalanceReduction,
+		)
+	}
+	if modification.Refund != nil {
+		emailData.RefundAmount = lib.FormatAmount(lib.PesewasToCedis(modification.Refund.Amount))
+		settlement += fmt.Sprintf(" You will be refunded %s %s.", modification.Currency, emailData.RefundAmount)
+	}
+
+	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render("booking/modified", emailData)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render booking modified email template")
+	}
+
+	if booking.Tenant.Email != nil {
+		go pkg.SendEmail(
+			s.appCtx.Config,
+			pkg.SendEmailInput{
+				Recipient: *booking.Tenant.Email,
+				Subject:   lib.BOOKING_MODIFIED_SUBJECT,
+				HtmlBody:  htmlBody,
+				TextBody:  textBody,
+			},
+		)
+	}
+
+	smsBody := strings.NewReplacer(
+		"{{tenant_name}}", booking.Tenant.FirstName,
+		"{{unit_name}}", booking.Unit.Name,
+		"{{check_in_date}}", booking.CheckInDate.Format("January 2, 2006 3:04pm"),
+		"{{check_out_date}}", booking.CheckOutDate.Format("January 2, 2006 3:04pm"),
+		"{{settlement}}", settlement,
+		"{{booking_code}}", booking.Code,
+	).Replace(lib.BOOKING_MODIFIED_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: booking.Tenant.Phone,
+			Message:   smsBody,
+		},
+	)
+}
+
+// ListBookingModifications returns a booking's modifications, oldest first.
+func (s *bookingService) ListBookingModifications(
+	ctx context.Context,
+	bookingID string,
+	populate *[]string,
+) ([]models.BookingModification, error) {
+	modifications, err := s.bookingModificationRepo.ListByBookingID(ctx, bookingID, populate)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ListBookingModifications",
+				"action":   "listing booking modifications",
+			},
+		})
+	}
+
+	return modifications, nil
+}
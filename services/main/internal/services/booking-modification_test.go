@@ -0,0 +1,126 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+// A stay can be changed until the guest leaves. Once they have checked in,
+// they are already in the unit and the nights behind them are spent, so only
+// the check-out can move, and not into the past.
+func TestValidateBookingModification(t *testing.T) {
+	checkIn := time.Date(2026, 10, 22, 14, 0, 0, 0, time.UTC)
+	checkOut := time.Date(2026, 10, 25, 11, 0, 0, 0, time.UTC)
+	now := time.Date(2026, 10, 23, 9, 0, 0, 0, time.UTC)
+	unit, otherUnit := "unit-a", "unit-b"
+
+	cases := []struct {
+		name     string
+		status   string
+		unitID   string
+		checkIn  time.Time
+		checkOut time.Time
+		wantErr  string
+	}{
+		{"extend a confirmed stay", "CONFIRMED", unit, checkIn, checkOut.AddDate(0, 0, 2), ""},
+		{"move a pending stay", "PENDING", otherUnit, checkIn, checkOut, ""},
+		{"extend a checked-in stay", "CHECKED_IN", unit, checkIn, checkOut.AddDate(0, 0, 2), ""},
+		{"shorten a checked-in stay", "CHECKED_IN", unit, checkIn, checkOut.AddDate(0, 0, -1), ""},
+		{
+			"check out a checked-in stay in the past", "CHECKED_IN", unit, checkIn, now.Add(-time.Hour),
+			"check_out_date of a checked-in booking must be in the future",
+		},
+		{
+			"move a checked-in stay", "CHECKED_IN", otherUnit, checkIn, checkOut,
+			"only the check_out_date of a checked-in booking can change",
+		},
+		{
+			"change a completed stay", "COMPLETED", unit, checkIn, checkOut.AddDate(0, 0, 1),
+			"only PENDING, CONFIRMED or CHECKED_IN bookings can be modified",
+		},
+		{"nothing changes", "CONFIRMED", unit, checkIn, checkOut, "BookingModificationChangesNothing"},
+		{
+			"check-out before check-in", "CONFIRMED", unit, checkIn, checkIn.Add(-time.Hour),
+			"check_out_date must be after check_in_date",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			booking := &models.Booking{
+				Status:       tc.status,
+				UnitID:       unit,
+				CheckInDate:  checkIn,
+				CheckOutDate: checkOut,
+			}
+
+			err := validateBookingModification(booking, tc.unitID, tc.checkIn, tc.checkOut, now)
+
+			var rentLoopErr *pkg.IRentLoopError
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			case tc.wantErr != "" && (!errors.As(err, &rentLoopErr) || rentLoopErr.Message != tc.wantErr):
+				t.Fatalf("err = %v, want %s", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// Only the difference the change makes is billed or credited, carried on
+// from what the stay was already priced at.
+func TestSettleBookingModification(t *testing.T) {
+	cases := []struct {
+		name                       string
+		previous, oldStay, newStay int64
+		want                       bookingModificationSettlement
+	}{
+		{"two more nights", 1500, 1500, 2500, bookingModificationSettlement{Total: 2500, Charge: 1000}},
+		{"one night fewer", 1500, 1500, 1000, bookingModificationSettlement{Total: 1000, CreditAmount: 500}},
+		{"rates rose since booking", 1500, 1800, 2400, bookingModificationSettlement{Total: 2100, Charge: 600}},
+		{"same price elsewhere", 1500, 1500, 1500, bookingModificationSettlement{Total: 1500}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := settleBookingModification(tc.previous, tc.oldStay, tc.newStay); got != tc.want {
+				t.Errorf("got %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+// A credit first comes off what the guest still owes, and only the rest is
+// refunded. A guest who has paid nothing is refunded nothing, and no one is
+// refunded more than they paid.
+func TestSplitBookingCredit(t *testing.T) {
+	cases := []struct {
+		name       string
+		credit     int64
+		paid       int64
+		balances   []int64
+		wantWrites []int64
+		wantRefund int64
+	}{
+		{"fully paid", 3000, 10000, []int64{}, []int64{}, 3000},
+		{"nothing paid yet", 3000, 0, []int64{10000}, []int64{3000}, 0},
+		{"part paid, balance covers it", 3000, 4000, []int64{6000}, []int64{3000}, 0},
+		{"part paid, balance too small", 3000, 8000, []int64{2000}, []int64{2000}, 1000},
+		{"unpaid extension first", 3000, 10000, []int64{0, 1000}, []int64{0, 1000}, 2000},
+		{"refund capped at paid", 3000, 500, []int64{1000}, []int64{1000}, 500},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := splitBookingCredit(tc.credit, tc.paid, tc.balances)
+			if !slices.Equal(got.WriteOffs, tc.wantWrites) || got.Refund != tc.wantRefund {
+				t.Errorf("got %v refund %d, want %v refund %d", got.WriteOffs, got.Refund, tc.wantWrites, tc.wantRefund)
+			}
+		})
+	}
+}
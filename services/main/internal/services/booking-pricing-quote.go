@@ -0,0 +1,283 @@
+package services
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+// BookingQuote is what a stay costs.
+type BookingQuote struct {
+	Currency  string
+	Frequency string // the unit's PaymentFrequency
+	// Quantity is how many periods of Frequency the stay takes, counted up:
+	// 26 hours at a DAILY unit is 2 nights.
+	Quantity    int64
+	PeriodLabel string // nights | hours | weeks | months
+	Rate        int64  // per period before any rule
+	// Nights is the stay night by night. Only DAILY stays are priced by
+	// night, so it is empty for any other frequency.
+	Nights          []BookingQuoteNight
+	SubTotal        int64
+	DiscountPercent int64
+	DiscountRuleID  *string
+	Discount        int64
+	// Guests above the unit's base occupancy are each charged ExtraGuestFee
+	// per period, on top of the stay.
+	ExtraGuests     int64
+	ExtraGuestFee   int64
+	ExtraGuestTotal int64
+	Total           int64
+}
+
+type BookingQuoteNight struct {
+	Date             time.Time
+	Rate             int64
+	RateRuleID       *string // the RATE rule that set the night's base rate
+	AdjustmentRuleID *string // the ADJUSTMENT rule applied on top of it
+}
+
+// uniformRate returns the one rate every period of the quote is charged at,
+// if there is one and no discount comes off the total.
+func (q *BookingQuote) uniformRate() (int64, bool) {
+	if q.Discount != 0 {
+		return 0, false
+	}
+	for _, night := range q.Nights {
+		if night.Rate != q.Nights[0].Rate {
+			return 0, false
+		}
+	}
+	if len(q.Nights) > 0 {
+		return q.Nights[0].Rate, true
+	}
+	return q.Rate, true
+}
+
+// stayTotal is what the stay itself costs, without extra guests.
+func (q *BookingQuote) stayTotal() int64 {
+	return q.Total - q.ExtraGuestTotal
+}
+
+type bookingStay struct {
+	Rate      int64
+	Currency  string
+	Frequency string
+	CheckIn   time.Time
+	CheckOut  time.Time
+	// Guests beyond BaseOccupancy pay ExtraGuestFee each per period.
+	Guests        int64
+	BaseOccupancy int
+	ExtraGuestFee int64
+}
+
+// bookingExtraGuests is how many of guests are above baseOccupancy. A unit
+// with no base occupancy charges no one extra.
+func bookingExtraGuests(baseOccupancy int, guests int64) int64 {
+	if baseOccupancy <= 0 {
+		return 0
+	}
+	return max(guests-int64(baseOccupancy), 0)
+}
+
+// quoteBookingStay prices stay under rules. A DAILY stay is priced night by
+// night; stays at any other frequency are Rate per period as they always
+// were, whatever the rules say.
+func quoteBookingStay(stay bookingStay, rules []models.BookingPricingRule) (*BookingQuote, error) {
+	hours := stay.CheckOut.Sub(stay.CheckIn).Hours()
+
+	quote := &BookingQuote{
+		Currency:    stay.Currency,
+		Frequency:   stay.Frequency,
+		Rate:        stay.Rate,
+		PeriodLabel: "nights",
+	}
+	switch stay.Frequency {
+	case "HOURLY":
+		quote.Quantity = int64(math.Ceil(hours))
+		quote.PeriodLabel = "hours"
+	case "DAILY":
+		quote.Quantity = int64(math.Ceil(hours / 24))
+	case "WEEKLY":
+		quote.Quantity = int64(math.Ceil(hours / (24 * 7)))
+		quote.PeriodLabel = "weeks"
+	case "MONTHLY":
+		quote.Quantity = int64(math.Ceil(hours / (24 * 30)))
+		quote.PeriodLabel = "months"
+	}
+
+	if stay.Frequency != "DAILY" {
+		quote.SubTotal = quote.Quantity * stay.Rate
+		quote.Total = quote.SubTotal
+		addBookingExtraGuests(quote, stay)
+		return quote, nil
+	}
+
+	ordered := orderBookingPricingRules(rules)
+	firstNight := calendarDate(stay.CheckIn)
+
+	if lengthRule := matchingPricingRule(ordered, "STAY_LENGTH", firstNight); lengthRule != nil {
+		if lengthRule.MinNights != nil && quote.Quantity < *lengthRule.MinNights {
+			return nil, pkg.BadRequestError("BookingStayTooShort", nil)
+		}
+		if lengthRule.MaxNights != nil && quote.Quantity > *lengthRule.MaxNights {
+			return nil, pkg.BadRequestError("BookingStayTooLong", nil)
+		}
+	}
+
+	quote.Nights = make([]BookingQuoteNight, 0, quote.Quantity)
+	for i := range quote.Quantity {
+		date := firstNight.AddDate(0, 0, int(i))
+		night := BookingQuoteNight{Date: date, Rate: stay.Rate}
+
+		if rateRule := matchingPricingRule(ordered, "RATE", date); rateRule != nil && rateRule.NightlyRate != nil {
+			ruleID := rateRule.ID.String()
+			night.Rate = *rateRule.NightlyRate
+			night.RateRuleID = &ruleID
+		}
+		if adjustment := matchingPricingRule(ordered, "ADJUSTMENT", date); adjustment != nil &&
+			adjustment.AdjustmentPercent != nil {
+			ruleID := adjustment.ID.String()
+			night.Rate = max(night.Rate+percentOf(night.Rate, *adjustment.AdjustmentPercent), 0)
+			night.AdjustmentRuleID = &ruleID
+		}
+
+		quote.Nights = append(quote.Nights, night)
+		quote.SubTotal += night.Rate
+	}
+
+	// The discount for the longest stay this one reaches; ordered breaks
+	// ties between rules for the same length.
+	var discountRule *models.BookingPricingRule
+	for i := range ordered {
+		rule := &ordered[i]
+		if rule.Type != "LENGTH_DISCOUNT" || rule.MinNights == nil || rule.DiscountPercent == nil ||
+			quote.Quantity < *rule.MinNights || !pricingRuleMatchesNight(rule, firstNight) {
+			continue
+		}
+		if discountRule == nil || *rule.MinNights > *discountRule.MinNights {
+			discountRule = rule
+		}
+	}
+	if discountRule != nil {
+		ruleID := discountRule.ID.String()
+		quote.DiscountPercent = *discountRule.DiscountPercent
+		quote.DiscountRuleID = &ruleID
+		quote.Discount = percentOf(quote.SubTotal, quote.DiscountPercent)
+	}
+
+	quote.Total = quote.SubTotal - quote.Discount
+	addBookingExtraGuests(quote, stay)
+	return quote, nil
+}
+
+// addBookingExtraGuests charges quote for stay's guests above the base
+// occupancy, for every period of the stay. Length discounts do not apply.
+func addBookingExtraGuests(quote *BookingQuote, stay bookingStay) {
+	quote.ExtraGuests = bookingExtraGuests(stay.BaseOccupancy, stay.Guests)
+	if quote.ExtraGuests == 0 || stay.ExtraGuestFee <= 0 {
+		quote.ExtraGuests = 0
+		return
+	}
+	quote.ExtraGuestFee = stay.ExtraGuestFee
+	quote.ExtraGuestTotal = quote.ExtraGuests * stay.ExtraGuestFee * quote.Quantity
+	quote.Total += quote.ExtraGuestTotal
+}
+
+// orderBookingPricingRules sorts a copy of rules so the one that wins comes
+// first: highest Priority, then a unit's own rule, then the newest.
+func orderBookingPricingRules(rules []models.BookingPricingRule) []models.BookingPricingRule {
+	ordered := make([]models.BookingPricingRule, len(rules))
+	copy(ordered, rules)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		a, b := ordered[i], ordered[j]
+		if a.Priority != b.Priority {
+			return a.Priority > b.Priority
+		}
+		if (a.UnitID != nil) != (b.UnitID != nil) {
+			return a.UnitID != nil
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+	return ordered
+}
+
+func matchingPricingRule(
+	ordered []models.BookingPricingRule,
+	ruleType string,
+	night time.Time,
+) *models.BookingPricingRule {
+	for i := range ordered {
+		if ordered[i].Type == ruleType && pricingRuleMatchesNight(&ordered[i], night) {
+			return &ordered[i]
+		}
+	}
+	return nil
+}
+
+var pricingRuleWeekdays = [...]string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+func pricingRuleMatchesNight(rule *models.BookingPricingRule, night time.Time) bool {
+	if rule.StartDate != nil && night.Before(calendarDate(*rule.StartDate)) {
+		return false
+	}
+	if rule.EndDate != nil && night.After(calendarDate(*rule.EndDate)) {
+		return false
+	}
+	if len(rule.DaysOfWeek) == 0 {
+		return true
+	}
+	weekday := pricingRuleWeekdays[night.Weekday()]
+	for _, day := range rule.DaysOfWeek {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// percentOf is percent% of amount, rounded half away from zero.
+func percentOf(amount int64, percent int64) int64 {
+	scaled := amount * percent
+	if scaled < 0 {
+		return (scaled - 50) / 100
+	}
+	return (scaled + 50) / 100
+}
+
+// validateBookingPricingRule checks rule carries what its Type needs.
+func validateBookingPricingRule(rule *models.BookingPricingRule) error {
+	if rule.StartDate != nil && rule.EndDate != nil &&
+		calendarDate(*rule.EndDate).Before(calendarDate(*rule.StartDate)) {
+		return pkg.BadRequestError("PricingRuleEndsBeforeItStarts", nil)
+	}
+
+	switch rule.Type {
+	case "RATE":
+		if rule.NightlyRate == nil || *rule.NightlyRate <= 0 {
+			return pkg.BadRequestError("PricingRuleNightlyRateRequired", nil)
+		}
+	case "ADJUSTMENT":
+		if rule.AdjustmentPercent == nil || *rule.AdjustmentPercent == 0 {
+			return pkg.BadRequestError("PricingRuleAdjustmentPercentRequired", nil)
+		}
+	case "STAY_LENGTH":
+		if rule.MinNights == nil && rule.MaxNights == nil {
+			return pkg.BadRequestError("PricingRuleStayLengthRequired", nil)
+		}
+		if rule.MinNights != nil && rule.MaxNights != nil && *rule.MinNights > *rule.MaxNights {
+			return pkg.BadRequestError("PricingRuleMinNightsAboveMaxNights", nil)
+		}
+	case "LENGTH_DISCOUNT":
+		if rule.MinNights == nil || rule.DiscountPercent == nil {
+			return pkg.BadRequestError("PricingRuleDiscountRequired", nil)
+		}
+	default:
+		return pkg.BadRequestError("PricingRuleTypeInvalid", nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/lib/pq"
+)
+
+// A night's rate is the season's rate if one covers it, moved by any weekday
+// adjustment; stay-length limits and length discounts follow the night the
+// guest checks in. Units not let by the day are priced per period as before.
+func TestQuoteBookingStay(t *testing.T) {
+	amount := func(n int64) *int64 { return &n }
+	date := func(s string) *time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return &d
+	}
+	unitID := "660e8400-e29b-41d4-a716-446655440000"
+
+	weekend := models.BookingPricingRule{
+		Type: "ADJUSTMENT", DaysOfWeek: pq.StringArray{"FRI", "SAT"}, AdjustmentPercent: amount(20),
+	}
+	christmas := models.BookingPricingRule{
+		Type: "RATE", StartDate: date("2026-12-24"), EndDate: date("2026-12-26"), NightlyRate: amount(900),
+	}
+	weekly := models.BookingPricingRule{Type: "LENGTH_DISCOUNT", MinNights: amount(7), DiscountPercent: amount(10)}
+	monthly := models.BookingPricingRule{Type: "LENGTH_DISCOUNT", MinNights: amount(28), DiscountPercent: amount(25)}
+
+	// Thursday afternoon to Sunday morning: three nights.
+	thursday := time.Date(2026, 10, 22, 14, 0, 0, 0, time.UTC)
+	sunday := time.Date(2026, 10, 25, 11, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		frequency    string
+		checkIn      time.Time
+		checkOut     time.Time
+		rules        []models.BookingPricingRule
+		wantNights   string
+		wantDiscount int64
+		wantTotal    int64
+		wantErr      string
+	}{
+		{"no rules", "DAILY", thursday, sunday, nil, "[500 500 500]", 0, 1500, ""},
+		{
+			"weekend adjustment",
+			"DAILY", thursday, sunday,
+			[]models.BookingPricingRule{weekend},
+			"[500 600 600]", 0, 1700, "",
+		},
+		{
+			"season rate with the weekend on top",
+			"DAILY", time.Date(2026, 12, 23, 14, 0, 0, 0, time.UTC), time.Date(2026, 12, 27, 11, 0, 0, 0, time.UTC),
+			[]models.BookingPricingRule{weekend, christmas},
+			"[500 900 1080 1080]", 0, 3560, "",
+		},
+		{
+			"higher priority wins",
+			"DAILY", thursday, sunday,
+			[]models.BookingPricingRule{
+				{Type: "RATE", NightlyRate: amount(700)},
+				{Type: "RATE", NightlyRate: amount(650), Priority: 1},
+			},
+			"[650 650 650]", 0, 1950, "",
+		},
+		{
+			"unit's own rule over the property's",
+			"DAILY", thursday, sunday,
+			[]models.BookingPricingRule{
+				{Type: "RATE", NightlyRate: amount(700)},
+				{Type: "RATE", NightlyRate: amount(800), UnitID: &unitID},
+			},
+			"[800 800 800]", 0, 2400, "",
+		},
+		{
+			"below minimum stay",
+			"DAILY", thursday, thursday.Add(44 * time.Hour),
+			[]models.BookingPricingRule{{Type: "STAY_LENGTH", MinNights: amount(3)}},
+			"", 0, 0, "BookingStayTooShort",
+		},
+		{
+			"minimum stay only for the season",
+			"DAILY", thursday, thursday.Add(44 * time.Hour),
+			[]models.BookingPricingRule{
+				{Type: "STAY_LENGTH", StartDate: date("2026-12-20"), EndDate: date("2027-01-02"), MinNights: amount(5)},
+			},
+			"[500 500]", 0, 1000, "",
+		},
+		{
+			"above maximum stay",
+			"DAILY", monday, monday.AddDate(0, 0, 8),
+			[]models.BookingPricingRule{{Type: "STAY_LENGTH", MaxNights: amount(7)}},
+			"", 0, 0, "BookingStayTooLong",
+		},
+		{
+			"weekly discount",
+			"DAILY", monday, monday.AddDate(0, 0, 8),
+			[]models.BookingPricingRule{weekly, monthly},
+			"[500 500 500 500 500 500 500 500]", 400, 3600, "",
+		},
+		{
+			"monthly discount over weekly",
+			"DAILY", monday, monday.AddDate(0, 0, 30),
+			[]models.BookingPricingRule{weekly, monthly},
+			"", 3750, 11250, "",
+		},
+		{
+			"weekly unit ignores rules",
+			"WEEKLY", monday, monday.AddDate(0, 0, 10),
+			[]models.BookingPricingRule{weekend, weekly},
+			"[]", 0, 1000, "",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			quote, err := quoteBookingStay(bookingStay{
+				Rate:      500,
+				Currency:  "GHS",
+				Frequency: tc.frequency,
+				CheckIn:   tc.checkIn,
+				CheckOut:  tc.checkOut,
+			}, tc.rules)
+
+			var rentLoopErr *pkg.IRentLoopError
+			if tc.wantErr != "" {
+				if !errors.As(err, &rentLoopErr) || rentLoopErr.Message != tc.wantErr {
+					t.Fatalf("err = %v, want %s", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("quoteBookingStay: %v", err)
+			}
+
+			rates := make([]int64, 0, len(quote.Nights))
+			for _, night := range quote.Nights {
+				rates = append(rates, night.Rate)
+			}
+			if got := fmt.Sprint(rates); tc.wantNights != "" && got != tc.wantNights {
+				t.Errorf("nights = %s, want %s", got, tc.wantNights)
+			}
+			if quote.Discount != tc.wantDiscount || quote.Total != tc.wantTotal {
+				t.Errorf("discount, total = %d, %d, want %d, %d",
+					quote.Discount, quote.Total, tc.wantDiscount, tc.wantTotal)
+			}
+		})
+	}
+}
+
+// Guests above the unit's base occupancy pay a flat fee each for every
+// period of the stay, after any length discount and whatever the rate.
+func TestQuoteBookingStayExtraGuests(t *testing.T) {
+	amount := func(n int64) *int64 { return &n }
+	monday := time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC)
+	weekly := models.BookingPricingRule{Type: "LENGTH_DISCOUNT", MinNights: amount(7), DiscountPercent: amount(10)}
+
+	cases := []struct {
+		name          string
+		frequency     string
+		nights        int
+		guests        int64
+		baseOccupancy int
+		rules         []models.BookingPricingRule
+		wantExtra     int64
+		wantTotal     int64
+	}{
+		{"no base occupancy", "DAILY", 3, 4, 0, nil, 0, 1500},
+		{"within base occupancy", "DAILY", 3, 2, 2, nil, 0, 1500},
+		{"two extra for three nights", "DAILY", 3, 4, 2, nil, 600, 2100},
+		{"not discounted", "DAILY", 8, 3, 2, []models.BookingPricingRule{weekly}, 800, 4400},
+		{"per week at a weekly unit", "WEEKLY", 10, 3, 2, nil, 200, 1200},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			quote, err := quoteBookingStay(bookingStay{
+				Rate:          500,
+				Currency:      "GHS",
+				Frequency:     tc.frequency,
+				CheckIn:       monday,
+				CheckOut:      monday.AddDate(0, 0, tc.nights),
+				Guests:        tc.guests,
+				BaseOccupancy: tc.baseOccupancy,
+				ExtraGuestFee: 100,
+			}, tc.rules)
+			if err != nil {
+				t.Fatalf("quoteBookingStay: %v", err)
+			}
+
+			if quote.ExtraGuestTotal != tc.wantExtra || quote.Total != tc.wantTotal {
+				t.Errorf("extra, total = %d, %d, want %d, %d",
+					quote.ExtraGuestTotal, quote.Total, tc.wantExtra, tc.wantTotal)
+			}
+			if quote.stayTotal() != tc.wantTotal-tc.wantExtra {
+				t.Errorf("stay total = %d, want %d", quote.stayTotal(), tc.wantTotal-tc.wantExtra)
+			}
+		})
+	}
+}
+
+// A rate agreed with the guest replaces what the rules would charge a night,
+// but the unit's stay-length limits and length discounts still hold.
+func TestWithoutNightlyRateRules(t *testing.T) {
+	amount := func(n int64) *int64 { return &n }
+	monday := time.Date(2026, 11, 2, 14, 0, 0, 0, time.UTC)
+	rules := []models.BookingPricingRule{
+		{Type: "RATE", NightlyRate: amount(900)},
+		{Type: "ADJUSTMENT", AdjustmentPercent: amount(20)},
+		{Type: "STAY_LENGTH", MinNights: amount(2)},
+		{Type: "LENGTH_DISCOUNT", MinNights: amount(7), DiscountPercent: amount(10)},
+	}
+	stay := func(nights int) bookingStay {
+		return bookingStay{
+			Rate:      400,
+			Currency:  "GHS",
+			Frequency: "DAILY",
+			CheckIn:   monday,
+			CheckOut:  monday.AddDate(0, 0, nights),
+		}
+	}
+
+	quote, err := quoteBookingStay(stay(7), withoutNightlyRateRules(rules))
+	if err != nil {
+		t.Fatalf("quoteBookingStay: %v", err)
+	}
+	if quote.SubTotal != 2800 || quote.Discount != 280 {
+		t.Errorf("subtotal, discount = %d, %d, want 2800, 280", quote.SubTotal, quote.Discount)
+	}
+
+	_, err = quoteBookingStay(stay(1), withoutNightlyRateRules(rules))
+	var rentLoopErr *pkg.IRentLoopError
+	if !errors.As(err, &rentLoopErr) || rentLoopErr.Message != "BookingStayTooShort" {
+		t.Errorf("one night: err = %v, want BookingStayTooShort", err)
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type BookingPricingService interface {
+	CreateRule(ctx context.Context, input CreateBookingPricingRuleInput) (*models.BookingPricingRule, error)
+	UpdateRule(ctx context.Context, input UpdateBookingPricingRuleInput) (*models.BookingPricingRule, error)
+	DeleteRule(ctx context.Context, input repository.DeleteBookingPricingRuleInput) error
+	GetRule(ctx context.Context, query repository.GetBookingPricingRuleQuery) (*models.BookingPricingRule, error)
+	ListRules(ctx context.Context, filter repository.ListBookingPricingRulesFilter) ([]models.BookingPricingRule, error)
+	CountRules(ctx context.Context, filter repository.ListBookingPricingRulesFilter) (int64, error)
+	// QuoteStay prices a stay at the unit's rent fee under its pricing rules.
+	QuoteStay(ctx context.Context, input QuoteBookingStayInput) (*BookingQuote, error)
+}
+
+type bookingPricingService struct {
+	appCtx      pkg.AppContext
+	repo        repository.BookingPricingRuleRepository
+	unitService UnitService
+}
+
+func NewBookingPricingService(
+	appCtx pkg.AppContext,
+	repo repository.BookingPricingRuleRepository,
+	unitService UnitService,
+) BookingPricingService {
+	return &bookingPricingService{appCtx: appCtx, repo: repo, unitService: unitService}
+}
+
+type CreateBookingPricingRuleInput struct {
+	PropertyID            string
+	UnitID                *string
+	Name                  string
+	Type                  string // RATE | ADJUSTMENT | STAY_LENGTH | LENGTH_DISCOUNT
+	Priority              int64
+	StartDate             *time.Time
+	EndDate               *time.Time
+	DaysOfWeek            []string
+	NightlyRate           *int64
+	AdjustmentPercent     *int64
+	MinNights             *int64
+	MaxNights             *int64
+	DiscountPercent       *int64
+	CreatedByClientUserID string
+}
+
+func (s *bookingPricingService) CreateRule(
+	ctx context.Context,
+	input CreateBookingPricingRuleInput,
+) (*models.BookingPricingRule, error) {
+	if err := s.assertUnitInProperty(ctx, input.PropertyID, input.UnitID); err != nil {
+		return nil, err
+	}
+
+	daysOfWeek := pq.StringArray{}
+	if input.DaysOfWeek != nil {
+		daysOfWeek = pq.StringArray(input.DaysOfWeek)
+	}
+
+	rule := &models.BookingPricingRule{
+		PropertyID:            input.PropertyID,
+		UnitID:                input.UnitID,
+		Name:                  input.Name,
+		Type:                  input.Type,
+		Priority:              input.Priority,
+		StartDate:             input.StartDate,
+		EndDate:               input.EndDate,
+		DaysOfWeek:            daysOfWeek,
+		NightlyRate:           input.NightlyRate,
+		AdjustmentPercent:     input.AdjustmentPercent,
+		MinNights:             input.MinNights,
+		MaxNights:             input.MaxNights,
+		DiscountPercent:       input.DiscountPercent,
+		CreatedByClientUserID: &input.CreatedByClientUserID,
+	}
+	if err := validateBookingPricingRule(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CreateRule",
+				"action":   "creating booking pricing rule",
+			},
+		})
+	}
+
+	return rule, nil
+}
+
+type UpdateBookingPricingRuleInput struct {
+	ID                string
+	PropertyID        string
+	UnitID            lib.Optional[string]
+	Name              *string
+	Type              *string
+	Priority          *int64
+	StartDate         lib.Optional[time.Time]
+	EndDate           lib.Optional[time.Time]
+	DaysOfWeek        *[]string
+	NightlyRate       lib.Optional[int64]
+	AdjustmentPercent lib.Optional[int64]
+	MinNights         lib.Optional[int64]
+	MaxNights         lib.Optional[int64]
+	DiscountPercent   lib.Optional[int64]
+}
+
+func (s *bookingPricingService) UpdateRule(
+	ctx context.Context,
+	input UpdateBookingPricingRuleInput,
+) (*models.BookingPricingRule, error) {
+	rule, err := s.GetRule(ctx, repository.GetBookingPricingRuleQuery{ID: input.ID, PropertyID: input.PropertyID})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.UnitID.IsSet {
+		if unitErr := s.assertUnitInProperty(ctx, input.PropertyID, input.UnitID.Value); unitErr != nil {
+			return nil, unitErr
+		}
+		rule.UnitID = input.UnitID.Value
+		rule.Unit = nil
+	}
+	if input.Name != nil {
+		rule.Name = *input.Name
+	}
+	if input.Type != nil {
+		rule.Type = *input.Type
+	}
+	if input.Priority != nil {
+		rule.Priority = *input.Priority
+	}
+	if input.StartDate.IsSet {
+		rule.StartDate = input.StartDate.Value
+	}
+	if input.EndDate.IsSet {
+		rule.EndDate = input.EndDate.Value
+	}
+	if input.DaysOfWeek != nil {
+		rule.DaysOfWeek = pq.StringArray(*input.DaysOfWeek)
+	}
+	if input.NightlyRate.IsSet {
+		rule.NightlyRate = input.NightlyRate.Value
+	}
+	if input.AdjustmentPercent.IsSet {
+		rule.AdjustmentPercent = input.AdjustmentPercent.Value
+	}
+	if input.MinNights.IsSet {
+		rule.MinNights = input.MinNights.Value
+	}
+	if input.MaxNights.IsSet {
+		rule.MaxNights = input.MaxNights.Value
+	}
+	if input.DiscountPercent.IsSet {
+		rule.DiscountPercent = input.DiscountPercent.Value
+	}
+
+	if validateErr := validateBookingPricingRule(rule); validateErr != nil {
+		return nil, validateErr
+	}
+
+	if updateErr := s.repo.Update(ctx, rule); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function": "UpdateRule",
+				"action":   "updating booking pricing rule",
+			},
+		})
+	}
+
+	return rule, nil
+}
+
+func (s *bookingPricingService) DeleteRule(ctx context.Context, input repository.DeleteBookingPricingRuleInput) error {
+	if _, err := s.GetRule(ctx, repository.GetBookingPricingRuleQuery{
+		ID:         input.ID,
+		PropertyID: input.PropertyID,
+	}); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, input); err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "DeleteRule",
+				"action":   "deleting booking pricing rule",
+			},
+		})
+	}
+
+	return nil
+}
+
+func (s *bookingPricingService) GetRule(
+	ctx context.Context,
+	query repository.GetBookingPricingRuleQuery,
+) (*models.BookingPricingRule, error) {
+	rule, err := s.repo.GetOne(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("BookingPricingRuleNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GetRule",
+				"action":   "fetching booking pricing rule",
+			},
+		})
+	}
+
+	return rule, nil
+}
+
+func (s *bookingPricingService) ListRules(
+	ctx context.Context,
+	filter repository.ListBookingPricingRulesFilter,
+) ([]models.BookingPricingRule, error) {
+	rules, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ListRules",
+				"action":   "listing booking pricing rules",
+			},
+		})
+	}
+
+	return *rules, nil
+}
+
+func (s *bookingPricingService) CountRules(
+	ctx context.Context,
+	filter repository.ListBookingPricingRulesFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CountRules",
+				"action":   "counting booking pricing rules",
+			},
+		})
+	}
+
+	return count, nil
+}
+
+type QuoteBookingStayInput struct {
+	Unit         *models.Unit
+	CheckInDate  time.Time
+	CheckOutDate time.Time
+	// Rate, when set, is a rate per period agreed with the guest. It replaces
+	// the unit's rent fee and its RATE and ADJUSTMENT rules; the unit's
+	// stay-length limits and length discounts still apply.
+	Rate *int64
+	// Guests is how many are staying, adults and children alike. 0 is one.
+	Guests int64
+}
+
+func (s *bookingPricingService) QuoteStay(ctx context.Context, input QuoteBookingStayInput) (*BookingQuote, error) {
+	if !input.CheckOutDate.After(input.CheckInDate) {
+		return nil, pkg.BadRequestError("check_out_date must be after check_in_date", nil)
+	}
+
+	guests := max(input.Guests, 1)
+	if guests > int64(input.Unit.MaxOccupantsAllowed) {
+		return nil, pkg.BadRequestError("BookingExceedsUnitOccupancy", nil)
+	}
+
+	stay := bookingStay{
+		Rate:          input.Unit.RentFee,
+		Currency:      input.Unit.RentFeeCurrency,
+		Frequency:     input.Unit.PaymentFrequency,
+		CheckIn:       input.CheckInDate,
+		CheckOut:      input.CheckOutDate,
+		Guests:        guests,
+		BaseOccupancy: input.Unit.BaseOccupancy,
+		ExtraGuestFee: input.Unit.ExtraGuestFee,
+	}
+	if input.Rate != nil {
+		stay.Rate = *input.Rate
+	}
+	// rules price nights, so a unit let by any other period has none to apply
+	if stay.Frequency != "DAILY" {
+		return quoteBookingStay(stay, nil)
+	}
+
+	rules, err := s.repo.ListForUnit(ctx, input.Unit.PropertyID, input.Unit.ID.String())
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "QuoteStay",
+				"action":   "listing pricing rules for unit",
+			},
+		})
+	}
+
+	if input.Rate != nil {
+		rules = withoutNightlyRateRules(rules)
+	}
+
+	return quoteBookingStay(stay, rules)
+}
+
+// withoutNightlyRateRules drops the rules that set a night's rate, for a stay
+// at a rate agreed with the guest.
+func withoutNightlyRateRules(rules []models.BookingPricingRule) []models.BookingPricingRule {
+	kept := make([]models.BookingPricingRule, 0, len(rules))
+	for _, rule := range rules {
+		if rule.Type != "RATE" && rule.Type != "ADJUSTMENT" {
+			kept = append(kept, rule)
+		}
+	}
+	return kept
+}
+
+// assertUnitInProperty checks that the unit a rule is for belongs to the
+// property and is let by the day, the only units rules apply to.
+func (s *bookingPricingService) assertUnitInProperty(ctx context.Context, propertyID string, unitID *string) error {
+	if unitID == nil {
+		return nil
+	}
+
+	unit, err := s.unitService.GetUnit(ctx, repository.GetUnitQuery{PropertyID: propertyID, UnitID: *unitID})
+	if err != nil {
+		return err
+	}
+	if unit.PaymentFrequency != "DAILY" {
+		return pkg.BadRequestError("BookingPricingRuleNeedsDailyUnit", &pkg.RentLoopErrorParams{
+			Err: errors.New("pricing rules only apply to units let by the day"),
+			Metadata: map[string]string{
+				"function":          "assertUnitInProperty",
+				"payment_frequency": unit.PaymentFrequency,
+			},
+		})
+	}
+
+	return nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"math"
 	"strings"
 	"time"
 
@@ -33,38 +32,76 @@ type BookingService interface {
 	CheckInBooking(ctx context.Context, id string, clientUserID string) (*models.Booking, error)
 	CompleteBooking(ctx context.Context, id string, clientUserID string) (*models.Booking, error)
 	CancelBooking(ctx context.Context, input CancelBookingInput) (*models.Booking, error)
+	// MarkBookingRefundPaid records that a cancelled booking's refund has
+	// been sent back to the guest.
+	MarkBookingRefundPaid(ctx context.Context, input MarkBookingRefundPaidInput) (*models.BookingRefund, error)
 	GetBookingByTrackingCode(ctx context.Context, trackingCode string) (*models.Booking, error)
+	// ModifyBooking changes a booking's dates or unit, reprices the change and
+	// records it in the booking's modification history.
+	ModifyBooking(ctx context.Context, input ModifyBookingInput) (*models.BookingModification, error)
+	ListBookingModifications(
+		ctx context.Context,
+		bookingID string,
+		populate *[]string,
+	) ([]models.BookingModification, error)
+	// SetBookingGuests replaces the guests registered on a booking for
+	// check-in.
+	SetBookingGuests(ctx context.Context, input SetBookingGuestsInput) ([]models.BookingGuest, error)
+	// ExtendBookingHold moves a held booking's payment deadline later.
+	ExtendBookingHold(ctx context.Context, input ExtendBookingHoldInput) (*models.Booking, error)
+	// RemindBookingHold and ExpireBookingHold run from the queue for the hold
+	// that expires at expiresAt, and do nothing for a hold since moved.
+	RemindBookingHold(ctx context.Context, bookingID string, expiresAt time.Time) error
+	ExpireBookingHold(ctx context.Context, bookingID string, expiresAt time.Time) error
 }
 
 type bookingService struct {
-	appCtx               pkg.AppContext
-	repo                 repository.BookingRepository
-	unitDateBlockService UnitDateBlockService
-	unitDateBlockRepo    repository.UnitDateBlockRepository
-	tenantService        TenantService
-	invoiceService       InvoiceService
-	unitService          UnitService
+	appCtx                  pkg.AppContext
+	repo                    repository.BookingRepository
+	bookingRefundRepo       repository.BookingRefundRepository
+	bookingModificationRepo repository.BookingModificationRepository
+	bookingGuestRepo        repository.BookingGuestRepository
+	unitDateBlockService    UnitDateBlockService
+	unitDateBlockRepo       repository.UnitDateBlockRepository
+	tenantService           TenantService
+	invoiceService          InvoiceService
+	unitService             UnitService
+	pricingService          BookingPricingService
+	turnoverTaskService     TurnoverTaskService
+	queue                   RentloopQueue
 }
 
 type BookingServiceDeps struct {
-	AppCtx               pkg.AppContext
-	Repo                 repository.BookingRepository
-	UnitDateBlockService UnitDateBlockService
-	UnitDateBlockRepo    repository.UnitDateBlockRepository
-	TenantService        TenantService
-	InvoiceService       InvoiceService
-	UnitService          UnitService
+	AppCtx                  pkg.AppContext
+	Repo                    repository.BookingRepository
+	BookingRefundRepo       repository.BookingRefundRepository
+	BookingModificationRepo repository.BookingModificationRepository
+	BookingGuestRepo        repository.BookingGuestRepository
+	UnitDateBlockService    UnitDateBlockService
+	UnitDateBlockRepo       repository.UnitDateBlockRepository
+	TenantService           TenantService
+	InvoiceService          InvoiceService
+	UnitService             UnitService
+	PricingService          BookingPricingService
+	TurnoverTaskService     TurnoverTaskService
+	RentloopQueue           RentloopQueue
 }
 
 func NewBookingService(deps BookingServiceDeps) BookingService {
 	return &bookingService{
-		appCtx:               deps.AppCtx,
-		repo:                 deps.Repo,
-		unitDateBlockService: deps.UnitDateBlockService,
-		unitDateBlockRepo:    deps.UnitDateBlockRepo,
-		tenantService:        deps.TenantService,
-		invoiceService:       deps.InvoiceService,
-		unitService:          deps.UnitService,
+		appCtx:                  deps.AppCtx,
+		repo:                    deps.Repo,
+		bookingRefundRepo:       deps.BookingRefundRepo,
+		bookingModificationRepo: deps.BookingModificationRepo,
+		bookingGuestRepo:        deps.BookingGuestRepo,
+		unitDateBlockService:    deps.UnitDateBlockService,
+		unitDateBlockRepo:       deps.UnitDateBlockRepo,
+		tenantService:           deps.TenantService,
+		invoiceService:          deps.InvoiceService,
+		unitService:             deps.UnitService,
+		pricingService:          deps.PricingService,
+		turnoverTaskService:     deps.TurnoverTaskService,
+		queue:                   deps.RentloopQueue,
 	}
 }
 
@@ -73,12 +110,15 @@ type CreateBookingInput struct {
 	PropertyID            string
 	CheckInDate           time.Time
 	CheckOutDate          time.Time
-	Rate                  int64
+	Rate                  *int64 // agreed rate per period, charged FLAT; nil prices the stay by the unit's rules
 	Currency              string
 	StayFrequency         string
 	BookingSource         string // MANAGER | GUEST_LINK
 	CreatedByClientUserID *string
 	Notes                 string
+	Adults                int64 // 0 is one adult
+	Children              int64
+	Guests                []BookingGuestInput // optional; who is staying, registered for check-in
 	// Guest info
 	GuestFirstName string
 	GuestLastName  string
@@ -105,6 +145,11 @@ func (s *bookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		return nil, errors.New("check_out_date must be after check_in_date")
 	}
 
+	adults := max(input.Adults, 1)
+	if err := validateBookingGuests(adults, input.Children, input.Guests); err != nil {
+		return nil, err
+	}
+
 	unit, unitErr := s.unitService.GetUnit(ctx, repository.GetUnitQuery{
 		PropertyID: input.PropertyID,
 		UnitID:     input.UnitID,
@@ -115,6 +160,22 @@ func (s *bookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		return nil, unitErr
 	}
 
+	quote, quoteErr := s.pricingService.QuoteStay(ctx, QuoteBookingStayInput{
+		Unit:         unit,
+		CheckInDate:  input.CheckInDate,
+		CheckOutDate: input.CheckOutDate,
+		Rate:         input.Rate,
+		Guests:       adults + input.Children,
+	})
+	if quoteErr != nil {
+		return nil, quoteErr
+	}
+
+	pricingMethod := "FLAT"
+	if input.Rate == nil && unit.PaymentFrequency == "DAILY" {
+		pricingMethod = "RULES"
+	}
+
 	tenant, err := s.tenantService.FindOrCreateLightTenant(ctx, FindOrCreateLightTenantInput{
 		FirstName:   input.GuestFirstName,
 		LastName:    input.GuestLastName,
@@ -138,36 +199,22 @@ func (s *bookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		TenantID:              tenant.ID.String(),
 		CheckInDate:           input.CheckInDate,
 		CheckOutDate:          input.CheckOutDate,
+		Adults:                adults,
+		Children:              input.Children,
 		StayFrequency:         input.StayFrequency,
+		PricingMethod:         pricingMethod,
+		CancellationPolicy:    unit.Property.BookingCancellationPolicy,
 		Status:                "PENDING",
 		BookingSource:         input.BookingSource,
 		CreatedByClientUserID: input.CreatedByClientUserID,
 		Notes:                 input.Notes,
+		Guests:                bookingGuestModels("", input.Guests),
 	}
-
-	// calculate the rate based on the unit's rent fee and the length of stay based on frequency: WEEKLY | DAILY | MONTHLY | HOURLY
-	hours := input.CheckOutDate.Sub(input.CheckInDate).Hours()
-
-	totalRate := int64(0)
-	quantity := int64(0)
-	paymentFrequency := "nights"
-	switch unit.PaymentFrequency {
-	case "HOURLY":
-		quantity = int64(math.Ceil(hours))
-		totalRate = quantity * input.Rate
-		paymentFrequency = "hours"
-	case "DAILY":
-		quantity = int64(math.Ceil(hours / 24))
-		totalRate = quantity * input.Rate
-		paymentFrequency = "nights"
-	case "WEEKLY":
-		quantity = int64(math.Ceil(hours / (24 * 7)))
-		totalRate = quantity * input.Rate
-		paymentFrequency = "weeks"
-	case "MONTHLY":
-		quantity = int64(math.Ceil(hours / (24 * 30)))
-		totalRate = quantity * input.Rate
-		paymentFrequency = "months"
+	// A property taking payment upfront holds the booking only until the
+	// guest has had time to pay.
+	if unit.Property.BookingRequiresUpfrontPayment {
+		booking.RequiresUpfrontPayment = true
+		booking.HoldExpiresAt = bookingHoldExpiry(time.Now(), unit.Property.BookingHoldHours)
 	}
 
 	if err := s.repo.Create(transCtx, booking); err != nil {
@@ -185,6 +232,11 @@ func (s *bookingService) CreateBooking(ctx context.Context, input CreateBookingI
 	propertyID := unit.PropertyID
 	bookingID := booking.ID.String()
 
+	lineItems := []LineItemInput{bookingFeeLine(unit.Name, quote)}
+	if quote.ExtraGuestTotal > 0 {
+		lineItems = append(lineItems, bookingExtraGuestLine(quote))
+	}
+
 	_, invoiceErr := s.invoiceService.CreateInvoice(transCtx, CreateInvoiceInput{
 		ClientID:         &clientID,
 		PropertyID:       &propertyID,
@@ -193,21 +245,12 @@ func (s *bookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		PayeeClientID:    &clientID,
 		ContextType:      "BOOKING_FEE",
 		ContextBookingID: &bookingID,
-		TotalAmount:      totalRate,
-		SubTotal:         totalRate,
+		TotalAmount:      quote.Total,
+		SubTotal:         quote.Total,
 		Currency:         input.Currency,
 		Status:           "DRAFT",
 		DueDate:          nil,
-		LineItems: []LineItemInput{
-			{
-				Label:       fmt.Sprintf("Booking for %s for %d %s", unit.Name, quantity, paymentFrequency),
-				Category:    "BOOKING_FEE",
-				Quantity:    quantity,
-				UnitAmount:  input.Rate,
-				TotalAmount: totalRate,
-				Currency:    input.Currency,
-			},
-		},
+		LineItems:        lineItems,
 	})
 
 	if invoiceErr != nil {
@@ -240,7 +283,8 @@ func (s *bookingService) CreateBooking(ctx context.Context, input CreateBookingI
 		})
 	}
 
-	go s.sendBookingCreatedNotification(*bookingReloaded, totalRate, input.Currency)
+	s.scheduleBookingHold(ctx, booking)
+	go s.sendBookingCreatedNotification(*bookingReloaded, quote.Total, input.Currency)
 
 	return booking, nil
 }
@@ -252,13 +296,15 @@ type UpdateBookingInput struct {
 	RequiresUpfrontPayment lib.Optional[bool]
 	CheckInDate            lib.Optional[time.Time]
 	CheckOutDate           lib.Optional[time.Time]
+	Adults                 lib.Optional[int64]
+	Children               lib.Optional[int64]
 	Meta                   lib.Optional[datatypes.JSON]
 }
 
 func (s *bookingService) UpdateBooking(ctx context.Context, input UpdateBookingInput) (*models.Booking, error) {
 	booking, err := s.repo.GetByIDWithPopulate(
 		ctx,
-		repository.GetBookingQuery{ID: input.BookingID, Populate: &[]string{"Invoice"}},
+		repository.GetBookingQuery{ID: input.BookingID, Populate: &[]string{"Invoice", "Property"}},
 	)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -287,6 +333,36 @@ func (s *bookingService) UpdateBooking(ctx context.Context, input UpdateBookingI
 			},
 		)
 	}
+	// So are guest counts, which the stay is priced by.
+	guestsChanged := input.Adults.IsSet || input.Children.IsSet
+	if guestsChanged && booking.Status != "PENDING" {
+		return nil, pkg.BadRequestError(
+			"guest counts can only be changed before the booking is confirmed",
+			&pkg.RentLoopErrorParams{
+				Err:      errors.New("booking already confirmed"),
+				Metadata: map[string]string{"function": "UpdateBooking"},
+			},
+		)
+	}
+	if guestsChanged {
+		if input.Adults.IsSet {
+			booking.Adults = *input.Adults.Value
+		}
+		if input.Children.IsSet {
+			booking.Children = *input.Children.Value
+		}
+
+		registered, err := s.bookingGuestRepo.ListByBookingID(ctx, booking.ID.String())
+		if err != nil {
+			return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err:      err,
+				Metadata: map[string]string{"function": "UpdateBooking", "action": "listing booking guests"},
+			})
+		}
+		if err := validateBookingGuests(booking.Adults, booking.Children, bookingGuestInputs(registered)); err != nil {
+			return nil, err
+		}
+	}
 
 	transaction := s.appCtx.DB.Begin()
 	transCtx := lib.WithTransaction(ctx, transaction)
@@ -294,8 +370,17 @@ func (s *bookingService) UpdateBooking(ctx context.Context, input UpdateBookingI
 	if input.Notes.IsSet {
 		booking.Notes = *input.Notes.Value
 	}
+	// Asking for payment upfront on a pending booking puts it on hold with
+	// the property's window; no longer asking lifts the hold.
+	holdStarted := false
 	if input.RequiresUpfrontPayment.IsSet {
 		booking.RequiresUpfrontPayment = *input.RequiresUpfrontPayment.Value
+		if !booking.RequiresUpfrontPayment {
+			booking.HoldExpiresAt = nil
+		} else if booking.Status == "PENDING" && booking.HoldExpiresAt == nil {
+			booking.HoldExpiresAt = bookingHoldExpiry(time.Now(), booking.Property.BookingHoldHours)
+			holdStarted = booking.HoldExpiresAt != nil
+		}
 	}
 	if input.Meta.IsSet {
 		booking.Meta = *input.Meta.Value
@@ -308,7 +393,7 @@ func (s *bookingService) UpdateBooking(ctx context.Context, input UpdateBookingI
 		booking.CheckOutDate = *input.CheckOutDate.Value
 	}
 
-	if input.CheckInDate.IsSet || input.CheckOutDate.IsSet {
+	if input.CheckInDate.IsSet || input.CheckOutDate.IsSet || guestsChanged {
 		if !booking.CheckOutDate.After(booking.CheckInDate) {
 			transaction.Rollback()
 			return nil, pkg.BadRequestError("check_out_date must be after check_in_date", &pkg.RentLoopErrorParams{
@@ -316,7 +401,7 @@ func (s *bookingService) UpdateBooking(ctx context.Context, input UpdateBookingI
 				Metadata: map[string]string{"function": "UpdateBooking"},
 			})
 		}
-		if err := s.recalculateBookingInvoice(transCtx, booking); err != nil {
+		if _, err := s.recalculateBookingInvoice(transCtx, booking); err != nil {
 			transaction.Rollback()
 			return nil, err
 		}
@@ -337,40 +422,33 @@ func (s *bookingService) UpdateBooking(ctx context.Context, input UpdateBookingI
 		})
 	}
 
+	if holdStarted {
+		s.scheduleBookingHold(ctx, booking)
+	}
+
 	return booking, nil
 }
 
 // recalculateBookingInvoice recomputes the BOOKING_FEE line item totals based on
-// the current dates. The per-unit rate is read from the existing line item so no
-// rate field is needed on the booking model. Must be called within a transaction context.
-func (s *bookingService) recalculateBookingInvoice(ctx context.Context, booking *models.Booking) error {
+// the current dates, unit and guests, and returns the quote it set. A RULES
+// booking is quoted again from the unit's pricing rules; a FLAT one keeps the
+// per-unit rate read from the existing line item so no rate field is needed on
+// the booking model. Extra guests are billed on a line of their own. Must be
+// called within a transaction context.
+func (s *bookingService) recalculateBookingInvoice(
+	ctx context.Context,
+	booking *models.Booking,
+) (*BookingQuote, error) {
 	if booking.Invoice == nil || booking.Invoice.ID.String() == "" {
-		return pkg.InternalServerError("booking invoice not loaded", &pkg.RentLoopErrorParams{
+		return nil, pkg.InternalServerError("booking invoice not loaded", &pkg.RentLoopErrorParams{
 			Err:      errors.New("invoice missing from booking"),
 			Metadata: map[string]string{"function": "recalculateBookingInvoice"},
 		})
 	}
 
-	lineItems, lineItemsErr := s.invoiceService.GetLineItems(ctx, booking.Invoice.ID.String())
-	if lineItemsErr != nil {
-		return pkg.InternalServerError(lineItemsErr.Error(), &pkg.RentLoopErrorParams{
-			Err:      lineItemsErr,
-			Metadata: map[string]string{"function": "recalculateBookingInvoice", "action": "fetching line items"},
-		})
-	}
-
-	var bookingLineItem *models.InvoiceLineItem
-	for i := range lineItems {
-		if lineItems[i].Category == "BOOKING_FEE" {
-			bookingLineItem = &lineItems[i]
-			break
-		}
-	}
-	if bookingLineItem == nil {
-		return pkg.NotFoundError("BookingInvoiceLineItemNotFound", &pkg.RentLoopErrorParams{
-			Err:      errors.New("booking invoice line item not found"),
-			Metadata: map[string]string{"function": "recalculateBookingInvoice"},
-		})
+	bookingLineItem, lineItemErr := s.bookingFeeLineItem(ctx, booking.Invoice.ID.String())
+	if lineItemErr != nil {
+		return nil, lineItemErr
 	}
 
 	unit, unitErr := s.unitService.GetUnit(ctx, repository.GetUnitQuery{
@@ -378,47 +456,111 @@ func (s *bookingService) recalculateBookingInvoice(ctx context.Context, booking
 		UnitID:     booking.UnitID,
 	})
 	if unitErr != nil {
-		return unitErr
-	}
-
-	unitRate := bookingLineItem.UnitAmount
-	hours := booking.CheckOutDate.Sub(booking.CheckInDate).Hours()
-	totalRate := int64(0)
-	quantity := int64(0)
-	paymentFrequency := "nights"
-	switch unit.PaymentFrequency {
-	case "HOURLY":
-		quantity = int64(math.Ceil(hours))
-		totalRate = quantity * unitRate
-		paymentFrequency = "hours"
-	case "DAILY":
-		quantity = int64(math.Ceil(hours / 24))
-		totalRate = quantity * unitRate
-		paymentFrequency = "nights"
-	case "WEEKLY":
-		quantity = int64(math.Ceil(hours / (24 * 7)))
-		totalRate = quantity * unitRate
-		paymentFrequency = "weeks"
-	case "MONTHLY":
-		quantity = int64(math.Ceil(hours / (24 * 30)))
-		totalRate = quantity * unitRate
-		paymentFrequency = "months"
-	}
-
-	label := fmt.Sprintf("Booking for %s for %d %s", unit.Name, quantity, paymentFrequency)
+		return nil, unitErr
+	}
+
+	quote, quoteErr := s.pricingService.QuoteStay(
+		ctx,
+		bookingStayQuoteInput(booking, unit, bookingLineItem, booking.CheckInDate, booking.CheckOutDate),
+	)
+	if quoteErr != nil {
+		return nil, quoteErr
+	}
+
+	line := bookingFeeLine(unit.Name, quote)
 	if _, updateErr := s.invoiceService.UpdateLineItem(ctx, UpdateLineItemInput{
 		InvoiceID:   booking.Invoice.ID.String(),
 		LineItemID:  bookingLineItem.ID.String(),
-		Label:       &label,
-		Quantity:    &quantity,
-		UnitAmount:  &unitRate,
-		TotalAmount: &totalRate,
+		Label:       &line.Label,
+		Quantity:    &line.Quantity,
+		UnitAmount:  &line.UnitAmount,
+		TotalAmount: &line.TotalAmount,
 		Currency:    &bookingLineItem.Currency,
+		Metadata:    line.Metadata,
 	}); updateErr != nil {
-		return updateErr
+		return nil, updateErr
 	}
 
-	return nil
+	if err := s.syncBookingExtraGuestLine(ctx, booking.Invoice.ID.String(), quote); err != nil {
+		return nil, err
+	}
+
+	return quote, nil
+}
+
+// bookingFeeLineItem is the BOOKING_FEE line on a booking's invoice.
+func (s *bookingService) bookingFeeLineItem(ctx context.Context, invoiceID string) (*models.InvoiceLineItem, error) {
+	lineItems, lineItemsErr := s.invoiceService.GetLineItems(ctx, invoiceID)
+	if lineItemsErr != nil {
+		return nil, pkg.InternalServerError(lineItemsErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      lineItemsErr,
+			Metadata: map[string]string{"function": "bookingFeeLineItem", "action": "fetching line items"},
+		})
+	}
+
+	for i := range lineItems {
+		if lineItems[i].Category == "BOOKING_FEE" {
+			return &lineItems[i], nil
+		}
+	}
+
+	return nil, pkg.NotFoundError("BookingInvoiceLineItemNotFound", &pkg.RentLoopErrorParams{
+		Err:      errors.New("booking invoice line item not found"),
+		Metadata: map[string]string{"function": "bookingFeeLineItem"},
+	})
+}
+
+// bookingStayQuoteInput prices booking's stay in unit from checkIn to
+// checkOut the way the booking is priced: by the unit's rules, or at the
+// FLAT rate on its invoice line.
+func bookingStayQuoteInput(
+	booking *models.Booking,
+	unit *models.Unit,
+	feeLine *models.InvoiceLineItem,
+	checkIn, checkOut time.Time,
+) QuoteBookingStayInput {
+	input := QuoteBookingStayInput{
+		Unit:         unit,
+		CheckInDate:  checkIn,
+		CheckOutDate: checkOut,
+		Guests:       booking.Adults + booking.Children,
+	}
+	if booking.PricingMethod != "RULES" {
+		input.Rate = &feeLine.UnitAmount
+	}
+	return input
+}
+
+// bookingFeeLine is the BOOKING_FEE invoice line for quote. A stay charged one
+// rate throughout is that rate times its periods. One whose nights are priced
+// differently, or that earns a discount, is a single line for the total with
+// its nights in the metadata.
+func bookingFeeLine(unitName string, quote *BookingQuote) LineItemInput {
+	line := LineItemInput{
+		Label:       fmt.Sprintf("Booking for %s for %d %s", unitName, quote.Quantity, quote.PeriodLabel),
+		Category:    "BOOKING_FEE",
+		Quantity:    quote.Quantity,
+		TotalAmount: quote.stayTotal(),
+		Currency:    quote.Currency,
+	}
+	if rate, uniform := quote.uniformRate(); uniform {
+		line.UnitAmount = rate
+		return line
+	}
+
+	nights := make([]map[string]any, 0, len(quote.Nights))
+	for _, night := range quote.Nights {
+		nights = append(nights, map[string]any{"date": night.Date.Format(time.DateOnly), "rate": night.Rate})
+	}
+	line.Quantity = 1
+	line.UnitAmount = quote.stayTotal()
+	line.Metadata = &map[string]any{
+		"nights":           nights,
+		"sub_total":        quote.SubTotal,
+		"discount_percent": quote.DiscountPercent,
+		"discount":         quote.Discount,
+	}
+	return line
 }
 
 func (s *bookingService) ConfirmBooking(ctx context.Context, input ConfirmBookingInput) (*models.Booking, error) {
@@ -499,6 +641,7 @@ func (s *bookingService) ConfirmBooking(ctx context.Context, input ConfirmBookin
 	booking.CheckInCode = checkInCode
 	booking.ConfirmedAt = &now
 	booking.ConfirmedByID = &input.ClientUserID
+	booking.HoldExpiresAt = nil
 	if err := s.repo.Update(transCtx, booking); err != nil {
 		tx.Rollback()
 		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
@@ -546,7 +689,8 @@ func (s *bookingService) ConfirmBooking(ctx context.Context, input ConfirmBookin
 
 func (s *bookingService) CheckInBooking(ctx context.Context, id string, clientUserID string) (*models.Booking, error) {
 	booking, err := s.repo.GetByIDWithPopulate(ctx, repository.GetBookingQuery{
-		ID: id,
+		ID:       id,
+		Populate: &[]string{"Property"},
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -568,6 +712,10 @@ func (s *bookingService) CheckInBooking(ctx context.Context, id string, clientUs
 		})
 	}
 
+	if err := s.assertBookingGuestsRegistered(ctx, booking); err != nil {
+		return nil, err
+	}
+
 	booking.Status = "CHECKED_IN"
 	now := time.Now()
 	booking.CheckedInAt = &now
@@ -614,7 +762,11 @@ func (s *bookingService) CompleteBooking(ctx context.Context, id string, clientU
 	booking.CheckedOutAt = &now
 	booking.CheckedOutByID = &clientUserID
 
-	if err := s.repo.Update(ctx, booking); err != nil {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.repo.Update(transCtx, booking); err != nil {
+		transaction.Rollback()
 		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
 			Err: err,
 			Metadata: map[string]string{
@@ -624,13 +776,35 @@ func (s *bookingService) CompleteBooking(ctx context.Context, id string, clientU
 		})
 	}
 
+	// Turn the unit over inside the same transaction — if this fails, the
+	// checkout rolls back and the booking stays CHECKED_IN to be completed
+	// again, rather than COMPLETED with a unit nobody was asked to ready.
+	if err := s.turnoverTaskService.GenerateForBooking(transCtx, booking); err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	if err := transaction.Commit().Error; err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CompleteBooking",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
 	return booking, nil
 }
 
+// CancelBooking cancels a booking and settles its invoices under the booking's
+// cancellation policy. An invoice nothing was paid against is voided; on one
+// with money against it the payments stand and the unpaid rest is written
+// off, and what the guest is owed back is recorded as a BookingRefund.
 func (s *bookingService) CancelBooking(ctx context.Context, input CancelBookingInput) (*models.Booking, error) {
 	booking, err := s.repo.GetByIDWithPopulate(ctx, repository.GetBookingQuery{
 		ID:       input.BookingID,
-		Populate: &[]string{"Tenant"},
+		Populate: &[]string{"Tenant", "Unit", "Invoice", "Invoice.Payments", "SupplementaryInvoices.Payments"},
 	})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -643,7 +817,7 @@ func (s *bookingService) CancelBooking(ctx context.Context, input CancelBookingI
 		})
 	}
 
-	if booking.Status == "COMPLETED" || booking.Status == "CHECKED_IN" {
+	if booking.Status != "PENDING" && booking.Status != "CONFIRMED" {
 		return nil, pkg.BadRequestError("only PENDING or CONFIRMED bookings can be cancelled", &pkg.RentLoopErrorParams{
 			Err: errors.New("booking is in invalid status for cancellation"),
 			Metadata: map[string]string{
@@ -653,15 +827,86 @@ func (s *bookingService) CancelBooking(ctx context.Context, input CancelBookingI
 		})
 	}
 
-	booking.Status = "CANCELLED"
-	booking.CancellationReason = input.CancellationReason
+	return s.cancelBooking(ctx, booking, input.CancellationReason, &input.ClientUserID)
+}
+
+// cancelBooking cancels booking, which needs its Tenant, Unit, Invoice,
+// Invoice.Payments and SupplementaryInvoices.Payments loaded. canceledByID is
+// nil when the system cancels it.
+func (s *bookingService) cancelBooking(
+	ctx context.Context,
+	booking *models.Booking,
+	reason string,
+	canceledByID *string,
+) (*models.Booking, error) {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.recordBookingCancellation(transCtx, booking, reason, canceledByID); err != nil {
+		transaction.Rollback()
+		return nil, err
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err: commitErr,
+			Metadata: map[string]string{
+				"function": "CancelBooking",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	return s.settleCancelledBooking(ctx, booking, reason, canceledByID), nil
+}
+
+// recordBookingCancellation marks booking CANCELLED and records the refund
+// its policy owes the guest, in ctx's transaction.
+func (s *bookingService) recordBookingCancellation(
+	ctx context.Context,
+	booking *models.Booking,
+	reason string,
+	canceledByID *string,
+) error {
+	// Credits for changes to the stay are refunded on their own, so they come
+	// off both the price and what was paid before the policy is applied.
+	modificationRefunds, err := s.bookingRefundRepo.ListForModifications(ctx, booking.ID.String())
+	if err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CancelBooking",
+				"action":   "listing booking modification refunds",
+			},
+		})
+	}
+	var credits int64
+	for _, modificationRefund := range modificationRefunds {
+		credits += modificationRefund.Amount
+	}
 
 	now := time.Now()
+	booking.Status = "CANCELLED"
+	booking.CancellationReason = reason
 	booking.CanceledAt = &now
-	booking.CanceledByID = &input.ClientUserID
+	booking.CanceledByID = canceledByID
+	booking.HoldExpiresAt = nil
+
+	var refund *bookingRefundQuote
+	if booking.Invoice != nil {
+		total, paid := bookingTotalAndPaid(booking, credits)
+		quote := quoteBookingRefund(
+			GetBookingCancellationPolicy(booking.CancellationPolicy),
+			total,
+			paid,
+			booking.CheckInDate,
+			now,
+		)
+		refund = &quote
+	}
 
 	if err := s.repo.Update(ctx, booking); err != nil {
-		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
 			Err: err,
 			Metadata: map[string]string{
 				"function": "CancelBooking",
@@ -670,10 +915,162 @@ func (s *bookingService) CancelBooking(ctx context.Context, input CancelBookingI
 		})
 	}
 
+	if refund != nil && refund.Amount > 0 {
+		booking.Refund = &models.BookingRefund{
+			BookingID:          booking.ID.String(),
+			InvoiceID:          booking.Invoice.ID.String(),
+			CancellationPolicy: booking.CancellationPolicy,
+			DaysBeforeCheckIn:  refund.DaysBeforeCheckIn,
+			RefundPercent:      refund.RefundPercent,
+			AmountPaid:         refund.AmountPaid,
+			Amount:             refund.Amount,
+			Currency:           booking.Invoice.Currency,
+			Status:             "PENDING",
+		}
+		if err := s.bookingRefundRepo.Create(ctx, booking.Refund); err != nil {
+			return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err: err,
+				Metadata: map[string]string{
+					"function": "CancelBooking",
+					"action":   "creating booking refund",
+				},
+			})
+		}
+	}
+
+	return nil
+}
+
+// settleCancelledBooking closes the invoices of a booking whose cancellation
+// has committed, frees its dates and tells the guest.
+func (s *bookingService) settleCancelledBooking(
+	ctx context.Context,
+	booking *models.Booking,
+	reason string,
+	canceledByID *string,
+) *models.Booking {
+	// Closing the invoices runs in transactions of their own. The booking is
+	// cancelled either way; an invoice left open here can be closed by hand.
+	if booking.Invoice != nil {
+		booking.Invoice = s.closeCancelledBookingInvoice(ctx, booking, booking.Invoice, canceledByID)
+	}
+	for i := range booking.SupplementaryInvoices {
+		booking.SupplementaryInvoices[i] = *s.closeCancelledBookingInvoice(
+			ctx, booking, &booking.SupplementaryInvoices[i], canceledByID,
+		)
+	}
+
 	go s.removeBookingDateBlock(context.Background(), booking.ID.String())
-	go s.sendBookingCancelledNotification(*booking, input.CancellationReason)
+	go s.sendBookingCancelledNotification(*booking, reason)
 
-	return booking, nil
+	return booking
+}
+
+// closeCancelledBookingInvoice stops a cancelled booking's invoice asking for
+// money. One nothing was paid against is voided; on one partly paid, the
+// payments stand and the unpaid rest is written off, since the refund has
+// already settled what the guest owes. invoice needs its Payments loaded.
+func (s *bookingService) closeCancelledBookingInvoice(
+	ctx context.Context,
+	booking *models.Booking,
+	invoice *models.Invoice,
+	clientUserID *string,
+) *models.Invoice {
+	reason := fmt.Sprintf("Booking #%s was cancelled", booking.Code)
+
+	var closed *models.Invoice
+	var err error
+	switch {
+	case invoice.Status == "DRAFT" || (invoice.Status == "ISSUED" && invoiceAmountPaid(invoice) == 0):
+		closed, err = s.invoiceService.VoidInvoice(ctx, VoidInvoiceInput{
+			InvoiceID:            invoice.ID.String(),
+			VoidedReason:         &reason,
+			VoidedByClientUserID: clientUserID,
+		})
+	case invoice.Status == "ISSUED" || invoice.Status == "PARTIALLY_PAID":
+		closed, err = s.invoiceService.WriteOffInvoiceBalance(ctx, WriteOffInvoiceBalanceInput{
+			InvoiceID:                invoice.ID.String(),
+			Reason:                   reason,
+			WrittenOffByClientUserID: clientUserID,
+		})
+	default:
+		return invoice
+	}
+	if err != nil {
+		log.WithError(err).
+			WithField("booking_id", booking.ID.String()).
+			WithField("invoice_id", invoice.ID.String()).
+			Error("failed to close cancelled booking invoice")
+		return invoice
+	}
+
+	return closed
+}
+
+type MarkBookingRefundPaidInput struct {
+	BookingID string
+	// RefundID picks a refund for a modification's credit; nil is the
+	// cancellation refund.
+	RefundID         *string
+	ClientUserID     string
+	PaymentReference *string
+}
+
+func (s *bookingService) MarkBookingRefundPaid(
+	ctx context.Context,
+	input MarkBookingRefundPaidInput,
+) (*models.BookingRefund, error) {
+	var refund *models.BookingRefund
+	var err error
+	if input.RefundID != nil {
+		refund, err = s.bookingRefundRepo.GetByID(ctx, *input.RefundID)
+		if err == nil && refund.BookingID != input.BookingID {
+			err = gorm.ErrRecordNotFound
+		}
+	} else {
+		refund, err = s.bookingRefundRepo.GetByBookingID(ctx, input.BookingID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("BookingRefundNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "MarkBookingRefundPaid",
+				"action":   "fetching booking refund",
+			},
+		})
+	}
+
+	if refund.Status != "PENDING" {
+		return nil, pkg.BadRequestError("BookingRefundAlreadyPaid", &pkg.RentLoopErrorParams{
+			Err: errors.New("booking refund is not PENDING"),
+			Metadata: map[string]string{
+				"function": "MarkBookingRefundPaid",
+				"action":   "validating refund status",
+			},
+		})
+	}
+
+	now := time.Now()
+	refund.Status = "PAID"
+	refund.PaidAt = &now
+	refund.PaidByClientUserID = &input.ClientUserID
+	refund.PaymentReference = input.PaymentReference
+
+	if err := s.bookingRefundRepo.Update(ctx, refund); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "MarkBookingRefundPaid",
+				"action":   "updating booking refund",
+			},
+		})
+	}
+
+	return refund, nil
 }
 
 func (s *bookingService) GetBooking(ctx context.Context, query repository.GetBookingQuery) (*models.Booking, error) {
@@ -731,7 +1128,12 @@ func (s *bookingService) GetBookingByTrackingCode(ctx context.Context, trackingC
 	booking, err := s.repo.GetByTrackingCode(
 		ctx,
 		trackingCode,
-		[]string{"Unit", "Property", "Tenant", "Invoice", "Invoice.LineItems", "Invoice.Payments"},
+		[]string{
+			"Unit", "Property", "Tenant",
+			"Invoice", "Invoice.LineItems", "Invoice.Payments",
+			"SupplementaryInvoices", "SupplementaryInvoices.LineItems", "SupplementaryInvoices.Payments",
+			"Refund",
+		},
 	)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
@@ -849,6 +1251,10 @@ func (s *bookingService) sendBookingCancelledNotification(booking models.Booking
 		CancellationReason: reason,
 		TrackingCode:       booking.Code,
 	}
+	if booking.Refund != nil {
+		emailData.RefundAmount = lib.FormatAmount(lib.PesewasToCedis(booking.Refund.Amount))
+		emailData.Currency = booking.Refund.Currency
+	}
 
 	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render("booking/cancelled", emailData)
 	if renderErr != nil {
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	ForecastIntervalWeek  = "WEEK"
+	ForecastIntervalMonth = "MONTH"
+)
+
+// MaxForecastHorizon bounds a single forecast. Beyond two years the charges
+// have mostly not been materialised yet, so the numbers would mean little.
+const MaxForecastHorizon = 2 * 366 * 24 * time.Hour
+
+type CashFlowForecastService interface {
+	Forecast(ctx context.Context, input CashFlowForecastInput) (*CashFlowForecast, error)
+}
+
+type cashFlowForecastService struct {
+	repo             repository.CashFlowForecastRepository
+	clientRepo       repository.ClientRepository
+	exchangeRateRepo repository.ExchangeRateRepository
+}
+
+type CashFlowForecastServiceDeps struct {
+	Repo             repository.CashFlowForecastRepository
+	ClientRepo       repository.ClientRepository
+	ExchangeRateRepo repository.ExchangeRateRepository
+}
+
+func NewCashFlowForecastService(deps CashFlowForecastServiceDeps) CashFlowForecastService {
+	return &cashFlowForecastService{
+		repo:             deps.Repo,
+		clientRepo:       deps.ClientRepo,
+		exchangeRateRepo: deps.ExchangeRateRepo,
+	}
+}
+
+type CashFlowForecastInput struct {
+	Scope     repository.CashFlowForecastScope
+	Interval  string
+	StartDate time.Time
+	EndDate   time.Time
+	// AsOf is when punctuality is measured and rates are read. Handlers pass
+	// time.Now().
+	AsOf time.Time
+}
+
+// CashFlowForecastBucket is one week or month. Every amount is in the
+// forecast's reporting currency, in minor units.
+type CashFlowForecastBucket struct {
+	StartDate         time.Time
+	EndDate           time.Time
+	ExpectedInflow    int64 // everything falling due, as if every tenant paid
+	WeightedInflow    int64 // each charge scaled by its tenant's on-time rate
+	ScheduledExpenses int64
+	NetCashFlow       int64 // WeightedInflow - ScheduledExpenses
+}
+
+type CashFlowForecast struct {
+	ReportingCurrency string
+	Interval          string
+	StartDate         time.Time
+	EndDate           time.Time
+	Buckets           []CashFlowForecastBucket
+}
+
+// Forecast projects the scope's cash position from charge state.
+//
+// Nothing is extrapolated: a charge that has not been materialised is not
+// forecast. Overdue charges land in the first bucket — they are still
+// expected, just late — and are weighted like any other.
+func (s *cashFlowForecastService) Forecast(
+	ctx context.Context,
+	input CashFlowForecastInput,
+) (*CashFlowForecast, error) {
+	if input.Interval != ForecastIntervalWeek && input.Interval != ForecastIntervalMonth {
+		return nil, pkg.BadRequestError("InvalidForecastInterval", nil)
+	}
+	if !input.EndDate.After(input.StartDate) {
+		return nil, pkg.BadRequestError("ForecastEndDateMustBeAfterStartDate", nil)
+	}
+	if input.EndDate.Sub(input.StartDate) > MaxForecastHorizon {
+		return nil, pkg.BadRequestError("ForecastHorizonTooLong", nil)
+	}
+
+	client, err := s.clientRepo.GetByID(ctx, input.Scope.ClientID)
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkg.NotFoundError("ClientNotFound", nil)
+		}
+		return nil, forecastInternalError(err, "fetching client")
+	}
+
+	charges, err := s.repo.ListPendingCharges(ctx, input.Scope, input.EndDate)
+	if err != nil {
+		return nil, forecastInternalError(err, "listing pending charges")
+	}
+
+	expenses, err := s.repo.ListScheduledExpenses(ctx, input.Scope, input.StartDate, input.EndDate)
+	if err != nil {
+		return nil, forecastInternalError(err, "listing scheduled expenses")
+	}
+
+	tenantIDs := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, charge := range charges {
+		if !seen[charge.TenantID] {
+			seen[charge.TenantID] = true
+			tenantIDs = append(tenantIDs, charge.TenantID)
+		}
+	}
+
+	punctuality, err := s.repo.ListTenantPunctuality(ctx, tenantIDs, input.AsOf)
+	if err != nil {
+		return nil, forecastInternalError(err, "measuring tenant punctuality")
+	}
+
+	rates, err := s.exchangeRateRepo.ListLatest(ctx, input.AsOf)
+	if err != nil {
+		return nil, forecastInternalError(err, "listing exchange rates")
+	}
+
+	return BuildCashFlowForecast(CashFlowForecastParams{
+		ReportingCurrency: client.Currency,
+		Interval:          input.Interval,
+		StartDate:         input.StartDate,
+		EndDate:           input.EndDate,
+		Charges:           charges,
+		Expenses:          expenses,
+		Punctuality:       punctuality,
+		Rates:             rates,
+	})
+}
+
+func forecastInternalError(err error, action string) error {
+	return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		Err: err,
+		Metadata: map[string]string{
+			"function": "Forecast",
+			"action":   action,
+		},
+	})
+}
+
+type CashFlowForecastParams struct {
+	ReportingCurrency string
+	Interval          string
+	StartDate         time.Time
+	EndDate           time.Time
+	Charges           []repository.ForecastChargeRow
+	Expenses          []repository.ForecastExpenseRow
+	Punctuality       []repository.TenantPaymentPunctuality
+	Rates             []models.ExchangeRate
+}
+
+// BuildCashFlowForecast is the arithmetic behind Forecast, kept pure so it can
+// be tested without a database.
+//
+// A tenant with no invoice history yet is weighted at 1: there is no evidence
+// they will pay late, and discounting every new lease would make a growing
+// portfolio look like a failing one.
+func BuildCashFlowForecast(params CashFlowForecastParams) (*CashFlowForecast, error) {
+	converter := newCurrencyConverter(params.ReportingCurrency, params.Rates)
+
+	onTimeRate := make(map[string]decimal.Decimal, len(params.Punctuality))
+	for _, record := range params.Punctuality {
+		if record.Due > 0 {
+			onTimeRate[record.TenantID] = decimal.NewFromInt(record.OnTime).Div(decimal.NewFromInt(record.Due))
+		}
+	}
+
+	buckets := make([]CashFlowForecastBucket, 0)
+	for start := forecastBucketStart(params.StartDate, params.Interval); start.Before(params.EndDate); {
+		end := forecastBucketEnd(start, params.Interval)
+		buckets = append(buckets, CashFlowForecastBucket{StartDate: start, EndDate: end})
+		start = end
+	}
+
+	bucketFor := func(at time.Time) int {
+		for i := range buckets {
+			if at.Before(buckets[i].EndDate) {
+				return i
+			}
+		}
+		return len(buckets) - 1
+	}
+
+	for _, charge := range params.Charges {
+		amount, convertErr := converter.convert(charge.Outstanding, charge.Currency)
+		if convertErr != nil {
+			return nil, convertErr
+		}
+
+		rate, ok := onTimeRate[charge.TenantID]
+		if !ok {
+			rate = decimal.NewFromInt(1)
+		}
+
+		bucket := &buckets[bucketFor(charge.DueDate)]
+		bucket.ExpectedInflow += amount
+		bucket.WeightedInflow += decimal.NewFromInt(amount).Mul(rate).Round(0).IntPart()
+	}
+
+	for _, expense := range params.Expenses {
+		amount, convertErr := converter.convert(expense.Amount, expense.Currency)
+		if convertErr != nil {
+			return nil, convertErr
+		}
+		buckets[bucketFor(expense.ScheduledFor)].ScheduledExpenses += amount
+	}
+
+	for i := range buckets {
+		buckets[i].NetCashFlow = buckets[i].WeightedInflow - buckets[i].ScheduledExpenses
+	}
+
+	return &CashFlowForecast{
+		ReportingCurrency: params.ReportingCurrency,
+		Interval:          params.Interval,
+		StartDate:         params.StartDate,
+		EndDate:           params.EndDate,
+		Buckets:           buckets,
+	}, nil
+}
+
+// forecastBucketStart snaps to the bucket containing t: Monday 00:00 UTC for
+// weeks, the 1st for months.
+func forecastBucketStart(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	if interval == ForecastIntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7 // days since Monday
+		return day.AddDate(0, 0, -offset)
+	}
+
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func forecastBucketEnd(start time.Time, interval string) time.Time {
+	if interval == ForecastIntervalWeek {
+		return start.AddDate(0, 0, 7)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+// currencyConverter converts through the USD-base rates the forex sync stores:
+// 1 USD buys rates[X] of X, so X → Y is amount / rates[X] * rates[Y].
+type currencyConverter struct {
+	target   string
+	usdRates map[string]decimal.Decimal
+}
+
+func newCurrencyConverter(target string, rates []models.ExchangeRate) *currencyConverter {
+	usdRates := make(map[string]decimal.Decimal, len(rates)+1)
+	usdRates["USD"] = decimal.NewFromInt(1)
+	for _, rate := range rates {
+		if rate.Rate.IsPositive() {
+			usdRates[rate.QuoteCurrency] = rate.Rate
+		}
+	}
+
+	return &currencyConverter{target: target, usdRates: usdRates}
+}
+
+func (c *currencyConverter) convert(amount int64, currency string) (int64, error) {
+	if currency == c.target || amount == 0 {
+		return amount, nil
+	}
+
+	from, fromOk := c.usdRates[currency]
+	to, toOk := c.usdRates[c.target]
+	if !fromOk || !toOk {
+		return 0, pkg.BadRequestError("ExchangeRateUnavailable", &pkg.RentLoopErrorParams{
+			Metadata: map[string]string{
+				"from": currency,
+				"to":   c.target,
+			},
+		})
+	}
+
+	return decimal.NewFromInt(amount).Div(from).Mul(to).Round(0).IntPart(), nil
+}
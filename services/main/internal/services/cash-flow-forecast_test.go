@@ -0,0 +1,119 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/shopspring/decimal"
+)
+
+func forecastDate(t *testing.T, value string) time.Time {
+	t.Helper()
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		t.Fatalf("parse %q: %v", value, err)
+	}
+	return parsed
+}
+
+// A tenant who paid half their invoices on time contributes half their rent to
+// the weighted line; the expected line still shows the full amount.
+func TestBuildCashFlowForecastWeightsByPunctuality(t *testing.T) {
+	forecast, err := BuildCashFlowForecast(CashFlowForecastParams{
+		ReportingCurrency: "GHS",
+		Interval:          ForecastIntervalMonth,
+		StartDate:         forecastDate(t, "2027-01-01"),
+		EndDate:           forecastDate(t, "2027-03-01"),
+		Charges: []repository.ForecastChargeRow{
+			{TenantID: "late", DueDate: forecastDate(t, "2027-01-05"), Outstanding: 100_000, Currency: "GHS"},
+			{TenantID: "new", DueDate: forecastDate(t, "2027-02-05"), Outstanding: 100_000, Currency: "GHS"},
+		},
+		Punctuality: []repository.TenantPaymentPunctuality{{TenantID: "late", Due: 4, OnTime: 2}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(forecast.Buckets) != 2 {
+		t.Fatalf("got %d buckets, want 2", len(forecast.Buckets))
+	}
+
+	january, february := forecast.Buckets[0], forecast.Buckets[1]
+	if january.ExpectedInflow != 100_000 || january.WeightedInflow != 50_000 {
+		t.Errorf("got january expected=%d weighted=%d, want 100000 and 50000",
+			january.ExpectedInflow, january.WeightedInflow)
+	}
+	// No history is not evidence of lateness.
+	if february.WeightedInflow != 100_000 {
+		t.Errorf("got february weighted=%d, want 100000 for a tenant with no history", february.WeightedInflow)
+	}
+}
+
+// Overdue charges are still expected money; they land in the first bucket
+// rather than falling out of the forecast.
+func TestBuildCashFlowForecastCarriesOverdueIntoFirstBucket(t *testing.T) {
+	forecast, err := BuildCashFlowForecast(CashFlowForecastParams{
+		ReportingCurrency: "GHS",
+		Interval:          ForecastIntervalWeek,
+		StartDate:         forecastDate(t, "2027-01-06"), // a Wednesday
+		EndDate:           forecastDate(t, "2027-01-20"),
+		Charges: []repository.ForecastChargeRow{
+			{TenantID: "t", DueDate: forecastDate(t, "2026-12-01"), Outstanding: 70_000, Currency: "GHS"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first := forecast.Buckets[0]
+	if !first.StartDate.Equal(forecastDate(t, "2027-01-04")) {
+		t.Errorf("got first bucket starting %s, want Monday 2027-01-04", first.StartDate)
+	}
+	if first.ExpectedInflow != 70_000 {
+		t.Errorf("got first bucket expected=%d, want the overdue 70000", first.ExpectedInflow)
+	}
+}
+
+// Foreign amounts are converted through the USD-base rates and scheduled
+// expenses come off the net.
+func TestBuildCashFlowForecastConvertsAndNetsExpenses(t *testing.T) {
+	forecast, err := BuildCashFlowForecast(CashFlowForecastParams{
+		ReportingCurrency: "GHS",
+		Interval:          ForecastIntervalMonth,
+		StartDate:         forecastDate(t, "2027-01-01"),
+		EndDate:           forecastDate(t, "2027-02-01"),
+		Charges: []repository.ForecastChargeRow{
+			{TenantID: "t", DueDate: forecastDate(t, "2027-01-10"), Outstanding: 10_000, Currency: "USD"},
+		},
+		Expenses: []repository.ForecastExpenseRow{
+			{ScheduledFor: forecastDate(t, "2027-01-20"), Amount: 30_000, Currency: "GHS"},
+		},
+		Rates: []models.ExchangeRate{{QuoteCurrency: "GHS", Rate: decimal.NewFromInt(12)}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bucket := forecast.Buckets[0]
+	if bucket.ExpectedInflow != 120_000 {
+		t.Errorf("got expected=%d, want 120000 (100 USD at 12)", bucket.ExpectedInflow)
+	}
+	if bucket.NetCashFlow != 90_000 {
+		t.Errorf("got net=%d, want 90000", bucket.NetCashFlow)
+	}
+}
+
+// A currency that has never been synced is an error, not a silent zero.
+func TestBuildCashFlowForecastRejectsUnknownCurrency(t *testing.T) {
+	_, err := BuildCashFlowForecast(CashFlowForecastParams{
+		ReportingCurrency: "GHS",
+		Interval:          ForecastIntervalMonth,
+		StartDate:         forecastDate(t, "2027-01-01"),
+		EndDate:           forecastDate(t, "2027-02-01"),
+		Charges: []repository.ForecastChargeRow{
+			{TenantID: "t", DueDate: forecastDate(t, "2027-01-10"), Outstanding: 10_000, Currency: "EUR"},
+		},
+	})
+	if err == nil {
+		t.Fatal("got no error, want ExchangeRateUnavailable")
+	}
+}
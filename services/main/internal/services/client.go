@@ -34,6 +34,11 @@ type UpdateClientInput struct {
 	IDNumber      lib.Optional[string]
 	IDExpiry      lib.Optional[string]
 	IDDocumentURL lib.Optional[string]
+	// signing settings
+	SigningOtpRequired     *bool
+	SigningOtpDocumentTags *[]string
+	// listing settings
+	PublicListingEnabled *bool
 }
 
 type ClientService interface {
@@ -203,6 +208,18 @@ func (s *clientService) UpdateClient(ctx context.Context, input UpdateClientInpu
 		client.IDDocumentURL = input.IDDocumentURL.Ptr()
 	}
 
+	if input.SigningOtpRequired != nil {
+		client.SigningOtpRequired = *input.SigningOtpRequired
+	}
+
+	if input.SigningOtpDocumentTags != nil {
+		client.SigningOtpDocumentTags = *input.SigningOtpDocumentTags
+	}
+
+	if input.PublicListingEnabled != nil {
+		client.PublicListingEnabled = *input.PublicListingEnabled
+	}
+
 	if updateErr := s.repo.Update(ctx, client); updateErr != nil {
 		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
 			Err: updateErr,
@@ -0,0 +1,481 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/documentpdf"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+// mergeFieldDateFormat matches the portal's "MMM D, YYYY".
+const mergeFieldDateFormat = "Jan 2, 2006"
+
+// mergeFieldVocabulary is every merge field the portal's editor offers. A
+// hashtag outside it is ordinary text and never blocks finalization.
+//
+// The Landlord fields are the property manager who set the tenancy up; the
+// Client fields are the company or landlord they manage it for.
+var mergeFieldVocabulary = map[string]bool{
+	"LandlordName": true, "LandlordEmail": true, "LandlordPhoneNumber": true,
+
+	"ClientName": true, "ClientAddress": true, "ClientEmail": true, "ClientPhoneNumber": true,
+	"ClientRegistrationNumber": true,
+
+	"TenantName": true, "TenantAddress": true, "TenantEmail": true, "TenantPhoneNumber": true,
+	"TenantIDType": true, "TenantIDNumber": true, "TenantDateOfBirth": true, "TenantNationality": true,
+	"TenantOccupation": true, "TenantEmployer": true,
+	"TenantEmergencyContactName": true, "TenantEmergencyContactPhone": true,
+
+	"PropertyName": true, "PropertyAddress": true, "PropertyCity": true, "PropertyRegion": true,
+	"PropertyGPSAddress": true, "UnitNumber": true, "UnitType": true,
+
+	"ApplicationCode": true, "LeaseStartDate": true, "LeaseEndDate": true, "LeaseDuration": true,
+	"MoveInDate": true, "MoveOutDate": true,
+	"RentAmount": true, "RentAmountWithCurrency": true, "RentAmountInWords": true, "RentFrequency": true,
+	"SecurityDeposit": true, "InitialDeposit": true,
+	"PaymentDueDate": true, "PaymentAccountDetails": true, "AgreementDate": true,
+
+	"TerminationCode": true, "TerminationReason": true, "NoticeDate": true, "EarlyTerminationFee": true,
+
+	"LandlordSignedOn": true, "TenantSignedOn": true,
+	"LandlordWitnessName": true, "LandlordWitnessSignedOn": true,
+	"TenantWitnessName": true, "TenantWitnessSignedOn": true,
+}
+
+// signingMergeFields only have values once the parties sign, so they are
+// expected to be unresolved when a document is finalized.
+var signingMergeFields = map[string]bool{
+	"LandlordSignedOn": true, "TenantSignedOn": true,
+	"LandlordWitnessName": true, "LandlordWitnessSignedOn": true,
+	"TenantWitnessName": true, "TenantWitnessSignedOn": true,
+}
+
+// Relations each builder reads, for loading its source.
+var (
+	leaseMergeFieldsPopulate = []string{
+		"Tenant",
+		"Unit.Property.Client.PaymentAccounts",
+		"TenantApplication.CreatedBy.User",
+	}
+	tenantApplicationMergeFieldsPopulate = []string{"DesiredUnit.Property.Client.PaymentAccounts", "CreatedBy.User"}
+)
+
+// mergeFields collects merge field values, skipping empty ones so an unset
+// value leaves its field visible rather than blank.
+type mergeFields map[string]string
+
+func (f mergeFields) set(name, value string) {
+	if value != "" {
+		f[name] = value
+	}
+}
+
+func (f mergeFields) setPtr(name string, value *string) {
+	if value != nil {
+		f.set(name, *value)
+	}
+}
+
+// setDate skips the 2099 sentinel open-ended leases carry as their move-out:
+// it is not a date anyone agreed to.
+func (f mergeFields) setDate(name string, value *time.Time) {
+	if value != nil && !value.IsZero() && value.Year() < 2099 {
+		f[name] = value.Format(mergeFieldDateFormat)
+	}
+}
+
+func (f mergeFields) setAmount(name string, pesewas *int64, currency string) {
+	if pesewas != nil && *pesewas > 0 {
+		f[name] = formatMergeFieldAmount(*pesewas, currency)
+	}
+}
+
+func (f mergeFields) setClient(client models.Client) {
+	f.set("ClientName", client.Name)
+	f.set("ClientAddress", client.Address)
+	f.setPtr("ClientEmail", client.SupportEmail)
+	f.setPtr("ClientPhoneNumber", client.SupportPhone)
+	f.setPtr("ClientRegistrationNumber", client.RegistrationNumber)
+	f.set("PaymentAccountDetails", paymentAccountDetails(client.PaymentAccounts))
+}
+
+func (f mergeFields) setProperty(property models.Property) {
+	f.set("PropertyName", property.Name)
+	f.set("PropertyAddress", property.Address)
+	f.set("PropertyCity", property.City)
+	f.set("PropertyRegion", property.Region)
+	f.setPtr("PropertyGPSAddress", property.GPSAddress)
+	f.setClient(property.Client)
+}
+
+func (f mergeFields) setLandlord(manager models.ClientUser) {
+	f.set("LandlordName", manager.User.Name)
+	f.set("LandlordEmail", manager.User.Email)
+	f.set("LandlordPhoneNumber", manager.User.PhoneNumber)
+}
+
+func (f mergeFields) setDuration(duration *int64, frequency *string) {
+	if duration != nil && *duration > 0 && frequency != nil && *frequency != "" {
+		f.set("LeaseDuration", fmt.Sprintf("%d %s", *duration, strings.ToLower(*frequency)))
+	}
+}
+
+func (f mergeFields) setRent(rentFee *int64, currency string, paymentFrequency *string) {
+	if rentFee != nil && *rentFee > 0 {
+		f.set("RentAmount", groupThousands(lib.FormatAmount(lib.PesewasToCedis(*rentFee))))
+		f.setAmount("RentAmountWithCurrency", rentFee, currency)
+		f.set("RentAmountInWords", amountInWords(*rentFee))
+	}
+	if paymentFrequency != nil {
+		f.set("RentFrequency", strings.ToLower(*paymentFrequency))
+	}
+}
+
+// setPaymentDueDate fills the day in "payable in advance on or before the
+// #PaymentDueDate of each period". Periods run from the move-in, so rent
+// falls due on its weekday for weekly rent and its day of the month for
+// anything longer.
+func (f mergeFields) setPaymentDueDate(moveIn *time.Time, paymentFrequency *string) {
+	if moveIn == nil || moveIn.IsZero() || paymentFrequency == nil || *paymentFrequency == "" {
+		return
+	}
+
+	switch strings.ToUpper(*paymentFrequency) {
+	case "DAILY":
+		f.set("PaymentDueDate", "start")
+	case "WEEKLY":
+		f.set("PaymentDueDate", moveIn.Weekday().String())
+	default:
+		f.set("PaymentDueDate", ordinalDay(moveIn.Day()))
+	}
+}
+
+// ordinalDay writes a day of the month as "1st", "22nd" or "13th".
+func ordinalDay(day int) string {
+	suffix := "th"
+	if day%100 < 11 || day%100 > 13 {
+		switch day % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", day, suffix)
+}
+
+// paymentAccountDetails lists the client's active accounts a tenant can pay
+// rent into, the default first. Card and offline accounts have nothing a
+// tenant could pay to.
+func paymentAccountDetails(accounts []models.PaymentAccount) string {
+	details := []string{}
+	for _, account := range accounts {
+		if account.OwnerType != "PROPERTY_OWNER" || account.Status != "ACTIVE" ||
+			account.Identifier == nil || *account.Identifier == "" {
+			continue
+		}
+
+		var detail string
+		switch account.Rail {
+		case "MOMO":
+			detail = fmt.Sprintf("%s Mobile Money %s", lib.SafeString(account.Provider), *account.Identifier)
+		case "BANK_TRANSFER":
+			detail = fmt.Sprintf("Bank account %s", *account.Identifier)
+		default:
+			continue
+		}
+
+		if account.IsDefault {
+			details = append([]string{strings.TrimSpace(detail)}, details...)
+		} else {
+			details = append(details, strings.TrimSpace(detail))
+		}
+	}
+
+	return strings.Join(details, "; ")
+}
+
+var (
+	onesInWords = []string{
+		"", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten",
+		"eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen", "seventeen", "eighteen", "nineteen",
+	}
+	tensInWords = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+)
+
+// amountInWords spells an amount out as the portal does, e.g. "four thousand
+// five hundred" or "twelve and fifty pesewas".
+func amountInWords(pesewas int64) string {
+	if pesewas == 0 {
+		return "zero"
+	}
+
+	words := integerInWords(pesewas / 100)
+	if fraction := pesewas % 100; fraction > 0 {
+		words += fmt.Sprintf(" and %s pesewas", integerInWords(fraction))
+	}
+	return strings.TrimSpace(words)
+}
+
+func integerInWords(n int64) string {
+	switch {
+	case n == 0:
+		return ""
+	case n < 20:
+		return onesInWords[n]
+	case n < 100:
+		if n%10 == 0 {
+			return tensInWords[n/10]
+		}
+		return tensInWords[n/10] + "-" + onesInWords[n%10]
+	case n < 1000:
+		if n%100 == 0 {
+			return onesInWords[n/100] + " hundred"
+		}
+		return onesInWords[n/100] + " hundred and " + integerInWords(n%100)
+	}
+
+	for _, scale := range []struct {
+		size int64
+		name string
+	}{{1_000_000_000, "billion"}, {1_000_000, "million"}, {1000, "thousand"}} {
+		if n < scale.size {
+			continue
+		}
+		words := integerInWords(n/scale.size) + " " + scale.name
+		switch remainder := n % scale.size; {
+		case remainder == 0:
+		case remainder < 100:
+			words += " and " + integerInWords(remainder)
+		default:
+			words += " " + integerInWords(remainder)
+		}
+		return words
+	}
+	return ""
+}
+
+// formatMergeFieldAmount writes an amount the way a contract states it, e.g.
+// "GHS 4,500.00". The currency code stands in for the portal's symbol, which
+// the PDF's fonts cannot all draw.
+func formatMergeFieldAmount(pesewas int64, currency string) string {
+	return fmt.Sprintf("%s %s", currency, groupThousands(lib.FormatAmount(lib.PesewasToCedis(pesewas))))
+}
+
+// groupThousands adds thousands separators to a formatted amount.
+func groupThousands(amount string) string {
+	whole, fraction, _ := strings.Cut(amount, ".")
+	sign := ""
+	if strings.HasPrefix(whole, "-") {
+		sign, whole = "-", whole[1:]
+	}
+
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+
+	if fraction == "" {
+		return sign + grouped.String()
+	}
+	return sign + grouped.String() + "." + fraction
+}
+
+func tenantFullName(tenant models.Tenant) string {
+	names := []string{tenant.FirstName}
+	if tenant.OtherNames != nil && *tenant.OtherNames != "" {
+		names = append(names, *tenant.OtherNames)
+	}
+	names = append(names, tenant.LastName)
+	return strings.TrimSpace(strings.Join(names, " "))
+}
+
+// leaseMergeFields resolves the merge field vocabulary for a lease, so a PDF
+// rendered here reads the same as one the portal rendered. agreementDate is
+// when the agreement was finalized, nil before then. Expects
+// leaseMergeFieldsPopulate to be loaded.
+func leaseMergeFields(
+	lease *models.Lease,
+	agreementDate *time.Time,
+	signatures []models.DocumentSignature,
+) map[string]string {
+	fields := mergeFields{}
+
+	fields.setDate("AgreementDate", agreementDate)
+
+	fields.setLandlord(lease.TenantApplication.CreatedBy)
+
+	tenant := lease.Tenant
+	fields.set("TenantName", tenantFullName(tenant))
+	// The tenant record keeps no address; the application they applied with does.
+	fields.setPtr("TenantAddress", lease.TenantApplication.CurrentAddress)
+	fields.setPtr("TenantEmail", tenant.Email)
+	fields.set("TenantPhoneNumber", tenant.Phone)
+	fields.setPtr("TenantIDType", tenant.IDType)
+	fields.setPtr("TenantIDNumber", tenant.IDNumber)
+	fields.setDate("TenantDateOfBirth", tenant.DateOfBirth)
+	fields.setPtr("TenantNationality", tenant.Nationality)
+	fields.setPtr("TenantOccupation", tenant.Occupation)
+	fields.setPtr("TenantEmployer", tenant.Employer)
+	fields.setPtr("TenantEmergencyContactName", tenant.EmergencyContactName)
+	fields.setPtr("TenantEmergencyContactPhone", tenant.EmergencyContactPhone)
+
+	fields.setProperty(lease.Unit.Property)
+	fields.set("UnitNumber", lease.Unit.Name)
+	fields.set("UnitType", lease.Unit.Type)
+
+	application := lease.TenantApplication
+	fields.set("ApplicationCode", application.Code)
+	fields.setDate("LeaseStartDate", &lease.MoveInDate)
+	fields.setDate("MoveInDate", &lease.MoveInDate)
+	fields.setDuration(&lease.StayDuration, &lease.StayDurationFrequency)
+	fields.setDate("LeaseEndDate", lease.MoveOutDate)
+	fields.setDate("MoveOutDate", lease.MoveOutDate)
+	fields.setRent(&lease.RentFee, lease.RentFeeCurrency, lease.PaymentFrequency)
+	fields.setPaymentDueDate(&lease.MoveInDate, lease.PaymentFrequency)
+
+	// Deposits are settled on the application and stay there.
+	fields.setAmount("SecurityDeposit", application.SecurityDepositFee, application.SecurityDepositFeeCurrency)
+	fields.setAmount("InitialDeposit", application.InitialDepositFee, application.InitialDepositFeeCurrency)
+
+	for _, signature := range signatures {
+		signedOn := signature.CreatedAt
+		switch signature.Role {
+		case "PROPERTY_MANAGER":
+			fields.setDate("LandlordSignedOn", &signedOn)
+		case "TENANT":
+			// The first tenant to sign dates the tenant side, as in the portal.
+			if _, ok := fields["TenantSignedOn"]; !ok {
+				fields.setDate("TenantSignedOn", &signedOn)
+			}
+		case "PM_WITNESS":
+			fields.setPtr("LandlordWitnessName", signature.SignedByName)
+			fields.setDate("LandlordWitnessSignedOn", &signedOn)
+		case "TENANT_WITNESS":
+			fields.setPtr("TenantWitnessName", signature.SignedByName)
+			fields.setDate("TenantWitnessSignedOn", &signedOn)
+		}
+	}
+
+	return fields
+}
+
+// tenantApplicationMergeFields resolves the vocabulary for an application
+// that has no lease yet. The tenant is the applicant and the dates are the
+// ones they asked for; agreementDate is as for leaseMergeFields. Expects
+// tenantApplicationMergeFieldsPopulate to be loaded.
+func tenantApplicationMergeFields(
+	application *models.TenantApplication,
+	agreementDate *time.Time,
+) map[string]string {
+	fields := mergeFields{}
+
+	fields.setDate("AgreementDate", agreementDate)
+
+	fields.setLandlord(application.CreatedBy)
+
+	names := []string{}
+	for _, name := range []*string{application.FirstName, application.OtherNames, application.LastName} {
+		if name != nil && *name != "" {
+			names = append(names, *name)
+		}
+	}
+	fields.set("TenantName", strings.Join(names, " "))
+	fields.setPtr("TenantAddress", application.CurrentAddress)
+	fields.setPtr("TenantEmail", application.Email)
+	fields.set("TenantPhoneNumber", application.Phone)
+	fields.setPtr("TenantIDType", application.IDType)
+	fields.setPtr("TenantIDNumber", application.IDNumber)
+	fields.setDate("TenantDateOfBirth", application.DateOfBirth)
+	fields.setPtr("TenantNationality", application.Nationality)
+	fields.setPtr("TenantOccupation", application.Occupation)
+	fields.setPtr("TenantEmployer", application.Employer)
+	fields.setPtr("TenantEmergencyContactName", application.EmergencyContactName)
+	fields.setPtr("TenantEmergencyContactPhone", application.EmergencyContactPhone)
+
+	fields.setProperty(application.DesiredUnit.Property)
+	fields.set("UnitNumber", application.DesiredUnit.Name)
+	fields.set("UnitType", application.DesiredUnit.Type)
+
+	fields.set("ApplicationCode", application.Code)
+	fields.setDate("LeaseStartDate", application.DesiredMoveInDate)
+	fields.setDate("MoveInDate", application.DesiredMoveInDate)
+	fields.setDuration(application.StayDuration, application.StayDurationFrequency)
+	if application.DesiredMoveInDate != nil && application.StayDuration != nil &&
+		application.StayDurationFrequency != nil {
+		moveOut := leaseEndDate(
+			*application.DesiredMoveInDate, *application.StayDuration, *application.StayDurationFrequency,
+		)
+		fields.setDate("LeaseEndDate", &moveOut)
+		fields.setDate("MoveOutDate", &moveOut)
+	}
+
+	currency := ""
+	if application.RentFeeCurrency != nil {
+		currency = *application.RentFeeCurrency
+	}
+	fields.setRent(application.RentFee, currency, application.PaymentFrequency)
+	fields.setPaymentDueDate(application.DesiredMoveInDate, application.PaymentFrequency)
+	fields.setAmount("SecurityDeposit", application.SecurityDepositFee, application.SecurityDepositFeeCurrency)
+	fields.setAmount("InitialDeposit", application.InitialDepositFee, application.InitialDepositFeeCurrency)
+
+	return fields
+}
+
+// leaseTerminationMergeFields resolves the vocabulary for a termination
+// agreement: the lease's fields, with the move-out the termination settles
+// on in place of the lease's own. termination.Lease must be loaded as
+// leaseMergeFields expects.
+func leaseTerminationMergeFields(termination *models.LeaseTermination) map[string]string {
+	fields := mergeFields(leaseMergeFields(&termination.Lease, nil, nil))
+
+	if termination.IntendedMoveOutDate != nil {
+		fields.setDate("MoveOutDate", termination.IntendedMoveOutDate)
+	}
+	fields.set("TerminationCode", termination.Code)
+	fields.set("TerminationReason", termination.Reason)
+	fields.setDate("NoticeDate", &termination.CreatedAt)
+	fields.setAmount("EarlyTerminationFee", &termination.EarlyTerminationFee, termination.Lease.RentFeeCurrency)
+
+	return fields
+}
+
+// unresolvedMergeFields lists the vocabulary's fields still written into a
+// document once fields are applied, leaving out those signing fills in.
+func unresolvedMergeFields(root *documentpdf.Node, fields map[string]string) []string {
+	unresolved := []string{}
+	for _, name := range documentpdf.MergeFieldNames(root) {
+		if !mergeFieldVocabulary[name] || signingMergeFields[name] || fields[name] != "" {
+			continue
+		}
+		unresolved = append(unresolved, name)
+	}
+	return unresolved
+}
+
+// checkMergeFieldsResolved blocks finalizing a document that would go out
+// with merge fields nobody could fill in. The PM types those values into the
+// document, or fills in the record they come from, and finalizes again.
+func checkMergeFieldsResolved(content []byte, fields map[string]string) error {
+	root, err := documentpdf.ParseContent(content)
+	if err != nil {
+		return pkg.BadRequestError("DocumentContentInvalid", &pkg.RentLoopErrorParams{Err: err})
+	}
+
+	if unresolved := unresolvedMergeFields(root, fields); len(unresolved) > 0 {
+		return pkg.BadRequestError("DocumentHasUnresolvedMergeFields", &pkg.RentLoopErrorParams{
+			Metadata: map[string]string{"fields": strings.Join(unresolved, ", ")},
+		})
+	}
+
+	return nil
+}
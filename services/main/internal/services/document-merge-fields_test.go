@@ -0,0 +1,346 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/documentpdf"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// Fields resolve to what the portal's own preview shows, and a value the
+// lease does not have is left out, so its #Field stays visible on the PDF
+// instead of silently printing blank.
+func TestLeaseMergeFields(t *testing.T) {
+	moveOut := time.Date(2027, 9, 30, 0, 0, 0, 0, time.UTC)
+	monthly := "Monthly"
+	firstSigned := time.Date(2026, 9, 20, 10, 0, 0, 0, time.UTC)
+
+	lease := &models.Lease{
+		Tenant:                models.Tenant{FirstName: "Ama", LastName: "Mensah", Phone: "+233200000000"},
+		Unit:                  models.Unit{Name: "A4", Property: models.Property{Name: "Palm Court"}},
+		MoveInDate:            time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		MoveOutDate:           &moveOut,
+		StayDuration:          12,
+		StayDurationFrequency: "Months",
+		RentFee:               450000,
+		PaymentFrequency:      &monthly,
+	}
+
+	tenantSignature := func(at time.Time) models.DocumentSignature {
+		signature := models.DocumentSignature{Role: "TENANT"}
+		signature.CreatedAt = at
+		return signature
+	}
+
+	fields := leaseMergeFields(lease, nil, []models.DocumentSignature{
+		tenantSignature(firstSigned),
+		tenantSignature(firstSigned.AddDate(0, 0, 2)),
+	})
+
+	want := map[string]string{
+		"TenantName":     "Ama Mensah",
+		"PropertyName":   "Palm Court",
+		"UnitNumber":     "A4",
+		"LeaseStartDate": "Oct 1, 2026",
+		"LeaseEndDate":   "Sep 30, 2027",
+		"LeaseDuration":  "12 months",
+		"RentAmount":     "4,500.00",
+		"RentFrequency":  "monthly",
+		"TenantSignedOn": "Sep 20, 2026",
+	}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("%s = %q, want %q", name, fields[name], value)
+		}
+	}
+
+	for _, missing := range []string{"TenantEmail", "LandlordName", "LandlordSignedOn"} {
+		if _, ok := fields[missing]; ok {
+			t.Errorf("%s is set to %q, want it left unresolved", missing, fields[missing])
+		}
+	}
+}
+
+// Amounts read as a contract states them, and an open-ended lease's 2099
+// sentinel is never printed as its end date.
+func TestMergeFieldFormats(t *testing.T) {
+	securityDeposit := int64(90000000)
+	openEnded := time.Date(2099, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	lease := &models.Lease{
+		MoveInDate:      time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC),
+		MoveOutDate:     &openEnded,
+		RentFee:         450000,
+		RentFeeCurrency: "GHS",
+		TenantApplication: models.TenantApplication{
+			SecurityDepositFee:         &securityDeposit,
+			SecurityDepositFeeCurrency: "USD",
+		},
+	}
+
+	fields := leaseMergeFields(lease, nil, nil)
+
+	want := map[string]string{
+		"RentAmountWithCurrency": "GHS 4,500.00",
+		"RentAmountInWords":      "four thousand five hundred",
+		"SecurityDeposit":        "USD 900,000.00",
+		"MoveInDate":             "Oct 1, 2026",
+	}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("%s = %q, want %q", name, fields[name], value)
+		}
+	}
+
+	for _, missing := range []string{"LeaseEndDate", "MoveOutDate", "InitialDeposit"} {
+		if _, ok := fields[missing]; ok {
+			t.Errorf("%s is set to %q, want it left unresolved", missing, fields[missing])
+		}
+	}
+}
+
+// The default lease template the portal seeds every client with finalizes
+// once the lease and its application are filled in. Before it is finalized
+// the agreement has no date, so only #AgreementDate is left.
+func TestFinalizeDefaultLeaseTemplate(t *testing.T) {
+	content, err := os.ReadFile("testdata/documents/basic-lease-agreement.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	str := func(value string) *string { return &value }
+	amount := func(value int64) *int64 { return &value }
+	moveIn := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	moveOut := time.Date(2027, 9, 30, 0, 0, 0, 0, time.UTC)
+	finalizedAt := time.Date(2026, 9, 18, 15, 0, 0, 0, time.UTC)
+
+	property := models.Property{
+		Name:    "Palm Court",
+		Address: "12 Palm Street, Osu",
+		Client: models.Client{
+			Name: "Palm Estates",
+			PaymentAccounts: []models.PaymentAccount{
+				{OwnerType: "PROPERTY_OWNER", Rail: "MOMO", Provider: str("MTN"), Identifier: str("0240000000"),
+					Status: "ACTIVE"},
+				{OwnerType: "PROPERTY_OWNER", Rail: "BANK_TRANSFER", Identifier: str("1441000123456"),
+					Status: "ACTIVE", IsDefault: true},
+				{OwnerType: "PROPERTY_OWNER", Rail: "MOMO", Provider: str("VODAFONE"), Identifier: str("0500000000"),
+					Status: "DISABLED"},
+			},
+		},
+	}
+	manager := models.ClientUser{User: models.User{Name: "Kofi Boateng"}}
+	application := models.TenantApplication{
+		Code:                       "TA-2609-XYZ789",
+		FirstName:                  str("Ama"),
+		LastName:                   str("Mensah"),
+		Phone:                      "+233200000000",
+		CurrentAddress:             str("4 Ring Road, Accra"),
+		IDType:                     str("GHANA_CARD"),
+		IDNumber:                   str("GHA-000000000-0"),
+		Nationality:                str("Ghanaian"),
+		DesiredUnit:                models.Unit{Name: "A4", Property: property},
+		DesiredMoveInDate:          &moveIn,
+		StayDuration:               amount(12),
+		StayDurationFrequency:      str("Months"),
+		RentFee:                    amount(450000),
+		RentFeeCurrency:            str("GHS"),
+		PaymentFrequency:           str("Monthly"),
+		SecurityDepositFee:         amount(900000),
+		SecurityDepositFeeCurrency: "GHS",
+		InitialDepositFee:          amount(450000),
+		InitialDepositFeeCurrency:  "GHS",
+		CreatedBy:                  manager,
+	}
+	lease := &models.Lease{
+		Tenant: models.Tenant{
+			FirstName:   "Ama",
+			LastName:    "Mensah",
+			Phone:       "+233200000000",
+			IDType:      str("GHANA_CARD"),
+			IDNumber:    str("GHA-000000000-0"),
+			Nationality: str("Ghanaian"),
+		},
+		Unit:                  models.Unit{Name: "A4", Property: property},
+		TenantApplication:     application,
+		MoveInDate:            moveIn,
+		MoveOutDate:           &moveOut,
+		StayDuration:          12,
+		StayDurationFrequency: "Months",
+		RentFee:               450000,
+		RentFeeCurrency:       "GHS",
+		PaymentFrequency:      str("Monthly"),
+	}
+
+	tests := []struct {
+		name   string
+		fields map[string]string
+	}{
+		{"lease", leaseMergeFields(lease, &finalizedAt, nil)},
+		{"application", tenantApplicationMergeFields(&application, &finalizedAt)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := checkMergeFieldsResolved(content, tt.fields); err != nil {
+				t.Errorf("got %v, want the default template to finalize", err)
+			}
+
+			want := map[string]string{
+				"AgreementDate":         "Sep 18, 2026",
+				"PaymentDueDate":        "1st",
+				"PaymentAccountDetails": "Bank account 1441000123456; MTN Mobile Money 0240000000",
+				"TenantAddress":         "4 Ring Road, Accra",
+			}
+			for name, value := range want {
+				if tt.fields[name] != value {
+					t.Errorf("%s = %q, want %q", name, tt.fields[name], value)
+				}
+			}
+		})
+	}
+
+	root, err := documentpdf.ParseContent(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := unresolvedMergeFields(root, leaseMergeFields(lease, nil, nil))
+	if want := []string{"AgreementDate"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("unfinalized: got %v, want %v", got, want)
+	}
+}
+
+// Rent falls due on the day its period starts, and amounts are spelled out
+// as the portal spells them.
+func TestPaymentDueDateAndAmountInWords(t *testing.T) {
+	moveIn := time.Date(2026, 10, 22, 0, 0, 0, 0, time.UTC) // a Thursday
+
+	dueDates := []struct {
+		frequency string
+		want      string
+	}{
+		{"WEEKLY", "Thursday"},
+		{"DAILY", "start"},
+		{"Monthly", "22nd"},
+		{"Annually", "22nd"},
+	}
+	for _, tt := range dueDates {
+		fields := mergeFields{}
+		fields.setPaymentDueDate(&moveIn, &tt.frequency)
+		if fields["PaymentDueDate"] != tt.want {
+			t.Errorf("%s: PaymentDueDate = %q, want %q", tt.frequency, fields["PaymentDueDate"], tt.want)
+		}
+	}
+
+	for _, day := range []struct {
+		day  int
+		want string
+	}{{1, "1st"}, {2, "2nd"}, {3, "3rd"}, {11, "11th"}, {12, "12th"}, {13, "13th"}, {21, "21st"}, {31, "31st"}} {
+		if got := ordinalDay(day.day); got != day.want {
+			t.Errorf("ordinalDay(%d) = %q, want %q", day.day, got, day.want)
+		}
+	}
+
+	amounts := []struct {
+		pesewas int64
+		want    string
+	}{
+		{0, "zero"},
+		{1205000, "twelve thousand and fifty"},
+		{1250, "twelve and fifty pesewas"},
+		{45000000, "four hundred and fifty thousand"},
+		{123456700, "one million two hundred and thirty-four thousand five hundred and sixty-seven"},
+	}
+	for _, tt := range amounts {
+		if got := amountInWords(tt.pesewas); got != tt.want {
+			t.Errorf("amountInWords(%d) = %q, want %q", tt.pesewas, got, tt.want)
+		}
+	}
+}
+
+// A termination agreement states the move-out the termination settled on,
+// not the one the lease was written with.
+func TestLeaseTerminationMergeFields(t *testing.T) {
+	leaseEnd := time.Date(2027, 9, 30, 0, 0, 0, 0, time.UTC)
+	intended := time.Date(2027, 3, 31, 0, 0, 0, 0, time.UTC)
+
+	termination := &models.LeaseTermination{
+		Code:                "LT-2703-ABC123",
+		Reason:              "Relocating",
+		IntendedMoveOutDate: &intended,
+		EarlyTerminationFee: 900000,
+		Lease:               models.Lease{MoveOutDate: &leaseEnd, RentFeeCurrency: "GHS"},
+	}
+
+	fields := leaseTerminationMergeFields(termination)
+
+	want := map[string]string{
+		"LeaseEndDate":        "Sep 30, 2027",
+		"MoveOutDate":         "Mar 31, 2027",
+		"TerminationReason":   "Relocating",
+		"EarlyTerminationFee": "GHS 9,000.00",
+	}
+	for name, value := range want {
+		if fields[name] != value {
+			t.Errorf("%s = %q, want %q", name, fields[name], value)
+		}
+	}
+}
+
+// Finalizing is blocked only by vocabulary fields nothing filled in. Signing
+// dates fill in later, and a hashtag the editor does not offer is just text.
+func TestUnresolvedMergeFields(t *testing.T) {
+	content := `{"root":{"type":"root","children":[{"type":"paragraph","children":[
+		{"type":"hashtag","text":"#TenantName"},
+		{"type":"hashtag","text":"#PaymentDueDate"},
+		{"type":"hashtag","text":"#TenantSignedOn"},
+		{"type":"hashtag","text":"#housing"},
+		{"type":"hashtag","text":"#AgreementDate"},
+		{"type":"hashtag","text":"#PaymentDueDate"}
+	]}]}}`
+
+	root, err := documentpdf.ParseContent([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := unresolvedMergeFields(root, map[string]string{"TenantName": "Ama Mensah"})
+	if want := []string{"PaymentDueDate", "AgreementDate"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+
+	if err := checkMergeFieldsResolved([]byte(content), map[string]string{}); err == nil {
+		t.Error("got no error for a document with unresolved fields")
+	}
+}
+
+// Instantiating keeps every editor property the renderer does not read, so
+// the PM's copy opens in the editor exactly as the template was written.
+func TestResolveMergeFieldsInJSON(t *testing.T) {
+	var content map[string]any
+	if err := json.Unmarshal([]byte(`{"root":{"type":"root","direction":"ltr","children":[
+		{"type":"hashtag","text":"#TenantName","format":1,"style":"color: red","version":1},
+		{"type":"hashtag","text":"#TenantEmail","version":1}
+	]}}`), &content); err != nil {
+		t.Fatal(err)
+	}
+
+	resolveMergeFieldsInJSON(content, map[string]string{"TenantName": "Ama Mensah"})
+
+	root := content["root"].(map[string]any)
+	children := root["children"].([]any)
+	resolved, unresolved := children[0].(map[string]any), children[1].(map[string]any)
+
+	if resolved["type"] != "text" || resolved["text"] != "Ama Mensah" || resolved["style"] != "color: red" {
+		t.Errorf("got resolved node %v", resolved)
+	}
+	if unresolved["type"] != "hashtag" || unresolved["text"] != "#TenantEmail" {
+		t.Errorf("got unresolved node %v", unresolved)
+	}
+	if root["direction"] != "ltr" {
+		t.Errorf("got root %v", root)
+	}
+}
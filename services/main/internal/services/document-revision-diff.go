@@ -0,0 +1,251 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// DocumentNodeChange is one difference between the Lexical trees of two
+// revisions.
+type DocumentNodeChange struct {
+	Op       string // "ADDED" | "REMOVED" | "CHANGED"
+	NodeType string
+	// FromPath and ToPath locate the node in each revision by child index
+	// from the root: [2, 0] is the first child of the root's third child.
+	// FromPath is nil for ADDED nodes and ToPath for REMOVED ones.
+	FromPath []int
+	ToPath   []int
+	FromText *string
+	ToText   *string
+}
+
+// lexicalContainerTypes are the nodes a change is reported inside of rather
+// than on: editing one cell of a table reports that cell, not the table.
+// Everything else — paragraphs, headings, list items — is reported whole,
+// with its text before and after.
+var lexicalContainerTypes = map[string]bool{
+	"root":            true,
+	"list":            true,
+	"table":           true,
+	"tablerow":        true,
+	"tablecell":       true,
+	"layoutcontainer": true,
+	"layoutitem":      true,
+}
+
+// diffLexicalContent compares two serialized Lexical editor states node by
+// node. Unchanged nodes are matched up first, so a paragraph inserted near the
+// top reports one addition rather than every paragraph after it changing.
+func diffLexicalContent(from, to []byte) ([]DocumentNodeChange, error) {
+	fromRoot, err := lexicalRoot(from)
+	if err != nil {
+		return nil, err
+	}
+	toRoot, err := lexicalRoot(to)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := diffLexicalNode(fromRoot, toRoot, []int{}, []int{})
+	if changes == nil {
+		changes = []DocumentNodeChange{}
+	}
+	return changes, nil
+}
+
+func lexicalRoot(content []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var state struct {
+		Root map[string]any `json:"root"`
+	}
+	if err := decoder.Decode(&state); err != nil {
+		return nil, fmt.Errorf("parse content: %w", err)
+	}
+	if state.Root == nil {
+		state.Root = map[string]any{"type": "root"}
+	}
+	return state.Root, nil
+}
+
+// diffLexicalNode compares two nodes that sit in the same place in their
+// trees.
+func diffLexicalNode(from, to map[string]any, fromPath, toPath []int) []DocumentNodeChange {
+	fromKey, toKey := lexicalNodeKey(from), lexicalNodeKey(to)
+	if fromKey == toKey {
+		return nil
+	}
+
+	// A container whose own properties changed — a bulleted list made
+	// numbered, say — is reported whole. The root's are left to the editor,
+	// which sets its text direction from whatever it holds.
+	nodeType := lexicalNodeType(from)
+	if nodeType == lexicalNodeType(to) && lexicalContainerTypes[nodeType] &&
+		(nodeType == "root" || lexicalNodeKey(withoutChildren(from)) == lexicalNodeKey(withoutChildren(to))) {
+		return diffLexicalChildren(lexicalChildren(from), lexicalChildren(to), fromPath, toPath)
+	}
+
+	fromText, toText := lexicalPlainText(from), lexicalPlainText(to)
+	return []DocumentNodeChange{{
+		Op:       "CHANGED",
+		NodeType: lexicalNodeType(to),
+		FromPath: fromPath,
+		ToPath:   toPath,
+		FromText: &fromText,
+		ToText:   &toText,
+	}}
+}
+
+// diffLexicalChildren matches up the unchanged nodes of two child lists by
+// longest common subsequence. The nodes left over between two matches are
+// paired off in order as changes where their types agree, and reported as
+// removed and added otherwise.
+func diffLexicalChildren(from, to []map[string]any, fromPath, toPath []int) []DocumentNodeChange {
+	fromKeys := make([]string, len(from))
+	for i := range from {
+		fromKeys[i] = lexicalNodeKey(from[i])
+	}
+	toKeys := make([]string, len(to))
+	for j := range to {
+		toKeys[j] = lexicalNodeKey(to[j])
+	}
+
+	// common[i][j] is the length of the longest common subsequence of
+	// fromKeys[i:] and toKeys[j:].
+	common := make([][]int, len(from)+1)
+	for i := range common {
+		common[i] = make([]int, len(to)+1)
+	}
+	for i := len(from) - 1; i >= 0; i-- {
+		for j := len(to) - 1; j >= 0; j-- {
+			if fromKeys[i] == toKeys[j] {
+				common[i][j] = common[i+1][j+1] + 1
+			} else {
+				common[i][j] = max(common[i+1][j], common[i][j+1])
+			}
+		}
+	}
+
+	var changes []DocumentNodeChange
+	i, j := 0, 0
+	gapFrom, gapTo := 0, 0
+	flushGap := func() {
+		changes = append(changes, pairLexicalGap(from, to, gapFrom, i, gapTo, j, fromPath, toPath)...)
+	}
+	for i < len(from) && j < len(to) {
+		switch {
+		case fromKeys[i] == toKeys[j]:
+			flushGap()
+			i, j = i+1, j+1
+			gapFrom, gapTo = i, j
+		case common[i+1][j] >= common[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	i, j = len(from), len(to)
+	flushGap()
+
+	return changes
+}
+
+// pairLexicalGap reports the unmatched nodes from[fromStart:fromEnd] and
+// to[toStart:toEnd].
+func pairLexicalGap(
+	from, to []map[string]any,
+	fromStart, fromEnd, toStart, toEnd int,
+	fromPath, toPath []int,
+) []DocumentNodeChange {
+	var changes []DocumentNodeChange
+
+	i, j := fromStart, toStart
+	for ; i < fromEnd && j < toEnd; i, j = i+1, j+1 {
+		if lexicalNodeType(from[i]) == lexicalNodeType(to[j]) {
+			changes = append(changes, diffLexicalNode(from[i], to[j], childPath(fromPath, i), childPath(toPath, j))...)
+			continue
+		}
+		changes = append(changes, removedLexicalNode(from[i], childPath(fromPath, i)))
+		changes = append(changes, addedLexicalNode(to[j], childPath(toPath, j)))
+	}
+	for ; i < fromEnd; i++ {
+		changes = append(changes, removedLexicalNode(from[i], childPath(fromPath, i)))
+	}
+	for ; j < toEnd; j++ {
+		changes = append(changes, addedLexicalNode(to[j], childPath(toPath, j)))
+	}
+
+	return changes
+}
+
+func removedLexicalNode(node map[string]any, path []int) DocumentNodeChange {
+	text := lexicalPlainText(node)
+	return DocumentNodeChange{Op: "REMOVED", NodeType: lexicalNodeType(node), FromPath: path, FromText: &text}
+}
+
+func addedLexicalNode(node map[string]any, path []int) DocumentNodeChange {
+	text := lexicalPlainText(node)
+	return DocumentNodeChange{Op: "ADDED", NodeType: lexicalNodeType(node), ToPath: path, ToText: &text}
+}
+
+func childPath(path []int, index int) []int {
+	child := make([]int, len(path), len(path)+1)
+	copy(child, path)
+	return append(child, index)
+}
+
+// lexicalNodeKey identifies a node by everything in it: encoding/json writes
+// map keys sorted, so equal nodes encode the same.
+func lexicalNodeKey(node map[string]any) string {
+	encoded, _ := json.Marshal(node)
+	return string(encoded)
+}
+
+func lexicalNodeType(node map[string]any) string {
+	nodeType, _ := node["type"].(string)
+	return nodeType
+}
+
+func lexicalChildren(node map[string]any) []map[string]any {
+	raw, _ := node["children"].([]any)
+	children := make([]map[string]any, 0, len(raw))
+	for _, child := range raw {
+		if childNode, ok := child.(map[string]any); ok {
+			children = append(children, childNode)
+		}
+	}
+	return children
+}
+
+func withoutChildren(node map[string]any) map[string]any {
+	shallow := make(map[string]any, len(node))
+	for key, value := range node {
+		if key != "children" {
+			shallow[key] = value
+		}
+	}
+	return shallow
+}
+
+// lexicalPlainText flattens node and its descendants into a single string,
+// as documentpdf does for the nodes it renders.
+func lexicalPlainText(node map[string]any) string {
+	if text, ok := node["text"].(string); ok {
+		return text
+	}
+	switch lexicalNodeType(node) {
+	case "linebreak":
+		return "\n"
+	case "tab":
+		return "\t"
+	}
+
+	var text strings.Builder
+	for _, child := range lexicalChildren(node) {
+		text.WriteString(lexicalPlainText(child))
+	}
+	return text.String()
+}
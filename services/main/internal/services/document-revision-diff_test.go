@@ -0,0 +1,119 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// The diff should read the way the PM made the edit: one paragraph inserted
+// or reworded is one change, whatever else in the document moved down, and
+// an edited table cell is reported on its own rather than as the table.
+func TestDiffLexicalContent(t *testing.T) {
+	paragraph := func(text string) string {
+		return fmt.Sprintf(`{"type":"paragraph","children":[{"type":"text","text":%q,"format":0}]}`, text)
+	}
+	heading := func(text string) string {
+		return fmt.Sprintf(`{"type":"heading","tag":"h1","children":[{"type":"text","text":%q}]}`, text)
+	}
+	table := func(cells ...string) string {
+		row := make([]string, 0, len(cells))
+		for _, cell := range cells {
+			row = append(row, fmt.Sprintf(`{"type":"tablecell","children":[%s]}`, paragraph(cell)))
+		}
+		return fmt.Sprintf(`{"type":"table","children":[{"type":"tablerow","children":[%s]}]}`, strings.Join(row, ","))
+	}
+	document := func(blocks ...string) []byte {
+		return []byte(`{"root":{"type":"root","children":[` + strings.Join(blocks, ",") + `]}}`)
+	}
+
+	reviewed := document(heading("Lease"), paragraph("Rent is due on the 1st."), paragraph("No pets."))
+
+	cases := []struct {
+		name  string
+		from  []byte
+		to    []byte
+		wants []string
+	}{
+		{"unchanged", reviewed, reviewed, nil},
+		{
+			"paragraph inserted",
+			reviewed,
+			document(heading("Lease"), paragraph("Deposit is two months."), paragraph("Rent is due on the 1st."),
+				paragraph("No pets.")),
+			[]string{`ADDED paragraph - [1] "" -> "Deposit is two months."`},
+		},
+		{
+			"paragraph reworded",
+			reviewed,
+			document(heading("Lease"), paragraph("Rent is due on the 5th."), paragraph("No pets.")),
+			[]string{`CHANGED paragraph [1] [1] "Rent is due on the 1st." -> "Rent is due on the 5th."`},
+		},
+		{
+			"paragraph removed",
+			reviewed,
+			document(heading("Lease"), paragraph("Rent is due on the 1st.")),
+			[]string{`REMOVED paragraph [2] - "No pets." -> ""`},
+		},
+		{
+			"paragraph replaced by a heading",
+			reviewed,
+			document(heading("Lease"), heading("Rent"), paragraph("No pets.")),
+			[]string{
+				`REMOVED paragraph [1] - "Rent is due on the 1st." -> ""`,
+				`ADDED heading - [1] "" -> "Rent"`,
+			},
+		},
+		{
+			"formatting only",
+			reviewed,
+			document(heading("Lease"), strings.Replace(paragraph("Rent is due on the 1st."), `"format":0`, `"format":1`, 1),
+				paragraph("No pets.")),
+			[]string{`CHANGED paragraph [1] [1] "Rent is due on the 1st." -> "Rent is due on the 1st."`},
+		},
+		{
+			"table cell edited",
+			document(table("Rent", "GHS 1,000")),
+			document(table("Rent", "GHS 1,200")),
+			[]string{`CHANGED paragraph [0 0 1 0] [0 0 1 0] "GHS 1,000" -> "GHS 1,200"`},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			changes, err := diffLexicalContent(tc.from, tc.to)
+			if err != nil {
+				t.Fatalf("diffLexicalContent: %v", err)
+			}
+
+			got := make([]string, 0, len(changes))
+			for _, change := range changes {
+				got = append(got, describeNodeChange(change))
+			}
+			if strings.Join(got, "\n") != strings.Join(tc.wants, "\n") {
+				t.Errorf("changes =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tc.wants, "\n"))
+			}
+		})
+	}
+}
+
+func describeNodeChange(change DocumentNodeChange) string {
+	path := func(p []int) string {
+		if p == nil {
+			return "-"
+		}
+		return fmt.Sprint(p)
+	}
+	text := func(s *string) string {
+		if s == nil {
+			return `""`
+		}
+		return fmt.Sprintf("%q", *s)
+	}
+	return fmt.Sprintf(
+		"%s %s %s %s %s -> %s",
+		change.Op, change.NodeType,
+		path(change.FromPath), path(change.ToPath),
+		text(change.FromText), text(change.ToText),
+	)
+}
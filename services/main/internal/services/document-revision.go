@@ -0,0 +1,391 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"gorm.io/gorm"
+)
+
+// recordRevision snapshots document's title and content as its next revision
+// and makes that the current one; the caller saves document. document's row
+// must be locked, or just created, in transCtx's transaction so two revisions
+// cannot take the same number.
+func (s *documentService) recordRevision(
+	transCtx context.Context,
+	document *models.Document,
+	source string,
+	createdByID *string,
+	restoredFromID *string,
+) (*models.DocumentRevision, error) {
+	number := int64(1)
+	latest, err := s.revisionRepo.GetLatest(transCtx, document.ID.String())
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "recordRevision",
+				"action":   "fetching latest revision",
+			},
+		})
+	}
+	if latest != nil {
+		number = latest.Number + 1
+	}
+
+	revision := &models.DocumentRevision{
+		DocumentID:     document.ID.String(),
+		Number:         number,
+		Title:          document.Title,
+		Content:        document.Content,
+		ContentHash:    documentContentHash(document.Content),
+		Source:         source,
+		RestoredFromID: restoredFromID,
+		CreatedByID:    createdByID,
+	}
+	if createErr := s.revisionRepo.Create(transCtx, revision); createErr != nil {
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err: createErr,
+			Metadata: map[string]string{
+				"function": "recordRevision",
+				"action":   "creating revision",
+			},
+		})
+	}
+
+	revisionID := revision.ID.String()
+	document.CurrentRevisionID = &revisionID
+	return revision, nil
+}
+
+// currentRevision returns document's current revision. A document from before
+// revisions were kept has its content recorded as a BACKFILLED revision
+// first. document's row must be locked in transCtx's transaction.
+func (s *documentService) currentRevision(
+	transCtx context.Context,
+	document *models.Document,
+) (*models.DocumentRevision, error) {
+	if document.CurrentRevisionID != nil {
+		revision, err := s.revisionRepo.GetOne(transCtx, repository.GetDocumentRevisionQuery{
+			ID:         *document.CurrentRevisionID,
+			DocumentID: document.ID.String(),
+		})
+		if err != nil {
+			return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err: err,
+				Metadata: map[string]string{
+					"function": "currentRevision",
+					"action":   "fetching current revision",
+				},
+			})
+		}
+		return revision, nil
+	}
+
+	revision, err := s.recordRevision(transCtx, document, "BACKFILLED", nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if updateErr := s.repo.Update(transCtx, document); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function": "currentRevision",
+				"action":   "setting backfilled revision as current",
+			},
+		})
+	}
+	return revision, nil
+}
+
+// createWithRevision creates document with its first revision.
+func (s *documentService) createWithRevision(
+	ctx context.Context,
+	document *models.Document,
+	source string,
+	createdByID string,
+) error {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.repo.Create(transCtx, document); err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "createWithRevision",
+				"action":   "creating new document record",
+			},
+		})
+	}
+
+	if _, err := s.recordRevision(transCtx, document, source, &createdByID, nil); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if err := s.repo.Update(transCtx, document); err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "createWithRevision",
+				"action":   "setting first revision as current",
+			},
+		})
+	}
+
+	if err := transaction.Commit().Error; err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "createWithRevision",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	return nil
+}
+
+// CurrentRevision returns the revision holding the document's content as it
+// is now, for finalization and signing to pin to.
+func (s *documentService) CurrentRevision(ctx context.Context, documentID string) (*models.DocumentRevision, error) {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	document, err := s.repo.GetByIDForUpdate(transCtx, documentID)
+	if err != nil {
+		transaction.Rollback()
+		return nil, documentFetchError(err, "CurrentRevision")
+	}
+
+	revision, revisionErr := s.currentRevision(transCtx, document)
+	if revisionErr != nil {
+		transaction.Rollback()
+		return nil, revisionErr
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err: commitErr,
+			Metadata: map[string]string{
+				"function": "CurrentRevision",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	return revision, nil
+}
+
+func (s *documentService) ListRevisions(
+	ctx context.Context,
+	filter repository.ListDocumentRevisionsFilter,
+) ([]models.DocumentRevision, error) {
+	revisions, err := s.revisionRepo.List(ctx, filter)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ListRevisions",
+				"action":   "listing document revisions",
+			},
+		})
+	}
+
+	return *revisions, nil
+}
+
+func (s *documentService) CountRevisions(
+	ctx context.Context,
+	filter repository.ListDocumentRevisionsFilter,
+) (int64, error) {
+	count, err := s.revisionRepo.Count(ctx, filter)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CountRevisions",
+				"action":   "counting document revisions",
+			},
+		})
+	}
+
+	return count, nil
+}
+
+func (s *documentService) GetRevision(
+	ctx context.Context,
+	query repository.GetDocumentRevisionQuery,
+) (*models.DocumentRevision, error) {
+	revision, err := s.revisionRepo.GetOne(ctx, query)
+	if err != nil {
+		return nil, revisionFetchError(err, "GetRevision")
+	}
+
+	return revision, nil
+}
+
+type RestoreDocumentRevisionInput struct {
+	DocumentID   string
+	RevisionID   string
+	ClientUserID string
+}
+
+// RestoreRevision brings back a revision's title and content. The restored
+// content is recorded as a new revision, so the history it replaces is kept.
+func (s *documentService) RestoreRevision(
+	ctx context.Context,
+	input RestoreDocumentRevisionInput,
+) (*models.Document, error) {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	document, err := s.repo.GetByIDForUpdate(transCtx, input.DocumentID)
+	if err != nil {
+		transaction.Rollback()
+		return nil, documentFetchError(err, "RestoreRevision")
+	}
+
+	revision, revisionErr := s.revisionRepo.GetOne(transCtx, repository.GetDocumentRevisionQuery{
+		ID:         input.RevisionID,
+		DocumentID: input.DocumentID,
+	})
+	if revisionErr != nil {
+		transaction.Rollback()
+		return nil, revisionFetchError(revisionErr, "RestoreRevision")
+	}
+
+	current, currentErr := s.currentRevision(transCtx, document)
+	if currentErr != nil {
+		transaction.Rollback()
+		return nil, currentErr
+	}
+	if current.ID == revision.ID {
+		transaction.Rollback()
+		return nil, pkg.BadRequestError("DocumentRevisionAlreadyCurrent", nil)
+	}
+
+	document.Title = revision.Title
+	document.Content = revision.Content
+	document.Size = int64(len(revision.Content))
+	document.UpdatedByID = &input.ClientUserID
+
+	restoredFromID := revision.ID.String()
+	_, recordErr := s.recordRevision(transCtx, document, "RESTORED", &input.ClientUserID, &restoredFromID)
+	if recordErr != nil {
+		transaction.Rollback()
+		return nil, recordErr
+	}
+
+	if updateErr := s.repo.Update(transCtx, document); updateErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function": "RestoreRevision",
+				"action":   "updating document record",
+			},
+		})
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err: commitErr,
+			Metadata: map[string]string{
+				"function": "RestoreRevision",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	return document, nil
+}
+
+type DiffDocumentRevisionsInput struct {
+	DocumentID     string
+	FromRevisionID string
+	ToRevisionID   *string // the current revision when nil
+}
+
+// DocumentRevisionDiff is what changed between two revisions of a document.
+type DocumentRevisionDiff struct {
+	From         *models.DocumentRevision
+	To           *models.DocumentRevision
+	TitleChanged bool
+	Changes      []DocumentNodeChange
+}
+
+func (s *documentService) DiffRevisions(
+	ctx context.Context,
+	input DiffDocumentRevisionsInput,
+) (*DocumentRevisionDiff, error) {
+	from, err := s.GetRevision(ctx, repository.GetDocumentRevisionQuery{
+		ID:         input.FromRevisionID,
+		DocumentID: input.DocumentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var to *models.DocumentRevision
+	if input.ToRevisionID != nil {
+		to, err = s.GetRevision(ctx, repository.GetDocumentRevisionQuery{
+			ID:         *input.ToRevisionID,
+			DocumentID: input.DocumentID,
+		})
+	} else {
+		to, err = s.CurrentRevision(ctx, input.DocumentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	changes, diffErr := diffLexicalContent(from.Content, to.Content)
+	if diffErr != nil {
+		return nil, pkg.BadRequestError("DocumentContentInvalid", &pkg.RentLoopErrorParams{Err: diffErr})
+	}
+
+	return &DocumentRevisionDiff{
+		From:         from,
+		To:           to,
+		TitleChanged: from.Title != to.Title,
+		Changes:      changes,
+	}, nil
+}
+
+func documentFetchError(err error, function string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pkg.NotFoundError("DocumentNotFound", &pkg.RentLoopErrorParams{Err: err})
+	}
+
+	return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		Err: err,
+		Metadata: map[string]string{
+			"function": function,
+			"action":   "fetching document by ID",
+		},
+	})
+}
+
+func revisionFetchError(err error, function string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pkg.NotFoundError("DocumentRevisionNotFound", &pkg.RentLoopErrorParams{Err: err})
+	}
+
+	return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		Err: err,
+		Metadata: map[string]string{
+			"function": function,
+			"action":   "fetching document revision",
+		},
+	})
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"gorm.io/gorm"
+)
+
+// InstantiateDocumentTemplateInput names the template and the one record its
+// merge fields resolve from. LeaseID is also required with
+// LeaseTerminationID, which is looked up within its lease.
+type InstantiateDocumentTemplateInput struct {
+	TemplateID          string
+	Title               *string
+	LeaseID             *string
+	TenantApplicationID *string
+	LeaseTerminationID  *string
+	ClientUserID        string
+}
+
+// InstantiateTemplate creates a DOCUMENT from a TEMPLATE with its merge
+// fields filled in from the lease, application or termination it is for.
+//
+// Fields the record has no value for stay in the document as #Field, for
+// the PM to fill in by hand; finalization refuses the document until they
+// have.
+func (s *documentService) InstantiateTemplate(
+	ctx context.Context,
+	input InstantiateDocumentTemplateInput,
+) (*models.Document, error) {
+	template, err := s.GetByID(ctx, input.TemplateID)
+	if err != nil {
+		return nil, err
+	}
+	if template.Type != "TEMPLATE" {
+		return nil, pkg.BadRequestError("DocumentIsNotATemplate", nil)
+	}
+
+	fields, propertyID, fieldsErr := s.mergeFieldsFor(ctx, input)
+	if fieldsErr != nil {
+		return nil, fieldsErr
+	}
+
+	// Resolved on the raw JSON rather than documentpdf's parsed tree, which
+	// only keeps what the renderer reads and would drop the editor's other
+	// node properties.
+	var content map[string]any
+	if unmarshalErr := json.Unmarshal(template.Content, &content); unmarshalErr != nil {
+		return nil, pkg.BadRequestError("DocumentContentInvalid", &pkg.RentLoopErrorParams{Err: unmarshalErr})
+	}
+	resolveMergeFieldsInJSON(content, fields)
+
+	contentBytes, marshalErr := json.Marshal(content)
+	if marshalErr != nil {
+		return nil, pkg.InternalServerError(marshalErr.Error(), &pkg.RentLoopErrorParams{
+			Err: marshalErr,
+			Metadata: map[string]string{
+				"function": "InstantiateTemplate",
+				"action":   "marshaling resolved content to JSON",
+			},
+		})
+	}
+
+	title := template.Title
+	if input.Title != nil && *input.Title != "" {
+		title = *input.Title
+	}
+
+	document := &models.Document{
+		Type:        "DOCUMENT",
+		Title:       title,
+		Content:     contentBytes,
+		Size:        int64(len(contentBytes)),
+		Tags:        template.Tags,
+		PropertyID:  &propertyID,
+		CreatedByID: input.ClientUserID,
+	}
+
+	if createErr := s.createWithRevision(ctx, document, "INSTANTIATED", input.ClientUserID); createErr != nil {
+		return nil, createErr
+	}
+
+	return document, nil
+}
+
+// mergeFieldsFor loads the record a template is instantiated for and
+// resolves its merge fields, returning them with the record's property.
+func (s *documentService) mergeFieldsFor(
+	ctx context.Context,
+	input InstantiateDocumentTemplateInput,
+) (map[string]string, string, error) {
+	switch {
+	case input.LeaseTerminationID != nil:
+		if input.LeaseID == nil {
+			return nil, "", pkg.BadRequestError("LeaseIDRequiredForTermination", nil)
+		}
+
+		populate := make([]string, 0, len(leaseMergeFieldsPopulate))
+		for _, field := range leaseMergeFieldsPopulate {
+			populate = append(populate, "Lease."+field)
+		}
+		termination, err := s.leaseTerminationRepo.GetOne(ctx, repository.GetTerminatedLeaseQuery{
+			ID:       *input.LeaseTerminationID,
+			LeaseID:  *input.LeaseID,
+			Populate: &populate,
+		})
+		if err != nil {
+			return nil, "", mergeFieldSourceError(err, "LeaseTerminationNotFound", "fetching lease termination")
+		}
+
+		return leaseTerminationMergeFields(termination), termination.Lease.Unit.PropertyID, nil
+
+	case input.LeaseID != nil:
+		lease, err := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{
+			ID:       *input.LeaseID,
+			Populate: &leaseMergeFieldsPopulate,
+		})
+		if err != nil {
+			return nil, "", mergeFieldSourceError(err, "LeaseNotFound", "fetching lease")
+		}
+
+		return leaseMergeFields(lease, nil, nil), lease.Unit.PropertyID, nil
+
+	case input.TenantApplicationID != nil:
+		application, err := s.tenantApplicationRepo.GetOneWithQuery(ctx, repository.GetTenantApplicationQuery{
+			TenantApplicationID: *input.TenantApplicationID,
+			Populate:            &tenantApplicationMergeFieldsPopulate,
+		})
+		if err != nil {
+			return nil, "", mergeFieldSourceError(err, "TenantApplicationNotFound", "fetching tenant application")
+		}
+
+		return tenantApplicationMergeFields(application, nil), application.DesiredUnit.PropertyID, nil
+
+	default:
+		return nil, "", pkg.BadRequestError("TemplateSourceRequired", nil)
+	}
+}
+
+func mergeFieldSourceError(err error, notFound, action string) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return pkg.NotFoundError(notFound, &pkg.RentLoopErrorParams{Err: err})
+	}
+
+	return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		Err: err,
+		Metadata: map[string]string{
+			"function": "InstantiateTemplate",
+			"action":   action,
+		},
+	})
+}
+
+// resolveMergeFieldsInJSON is documentpdf.ResolveMergeFields over the
+// editor's raw JSON, turning each hashtag node with a value into a text node.
+func resolveMergeFieldsInJSON(node map[string]any, fields map[string]string) {
+	if text, ok := node["text"].(string); ok && node["type"] == "hashtag" {
+		if value := fields[strings.TrimPrefix(text, "#")]; value != "" {
+			node["type"] = "text"
+			node["text"] = value
+		}
+	}
+
+	if root, ok := node["root"].(map[string]any); ok {
+		resolveMergeFieldsInJSON(root, fields)
+	}
+	children, _ := node["children"].([]any)
+	for _, child := range children {
+		if childNode, ok := child.(map[string]any); ok {
+			resolveMergeFieldsInJSON(childNode, fields)
+		}
+	}
+}
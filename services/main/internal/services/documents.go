@@ -27,17 +27,39 @@ type DocumentService interface {
 		ctx context.Context,
 		filterQuery repository.GetDocumentWithPopulateFilter,
 	) (*models.Document, error)
+	InstantiateTemplate(ctx context.Context, input InstantiateDocumentTemplateInput) (*models.Document, error)
+	CurrentRevision(ctx context.Context, documentID string) (*models.DocumentRevision, error)
+	ListRevisions(ctx context.Context, filter repository.ListDocumentRevisionsFilter) ([]models.DocumentRevision, error)
+	CountRevisions(ctx context.Context, filter repository.ListDocumentRevisionsFilter) (int64, error)
+	GetRevision(ctx context.Context, query repository.GetDocumentRevisionQuery) (*models.DocumentRevision, error)
+	RestoreRevision(ctx context.Context, input RestoreDocumentRevisionInput) (*models.Document, error)
+	DiffRevisions(ctx context.Context, input DiffDocumentRevisionsInput) (*DocumentRevisionDiff, error)
 }
 
 type documentService struct {
-	appCtx pkg.AppContext
-	repo   repository.DocumentRepository
+	appCtx                pkg.AppContext
+	repo                  repository.DocumentRepository
+	revisionRepo          repository.DocumentRevisionRepository
+	leaseRepo             repository.LeaseRepository
+	tenantApplicationRepo repository.TenantApplicationRepository
+	leaseTerminationRepo  repository.LeaseTerminationRepository
 }
 
-func NewDocumentService(appCtx pkg.AppContext, repo repository.DocumentRepository) DocumentService {
+func NewDocumentService(
+	appCtx pkg.AppContext,
+	repo repository.DocumentRepository,
+	revisionRepo repository.DocumentRevisionRepository,
+	leaseRepo repository.LeaseRepository,
+	tenantApplicationRepo repository.TenantApplicationRepository,
+	leaseTerminationRepo repository.LeaseTerminationRepository,
+) DocumentService {
 	return &documentService{
-		appCtx: appCtx,
-		repo:   repo,
+		appCtx:                appCtx,
+		repo:                  repo,
+		revisionRepo:          revisionRepo,
+		leaseRepo:             leaseRepo,
+		tenantApplicationRepo: tenantApplicationRepo,
+		leaseTerminationRepo:  leaseTerminationRepo,
 	}
 }
 
@@ -81,14 +103,8 @@ func (s *documentService) Create(
 		CreatedByID: input.ClientUserID,
 	}
 
-	if err := s.repo.Create(ctx, document); err != nil {
-		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
-			Err: err,
-			Metadata: map[string]string{
-				"function": "CreateDocument",
-				"action":   "creating new document record",
-			},
-		})
+	if err := s.createWithRevision(ctx, document, "CREATED", input.ClientUserID); err != nil {
+		return nil, err
 	}
 
 	return document, nil
@@ -105,25 +121,25 @@ type UpdateDocumentInput struct {
 	ClientUserID *string
 }
 
+// Update applies input to the document, recording a new revision when its
+// title or content changes.
 func (s *documentService) Update(
 	ctx context.Context,
 	input UpdateDocumentInput,
 ) (*models.Document, error) {
-	document, err := s.repo.GetByID(ctx, input.DocumentID)
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	document, err := s.repo.GetByIDForUpdate(transCtx, input.DocumentID)
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, pkg.NotFoundError("DocumentNotFound", &pkg.RentLoopErrorParams{
-				Err: err,
-			})
-		}
+		transaction.Rollback()
+		return nil, documentFetchError(err, "UpdateDocument")
+	}
 
-		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
-			Err: err,
-			Metadata: map[string]string{
-				"function": "UpdateDocument",
-				"action":   "fetching document by ID",
-			},
-		})
+	previous, previousErr := s.currentRevision(transCtx, document)
+	if previousErr != nil {
+		transaction.Rollback()
+		return nil, previousErr
 	}
 
 	if input.Title != nil {
@@ -133,6 +149,7 @@ func (s *documentService) Update(
 	if input.Content != nil {
 		contentBytes, contentBytesErr := json.Marshal(*input.Content)
 		if contentBytesErr != nil {
+			transaction.Rollback()
 			return nil, pkg.InternalServerError(contentBytesErr.Error(), &pkg.RentLoopErrorParams{
 				Err: contentBytesErr,
 				Metadata: map[string]string{
@@ -161,7 +178,15 @@ func (s *documentService) Update(
 		document.UpdatedByID = input.ClientUserID
 	}
 
-	if err := s.repo.Update(ctx, document); err != nil {
+	if document.Title != previous.Title || documentContentHash(document.Content) != previous.ContentHash {
+		if _, recordErr := s.recordRevision(transCtx, document, "UPDATED", input.ClientUserID, nil); recordErr != nil {
+			transaction.Rollback()
+			return nil, recordErr
+		}
+	}
+
+	if err := s.repo.Update(transCtx, document); err != nil {
+		transaction.Rollback()
 		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
 			Err: err,
 			Metadata: map[string]string{
@@ -171,6 +196,17 @@ func (s *documentService) Update(
 		})
 	}
 
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err: commitErr,
+			Metadata: map[string]string{
+				"function": "UpdateDocument",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
 	return document, nil
 }
 
@@ -29,6 +29,8 @@ type ExpenseService interface {
 		filters repository.ListExpensesFilter,
 	) (int64, error)
 	DeleteExpense(ctx context.Context, expenseID string) error
+	// SettleExpense records a scheduled expense as paid and posts it.
+	SettleExpense(ctx context.Context, input SettleExpenseInput) (*models.Expense, error)
 }
 
 type expenseService struct {
@@ -66,6 +68,7 @@ type AddExpenseInput struct {
 	Description                 string
 	Amount                      int64
 	Currency                    string
+	ScheduledFor                *time.Time
 	ClientUserID                string
 }
 
@@ -99,8 +102,13 @@ func (s *expenseService) AddExpense(ctx context.Context, input AddExpenseInput)
 		Description:                 input.Description,
 		Amount:                      input.Amount,
 		Currency:                    currency,
+		ScheduledFor:                input.ScheduledFor,
 		CreatedByClientUserID:       input.ClientUserID,
 	}
+	if expense.ScheduledFor == nil {
+		now := time.Now()
+		expense.PaidAt = &now
+	}
 
 	if err := s.repo.Create(ctx, expense); err != nil {
 		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
@@ -114,7 +122,61 @@ func (s *expenseService) AddExpense(ctx context.Context, input AddExpenseInput)
 
 	// Expenses used to reach Fincore by generating an invoice. Now that they
 	// bill nobody, they must post themselves — otherwise they would vanish
-	// from the landlord's books entirely and silently.
+	// from the landlord's books entirely and silently. A scheduled one has not
+	// left the account yet, so it is posted when it is settled instead.
+	if expense.PaidAt != nil {
+		if postErr := s.postExpenseJournalEntry(ctx, expense); postErr != nil {
+			log.WithError(postErr).WithField("expense_code", expense.Code).
+				Error("failed to post expense journal entry")
+		}
+	}
+
+	return expense, nil
+}
+
+type SettleExpenseInput struct {
+	ExpenseID  string
+	PropertyID string
+}
+
+// SettleExpense records that a scheduled expense has been paid. It stops
+// being a commitment the forecast subtracts and is posted to the books as of
+// now, as an unscheduled expense is when it is recorded.
+func (s *expenseService) SettleExpense(ctx context.Context, input SettleExpenseInput) (*models.Expense, error) {
+	expense, err := s.repo.GetOne(ctx, repository.GetExpenseQuery{ID: input.ExpenseID})
+	if err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, pkg.NotFoundError("expense not found", nil)
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "SettleExpense",
+				"action":   "fetching expense",
+			},
+		})
+	}
+	if expense.PropertyID != input.PropertyID {
+		return nil, pkg.NotFoundError("expense not found", nil)
+	}
+	if expense.ScheduledFor == nil {
+		return nil, pkg.BadRequestError("ExpenseAlreadyPaid", nil)
+	}
+
+	now := time.Now()
+	expense.PaidAt = &now
+	expense.ScheduledFor = nil
+
+	if err := s.repo.Update(ctx, expense); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "SettleExpense",
+				"action":   "updating expense",
+			},
+		})
+	}
+
 	if postErr := s.postExpenseJournalEntry(ctx, expense); postErr != nil {
 		log.WithError(postErr).WithField("expense_code", expense.Code).
 			Error("failed to post expense journal entry")
@@ -133,7 +195,7 @@ func (s *expenseService) AddExpense(ctx context.Context, input AddExpenseInput)
 // this record — the landlord may recharge more, less, or nothing.
 func (s *expenseService) postExpenseJournalEntry(ctx context.Context, expense *models.Expense) error {
 	accounts := s.appCtx.Config.ChartOfAccounts
-	transactionDate := time.Now().Format(time.RFC3339)
+	transactionDate := expense.PaidAt.Format(time.RFC3339)
 
 	_, err := s.accountingService.RecordInvoiceCreated(ctx, accounting.CreateJournalEntryRequest{
 		Status:          string(accounting.JournalEntryStatusPosted),
@@ -54,6 +54,9 @@ type AccountSummary struct {
 	TotalSettled      int64
 	OutstandingAmount int64
 	AvailableCredit   int64
+	// PaymentsByPayer attributes what has been paid to the tenant who paid
+	// it. Every payment still settles the shared account; this is only who.
+	PaymentsByPayer []repository.PayerPaymentTotal
 }
 
 type FinancialAccountService interface {
@@ -324,6 +327,14 @@ func (s *financialAccountService) Summary(
 		return nil, creditErr
 	}
 
+	byPayer, payerErr := s.repo.SumSuccessfulPaymentsByPayer(ctx, accountID)
+	if payerErr != nil {
+		return nil, pkg.InternalServerError(payerErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      payerErr,
+			Metadata: map[string]string{"function": "Summary", "action": "summing payments by payer"},
+		})
+	}
+
 	var charged, settled int64
 	for _, v := range views {
 		charged += v.Amount
@@ -337,6 +348,7 @@ func (s *financialAccountService) Summary(
 		TotalSettled:      settled,
 		OutstandingAmount: AccountBalance(views),
 		AvailableCredit:   credit,
+		PaymentsByPayer:   byPayer,
 	}, nil
 }
 
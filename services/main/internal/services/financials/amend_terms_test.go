@@ -0,0 +1,130 @@
+package financials
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+// An amendment effective on a period boundary applies from that very period.
+func TestAmendmentBoundaryOnPeriodStart(t *testing.T) {
+	got, err := AmendmentBoundary(mustDate(t, "2027-01-01"), mustDate(t, "2027-04-01"), "MONTHLY")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := mustDate(t, "2027-04-01"); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// Mid-period, the running period keeps its terms — it may already be on an
+// invoice — and the amendment starts at the next one.
+func TestAmendmentBoundaryMidPeriodRollsForward(t *testing.T) {
+	got, err := AmendmentBoundary(mustDate(t, "2027-01-01"), mustDate(t, "2027-04-15"), "MONTHLY")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := mustDate(t, "2027-05-01"); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// Boundaries are stepped from move-in, not snapped to the calendar: a tenancy
+// that started on the 10th is billed from the 10th.
+func TestAmendmentBoundaryFollowsMoveInAnniversary(t *testing.T) {
+	got, err := AmendmentBoundary(mustDate(t, "2027-01-10"), mustDate(t, "2027-03-01"), "MONTHLY")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := mustDate(t, "2027-03-10"); !got.Equal(want) {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+// OneTime has no periods to align to; the effective date is taken as given.
+func TestAmendmentBoundaryWithoutRecurrence(t *testing.T) {
+	effective := mustDate(t, "2027-04-15")
+	got, err := AmendmentBoundary(mustDate(t, "2027-01-01"), effective, "OneTime")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !got.Equal(effective) {
+		t.Errorf("got %s, want %s", got, effective)
+	}
+}
+
+// A stray 2099 sentinel must not spin through thousands of periods.
+func TestAmendmentBoundaryCapsSteps(t *testing.T) {
+	_, err := AmendmentBoundary(mustDate(t, "2027-01-01"), mustDate(t, "2099-01-01"), "MONTHLY")
+	if !errors.Is(err, ErrTermTooLong) {
+		t.Fatalf("got %v, want ErrTermTooLong", err)
+	}
+}
+
+// The rebuilt tail covers exactly the periods from the boundary to the end of
+// the amended term, named after the charge rather than always "Rent".
+func TestMaterialiseWindowFromBoundary(t *testing.T) {
+	got, err := MaterialiseWindow(MaterialiseWindowInput{
+		Name:      "Parking",
+		Category:  CategoryOther,
+		Amount:    15_000,
+		Currency:  "GHS",
+		Frequency: "MONTHLY",
+		From:      mustDate(t, "2027-05-01"),
+		Until:     mustDate(t, "2028-01-01"),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 8 {
+		t.Fatalf("got %d instances, want 8 (May to December)", len(got))
+	}
+	if got[0].Name != "Parking – May 2027" {
+		t.Errorf("got name %q, want %q", got[0].Name, "Parking – May 2027")
+	}
+	if got[0].Category != CategoryOther {
+		t.Errorf("got category %q, want %q", got[0].Category, CategoryOther)
+	}
+}
+
+func amendedInstance(t *testing.T, definitionID *string, periodStart string) models.ChargeInstance {
+	t.Helper()
+	id, _ := uuid.NewV4()
+	start := mustDate(t, periodStart)
+	instance := models.ChargeInstance{ChargeDefinitionID: definitionID, PeriodStart: &start}
+	instance.ID = id
+	return instance
+}
+
+// Only periods of the amended definitions, starting on or after the boundary,
+// are replaced. Earlier periods, other definitions and ad-hoc charges are the
+// record of what was agreed before and stay as they are.
+func TestSupersededInstancesPicksTheTail(t *testing.T) {
+	rent := "rent-definition"
+	deposit := "deposit-definition"
+	voidedAt := time.Now()
+
+	march := amendedInstance(t, &rent, "2027-03-01")
+	april := amendedInstance(t, &rent, "2027-04-01")
+	may := amendedInstance(t, &rent, "2027-05-01")
+	voided := amendedInstance(t, &rent, "2027-06-01")
+	voided.VoidedAt = &voidedAt
+	depositCharge := amendedInstance(t, &deposit, "2027-05-01")
+	adHoc := amendedInstance(t, nil, "2027-05-01")
+
+	got := supersededInstances(
+		[]models.ChargeInstance{march, april, may, voided, depositCharge, adHoc},
+		map[string]bool{rent: true},
+		mustDate(t, "2027-04-01"),
+	)
+
+	if len(got) != 2 {
+		t.Fatalf("got %d superseded, want 2 (April and May rent)", len(got))
+	}
+	if got[0].ID != april.ID || got[1].ID != may.ID {
+		t.Errorf("got %v and %v, want April and May rent", got[0].PeriodStart, got[1].PeriodStart)
+	}
+}
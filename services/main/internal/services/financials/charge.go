@@ -99,11 +99,37 @@ type RederiveRentInput struct {
 	StayDurationFrequency string
 }
 
+type AmendTermsInput struct {
+	FinancialAccountID string
+	LeaseID            string
+	// TermStart is the lease's move-in date. Periods are stepped from it to
+	// find where the amended terms can begin.
+	TermStart        time.Time
+	EffectiveDate    time.Time
+	TermEnd          time.Time // the amended move-out date
+	PaymentFrequency string
+	Currency         string
+	RentFee          int64 // the amended rent; an unchanged rent passes the current one
+	AddedCharges     []RecurringChargeInput
+}
+
+// RecurringChargeInput is a charge billed every period alongside the rent —
+// parking, a service charge.
+type RecurringChargeInput struct {
+	Name     string
+	Category string
+	Amount   int64
+}
+
 type ChargeService interface {
 	MaterialiseForAccount(ctx context.Context, input MaterialiseForAccountInput) error
 	CreateAdHoc(ctx context.Context, input CreateAdHocChargeInput) (*models.ChargeInstance, error)
 	VoidInstance(ctx context.Context, input VoidChargeInput) error
 	RederiveRent(ctx context.Context, input RederiveRentInput) error
+	// AmendTerms rebuilds a lease's recurring schedule from an amendment's
+	// effective date, leaving every period before it untouched. MUST be called
+	// inside a transaction.
+	AmendTerms(ctx context.Context, input AmendTermsInput) error
 	// ScopeUnassignedToLease gives an application's charges the contractual
 	// context of the lease that application became.
 	ScopeUnassignedToLease(ctx context.Context, financialAccountID, leaseID string) error
@@ -111,6 +137,10 @@ type ChargeService interface {
 	// renewal does not leave a second ACTIVE template behind and the account
 	// keeps exactly one answer to "what is the rent?".
 	CloseDefinitionsForLease(ctx context.Context, financialAccountID, leaseID string) error
+	// EndDefinitionsForLease closes a term's definitions at endDate and voids
+	// the instances they had already generated for periods from endDate on.
+	// MUST be called inside a transaction.
+	EndDefinitionsForLease(ctx context.Context, financialAccountID, leaseID string, endDate time.Time) error
 	ListViews(ctx context.Context, financialAccountID string) ([]ChargeView, error)
 	// ListInstances returns the persisted models. The transformation layer
 	// needs Name, Currency and VoidedAt, which ChargeView deliberately does
@@ -206,6 +236,96 @@ func (s *chargeService) CloseDefinitionsForLease(
 	return nil
 }
 
+// EndDefinitionsForLease is CloseDefinitionsForLease for a term that is being
+// replaced from a date rather than as a whole — a lease rolling into holdover.
+// The periods the old definitions generated past that date would otherwise
+// stay live and bill alongside whatever replaces them. As in AmendTerms, it is
+// refused when one of those periods has already been billed.
+func (s *chargeService) EndDefinitionsForLease(
+	ctx context.Context,
+	financialAccountID, leaseID string,
+	endDate time.Time,
+) error {
+	if err := s.assertOpen(ctx, financialAccountID); err != nil {
+		return err
+	}
+
+	activeStatus := "ACTIVE"
+	definitions, defErr := s.repo.ListDefinitions(ctx, repository.ListChargeDefinitionsFilter{
+		FinancialAccountID: &financialAccountID,
+		LeaseID:            &leaseID,
+		Status:             &activeStatus,
+	})
+	if defErr != nil {
+		return endDefinitionsInternalError(defErr, "listing definitions")
+	}
+
+	recurringIDs := make(map[string]bool)
+	for _, definition := range *definitions {
+		if definition.Frequency == "ONCE" || definition.StartDate == nil {
+			continue
+		}
+		recurringIDs[definition.ID.String()] = true
+	}
+
+	existing, listErr := s.repo.ListInstances(ctx, repository.ListChargeInstancesFilter{
+		FinancialAccountID: &financialAccountID,
+		LeaseID:            &leaseID,
+	})
+	if listErr != nil {
+		return endDefinitionsInternalError(listErr, "listing instances")
+	}
+
+	supersededIDs := make([]string, 0)
+	for _, instance := range supersededInstances(*existing, recurringIDs, endDate) {
+		supersededIDs = append(supersededIDs, instance.ID.String())
+	}
+
+	superseded, lockErr := s.repo.LockInstances(ctx, supersededIDs)
+	if lockErr != nil {
+		return endDefinitionsInternalError(lockErr, "locking superseded instances")
+	}
+
+	views := make([]ChargeView, 0, len(superseded))
+	for _, instance := range superseded {
+		views = append(views, ToChargeView(instance))
+	}
+	if HasDirtyInstances(views) {
+		return pkg.BadRequestError("EndedPeriodsAlreadyBilled", nil)
+	}
+
+	now := time.Now()
+	reason := "Lease term ended"
+	for i := range superseded {
+		instance := superseded[i]
+		instance.VoidedAt = &now
+		instance.VoidedReason = &reason
+		if updateErr := s.repo.UpdateInstance(ctx, &instance); updateErr != nil {
+			return endDefinitionsInternalError(updateErr, "voiding superseded instance")
+		}
+	}
+
+	for i := range *definitions {
+		definition := (*definitions)[i]
+		definition.Status = "CLOSED"
+		if recurringIDs[definition.ID.String()] {
+			definition.EndDate = &endDate
+		}
+		if updateErr := s.repo.UpdateDefinition(ctx, &definition); updateErr != nil {
+			return endDefinitionsInternalError(updateErr, "closing definition")
+		}
+	}
+
+	return nil
+}
+
+func endDefinitionsInternalError(err error, action string) error {
+	return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		Err:      err,
+		Metadata: map[string]string{"function": "EndDefinitionsForLease", "action": action},
+	})
+}
+
 func (s *chargeService) ScopeUnassignedToLease(ctx context.Context, financialAccountID, leaseID string) error {
 	if err := s.repo.ScopeUnassignedToLease(ctx, financialAccountID, leaseID); err != nil {
 		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
@@ -515,3 +635,213 @@ func (s *chargeService) RederiveRent(ctx context.Context, input RederiveRentInpu
 		SecurityDepositFee:    0, // deposit is untouched by a rent terms change
 	})
 }
+
+// AmendTerms is the mid-term counterpart of RederiveRent. Where RederiveRent
+// rebuilds a whole schedule and is refused once anything is billed, this only
+// replaces the periods starting on or after the amendment's boundary, so it is
+// refused only when one of THOSE has been billed.
+//
+// Each recurring definition of the lease is closed at the boundary and, if
+// the amended term still runs past it, reopened from there — at the amended
+// rent for the rent definition and at its existing amount otherwise. Closing
+// and reopening rather than editing in place is what keeps the old amount on
+// record for the periods it was billed at.
+func (s *chargeService) AmendTerms(ctx context.Context, input AmendTermsInput) error {
+	if err := s.assertOpen(ctx, input.FinancialAccountID); err != nil {
+		return err
+	}
+
+	boundary, boundaryErr := AmendmentBoundary(input.TermStart, input.EffectiveDate, input.PaymentFrequency)
+	if boundaryErr != nil {
+		return pkg.BadRequestError("LeaseTermTooLong", &pkg.RentLoopErrorParams{Err: boundaryErr})
+	}
+
+	activeStatus := "ACTIVE"
+	definitions, defErr := s.repo.ListDefinitions(ctx, repository.ListChargeDefinitionsFilter{
+		FinancialAccountID: &input.FinancialAccountID,
+		LeaseID:            &input.LeaseID,
+		Status:             &activeStatus,
+	})
+	if defErr != nil {
+		return amendTermsInternalError(defErr, "listing definitions")
+	}
+
+	recurring := make([]models.ChargeDefinition, 0, len(*definitions))
+	recurringIDs := make(map[string]bool)
+	for _, definition := range *definitions {
+		if definition.Frequency == "ONCE" || definition.StartDate == nil {
+			continue
+		}
+		recurring = append(recurring, definition)
+		recurringIDs[definition.ID.String()] = true
+	}
+
+	existing, listErr := s.repo.ListInstances(ctx, repository.ListChargeInstancesFilter{
+		FinancialAccountID: &input.FinancialAccountID,
+		LeaseID:            &input.LeaseID,
+	})
+	if listErr != nil {
+		return amendTermsInternalError(listErr, "listing instances")
+	}
+
+	supersededIDs := make([]string, 0)
+	for _, instance := range supersededInstances(*existing, recurringIDs, boundary) {
+		supersededIDs = append(supersededIDs, instance.ID.String())
+	}
+
+	// Lock before judging: issuance claims charges read-modify-write, and an
+	// invoice composed between the read above and the void below would be
+	// left pointing at a charge that no longer exists.
+	superseded, lockErr := s.repo.LockInstances(ctx, supersededIDs)
+	if lockErr != nil {
+		return amendTermsInternalError(lockErr, "locking superseded instances")
+	}
+
+	views := make([]ChargeView, 0, len(superseded))
+	for _, instance := range superseded {
+		views = append(views, ToChargeView(instance))
+	}
+	if HasDirtyInstances(views) {
+		return pkg.BadRequestError("AmendedPeriodsAlreadyBilled", nil)
+	}
+
+	now := time.Now()
+	reason := "Lease terms amended"
+	for i := range superseded {
+		instance := superseded[i]
+		instance.VoidedAt = &now
+		instance.VoidedReason = &reason
+		if updateErr := s.repo.UpdateInstance(ctx, &instance); updateErr != nil {
+			return amendTermsInternalError(updateErr, "voiding superseded instance")
+		}
+	}
+
+	windows := make([]MaterialiseWindowInput, 0, len(recurring)+len(input.AddedCharges))
+	reopened := make([]models.ChargeDefinition, 0, cap(windows))
+
+	for i := range recurring {
+		definition := recurring[i]
+		definition.Status = "CLOSED"
+		definition.EndDate = &boundary
+		if updateErr := s.repo.UpdateDefinition(ctx, &definition); updateErr != nil {
+			return amendTermsInternalError(updateErr, "closing definition")
+		}
+
+		amount := definition.Amount
+		if definition.Category == CategoryRent {
+			amount = input.RentFee
+		}
+
+		reopened = append(reopened, models.ChargeDefinition{
+			FinancialAccountID: input.FinancialAccountID,
+			LeaseID:            &input.LeaseID,
+			Name:               definition.Name,
+			Category:           definition.Category,
+			Amount:             amount,
+			Currency:           definition.Currency,
+			Frequency:          definition.Frequency,
+			Status:             "ACTIVE",
+		})
+	}
+
+	for _, added := range input.AddedCharges {
+		reopened = append(reopened, models.ChargeDefinition{
+			FinancialAccountID: input.FinancialAccountID,
+			LeaseID:            &input.LeaseID,
+			Name:               added.Name,
+			Category:           added.Category,
+			Amount:             added.Amount,
+			Currency:           input.Currency,
+			Frequency:          input.PaymentFrequency,
+			Status:             "ACTIVE",
+		})
+	}
+
+	// An amendment that shortens the term to end inside the running period
+	// closes everything and opens nothing.
+	if !boundary.Before(input.TermEnd) {
+		return nil
+	}
+
+	instances := make([]models.ChargeInstance, 0)
+	for i := range reopened {
+		definition := &reopened[i]
+		definition.StartDate = &boundary
+		if createErr := s.repo.CreateDefinition(ctx, definition); createErr != nil {
+			return amendTermsInternalError(createErr, "opening definition")
+		}
+
+		drafts, materialiseErr := MaterialiseWindow(MaterialiseWindowInput{
+			Name:      definition.Name,
+			Category:  definition.Category,
+			Amount:    definition.Amount,
+			Currency:  definition.Currency,
+			Frequency: definition.Frequency,
+			From:      boundary,
+			Until:     input.TermEnd,
+		})
+		if materialiseErr != nil {
+			return pkg.BadRequestError("LeaseTermTooLong", &pkg.RentLoopErrorParams{Err: materialiseErr})
+		}
+
+		definitionID := definition.ID.String()
+		for _, draft := range drafts {
+			periodStart := draft.PeriodStart
+			periodEnd := draft.PeriodEnd
+			instances = append(instances, models.ChargeInstance{
+				FinancialAccountID: input.FinancialAccountID,
+				LeaseID:            &input.LeaseID,
+				ChargeDefinitionID: &definitionID,
+				Name:               draft.Name,
+				Category:           draft.Category,
+				Amount:             draft.Amount,
+				Currency:           draft.Currency,
+				PeriodStart:        &periodStart,
+				PeriodEnd:          &periodEnd,
+				DueDate:            draft.DueDate,
+			})
+		}
+	}
+
+	if len(instances) == 0 {
+		return nil
+	}
+
+	if createErr := s.repo.CreateInstances(ctx, instances); createErr != nil {
+		return amendTermsInternalError(createErr, "creating instances")
+	}
+
+	return nil
+}
+
+// supersededInstances picks the live instances an amendment replaces: those
+// generated from one of the given recurring definitions whose period starts
+// on or after the boundary. Ad-hoc charges and one-off definitions such as
+// the deposit are never touched — they do not derive from the terms.
+func supersededInstances(
+	instances []models.ChargeInstance,
+	recurringDefinitionIDs map[string]bool,
+	boundary time.Time,
+) []models.ChargeInstance {
+	superseded := make([]models.ChargeInstance, 0)
+	for _, instance := range instances {
+		if instance.VoidedAt != nil || instance.ChargeDefinitionID == nil || instance.PeriodStart == nil {
+			continue
+		}
+		if !recurringDefinitionIDs[*instance.ChargeDefinitionID] {
+			continue
+		}
+		if instance.PeriodStart.Before(boundary) {
+			continue
+		}
+		superseded = append(superseded, instance)
+	}
+	return superseded
+}
+
+func amendTermsInternalError(err error, action string) error {
+	return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		Err:      err,
+		Metadata: map[string]string{"function": "AmendTerms", "action": action},
+	})
+}
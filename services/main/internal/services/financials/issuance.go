@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
 	log "github.com/sirupsen/logrus"
 )
@@ -15,6 +16,24 @@ type InvoiceComposer interface {
 	ComposeAccountInvoice(ctx context.Context, accountID string, claims []Claim, dueDate time.Time) error
 }
 
+// IssuancePlan is what the sweep would bill on one account at one instant:
+// the charges selected, the claim against each and the invoice's due date.
+type IssuancePlan struct {
+	Account *models.FinancialAccount
+	Charges []ChargeView
+	Claims  []Claim
+	DueDate time.Time
+	Total   int64
+}
+
+// PreviewIssuanceFilter narrows a preview. Every field is optional; an empty
+// filter previews every billable account, exactly as the cron would sweep.
+type PreviewIssuanceFilter struct {
+	ClientID           *string
+	PropertyID         *string
+	FinancialAccountID *string
+}
+
 type IssuanceService interface {
 	IssueDueInvoices(ctx context.Context, asOf time.Time) (issued int, failed int, err error)
 	// IssueDueInvoicesForAccount is the same sweep restricted to one account.
@@ -23,8 +42,24 @@ type IssuanceService interface {
 	IssueDueInvoicesForAccount(
 		ctx context.Context, accountID string, asOf time.Time,
 	) (issued int, failed int, err error)
+	// PreviewDueInvoices runs the sweep's selection without composing
+	// anything. It writes nothing, so it can be called for any asOf, as often
+	// as a PM likes.
+	PreviewDueInvoices(ctx context.Context, filter PreviewIssuanceFilter, asOf time.Time) ([]IssuancePlan, error)
+	// IssueDueShortCadenceInvoices is the sweep restricted to hourly and daily
+	// accounts with a charge already inside its lead window. It is cheap enough
+	// to run every time such a charge falls due, which the midnight cron is not.
+	IssueDueShortCadenceInvoices(ctx context.Context, asOf time.Time) (issued int, failed int, err error)
+	// NextShortCadenceIssuanceAt is when the short-cadence sweep next has
+	// anything to do, or nil when no hourly or daily charge is pending.
+	NextShortCadenceIssuanceAt(ctx context.Context, after time.Time) (*time.Time, error)
 }
 
+// ShortCadenceFrequencies are the rent frequencies billed finer than the daily
+// cron can keep up with. Charge definitions store the lease's
+// PaymentFrequency verbatim, so both spellings in use are listed.
+var ShortCadenceFrequencies = []string{"Hourly", "HOURLY", "Daily", "DAILY"}
+
 type issuanceService struct {
 	accounts repository.FinancialAccountRepository
 	charges  ChargeService
@@ -74,9 +109,38 @@ func (s *issuanceService) issue(
 		return 0, 0, err
 	}
 
+	return s.issueAccounts(ctx, *accounts, onlyAccountID, asOf)
+}
+
+// IssueDueShortCadenceInvoices differs from IssueDueInvoices only in where the
+// account list comes from. The repository returns just the accounts with a
+// pending hourly or daily charge in its window, so a run that has nothing to
+// do touches no ledger at all.
+func (s *issuanceService) IssueDueShortCadenceInvoices(ctx context.Context, asOf time.Time) (int, int, error) {
+	accounts, err := s.accounts.ListDueForIssuance(ctx, asOf, ShortCadenceFrequencies)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return s.issueAccounts(ctx, *accounts, "", asOf)
+}
+
+func (s *issuanceService) NextShortCadenceIssuanceAt(ctx context.Context, after time.Time) (*time.Time, error) {
+	return s.accounts.NextIssuanceAt(ctx, after, ShortCadenceFrequencies)
+}
+
+// issueAccounts plans and composes each account in turn. Its callers decide
+// which accounts to look at; everything from here on is shared, so the
+// short-cadence sweep bills exactly what the midnight cron would.
+func (s *issuanceService) issueAccounts(
+	ctx context.Context,
+	accounts []models.FinancialAccount,
+	onlyAccountID string,
+	asOf time.Time,
+) (int, int, error) {
 	var issued, failed int
 
-	for _, account := range *accounts {
+	for _, account := range accounts {
 		accountID := account.ID.String()
 
 		if onlyAccountID != "" && accountID != onlyAccountID {
@@ -91,37 +155,13 @@ func (s *issuanceService) issue(
 			continue
 		}
 
-		selected := SelectIssuableCharges(
-			views,
-			asOf,
-			RentBillingPolicy{
-				Cadence:  account.RentBillingCadence,
-				Interval: account.RentBillingInterval,
-			},
-			account.AutoIssueDaysBefore,
-		)
-		if len(selected) == 0 {
+		plan := PlanIssuance(&account, views, asOf)
+		if plan == nil {
 			continue
 		}
 
-		claims := make([]Claim, 0, len(selected))
-		for _, view := range selected {
-			claims = append(claims, Claim{
-				ChargeInstanceID: view.ID,
-				Amount:           view.UninvoicedAmount(),
-			})
-		}
-
-		// The earliest of everything selected, not selected[0]: one-offs are
-		// listed first but a damage charge raised mid-term can fall due after
-		// the rent it rides along with.
-		dueDate := selected[0].DueDate
-		for _, view := range selected[1:] {
-			if view.DueDate.Before(dueDate) {
-				dueDate = view.DueDate
-			}
-		}
-		if composeErr := s.composer.ComposeAccountInvoice(ctx, accountID, claims, dueDate); composeErr != nil {
+		composeErr := s.composer.ComposeAccountInvoice(ctx, accountID, plan.Claims, plan.DueDate)
+		if composeErr != nil {
 			log.WithError(composeErr).WithField("account_id", accountID).
 				Error("[Cron] failed to issue invoice")
 			failed++
@@ -132,3 +172,96 @@ func (s *issuanceService) issue(
 
 	return issued, failed, nil
 }
+
+// PreviewDueInvoices is the dry run behind the PM's "what goes out tonight"
+// view. It lists accounts and filters them exactly as issue does, and plans
+// each one through the same PlanIssuance, so a preview cannot drift from what
+// the cron would actually bill.
+//
+// An account whose charges cannot be read is an error here rather than a
+// logged skip: the cron can carry on without one ledger, but a preview that
+// silently omitted one would tell the PM nothing is going out when it is.
+func (s *issuanceService) PreviewDueInvoices(
+	ctx context.Context,
+	filter PreviewIssuanceFilter,
+	asOf time.Time,
+) ([]IssuancePlan, error) {
+	accounts, err := s.accounts.ListActiveForBilling(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	plans := make([]IssuancePlan, 0)
+
+	for i := range *accounts {
+		account := &(*accounts)[i]
+		accountID := account.ID.String()
+
+		if filter.FinancialAccountID != nil && accountID != *filter.FinancialAccountID {
+			continue
+		}
+		if filter.ClientID != nil && (account.ClientID == nil || *account.ClientID != *filter.ClientID) {
+			continue
+		}
+		if filter.PropertyID != nil && (account.PropertyID == nil || *account.PropertyID != *filter.PropertyID) {
+			continue
+		}
+
+		views, viewErr := s.charges.ListViews(ctx, accountID)
+		if viewErr != nil {
+			return nil, viewErr
+		}
+
+		if plan := PlanIssuance(account, views, asOf); plan != nil {
+			plans = append(plans, *plan)
+		}
+	}
+
+	return plans, nil
+}
+
+// PlanIssuance decides what one account would be billed at asOf, or nil when
+// nothing is due. Pure: both the sweep and the preview call it, which is what
+// keeps the two in step.
+func PlanIssuance(account *models.FinancialAccount, views []ChargeView, asOf time.Time) *IssuancePlan {
+	selected := SelectIssuableCharges(
+		views,
+		asOf,
+		RentBillingPolicy{
+			Cadence:  account.RentBillingCadence,
+			Interval: account.RentBillingInterval,
+		},
+		account.AutoIssueDaysBefore,
+	)
+	if len(selected) == 0 {
+		return nil
+	}
+
+	var total int64
+	claims := make([]Claim, 0, len(selected))
+	for _, view := range selected {
+		claims = append(claims, Claim{
+			ChargeInstanceID: view.ID,
+			Amount:           view.UninvoicedAmount(),
+		})
+		total += view.UninvoicedAmount()
+	}
+
+	// The earliest of everything selected, not selected[0]: one-offs are
+	// listed first but a damage charge raised mid-term can fall due after
+	// the rent it rides along with.
+	dueDate := selected[0].DueDate
+	for _, view := range selected[1:] {
+		if view.DueDate.Before(dueDate) {
+			dueDate = view.DueDate
+		}
+	}
+
+	return &IssuancePlan{
+		Account: account,
+		Charges: selected,
+		Claims:  claims,
+		DueDate: dueDate,
+		Total:   total,
+	}
+}
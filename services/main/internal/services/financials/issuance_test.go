@@ -12,12 +12,30 @@ import (
 
 // ─── fakes ────────────────────────────────────────────────────────────────────
 
-type fakeAccountRepo struct{ accounts []models.FinancialAccount }
+type fakeAccountRepo struct {
+	accounts []models.FinancialAccount
+	// due is what ListDueForIssuance returns; the frequencies it was asked
+	// for are recorded so a test can see which sweep called it.
+	due            []models.FinancialAccount
+	dueFrequencies []string
+	next           *time.Time
+}
 
 func (f *fakeAccountRepo) ListActiveForBilling(context.Context) (*[]models.FinancialAccount, error) {
 	return &f.accounts, nil
 }
 
+func (f *fakeAccountRepo) ListDueForIssuance(
+	_ context.Context, _ time.Time, frequencies []string,
+) (*[]models.FinancialAccount, error) {
+	f.dueFrequencies = frequencies
+	return &f.due, nil
+}
+
+func (f *fakeAccountRepo) NextIssuanceAt(context.Context, time.Time, []string) (*time.Time, error) {
+	return f.next, nil
+}
+
 // Not exercised here: the closure sweep has its own coverage, and this fake
 // exists for issuance.
 func (f *fakeAccountRepo) ListDueForClosure(
@@ -37,6 +55,12 @@ func (f *fakeAccountRepo) SumSuccessfulPayments(context.Context, string) (int64,
 	return 0, nil
 }
 
+func (f *fakeAccountRepo) SumSuccessfulPaymentsByPayer(
+	context.Context, string,
+) ([]repository.PayerPaymentTotal, error) {
+	return nil, nil
+}
+
 type fakeChargeService struct{ views []ChargeView }
 
 func (f *fakeChargeService) ListViews(context.Context, string) ([]ChargeView, error) {
@@ -57,11 +81,16 @@ func (f *fakeChargeService) CloseDefinitionsForLease(context.Context, string, st
 	return nil
 }
 
+func (f *fakeChargeService) EndDefinitionsForLease(context.Context, string, string, time.Time) error {
+	return nil
+}
+
 func (f *fakeChargeService) ScopeUnassignedToLease(context.Context, string, string) error {
 	return nil
 }
 func (f *fakeChargeService) VoidInstance(context.Context, VoidChargeInput) error   { return nil }
 func (f *fakeChargeService) RederiveRent(context.Context, RederiveRentInput) error { return nil }
+func (f *fakeChargeService) AmendTerms(context.Context, AmendTermsInput) error     { return nil }
 func (f *fakeChargeService) ListInstances(
 	context.Context, string, *string, bool,
 ) ([]models.ChargeInstance, error) {
@@ -151,3 +180,77 @@ func TestIssueDueInvoicesUsesEarliestDueDate(t *testing.T) {
 		t.Errorf("got due date %s, want 2027-01-01", composer.dues[0])
 	}
 }
+
+// A preview must plan exactly what the sweep would issue and compose nothing.
+func TestPreviewDueInvoicesComposesNothing(t *testing.T) {
+	accounts := &fakeAccountRepo{accounts: []models.FinancialAccount{billableAccount(t)}}
+	charges := &fakeChargeService{views: rentMonths(t, []string{"2027-01-01", "2027-02-01"})}
+	composer := &recordingComposer{}
+	svc := NewIssuanceService(accounts, charges, composer)
+
+	plans, err := svc.PreviewDueInvoices(
+		context.Background(), PreviewIssuanceFilter{}, mustDate(t, "2026-12-28"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(composer.calls) != 0 {
+		t.Fatalf("preview composed %d invoices, want none", len(composer.calls))
+	}
+	if len(plans) != 1 || len(plans[0].Claims) != 1 {
+		t.Fatalf("got %d plans, want one plan of one claim", len(plans))
+	}
+	if plans[0].Total != 100_000 || !plans[0].DueDate.Equal(mustDate(t, "2027-01-01")) {
+		t.Errorf("got total=%d due=%s, want 100000 due 2027-01-01", plans[0].Total, plans[0].DueDate)
+	}
+}
+
+// Scoping happens on the account's denormalised property, so an account at
+// another property is left out of the preview entirely.
+func TestPreviewDueInvoicesScopesToProperty(t *testing.T) {
+	here, elsewhere := "property-a", "property-b"
+	inScope := billableAccount(t)
+	inScope.PropertyID = &here
+	outOfScope := billableAccount(t)
+	outOfScope.PropertyID = &elsewhere
+
+	accounts := &fakeAccountRepo{accounts: []models.FinancialAccount{inScope, outOfScope}}
+	charges := &fakeChargeService{views: rentMonths(t, []string{"2027-01-01"})}
+	svc := NewIssuanceService(accounts, charges, &recordingComposer{})
+
+	plans, err := svc.PreviewDueInvoices(
+		context.Background(), PreviewIssuanceFilter{PropertyID: &here}, mustDate(t, "2026-12-28"),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(plans) != 1 || plans[0].Account.ID != inScope.ID {
+		t.Fatalf("got %d plans, want only the in-scope account", len(plans))
+	}
+}
+
+// The short-cadence sweep bills only what the repository says is due for the
+// hourly and daily frequencies, never the full billable list the midnight cron
+// walks.
+func TestIssueDueShortCadenceInvoicesSweepsOnlyDueAccounts(t *testing.T) {
+	due := billableAccount(t)
+	due.AutoIssueDaysBefore = 0
+	accounts := &fakeAccountRepo{
+		accounts: []models.FinancialAccount{billableAccount(t), due},
+		due:      []models.FinancialAccount{due},
+	}
+	charges := &fakeChargeService{views: rentMonths(t, []string{"2027-01-01"})}
+	composer := &recordingComposer{}
+	svc := NewIssuanceService(accounts, charges, composer)
+
+	issued, failed, err := svc.IssueDueShortCadenceInvoices(context.Background(), mustDate(t, "2027-01-01"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if issued != 1 || failed != 0 {
+		t.Fatalf("got issued=%d failed=%d, want 1 and 0", issued, failed)
+	}
+	if len(accounts.dueFrequencies) != len(ShortCadenceFrequencies) {
+		t.Errorf("got frequencies %v, want %v", accounts.dueFrequencies, ShortCadenceFrequencies)
+	}
+}
@@ -31,14 +31,39 @@ type MaterialiseRentInput struct {
 // drafts, which is what lets a rent review close one definition and open
 // another without recomputing anything already invoiced.
 func MaterialiseRentInstances(in MaterialiseRentInput) ([]ChargeInstanceDraft, error) {
-	endDate := termEndDate(in.MoveInDate, in.StayDuration, in.StayDurationFrequency)
-	grace := lib.RentInvoiceGracePeriod(in.PaymentFrequency)
+	return MaterialiseWindow(MaterialiseWindowInput{
+		Name:      "Rent",
+		Category:  CategoryRent,
+		Amount:    in.RentFee,
+		Currency:  in.Currency,
+		Frequency: in.PaymentFrequency,
+		From:      in.MoveInDate,
+		Until:     termEndDate(in.MoveInDate, in.StayDuration, in.StayDurationFrequency),
+	})
+}
+
+type MaterialiseWindowInput struct {
+	Name      string // "Rent", "Parking"
+	Category  string
+	Amount    int64
+	Currency  string
+	Frequency string
+	From      time.Time
+	Until     time.Time
+}
+
+// MaterialiseWindow generates one draft per billing period starting in
+// [From, Until). It is the loop behind MaterialiseRentInstances, exposed so an
+// amendment can rebuild the tail of a schedule from its effective date without
+// regenerating the periods before it.
+func MaterialiseWindow(in MaterialiseWindowInput) ([]ChargeInstanceDraft, error) {
+	grace := lib.RentInvoiceGracePeriod(in.Frequency)
 
 	drafts := make([]ChargeInstanceDraft, 0, 12)
-	periodStart := in.MoveInDate
+	periodStart := in.From
 
-	for periodStart.Before(endDate) {
-		next := advance(periodStart, in.PaymentFrequency)
+	for periodStart.Before(in.Until) {
+		next := advance(periodStart, in.Frequency)
 		if next == nil {
 			// OneTime or an unrecognised frequency has no recurrence.
 			return []ChargeInstanceDraft{}, nil
@@ -50,9 +75,9 @@ func MaterialiseRentInstances(in MaterialiseRentInput) ([]ChargeInstanceDraft, e
 
 		periodEnd := next.Add(-24 * time.Hour)
 		drafts = append(drafts, ChargeInstanceDraft{
-			Name:        lib.RentInvoiceLabel(in.PaymentFrequency, periodStart),
-			Category:    CategoryRent,
-			Amount:      in.RentFee,
+			Name:        lib.ChargeInvoiceLabel(in.Name, in.Frequency, periodStart),
+			Category:    in.Category,
+			Amount:      in.Amount,
 			Currency:    in.Currency,
 			PeriodStart: periodStart,
 			PeriodEnd:   periodEnd,
@@ -65,6 +90,31 @@ func MaterialiseRentInstances(in MaterialiseRentInput) ([]ChargeInstanceDraft, e
 	return drafts, nil
 }
 
+// AmendmentBoundary is the first billing-period start on or after effective,
+// stepping from the start of the term.
+//
+// A period already running when an amendment takes effect keeps the terms it
+// started on: it may already be on an invoice, and splitting it would leave
+// the tenant holding a figure for a period that no longer exists. The new
+// terms therefore apply from the next period boundary. A frequency with no
+// recurrence has no boundaries, so the effective date is used as given.
+func AmendmentBoundary(termStart, effective time.Time, frequency string) (time.Time, error) {
+	boundary := termStart
+	for steps := 0; boundary.Before(effective); steps++ {
+		if steps >= maxRentPeriods {
+			return time.Time{}, ErrTermTooLong
+		}
+
+		next := advance(boundary, frequency)
+		if next == nil {
+			return effective, nil
+		}
+		boundary = *next
+	}
+
+	return boundary, nil
+}
+
 // termEndDate mirrors leaseEndDate in internal/services/lease.go. It is
 // duplicated rather than imported because this package must stay free of the
 // services package to avoid an import cycle.
@@ -0,0 +1,359 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"gorm.io/gorm"
+)
+
+type GuarantorService interface {
+	List(ctx context.Context, filter repository.ListGuarantorsFilter) (*[]models.Guarantor, error)
+	GetOne(ctx context.Context, query repository.GetGuarantorQuery) (*models.Guarantor, error)
+	Create(ctx context.Context, input CreateGuarantorInput) (*models.Guarantor, error)
+	Update(ctx context.Context, input UpdateGuarantorInput) (*models.Guarantor, error)
+	Delete(ctx context.Context, query repository.GetGuarantorQuery) error
+	// CarryToLease moves an approved application's guarantors onto its lease.
+	// Called inside the approval transaction.
+	CarryToLease(ctx context.Context, tenantApplicationID string, leaseID string) error
+}
+
+type guarantorService struct {
+	appCtx                pkg.AppContext
+	repo                  repository.GuarantorRepository
+	tenantApplicationRepo repository.TenantApplicationRepository
+	leaseRepo             repository.LeaseRepository
+	signingRepo           repository.SigningRepository
+}
+
+type GuarantorServiceDeps struct {
+	AppCtx                pkg.AppContext
+	Repo                  repository.GuarantorRepository
+	TenantApplicationRepo repository.TenantApplicationRepository
+	LeaseRepo             repository.LeaseRepository
+	SigningRepo           repository.SigningRepository
+}
+
+func NewGuarantorService(deps GuarantorServiceDeps) GuarantorService {
+	return &guarantorService{
+		appCtx:                deps.AppCtx,
+		repo:                  deps.Repo,
+		tenantApplicationRepo: deps.TenantApplicationRepo,
+		leaseRepo:             deps.LeaseRepo,
+		signingRepo:           deps.SigningRepo,
+	}
+}
+
+func (s *guarantorService) List(
+	ctx context.Context,
+	filter repository.ListGuarantorsFilter,
+) (*[]models.Guarantor, error) {
+	guarantors, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "ListGuarantors", "action": "listing guarantors"},
+		})
+	}
+
+	return guarantors, nil
+}
+
+func (s *guarantorService) GetOne(
+	ctx context.Context,
+	query repository.GetGuarantorQuery,
+) (*models.Guarantor, error) {
+	guarantor, err := s.repo.GetOne(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("GuarantorNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GetGuarantor", "action": "fetching guarantor"},
+		})
+	}
+
+	return guarantor, nil
+}
+
+// assertOwnerEditable checks that the application or lease a guarantor hangs
+// off can still take changes: an application while it is in progress, a lease
+// until it has ended. A guarantor can be added to a running lease — that is
+// usually how a tenant in arrears is kept on.
+func (s *guarantorService) assertOwnerEditable(ctx context.Context, owner repository.ListGuarantorsFilter) error {
+	if owner.LeaseID != nil {
+		lease, err := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{ID: *owner.LeaseID})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkg.NotFoundError("LeaseNotFound", &pkg.RentLoopErrorParams{Err: err})
+			}
+			return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err:      err,
+				Metadata: map[string]string{"function": "assertOwnerEditable", "action": "fetching lease"},
+			})
+		}
+		if lease.Status != "Lease.Status.Pending" && lease.Status != "Lease.Status.Active" {
+			return pkg.BadRequestError("LeaseGuarantorsNotEditable", nil)
+		}
+		return nil
+	}
+
+	if owner.TenantApplicationID != nil {
+		application, err := s.tenantApplicationRepo.GetOneWithQuery(ctx, repository.GetTenantApplicationQuery{
+			TenantApplicationID: *owner.TenantApplicationID,
+		})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return pkg.NotFoundError("TenantApplicationNotFound", &pkg.RentLoopErrorParams{Err: err})
+			}
+			return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err:      err,
+				Metadata: map[string]string{"function": "assertOwnerEditable", "action": "fetching application"},
+			})
+		}
+		if application.Status != "TenantApplication.Status.InProgress" {
+			return pkg.BadRequestError("TenantApplicationGuarantorsNotEditable", nil)
+		}
+		return nil
+	}
+
+	return pkg.BadRequestError("GuarantorOwnerRequired", nil)
+}
+
+// hasSigned reports whether the guarantor has signed anything — once they
+// have, what they signed is fixed.
+func (s *guarantorService) hasSigned(ctx context.Context, guarantorID string) (bool, error) {
+	count, err := s.signingRepo.CountDocumentSignatures(
+		ctx,
+		lib.FilterQuery{Page: 1, PageSize: 1},
+		repository.ListDocumentSignaturesFilter{GuarantorID: &guarantorID},
+	)
+	if err != nil {
+		return false, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "hasSigned", "action": "counting guarantor signatures"},
+		})
+	}
+
+	return count > 0, nil
+}
+
+type CreateGuarantorInput struct {
+	TenantApplicationID   *string
+	LeaseID               *string
+	FirstName             string
+	LastName              string
+	Email                 *string
+	Phone                 string
+	RelationshipToTenant  *string
+	IDType                *string
+	IDNumber              *string
+	IDFrontUrl            *string
+	IDBackUrl             *string
+	Occupation            *string
+	Employer              *string
+	ProofOfIncomeUrl      *string
+	LiabilityCap          *int64
+	LiabilityCapCurrency  *string
+	ArrearsEscalationDays *int64
+	CreatedById           string
+}
+
+func (s *guarantorService) Create(ctx context.Context, input CreateGuarantorInput) (*models.Guarantor, error) {
+	owner := repository.ListGuarantorsFilter{TenantApplicationID: input.TenantApplicationID, LeaseID: input.LeaseID}
+	if err := s.assertOwnerEditable(ctx, owner); err != nil {
+		return nil, err
+	}
+
+	if input.LiabilityCap != nil && input.LiabilityCapCurrency == nil {
+		return nil, pkg.BadRequestError("LiabilityCapCurrencyRequired", nil)
+	}
+
+	guarantor := models.Guarantor{
+		TenantApplicationID:   input.TenantApplicationID,
+		LeaseID:               input.LeaseID,
+		FirstName:             input.FirstName,
+		LastName:              input.LastName,
+		Email:                 input.Email,
+		Phone:                 input.Phone,
+		RelationshipToTenant:  input.RelationshipToTenant,
+		IDType:                input.IDType,
+		IDNumber:              input.IDNumber,
+		IDFrontUrl:            input.IDFrontUrl,
+		IDBackUrl:             input.IDBackUrl,
+		Occupation:            input.Occupation,
+		Employer:              input.Employer,
+		ProofOfIncomeUrl:      input.ProofOfIncomeUrl,
+		LiabilityCap:          input.LiabilityCap,
+		LiabilityCapCurrency:  input.LiabilityCapCurrency,
+		ArrearsEscalationDays: input.ArrearsEscalationDays,
+		CreatedById:           input.CreatedById,
+	}
+
+	if err := s.repo.Create(ctx, &guarantor); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "CreateGuarantor", "action": "creating guarantor"},
+		})
+	}
+
+	return &guarantor, nil
+}
+
+type UpdateGuarantorInput struct {
+	Query repository.GetGuarantorQuery
+
+	FirstName             *string
+	LastName              *string
+	Phone                 *string
+	Email                 lib.Optional[string]
+	RelationshipToTenant  lib.Optional[string]
+	IDType                lib.Optional[string]
+	IDNumber              lib.Optional[string]
+	IDFrontUrl            lib.Optional[string]
+	IDBackUrl             lib.Optional[string]
+	Occupation            lib.Optional[string]
+	Employer              lib.Optional[string]
+	ProofOfIncomeUrl      lib.Optional[string]
+	LiabilityCap          lib.Optional[int64]
+	LiabilityCapCurrency  lib.Optional[string]
+	ArrearsEscalationDays lib.Optional[int64]
+}
+
+// changesSignedTerms reports whether the update touches anything a signed
+// guarantee was given on. Only the escalation setting is ours rather than
+// theirs, so it is the one field that stays editable after signing.
+func (input UpdateGuarantorInput) changesSignedTerms() bool {
+	return input.FirstName != nil || input.LastName != nil || input.Phone != nil ||
+		input.Email.IsSet || input.RelationshipToTenant.IsSet ||
+		input.IDType.IsSet || input.IDNumber.IsSet || input.IDFrontUrl.IsSet || input.IDBackUrl.IsSet ||
+		input.Occupation.IsSet || input.Employer.IsSet || input.ProofOfIncomeUrl.IsSet ||
+		input.LiabilityCap.IsSet || input.LiabilityCapCurrency.IsSet
+}
+
+func (s *guarantorService) Update(ctx context.Context, input UpdateGuarantorInput) (*models.Guarantor, error) {
+	guarantor, err := s.GetOne(ctx, input.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerErr := s.assertOwnerEditable(ctx, input.Query.ListGuarantorsFilter); ownerErr != nil {
+		return nil, ownerErr
+	}
+
+	if input.changesSignedTerms() {
+		signed, signedErr := s.hasSigned(ctx, guarantor.ID.String())
+		if signedErr != nil {
+			return nil, signedErr
+		}
+		if signed {
+			return nil, pkg.BadRequestError("GuarantorAlreadySigned", nil)
+		}
+	}
+
+	if input.FirstName != nil {
+		guarantor.FirstName = *input.FirstName
+	}
+	if input.LastName != nil {
+		guarantor.LastName = *input.LastName
+	}
+	if input.Phone != nil {
+		guarantor.Phone = *input.Phone
+	}
+	if input.Email.IsSet {
+		guarantor.Email = input.Email.Value
+	}
+	if input.RelationshipToTenant.IsSet {
+		guarantor.RelationshipToTenant = input.RelationshipToTenant.Value
+	}
+	if input.IDType.IsSet {
+		guarantor.IDType = input.IDType.Value
+	}
+	if input.IDNumber.IsSet {
+		guarantor.IDNumber = input.IDNumber.Value
+	}
+	if input.IDFrontUrl.IsSet {
+		guarantor.IDFrontUrl = input.IDFrontUrl.Value
+	}
+	if input.IDBackUrl.IsSet {
+		guarantor.IDBackUrl = input.IDBackUrl.Value
+	}
+	if input.Occupation.IsSet {
+		guarantor.Occupation = input.Occupation.Value
+	}
+	if input.Employer.IsSet {
+		guarantor.Employer = input.Employer.Value
+	}
+	if input.ProofOfIncomeUrl.IsSet {
+		guarantor.ProofOfIncomeUrl = input.ProofOfIncomeUrl.Value
+	}
+	if input.LiabilityCap.IsSet {
+		guarantor.LiabilityCap = input.LiabilityCap.Value
+	}
+	if input.LiabilityCapCurrency.IsSet {
+		guarantor.LiabilityCapCurrency = input.LiabilityCapCurrency.Value
+	}
+	if input.ArrearsEscalationDays.IsSet {
+		guarantor.ArrearsEscalationDays = input.ArrearsEscalationDays.Value
+	}
+
+	if guarantor.LiabilityCap != nil && guarantor.LiabilityCapCurrency == nil {
+		return nil, pkg.BadRequestError("LiabilityCapCurrencyRequired", nil)
+	}
+	if guarantor.ArrearsEscalationDays != nil && *guarantor.ArrearsEscalationDays < 1 {
+		return nil, pkg.BadRequestError("InvalidArrearsEscalationDays", nil)
+	}
+
+	if updateErr := s.repo.Update(ctx, guarantor); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "UpdateGuarantor", "action": "updating guarantor"},
+		})
+	}
+
+	return guarantor, nil
+}
+
+func (s *guarantorService) Delete(ctx context.Context, query repository.GetGuarantorQuery) error {
+	guarantor, err := s.GetOne(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	if ownerErr := s.assertOwnerEditable(ctx, query.ListGuarantorsFilter); ownerErr != nil {
+		return ownerErr
+	}
+
+	signed, signedErr := s.hasSigned(ctx, guarantor.ID.String())
+	if signedErr != nil {
+		return signedErr
+	}
+	if signed {
+		return pkg.BadRequestError("GuarantorAlreadySigned", nil)
+	}
+
+	if deleteErr := s.repo.Delete(ctx, guarantor); deleteErr != nil {
+		return pkg.InternalServerError(deleteErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      deleteErr,
+			Metadata: map[string]string{"function": "DeleteGuarantor", "action": "deleting guarantor"},
+		})
+	}
+
+	return nil
+}
+
+func (s *guarantorService) CarryToLease(ctx context.Context, tenantApplicationID string, leaseID string) error {
+	if err := s.repo.CarryToLease(ctx, tenantApplicationID, leaseID); err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "CarryGuarantorsToLease", "action": "linking guarantors to lease"},
+		})
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+// After a guarantor has signed, their identity and cap are what the signature
+// vouches for. The escalation threshold is the landlord's own setting and must
+// stay editable, or a signed guarantor could never be chased.
+func TestGuarantorUpdateChangesSignedTerms(t *testing.T) {
+	days := int64(7)
+	onlyEscalation := UpdateGuarantorInput{
+		ArrearsEscalationDays: lib.Optional[int64]{Value: &days, IsSet: true},
+	}
+	if onlyEscalation.changesSignedTerms() {
+		t.Error("changing only the escalation threshold was treated as changing signed terms")
+	}
+
+	clearingCap := UpdateGuarantorInput{
+		LiabilityCap: lib.Optional[int64]{IsSet: true},
+	}
+	if !clearingCap.changesSignedTerms() {
+		t.Error("clearing the liability cap was not treated as changing signed terms")
+	}
+
+	name := "Ama"
+	renaming := UpdateGuarantorInput{FirstName: &name}
+	if !renaming.changesSignedTerms() {
+		t.Error("renaming the guarantor was not treated as changing signed terms")
+	}
+}
+
+// A lease document with guarantors is not signed until each of them has
+// signed; a guarantor's signature on some other lease does not count.
+func TestUnsignedGuarantors(t *testing.T) {
+	first := models.Guarantor{}
+	first.ID = uuid.Must(uuid.NewV4())
+	second := models.Guarantor{}
+	second.ID = uuid.Must(uuid.NewV4())
+	firstID := first.ID.String()
+
+	unsigned := unsignedGuarantors(
+		[]models.Guarantor{first, second},
+		[]models.DocumentSignature{{Role: "GUARANTOR", GuarantorID: &firstID}},
+	)
+	if len(unsigned) != 1 || unsigned[0] != second.ID.String() {
+		t.Errorf("got %v, want only the second guarantor outstanding", unsigned)
+	}
+
+	if got := unsignedGuarantors(nil, nil); len(got) != 0 {
+		t.Errorf("got %v, want nobody outstanding on a lease without guarantors", got)
+	}
+}
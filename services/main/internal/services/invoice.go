@@ -26,6 +26,12 @@ import (
 type InvoiceService interface {
 	CreateInvoice(context context.Context, input CreateInvoiceInput) (*models.Invoice, error)
 	VoidInvoice(context context.Context, input VoidInvoiceInput) (*models.Invoice, error)
+	// WriteOffInvoiceBalance gives up on what is still owed on an issued
+	// invoice, settling it for what has been paid.
+	WriteOffInvoiceBalance(ctx context.Context, input WriteOffInvoiceBalanceInput) (*models.Invoice, error)
+	// ReopenInvoice takes an issued invoice nothing has been paid against back
+	// to DRAFT, so what it asks for can be corrected and issued again.
+	ReopenInvoice(ctx context.Context, input ReopenInvoiceInput) (*models.Invoice, error)
 	UpdateInvoice(context context.Context, input UpdateInvoiceInput) (*models.Invoice, error)
 	DeleteInvoice(context context.Context, invoiceID string) error
 	GetByQuery(context context.Context, query repository.GetInvoiceQuery) (*models.Invoice, error)
@@ -54,6 +60,9 @@ type InvoiceService interface {
 		claims []financials.Claim,
 		dueDate time.Time,
 	) error
+	// PreviewIssuance is a dry run of the issuance sweep: what would be
+	// invoiced, to whom, at asOf. Nothing is reserved, created or sent.
+	PreviewIssuance(ctx context.Context, input PreviewIssuanceInput) ([]IssuancePreview, error)
 }
 
 type invoiceService struct {
@@ -485,11 +494,20 @@ func (s *invoiceService) UpdateInvoice(ctx context.Context, input UpdateInvoiceI
 	}
 
 	if issuingNow {
-		transaction := s.appCtx.DB.Begin()
+		// Use an existing outer transaction if provided, otherwise start our own
+		outerTx, hasOuterTx := lib.TransactionFromContext(ctx)
+		var transaction *gorm.DB
+		if hasOuterTx && outerTx != nil {
+			transaction = outerTx
+		} else {
+			transaction = s.appCtx.DB.Begin()
+		}
 		transCtx := lib.WithTransaction(ctx, transaction)
 
 		if updateErr := s.repo.Update(transCtx, invoice); updateErr != nil {
-			transaction.Rollback()
+			if !hasOuterTx {
+				transaction.Rollback()
+			}
 			return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
 				Err:      updateErr,
 				Metadata: map[string]string{"function": "UpdateInvoice", "action": "updating invoice to ISSUED"},
@@ -497,7 +515,9 @@ func (s *invoiceService) UpdateInvoice(ctx context.Context, input UpdateInvoiceI
 		}
 
 		if journalErr := s.recordIssuanceEntry(transCtx, invoice); journalErr != nil {
-			transaction.Rollback()
+			if !hasOuterTx {
+				transaction.Rollback()
+			}
 			return nil, pkg.InternalServerError("Failed to create journal entry for invoice", &pkg.RentLoopErrorParams{
 				Err: journalErr,
 				Metadata: map[string]string{
@@ -508,12 +528,14 @@ func (s *invoiceService) UpdateInvoice(ctx context.Context, input UpdateInvoiceI
 			})
 		}
 
-		if commitErr := transaction.Commit().Error; commitErr != nil {
-			transaction.Rollback()
-			return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
-				Err:      commitErr,
-				Metadata: map[string]string{"function": "UpdateInvoice", "action": "committing transaction"},
-			})
+		if !hasOuterTx {
+			if commitErr := transaction.Commit().Error; commitErr != nil {
+				transaction.Rollback()
+				return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+					Err:      commitErr,
+					Metadata: map[string]string{"function": "UpdateInvoice", "action": "committing transaction"},
+				})
+			}
 		}
 
 		return invoice, nil
@@ -639,6 +661,138 @@ func (s *invoiceService) GetByQuery(ctx context.Context, query repository.GetInv
 	return invoice, nil
 }
 
+type ReopenInvoiceInput struct {
+	InvoiceID string
+	Reason    string
+}
+
+// ReopenInvoice reverses an unpaid invoice's issuance so it can be corrected
+// as a draft. The payer has seen it, so anything still pending against it is
+// failed rather than left to settle an amount that is about to change. Only
+// invoices with nothing received qualify; once money has come in, what was
+// billed stands and a change is settled beside it.
+func (s *invoiceService) ReopenInvoice(ctx context.Context, input ReopenInvoiceInput) (*models.Invoice, error) {
+	invoice, getErr := s.repo.GetByQuery(ctx, repository.GetInvoiceQuery{
+		Query:    map[string]any{"id": input.InvoiceID},
+		Populate: &[]string{"LineItems", "Payments"},
+	})
+	if getErr != nil {
+		if errors.Is(getErr, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("InvoiceNotFound", &pkg.RentLoopErrorParams{Err: getErr})
+		}
+		return nil, pkg.InternalServerError(getErr.Error(), &pkg.RentLoopErrorParams{
+			Err: getErr,
+			Metadata: map[string]string{
+				"function": "ReopenInvoice",
+				"action":   "getting invoice",
+			},
+		})
+	}
+
+	if invoice.FinancialAccountID != nil {
+		return nil, pkg.BadRequestError("UseComposeEndpoint", nil)
+	}
+	if invoice.Status != "ISSUED" || invoiceAmountPaid(invoice) > 0 {
+		return nil, pkg.BadRequestError(
+			"Only issued invoices with nothing paid can be reopened",
+			&pkg.RentLoopErrorParams{
+				Metadata: map[string]string{
+					"function":       "ReopenInvoice",
+					"action":         "checking invoice status",
+					"current_status": invoice.Status,
+				},
+			},
+		)
+	}
+
+	originalLines := buildJournalEntryForInvoice(invoice, s.appCtx.Config.ChartOfAccounts)
+
+	now := time.Now()
+	invoice.Status = "DRAFT"
+	invoice.IssuedAt = nil
+
+	// Use an existing outer transaction if provided, otherwise start our own
+	outerTx, hasOuterTx := lib.TransactionFromContext(ctx)
+	var transaction *gorm.DB
+	if hasOuterTx && outerTx != nil {
+		transaction = outerTx
+	} else {
+		transaction = s.appCtx.DB.Begin()
+	}
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.repo.Update(transCtx, invoice); err != nil {
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ReopenInvoice",
+				"action":   "updating invoice",
+			},
+		})
+	}
+
+	if err := s.failPendingPayments(transCtx, invoice.ID.String(), "invoice reopened"); err != nil {
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
+		return nil, err
+	}
+
+	if len(originalLines) > 0 {
+		transactionDate := now.Format(time.RFC3339)
+
+		_, journalErr := s.accountingService.RecordInvoiceCreated(transCtx, accounting.CreateJournalEntryRequest{
+			Status:          string(accounting.JournalEntryStatusPosted),
+			Reference:       fmt.Sprintf("REOPEN-%s", invoice.Code),
+			TransactionDate: &transactionDate,
+			Metadata: map[string]any{
+				"invoice_id":      invoice.ID.String(),
+				"invoice_code":    invoice.Code,
+				"context_type":    invoice.ContextType,
+				"payer_type":      invoice.PayerType,
+				"payee_type":      invoice.PayeeType,
+				"client_id":       lib.SafeString(invoice.ClientID),
+				"property_id":     lib.SafeString(invoice.PropertyID),
+				"is_reversal":     true,
+				"reversal_reason": "INVOICE_REOPENED",
+				"reopen_reason":   input.Reason,
+				"original_ref":    invoice.Code,
+			},
+			Lines: buildReversingJournalEntry(originalLines),
+		})
+		if journalErr != nil {
+			if !hasOuterTx {
+				transaction.Rollback()
+			}
+			return nil, pkg.InternalServerError("Failed to create reversing journal entry", &pkg.RentLoopErrorParams{
+				Err: journalErr,
+				Metadata: map[string]string{
+					"function":    "ReopenInvoice",
+					"action":      "creating reversing journal entry",
+					"invoiceCode": invoice.Code,
+				},
+			})
+		}
+	}
+
+	if !hasOuterTx {
+		if commitErr := transaction.Commit().Error; commitErr != nil {
+			return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+				Err: commitErr,
+				Metadata: map[string]string{
+					"function": "ReopenInvoice",
+					"action":   "committing transaction",
+				},
+			})
+		}
+	}
+
+	return invoice, nil
+}
+
 type VoidInvoiceInput struct {
 	InvoiceID            string
 	VoidedReason         *string
@@ -669,8 +823,11 @@ func (s *invoiceService) VoidInvoice(ctx context.Context, input VoidInvoiceInput
 		})
 	}
 
-	if invoice.Status != "ISSUED" {
-		return nil, pkg.BadRequestError("Only issued invoices can be voided", &pkg.RentLoopErrorParams{
+	// A draft was never posted or shown to the payer, so voiding one has
+	// nothing to reverse and no one to tell.
+	wasIssued := invoice.Status == "ISSUED"
+	if !wasIssued && invoice.Status != "DRAFT" {
+		return nil, pkg.BadRequestError("Only draft or issued invoices can be voided", &pkg.RentLoopErrorParams{
 			Metadata: map[string]string{
 				"function":       "VoidInvoice",
 				"action":         "checking invoice status",
@@ -723,39 +880,14 @@ func (s *invoiceService) VoidInvoice(ctx context.Context, input VoidInvoiceInput
 	}
 
 	// delete all pending payments for the invoice
-	invoiceID := invoice.ID.String()
-	pendingPayments, pendingErr := s.paymentRepo.List(transCtx, repository.ListPaymentsFilter{
-		InvoiceID: &invoiceID,
-		Statuses:  &[]string{"PENDING"},
-	})
-	if pendingErr != nil {
+	if err := s.failPendingPayments(transCtx, invoice.ID.String(), "invoice voided"); err != nil {
 		transaction.Rollback()
-		return nil, pkg.InternalServerError(pendingErr.Error(), &pkg.RentLoopErrorParams{
-			Err: pendingErr,
-			Metadata: map[string]string{
-				"function":   "VoidInvoice",
-				"action":     "listing pending payments",
-				"invoice_id": invoiceID,
-			},
-		})
-	}
-	for i := range *pendingPayments {
-		if err := failOfflinePayment(transCtx, s.paymentRepo, &(*pendingPayments)[i], "invoice voided"); err != nil {
-			transaction.Rollback()
-			return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
-				Metadata: map[string]string{
-					"function":   "VoidInvoice",
-					"action":     "failing pending payment",
-					"invoice_id": invoiceID,
-					"payment_id": (*pendingPayments)[i].ID.String(),
-				},
-			})
-		}
+		return nil, err
 	}
 
 	// Create reversing journal entry to undo the original accounting entries
 	originalLines := buildJournalEntryForInvoice(invoice, s.appCtx.Config.ChartOfAccounts)
-	if len(originalLines) > 0 {
+	if wasIssued && len(originalLines) > 0 {
 		reversedLines := buildReversingJournalEntry(originalLines)
 		transactionDate := now.Format(time.RFC3339)
 		reversalReference := fmt.Sprintf("VOID-%s", invoice.Code)
@@ -802,7 +934,7 @@ func (s *invoiceService) VoidInvoice(ctx context.Context, input VoidInvoiceInput
 		})
 	}
 
-	if invoice.PayerLeaseID != nil && invoice.PayerLease != nil {
+	if wasIssued && invoice.PayerLeaseID != nil && invoice.PayerLease != nil {
 		tenantID := invoice.PayerLease.TenantId
 		invoiceCode := invoice.Code
 		go func() {
@@ -861,6 +993,201 @@ func (s *invoiceService) VoidInvoice(ctx context.Context, input VoidInvoiceInput
 	return invoice, nil
 }
 
+// failPendingPayments fails the payments still waiting on invoiceID, which
+// can no longer be paid. Must be called within a transaction context.
+func (s *invoiceService) failPendingPayments(ctx context.Context, invoiceID, reason string) error {
+	pendingPayments, pendingErr := s.paymentRepo.List(ctx, repository.ListPaymentsFilter{
+		InvoiceID: &invoiceID,
+		Statuses:  &[]string{"PENDING"},
+	})
+	if pendingErr != nil {
+		return pkg.InternalServerError(pendingErr.Error(), &pkg.RentLoopErrorParams{
+			Err: pendingErr,
+			Metadata: map[string]string{
+				"function":   "failPendingPayments",
+				"action":     "listing pending payments",
+				"invoice_id": invoiceID,
+			},
+		})
+	}
+	for i := range *pendingPayments {
+		if err := failOfflinePayment(ctx, s.paymentRepo, &(*pendingPayments)[i], reason); err != nil {
+			return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Metadata: map[string]string{
+					"function":   "failPendingPayments",
+					"action":     "failing pending payment",
+					"invoice_id": invoiceID,
+					"payment_id": (*pendingPayments)[i].ID.String(),
+				},
+			})
+		}
+	}
+
+	return nil
+}
+
+type WriteOffInvoiceBalanceInput struct {
+	InvoiceID string
+	// Amount is how much of the balance to give up on; 0 is all of it.
+	Amount                   int64
+	Reason                   string
+	WrittenOffByClientUserID *string
+}
+
+// WriteOffInvoiceBalance gives up on some or all of what is still owed on an
+// issued or part-paid invoice. Unlike voiding, the payments stand; only the
+// amount written off comes back out of income, and the invoice is settled
+// once nothing is left owing. Account-backed invoices carry their balance on
+// the account and are settled there.
+func (s *invoiceService) WriteOffInvoiceBalance(
+	ctx context.Context,
+	input WriteOffInvoiceBalanceInput,
+) (*models.Invoice, error) {
+	invoice, getErr := s.repo.GetByQuery(ctx, repository.GetInvoiceQuery{
+		Query:    map[string]any{"id": input.InvoiceID},
+		Populate: &[]string{"Payments"},
+	})
+	if getErr != nil {
+		if errors.Is(getErr, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("InvoiceNotFound", &pkg.RentLoopErrorParams{Err: getErr})
+		}
+		return nil, pkg.InternalServerError(getErr.Error(), &pkg.RentLoopErrorParams{
+			Err: getErr,
+			Metadata: map[string]string{
+				"function": "WriteOffInvoiceBalance",
+				"action":   "getting invoice",
+			},
+		})
+	}
+
+	if invoice.FinancialAccountID != nil {
+		return nil, pkg.BadRequestError("UseComposeEndpoint", nil)
+	}
+	if invoice.Status != "ISSUED" && invoice.Status != "PARTIALLY_PAID" {
+		return nil, pkg.BadRequestError(
+			"Only issued or partially paid invoices can be written off",
+			&pkg.RentLoopErrorParams{
+				Metadata: map[string]string{
+					"function":       "WriteOffInvoiceBalance",
+					"action":         "checking invoice status",
+					"current_status": invoice.Status,
+				},
+			},
+		)
+	}
+
+	balance := invoice.TotalAmount - invoice.WrittenOffAmount - invoiceAmountPaid(invoice)
+	if balance <= 0 {
+		return invoice, nil
+	}
+	// A partial write-off leaves the invoice open for the rest.
+	settled := input.Amount <= 0 || input.Amount >= balance
+	if !settled {
+		balance = input.Amount
+	}
+
+	now := time.Now()
+	if settled {
+		invoice.Status = "PAID"
+	}
+	invoice.WrittenOffAmount += balance
+	invoice.WrittenOffAt = &now
+	invoice.WrittenOffReason = &input.Reason
+	invoice.WrittenOffByClientUserID = input.WrittenOffByClientUserID
+
+	// Use an existing outer transaction if provided, otherwise start our own
+	outerTx, hasOuterTx := lib.TransactionFromContext(ctx)
+	var transaction *gorm.DB
+	if hasOuterTx && outerTx != nil {
+		transaction = outerTx
+	} else {
+		transaction = s.appCtx.DB.Begin()
+	}
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.repo.Update(transCtx, invoice); err != nil {
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "WriteOffInvoiceBalance",
+				"action":   "updating invoice",
+			},
+		})
+	}
+
+	if settled {
+		if err := s.failPendingPayments(transCtx, invoice.ID.String(), "invoice balance written off"); err != nil {
+			if !hasOuterTx {
+				transaction.Rollback()
+			}
+			return nil, err
+		}
+	}
+
+	// Take the balance back out the way issuing the invoice put it in.
+	writtenOff := *invoice
+	writtenOff.TotalAmount = balance
+	writtenOff.SubTotal = balance
+	writtenOff.LineItems = []models.InvoiceLineItem{{
+		Label:       fmt.Sprintf("Balance written off on invoice %s", invoice.Code),
+		Category:    "OTHER",
+		TotalAmount: balance,
+	}}
+	originalLines := buildJournalEntryForInvoice(&writtenOff, s.appCtx.Config.ChartOfAccounts)
+	if len(originalLines) > 0 {
+		transactionDate := now.Format(time.RFC3339)
+
+		_, journalErr := s.accountingService.RecordInvoiceCreated(transCtx, accounting.CreateJournalEntryRequest{
+			Status:          string(accounting.JournalEntryStatusPosted),
+			Reference:       fmt.Sprintf("WRITEOFF-%s", invoice.Code),
+			TransactionDate: &transactionDate,
+			Metadata: map[string]any{
+				"invoice_id":      invoice.ID.String(),
+				"invoice_code":    invoice.Code,
+				"context_type":    invoice.ContextType,
+				"payer_type":      invoice.PayerType,
+				"payee_type":      invoice.PayeeType,
+				"client_id":       lib.SafeString(invoice.ClientID),
+				"property_id":     lib.SafeString(invoice.PropertyID),
+				"is_reversal":     true,
+				"reversal_reason": "INVOICE_WRITTEN_OFF",
+				"original_ref":    invoice.Code,
+			},
+			Lines: buildReversingJournalEntry(originalLines),
+		})
+		if journalErr != nil {
+			if !hasOuterTx {
+				transaction.Rollback()
+			}
+			return nil, pkg.InternalServerError("Failed to create write-off journal entry", &pkg.RentLoopErrorParams{
+				Err: journalErr,
+				Metadata: map[string]string{
+					"function":    "WriteOffInvoiceBalance",
+					"action":      "creating write-off journal entry",
+					"invoiceCode": invoice.Code,
+				},
+			})
+		}
+	}
+
+	if !hasOuterTx {
+		if commitErr := transaction.Commit().Error; commitErr != nil {
+			return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+				Err: commitErr,
+				Metadata: map[string]string{
+					"function": "WriteOffInvoiceBalance",
+					"action":   "committing transaction",
+				},
+			})
+		}
+	}
+
+	return invoice, nil
+}
+
 func (s *invoiceService) ListInvoices(
 	ctx context.Context,
 	filterQuery repository.ListInvoicesFilter,
@@ -1030,12 +1357,21 @@ func (s *invoiceService) AddLineItem(ctx context.Context, input AddLineItemInput
 		Metadata:    metaJson,
 	}
 
-	transaction := s.appCtx.DB.Begin()
+	// Use an existing outer transaction if provided, otherwise start our own
+	outerTx, hasOuterTx := lib.TransactionFromContext(ctx)
+	var transaction *gorm.DB
+	if hasOuterTx && outerTx != nil {
+		transaction = outerTx
+	} else {
+		transaction = s.appCtx.DB.Begin()
+	}
 	transCtx := lib.WithTransaction(ctx, transaction)
 
 	createErr := s.repo.CreateLineItem(transCtx, &lineItem)
 	if createErr != nil {
-		transaction.Rollback()
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
 		return nil, pkg.BadRequestError(createErr.Error(), &pkg.RentLoopErrorParams{
 			Err: createErr,
 			Metadata: map[string]string{
@@ -1052,7 +1388,9 @@ func (s *invoiceService) AddLineItem(ctx context.Context, input AddLineItemInput
 
 	updateErr := s.repo.Update(transCtx, invoice)
 	if updateErr != nil {
-		transaction.Rollback()
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
 		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
 			Err: updateErr,
 			Metadata: map[string]string{
@@ -1062,15 +1400,17 @@ func (s *invoiceService) AddLineItem(ctx context.Context, input AddLineItemInput
 		})
 	}
 
-	if commitErr := transaction.Commit().Error; commitErr != nil {
-		transaction.Rollback()
-		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
-			Err: commitErr,
-			Metadata: map[string]string{
-				"function": "AddLineItem",
-				"action":   "committing transaction",
-			},
-		})
+	if !hasOuterTx {
+		if commitErr := transaction.Commit().Error; commitErr != nil {
+			transaction.Rollback()
+			return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+				Err: commitErr,
+				Metadata: map[string]string{
+					"function": "AddLineItem",
+					"action":   "committing transaction",
+				},
+			})
+		}
 	}
 
 	return &lineItem, nil
@@ -1147,13 +1487,22 @@ func (s *invoiceService) RemoveLineItem(ctx context.Context, input RemoveLineIte
 		})
 	}
 
-	transaction := s.appCtx.DB.Begin()
+	// Use an existing outer transaction if provided, otherwise start our own
+	outerTx, hasOuterTx := lib.TransactionFromContext(ctx)
+	var transaction *gorm.DB
+	if hasOuterTx && outerTx != nil {
+		transaction = outerTx
+	} else {
+		transaction = s.appCtx.DB.Begin()
+	}
 	transCtx := lib.WithTransaction(ctx, transaction)
 
 	// Delete line item
 	deleteErr := s.repo.DeleteLineItem(transCtx, input.LineItemID)
 	if deleteErr != nil {
-		transaction.Rollback()
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
 		return pkg.InternalServerError(deleteErr.Error(), &pkg.RentLoopErrorParams{
 			Err: deleteErr,
 			Metadata: map[string]string{
@@ -1170,7 +1519,9 @@ func (s *invoiceService) RemoveLineItem(ctx context.Context, input RemoveLineIte
 
 	updateErr := s.repo.Update(transCtx, invoice)
 	if updateErr != nil {
-		transaction.Rollback()
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
 		return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
 			Err: updateErr,
 			Metadata: map[string]string{
@@ -1180,15 +1531,17 @@ func (s *invoiceService) RemoveLineItem(ctx context.Context, input RemoveLineIte
 		})
 	}
 
-	if commitErr := transaction.Commit().Error; commitErr != nil {
-		transaction.Rollback()
-		return pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
-			Err: commitErr,
-			Metadata: map[string]string{
-				"function": "RemoveLineItem",
-				"action":   "committing transaction",
-			},
-		})
+	if !hasOuterTx {
+		if commitErr := transaction.Commit().Error; commitErr != nil {
+			transaction.Rollback()
+			return pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+				Err: commitErr,
+				Metadata: map[string]string{
+					"function": "RemoveLineItem",
+					"action":   "committing transaction",
+				},
+			})
+		}
 	}
 
 	return nil
@@ -1328,13 +1681,22 @@ func (s *invoiceService) UpdateLineItem(
 		lineItem.Metadata = json
 	}
 
-	transaction := s.appCtx.DB.Begin()
+	// Use an existing outer transaction if provided, otherwise start our own
+	outerTx, hasOuterTx := lib.TransactionFromContext(ctx)
+	var transaction *gorm.DB
+	if hasOuterTx && outerTx != nil {
+		transaction = outerTx
+	} else {
+		transaction = s.appCtx.DB.Begin()
+	}
 	transCtx := lib.WithTransaction(ctx, transaction)
 
 	// Update line item
 	updateErr := s.repo.UpdateLineItem(transCtx, lineItem)
 	if updateErr != nil {
-		transaction.Rollback()
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
 		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
 			Err: updateErr,
 			Metadata: map[string]string{
@@ -1351,7 +1713,9 @@ func (s *invoiceService) UpdateLineItem(
 
 	invoiceErr := s.repo.Update(transCtx, invoice)
 	if invoiceErr != nil {
-		transaction.Rollback()
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
 		return nil, pkg.InternalServerError(invoiceErr.Error(), &pkg.RentLoopErrorParams{
 			Err: invoiceErr,
 			Metadata: map[string]string{
@@ -1361,15 +1725,17 @@ func (s *invoiceService) UpdateLineItem(
 		})
 	}
 
-	if commitErr := transaction.Commit().Error; commitErr != nil {
-		transaction.Rollback()
-		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
-			Err: commitErr,
-			Metadata: map[string]string{
-				"function": "UpdateLineItem",
-				"action":   "committing transaction",
-			},
-		})
+	if !hasOuterTx {
+		if commitErr := transaction.Commit().Error; commitErr != nil {
+			transaction.Rollback()
+			return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+				Err: commitErr,
+				Metadata: map[string]string{
+					"function": "UpdateLineItem",
+					"action":   "committing transaction",
+				},
+			})
+		}
 	}
 
 	return lineItem, nil
@@ -1522,6 +1888,138 @@ func (s *invoiceService) ComposeAccountInvoice(
 	return err
 }
 
+type PreviewIssuanceInput struct {
+	ClientID   *string
+	PropertyID *string
+	AsOf       time.Time
+}
+
+type IssuancePreviewLine struct {
+	ChargeInstanceID string
+	Label            string
+	Category         string
+	Amount           int64
+	Currency         string
+	DueDate          time.Time
+}
+
+// IssuancePreviewRecipient is who CreateInvoice would notify, and over which
+// channels. Channels mirror the notification goroutine's own rules: nothing at
+// all without a tenant account, email only with an address, push and SMS
+// otherwise.
+type IssuancePreviewRecipient struct {
+	TenantID string
+	Name     string
+	Email    *string
+	Phone    string
+	Channels []string
+}
+
+type IssuancePreview struct {
+	Account      *models.FinancialAccount
+	PayerLeaseID *string
+	DueDate      time.Time
+	Currency     string
+	TotalAmount  int64
+	LineItems    []IssuancePreviewLine
+	// Recipient is nil for an application-stage account: no tenant exists
+	// yet, so the invoice would issue without anyone being notified.
+	Recipient *IssuancePreviewRecipient
+}
+
+// PreviewIssuance renders each plan the way ComposeAccountInvoice would
+// compose it. Labels and currency come from the persisted instances because
+// ChargeView deliberately does not carry them.
+func (s *invoiceService) PreviewIssuance(
+	ctx context.Context,
+	input PreviewIssuanceInput,
+) ([]IssuancePreview, error) {
+	plans, err := s.financials.Issuance.PreviewDueInvoices(ctx, financials.PreviewIssuanceFilter{
+		ClientID:   input.ClientID,
+		PropertyID: input.PropertyID,
+	}, input.AsOf)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "PreviewIssuance", "action": "planning issuance"},
+		})
+	}
+
+	previews := make([]IssuancePreview, 0, len(plans))
+	for _, plan := range plans {
+		accountID := plan.Account.ID.String()
+
+		instances, listErr := s.financials.Charges.ListInstances(ctx, accountID, nil, false)
+		if listErr != nil {
+			return nil, listErr
+		}
+		byID := make(map[string]models.ChargeInstance, len(instances))
+		for _, instance := range instances {
+			byID[instance.ID.String()] = instance
+		}
+
+		lines := make([]IssuancePreviewLine, 0, len(plan.Claims))
+		for i, claim := range plan.Claims {
+			instance := byID[claim.ChargeInstanceID]
+			lines = append(lines, IssuancePreviewLine{
+				ChargeInstanceID: claim.ChargeInstanceID,
+				Label:            instance.Name,
+				Category:         instance.Category,
+				Amount:           claim.Amount,
+				Currency:         instance.Currency,
+				DueDate:          plan.Charges[i].DueDate,
+			})
+		}
+
+		allViews, viewErr := s.financials.Charges.ListViews(ctx, accountID)
+		if viewErr != nil {
+			return nil, viewErr
+		}
+
+		previews = append(previews, IssuancePreview{
+			Account:      plan.Account,
+			PayerLeaseID: s.payerLeaseFor(ctx, accountID, allViews),
+			DueDate:      plan.DueDate,
+			Currency:     plan.Account.Currency,
+			TotalAmount:  plan.Total,
+			LineItems:    lines,
+			Recipient:    s.previewRecipient(ctx, plan.Account.TenantID),
+		})
+	}
+
+	return previews, nil
+}
+
+// previewRecipient resolves who would be notified. A lookup failure yields no
+// recipient rather than failing the preview — the notification goroutine
+// gives up the same way, so "nobody" is the honest answer.
+func (s *invoiceService) previewRecipient(ctx context.Context, tenantID *string) *IssuancePreviewRecipient {
+	if tenantID == nil {
+		return nil
+	}
+
+	tenant, err := s.tenantRepo.FindOne(ctx, map[string]any{"id": *tenantID})
+	if err != nil {
+		return nil
+	}
+
+	channels := []string{}
+	if _, accountErr := s.tenantAccountRepo.FindOne(ctx, map[string]any{"tenant_id": *tenantID}); accountErr == nil {
+		channels = append(channels, "PUSH", "SMS")
+		if tenant.Email != nil {
+			channels = append(channels, "EMAIL")
+		}
+	}
+
+	return &IssuancePreviewRecipient{
+		TenantID: *tenantID,
+		Name:     strings.TrimSpace(tenant.FirstName + " " + tenant.LastName),
+		Email:    tenant.Email,
+		Phone:    tenant.Phone,
+		Channels: channels,
+	}
+}
+
 func (s *invoiceService) GetLineItems(ctx context.Context, invoiceID string) ([]models.InvoiceLineItem, error) {
 	lineItems, err := s.repo.GetLineItems(ctx, invoiceID)
 	if err != nil {
@@ -1641,7 +2139,7 @@ func buildLeaseRentJournalEntry(
 	// Credit appropriate accounts based on line items
 	for _, lineItem := range invoice.LineItems {
 		switch lineItem.Category {
-		case "RENT", "OTHER", "BOOKING_FEE":
+		case "RENT", "OTHER", "BOOKING_FEE", "EXTRA_GUEST_FEE":
 			lines = append(lines, accounting.CreateJournalEntryLineRequest{
 				AccountID: accounts.RentalIncomeID,
 				Debit:     0,
@@ -1700,7 +2198,7 @@ func buildJournalEntryForInvoice(
 	switch invoice.ContextType {
 	case "TENANT_APPLICATION":
 		return buildTenantApplicationJournalEntry(invoice, accounts)
-	case "LEASE_RENT", "BOOKING_FEE":
+	case "LEASE_RENT", "BOOKING_FEE", "BOOKING_MODIFICATION":
 		return buildLeaseRentJournalEntry(invoice, accounts)
 	case "SAAS_FEE":
 		return buildSaasJournalEntry(invoice, accounts)
@@ -1938,7 +2436,7 @@ func buildPaymentJournalLines(
 	case "LEASE_TERMINATION":
 		return buildLeaseTerminationPaymentJournalLines(invoice, accounts)
 	default:
-		// TENANT_APPLICATION, LEASE_RENT, BOOKING_FEE, SAAS_FEE:
+		// TENANT_APPLICATION, LEASE_RENT, BOOKING_FEE, BOOKING_MODIFICATION, SAAS_FEE:
 		// cash received, AR cleared
 		return []accounting.CreateJournalEntryLineRequest{
 			{
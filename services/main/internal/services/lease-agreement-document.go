@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
@@ -23,14 +24,28 @@ type LeaseAgreementDocumentService interface {
 	DeleteLeaseAgreementDocument(ctx context.Context, leaseID string) error
 	FinalizeLeaseAgreementDocument(ctx context.Context, leaseID string) (*models.LeaseAgreementDocument, error)
 	RevertLeaseAgreementDocumentToDraft(ctx context.Context, leaseID string) (*models.LeaseAgreementDocument, error)
+	StoreSignedPdf(ctx context.Context, leaseID string)
 }
 
 type leaseAgreementDocumentService struct {
-	repo repository.LeaseAgreementDocumentRepository
+	appCtx          pkg.AppContext
+	repo            repository.LeaseAgreementDocumentRepository
+	leaseRepo       repository.LeaseRepository
+	documentService DocumentService
 }
 
-func NewLeaseAgreementDocumentService(repo repository.LeaseAgreementDocumentRepository) LeaseAgreementDocumentService {
-	return &leaseAgreementDocumentService{repo: repo}
+func NewLeaseAgreementDocumentService(
+	appCtx pkg.AppContext,
+	repo repository.LeaseAgreementDocumentRepository,
+	leaseRepo repository.LeaseRepository,
+	documentService DocumentService,
+) LeaseAgreementDocumentService {
+	return &leaseAgreementDocumentService{
+		appCtx:          appCtx,
+		repo:            repo,
+		leaseRepo:       leaseRepo,
+		documentService: documentService,
+	}
 }
 
 type CreateLeaseAgreementDocumentInput struct {
@@ -107,6 +122,7 @@ func (s *leaseAgreementDocumentService) UpdateLeaseAgreementDocument(
 		// clear the opposing field when switching modes
 		if *input.Mode == "ONLINE" {
 			doc.DocumentUrl = nil
+			doc.DocumentHash = nil
 		} else if *input.Mode == "MANUAL" {
 			doc.DocumentID = nil
 		}
@@ -116,6 +132,7 @@ func (s *leaseAgreementDocumentService) UpdateLeaseAgreementDocument(
 	}
 	if input.DocumentUrl != nil {
 		doc.DocumentUrl = input.DocumentUrl
+		doc.DocumentHash = nil
 	}
 
 	if err := s.repo.Update(ctx, doc); err != nil {
@@ -138,11 +155,16 @@ func (s *leaseAgreementDocumentService) DeleteLeaseAgreementDocument(ctx context
 	return nil
 }
 
+// FinalizeLeaseAgreementDocument locks the content and, for an ONLINE
+// document, pins its current revision and renders the unsigned PDF the
+// parties will review. The document stays a draft if rendering fails, so the
+// PM can simply try again, and while any merge field the lease cannot fill is
+// left in it.
 func (s *leaseAgreementDocumentService) FinalizeLeaseAgreementDocument(
 	ctx context.Context,
 	leaseID string,
 ) (*models.LeaseAgreementDocument, error) {
-	doc, err := s.repo.GetByLeaseID(ctx, leaseID, nil)
+	doc, err := s.repo.GetByLeaseID(ctx, leaseID, &[]string{"Document"})
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, pkg.NotFoundError("LeaseAgreementDocumentNotFound", &pkg.RentLoopErrorParams{Err: err})
@@ -153,6 +175,39 @@ func (s *leaseAgreementDocumentService) FinalizeLeaseAgreementDocument(
 		return nil, pkg.BadRequestError("LeaseAgreementDocumentAlreadyFinalized", nil)
 	}
 
+	finalizedAt := time.Now()
+	doc.FinalizedAt = &finalizedAt
+
+	if doc.Mode == "ONLINE" {
+		if doc.Document == nil {
+			return nil, pkg.BadRequestError("LeaseAgreementDocumentHasNoDocument", nil)
+		}
+
+		revision, revisionErr := s.documentService.CurrentRevision(ctx, doc.Document.ID.String())
+		if revisionErr != nil {
+			return nil, revisionErr
+		}
+		revisionID := revision.ID.String()
+		doc.DocumentRevisionID = &revisionID
+		doc.DocumentRevision = revision
+
+		lease, leaseErr := s.leaseForMergeFields(ctx, leaseID)
+		if leaseErr != nil {
+			return nil, leaseErr
+		}
+		fields := leaseMergeFields(lease, doc.FinalizedAt, nil)
+		if fieldsErr := checkMergeFieldsResolved(revision.Content, fields); fieldsErr != nil {
+			return nil, fieldsErr
+		}
+
+		url, hash, renderErr := s.renderPdf(ctx, doc, lease, nil)
+		if renderErr != nil {
+			return nil, renderErr
+		}
+		doc.DocumentUrl = &url
+		doc.DocumentHash = &hash
+	}
+
 	doc.Status = "FINALIZED"
 	if err := s.repo.Update(ctx, doc); err != nil {
 		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{Err: err})
@@ -175,7 +230,16 @@ func (s *leaseAgreementDocumentService) RevertLeaseAgreementDocumentToDraft(
 		return nil, pkg.BadRequestError("LeaseAgreementDocumentNotFinalized", nil)
 	}
 
+	// The rendered PDF is of the content being unlocked; it is rendered, and
+	// the revision pinned, afresh on the next finalize.
+	if doc.Mode == "ONLINE" {
+		doc.DocumentUrl = nil
+		doc.DocumentHash = nil
+		doc.DocumentRevisionID = nil
+	}
+
 	doc.Status = "DRAFT"
+	doc.FinalizedAt = nil
 	if err := s.repo.Update(ctx, doc); err != nil {
 		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{Err: err})
 	}
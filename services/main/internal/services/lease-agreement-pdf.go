@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/objectstorage"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/documentpdf"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+)
+
+// StoreSignedPdf renders the signed agreement and makes it the lease's
+// agreement document. It runs once the last party signs, away from their
+// request, so failures are logged rather than returned.
+func (s *leaseAgreementDocumentService) StoreSignedPdf(ctx context.Context, leaseID string) {
+	doc, err := s.repo.GetByLeaseID(ctx, leaseID, &[]string{
+		"Document",
+		"DocumentRevision",
+		"Signatures",
+		"Signatures.SignedBy.User",
+		"Signatures.Tenant",
+		"Signatures.Guarantor",
+	})
+	if err != nil {
+		log.WithError(err).WithField("lease_id", leaseID).Error("failed to load signed lease agreement for rendering")
+		return
+	}
+	if doc.Mode != "ONLINE" || doc.Status != "SIGNED" {
+		return
+	}
+
+	lease, leaseErr := s.leaseForMergeFields(ctx, leaseID)
+	if leaseErr != nil {
+		log.WithError(leaseErr).WithField("lease_id", leaseID).Error("failed to load lease for rendering")
+		return
+	}
+
+	url, hash, renderErr := s.renderPdf(ctx, doc, lease, doc.Signatures)
+	if renderErr != nil {
+		log.WithError(renderErr).WithField("lease_id", leaseID).Error("failed to render signed lease agreement")
+		return
+	}
+
+	doc.DocumentUrl = &url
+	doc.DocumentHash = &hash
+	if updateErr := s.repo.Update(ctx, doc); updateErr != nil {
+		log.WithError(updateErr).WithField("lease_id", leaseID).Error("failed to store signed lease agreement url")
+		return
+	}
+
+	if updateErr := s.leaseRepo.SetAgreementDocumentUrl(ctx, leaseID, url); updateErr != nil {
+		log.WithError(updateErr).WithField("lease_id", leaseID).Error("failed to set lease agreement url")
+	}
+}
+
+// leaseForMergeFields loads a lease with what leaseMergeFields reads.
+func (s *leaseAgreementDocumentService) leaseForMergeFields(
+	ctx context.Context,
+	leaseID string,
+) (*models.Lease, error) {
+	lease, err := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{
+		ID:       leaseID,
+		Populate: &leaseMergeFieldsPopulate,
+	})
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "leaseForMergeFields", "action": "fetching lease"},
+		})
+	}
+
+	return lease, nil
+}
+
+// renderPdf renders doc's document with the given signatures and uploads it,
+// returning its URL and SHA-256. doc.DocumentRevision must be loaded where doc
+// pins one, and doc.Document otherwise; lease as leaseForMergeFields loads it.
+func (s *leaseAgreementDocumentService) renderPdf(
+	ctx context.Context,
+	doc *models.LeaseAgreementDocument,
+	lease *models.Lease,
+	signatures []models.DocumentSignature,
+) (string, string, error) {
+	// Agreements finalized before documents had revisions render the
+	// document as it is.
+	var title string
+	var content []byte
+	switch {
+	case doc.DocumentRevision != nil:
+		title, content = doc.DocumentRevision.Title, doc.DocumentRevision.Content
+	case doc.Document != nil:
+		title, content = doc.Document.Title, doc.Document.Content
+	default:
+		return "", "", pkg.BadRequestError("LeaseAgreementDocumentHasNoDocument", nil)
+	}
+
+	rendered := make([]documentpdf.Signature, 0, len(signatures))
+	for _, signature := range signatures {
+		image, fetchErr := fetchImage(ctx, s.appCtx.Clients.ObjectStorage, signature.SignatureUrl)
+		if fetchErr != nil {
+			// A signed PDF missing a signature is the bug this renderer exists
+			// to fix, so it is better to have no PDF than that one.
+			return "", "", pkg.InternalServerError(fetchErr.Error(), &pkg.RentLoopErrorParams{
+				Err:      fetchErr,
+				Metadata: map[string]string{"function": "renderPdf", "action": "fetching signature image"},
+			})
+		}
+
+		rendered = append(rendered, documentpdf.Signature{
+			Role:               signature.Role,
+			Name:               signerName(signature),
+			SignedAt:           signature.CreatedAt,
+			IPAddress:          signature.IPAddress,
+			UserAgent:          lib.SafeString(signature.UserAgent),
+			VerificationMethod: signature.VerificationMethod,
+			ContentHash:        signature.ContentHash,
+			EventHash:          signature.EventHash,
+			Image:              image,
+		})
+	}
+
+	root, parseErr := documentpdf.ParseContent(content)
+	if parseErr != nil {
+		return "", "", pkg.BadRequestError(
+			"LeaseAgreementDocumentContentInvalid",
+			&pkg.RentLoopErrorParams{Err: parseErr},
+		)
+	}
+	images := map[string]documentpdf.Image{}
+	for _, src := range documentpdf.ImageSources(root) {
+		if image, fetchErr := fetchImage(ctx, s.appCtx.Clients.ObjectStorage, src); fetchErr == nil {
+			images[src] = image
+		}
+	}
+
+	pdf, renderErr := documentpdf.Render(documentpdf.RenderInput{
+		Title:      title,
+		Content:    content,
+		Fields:     leaseMergeFields(lease, doc.FinalizedAt, signatures),
+		Images:     images,
+		Signatures: rendered,
+	})
+	if renderErr != nil {
+		return "", "", pkg.InternalServerError(renderErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      renderErr,
+			Metadata: map[string]string{"function": "renderPdf", "action": "rendering pdf"},
+		})
+	}
+
+	url, uploadErr := s.appCtx.Clients.ObjectStorage.PutObject(ctx, objectstorage.PutObjectInput{
+		Key:         fmt.Sprintf("lease-agreements/%s-%s.pdf", lease.Code, time.Now().UTC().Format("20060102T150405Z")),
+		ContentType: "application/pdf",
+		Body:        pdf,
+	})
+	if uploadErr != nil {
+		return "", "", pkg.InternalServerError(uploadErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      uploadErr,
+			Metadata: map[string]string{"function": "renderPdf", "action": "uploading pdf"},
+		})
+	}
+
+	return url, sha256Hex(pdf), nil
+}
+
+// signerName is the name printed under a signature. Token signers type their
+// own name; otherwise it comes from whoever the signature belongs to.
+func signerName(signature models.DocumentSignature) string {
+	switch {
+	case signature.SignedByName != nil && *signature.SignedByName != "":
+		return *signature.SignedByName
+	case signature.SignedBy != nil:
+		return signature.SignedBy.User.Name
+	case signature.Tenant != nil:
+		return tenantFullName(*signature.Tenant)
+	case signature.Guarantor != nil:
+		return signature.Guarantor.FullName()
+	default:
+		return ""
+	}
+}
+
+// fetchImage loads an image from object storage, or decodes it from a data
+// URL, which is how some signature pads hand signatures over.
+func fetchImage(ctx context.Context, storage objectstorage.Client, url string) (documentpdf.Image, error) {
+	data, err := fetchFile(ctx, storage, url)
+	if err != nil {
+		return documentpdf.Image{}, err
+	}
+
+	imageType := documentpdf.DetectImageType(data)
+	if imageType == "" {
+		return documentpdf.Image{}, fmt.Errorf("unsupported image format")
+	}
+
+	return documentpdf.Image{Data: data, Type: imageType}, nil
+}
+
+// fetchFile loads a file from a base64 data URL or from object storage. The
+// URLs come from signers and callers, so nothing is fetched from anywhere
+// but our own bucket.
+func fetchFile(ctx context.Context, storage objectstorage.Client, url string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		_, encoded, found := strings.Cut(rest, ";base64,")
+		if !found {
+			return nil, fmt.Errorf("unsupported data url")
+		}
+		// Held to the same cap as an object fetched from storage, checked
+		// before decoding so an oversized payload is never allocated.
+		if len(encoded) > base64.StdEncoding.EncodedLen(objectstorage.MaxObjectBytes) {
+			return nil, fmt.Errorf("data url is larger than %d bytes", objectstorage.MaxObjectBytes)
+		}
+		decoded, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("decode data url: %w", err)
+		}
+		return decoded, nil
+	}
+
+	return storage.GetObject(ctx, url)
+}
@@ -0,0 +1,26 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/objectstorage"
+)
+
+// A signature handed over inline is held to the same cap as one read from
+// storage, and refused before it is decoded rather than after.
+func TestFetchFileCapsDataURLs(t *testing.T) {
+	small := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte("signature"))
+	data, err := fetchFile(context.Background(), nil, small)
+	if err != nil || string(data) != "signature" {
+		t.Fatalf("got %q, %v, want the decoded signature", data, err)
+	}
+
+	oversized := "data:image/png;base64," +
+		strings.Repeat("A", base64.StdEncoding.EncodedLen(objectstorage.MaxObjectBytes)+4)
+	if _, err := fetchFile(context.Background(), nil, oversized); err == nil {
+		t.Error("expected an oversized data url to be refused")
+	}
+}
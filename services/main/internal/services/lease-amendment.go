@@ -0,0 +1,504 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services/financials"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+type LeaseAmendmentService interface {
+	Create(ctx context.Context, input CreateLeaseAmendmentInput) (*models.LeaseAmendment, error)
+	GetOne(ctx context.Context, query repository.GetLeaseAmendmentQuery) (*models.LeaseAmendment, error)
+	List(ctx context.Context, filter repository.ListLeaseAmendmentsFilter) ([]models.LeaseAmendment, error)
+	Count(ctx context.Context, filter repository.ListLeaseAmendmentsFilter) (int64, error)
+	Update(ctx context.Context, input UpdateLeaseAmendmentInput) (*models.LeaseAmendment, error)
+	Execute(ctx context.Context, input ExecuteLeaseAmendmentInput) (*models.LeaseAmendment, error)
+	Cancel(ctx context.Context, input CancelLeaseAmendmentInput) error
+}
+
+type leaseAmendmentService struct {
+	appCtx      pkg.AppContext
+	repo        repository.LeaseAmendmentRepository
+	leaseRepo   repository.LeaseRepository
+	signingRepo repository.SigningRepository
+	financials  *financials.Financials
+}
+
+type LeaseAmendmentServiceDeps struct {
+	AppCtx      pkg.AppContext
+	Repo        repository.LeaseAmendmentRepository
+	LeaseRepo   repository.LeaseRepository
+	SigningRepo repository.SigningRepository
+	Financials  *financials.Financials
+}
+
+func NewLeaseAmendmentService(deps LeaseAmendmentServiceDeps) LeaseAmendmentService {
+	return &leaseAmendmentService{
+		appCtx:      deps.AppCtx,
+		repo:        deps.Repo,
+		leaseRepo:   deps.LeaseRepo,
+		signingRepo: deps.SigningRepo,
+		financials:  deps.Financials,
+	}
+}
+
+type CreateLeaseAmendmentInput struct {
+	LeaseID               string
+	Reason                string
+	EffectiveDate         time.Time
+	RentFee               *int64
+	StayDuration          *int64
+	StayDurationFrequency *string
+	AddedCharges          []models.LeaseAmendmentCharge
+	InitiatedById         string
+}
+
+func (s *leaseAmendmentService) Create(
+	ctx context.Context,
+	input CreateLeaseAmendmentInput,
+) (*models.LeaseAmendment, error) {
+	lease, err := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{ID: input.LeaseID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Create", "action": "fetching lease"},
+		})
+	}
+
+	if lease.Status != "Lease.Status.Active" {
+		return nil, pkg.BadRequestError("LeaseIsNotActive", nil)
+	}
+
+	amendment := &models.LeaseAmendment{
+		LeaseID:               input.LeaseID,
+		Reason:                input.Reason,
+		Status:                "LeaseAmendment.Status.Draft",
+		EffectiveDate:         input.EffectiveDate,
+		RentFee:               input.RentFee,
+		StayDuration:          input.StayDuration,
+		StayDurationFrequency: input.StayDurationFrequency,
+		AddedCharges:          input.AddedCharges,
+		InitiatedById:         input.InitiatedById,
+	}
+
+	if validateErr := validateLeaseAmendmentTerms(lease, amendment); validateErr != nil {
+		return nil, validateErr
+	}
+
+	if createErr := s.repo.Create(ctx, amendment); createErr != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(createErr, &pgErr) && pgErr.Code == "23505" {
+			return nil, pkg.BadRequestError("AmendmentAlreadyInDraft", nil)
+		}
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      createErr,
+			Metadata: map[string]string{"function": "Create", "action": "creating amendment"},
+		})
+	}
+
+	return amendment, nil
+}
+
+func (s *leaseAmendmentService) GetOne(
+	ctx context.Context,
+	query repository.GetLeaseAmendmentQuery,
+) (*models.LeaseAmendment, error) {
+	amendment, err := s.repo.GetOne(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseAmendmentNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GetOne", "action": "fetching amendment"},
+		})
+	}
+	return amendment, nil
+}
+
+func (s *leaseAmendmentService) List(
+	ctx context.Context,
+	filter repository.ListLeaseAmendmentsFilter,
+) ([]models.LeaseAmendment, error) {
+	result, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "List", "action": "listing amendments"},
+		})
+	}
+	return *result, nil
+}
+
+func (s *leaseAmendmentService) Count(
+	ctx context.Context,
+	filter repository.ListLeaseAmendmentsFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Count", "action": "counting amendments"},
+		})
+	}
+	return count, nil
+}
+
+type UpdateLeaseAmendmentInput struct {
+	ID      string
+	LeaseID string
+
+	Reason                *string
+	EffectiveDate         *time.Time
+	RentFee               lib.Optional[int64]
+	StayDuration          lib.Optional[int64]
+	StayDurationFrequency lib.Optional[string]
+	AddedCharges          *[]models.LeaseAmendmentCharge
+	DocumentMode          lib.Optional[string]
+	DocumentUrl           lib.Optional[string]
+	DocumentId            lib.Optional[string]
+}
+
+func (s *leaseAmendmentService) Update(
+	ctx context.Context,
+	input UpdateLeaseAmendmentInput,
+) (*models.LeaseAmendment, error) {
+	amendment, err := s.repo.GetOne(ctx, repository.GetLeaseAmendmentQuery{ID: input.ID, LeaseID: input.LeaseID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseAmendmentNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Update", "action": "fetching amendment"},
+		})
+	}
+
+	if amendment.Status != "LeaseAmendment.Status.Draft" {
+		return nil, pkg.BadRequestError("LeaseAmendmentIsNotDraft", nil)
+	}
+
+	if input.Reason != nil {
+		amendment.Reason = *input.Reason
+	}
+	if input.EffectiveDate != nil {
+		amendment.EffectiveDate = *input.EffectiveDate
+	}
+	if input.RentFee.IsSet {
+		amendment.RentFee = input.RentFee.Ptr()
+	}
+	if input.StayDuration.IsSet {
+		amendment.StayDuration = input.StayDuration.Ptr()
+	}
+	if input.StayDurationFrequency.IsSet {
+		amendment.StayDurationFrequency = input.StayDurationFrequency.Ptr()
+	}
+	if input.AddedCharges != nil {
+		amendment.AddedCharges = *input.AddedCharges
+	}
+	if input.DocumentMode.IsSet {
+		amendment.DocumentMode = input.DocumentMode.Ptr()
+	}
+	if input.DocumentUrl.IsSet {
+		amendment.DocumentUrl = input.DocumentUrl.Ptr()
+	}
+	if input.DocumentId.IsSet {
+		amendment.DocumentID = input.DocumentId.Ptr()
+	}
+
+	lease, leaseErr := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{ID: input.LeaseID})
+	if leaseErr != nil {
+		return nil, pkg.InternalServerError(leaseErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      leaseErr,
+			Metadata: map[string]string{"function": "Update", "action": "fetching lease"},
+		})
+	}
+
+	if validateErr := validateLeaseAmendmentTerms(lease, amendment); validateErr != nil {
+		return nil, validateErr
+	}
+
+	if updateErr := s.repo.Update(ctx, amendment); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Update", "action": "saving amendment"},
+		})
+	}
+
+	return amendment, nil
+}
+
+type ExecuteLeaseAmendmentInput struct {
+	ID           string
+	LeaseID      string
+	ClientUserID string
+}
+
+// Execute applies a signed amendment to its lease.
+//
+// The lease, its charge schedule and the amendment change together or not at
+// all: a lease showing the new rent over a schedule still billing the old one
+// is exactly the disagreement the amendment exists to resolve.
+func (s *leaseAmendmentService) Execute(
+	ctx context.Context,
+	input ExecuteLeaseAmendmentInput,
+) (*models.LeaseAmendment, error) {
+	amendment, err := s.repo.GetOne(ctx, repository.GetLeaseAmendmentQuery{ID: input.ID, LeaseID: input.LeaseID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseAmendmentNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Execute", "action": "fetching amendment"},
+		})
+	}
+
+	if amendment.Status != "LeaseAmendment.Status.Draft" {
+		return nil, pkg.BadRequestError("LeaseAmendmentIsNotDraft", nil)
+	}
+
+	lease, leaseErr := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{ID: input.LeaseID})
+	if leaseErr != nil {
+		if errors.Is(leaseErr, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseNotFound", &pkg.RentLoopErrorParams{Err: leaseErr})
+		}
+		return nil, pkg.InternalServerError(leaseErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      leaseErr,
+			Metadata: map[string]string{"function": "Execute", "action": "fetching lease"},
+		})
+	}
+
+	if lease.Status != "Lease.Status.Active" {
+		return nil, pkg.BadRequestError("LeaseIsNotActive", nil)
+	}
+
+	// Terms are re-checked against the lease as it is now, not as it was when
+	// the draft was written.
+	if validateErr := validateLeaseAmendmentTerms(lease, amendment); validateErr != nil {
+		return nil, validateErr
+	}
+
+	if docErr := s.assertAmendmentSigned(ctx, amendment); docErr != nil {
+		return nil, docErr
+	}
+
+	terms := amendedLeaseTerms(lease, amendment)
+
+	tx := s.appCtx.DB.Begin()
+	txCtx := lib.WithTransaction(ctx, tx)
+
+	if lease.FinancialAccountID != nil && lease.PaymentFrequency != nil {
+		addedCharges := make([]financials.RecurringChargeInput, 0, len(amendment.AddedCharges))
+		for _, charge := range amendment.AddedCharges {
+			addedCharges = append(addedCharges, financials.RecurringChargeInput{
+				Name:     charge.Name,
+				Category: charge.Category,
+				Amount:   charge.Amount,
+			})
+		}
+
+		if amendErr := s.financials.Charges.AmendTerms(txCtx, financials.AmendTermsInput{
+			FinancialAccountID: *lease.FinancialAccountID,
+			LeaseID:            lease.ID.String(),
+			TermStart:          lease.MoveInDate,
+			EffectiveDate:      amendment.EffectiveDate,
+			TermEnd:            terms.MoveOutDate,
+			PaymentFrequency:   *lease.PaymentFrequency,
+			Currency:           lease.RentFeeCurrency,
+			RentFee:            terms.RentFee,
+			AddedCharges:       addedCharges,
+		}); amendErr != nil {
+			tx.Rollback()
+			return nil, amendErr
+		}
+	}
+
+	now := time.Now()
+	version := lease.Version + 1
+
+	amendment.Status = "LeaseAmendment.Status.Executed"
+	amendment.Version = &version
+	amendment.PreviousRentFee = &lease.RentFee
+	amendment.PreviousStayDuration = &lease.StayDuration
+	amendment.PreviousStayDurationFrequency = &lease.StayDurationFrequency
+	amendment.PreviousMoveOutDate = lease.MoveOutDate
+	amendment.ExecutedAt = &now
+	amendment.ExecutedById = &input.ClientUserID
+
+	if updateErr := s.repo.Update(txCtx, amendment); updateErr != nil {
+		tx.Rollback()
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Execute", "action": "updating amendment status"},
+		})
+	}
+
+	// A moved end date makes the move-out reminders already sent wrong, so
+	// they are allowed to fire again against the new date.
+	if lease.MoveOutDate == nil || !lease.MoveOutDate.Equal(terms.MoveOutDate) {
+		lease.RemindersSent = pq.StringArray{}
+	}
+
+	lease.RentFee = terms.RentFee
+	lease.StayDuration = terms.StayDuration
+	lease.StayDurationFrequency = terms.StayDurationFrequency
+	lease.MoveOutDate = &terms.MoveOutDate
+	lease.Version = version
+
+	if leaseUpdateErr := s.leaseRepo.Update(txCtx, lease); leaseUpdateErr != nil {
+		tx.Rollback()
+		return nil, pkg.InternalServerError(leaseUpdateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      leaseUpdateErr,
+			Metadata: map[string]string{"function": "Execute", "action": "updating lease terms"},
+		})
+	}
+
+	if commitErr := tx.Commit().Error; commitErr != nil {
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      commitErr,
+			Metadata: map[string]string{"function": "Execute", "action": "committing transaction"},
+		})
+	}
+
+	return amendment, nil
+}
+
+// assertAmendmentSigned requires the amendment's document before execution.
+// A MANUAL document is trusted once uploaded, as on terminations; an ONLINE
+// one needs both the property manager's and the tenant's signature on it.
+func (s *leaseAmendmentService) assertAmendmentSigned(ctx context.Context, amendment *models.LeaseAmendment) error {
+	if amendment.DocumentMode == nil {
+		return pkg.BadRequestError("AmendmentDocumentRequired", nil)
+	}
+
+	if *amendment.DocumentMode == "MANUAL" {
+		if amendment.DocumentUrl == nil {
+			return pkg.BadRequestError("AmendmentDocumentRequired", nil)
+		}
+		return nil
+	}
+
+	if amendment.DocumentID == nil {
+		return pkg.BadRequestError("AmendmentDocumentRequired", nil)
+	}
+
+	for _, role := range []string{"PROPERTY_MANAGER", "TENANT"} {
+		_, sigErr := s.signingRepo.GetDocumentSignatureByQuery(ctx, map[string]any{
+			"document_id":        *amendment.DocumentID,
+			"lease_amendment_id": amendment.ID.String(),
+			"role":               role,
+		})
+		if sigErr != nil {
+			if errors.Is(sigErr, gorm.ErrRecordNotFound) {
+				return pkg.BadRequestError("AmendmentDocumentNotSigned", nil)
+			}
+			return pkg.InternalServerError(sigErr.Error(), &pkg.RentLoopErrorParams{
+				Err:      sigErr,
+				Metadata: map[string]string{"function": "Execute", "action": "checking amendment signatures"},
+			})
+		}
+	}
+
+	return nil
+}
+
+type CancelLeaseAmendmentInput struct {
+	ID           string
+	LeaseID      string
+	ClientUserID string
+}
+
+func (s *leaseAmendmentService) Cancel(ctx context.Context, input CancelLeaseAmendmentInput) error {
+	amendment, err := s.repo.GetOne(ctx, repository.GetLeaseAmendmentQuery{ID: input.ID, LeaseID: input.LeaseID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkg.NotFoundError("LeaseAmendmentNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Cancel", "action": "fetching amendment"},
+		})
+	}
+
+	if amendment.Status != "LeaseAmendment.Status.Draft" {
+		return pkg.BadRequestError("LeaseAmendmentIsNotDraft", nil)
+	}
+
+	now := time.Now()
+	amendment.Status = "LeaseAmendment.Status.Cancelled"
+	amendment.CancelledAt = &now
+	amendment.CancelledById = &input.ClientUserID
+
+	if updateErr := s.repo.Update(ctx, amendment); updateErr != nil {
+		return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Cancel", "action": "saving amendment"},
+		})
+	}
+
+	return nil
+}
+
+// leaseTerms is the part of a lease an amendment can change.
+type leaseTerms struct {
+	RentFee               int64
+	StayDuration          int64
+	StayDurationFrequency string
+	MoveOutDate           time.Time
+}
+
+// amendedLeaseTerms overlays an amendment on the lease's current terms and
+// recomputes the move-out date from them.
+func amendedLeaseTerms(lease *models.Lease, amendment *models.LeaseAmendment) leaseTerms {
+	terms := leaseTerms{
+		RentFee:               lease.RentFee,
+		StayDuration:          lease.StayDuration,
+		StayDurationFrequency: lease.StayDurationFrequency,
+	}
+
+	if amendment.RentFee != nil {
+		terms.RentFee = *amendment.RentFee
+	}
+	if amendment.StayDuration != nil {
+		terms.StayDuration = *amendment.StayDuration
+	}
+	if amendment.StayDurationFrequency != nil {
+		terms.StayDurationFrequency = *amendment.StayDurationFrequency
+	}
+
+	terms.MoveOutDate = leaseEndDate(lease.MoveInDate, terms.StayDuration, terms.StayDurationFrequency)
+	return terms
+}
+
+// validateLeaseAmendmentTerms rejects an amendment that changes nothing, or
+// whose effective date falls outside the term it amends.
+func validateLeaseAmendmentTerms(lease *models.Lease, amendment *models.LeaseAmendment) error {
+	if amendment.RentFee == nil && amendment.StayDuration == nil &&
+		amendment.StayDurationFrequency == nil && len(amendment.AddedCharges) == 0 {
+		return pkg.BadRequestError("AmendmentChangesNothing", nil)
+	}
+
+	if amendment.EffectiveDate.Before(lease.MoveInDate) {
+		return pkg.BadRequestError("AmendmentEffectiveBeforeMoveIn", nil)
+	}
+
+	terms := amendedLeaseTerms(lease, amendment)
+	if !amendment.EffectiveDate.Before(terms.MoveOutDate) {
+		return pkg.BadRequestError("AmendmentEffectiveAfterMoveOut", nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+func amendableLease() *models.Lease {
+	return &models.Lease{
+		RentFee:               500_000,
+		MoveInDate:            time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+		StayDuration:          12,
+		StayDurationFrequency: "Months",
+	}
+}
+
+// An extension restates the whole term from move-in; the move-out date is
+// recomputed from it rather than carried over.
+func TestAmendedLeaseTermsRecomputesMoveOut(t *testing.T) {
+	duration := int64(18)
+	terms := amendedLeaseTerms(amendableLease(), &models.LeaseAmendment{StayDuration: &duration})
+
+	if want := time.Date(2028, 7, 1, 0, 0, 0, 0, time.UTC); !terms.MoveOutDate.Equal(want) {
+		t.Errorf("got move-out %s, want %s", terms.MoveOutDate, want)
+	}
+	if terms.RentFee != 500_000 {
+		t.Errorf("got rent %d, want the current 500000 — the amendment did not change it", terms.RentFee)
+	}
+}
+
+// A rent-only amendment leaves the end date exactly where it was.
+func TestAmendedLeaseTermsRentOnlyKeepsMoveOut(t *testing.T) {
+	rent := int64(450_000)
+	terms := amendedLeaseTerms(amendableLease(), &models.LeaseAmendment{RentFee: &rent})
+
+	if terms.RentFee != 450_000 {
+		t.Errorf("got rent %d, want 450000", terms.RentFee)
+	}
+	if want := time.Date(2028, 1, 1, 0, 0, 0, 0, time.UTC); !terms.MoveOutDate.Equal(want) {
+		t.Errorf("got move-out %s, want %s", terms.MoveOutDate, want)
+	}
+}
+
+// An amendment with no changed term would bump the version for nothing.
+func TestValidateLeaseAmendmentTermsRejectsNoChange(t *testing.T) {
+	amendment := &models.LeaseAmendment{EffectiveDate: time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC)}
+
+	if err := validateLeaseAmendmentTerms(amendableLease(), amendment); err == nil {
+		t.Error("got nil, want AmendmentChangesNothing")
+	}
+}
+
+// Shortening the term to end before the amendment takes effect leaves nothing
+// for the new terms to apply to.
+func TestValidateLeaseAmendmentTermsRejectsEffectiveAfterAmendedEnd(t *testing.T) {
+	duration := int64(3)
+	amendment := &models.LeaseAmendment{
+		EffectiveDate: time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC),
+		StayDuration:  &duration,
+	}
+
+	if err := validateLeaseAmendmentTerms(amendableLease(), amendment); err == nil {
+		t.Error("got nil, want AmendmentEffectiveAfterMoveOut")
+	}
+}
+
+// Added parking alone is a real change.
+func TestValidateLeaseAmendmentTermsAcceptsAddedCharge(t *testing.T) {
+	amendment := &models.LeaseAmendment{
+		EffectiveDate: time.Date(2027, 6, 1, 0, 0, 0, 0, time.UTC),
+		AddedCharges:  []models.LeaseAmendmentCharge{{Name: "Parking", Category: "OTHER", Amount: 15_000}},
+	}
+
+	if err := validateLeaseAmendmentTerms(amendableLease(), amendment); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
@@ -387,38 +387,41 @@ func (s *leaseChecklistService) SubmitLeaseChecklist(
 		})
 	}
 
-	// Send push notification to tenant (fire-and-forget)
+	// Send push notification to everyone on the lease (fire-and-forget). Each
+	// co-tenant and occupant acknowledges for themselves.
 	go func() {
 		lease, leaseErr := s.leaseRepo.GetOneWithPopulate(context.Background(), repository.GetLeaseQuery{
 			ID:       leaseID,
-			Populate: &[]string{"Tenant"},
+			Populate: &[]string{"Tenants"},
 		})
 		if leaseErr != nil {
 			return
 		}
 
-		tenantAccount, taErr := s.tenantAccountRepo.FindOne(context.Background(), map[string]any{
-			"tenant_id": lease.TenantId,
-		})
-		if taErr != nil {
-			return
-		}
+		for _, leaseTenant := range lease.Tenants {
+			tenantAccount, taErr := s.tenantAccountRepo.FindOne(context.Background(), map[string]any{
+				"tenant_id": leaseTenant.TenantID,
+			})
+			if taErr != nil {
+				continue
+			}
 
-		_ = s.notificationService.SendToTenantAccount(
-			context.Background(),
-			tenantAccount.ID.String(),
-			"Report submitted for review",
-			fmt.Sprintf(
-				"Your landlord has submitted a %s report for your review. Please review and respond.",
-				lib.LeaseChecklistTypeLabel(checklist.Type),
-			),
-			map[string]string{
-				"type":           "CHECKLIST_SUBMITTED",
-				"checklist_id":   checklistID,
-				"lease_id":       leaseID,
-				"checklist_type": checklist.Type,
-			},
-		)
+			_ = s.notificationService.SendToTenantAccount(
+				context.Background(),
+				tenantAccount.ID.String(),
+				"Report submitted for review",
+				fmt.Sprintf(
+					"Your landlord has submitted a %s report for your review. Please review and respond.",
+					lib.LeaseChecklistTypeLabel(checklist.Type),
+				),
+				map[string]string{
+					"type":           "CHECKLIST_SUBMITTED",
+					"checklist_id":   checklistID,
+					"lease_id":       leaseID,
+					"checklist_type": checklist.Type,
+				},
+			)
+		}
 	}()
 
 	return checklist, nil
@@ -0,0 +1,246 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/services/financials"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// holdoverPeriodFrequency is the length of one holdover period, and the billing
+// cadence inside it, whatever the original term was paid at.
+const holdoverPeriodFrequency = "Months"
+
+// LeaseMoveOutOutcome is what the completion sweep will do with a lease once
+// its move-out passes.
+type LeaseMoveOutOutcome struct {
+	HoldsOver bool
+	// Rent is what the next holdover month bills. Only set when HoldsOver.
+	Rent int64
+}
+
+// leaseHoldsOver reports whether a lease past move-out rolls over instead of
+// completing.
+//
+// Only an Active lease qualifies: a Pending one never started, so there is no
+// tenancy to continue. A renewal that counts wins over the policy — the tenant
+// already has their next term, and holding the parent over would overlap it.
+// So does a termination that ends the tenancy by this move-out.
+func leaseHoldsOver(status, policy string, renewed, vacating bool) bool {
+	return status == "Lease.Status.Active" &&
+		policy == models.PropertyHoldoverPolicyMonthToMonth &&
+		!renewed &&
+		!vacating
+}
+
+// vacatesByMoveOut reports whether an open termination ends the tenancy by the
+// lease's current move-out. A notice to vacate for a later date still lets
+// the lease hold over until then; one started by the PM has no date and ends
+// it now.
+func vacatesByMoveOut(termination *models.LeaseTermination, moveOut *time.Time) bool {
+	if termination == nil {
+		return false
+	}
+	if termination.IntendedMoveOutDate == nil || moveOut == nil {
+		return true
+	}
+
+	return !termination.IntendedMoveOutDate.After(*moveOut)
+}
+
+// holdoverRent is the rent for the next holdover month. The premium is charged
+// on the first rollover only; later months keep the rent it produced rather
+// than compounding it every month.
+func holdoverRent(rent, premiumPercent int64, alreadyHeldOver bool) int64 {
+	if alreadyHeldOver || premiumPercent <= 0 {
+		return rent
+	}
+
+	return rent + rent*premiumPercent/100
+}
+
+// holdoverStayDuration restates a lease's term as running from moveIn to
+// moveOut, so that leaseEndDate over the lease's own fields lands on the
+// holdover's move-out rather than the original term's. It uses the coarsest
+// unit that lands there exactly: whole months for the usual monthly lease,
+// days when month-end clamping gets in the way, hours for short stays.
+func holdoverStayDuration(moveIn, moveOut time.Time) (int64, string) {
+	year, month, _ := moveIn.Date()
+	outYear, outMonth, _ := moveOut.Date()
+	months := int64((outYear-year)*12 + int(outMonth-month))
+	if months > 0 && leaseEndDate(moveIn, months, "Months").Equal(moveOut) {
+		return months, "Months"
+	}
+
+	days := int64(moveOut.Sub(moveIn).Round(24*time.Hour) / (24 * time.Hour))
+	if days > 0 && leaseEndDate(moveIn, days, "Days").Equal(moveOut) {
+		return days, "Days"
+	}
+
+	return int64(moveOut.Sub(moveIn) / time.Hour), "Hours"
+}
+
+// ResolveMoveOutOutcome expects lease.Unit.Property to be loaded, which both
+// the completion sweep and the move-out reminder query preload.
+func (s *leaseService) ResolveMoveOutOutcome(
+	ctx context.Context,
+	lease *models.Lease,
+) (LeaseMoveOutOutcome, error) {
+	property := lease.Unit.Property
+	if property.HoldoverPolicy != models.PropertyHoldoverPolicyMonthToMonth {
+		return LeaseMoveOutOutcome{}, nil
+	}
+
+	children, err := s.repo.ListChildren(ctx, lease.ID.String())
+	if err != nil {
+		return LeaseMoveOutOutcome{}, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "ResolveMoveOutOutcome", "action": "listing children"},
+		})
+	}
+
+	termination, terminationErr := s.leaseTerminationRepo.GetInProgressForLease(ctx, lease.ID.String())
+	if terminationErr != nil && !errors.Is(terminationErr, gorm.ErrRecordNotFound) {
+		return LeaseMoveOutOutcome{}, pkg.InternalServerError(terminationErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      terminationErr,
+			Metadata: map[string]string{"function": "ResolveMoveOutOutcome", "action": "fetching open termination"},
+		})
+	}
+
+	if !leaseHoldsOver(
+		lease.Status,
+		property.HoldoverPolicy,
+		HasBlockingRenewal(*children),
+		vacatesByMoveOut(termination, lease.MoveOutDate),
+	) {
+		return LeaseMoveOutOutcome{}, nil
+	}
+
+	return LeaseMoveOutOutcome{
+		HoldsOver: true,
+		Rent:      holdoverRent(lease.RentFee, property.HoldoverRentPremiumPercent, lease.HoldoverStartedAt != nil),
+	}, nil
+}
+
+// holdOverLease rolls a lease on by one month past its move-out.
+//
+// The lease stays Active, so its unit stays occupied without being touched.
+// Its stay duration is restated to end on the new move-out, so a later edit
+// or amendment that recomputes the move-out does not pull it back to the
+// original term. The move-out reminder keys are cleared so the tenant hears
+// about the end of each holdover month the same way they heard about the end
+// of the term.
+func (s *leaseService) holdOverLease(ctx context.Context, lease *models.Lease, rent int64) error {
+	periodStart := *lease.MoveOutDate
+	periodEnd := leaseEndDate(periodStart, 1, holdoverPeriodFrequency)
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	now := time.Now()
+	if lease.HoldoverStartedAt == nil {
+		lease.HoldoverStartedAt = &now
+	}
+	lease.RentFee = rent
+	lease.StayDuration, lease.StayDurationFrequency = holdoverStayDuration(lease.MoveInDate, periodEnd)
+	lease.MoveOutDate = &periodEnd
+	lease.RemindersSent = []string{}
+
+	if updateErr := s.repo.Update(transCtx, lease); updateErr != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "holdOverLease", "action": "extending lease"},
+		})
+	}
+
+	if billErr := s.billHoldoverPeriod(transCtx, lease, periodStart); billErr != nil {
+		transaction.Rollback()
+		return billErr
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      commitErr,
+			Metadata: map[string]string{"function": "holdOverLease", "action": "committing transaction"},
+		})
+	}
+
+	s.notifyHoldover(lease)
+
+	return nil
+}
+
+// billHoldoverPeriod replaces the lease's rent template with one covering the
+// holdover month. Each month ends the previous template at the old move-out,
+// voiding anything it had generated past it, so the account never carries two
+// answers to "what is the rent?" nor two charges for the same month.
+//
+// A lease without an account or a rent schedule is extended without billing,
+// as RenewLease does for the same leases.
+func (s *leaseService) billHoldoverPeriod(ctx context.Context, lease *models.Lease, periodStart time.Time) error {
+	if lease.FinancialAccountID == nil || lease.PaymentFrequency == nil {
+		return nil
+	}
+
+	accountID := *lease.FinancialAccountID
+	leaseID := lease.ID.String()
+
+	if endErr := s.financials.Charges.EndDefinitionsForLease(ctx, accountID, leaseID, periodStart); endErr != nil {
+		return endErr
+	}
+
+	return s.financials.Charges.MaterialiseForAccount(ctx, financials.MaterialiseForAccountInput{
+		FinancialAccountID:    accountID,
+		LeaseID:               &leaseID,
+		RentFee:               lease.RentFee,
+		Currency:              lease.RentFeeCurrency,
+		PaymentFrequency:      "Monthly",
+		MoveInDate:            periodStart,
+		StayDuration:          1,
+		StayDurationFrequency: holdoverPeriodFrequency,
+		SecurityDepositFee:    0,
+	})
+}
+
+func (s *leaseService) notifyHoldover(lease *models.Lease) {
+	smsMessage := strings.NewReplacer(
+		"{{tenant_name}}", lease.Tenant.FirstName,
+		"{{unit_name}}", lease.Unit.Name,
+		"{{move_out_date}}", lease.MoveOutDate.Format("2 Jan 2006"),
+		"{{rent}}", lib.FormatAmount(lib.PesewasToCedis(lease.RentFee)),
+		"{{currency}}", lease.RentFeeCurrency,
+	).Replace(lib.LEASE_HOLDOVER_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: lease.Tenant.Phone,
+			Message:   smsMessage,
+		},
+	)
+
+	if lease.Tenant.TenantAccount != nil {
+		tenantAccountID := lease.Tenant.TenantAccount.ID.String()
+		leaseID := lease.ID.String()
+		go func() {
+			if err := s.notificationService.SendToTenantAccount(
+				context.Background(),
+				tenantAccountID,
+				lib.LEASE_HOLDOVER_SUBJECT,
+				smsMessage,
+				map[string]string{"type": "LEASE_HOLDOVER", "lease_id": leaseID},
+			); err != nil {
+				log.WithError(err).WithField("lease_id", leaseID).Error("failed to send lease holdover notification")
+			}
+		}()
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// Holding over continues a tenancy, so it needs one to continue: a Pending
+// lease past move-out still completes, a renewal already in place is the
+// tenant's next term, which a holdover would overlap, and a tenant who has
+// given notice for this move-out is leaving.
+func TestLeaseHoldsOver(t *testing.T) {
+	monthly := models.PropertyHoldoverPolicyMonthToMonth
+	complete := models.PropertyHoldoverPolicyComplete
+
+	cases := []struct {
+		name     string
+		status   string
+		policy   string
+		renewed  bool
+		vacating bool
+		want     bool
+	}{
+		{"active on a holdover property", "Lease.Status.Active", monthly, false, false, true},
+		{"property completes leases", "Lease.Status.Active", complete, false, false, false},
+		{"never activated", "Lease.Status.Pending", monthly, false, false, false},
+		{"already renewed", "Lease.Status.Active", monthly, true, false, false},
+		{"tenant is vacating", "Lease.Status.Active", monthly, false, true, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := leaseHoldsOver(tc.status, tc.policy, tc.renewed, tc.vacating); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// The premium is a one-off step up when the fixed term runs out. Applying it
+// on every rollover would compound it month after month.
+func TestHoldoverRent(t *testing.T) {
+	cases := []struct {
+		name            string
+		rent            int64
+		premiumPercent  int64
+		alreadyHeldOver bool
+		want            int64
+	}{
+		{"no premium", 450000, 0, false, 450000},
+		{"first rollover", 450000, 10, false, 495000},
+		{"later rollover", 495000, 10, true, 495000},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := holdoverRent(tc.rent, tc.premiumPercent, tc.alreadyHeldOver); got != tc.want {
+				t.Errorf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+// A held-over lease's duration must recompute to its holdover move-out, or the
+// next edit or amendment would pull the lease back to its original term.
+// Month-end clamping rules out whole months for a term that began on the
+// 31st, and a stay booked at a time of day only lands exactly in hours.
+func TestHoldoverStayDuration(t *testing.T) {
+	cases := []struct {
+		name          string
+		moveIn        time.Time
+		moveOut       time.Time
+		wantDuration  int64
+		wantFrequency string
+	}{
+		{
+			"monthly term",
+			time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC),
+			13, "Months",
+		},
+		{
+			"term from the 31st",
+			time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC),
+			time.Date(2026, 4, 3, 0, 0, 0, 0, time.UTC),
+			62, "Days",
+		},
+		{
+			"stay booked at a time of day",
+			time.Date(2026, 1, 1, 10, 0, 0, 0, time.UTC),
+			time.Date(2026, 2, 1, 14, 0, 0, 0, time.UTC),
+			748, "Hours",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			duration, frequency := holdoverStayDuration(tc.moveIn, tc.moveOut)
+			if duration != tc.wantDuration || frequency != tc.wantFrequency {
+				t.Fatalf("got %d %s, want %d %s", duration, frequency, tc.wantDuration, tc.wantFrequency)
+			}
+			if end := leaseEndDate(tc.moveIn, duration, frequency); !end.Equal(tc.moveOut) {
+				t.Errorf("recomputed move-out %v, want %v", end, tc.moveOut)
+			}
+		})
+	}
+}
+
+// Only a notice that ends the tenancy by this move-out stops the holdover; a
+// later date needs the lease to keep running until then. A termination the
+// PM opened has no date of its own and always counts.
+func TestVacatesByMoveOut(t *testing.T) {
+	moveOut := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	before := moveOut.AddDate(0, 0, -10)
+	after := moveOut.AddDate(0, 1, 0)
+
+	cases := []struct {
+		name        string
+		termination *models.LeaseTermination
+		want        bool
+	}{
+		{"no termination", nil, false},
+		{"started by the PM", &models.LeaseTermination{}, true},
+		{"leaving before move-out", &models.LeaseTermination{IntendedMoveOutDate: &before}, true},
+		{"leaving on move-out", &models.LeaseTermination{IntendedMoveOutDate: &moveOut}, true},
+		{"leaving a month later", &models.LeaseTermination{IntendedMoveOutDate: &after}, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := vacatesByMoveOut(tc.termination, &moveOut); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
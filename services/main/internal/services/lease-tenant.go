@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type LeaseTenantService interface {
+	List(ctx context.Context, leaseID string) (*[]models.LeaseTenant, error)
+	Add(ctx context.Context, input AddLeaseTenantInput) (*models.LeaseTenant, error)
+	UpdateRole(ctx context.Context, input UpdateLeaseTenantRoleInput) (*models.LeaseTenant, error)
+	Remove(ctx context.Context, leaseID string, leaseTenantID string) error
+	// HasTenant reports whether a tenant is on the lease in any role.
+	HasTenant(ctx context.Context, leaseID string, tenantID string) (bool, error)
+	// HasTenantAccount is HasTenant for the authenticated tenant-app user.
+	HasTenantAccount(ctx context.Context, leaseID string, tenantAccountID string) (bool, error)
+}
+
+type leaseTenantService struct {
+	appCtx        pkg.AppContext
+	repo          repository.LeaseTenantRepository
+	leaseRepo     repository.LeaseRepository
+	tenantService TenantService
+}
+
+type LeaseTenantServiceDeps struct {
+	AppCtx        pkg.AppContext
+	Repo          repository.LeaseTenantRepository
+	LeaseRepo     repository.LeaseRepository
+	TenantService TenantService
+}
+
+func NewLeaseTenantService(deps LeaseTenantServiceDeps) LeaseTenantService {
+	return &leaseTenantService{
+		appCtx:        deps.AppCtx,
+		repo:          deps.Repo,
+		leaseRepo:     deps.LeaseRepo,
+		tenantService: deps.TenantService,
+	}
+}
+
+// LeaseTenantInput puts an existing tenant on a lease being created.
+type LeaseTenantInput struct {
+	TenantID string
+	Role     string
+}
+
+// leaseTenantChangeAllowed decides whether someone may join, change role on,
+// or leave a lease. fromRole is empty when they are joining and toRole is
+// empty when they are leaving.
+//
+// The primary is fixed: it is the tenant the lease was written for and the
+// account holder on its financial account. A co-tenant signs the agreement
+// and is jointly liable for it, so one can only join or be promoted before
+// the lease is activated — afterwards the signed agreement does not name
+// them. Occupants sign nothing and can come and go while the lease runs.
+func leaseTenantChangeAllowed(leaseStatus, fromRole, toRole string) error {
+	if leaseStatus != "Lease.Status.Pending" && leaseStatus != "Lease.Status.Active" {
+		return pkg.BadRequestError("LeaseTenantsNotEditable", &pkg.RentLoopErrorParams{
+			Metadata: map[string]string{"status": leaseStatus},
+		})
+	}
+
+	if fromRole == models.LeaseTenantRolePrimary || toRole == models.LeaseTenantRolePrimary {
+		return pkg.BadRequestError("PrimaryLeaseTenantFixed", nil)
+	}
+
+	if toRole == models.LeaseTenantRoleCoTenant && leaseStatus != "Lease.Status.Pending" {
+		return pkg.BadRequestError("CoTenantOnlyBeforeActivation", nil)
+	}
+
+	return nil
+}
+
+func (s *leaseTenantService) getLease(ctx context.Context, leaseID string, function string) (*models.Lease, error) {
+	lease, err := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{ID: leaseID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": function, "action": "fetching lease"},
+		})
+	}
+
+	return lease, nil
+}
+
+func (s *leaseTenantService) getLeaseTenant(
+	ctx context.Context,
+	leaseID string,
+	leaseTenantID string,
+	function string,
+) (*models.LeaseTenant, error) {
+	leaseTenant, err := s.repo.GetOne(ctx, repository.GetLeaseTenantQuery{
+		ID:       &leaseTenantID,
+		LeaseID:  leaseID,
+		Populate: &[]string{"Tenant"},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseTenantNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": function, "action": "fetching lease tenant"},
+		})
+	}
+
+	return leaseTenant, nil
+}
+
+func (s *leaseTenantService) List(ctx context.Context, leaseID string) (*[]models.LeaseTenant, error) {
+	leaseTenants, err := s.repo.ListByLease(ctx, leaseID, &[]string{"Tenant"})
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "ListLeaseTenants", "action": "listing lease tenants"},
+		})
+	}
+
+	return leaseTenants, nil
+}
+
+type AddLeaseTenantInput struct {
+	LeaseID     string
+	Role        string
+	FirstName   string
+	LastName    string
+	Phone       string
+	Email       *string
+	Gender      string
+	CreatedById string
+}
+
+// Add puts a tenant on the lease, creating them from their contact details if
+// they are new — found by phone otherwise, so a roommate who rented here
+// before keeps their history and their tenant-app login.
+func (s *leaseTenantService) Add(ctx context.Context, input AddLeaseTenantInput) (*models.LeaseTenant, error) {
+	lease, err := s.getLease(ctx, input.LeaseID, "AddLeaseTenant")
+	if err != nil {
+		return nil, err
+	}
+
+	if allowedErr := leaseTenantChangeAllowed(lease.Status, "", input.Role); allowedErr != nil {
+		return nil, allowedErr
+	}
+
+	tenant, tenantErr := s.tenantService.FindOrCreateLightTenant(ctx, FindOrCreateLightTenantInput{
+		FirstName:   input.FirstName,
+		LastName:    input.LastName,
+		Phone:       input.Phone,
+		Email:       input.Email,
+		Gender:      input.Gender,
+		CreatedById: &input.CreatedById,
+	})
+	if tenantErr != nil {
+		return nil, pkg.InternalServerError(tenantErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      tenantErr,
+			Metadata: map[string]string{"function": "AddLeaseTenant", "action": "finding or creating tenant"},
+		})
+	}
+
+	leaseTenant := models.LeaseTenant{
+		LeaseID:  input.LeaseID,
+		TenantID: tenant.ID.String(),
+		Role:     input.Role,
+	}
+
+	if createErr := s.repo.Create(ctx, &leaseTenant); createErr != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(createErr, &pgErr) && pgErr.Code == "23505" {
+			return nil, pkg.BadRequestError("TenantAlreadyOnLease", &pkg.RentLoopErrorParams{Err: createErr})
+		}
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      createErr,
+			Metadata: map[string]string{"function": "AddLeaseTenant", "action": "creating lease tenant"},
+		})
+	}
+
+	leaseTenant.Tenant = *tenant
+	return &leaseTenant, nil
+}
+
+type UpdateLeaseTenantRoleInput struct {
+	LeaseID       string
+	LeaseTenantID string
+	Role          string
+}
+
+func (s *leaseTenantService) UpdateRole(
+	ctx context.Context,
+	input UpdateLeaseTenantRoleInput,
+) (*models.LeaseTenant, error) {
+	lease, err := s.getLease(ctx, input.LeaseID, "UpdateLeaseTenantRole")
+	if err != nil {
+		return nil, err
+	}
+
+	leaseTenant, err := s.getLeaseTenant(ctx, input.LeaseID, input.LeaseTenantID, "UpdateLeaseTenantRole")
+	if err != nil {
+		return nil, err
+	}
+
+	if leaseTenant.Role == input.Role {
+		return leaseTenant, nil
+	}
+
+	if allowedErr := leaseTenantChangeAllowed(lease.Status, leaseTenant.Role, input.Role); allowedErr != nil {
+		return nil, allowedErr
+	}
+
+	leaseTenant.Role = input.Role
+	if updateErr := s.repo.Update(ctx, leaseTenant); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "UpdateLeaseTenantRole", "action": "updating lease tenant"},
+		})
+	}
+
+	return leaseTenant, nil
+}
+
+func (s *leaseTenantService) Remove(ctx context.Context, leaseID string, leaseTenantID string) error {
+	lease, err := s.getLease(ctx, leaseID, "RemoveLeaseTenant")
+	if err != nil {
+		return err
+	}
+
+	leaseTenant, err := s.getLeaseTenant(ctx, leaseID, leaseTenantID, "RemoveLeaseTenant")
+	if err != nil {
+		return err
+	}
+
+	if allowedErr := leaseTenantChangeAllowed(lease.Status, leaseTenant.Role, ""); allowedErr != nil {
+		return allowedErr
+	}
+
+	if deleteErr := s.repo.Delete(ctx, leaseTenant); deleteErr != nil {
+		return pkg.InternalServerError(deleteErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      deleteErr,
+			Metadata: map[string]string{"function": "RemoveLeaseTenant", "action": "deleting lease tenant"},
+		})
+	}
+
+	return nil
+}
+
+func (s *leaseTenantService) HasTenant(ctx context.Context, leaseID string, tenantID string) (bool, error) {
+	_, err := s.repo.GetOne(ctx, repository.GetLeaseTenantQuery{LeaseID: leaseID, TenantID: &tenantID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		return false, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "HasTenant", "action": "fetching lease tenant"},
+		})
+	}
+
+	return true, nil
+}
+
+func (s *leaseTenantService) HasTenantAccount(
+	ctx context.Context,
+	leaseID string,
+	tenantAccountID string,
+) (bool, error) {
+	exists, err := s.repo.ExistsForTenantAccount(ctx, leaseID, tenantAccountID)
+	if err != nil {
+		return false, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "HasTenantAccount", "action": "checking lease tenant"},
+		})
+	}
+
+	return exists, nil
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+func leaseTenantErrorCode(err error) string {
+	var rentLoopErr *pkg.IRentLoopError
+	if errors.As(err, &rentLoopErr) {
+		return rentLoopErr.Message
+	}
+	return ""
+}
+
+// The rules are a small table; this pins each row so a later status or role
+// does not quietly open a path that lets someone onto a signed agreement.
+func TestLeaseTenantChangeAllowed(t *testing.T) {
+	pending, active := "Lease.Status.Pending", "Lease.Status.Active"
+	primary := models.LeaseTenantRolePrimary
+	coTenant := models.LeaseTenantRoleCoTenant
+	occupant := models.LeaseTenantRoleOccupant
+
+	cases := []struct {
+		name     string
+		status   string
+		from     string
+		to       string
+		wantCode string
+	}{
+		{"co-tenant joins pending lease", pending, "", coTenant, ""},
+		{"co-tenant joins active lease", active, "", coTenant, "CoTenantOnlyBeforeActivation"},
+		{"occupant joins active lease", active, "", occupant, ""},
+		{"occupant promoted after activation", active, occupant, coTenant, "CoTenantOnlyBeforeActivation"},
+		{"co-tenant demoted on active lease", active, coTenant, occupant, ""},
+		{"co-tenant leaves active lease", active, coTenant, "", ""},
+		{"primary cannot leave", pending, primary, "", "PrimaryLeaseTenantFixed"},
+		{"nobody becomes primary", pending, coTenant, primary, "PrimaryLeaseTenantFixed"},
+		{"completed lease is frozen", "Lease.Status.Completed", "", occupant, "LeaseTenantsNotEditable"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := leaseTenantChangeAllowed(tc.status, tc.from, tc.to)
+			if tc.wantCode == "" {
+				if err != nil {
+					t.Fatalf("got %v, want allowed", err)
+				}
+				return
+			}
+			if got := leaseTenantErrorCode(err); got != tc.wantCode {
+				t.Errorf("got %q, want %q", got, tc.wantCode)
+			}
+		})
+	}
+}
+
+// The primary is always written as a lease tenant, first, and a co-tenant
+// entry naming the primary again must not produce a second row — the unique
+// index would reject the whole lease.
+func TestLeaseTenantsFromCreateInput(t *testing.T) {
+	primary := "33333333-3333-3333-3333-333333333333"
+	coTenant := "44444444-4444-4444-4444-444444444444"
+
+	tenants := leaseTenantsFromCreateInput(CreateLeaseInput{
+		TenantId: primary,
+		CoTenants: []LeaseTenantInput{
+			{TenantID: coTenant, Role: models.LeaseTenantRoleCoTenant},
+			{TenantID: primary, Role: models.LeaseTenantRoleCoTenant},
+		},
+	})
+
+	if len(tenants) != 2 {
+		t.Fatalf("got %d lease tenants, want 2", len(tenants))
+	}
+	if tenants[0].TenantID != primary || tenants[0].Role != models.LeaseTenantRolePrimary {
+		t.Errorf("got first %+v, want the primary", tenants[0])
+	}
+	if tenants[1].TenantID != coTenant || tenants[1].Role != models.LeaseTenantRoleCoTenant {
+		t.Errorf("got second %+v, want the co-tenant", tenants[1])
+	}
+}
+
+// A renewal re-creates the primary from the lease itself, so only the others
+// are carried — with their roles, since an occupant stays an occupant.
+func TestRenewedCoTenantsSkipsPrimary(t *testing.T) {
+	coTenants := renewedCoTenants([]models.LeaseTenant{
+		{TenantID: "a", Role: models.LeaseTenantRolePrimary},
+		{TenantID: "b", Role: models.LeaseTenantRoleCoTenant},
+		{TenantID: "c", Role: models.LeaseTenantRoleOccupant},
+	})
+
+	if len(coTenants) != 2 {
+		t.Fatalf("got %d, want 2", len(coTenants))
+	}
+	if coTenants[0].TenantID != "b" || coTenants[1].Role != models.LeaseTenantRoleOccupant {
+		t.Errorf("got %+v", coTenants)
+	}
+}
+
+// A lease document is only signed once every signatory has signed. A TENANT
+// signature with no tenant recorded predates co-tenants and can only have
+// come from the primary; occupants are never waited on.
+func TestUnsignedLeaseSignatories(t *testing.T) {
+	leaseTenants := []models.LeaseTenant{
+		{TenantID: "primary", Role: models.LeaseTenantRolePrimary},
+		{TenantID: "co", Role: models.LeaseTenantRoleCoTenant},
+		{TenantID: "occupant", Role: models.LeaseTenantRoleOccupant},
+	}
+	co := "co"
+
+	unsigned := unsignedLeaseSignatories(leaseTenants, []models.DocumentSignature{{Role: "TENANT"}})
+	if len(unsigned) != 1 || unsigned[0] != "co" {
+		t.Errorf("got %v, want only the co-tenant outstanding", unsigned)
+	}
+
+	unsigned = unsignedLeaseSignatories(leaseTenants, []models.DocumentSignature{
+		{Role: "TENANT"},
+		{Role: "TENANT", TenantID: &co},
+	})
+	if len(unsigned) != 0 {
+		t.Errorf("got %v, want nobody outstanding", unsigned)
+	}
+}
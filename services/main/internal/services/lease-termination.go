@@ -26,34 +26,55 @@ type LeaseTerminationService interface {
 	Update(ctx context.Context, input UpdateLeaseTerminationInput) (*models.LeaseTermination, error)
 	Complete(ctx context.Context, input CompleteLeaseTerminationInput) error
 	Cancel(ctx context.Context, input CancelLeaseTerminationInput) error
+	// GiveNotice is the tenant's route into a termination: a notice to vacate
+	// on a set date, held to the notice period.
+	GiveNotice(ctx context.Context, input GiveNoticeToVacateInput) (*models.LeaseTermination, error)
 }
 
 type leaseTerminationService struct {
-	appCtx              pkg.AppContext
-	repo                repository.LeaseTerminationRepository
-	leaseRepo           repository.LeaseRepository
-	unitService         UnitService
-	notificationService NotificationService
-	financials          *financials.Financials
+	appCtx                pkg.AppContext
+	repo                  repository.LeaseTerminationRepository
+	leaseRepo             repository.LeaseRepository
+	leaseTenantRepo       repository.LeaseTenantRepository
+	leaseChecklistRepo    repository.LeaseChecklistRepository
+	leaseService          LeaseService
+	leaseChecklistService LeaseChecklistService
+	unitService           UnitService
+	notificationService   NotificationService
+	turnoverTaskService   TurnoverTaskService
+	financials            *financials.Financials
+	queue                 RentloopQueue
 }
 
 type LeaseTerminationServiceDeps struct {
-	AppCtx              pkg.AppContext
-	Repo                repository.LeaseTerminationRepository
-	LeaseRepo           repository.LeaseRepository
-	UnitService         UnitService
-	NotificationService NotificationService
-	Financials          *financials.Financials
+	AppCtx                pkg.AppContext
+	Repo                  repository.LeaseTerminationRepository
+	LeaseRepo             repository.LeaseRepository
+	LeaseTenantRepo       repository.LeaseTenantRepository
+	LeaseChecklistRepo    repository.LeaseChecklistRepository
+	LeaseService          LeaseService
+	LeaseChecklistService LeaseChecklistService
+	UnitService           UnitService
+	NotificationService   NotificationService
+	TurnoverTaskService   TurnoverTaskService
+	Financials            *financials.Financials
+	RentloopQueue         RentloopQueue
 }
 
 func NewLeaseTerminationService(deps LeaseTerminationServiceDeps) LeaseTerminationService {
 	return &leaseTerminationService{
-		appCtx:              deps.AppCtx,
-		repo:                deps.Repo,
-		leaseRepo:           deps.LeaseRepo,
-		unitService:         deps.UnitService,
-		notificationService: deps.NotificationService,
-		financials:          deps.Financials,
+		appCtx:                deps.AppCtx,
+		repo:                  deps.Repo,
+		leaseRepo:             deps.LeaseRepo,
+		leaseTenantRepo:       deps.LeaseTenantRepo,
+		leaseChecklistRepo:    deps.LeaseChecklistRepo,
+		leaseService:          deps.LeaseService,
+		leaseChecklistService: deps.LeaseChecklistService,
+		unitService:           deps.UnitService,
+		notificationService:   deps.NotificationService,
+		turnoverTaskService:   deps.TurnoverTaskService,
+		financials:            deps.Financials,
+		queue:                 deps.RentloopQueue,
 	}
 }
 
@@ -104,7 +125,7 @@ func (s *leaseTerminationService) Create(
 		Type:          input.Type,
 		Reason:        input.Reason,
 		Status:        "LeaseTermination.Status.InProgress",
-		InitiatedById: input.InitiatedById,
+		InitiatedById: &input.InitiatedById,
 	}
 
 	if createErr := s.repo.Create(ctx, termination); createErr != nil {
@@ -118,9 +139,20 @@ func (s *leaseTerminationService) Create(
 		})
 	}
 
+	s.notifyWaitlistOfVacancy(ctx, lease.UnitId, time.Now())
+
 	return termination, nil
 }
 
+// notifyWaitlistOfVacancy queues a heads-up to the unit's waitlist that it is
+// coming free at availableFrom. The termination is saved by then, so a
+// failure is logged rather than returned.
+func (s *leaseTerminationService) notifyWaitlistOfVacancy(ctx context.Context, unitID string, availableFrom time.Time) {
+	if err := s.queue.EnqueueWaitlistUnitVacating(ctx, unitID, availableFrom); err != nil {
+		log.WithError(err).WithField("unit_id", unitID).Error("failed to enqueue waitlist vacancy notice")
+	}
+}
+
 func (s *leaseTerminationService) GetOne(
 	ctx context.Context,
 	query repository.GetTerminatedLeaseQuery,
@@ -271,6 +303,11 @@ func (s *leaseTerminationService) Complete(ctx context.Context, input CompleteLe
 	termination.CompletedAt = &now
 	termination.CompletedById = &input.ClientUserID
 
+	if chargeErr := s.chargeEarlyTerminationFee(txCtx, lease, termination); chargeErr != nil {
+		tx.Rollback()
+		return chargeErr
+	}
+
 	if updateErr := s.repo.Update(txCtx, termination); updateErr != nil {
 		tx.Rollback()
 		return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
@@ -296,15 +333,13 @@ func (s *leaseTerminationService) Complete(ctx context.Context, input CompleteLe
 		})
 	}
 
-	// Release the unit inside the same transaction — if this fails, the whole
-	// completion rolls back so the termination isn't left half-applied with a
-	// stale unit status.
-	if releaseErr := releaseUnitIfNoActiveLease(txCtx, s.leaseRepo, s.unitService, &lease.Unit); releaseErr != nil {
+	// Turn the unit over inside the same transaction — if this fails, the
+	// whole completion rolls back so the termination isn't left half-applied.
+	// The unit stays occupied, and its waitlist uninvited, until the turnover
+	// is settled.
+	if turnoverErr := s.turnoverTaskService.GenerateForLease(txCtx, lease); turnoverErr != nil {
 		tx.Rollback()
-		return pkg.InternalServerError(releaseErr.Error(), &pkg.RentLoopErrorParams{
-			Err:      releaseErr,
-			Metadata: map[string]string{"function": "Complete", "action": "releasing unit"},
-		})
+		return turnoverErr
 	}
 
 	if commitErr := tx.Commit().Error; commitErr != nil {
@@ -47,7 +47,12 @@ type LeaseService interface {
 	// exercise it without transitioning every other lease in the database as
 	// a side effect — the same reason IssueDueInvoicesForAccount exists.
 	ActivateDueLeases(ctx context.Context, onlyLeaseID string) (activated int, failed int, err error)
-	CompleteDueLeases(ctx context.Context, onlyLeaseID string) (completed int, failed int, err error)
+	// CompleteDueLeases completes, or holds over per the property's policy,
+	// every lease past its move-out.
+	CompleteDueLeases(ctx context.Context, onlyLeaseID string) (completed int, heldOver int, failed int, err error)
+	// ResolveMoveOutOutcome says whether the lease will complete or roll over
+	// month to month when its move-out passes, and at what rent.
+	ResolveMoveOutOutcome(ctx context.Context, lease *models.Lease) (LeaseMoveOutOutcome, error)
 	ResolveManagerRecipient(ctx context.Context, lease *models.Lease) (*models.ClientUser, error)
 }
 
@@ -60,7 +65,9 @@ type leaseService struct {
 	unitService          UnitService
 	clientUserRepo       repository.ClientUserRepository
 	userRepo             repository.UserRepository
+	leaseTerminationRepo repository.LeaseTerminationRepository
 	financials           *financials.Financials
+	turnoverTaskService  TurnoverTaskService
 }
 
 func NewLeaseService(
@@ -72,7 +79,9 @@ func NewLeaseService(
 	unitService UnitService,
 	clientUserRepo repository.ClientUserRepository,
 	userRepo repository.UserRepository,
+	leaseTerminationRepo repository.LeaseTerminationRepository,
 	financialsFacade *financials.Financials,
+	turnoverTaskService TurnoverTaskService,
 ) LeaseService {
 	return &leaseService{
 		appCtx:               appCtx,
@@ -83,7 +92,9 @@ func NewLeaseService(
 		clientUserRepo:       clientUserRepo,
 		userRepo:             userRepo,
 		unitService:          unitService,
+		leaseTerminationRepo: leaseTerminationRepo,
 		financials:           financialsFacade,
+		turnoverTaskService:  turnoverTaskService,
 	}
 }
 
@@ -106,6 +117,8 @@ type CreateLeaseInput struct {
 	TerminationAgreementDocumentUrl *string
 	ParentLeaseId                   *string
 	Type                            string
+	// CoTenants joins everyone besides TenantId, who is always the primary.
+	CoTenants []LeaseTenantInput
 }
 
 // leaseFromCreateInput maps the service input onto the model.
@@ -137,9 +150,30 @@ func leaseFromCreateInput(input CreateLeaseInput) models.Lease {
 		LeaseAgreementDocumentUrl:       input.LeaseAgreementDocumentUrl,
 		TerminationAgreementDocumentUrl: input.TerminationAgreementDocumentUrl,
 		ParentLeaseId:                   input.ParentLeaseId,
+		Tenants:                         leaseTenantsFromCreateInput(input),
 	}
 }
 
+// leaseTenantsFromCreateInput lists the primary first, then the co-tenants.
+// The primary row is created with the lease so every lease has one, whichever
+// path created it.
+func leaseTenantsFromCreateInput(input CreateLeaseInput) []models.LeaseTenant {
+	tenants := make([]models.LeaseTenant, 0, len(input.CoTenants)+1)
+	tenants = append(tenants, models.LeaseTenant{
+		TenantID: input.TenantId,
+		Role:     models.LeaseTenantRolePrimary,
+	})
+
+	for _, coTenant := range input.CoTenants {
+		if coTenant.TenantID == input.TenantId {
+			continue
+		}
+		tenants = append(tenants, models.LeaseTenant{TenantID: coTenant.TenantID, Role: coTenant.Role})
+	}
+
+	return tenants
+}
+
 func (s *leaseService) CreateLease(ctx context.Context, input CreateLeaseInput) (*models.Lease, error) {
 	metaJson, marshallErr := lib.InterfaceToJSON(input.Meta)
 	if marshallErr != nil {
@@ -202,11 +236,16 @@ type UpdateLeaseInput struct {
 	StayDuration              *int64
 	LeaseAgreementDocumentUrl *string
 
+	EarlyTerminationFeePolicy      *string
+	EarlyTerminationFeeAmount      *int64
+	EarlyTerminationFeeRentPeriods *int64
+
 	// Nullable fields (use Optional to allow explicit null)
 	PaymentFrequency                                      lib.Optional[string]
 	KeyHandoverDate                                       lib.Optional[time.Time]
 	UtilityTransfersDate                                  lib.Optional[time.Time]
 	PropertyInspectionDate                                lib.Optional[time.Time]
+	NoticePeriodDays                                      lib.Optional[int64]
 	TerminationAgreementDocumentUrl                       lib.Optional[string]
 	TerminationAgreementDocumentPropertyManagerSignedAt   lib.Optional[time.Time]
 	TerminationAgreementDocumentPropertyManagerSignedByID lib.Optional[string]
@@ -273,6 +312,18 @@ func (s *leaseService) UpdateLease(ctx context.Context, input UpdateLeaseInput)
 		lease.LeaseAgreementDocumentUrl = input.LeaseAgreementDocumentUrl
 	}
 
+	if input.EarlyTerminationFeePolicy != nil {
+		lease.EarlyTerminationFeePolicy = *input.EarlyTerminationFeePolicy
+	}
+
+	if input.EarlyTerminationFeeAmount != nil {
+		lease.EarlyTerminationFeeAmount = *input.EarlyTerminationFeeAmount
+	}
+
+	if input.EarlyTerminationFeeRentPeriods != nil {
+		lease.EarlyTerminationFeeRentPeriods = *input.EarlyTerminationFeeRentPeriods
+	}
+
 	if input.Meta != nil {
 		meta, marshallErr := lib.InterfaceToJSON(*input.Meta)
 		if marshallErr != nil {
@@ -305,6 +356,10 @@ func (s *leaseService) UpdateLease(ctx context.Context, input UpdateLeaseInput)
 		lease.PropertyInspectionDate = input.PropertyInspectionDate.Ptr()
 	}
 
+	if input.NoticePeriodDays.IsSet {
+		lease.NoticePeriodDays = input.NoticePeriodDays.Ptr()
+	}
+
 	if input.TerminationAgreementDocumentUrl.IsSet {
 		lease.TerminationAgreementDocumentUrl = input.TerminationAgreementDocumentUrl.Ptr()
 	}
@@ -783,12 +838,13 @@ func (s *leaseService) ActivateDueLeases(ctx context.Context, onlyLeaseID string
 	return activated, failed, nil
 }
 
-// CompleteDueLeases completes every lease whose move-out date has fully passed.
-// Same failure policy as ActivateDueLeases.
-func (s *leaseService) CompleteDueLeases(ctx context.Context, onlyLeaseID string) (int, int, error) {
+// CompleteDueLeases completes every lease whose move-out date has fully passed,
+// unless its property holds leases over, in which case the lease rolls on by a
+// month instead. Same failure policy as ActivateDueLeases.
+func (s *leaseService) CompleteDueLeases(ctx context.Context, onlyLeaseID string) (int, int, int, error) {
 	leases, err := s.repo.ListDueForCompletion(ctx)
 	if err != nil {
-		return 0, 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+		return 0, 0, 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
 			Err: err,
 			Metadata: map[string]string{
 				"function": "CompleteDueLeases",
@@ -797,14 +853,35 @@ func (s *leaseService) CompleteDueLeases(ctx context.Context, onlyLeaseID string
 		})
 	}
 
-	var completed, failed int
+	var completed, heldOver, failed int
 	for i := range *leases {
-		leaseID := (*leases)[i].ID.String()
+		lease := &(*leases)[i]
+		leaseID := lease.ID.String()
 
 		if onlyLeaseID != "" && leaseID != onlyLeaseID {
 			continue
 		}
 
+		outcome, outcomeErr := s.ResolveMoveOutOutcome(ctx, lease)
+		if outcomeErr != nil {
+			log.WithError(outcomeErr).WithField("lease_id", leaseID).
+				Error("failed to resolve lease move-out outcome")
+			failed++
+			continue
+		}
+
+		if outcome.HoldsOver {
+			if holdErr := s.holdOverLease(ctx, lease, outcome.Rent); holdErr != nil {
+				log.WithError(holdErr).WithField("lease_id", leaseID).
+					Error("failed to hold over lease")
+				failed++
+				continue
+			}
+
+			heldOver++
+			continue
+		}
+
 		if _, completeErr := s.CompleteLease(ctx, leaseID); completeErr != nil {
 			log.WithError(completeErr).WithField("lease_id", leaseID).
 				Error("failed to complete lease")
@@ -815,7 +892,7 @@ func (s *leaseService) CompleteDueLeases(ctx context.Context, onlyLeaseID string
 		completed++
 	}
 
-	return completed, failed, nil
+	return completed, heldOver, failed, nil
 }
 
 // isCompletableStatus reports whether a lease in this status may transition to
@@ -875,18 +952,13 @@ func (s *leaseService) CompleteLease(ctx context.Context, leaseID string) (*mode
 		})
 	}
 
-	// Release the unit inside the same transaction — if this fails, the whole
-	// completion rolls back so the lease stays Active and is retried by the
-	// next cron run, instead of being stuck Completed with a stale unit status.
-	if releaseErr := releaseUnitIfNoActiveLease(transCtx, s.repo, s.unitService, &lease.Unit); releaseErr != nil {
+	// Turn the unit over inside the same transaction — if this fails, the
+	// whole completion rolls back so the lease stays Active and is retried by
+	// the next cron run, instead of being stuck Completed with a unit nobody
+	// will release. The unit is released once the turnover is settled.
+	if turnoverErr := s.turnoverTaskService.GenerateForLease(transCtx, lease); turnoverErr != nil {
 		transaction.Rollback()
-		return nil, pkg.InternalServerError(releaseErr.Error(), &pkg.RentLoopErrorParams{
-			Err: releaseErr,
-			Metadata: map[string]string{
-				"function": "CompleteLease",
-				"action":   "releasing unit",
-			},
-		})
+		return nil, turnoverErr
 	}
 
 	if commitErr := transaction.Commit().Error; commitErr != nil {
@@ -1008,16 +1080,16 @@ func (s *leaseService) ResolveManagerRecipient(ctx context.Context, lease *model
 	return owner, nil
 }
 
-// releaseUnitIfNoActiveLease re-evaluates a unit's occupancy status after one
-// of its leases ends. Mirrors the exact counting/threshold logic
+// releaseUnitIfNoActiveLease re-evaluates a unit's occupancy status once one
+// of its leases has ended and the unit has been turned over. Mirrors the exact counting/threshold logic
 // ApproveTenantApplication uses when a lease is added (internal/services/
 // tenant-application.go), just run in reverse: re-count remaining
 // Pending/Active leases against the unit's MaxOccupantsAllowed and downgrade
 // accordingly — Available if none remain, PartiallyOccupied if some remain
 // but under capacity (covers multi-tenant units losing one of several
-// tenants), or left as-is if still at/over capacity. Shared by CompleteLease
-// and LeaseTerminationService.Complete — the two places a lease ending can
-// change a unit's occupancy — so this rule lives once.
+// tenants), or left as-is if still at/over capacity. CompleteLease and
+// LeaseTerminationService.Complete leave the unit occupied and generate its
+// turnover; settling the lease's last turnover task runs this.
 func releaseUnitIfNoActiveLease(
 	ctx context.Context,
 	leaseRepo repository.LeaseRepository,
@@ -38,10 +38,21 @@ type Services struct {
 	ExpenseService                ExpenseService
 	AgreementService              AgreementService
 	UnitDateBlockService          UnitDateBlockService
+	TurnoverTaskService           TurnoverTaskService
 	BookingService                BookingService
+	BookingPricingService         BookingPricingService
+	UnitCalendarService           UnitCalendarService
 	ExchangeRateService           ExchangeRateService
 	LeaseTerminationService       LeaseTerminationService
+	LeaseAmendmentService         LeaseAmendmentService
+	LeaseTenantService            LeaseTenantService
+	GuarantorService              GuarantorService
+	RenewalOfferService           RenewalOfferService
 	LeaseAgreementDocumentService LeaseAgreementDocumentService
+	CashFlowForecastService       CashFlowForecastService
+	WaitlistService               WaitlistService
+	ViewingSlotService            ViewingSlotService
+	ViewingService                ViewingService
 	Financials                    *financials.Financials
 }
 
@@ -141,6 +152,7 @@ func NewServices(params INewServicesParams) Services {
 		Repo:                 params.Repository.UnitRepository,
 		PropertyRepo:         params.Repository.PropertyRepository,
 		PropertyBlockService: propertyBlockService,
+		RentloopQueue:        params.RentloopQueue,
 	})
 
 	propertyService := NewPropertyService(
@@ -161,12 +173,27 @@ func NewServices(params INewServicesParams) Services {
 	documentService := NewDocumentService(
 		params.AppCtx,
 		params.Repository.DocumentRepository,
+		params.Repository.DocumentRevisionRepository,
+		params.Repository.LeaseRepository,
+		params.Repository.TenantApplicationRepository,
+		params.Repository.LeaseTerminationRepository,
 	)
 
 	tenantService := NewTenantService(params.AppCtx, params.Repository.TenantRepository)
 
 	unitDateBlockService := NewUnitDateBlockService(params.AppCtx, params.Repository.UnitDateBlockRepository)
 
+	turnoverTaskService := NewTurnoverTaskService(TurnoverTaskServiceDeps{
+		AppCtx:                 params.AppCtx,
+		Repo:                   params.Repository.TurnoverTaskRepository,
+		UnitDateBlockRepo:      params.Repository.UnitDateBlockRepository,
+		BookingRepo:            params.Repository.BookingRepository,
+		LeaseRepo:              params.Repository.LeaseRepository,
+		UnitRepo:               params.Repository.UnitRepository,
+		ClientUserPropertyRepo: params.Repository.ClientUserPropertyRepository,
+		UnitService:            unitService,
+	})
+
 	leaseService := NewLeaseService(
 		params.AppCtx,
 		params.Repository.LeaseRepository,
@@ -176,7 +203,9 @@ func NewServices(params INewServicesParams) Services {
 		unitService,
 		params.Repository.ClientUserRepository,
 		params.Repository.UserRepository,
+		params.Repository.LeaseTerminationRepository,
 		financialsFacade,
+		turnoverTaskService,
 	)
 
 	// Attach closure now that LeaseService exists. Closure reads lease terms
@@ -193,6 +222,13 @@ func NewServices(params INewServicesParams) Services {
 	tenantAccountService := NewTenantAccountService(params.AppCtx, params.Repository.TenantAccountRepository)
 
 	paymentAccountService := NewPaymentAccountService(params.AppCtx, params.Repository.PaymentAccountRepository)
+	guarantorService := NewGuarantorService(GuarantorServiceDeps{
+		AppCtx:                params.AppCtx,
+		Repo:                  params.Repository.GuarantorRepository,
+		TenantApplicationRepo: params.Repository.TenantApplicationRepository,
+		LeaseRepo:             params.Repository.LeaseRepository,
+		SigningRepo:           params.Repository.SigningRepository,
+	})
 	tenantApplicationService := NewTenantApplicationService(TenantApplicationServiceDeps{
 		AppCtx:               params.AppCtx,
 		Repo:                 params.Repository.TenantApplicationRepository,
@@ -202,17 +238,45 @@ func NewServices(params INewServicesParams) Services {
 		LeaseService:         leaseService,
 		TenantAccountService: tenantAccountService,
 		InvoiceService:       invoiceService,
+		GuarantorService:     guarantorService,
+		DocumentRepo:         params.Repository.DocumentRepository,
+		WaitlistEntryRepo:    params.Repository.WaitlistEntryRepository,
 		Financials:           financialsFacade,
 	})
-	signingService := NewSigningService(
+	leaseAgreementDocumentService := NewLeaseAgreementDocumentService(
 		params.AppCtx,
-		params.Repository.SigningRepository,
 		params.Repository.LeaseAgreementDocumentRepository,
+		params.Repository.LeaseRepository,
+		documentService,
 	)
-	leaseAgreementDocumentService := NewLeaseAgreementDocumentService(
+	signingService := NewSigningService(
+		params.AppCtx,
+		params.Repository.SigningRepository,
+		params.Repository.SigningEnvelopeRepository,
+		params.Repository.DocumentRepository,
 		params.Repository.LeaseAgreementDocumentRepository,
+		params.Repository.LeaseTenantRepository,
+		params.Repository.GuarantorRepository,
+		leaseAgreementDocumentService,
+		documentService,
 	)
 
+	leaseTenantService := NewLeaseTenantService(LeaseTenantServiceDeps{
+		AppCtx:        params.AppCtx,
+		Repo:          params.Repository.LeaseTenantRepository,
+		LeaseRepo:     params.Repository.LeaseRepository,
+		TenantService: tenantService,
+	})
+
+	renewalOfferService := NewRenewalOfferService(RenewalOfferServiceDeps{
+		AppCtx:              params.AppCtx,
+		Repo:                params.Repository.RenewalOfferRepository,
+		LeaseRepo:           params.Repository.LeaseRepository,
+		LeaseTenantRepo:     params.Repository.LeaseTenantRepository,
+		LeaseService:        leaseService,
+		NotificationService: notificationService,
+	})
+
 	paymentService := NewPaymentService(PaymentServiceDeps{
 		AppCtx:                   params.AppCtx,
 		Repo:                     params.Repository.PaymentRepository,
@@ -222,6 +286,7 @@ func NewServices(params INewServicesParams) Services {
 		NotificationService:      notificationService,
 		LeaseService:             leaseService,
 		TenantApplicationService: tenantApplicationService,
+		LeaseTenantService:       leaseTenantService,
 		Financials:               financialsFacade,
 	})
 
@@ -247,6 +312,7 @@ func NewServices(params INewServicesParams) Services {
 		AppCtx:              params.AppCtx,
 		Repo:                params.Repository.AnnouncementRepository,
 		TenantAccountRepo:   params.Repository.TenantAccountRepository,
+		TenantRepo:          params.Repository.TenantRepository,
 		NotificationService: notificationService,
 		RentloopQueue:       params.RentloopQueue,
 	})
@@ -262,25 +328,86 @@ func NewServices(params INewServicesParams) Services {
 
 	agreementService := NewAgreementService(params.Repository.AgreementRepository)
 
+	bookingPricingService := NewBookingPricingService(
+		params.AppCtx,
+		params.Repository.BookingPricingRuleRepository,
+		unitService,
+	)
+
 	bookingService := NewBookingService(BookingServiceDeps{
-		AppCtx:               params.AppCtx,
-		Repo:                 params.Repository.BookingRepository,
-		UnitDateBlockService: unitDateBlockService,
-		UnitDateBlockRepo:    params.Repository.UnitDateBlockRepository,
-		TenantService:        tenantService,
-		InvoiceService:       invoiceService,
-		UnitService:          unitService,
+		AppCtx:                  params.AppCtx,
+		Repo:                    params.Repository.BookingRepository,
+		BookingRefundRepo:       params.Repository.BookingRefundRepository,
+		BookingModificationRepo: params.Repository.BookingModificationRepository,
+		BookingGuestRepo:        params.Repository.BookingGuestRepository,
+		UnitDateBlockService:    unitDateBlockService,
+		UnitDateBlockRepo:       params.Repository.UnitDateBlockRepository,
+		TenantService:           tenantService,
+		InvoiceService:          invoiceService,
+		UnitService:             unitService,
+		PricingService:          bookingPricingService,
+		TurnoverTaskService:     turnoverTaskService,
+		RentloopQueue:           params.RentloopQueue,
+	})
+
+	unitCalendarService := NewUnitCalendarService(UnitCalendarServiceDeps{
+		AppCtx:            params.AppCtx,
+		Repo:              params.Repository.UnitCalendarSubscriptionRepository,
+		UnitDateBlockRepo: params.Repository.UnitDateBlockRepository,
+		BookingRepo:       params.Repository.BookingRepository,
+		UnitRepo:          params.Repository.UnitRepository,
+		UnitService:       unitService,
+		Fetcher:           params.AppCtx.Clients.CalendarFeed,
 	})
 
 	exchangeRateService := NewExchangeRateService(params.AppCtx, params.Repository.ExchangeRateRepository)
 
 	leaseTerminationService := NewLeaseTerminationService(LeaseTerminationServiceDeps{
-		AppCtx:              params.AppCtx,
-		Repo:                params.Repository.LeaseTerminationRepository,
-		LeaseRepo:           params.Repository.LeaseRepository,
-		UnitService:         unitService,
-		NotificationService: notificationService,
-		Financials:          financialsFacade,
+		AppCtx:                params.AppCtx,
+		Repo:                  params.Repository.LeaseTerminationRepository,
+		LeaseRepo:             params.Repository.LeaseRepository,
+		LeaseTenantRepo:       params.Repository.LeaseTenantRepository,
+		LeaseChecklistRepo:    params.Repository.LeaseChecklistRepository,
+		LeaseService:          leaseService,
+		LeaseChecklistService: leaseChecklistService,
+		UnitService:           unitService,
+		NotificationService:   notificationService,
+		TurnoverTaskService:   turnoverTaskService,
+		Financials:            financialsFacade,
+		RentloopQueue:         params.RentloopQueue,
+	})
+
+	waitlistService := NewWaitlistService(WaitlistServiceDeps{
+		AppCtx:        params.AppCtx,
+		Repo:          params.Repository.WaitlistEntryRepository,
+		UnitRepo:      params.Repository.UnitRepository,
+		PropertyRepo:  params.Repository.PropertyRepository,
+		RentloopQueue: params.RentloopQueue,
+	})
+
+	viewingSlotService := NewViewingSlotService(ViewingSlotServiceDeps{
+		AppCtx:                 params.AppCtx,
+		Repo:                   params.Repository.ViewingSlotRepository,
+		ViewingRepo:            params.Repository.ViewingRepository,
+		ClientUserPropertyRepo: params.Repository.ClientUserPropertyRepository,
+	})
+
+	viewingService := NewViewingService(ViewingServiceDeps{
+		AppCtx:                params.AppCtx,
+		Repo:                  params.Repository.ViewingRepository,
+		SlotRepo:              params.Repository.ViewingSlotRepository,
+		UnitRepo:              params.Repository.UnitRepository,
+		PropertyRepo:          params.Repository.PropertyRepository,
+		TenantApplicationRepo: params.Repository.TenantApplicationRepository,
+		RentloopQueue:         params.RentloopQueue,
+	})
+
+	leaseAmendmentService := NewLeaseAmendmentService(LeaseAmendmentServiceDeps{
+		AppCtx:      params.AppCtx,
+		Repo:        params.Repository.LeaseAmendmentRepository,
+		LeaseRepo:   params.Repository.LeaseRepository,
+		SigningRepo: params.Repository.SigningRepository,
+		Financials:  financialsFacade,
 	})
 
 	expenseService := NewExpenseService(ExpenseServiceDeps{
@@ -291,6 +418,12 @@ func NewServices(params INewServicesParams) Services {
 		AccountingService: accountingService,
 	})
 
+	cashFlowForecastService := NewCashFlowForecastService(CashFlowForecastServiceDeps{
+		Repo:             params.Repository.CashFlowForecastRepository,
+		ClientRepo:       params.Repository.ClientRepository,
+		ExchangeRateRepo: params.Repository.ExchangeRateRepository,
+	})
+
 	return Services{
 		NotificationService: notificationService,
 		AccountingService:   accountingService,
@@ -325,9 +458,20 @@ func NewServices(params INewServicesParams) Services {
 		ExpenseService:                expenseService,
 		AgreementService:              agreementService,
 		UnitDateBlockService:          unitDateBlockService,
+		TurnoverTaskService:           turnoverTaskService,
 		BookingService:                bookingService,
+		BookingPricingService:         bookingPricingService,
+		UnitCalendarService:           unitCalendarService,
 		ExchangeRateService:           exchangeRateService,
 		LeaseTerminationService:       leaseTerminationService,
+		LeaseAmendmentService:         leaseAmendmentService,
+		LeaseTenantService:            leaseTenantService,
+		GuarantorService:              guarantorService,
+		RenewalOfferService:           renewalOfferService,
 		LeaseAgreementDocumentService: leaseAgreementDocumentService,
+		CashFlowForecastService:       cashFlowForecastService,
+		WaitlistService:               waitlistService,
+		ViewingSlotService:            viewingSlotService,
+		ViewingService:                viewingService,
 	}
 }
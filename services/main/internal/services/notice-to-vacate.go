@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/internal/services/financials"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/jackc/pgx/v5/pgconn"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+type GiveNoticeToVacateInput struct {
+	LeaseID             string
+	TenantID            string
+	IntendedMoveOutDate time.Time
+	Reason              *string
+}
+
+// noticePeriodDays is the lease's own notice period when it has one, the
+// property's otherwise.
+func noticePeriodDays(leaseDays *int64, propertyDays int64) int64 {
+	if leaseDays != nil {
+		return *leaseDays
+	}
+
+	return propertyDays
+}
+
+// validateNoticeToVacate checks an intended move-out against the notice period
+// and the lease's end.
+//
+// Dates are compared as whole days: notice given at 23:00 still counts the
+// day it was given. Moving out after the term ends is refused unless the
+// property holds leases over — there is then still a tenancy to leave.
+func validateNoticeToVacate(
+	intended time.Time,
+	moveOut *time.Time,
+	mayHoldOver bool,
+	noticeDays int64,
+	now time.Time,
+) error {
+	today := calendarDate(now)
+	intendedDay := calendarDate(intended)
+
+	if intendedDay.Before(today) {
+		return pkg.BadRequestError("IntendedMoveOutInPast", nil)
+	}
+
+	if intendedDay.Before(today.AddDate(0, 0, int(noticeDays))) {
+		return pkg.BadRequestError("NoticePeriodNotMet", nil)
+	}
+
+	if !mayHoldOver && moveOut != nil && intendedDay.After(calendarDate(*moveOut)) {
+		return pkg.BadRequestError("IntendedMoveOutAfterLeaseEnd", nil)
+	}
+
+	return nil
+}
+
+// calendarDate is the day t falls on where it was recorded, as midnight UTC,
+// so dates stored without a zone compare equal to it.
+func calendarDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// earlyTerminationFee works out what leaving on `intended` costs under the
+// lease's policy.
+//
+// Only leaving on a day before the fixed term ends is early; leaving on its
+// last day, at whatever hour, is not. A lease already holding over is
+// periodic, so notice on it never carries a fee.
+func earlyTerminationFee(lease *models.Lease, intended time.Time) int64 {
+	if lease.HoldoverStartedAt != nil || lease.MoveOutDate == nil ||
+		!calendarDate(intended).Before(calendarDate(*lease.MoveOutDate)) {
+		return 0
+	}
+
+	switch lease.EarlyTerminationFeePolicy {
+	case models.EarlyTerminationFeePolicyFixed:
+		return lease.EarlyTerminationFeeAmount
+	case models.EarlyTerminationFeePolicyRentPeriods:
+		return lease.EarlyTerminationFeeRentPeriods * lease.RentFee
+	default:
+		return 0
+	}
+}
+
+// GiveNotice opens a TENANT_INITIATED termination with a CHECK_OUT checklist
+// ready for the PM, and tells them.
+//
+// The early termination fee is fixed now, so the tenant knows the cost when
+// they give notice, but charged only when the PM completes the termination —
+// a termination the PM cancels never bills it.
+func (s *leaseTerminationService) GiveNotice(
+	ctx context.Context,
+	input GiveNoticeToVacateInput,
+) (*models.LeaseTermination, error) {
+	lease, err := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{
+		ID:       input.LeaseID,
+		Populate: &[]string{"Unit.Property", "Tenant", "ActivatedBy.User"},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GiveNotice", "action": "fetching lease"},
+		})
+	}
+
+	if lease.Status != "Lease.Status.Active" {
+		return nil, pkg.BadRequestError("LeaseIsNotActive", nil)
+	}
+
+	leaseTenant, tenantErr := s.leaseTenantRepo.GetOne(ctx, repository.GetLeaseTenantQuery{
+		LeaseID:  input.LeaseID,
+		TenantID: &input.TenantID,
+	})
+	if tenantErr != nil {
+		if errors.Is(tenantErr, gorm.ErrRecordNotFound) {
+			return nil, pkg.ForbiddenError("LeaseDoesNotBelongToTenant", nil)
+		}
+		return nil, pkg.InternalServerError(tenantErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      tenantErr,
+			Metadata: map[string]string{"function": "GiveNotice", "action": "fetching lease tenant"},
+		})
+	}
+	if !leaseTenant.IsSignatory() {
+		return nil, pkg.ForbiddenError("NoticeGiverNotSignatory", nil)
+	}
+
+	property := lease.Unit.Property
+	noticeDays := noticePeriodDays(lease.NoticePeriodDays, property.NoticePeriodDays)
+	mayHoldOver := property.HoldoverPolicy == models.PropertyHoldoverPolicyMonthToMonth
+
+	if validateErr := validateNoticeToVacate(
+		input.IntendedMoveOutDate, lease.MoveOutDate, mayHoldOver, noticeDays, time.Now(),
+	); validateErr != nil {
+		return nil, validateErr
+	}
+
+	// The checklist is created on the manager's behalf: they are the one who
+	// will walk the unit with the tenant.
+	manager, managerErr := s.leaseService.ResolveManagerRecipient(ctx, lease)
+	if managerErr != nil {
+		return nil, managerErr
+	}
+
+	reason := "Tenant gave notice to vacate"
+	if input.Reason != nil && *input.Reason != "" {
+		reason = *input.Reason
+	}
+
+	termination := &models.LeaseTermination{
+		LeaseID:             input.LeaseID,
+		Type:                "TENANT_INITIATED",
+		Reason:              reason,
+		Status:              "LeaseTermination.Status.InProgress",
+		InitiatedByTenantID: &input.TenantID,
+		IntendedMoveOutDate: &input.IntendedMoveOutDate,
+		NoticePeriodDays:    &noticeDays,
+		EarlyTerminationFee: earlyTerminationFee(lease, input.IntendedMoveOutDate),
+	}
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if createErr := s.repo.Create(transCtx, termination); createErr != nil {
+		transaction.Rollback()
+		var pgErr *pgconn.PgError
+		if errors.As(createErr, &pgErr) && pgErr.Code == "23505" {
+			return nil, pkg.BadRequestError("TerminationAlreadyInProgress", nil)
+		}
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      createErr,
+			Metadata: map[string]string{"function": "GiveNotice", "action": "creating termination"},
+		})
+	}
+
+	checklistID, checklistErr := s.checkOutChecklistFor(transCtx, input.LeaseID, manager.ID.String())
+	if checklistErr != nil {
+		transaction.Rollback()
+		return nil, checklistErr
+	}
+
+	termination.LeaseChecklistID = &checklistID
+	if updateErr := s.repo.Update(transCtx, termination); updateErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "GiveNotice", "action": "linking checklist"},
+		})
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      commitErr,
+			Metadata: map[string]string{"function": "GiveNotice", "action": "committing transaction"},
+		})
+	}
+
+	s.notifyManagerOfNotice(lease, manager, termination)
+	s.notifyWaitlistOfVacancy(ctx, lease.UnitId, input.IntendedMoveOutDate)
+
+	return termination, nil
+}
+
+// checkOutChecklistFor reuses the lease's CHECK_OUT checklist when the PM has
+// already started one, so a notice never leaves two move-out reports.
+func (s *leaseTerminationService) checkOutChecklistFor(
+	ctx context.Context,
+	leaseID, managerID string,
+) (string, error) {
+	existing, err := s.leaseChecklistRepo.GetCheckOutChecklist(ctx, leaseID)
+	if err == nil {
+		return existing.ID.String(), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "checkOutChecklistFor", "action": "fetching checklist"},
+		})
+	}
+
+	checklist, createErr := s.leaseChecklistService.CreateLeaseChecklist(ctx, CreateLeaseChecklistInput{
+		LeaseId:     leaseID,
+		Type:        "CHECK_OUT",
+		CreatedById: managerID,
+	})
+	if createErr != nil {
+		return "", createErr
+	}
+
+	return checklist.ID.String(), nil
+}
+
+// chargeEarlyTerminationFee bills the fee fixed when notice was given, due on
+// the day the tenant leaves. A lease without an account has nowhere to bill
+// it, as with every other charge on such leases.
+func (s *leaseTerminationService) chargeEarlyTerminationFee(
+	ctx context.Context,
+	lease *models.Lease,
+	termination *models.LeaseTermination,
+) error {
+	if termination.EarlyTerminationFee <= 0 || termination.EarlyTerminationChargeID != nil ||
+		lease.FinancialAccountID == nil {
+		return nil
+	}
+
+	dueDate := time.Now()
+	if termination.IntendedMoveOutDate != nil {
+		dueDate = *termination.IntendedMoveOutDate
+	}
+
+	leaseID := lease.ID.String()
+	charge, err := s.financials.Charges.CreateAdHoc(ctx, financials.CreateAdHocChargeInput{
+		FinancialAccountID: *lease.FinancialAccountID,
+		LeaseID:            &leaseID,
+		Name:               "Early termination fee",
+		Category:           financials.CategoryEarlyTerminationFee,
+		Amount:             termination.EarlyTerminationFee,
+		Currency:           lease.RentFeeCurrency,
+		DueDate:            dueDate,
+	})
+	if err != nil {
+		return err
+	}
+
+	chargeID := charge.ID.String()
+	termination.EarlyTerminationChargeID = &chargeID
+
+	return nil
+}
+
+func (s *leaseTerminationService) notifyManagerOfNotice(
+	lease *models.Lease,
+	manager *models.ClientUser,
+	termination *models.LeaseTermination,
+) {
+	if manager.User.Email == "" {
+		return
+	}
+
+	fee := ""
+	if termination.EarlyTerminationFee > 0 {
+		fee = fmt.Sprintf(
+			"%s %s", lease.RentFeeCurrency, lib.FormatAmount(lib.PesewasToCedis(termination.EarlyTerminationFee)),
+		)
+	}
+
+	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+		"lease/notice-to-vacate-manager",
+		emailtemplates.LeaseNoticeToVacateManagerData{
+			ManagerName:         manager.User.Name,
+			TenantName:          lease.Tenant.FirstName,
+			UnitName:            lease.Unit.Name,
+			IntendedMoveOutDate: termination.IntendedMoveOutDate.Format("2 Jan 2006"),
+			Reason:              termination.Reason,
+			EarlyTerminationFee: fee,
+		},
+	)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render lease/notice-to-vacate-manager email template")
+		return
+	}
+
+	go pkg.SendEmail(s.appCtx.Config, pkg.SendEmailInput{
+		Recipient: manager.User.Email,
+		Subject:   lib.PM_LEASE_NOTICE_TO_VACATE_SUBJECT,
+		HtmlBody:  htmlBody,
+		TextBody:  textBody,
+	})
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// Notice is counted in whole days from the day it is given, so the last
+// valid date does not depend on the hour the tenant pressed submit. Leaving
+// after the term ends only makes sense where the lease would hold over, and
+// leaving on the move-out day is never after it, whatever hour either is at.
+func TestValidateNoticeToVacate(t *testing.T) {
+	now := time.Date(2026, 10, 1, 22, 0, 0, 0, time.UTC)
+	moveOut := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	day := func(offset int) time.Time { return time.Date(2026, 10, 1+offset, 9, 0, 0, 0, time.UTC) }
+	ahead := time.FixedZone("GMT+1", 60*60)
+	moveOutAhead := time.Date(2026, 12, 1, 0, 0, 0, 0, ahead)
+
+	cases := []struct {
+		name        string
+		intended    time.Time
+		moveOut     *time.Time
+		mayHoldOver bool
+		noticeDays  int64
+		wantCode    string
+	}{
+		{"exactly the notice period", day(30), &moveOut, false, 30, ""},
+		{"short of the notice period", day(29), &moveOut, false, 30, "NoticePeriodNotMet"},
+		{"no notice period", day(0), &moveOut, false, 0, ""},
+		{"in the past", day(-1), &moveOut, false, 0, "IntendedMoveOutInPast"},
+		{"on move-out", moveOut, &moveOut, false, 30, ""},
+		{"after move-out", moveOut.AddDate(0, 0, 1), &moveOut, false, 30, "IntendedMoveOutAfterLeaseEnd"},
+		{"after move-out on a holdover property", moveOut.AddDate(0, 0, 1), &moveOut, true, 30, ""},
+		{"on move-out, later in the day", moveOut.Add(17 * time.Hour), &moveOut, false, 30, ""},
+		{"on move-out kept at another offset", day(61), &moveOutAhead, false, 30, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateNoticeToVacate(tc.intended, tc.moveOut, tc.mayHoldOver, tc.noticeDays, now)
+			if got := leaseTenantErrorCode(err); got != tc.wantCode {
+				t.Errorf("got %q, want %q", got, tc.wantCode)
+			}
+		})
+	}
+}
+
+// The fee prices breaking the fixed term. Leaving on or after its last day,
+// at any hour, or from a holdover month, breaks nothing.
+func TestEarlyTerminationFee(t *testing.T) {
+	moveOut := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	early := moveOut.AddDate(0, -2, 0)
+	heldOver := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	lease := func(policy string) *models.Lease {
+		return &models.Lease{
+			MoveOutDate:                    &moveOut,
+			RentFee:                        450000,
+			EarlyTerminationFeePolicy:      policy,
+			EarlyTerminationFeeAmount:      200000,
+			EarlyTerminationFeeRentPeriods: 2,
+		}
+	}
+	inHoldover := lease(models.EarlyTerminationFeePolicyFixed)
+	inHoldover.HoldoverStartedAt = &heldOver
+	moveOutAfternoon := moveOut.Add(14 * time.Hour)
+	sameDay := lease(models.EarlyTerminationFeePolicyFixed)
+	sameDay.MoveOutDate = &moveOutAfternoon
+
+	cases := []struct {
+		name     string
+		lease    *models.Lease
+		intended time.Time
+		want     int64
+	}{
+		{"no policy", lease(models.EarlyTerminationFeePolicyNone), early, 0},
+		{"fixed amount", lease(models.EarlyTerminationFeePolicyFixed), early, 200000},
+		{"rent periods", lease(models.EarlyTerminationFeePolicyRentPeriods), early, 900000},
+		{"leaving at term end", lease(models.EarlyTerminationFeePolicyFixed), moveOut, 0},
+		{"leaving on the last day, before a later move-out hour", sameDay, moveOut, 0},
+		{"holding over", inHoldover, early, 0},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := earlyTerminationFee(tc.lease, tc.intended); got != tc.want {
+				t.Errorf("got %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
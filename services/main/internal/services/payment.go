@@ -34,6 +34,7 @@ type paymentService struct {
 	notificationService      NotificationService
 	leaseService             LeaseService
 	tenantApplicationService TenantApplicationService
+	leaseTenantService       LeaseTenantService
 	financials               *financials.Financials
 }
 
@@ -46,6 +47,7 @@ type PaymentServiceDeps struct {
 	NotificationService      NotificationService
 	LeaseService             LeaseService
 	TenantApplicationService TenantApplicationService
+	LeaseTenantService       LeaseTenantService
 	Financials               *financials.Financials
 }
 
@@ -59,6 +61,7 @@ func NewPaymentService(deps PaymentServiceDeps) PaymentService {
 		notificationService:      deps.NotificationService,
 		leaseService:             deps.LeaseService,
 		tenantApplicationService: deps.TenantApplicationService,
+		leaseTenantService:       deps.LeaseTenantService,
 		financials:               deps.Financials,
 	}
 }
@@ -71,6 +74,7 @@ type CreateOfflinePaymentInput struct {
 	Reference               *string
 	Metadata                *map[string]any
 	InitiatedByClientUserID *string // set when a manager initiates; suppresses the submission notification
+	PayerTenantID           *string // the lease tenant paying, when known
 }
 
 func (s *paymentService) CreateOfflinePayment(
@@ -214,14 +218,21 @@ func (s *paymentService) CreateOfflinePayment(
 		})
 	}
 
+	if input.PayerTenantID != nil {
+		if payerErr := s.assertPayerOnInvoiceLease(ctx, invoice, *input.PayerTenantID); payerErr != nil {
+			return nil, payerErr
+		}
+	}
+
 	payment := models.Payment{
-		InvoiceID: input.InvoiceID,
-		Rail:      "OFFLINE",
-		Provider:  &input.Provider,
-		Amount:    input.Amount,
-		Currency:  invoice.Currency,
-		Reference: input.Reference,
-		Status:    "PENDING",
+		InvoiceID:     input.InvoiceID,
+		PayerTenantID: input.PayerTenantID,
+		Rail:          "OFFLINE",
+		Provider:      &input.Provider,
+		Amount:        input.Amount,
+		Currency:      invoice.Currency,
+		Reference:     input.Reference,
+		Status:        "PENDING",
 	}
 
 	initialMetadata := map[string]any{
@@ -359,6 +370,34 @@ func (s *paymentService) CreateOfflinePayment(
 	return &payment, nil
 }
 
+// assertPayerOnInvoiceLease refuses to attribute a payment to a tenant who is
+// not on the lease the invoice bills. Application-stage invoices have no lease
+// yet, so only their applicant can pay them and attribution is not checked.
+func (s *paymentService) assertPayerOnInvoiceLease(
+	ctx context.Context,
+	invoice *models.Invoice,
+	payerTenantID string,
+) error {
+	if invoice.PayerLeaseID == nil {
+		return nil
+	}
+
+	onLease, err := s.leaseTenantService.HasTenant(ctx, *invoice.PayerLeaseID, payerTenantID)
+	if err != nil {
+		return err
+	}
+	if !onLease {
+		return pkg.BadRequestError("PayerNotOnLease", &pkg.RentLoopErrorParams{
+			Metadata: map[string]string{
+				"invoice_id":      invoice.ID.String(),
+				"payer_tenant_id": payerTenantID,
+			},
+		})
+	}
+
+	return nil
+}
+
 type VerifyOfflinePaymentInput struct {
 	VerifiedByID string
 	PaymentID    string
@@ -757,7 +796,8 @@ func failOfflinePayment(
 	return repo.Update(ctx, payment)
 }
 
-// getRemainingInvoiceBalance is what the invoice still expects to receive.
+// getRemainingInvoiceBalance is what the invoice still expects to receive:
+// its total less anything written off and anything paid.
 //
 // Only SUCCESSFUL payments count, deliberately: a PENDING payment is a claim
 // nobody has verified, and treating it as money received would let an
@@ -774,5 +814,5 @@ func getRemainingInvoiceBalance(
 		return 0, err
 	}
 
-	return invoice.TotalAmount - totalPaid, nil
+	return invoice.TotalAmount - invoice.WrittenOffAmount - totalPaid, nil
 }
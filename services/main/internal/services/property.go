@@ -293,23 +293,30 @@ func (s *propertyService) GetProperty(
 }
 
 type UpdatePropertyInput struct {
-	PropertyID  string
-	ClientID    string
-	Name        *string
-	Currency    *string
-	Description lib.Optional[string]
-	Images      lib.Optional[[]string]
-	Tags        lib.Optional[[]string]
-	Modes       lib.Optional[[]string]
-	Latitude    *float64
-	Longitude   *float64
-	Address     *string
-	Country     *string
-	Region      *string
-	City        *string
-	GPSAddress  lib.Optional[string]
-	Type        *string
-	Status      *string
+	PropertyID                    string
+	ClientID                      string
+	Name                          *string
+	Currency                      *string
+	Description                   lib.Optional[string]
+	Images                        lib.Optional[[]string]
+	Tags                          lib.Optional[[]string]
+	Modes                         lib.Optional[[]string]
+	Latitude                      *float64
+	Longitude                     *float64
+	Address                       *string
+	Country                       *string
+	Region                        *string
+	City                          *string
+	GPSAddress                    lib.Optional[string]
+	Type                          *string
+	Status                        *string
+	HoldoverPolicy                *string
+	HoldoverRentPremiumPercent    *int64
+	NoticePeriodDays              *int64
+	BookingCancellationPolicy     *string
+	BookingRequiresUpfrontPayment *bool
+	BookingHoldHours              *int64
+	BookingRequiresGuestList      *bool
 }
 
 func (s *propertyService) UpdateProperty(
@@ -466,6 +473,34 @@ func (s *propertyService) UpdateProperty(
 		}
 	}
 
+	if input.HoldoverPolicy != nil {
+		property.HoldoverPolicy = *input.HoldoverPolicy
+	}
+
+	if input.HoldoverRentPremiumPercent != nil {
+		property.HoldoverRentPremiumPercent = *input.HoldoverRentPremiumPercent
+	}
+
+	if input.NoticePeriodDays != nil {
+		property.NoticePeriodDays = *input.NoticePeriodDays
+	}
+
+	if input.BookingCancellationPolicy != nil {
+		property.BookingCancellationPolicy = *input.BookingCancellationPolicy
+	}
+
+	if input.BookingRequiresUpfrontPayment != nil {
+		property.BookingRequiresUpfrontPayment = *input.BookingRequiresUpfrontPayment
+	}
+
+	if input.BookingHoldHours != nil {
+		property.BookingHoldHours = *input.BookingHoldHours
+	}
+
+	if input.BookingRequiresGuestList != nil {
+		property.BookingRequiresGuestList = *input.BookingRequiresGuestList
+	}
+
 	if updateErr := s.repo.Update(context, property); updateErr != nil {
 		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
 			Err: updateErr,
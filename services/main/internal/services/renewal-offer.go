@@ -0,0 +1,521 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type RenewalOfferService interface {
+	Create(ctx context.Context, input CreateRenewalOfferInput) (*models.RenewalOffer, error)
+	GetOne(ctx context.Context, query repository.GetRenewalOfferQuery) (*models.RenewalOffer, error)
+	List(ctx context.Context, filter repository.ListRenewalOffersFilter) ([]models.RenewalOffer, error)
+	Count(ctx context.Context, filter repository.ListRenewalOffersFilter) (int64, error)
+	Withdraw(ctx context.Context, input WithdrawRenewalOfferInput) (*models.RenewalOffer, error)
+
+	// Accept, Decline and Counter are the tenant's answers. Only a signatory
+	// on the lease may give one: an occupant is not party to the tenancy and
+	// so cannot commit it to another term.
+	Accept(ctx context.Context, input AcceptRenewalOfferInput) (*models.RenewalOffer, error)
+	Decline(ctx context.Context, input DeclineRenewalOfferInput) (*models.RenewalOffer, error)
+	Counter(ctx context.Context, input CounterRenewalOfferInput) (*models.RenewalOffer, error)
+
+	// ExpireDueOffers closes every open offer past its expiry. Run by the
+	// queue; returns how many it closed.
+	ExpireDueOffers(ctx context.Context) (int64, error)
+}
+
+type renewalOfferService struct {
+	appCtx              pkg.AppContext
+	repo                repository.RenewalOfferRepository
+	leaseRepo           repository.LeaseRepository
+	leaseTenantRepo     repository.LeaseTenantRepository
+	leaseService        LeaseService
+	notificationService NotificationService
+}
+
+type RenewalOfferServiceDeps struct {
+	AppCtx              pkg.AppContext
+	Repo                repository.RenewalOfferRepository
+	LeaseRepo           repository.LeaseRepository
+	LeaseTenantRepo     repository.LeaseTenantRepository
+	LeaseService        LeaseService
+	NotificationService NotificationService
+}
+
+func NewRenewalOfferService(deps RenewalOfferServiceDeps) RenewalOfferService {
+	return &renewalOfferService{
+		appCtx:              deps.AppCtx,
+		repo:                deps.Repo,
+		leaseRepo:           deps.LeaseRepo,
+		leaseTenantRepo:     deps.LeaseTenantRepo,
+		leaseService:        deps.LeaseService,
+		notificationService: deps.NotificationService,
+	}
+}
+
+// validateRenewalOfferExpiry requires an offer to close before the lease it
+// continues ends. An offer still open after move-out would renew a tenancy
+// that has already been completed and its unit released.
+func validateRenewalOfferExpiry(expiresAt time.Time, moveOut *time.Time, now time.Time) error {
+	if moveOut == nil {
+		return pkg.BadRequestError("LeaseHasNoMoveOutDate", nil)
+	}
+	if !expiresAt.After(now) {
+		return pkg.BadRequestError("RenewalOfferExpiryInPast", nil)
+	}
+	if expiresAt.After(*moveOut) {
+		return pkg.BadRequestError("RenewalOfferExpiresAfterMoveOut", nil)
+	}
+
+	return nil
+}
+
+// validateRenewalOfferOptions applies RenewLease's fee rules up front, so an
+// option the tenant can see is one they can actually accept.
+func validateRenewalOfferOptions(options []models.RenewalOfferOption) error {
+	if len(options) == 0 {
+		return pkg.BadRequestError("RenewalOfferOptionsRequired", nil)
+	}
+
+	for _, option := range options {
+		if option.StayDuration <= 0 {
+			return pkg.BadRequestError("RenewalOfferStayDurationMustBePositive", nil)
+		}
+		for _, fee := range option.Fees {
+			if fee.Amount <= 0 {
+				return pkg.BadRequestError("RenewalFeeMustBePositive", nil)
+			}
+			if !RenewalFeeCategoryAllowed(fee.Category) {
+				return pkg.BadRequestError("RenewalFeeCategoryInvalid", nil)
+			}
+		}
+	}
+
+	return nil
+}
+
+// renewalFeesFromOption maps an accepted option's fees onto RenewLease.
+func renewalFeesFromOption(option models.RenewalOfferOption) []RenewalFee {
+	if len(option.Fees) == 0 {
+		return nil
+	}
+
+	fees := make([]RenewalFee, 0, len(option.Fees))
+	for _, fee := range option.Fees {
+		fees = append(fees, RenewalFee{Category: fee.Category, Name: fee.Name, Amount: fee.Amount})
+	}
+
+	return fees
+}
+
+type CreateRenewalOfferInput struct {
+	LeaseID     string
+	Message     *string
+	Options     []models.RenewalOfferOption
+	ExpiresAt   time.Time
+	CreatedById string
+}
+
+func (s *renewalOfferService) Create(
+	ctx context.Context,
+	input CreateRenewalOfferInput,
+) (*models.RenewalOffer, error) {
+	lease, err := s.leaseRepo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{
+		ID:       input.LeaseID,
+		Populate: &[]string{"Unit", "Tenant.TenantAccount"},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("LeaseNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Create", "action": "fetching lease"},
+		})
+	}
+
+	// Completed leases can still be renewed by a PM, but an offer is made
+	// before move-out — afterwards there is nothing left to offer to continue.
+	if lease.Status != "Lease.Status.Active" {
+		return nil, pkg.BadRequestError("LeaseIsNotActive", nil)
+	}
+
+	if expiryErr := validateRenewalOfferExpiry(input.ExpiresAt, lease.MoveOutDate, time.Now()); expiryErr != nil {
+		return nil, expiryErr
+	}
+
+	if optionsErr := validateRenewalOfferOptions(input.Options); optionsErr != nil {
+		return nil, optionsErr
+	}
+
+	children, childErr := s.leaseRepo.ListChildren(ctx, input.LeaseID)
+	if childErr != nil {
+		return nil, pkg.InternalServerError(childErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      childErr,
+			Metadata: map[string]string{"function": "Create", "action": "listing children"},
+		})
+	}
+	if HasBlockingRenewal(*children) {
+		return nil, pkg.BadRequestError("LeaseAlreadyRenewed", nil)
+	}
+
+	offer := &models.RenewalOffer{
+		LeaseID:     input.LeaseID,
+		Status:      "RenewalOffer.Status.Sent",
+		Message:     input.Message,
+		Options:     input.Options,
+		ExpiresAt:   input.ExpiresAt,
+		CreatedById: input.CreatedById,
+	}
+
+	if createErr := s.repo.Create(ctx, offer); createErr != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(createErr, &pgErr) && pgErr.Code == "23505" {
+			return nil, pkg.BadRequestError("RenewalOfferAlreadyOpen", nil)
+		}
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      createErr,
+			Metadata: map[string]string{"function": "Create", "action": "creating renewal offer"},
+		})
+	}
+
+	s.notifyTenantOfOffer(lease, offer)
+
+	return offer, nil
+}
+
+// notifyTenantOfOffer tells the lease's primary tenant an offer is waiting.
+// Best-effort, like the lease lifecycle notifications: the offer stands
+// whether or not the message arrives, and the tenant app lists it either way.
+func (s *renewalOfferService) notifyTenantOfOffer(lease *models.Lease, offer *models.RenewalOffer) {
+	smsMessage := strings.NewReplacer(
+		"{{tenant_name}}", lease.Tenant.FirstName,
+		"{{unit_name}}", lease.Unit.Name,
+		"{{expiry_date}}", offer.ExpiresAt.Format("2 Jan 2006"),
+	).Replace(lib.LEASE_RENEWAL_OFFER_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: lease.Tenant.Phone,
+			Message:   smsMessage,
+		},
+	)
+
+	if lease.Tenant.TenantAccount != nil {
+		tenantAccountID := lease.Tenant.TenantAccount.ID.String()
+		data := map[string]string{
+			"type":             "LEASE_RENEWAL_OFFER",
+			"lease_id":         lease.ID.String(),
+			"renewal_offer_id": offer.ID.String(),
+		}
+		go func() {
+			_ = s.notificationService.SendToTenantAccount(
+				context.Background(),
+				tenantAccountID,
+				lib.LEASE_RENEWAL_OFFER_SUBJECT,
+				smsMessage,
+				data,
+			)
+		}()
+	}
+}
+
+func (s *renewalOfferService) GetOne(
+	ctx context.Context,
+	query repository.GetRenewalOfferQuery,
+) (*models.RenewalOffer, error) {
+	offer, err := s.repo.GetOne(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("RenewalOfferNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GetOne", "action": "fetching renewal offer"},
+		})
+	}
+	return offer, nil
+}
+
+func (s *renewalOfferService) List(
+	ctx context.Context,
+	filter repository.ListRenewalOffersFilter,
+) ([]models.RenewalOffer, error) {
+	result, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "List", "action": "listing renewal offers"},
+		})
+	}
+	return *result, nil
+}
+
+func (s *renewalOfferService) Count(
+	ctx context.Context,
+	filter repository.ListRenewalOffersFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Count", "action": "counting renewal offers"},
+		})
+	}
+	return count, nil
+}
+
+type WithdrawRenewalOfferInput struct {
+	ID           string
+	LeaseID      string
+	ClientUserID string
+}
+
+func (s *renewalOfferService) Withdraw(
+	ctx context.Context,
+	input WithdrawRenewalOfferInput,
+) (*models.RenewalOffer, error) {
+	offer, err := s.GetOne(ctx, repository.GetRenewalOfferQuery{ID: input.ID, LeaseID: input.LeaseID})
+	if err != nil {
+		return nil, err
+	}
+
+	if offer.Status != "RenewalOffer.Status.Sent" {
+		return nil, pkg.BadRequestError("RenewalOfferNotOpen", nil)
+	}
+
+	now := time.Now()
+	offer.Status = "RenewalOffer.Status.Withdrawn"
+	offer.WithdrawnAt = &now
+	offer.WithdrawnById = &input.ClientUserID
+
+	if updateErr := s.repo.Update(ctx, offer); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Withdraw", "action": "saving renewal offer"},
+		})
+	}
+
+	return offer, nil
+}
+
+// RespondToRenewalOfferInput identifies the offer and who is answering it.
+type RespondToRenewalOfferInput struct {
+	ID       string
+	LeaseID  string
+	TenantID string
+}
+
+// respondable loads an offer the tenant may still answer.
+//
+// Expiry is checked here as well as by the queue: the sweep runs on a
+// schedule, so an offer can sit past its expiry for up to an interval and
+// must not be acceptable in that gap.
+func (s *renewalOfferService) respondable(
+	ctx context.Context,
+	input RespondToRenewalOfferInput,
+) (*models.RenewalOffer, error) {
+	offer, err := s.GetOne(ctx, repository.GetRenewalOfferQuery{
+		ID:       input.ID,
+		LeaseID:  input.LeaseID,
+		Populate: &[]string{"Lease"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	leaseTenant, tenantErr := s.leaseTenantRepo.GetOne(ctx, repository.GetLeaseTenantQuery{
+		LeaseID:  input.LeaseID,
+		TenantID: &input.TenantID,
+	})
+	if tenantErr != nil {
+		if errors.Is(tenantErr, gorm.ErrRecordNotFound) {
+			return nil, pkg.ForbiddenError("LeaseDoesNotBelongToTenant", nil)
+		}
+		return nil, pkg.InternalServerError(tenantErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      tenantErr,
+			Metadata: map[string]string{"function": "respondable", "action": "fetching lease tenant"},
+		})
+	}
+	if !leaseTenant.IsSignatory() {
+		return nil, pkg.ForbiddenError("RenewalOfferResponderNotSignatory", nil)
+	}
+
+	if offer.Status != "RenewalOffer.Status.Sent" {
+		return nil, pkg.BadRequestError("RenewalOfferNotOpen", nil)
+	}
+
+	if !time.Now().Before(offer.ExpiresAt) {
+		offer.Status = "RenewalOffer.Status.Expired"
+		if updateErr := s.repo.Update(ctx, offer); updateErr != nil {
+			return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+				Err:      updateErr,
+				Metadata: map[string]string{"function": "respondable", "action": "expiring renewal offer"},
+			})
+		}
+		return nil, pkg.BadRequestError("RenewalOfferExpired", nil)
+	}
+
+	return offer, nil
+}
+
+// closeWithResponse records the tenant's answer and closes the offer.
+func (s *renewalOfferService) closeWithResponse(
+	ctx context.Context,
+	offer *models.RenewalOffer,
+	status string,
+	tenantID string,
+) error {
+	now := time.Now()
+	offer.Status = status
+	offer.RespondedAt = &now
+	offer.RespondedByTenantID = &tenantID
+
+	return s.repo.Update(ctx, offer)
+}
+
+type AcceptRenewalOfferInput struct {
+	RespondToRenewalOfferInput
+	OptionIndex int
+}
+
+// Accept renews the lease on the chosen option's terms.
+//
+// The renewal starts on the current lease's move-out date — a continuous
+// renewal — and is attributed to the PM who made the offer, since it is their
+// terms being taken up. RenewLease runs its own transaction, so the lease is
+// renewed before the offer is closed; should closing it then fail, the offer
+// cannot be accepted twice because the lease now has a blocking renewal.
+func (s *renewalOfferService) Accept(
+	ctx context.Context,
+	input AcceptRenewalOfferInput,
+) (*models.RenewalOffer, error) {
+	offer, err := s.respondable(ctx, input.RespondToRenewalOfferInput)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.OptionIndex < 0 || input.OptionIndex >= len(offer.Options) {
+		return nil, pkg.BadRequestError("RenewalOfferOptionNotFound", nil)
+	}
+	option := offer.Options[input.OptionIndex]
+
+	if offer.Lease.MoveOutDate == nil {
+		return nil, pkg.BadRequestError("LeaseHasNoMoveOutDate", nil)
+	}
+
+	renewal, renewErr := s.leaseService.RenewLease(ctx, RenewLeaseInput{
+		LeaseID:               offer.LeaseID,
+		MoveInDate:            *offer.Lease.MoveOutDate,
+		StayDuration:          option.StayDuration,
+		StayDurationFrequency: option.StayDurationFrequency,
+		RentFee:               &option.RentFee,
+		Fees:                  renewalFeesFromOption(option),
+		ClientUserID:          offer.CreatedById,
+	})
+	if renewErr != nil {
+		return nil, renewErr
+	}
+
+	renewalID := renewal.ID.String()
+	offer.AcceptedOptionIndex = &input.OptionIndex
+	offer.RenewalLeaseID = &renewalID
+
+	if updateErr := s.closeWithResponse(ctx, offer, "RenewalOffer.Status.Accepted", input.TenantID); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function":         "Accept",
+				"action":           "saving accepted renewal offer",
+				"renewal_lease_id": renewalID,
+			},
+		})
+	}
+
+	return offer, nil
+}
+
+type DeclineRenewalOfferInput struct {
+	RespondToRenewalOfferInput
+	Reason *string
+}
+
+func (s *renewalOfferService) Decline(
+	ctx context.Context,
+	input DeclineRenewalOfferInput,
+) (*models.RenewalOffer, error) {
+	offer, err := s.respondable(ctx, input.RespondToRenewalOfferInput)
+	if err != nil {
+		return nil, err
+	}
+
+	offer.DeclineReason = input.Reason
+
+	if updateErr := s.closeWithResponse(ctx, offer, "RenewalOffer.Status.Declined", input.TenantID); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Decline", "action": "saving declined renewal offer"},
+		})
+	}
+
+	return offer, nil
+}
+
+type CounterRenewalOfferInput struct {
+	RespondToRenewalOfferInput
+	RentFee               *int64
+	StayDuration          *int64
+	StayDurationFrequency *string
+	Message               *string
+}
+
+func (s *renewalOfferService) Counter(
+	ctx context.Context,
+	input CounterRenewalOfferInput,
+) (*models.RenewalOffer, error) {
+	if input.RentFee == nil && input.StayDuration == nil && input.Message == nil {
+		return nil, pkg.BadRequestError("RenewalOfferCounterEmpty", nil)
+	}
+
+	offer, err := s.respondable(ctx, input.RespondToRenewalOfferInput)
+	if err != nil {
+		return nil, err
+	}
+
+	offer.CounterRentFee = input.RentFee
+	offer.CounterStayDuration = input.StayDuration
+	offer.CounterStayDurationFrequency = input.StayDurationFrequency
+	offer.CounterMessage = input.Message
+
+	if updateErr := s.closeWithResponse(ctx, offer, "RenewalOffer.Status.Countered", input.TenantID); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Counter", "action": "saving countered renewal offer"},
+		})
+	}
+
+	return offer, nil
+}
+
+func (s *renewalOfferService) ExpireDueOffers(ctx context.Context) (int64, error) {
+	expired, err := s.repo.ExpireDue(ctx, time.Now())
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "ExpireDueOffers", "action": "expiring renewal offers"},
+		})
+	}
+
+	return expired, nil
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// An offer must lapse by move-out: one still open afterwards would renew a
+// tenancy that has already completed and released its unit. Expiring exactly
+// at move-out is the latest allowed.
+func TestValidateRenewalOfferExpiry(t *testing.T) {
+	now := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	moveOut := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		expiresAt time.Time
+		moveOut   *time.Time
+		wantCode  string
+	}{
+		{"before move-out", now.AddDate(0, 0, 14), &moveOut, ""},
+		{"exactly at move-out", moveOut, &moveOut, ""},
+		{"after move-out", moveOut.Add(time.Hour), &moveOut, "RenewalOfferExpiresAfterMoveOut"},
+		{"already past", now.Add(-time.Minute), &moveOut, "RenewalOfferExpiryInPast"},
+		{"lease without move-out", now.AddDate(0, 0, 14), nil, "LeaseHasNoMoveOutDate"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := leaseTenantErrorCode(validateRenewalOfferExpiry(tc.expiresAt, tc.moveOut, now))
+			if got != tc.wantCode {
+				t.Errorf("got %q, want %q", got, tc.wantCode)
+			}
+		})
+	}
+}
+
+// Options are held to RenewLease's fee rules when the offer is made, so the
+// tenant is never shown terms that would fail only once they accept.
+func TestValidateRenewalOfferOptions(t *testing.T) {
+	year := models.RenewalOfferOption{RentFee: 450000, StayDuration: 12, StayDurationFrequency: "Months"}
+
+	withFee := func(category string, amount int64) []models.RenewalOfferOption {
+		option := year
+		option.Fees = []models.RenewalOfferFee{{Category: category, Name: "Fee", Amount: amount}}
+		return []models.RenewalOfferOption{option}
+	}
+
+	cases := []struct {
+		name     string
+		options  []models.RenewalOfferOption
+		wantCode string
+	}{
+		{"one plain option", []models.RenewalOfferOption{year}, ""},
+		{"deposit top-up", withFee("SECURITY_DEPOSIT", 15000), ""},
+		{"no options", nil, "RenewalOfferOptionsRequired"},
+		{"rent as a fee", withFee("RENT", 15000), "RenewalFeeCategoryInvalid"},
+		{"zero fee", withFee("OTHER", 0), "RenewalFeeMustBePositive"},
+		{
+			"zero-length term",
+			[]models.RenewalOfferOption{{RentFee: 450000, StayDurationFrequency: "Months"}},
+			"RenewalOfferStayDurationMustBePositive",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := leaseTenantErrorCode(validateRenewalOfferOptions(tc.options))
+			if got != tc.wantCode {
+				t.Errorf("got %q, want %q", got, tc.wantCode)
+			}
+		})
+	}
+}
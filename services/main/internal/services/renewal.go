@@ -117,6 +117,21 @@ func OverlapsParentTerm(moveIn time.Time, parentMoveOut *time.Time) bool {
 	return moveIn.Before(*parentMoveOut)
 }
 
+// renewedCoTenants carries everyone but the primary onto the renewal in the
+// roles they held. The primary is carried separately, as the renewal's
+// TenantId.
+func renewedCoTenants(tenants []models.LeaseTenant) []LeaseTenantInput {
+	coTenants := make([]LeaseTenantInput, 0, len(tenants))
+	for _, tenant := range tenants {
+		if tenant.Role == models.LeaseTenantRolePrimary {
+			continue
+		}
+		coTenants = append(coTenants, LeaseTenantInput{TenantID: tenant.TenantID, Role: tenant.Role})
+	}
+
+	return coTenants
+}
+
 // UnitHasCapacity reports whether a unit can take one more tenancy.
 //
 // A count rather than a boolean because rooms may hold several tenants: two of
@@ -128,7 +143,7 @@ func UnitHasCapacity(occupying, maxOccupants int64) bool {
 func (s *leaseService) RenewLease(ctx context.Context, input RenewLeaseInput) (*models.Lease, error) {
 	parent, err := s.repo.GetOneWithPopulate(ctx, repository.GetLeaseQuery{
 		ID:       input.LeaseID,
-		Populate: &[]string{"Unit"},
+		Populate: &[]string{"Unit", "Tenants"},
 	})
 	if err != nil {
 		return nil, pkg.NotFoundError("LeaseNotFound", &pkg.RentLoopErrorParams{Err: err})
@@ -219,6 +234,7 @@ func (s *leaseService) RenewLease(ctx context.Context, input RenewLeaseInput) (*
 		StayDurationFrequency:     input.StayDurationFrequency,
 		LeaseAgreementDocumentUrl: input.LeaseAgreementDocumentUrl,
 		ParentLeaseId:             &parentID,
+		CoTenants:                 renewedCoTenants(parent.Tenants),
 	})
 	if createErr != nil {
 		transaction.Rollback()
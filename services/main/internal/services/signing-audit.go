@@ -0,0 +1,404 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/objectstorage"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/documentpdf"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// chainDocumentSignature fills in sig's content and event hashes before it is
+// created. It must run in the transaction that creates sig: the document row
+// is locked until it commits, so two signatures on one document cannot both
+// chain onto the same predecessor.
+func (s *signingService) chainDocumentSignature(
+	transCtx context.Context,
+	sig *models.DocumentSignature,
+	now time.Time,
+) error {
+	document, err := s.repo.GetDocumentForSigningUpdate(transCtx, sig.DocumentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkg.NotFoundError("DocumentNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "chainDocumentSignature",
+				"action":   "locking document",
+			},
+		})
+	}
+
+	previous, previousErr := s.repo.GetLatestChainedDocumentSignature(transCtx, sig.DocumentID)
+	if previousErr != nil && !errors.Is(previousErr, gorm.ErrRecordNotFound) {
+		return pkg.InternalServerError(previousErr.Error(), &pkg.RentLoopErrorParams{
+			Err: previousErr,
+			Metadata: map[string]string{
+				"function": "chainDocumentSignature",
+				"action":   "fetching previous signature",
+			},
+		})
+	}
+
+	// A signature on a revision covers that revision's content, whatever the
+	// document holds now.
+	contentHash := documentContentHash(document.Content)
+	if sig.DocumentRevisionID != nil {
+		revision, revisionErr := s.documentService.GetRevision(transCtx, repository.GetDocumentRevisionQuery{
+			ID:         *sig.DocumentRevisionID,
+			DocumentID: sig.DocumentID,
+		})
+		if revisionErr != nil {
+			return revisionErr
+		}
+		contentHash = revision.ContentHash
+	}
+
+	// Set here rather than left to gorm so the hash covers the exact time
+	// stored: Postgres keeps microseconds.
+	sig.CreatedAt = now.UTC().Truncate(time.Microsecond)
+	sig.ContentHash = contentHash
+	sig.PreviousEventHash = nil
+	if previous != nil {
+		sig.PreviousEventHash = &previous.EventHash
+	}
+	sig.EventHash = signatureEventHash(*sig)
+
+	return nil
+}
+
+// documentContentHash is the SHA-256 of a document's content. The JSON is
+// re-encoded first, with object keys sorted, so the hash survives the
+// content's round trip through jsonb, which does not keep key order or
+// whitespace. Content that is not JSON is hashed as it is.
+func documentContentHash(content []byte) string {
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err == nil {
+		if canonical, marshalErr := json.Marshal(value); marshalErr == nil {
+			content = canonical
+		}
+	}
+
+	return sha256Hex(content)
+}
+
+// signatureEventHash is the SHA-256 of everything recorded about a signing
+// event, including how the signer was verified and the hash of the event
+// before it.
+func signatureEventHash(sig models.DocumentSignature) string {
+	event, _ := json.Marshal([]string{
+		lib.SafeString(sig.PreviousEventHash),
+		sig.DocumentID,
+		sig.ContentHash,
+		sig.Role,
+		lib.SafeString(sig.TenantID),
+		lib.SafeString(sig.GuarantorID),
+		lib.SafeString(sig.SignedByID),
+		lib.SafeString(sig.SignedByName),
+		sig.SignatureUrl,
+		sig.IPAddress,
+		lib.SafeString(sig.UserAgent),
+		sig.VerificationMethod,
+		sig.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+
+	return sha256Hex(event)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// signatureChainValid reports, for each of a document's signatures in the
+// order they were made, whether its event hash still matches its record and
+// the signature before it. Signatures made before hashing carry no hashes,
+// sit outside the chain and are reported as invalid.
+func signatureChainValid(signatures []models.DocumentSignature) []bool {
+	valid := make([]bool, len(signatures))
+
+	var previous *string
+	for i, sig := range signatures {
+		if sig.EventHash == "" {
+			continue
+		}
+
+		linked := (previous == nil && sig.PreviousEventHash == nil) ||
+			(previous != nil && sig.PreviousEventHash != nil && *previous == *sig.PreviousEventHash)
+		valid[i] = linked && signatureEventHash(sig) == sig.EventHash
+
+		eventHash := sig.EventHash
+		previous = &eventHash
+	}
+
+	return valid
+}
+
+type VerifyDocumentSignaturesInput struct {
+	DocumentID string
+	// Content is a version of the document to check against each signature's
+	// content hash. The document's current content is checked when it is nil.
+	Content []byte
+	// PdfUrl is a PDF to check against the signed PDFs and audit
+	// certificates produced for the document. It must be an object in our
+	// storage; nothing is fetched from anywhere else.
+	PdfUrl *string
+}
+
+// DocumentSignatureCheck is one signature's part of a verification.
+type DocumentSignatureCheck struct {
+	Signature models.DocumentSignature
+	// EventHashValid is false when the signature's record or its place in
+	// the chain has changed since it was made.
+	EventHashValid bool
+	// ContentMatches is whether the content checked is what was signed.
+	ContentMatches bool
+}
+
+type DocumentSignatureVerification struct {
+	DocumentID  string
+	ContentHash string
+	// ChainIntact is true when every hashed signature checks out.
+	ChainIntact bool
+	Signatures  []DocumentSignatureCheck
+	// PdfHash is the hash of the PDF checked, nil when none was given.
+	PdfHash *string
+	// PdfMatchedBy names what the PDF was recorded as: "LEASE_AGREEMENT" or
+	// "AUDIT_CERTIFICATE". Nil when it matches nothing on record.
+	PdfMatchedBy *string
+}
+
+// VerifyDocumentSignatures checks a document's signatures against their
+// recorded hashes, and a given version of its content or PDF against what was
+// signed.
+func (s *signingService) VerifyDocumentSignatures(
+	ctx context.Context,
+	input VerifyDocumentSignaturesInput,
+) (*DocumentSignatureVerification, error) {
+	document, err := s.documentRepo.GetByID(ctx, input.DocumentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("DocumentNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "VerifyDocumentSignatures",
+				"action":   "fetching document",
+			},
+		})
+	}
+
+	signatures, listErr := s.listDocumentSignaturesInOrder(ctx, input.DocumentID, []string{"LeaseAgreementDocument"})
+	if listErr != nil {
+		return nil, listErr
+	}
+
+	content := input.Content
+	if content == nil {
+		content = document.Content
+	}
+
+	verification := &DocumentSignatureVerification{
+		DocumentID:  input.DocumentID,
+		ContentHash: documentContentHash(content),
+		ChainIntact: true,
+		Signatures:  make([]DocumentSignatureCheck, 0, len(signatures)),
+	}
+
+	valid := signatureChainValid(signatures)
+	for i, sig := range signatures {
+		if sig.EventHash != "" && !valid[i] {
+			verification.ChainIntact = false
+		}
+		verification.Signatures = append(verification.Signatures, DocumentSignatureCheck{
+			Signature:      sig,
+			EventHashValid: valid[i],
+			ContentMatches: sig.ContentHash != "" && sig.ContentHash == verification.ContentHash,
+		})
+	}
+
+	if input.PdfUrl != nil {
+		pdf, fetchErr := s.appCtx.Clients.ObjectStorage.GetObject(ctx, *input.PdfUrl)
+		if fetchErr != nil {
+			if errors.Is(fetchErr, objectstorage.ErrNotStoredObject) {
+				return nil, pkg.BadRequestError("PdfNotInStorage", &pkg.RentLoopErrorParams{Err: fetchErr})
+			}
+			return nil, pkg.BadRequestError("PdfCouldNotBeFetched", &pkg.RentLoopErrorParams{Err: fetchErr})
+		}
+		pdfHash := sha256Hex(pdf)
+		verification.PdfHash = &pdfHash
+
+		matchedBy, matchErr := s.recordedPdfMatch(ctx, input.DocumentID, signatures, pdfHash)
+		if matchErr != nil {
+			return nil, matchErr
+		}
+		verification.PdfMatchedBy = matchedBy
+	}
+
+	return verification, nil
+}
+
+// recordedPdfMatch names the PDF produced for the document whose hash is
+// pdfHash: the signed lease agreement, or a signing envelope's audit
+// certificate.
+func (s *signingService) recordedPdfMatch(
+	ctx context.Context,
+	documentID string,
+	signatures []models.DocumentSignature,
+	pdfHash string,
+) (*string, error) {
+	for _, sig := range signatures {
+		lad := sig.LeaseAgreementDocument
+		if lad != nil && lad.DocumentHash != nil && *lad.DocumentHash == pdfHash {
+			return lib.StringPointer("LEASE_AGREEMENT"), nil
+		}
+	}
+
+	envelopes, err := s.envelopeRepo.List(ctx, repository.ListSigningEnvelopesFilter{
+		FilterQuery: lib.FilterQuery{Page: 1, PageSize: 100},
+		DocumentID:  &documentID,
+	})
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "recordedPdfMatch",
+				"action":   "listing signing envelopes",
+			},
+		})
+	}
+	for _, envelope := range *envelopes {
+		if envelope.AuditCertificateHash != nil && *envelope.AuditCertificateHash == pdfHash {
+			return lib.StringPointer("AUDIT_CERTIFICATE"), nil
+		}
+	}
+
+	return nil, nil
+}
+
+// listDocumentSignaturesInOrder is every signature on the document, oldest
+// first, the order they chain in.
+func (s *signingService) listDocumentSignaturesInOrder(
+	ctx context.Context,
+	documentID string,
+	populate []string,
+) ([]models.DocumentSignature, error) {
+	signatures, err := s.repo.ListDocumentSignatures(
+		ctx,
+		lib.FilterQuery{
+			Page:     1,
+			PageSize: 100,
+			OrderBy:  "document_signatures.created_at",
+			Order:    "asc",
+			Populate: &populate,
+		},
+		repository.ListDocumentSignaturesFilter{DocumentID: &documentID},
+	)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "listDocumentSignaturesInOrder",
+				"action":   "listing document signatures",
+			},
+		})
+	}
+
+	return *signatures, nil
+}
+
+// storeSigningAuditCertificate renders the audit certificate of a completed
+// envelope's document and records it on the envelope. It runs once the last
+// signature is in, away from the signer's request, so failures are logged.
+func (s *signingService) storeSigningAuditCertificate(envelopeID string) {
+	ctx := context.Background()
+	logger := log.WithField("signing_envelope_id", envelopeID)
+
+	envelope, err := s.envelopeRepo.GetOne(ctx, repository.GetSigningEnvelopeQuery{
+		ID:       envelopeID,
+		Populate: &[]string{"Document"},
+	})
+	if err != nil {
+		logger.WithError(err).Error("failed to load signing envelope for audit certificate")
+		return
+	}
+
+	signatures, listErr := s.listDocumentSignaturesInOrder(
+		ctx,
+		envelope.DocumentID,
+		[]string{"SignedBy.User", "Tenant", "Guarantor"},
+	)
+	if listErr != nil {
+		logger.WithError(listErr).Error("failed to list signatures for audit certificate")
+		return
+	}
+
+	rendered := make([]documentpdf.Signature, 0, len(signatures))
+	for _, sig := range signatures {
+		// The certificate is evidence of the record, so a signature image that
+		// cannot be fetched is left off rather than holding the rest back.
+		image, _ := fetchImage(ctx, s.appCtx.Clients.ObjectStorage, sig.SignatureUrl)
+		rendered = append(rendered, documentpdf.Signature{
+			Role:               sig.Role,
+			Name:               signerName(sig),
+			SignedAt:           sig.CreatedAt,
+			IPAddress:          sig.IPAddress,
+			UserAgent:          lib.SafeString(sig.UserAgent),
+			VerificationMethod: sig.VerificationMethod,
+			ContentHash:        sig.ContentHash,
+			EventHash:          sig.EventHash,
+			Image:              image,
+		})
+	}
+
+	completedAt := time.Now()
+	if envelope.CompletedAt != nil {
+		completedAt = *envelope.CompletedAt
+	}
+
+	pdf, renderErr := documentpdf.RenderAuditCertificate(documentpdf.AuditCertificateInput{
+		Title:       envelope.Document.Title,
+		DocumentID:  envelope.DocumentID,
+		CompletedAt: completedAt,
+		Signatures:  rendered,
+	})
+	if renderErr != nil {
+		logger.WithError(renderErr).Error("failed to render audit certificate")
+		return
+	}
+
+	url, uploadErr := s.appCtx.Clients.ObjectStorage.PutObject(ctx, objectstorage.PutObjectInput{
+		Key:         fmt.Sprintf("signing-certificates/%s.pdf", envelopeID),
+		ContentType: "application/pdf",
+		Body:        pdf,
+	})
+	if uploadErr != nil {
+		logger.WithError(uploadErr).Error("failed to upload audit certificate")
+		return
+	}
+
+	hash := sha256Hex(pdf)
+	envelope.AuditCertificateUrl = &url
+	envelope.AuditCertificateHash = &hash
+	if updateErr := s.envelopeRepo.Update(ctx, envelope); updateErr != nil {
+		logger.WithError(updateErr).Error("failed to store audit certificate on signing envelope")
+	}
+}
@@ -0,0 +1,137 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// Content comes back from jsonb with its keys reordered and whitespace
+// dropped. The hash must not change across that round trip, or every
+// signature would stop matching the document it was made on.
+func TestDocumentContentHash(t *testing.T) {
+	signed := documentContentHash([]byte(`{"root": {"type": "root", "children": [{"text": "Rent", "format": 1}]}}`))
+
+	cases := []struct {
+		name    string
+		content string
+		same    bool
+	}{
+		{"same content", `{"root": {"type": "root", "children": [{"text": "Rent", "format": 1}]}}`, true},
+		{"keys reordered", `{"root":{"children":[{"format":1,"text":"Rent"}],"type":"root"}}`, true},
+		{"text edited", `{"root": {"type": "root", "children": [{"text": "Rent!", "format": 1}]}}`, false},
+		{"format edited", `{"root": {"type": "root", "children": [{"text": "Rent", "format": 2}]}}`, false},
+		{"child added", `{"root": {"type": "root", "children": [{"format": 1, "text": "Rent"}, {}]}}`, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := documentContentHash([]byte(tc.content)) == signed
+			if got != tc.same {
+				t.Errorf("hash matches signed content = %v, want %v", got, tc.same)
+			}
+		})
+	}
+}
+
+// Each signature's event hash covers its record and the hash before it, so
+// editing, reordering or dropping a signature shows up. Signatures made
+// before hashing sit outside the chain without breaking it.
+func TestSignatureChainValid(t *testing.T) {
+	at := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	chain := func(signatures ...models.DocumentSignature) []models.DocumentSignature {
+		var previous *string
+		for i := range signatures {
+			if signatures[i].IPAddress == "legacy" {
+				continue
+			}
+			signatures[i].CreatedAt = at.Add(time.Duration(i) * time.Minute)
+			signatures[i].ContentHash = documentContentHash([]byte(`{"root":{}}`))
+			signatures[i].PreviousEventHash = previous
+			signatures[i].EventHash = signatureEventHash(signatures[i])
+			eventHash := signatures[i].EventHash
+			previous = &eventHash
+		}
+		return signatures
+	}
+	signature := func(role string) models.DocumentSignature {
+		return models.DocumentSignature{
+			DocumentID:         "doc",
+			Role:               role,
+			IPAddress:          "197.251.1.10",
+			VerificationMethod: "LINK",
+		}
+	}
+	legacy := models.DocumentSignature{DocumentID: "doc", Role: "TENANT", IPAddress: "legacy"}
+
+	cases := []struct {
+		name       string
+		signatures func() []models.DocumentSignature
+		want       []bool
+	}{
+		{
+			"intact",
+			func() []models.DocumentSignature {
+				return chain(signature("TENANT"), signature("GUARANTOR"), signature("PROPERTY_MANAGER"))
+			},
+			[]bool{true, true, true},
+		},
+		{
+			"legacy signature before the chain",
+			func() []models.DocumentSignature {
+				return chain(legacy, signature("GUARANTOR"), signature("PROPERTY_MANAGER"))
+			},
+			[]bool{false, true, true},
+		},
+		{
+			"IP address edited",
+			func() []models.DocumentSignature {
+				signatures := chain(signature("TENANT"), signature("GUARANTOR"), signature("PROPERTY_MANAGER"))
+				signatures[1].IPAddress = "8.8.8.8"
+				return signatures
+			},
+			[]bool{true, false, true},
+		},
+		{
+			"verification method edited",
+			func() []models.DocumentSignature {
+				signatures := chain(signature("TENANT"), signature("GUARANTOR"))
+				signatures[0].VerificationMethod = "OTP_SMS"
+				return signatures
+			},
+			[]bool{false, true},
+		},
+		{
+			"signature removed",
+			func() []models.DocumentSignature {
+				signatures := chain(signature("TENANT"), signature("GUARANTOR"), signature("PROPERTY_MANAGER"))
+				return []models.DocumentSignature{signatures[0], signatures[2]}
+			},
+			[]bool{true, false},
+		},
+		{
+			"signatures reordered",
+			func() []models.DocumentSignature {
+				signatures := chain(signature("TENANT"), signature("GUARANTOR"))
+				return []models.DocumentSignature{signatures[1], signatures[0]}
+			},
+			[]bool{false, false},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := signatureChainValid(tc.signatures())
+			if len(got) != len(tc.want) {
+				t.Fatalf("got %d results, want %d", len(got), len(tc.want))
+			}
+			for i := range got {
+				if got[i] != tc.want[i] {
+					t.Errorf("signature %d valid = %v, want %v", i, got[i], tc.want[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,633 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// signingEnvelopeNotifyPopulate is what an envelope needs loaded to invite
+// its signers and write to its PM.
+var signingEnvelopeNotifyPopulate = []string{"Document", "CreatedBy.User"}
+
+type SigningEnvelopeSignerInput struct {
+	Role         string
+	SigningOrder int64
+	TenantID     *string
+	GuarantorID  *string
+	SignerName   *string
+	SignerEmail  *string
+	SignerPhone  *string
+}
+
+type CreateSigningEnvelopeInput struct {
+	DocumentID           string
+	TenantApplicationID  *string
+	LeaseID              *string
+	LeaseTerminationID   *string
+	LeaseAmendmentID     *string
+	Signers              []SigningEnvelopeSignerInput
+	PMSigningOrder       *int64
+	ExpiryPolicy         *string
+	TokenValidityDays    *int64
+	ReminderIntervalDays *int64
+	CreatedByID          string
+}
+
+// CreateSigningEnvelope issues a token for each signer and invites only the
+// first step. Later steps are invited as the ones before them finish signing.
+func (s *signingService) CreateSigningEnvelope(
+	ctx context.Context,
+	input CreateSigningEnvelopeInput,
+) (*models.SigningEnvelope, error) {
+	if input.TenantApplicationID == nil && input.LeaseID == nil &&
+		input.LeaseTerminationID == nil && input.LeaseAmendmentID == nil {
+		return nil, pkg.BadRequestError("SigningEnvelopeContextRequired", nil)
+	}
+
+	existing, existingErr := s.inProgressEnvelopeForDocument(ctx, input.DocumentID)
+	if existingErr != nil {
+		return nil, existingErr
+	}
+	if existing != nil {
+		return nil, pkg.BadRequestError("SigningEnvelopeAlreadyInProgress", nil)
+	}
+
+	envelope := &models.SigningEnvelope{
+		DocumentID:           input.DocumentID,
+		TenantApplicationID:  input.TenantApplicationID,
+		LeaseID:              input.LeaseID,
+		LeaseTerminationID:   input.LeaseTerminationID,
+		LeaseAmendmentID:     input.LeaseAmendmentID,
+		PMSigningOrder:       input.PMSigningOrder,
+		ExpiryPolicy:         "REGENERATE",
+		TokenValidityDays:    7,
+		ReminderIntervalDays: 2,
+		CreatedByID:          input.CreatedByID,
+	}
+	if input.ExpiryPolicy != nil {
+		envelope.ExpiryPolicy = *input.ExpiryPolicy
+	}
+	if input.TokenValidityDays != nil {
+		envelope.TokenValidityDays = *input.TokenValidityDays
+	}
+	if input.ReminderIntervalDays != nil {
+		envelope.ReminderIntervalDays = *input.ReminderIntervalDays
+	}
+
+	// A PM who signed before the envelope was sent is not asked again.
+	if input.PMSigningOrder != nil {
+		pmSig, pmSigErr := s.repo.GetDocumentSignatureByQuery(ctx, map[string]any{
+			"document_id":           input.DocumentID,
+			"role":                  "PROPERTY_MANAGER",
+			"tenant_application_id": input.TenantApplicationID,
+			"lease_id":              input.LeaseID,
+			"lease_termination_id":  input.LeaseTerminationID,
+			"lease_amendment_id":    input.LeaseAmendmentID,
+		})
+		if pmSigErr != nil && !errors.Is(pmSigErr, gorm.ErrRecordNotFound) {
+			return nil, pkg.InternalServerError(pmSigErr.Error(), &pkg.RentLoopErrorParams{
+				Err: pmSigErr,
+				Metadata: map[string]string{
+					"function": "CreateSigningEnvelope",
+					"action":   "checking for existing PM signature",
+				},
+			})
+		}
+		if pmSig != nil {
+			envelope.PMSignedAt = &pmSig.CreatedAt
+		}
+	}
+
+	revisionID, revisionErr := s.signingRevisionFor(ctx, input.DocumentID, input.LeaseID)
+	if revisionErr != nil {
+		return nil, revisionErr
+	}
+	envelope.DocumentRevisionID = revisionID
+
+	// Until its step comes up a token's expiry is only a placeholder; it is
+	// reset when the signer is invited.
+	placeholderExpiry := time.Now().Add(time.Duration(envelope.TokenValidityDays) * 24 * time.Hour)
+	for _, signer := range input.Signers {
+		token, err := s.prepareSigningToken(ctx, GenerateTokenInput{
+			DocumentID:          input.DocumentID,
+			TenantApplicationID: input.TenantApplicationID,
+			LeaseID:             input.LeaseID,
+			LeaseTerminationID:  input.LeaseTerminationID,
+			LeaseAmendmentID:    input.LeaseAmendmentID,
+			TenantID:            signer.TenantID,
+			GuarantorID:         signer.GuarantorID,
+			Role:                signer.Role,
+			SignerName:          signer.SignerName,
+			SignerEmail:         signer.SignerEmail,
+			SignerPhone:         signer.SignerPhone,
+			CreatedByID:         input.CreatedByID,
+			DocumentRevisionID:  revisionID,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		token.SigningOrder = signer.SigningOrder
+		token.ExpiresAt = placeholderExpiry
+		envelope.Tokens = append(envelope.Tokens, *token)
+	}
+
+	if err := s.envelopeRepo.Create(ctx, envelope); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CreateSigningEnvelope",
+				"action":   "creating signing envelope",
+			},
+		})
+	}
+
+	if input.LeaseID != nil {
+		go s.markLeaseDocSigning(*input.LeaseID)
+	}
+
+	s.advanceSigningEnvelope(ctx, envelope.ID.String())
+
+	return s.GetSigningEnvelope(ctx, envelope.ID.String(), nil)
+}
+
+func (s *signingService) GetSigningEnvelope(
+	ctx context.Context,
+	envelopeID string,
+	populate *[]string,
+) (*models.SigningEnvelope, error) {
+	envelope, err := s.envelopeRepo.GetOne(ctx, repository.GetSigningEnvelopeQuery{
+		ID:       envelopeID,
+		Populate: populate,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("SigningEnvelopeNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GetSigningEnvelope",
+				"action":   "fetching signing envelope",
+			},
+		})
+	}
+
+	setSigningEnvelopeCurrentStep(envelope)
+
+	return envelope, nil
+}
+
+func (s *signingService) ListSigningEnvelopes(
+	ctx context.Context,
+	filter repository.ListSigningEnvelopesFilter,
+) (*[]models.SigningEnvelope, error) {
+	envelopes, err := s.envelopeRepo.List(ctx, filter)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ListSigningEnvelopes",
+				"action":   "listing signing envelopes",
+			},
+		})
+	}
+
+	for i := range *envelopes {
+		setSigningEnvelopeCurrentStep(&(*envelopes)[i])
+	}
+
+	return envelopes, nil
+}
+
+func (s *signingService) CountSigningEnvelopes(
+	ctx context.Context,
+	filter repository.ListSigningEnvelopesFilter,
+) (int64, error) {
+	count, err := s.envelopeRepo.Count(ctx, filter)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CountSigningEnvelopes",
+				"action":   "counting signing envelopes",
+			},
+		})
+	}
+
+	return count, nil
+}
+
+type CancelSigningEnvelopeInput struct {
+	EnvelopeID    string
+	Reason        string
+	CancelledByID string
+}
+
+// CancelSigningEnvelope stops an envelope. Signatures already given stand;
+// the links of everyone who has not signed stop working.
+func (s *signingService) CancelSigningEnvelope(
+	ctx context.Context,
+	input CancelSigningEnvelopeInput,
+) (*models.SigningEnvelope, error) {
+	envelope, err := s.GetSigningEnvelope(ctx, input.EnvelopeID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if envelope.Status != "SigningEnvelope.Status.InProgress" {
+		return nil, pkg.BadRequestError("SigningEnvelopeNotInProgress", nil)
+	}
+
+	if cancelErr := s.cancelSigningEnvelope(
+		ctx,
+		envelope,
+		&input.CancelledByID,
+		input.Reason,
+		time.Now(),
+	); cancelErr != nil {
+		return nil, pkg.InternalServerError(cancelErr.Error(), &pkg.RentLoopErrorParams{
+			Err: cancelErr,
+			Metadata: map[string]string{
+				"function": "CancelSigningEnvelope",
+				"action":   "cancelling signing envelope",
+			},
+		})
+	}
+
+	setSigningEnvelopeCurrentStep(envelope)
+
+	return envelope, nil
+}
+
+type SigningEnvelopeSweepResult struct {
+	Reminded  int64
+	Renewed   int64
+	Cancelled int64
+}
+
+func (s *signingService) SweepSigningEnvelopes(
+	ctx context.Context,
+	now time.Time,
+) (SigningEnvelopeSweepResult, error) {
+	var result SigningEnvelopeSweepResult
+
+	envelopes, err := s.envelopeRepo.ListInProgress(ctx, &signingEnvelopeNotifyPopulate)
+	if err != nil {
+		return result, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "SweepSigningEnvelopes",
+				"action":   "listing in-progress signing envelopes",
+			},
+		})
+	}
+
+	// One envelope failing is logged and left for the next run rather than
+	// holding up the rest.
+	for i := range *envelopes {
+		envelope := &(*envelopes)[i]
+		if sweepErr := s.sweepSigningEnvelope(ctx, envelope, now, &result); sweepErr != nil {
+			log.WithError(sweepErr).
+				WithField("signing_envelope_id", envelope.ID.String()).
+				Error("failed to sweep signing envelope")
+		}
+	}
+
+	return result, nil
+}
+
+func (s *signingService) sweepSigningEnvelope(
+	ctx context.Context,
+	envelope *models.SigningEnvelope,
+	now time.Time,
+	result *SigningEnvelopeSweepResult,
+) error {
+	// Picks up a step whose invitation was missed, or completes an envelope
+	// whose last signature did not.
+	if err := s.inviteSigningEnvelopeStep(ctx, envelope, now); err != nil {
+		return err
+	}
+
+	step, ok := signingEnvelopeStep(envelope)
+	if !ok || envelope.Status != "SigningEnvelope.Status.InProgress" {
+		return nil
+	}
+
+	for i := range envelope.Tokens {
+		token := &envelope.Tokens[i]
+		if token.SigningOrder != step || token.IsUsed() || token.IsCancelled() || token.IsAwaitingTurn() {
+			continue
+		}
+
+		if !now.Before(token.ExpiresAt) {
+			if envelope.ExpiryPolicy == "CANCEL" {
+				reason := "A signing link expired before it was signed"
+				if err := s.cancelSigningEnvelope(ctx, envelope, nil, reason, now); err != nil {
+					return err
+				}
+				result.Cancelled++
+				s.notifySigningEnvelopeCancelled(envelope)
+				return nil
+			}
+
+			token.ExpiresAt = now.Add(signingEnvelopeValidity(envelope))
+			token.LastRemindedAt = &now
+			if err := s.repo.UpdateSigningToken(ctx, token); err != nil {
+				return err
+			}
+			s.sendSigningTokenNotification(ctx, token, true)
+			result.Renewed++
+			continue
+		}
+
+		if signingReminderDue(token.NotifiedAt, token.LastRemindedAt, envelope.ReminderIntervalDays, now) {
+			token.LastRemindedAt = &now
+			if err := s.repo.UpdateSigningToken(ctx, token); err != nil {
+				return err
+			}
+			s.sendSigningTokenNotification(ctx, token, true)
+			result.Reminded++
+		}
+	}
+
+	if envelope.PMSigningOrder != nil && *envelope.PMSigningOrder == step && envelope.PMSignedAt == nil &&
+		signingReminderDue(envelope.PMNotifiedAt, envelope.PMLastRemindedAt, envelope.ReminderIntervalDays, now) {
+		envelope.PMLastRemindedAt = &now
+		if err := s.envelopeRepo.Update(ctx, envelope); err != nil {
+			return err
+		}
+		s.notifySigningEnvelopePMTurn(envelope, true)
+		result.Reminded++
+	}
+
+	return nil
+}
+
+// advanceSigningEnvelope invites the envelope's next step once a signature
+// has closed the one before it. It runs after the signature is saved, so a
+// failure is logged for the sweep to retry rather than returned.
+func (s *signingService) advanceSigningEnvelope(ctx context.Context, envelopeID string) {
+	envelope, err := s.envelopeRepo.GetOne(ctx, repository.GetSigningEnvelopeQuery{
+		ID:       envelopeID,
+		Populate: &signingEnvelopeNotifyPopulate,
+	})
+	if err != nil {
+		log.WithError(err).WithField("signing_envelope_id", envelopeID).Error("failed to load signing envelope")
+		return
+	}
+
+	if envelope.Status != "SigningEnvelope.Status.InProgress" {
+		return
+	}
+
+	if inviteErr := s.inviteSigningEnvelopeStep(ctx, envelope, time.Now()); inviteErr != nil {
+		log.WithError(inviteErr).
+			WithField("signing_envelope_id", envelopeID).
+			Error("failed to invite next signing envelope step")
+	}
+}
+
+// inviteSigningEnvelopeStep sends the current step's signers their links,
+// each valid from now, and completes the envelope once no step is left.
+// Signers already invited are not sent another.
+func (s *signingService) inviteSigningEnvelopeStep(
+	ctx context.Context,
+	envelope *models.SigningEnvelope,
+	now time.Time,
+) error {
+	step, ok := signingEnvelopeStep(envelope)
+	if !ok {
+		envelope.Status = "SigningEnvelope.Status.Completed"
+		envelope.CompletedAt = &now
+		if err := s.envelopeRepo.Update(ctx, envelope); err != nil {
+			return err
+		}
+
+		go s.storeSigningAuditCertificate(envelope.ID.String())
+		return nil
+	}
+
+	for i := range envelope.Tokens {
+		token := &envelope.Tokens[i]
+		if token.SigningOrder != step || token.IsCancelled() || !token.IsAwaitingTurn() {
+			continue
+		}
+
+		token.NotifiedAt = &now
+		token.ExpiresAt = now.Add(signingEnvelopeValidity(envelope))
+		if err := s.repo.UpdateSigningToken(ctx, token); err != nil {
+			return err
+		}
+		s.sendSigningTokenNotification(ctx, token, false)
+	}
+
+	if envelope.PMSigningOrder != nil && *envelope.PMSigningOrder == step &&
+		envelope.PMSignedAt == nil && envelope.PMNotifiedAt == nil {
+		envelope.PMNotifiedAt = &now
+		if err := s.envelopeRepo.Update(ctx, envelope); err != nil {
+			return err
+		}
+		s.notifySigningEnvelopePMTurn(envelope, false)
+	}
+
+	return nil
+}
+
+// cancelSigningEnvelope cancels the envelope and every token in it that has
+// not been signed.
+func (s *signingService) cancelSigningEnvelope(
+	ctx context.Context,
+	envelope *models.SigningEnvelope,
+	cancelledByID *string,
+	reason string,
+	now time.Time,
+) error {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	for i := range envelope.Tokens {
+		token := &envelope.Tokens[i]
+		if token.IsUsed() || token.IsCancelled() {
+			continue
+		}
+
+		token.CancelledAt = &now
+		if err := s.repo.UpdateSigningToken(transCtx, token); err != nil {
+			transaction.Rollback()
+			return err
+		}
+	}
+
+	envelope.Status = "SigningEnvelope.Status.Cancelled"
+	envelope.CancelledAt = &now
+	envelope.CancelledByID = cancelledByID
+	envelope.CancellationReason = &reason
+	if err := s.envelopeRepo.Update(transCtx, envelope); err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	if err := transaction.Commit().Error; err != nil {
+		transaction.Rollback()
+		return err
+	}
+
+	return nil
+}
+
+// inProgressEnvelopeForDocument returns the document's in-progress envelope,
+// or nil if it has none.
+func (s *signingService) inProgressEnvelopeForDocument(
+	ctx context.Context,
+	documentID string,
+) (*models.SigningEnvelope, error) {
+	status := "SigningEnvelope.Status.InProgress"
+	envelope, err := s.envelopeRepo.GetOne(ctx, repository.GetSigningEnvelopeQuery{
+		DocumentID: &documentID,
+		Status:     &status,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "inProgressEnvelopeForDocument",
+				"action":   "fetching in-progress signing envelope",
+			},
+		})
+	}
+
+	return envelope, nil
+}
+
+func (s *signingService) notifySigningEnvelopePMTurn(envelope *models.SigningEnvelope, isReminder bool) {
+	if envelope.CreatedBy.User.Email == "" {
+		return
+	}
+
+	subject := lib.SIGNING_ENVELOPE_PM_TURN_SUBJECT
+	if isReminder {
+		subject = lib.SIGNING_ENVELOPE_PM_REMINDER_SUBJECT
+	}
+
+	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+		"document/signing-your-turn",
+		emailtemplates.SigningTurnData{
+			ManagerName:   envelope.CreatedBy.User.Name,
+			DocumentTitle: envelope.Document.Title,
+			IsReminder:    isReminder,
+		},
+	)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render signing-your-turn email template")
+		return
+	}
+
+	go pkg.SendEmail(s.appCtx.Config, pkg.SendEmailInput{
+		Recipient: envelope.CreatedBy.User.Email,
+		Subject:   subject,
+		HtmlBody:  htmlBody,
+		TextBody:  textBody,
+	})
+}
+
+func (s *signingService) notifySigningEnvelopeCancelled(envelope *models.SigningEnvelope) {
+	if envelope.CreatedBy.User.Email == "" {
+		return
+	}
+
+	reason := ""
+	if envelope.CancellationReason != nil {
+		reason = *envelope.CancellationReason
+	}
+
+	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+		"document/signing-envelope-cancelled",
+		emailtemplates.SigningEnvelopeCancelledData{
+			ManagerName:   envelope.CreatedBy.User.Name,
+			DocumentTitle: envelope.Document.Title,
+			Reason:        reason,
+		},
+	)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render signing-envelope-cancelled email template")
+		return
+	}
+
+	go pkg.SendEmail(s.appCtx.Config, pkg.SendEmailInput{
+		Recipient: envelope.CreatedBy.User.Email,
+		Subject:   lib.SIGNING_ENVELOPE_CANCELLED_SUBJECT,
+		HtmlBody:  htmlBody,
+		TextBody:  textBody,
+	})
+}
+
+func signingEnvelopeValidity(envelope *models.SigningEnvelope) time.Duration {
+	return time.Duration(envelope.TokenValidityDays) * 24 * time.Hour
+}
+
+func setSigningEnvelopeCurrentStep(envelope *models.SigningEnvelope) {
+	envelope.CurrentStep = nil
+	if envelope.Status != "SigningEnvelope.Status.InProgress" {
+		return
+	}
+	if step, ok := signingEnvelopeStep(envelope); ok {
+		envelope.CurrentStep = &step
+	}
+}
+
+// signingEnvelopeStep returns the signing order the envelope is waiting on:
+// the lowest order with a token neither signed nor cancelled, counting the PM
+// at PMSigningOrder until they have signed. ok is false once nobody is left.
+func signingEnvelopeStep(envelope *models.SigningEnvelope) (int64, bool) {
+	var step int64
+	found := false
+	consider := func(order int64) {
+		if !found || order < step {
+			step = order
+			found = true
+		}
+	}
+
+	for i := range envelope.Tokens {
+		token := &envelope.Tokens[i]
+		if token.IsUsed() || token.IsCancelled() {
+			continue
+		}
+		consider(token.SigningOrder)
+	}
+
+	if envelope.PMSigningOrder != nil && envelope.PMSignedAt == nil {
+		consider(*envelope.PMSigningOrder)
+	}
+
+	return step, found
+}
+
+// signingReminderDue reports whether a signer invited at notifiedAt, and last
+// reminded at lastRemindedAt, is due another reminder. An interval of zero
+// turns reminders off.
+func signingReminderDue(notifiedAt, lastRemindedAt *time.Time, intervalDays int64, now time.Time) bool {
+	if intervalDays <= 0 || notifiedAt == nil {
+		return false
+	}
+
+	last := *notifiedAt
+	if lastRemindedAt != nil && lastRemindedAt.After(last) {
+		last = *lastRemindedAt
+	}
+
+	return !now.Before(last.Add(time.Duration(intervalDays) * 24 * time.Hour))
+}
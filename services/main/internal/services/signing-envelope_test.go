@@ -0,0 +1,127 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// The envelope waits on the lowest order anyone is still to sign at. Signed
+// and cancelled tokens no longer hold it up, and the PM counts at their own
+// order until they have signed.
+func TestSigningEnvelopeStep(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	order := func(n int64) *int64 { return &n }
+
+	pending := func(order int64) models.SigningToken {
+		return models.SigningToken{SigningOrder: order}
+	}
+	signed := func(order int64) models.SigningToken {
+		return models.SigningToken{SigningOrder: order, SignedAt: &now}
+	}
+	cancelled := func(order int64) models.SigningToken {
+		return models.SigningToken{SigningOrder: order, CancelledAt: &now}
+	}
+
+	cases := []struct {
+		name       string
+		envelope   models.SigningEnvelope
+		wantStep   int64
+		wantWaited bool
+	}{
+		{
+			"first step open",
+			models.SigningEnvelope{Tokens: []models.SigningToken{pending(1), pending(2)}},
+			1, true,
+		},
+		{
+			"parallel signer still open",
+			models.SigningEnvelope{Tokens: []models.SigningToken{signed(1), pending(1), pending(2)}},
+			1, true,
+		},
+		{
+			"first step done",
+			models.SigningEnvelope{Tokens: []models.SigningToken{signed(1), signed(1), pending(2)}},
+			2, true,
+		},
+		{
+			"PM between signers",
+			models.SigningEnvelope{
+				Tokens:         []models.SigningToken{signed(1), pending(3)},
+				PMSigningOrder: order(2),
+			},
+			2, true,
+		},
+		{
+			"PM already signed",
+			models.SigningEnvelope{
+				Tokens:         []models.SigningToken{signed(1), pending(3)},
+				PMSigningOrder: order(2),
+				PMSignedAt:     &now,
+			},
+			3, true,
+		},
+		{
+			"PM last",
+			models.SigningEnvelope{
+				Tokens:         []models.SigningToken{signed(1), signed(2)},
+				PMSigningOrder: order(3),
+			},
+			3, true,
+		},
+		{
+			"cancelled tokens skipped",
+			models.SigningEnvelope{Tokens: []models.SigningToken{cancelled(1), pending(2)}},
+			2, true,
+		},
+		{
+			"everyone signed",
+			models.SigningEnvelope{Tokens: []models.SigningToken{signed(1), signed(2)}},
+			0, false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			step, ok := signingEnvelopeStep(&tc.envelope)
+			if ok != tc.wantWaited || step != tc.wantStep {
+				t.Errorf("got (%d, %v), want (%d, %v)", step, ok, tc.wantStep, tc.wantWaited)
+			}
+		})
+	}
+}
+
+// Reminders are spaced from the later of the invitation and the last
+// reminder, so a renewed or manually resent link restarts the interval. A
+// signer not yet invited, or an interval of zero, is never reminded.
+func TestSigningReminderDue(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) *time.Time {
+		at := now.AddDate(0, 0, -days)
+		return &at
+	}
+
+	cases := []struct {
+		name           string
+		notifiedAt     *time.Time
+		lastRemindedAt *time.Time
+		intervalDays   int64
+		want           bool
+	}{
+		{"not yet invited", nil, nil, 2, false},
+		{"invited within interval", daysAgo(1), nil, 2, false},
+		{"invited exactly one interval ago", daysAgo(2), nil, 2, true},
+		{"reminded recently", daysAgo(5), daysAgo(1), 2, false},
+		{"reminded one interval ago", daysAgo(5), daysAgo(2), 2, true},
+		{"reminders off", daysAgo(10), nil, 0, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := signingReminderDue(tc.notifiedAt, tc.lastRemindedAt, tc.intervalDays, now); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// An org can require a code for every document it sends for signing, or only
+// for documents with certain tags — lease agreements, say.
+func TestSigningOtpRequired(t *testing.T) {
+	leaseAgreementsOnly := models.Client{SigningOtpDocumentTags: []string{"LEASE_AGREEMENT"}}
+
+	cases := []struct {
+		name   string
+		client models.Client
+		tags   []string
+		want   bool
+	}{
+		{"not configured", models.Client{}, []string{"LEASE_AGREEMENT"}, false},
+		{"required for every document", models.Client{SigningOtpRequired: true}, nil, true},
+		{"tag matches", leaseAgreementsOnly, []string{"OTHER", "LEASE_AGREEMENT"}, true},
+		{"tag does not match", leaseAgreementsOnly, []string{"INSPECTION_REPORT"}, false},
+		{"untagged document", leaseAgreementsOnly, nil, false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := signingOtpRequired(tc.client, tc.tags); got != tc.want {
+				t.Errorf("signingOtpRequired = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// A confirmed code covers the signer for signingOtpValidity; a signature made
+// after that, or without a code, records only that the signer held the link.
+func TestSignatureVerificationMethod(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	verified := func(ago time.Duration, via *string) models.SigningToken {
+		verifiedAt := now.Add(-ago)
+		return models.SigningToken{OtpVerifiedAt: &verifiedAt, OtpVerifiedVia: via}
+	}
+	sms := "SMS"
+	email := "EMAIL"
+
+	cases := []struct {
+		name  string
+		token models.SigningToken
+		want  string
+	}{
+		{"no code confirmed", models.SigningToken{}, "LINK"},
+		{"code by SMS", verified(5*time.Minute, &sms), "OTP_SMS"},
+		{"code by email", verified(29*time.Minute, &email), "OTP_EMAIL"},
+		{"code lapsed", verified(31*time.Minute, &sms), "LINK"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := signatureVerificationMethod(&tc.token, now); got != tc.want {
+				t.Errorf("signatureVerificationMethod = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+// The signer sees where their code went without the link giving away their
+// full number or address.
+func TestMaskOtpReceiver(t *testing.T) {
+	cases := map[string]string{
+		"+233201080802":    "**********802",
+		"ama@example.com":  "a**@example.com",
+		"a@example.com":    "*@example.com",
+		"802":              "***",
+		"kofi.mensah@x.co": "k**********@x.co",
+	}
+
+	for receiver, want := range cases {
+		if got := maskOtpReceiver(receiver); got != want {
+			t.Errorf("maskOtpReceiver(%q) = %q, want %q", receiver, got, want)
+		}
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/Bendomey/rent-loop/services/main/internal/models"
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
 	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"github.com/gofrs/uuid"
 	log "github.com/sirupsen/logrus"
 	"gorm.io/gorm"
 )
@@ -19,6 +20,8 @@ import (
 type SigningService interface {
 	GenerateToken(ctx context.Context, input GenerateTokenInput) (*models.SigningToken, error)
 	VerifyToken(ctx context.Context, tokenStr string, populate *[]string) (*models.SigningToken, error)
+	SendSigningOtp(ctx context.Context, tokenStr string) (*SigningOtpSent, error)
+	VerifySigningOtp(ctx context.Context, tokenStr string, code string) (*models.SigningToken, error)
 	SignDocument(ctx context.Context, input SignDocumentInput) (*models.DocumentSignature, error)
 	SignDocumentByPM(ctx context.Context, input SignDocumentPMInput) (*models.DocumentSignature, error)
 	UpdateSigningTokenDetails(
@@ -47,20 +50,60 @@ type SigningService interface {
 		filterQuery lib.FilterQuery,
 		filters repository.ListDocumentSignaturesFilter,
 	) (int64, error)
+	CreateSigningEnvelope(ctx context.Context, input CreateSigningEnvelopeInput) (*models.SigningEnvelope, error)
+	GetSigningEnvelope(ctx context.Context, envelopeID string, populate *[]string) (*models.SigningEnvelope, error)
+	ListSigningEnvelopes(
+		ctx context.Context,
+		filter repository.ListSigningEnvelopesFilter,
+	) (*[]models.SigningEnvelope, error)
+	CountSigningEnvelopes(ctx context.Context, filter repository.ListSigningEnvelopesFilter) (int64, error)
+	CancelSigningEnvelope(
+		ctx context.Context,
+		input CancelSigningEnvelopeInput,
+	) (*models.SigningEnvelope, error)
+	// SweepSigningEnvelopes reminds signers who have not signed and deals
+	// with links that lapsed unsigned. Run by the queue.
+	SweepSigningEnvelopes(ctx context.Context, now time.Time) (SigningEnvelopeSweepResult, error)
+	VerifyDocumentSignatures(
+		ctx context.Context,
+		input VerifyDocumentSignaturesInput,
+	) (*DocumentSignatureVerification, error)
 }
 
 type signingService struct {
-	appCtx  pkg.AppContext
-	repo    repository.SigningRepository
-	ladRepo repository.LeaseAgreementDocumentRepository // side effects only
+	appCtx          pkg.AppContext
+	repo            repository.SigningRepository
+	envelopeRepo    repository.SigningEnvelopeRepository
+	documentRepo    repository.DocumentRepository
+	ladRepo         repository.LeaseAgreementDocumentRepository // side effects only
+	leaseTenantRepo repository.LeaseTenantRepository
+	guarantorRepo   repository.GuarantorRepository
+	ladService      LeaseAgreementDocumentService
+	documentService DocumentService
 }
 
 func NewSigningService(
 	appCtx pkg.AppContext,
 	repo repository.SigningRepository,
+	envelopeRepo repository.SigningEnvelopeRepository,
+	documentRepo repository.DocumentRepository,
 	ladRepo repository.LeaseAgreementDocumentRepository,
+	leaseTenantRepo repository.LeaseTenantRepository,
+	guarantorRepo repository.GuarantorRepository,
+	ladService LeaseAgreementDocumentService,
+	documentService DocumentService,
 ) SigningService {
-	return &signingService{appCtx: appCtx, repo: repo, ladRepo: ladRepo}
+	return &signingService{
+		appCtx:          appCtx,
+		repo:            repo,
+		envelopeRepo:    envelopeRepo,
+		documentRepo:    documentRepo,
+		ladRepo:         ladRepo,
+		leaseTenantRepo: leaseTenantRepo,
+		guarantorRepo:   guarantorRepo,
+		ladService:      ladService,
+		documentService: documentService,
+	}
 }
 
 type GenerateTokenInput struct {
@@ -68,55 +111,213 @@ type GenerateTokenInput struct {
 	TenantApplicationID *string
 	LeaseID             *string
 	LeaseTerminationID  *string
+	LeaseAmendmentID    *string
+	TenantID            *string // which lease tenant a TENANT token is for
+	GuarantorID         *string // which guarantor a GUARANTOR token is for
 	Role                string
 	SignerName          *string
 	SignerEmail         *string
 	SignerPhone         *string
 	CreatedByID         string
+	// DocumentRevisionID is the revision the token is for, resolved with
+	// signingRevisionFor when nil.
+	DocumentRevisionID *string
 }
 
 func (s *signingService) GenerateToken(
 	ctx context.Context,
 	input GenerateTokenInput,
 ) (*models.SigningToken, error) {
+	token, err := s.prepareSigningToken(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	token.ExpiresAt = now.Add(7 * 24 * time.Hour)
+	token.NotifiedAt = &now
+
+	if err := s.repo.CreateSigningToken(ctx, token); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GenerateToken",
+				"action":   "creating signing token",
+			},
+		})
+	}
+
+	s.sendSigningTokenNotification(ctx, token, false)
+
+	// If this token is for a lease, advance LeaseAgreementDocument FINALIZED→SIGNING
+	if input.LeaseID != nil {
+		go s.markLeaseDocSigning(*input.LeaseID)
+	}
+
+	return token, nil
+}
+
+// prepareSigningToken checks the signer a token is being issued for and
+// builds the unsaved token, filling in a guarantor's contact details.
+func (s *signingService) prepareSigningToken(
+	ctx context.Context,
+	input GenerateTokenInput,
+) (*models.SigningToken, error) {
+	if input.TenantID != nil {
+		if input.Role != "TENANT" || input.LeaseID == nil {
+			return nil, pkg.BadRequestError("SigningTokenTenantRequiresLeaseTenantRole", nil)
+		}
+
+		leaseTenant, err := s.leaseTenantRepo.GetOne(ctx, repository.GetLeaseTenantQuery{
+			LeaseID:  *input.LeaseID,
+			TenantID: input.TenantID,
+		})
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, pkg.BadRequestError("TenantNotOnLease", &pkg.RentLoopErrorParams{Err: err})
+			}
+			return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err: err,
+				Metadata: map[string]string{
+					"function": "GenerateToken",
+					"action":   "fetching lease tenant",
+				},
+			})
+		}
+
+		if !leaseTenant.IsSignatory() {
+			return nil, pkg.BadRequestError("LeaseTenantIsNotASignatory", nil)
+		}
+	}
+
+	if input.GuarantorID != nil || input.Role == "GUARANTOR" {
+		guarantor, err := s.guarantorForToken(ctx, input)
+		if err != nil {
+			return nil, err
+		}
+
+		// The invitation goes to the guarantor's own details unless the
+		// manager has given others.
+		if input.SignerName == nil {
+			name := guarantor.FullName()
+			input.SignerName = &name
+		}
+		if input.SignerEmail == nil {
+			input.SignerEmail = guarantor.Email
+		}
+		if input.SignerPhone == nil {
+			input.SignerPhone = &guarantor.Phone
+		}
+	}
+
+	if input.DocumentRevisionID == nil {
+		revisionID, err := s.signingRevisionFor(ctx, input.DocumentID, input.LeaseID)
+		if err != nil {
+			return nil, err
+		}
+		input.DocumentRevisionID = revisionID
+	}
+
 	token := &models.SigningToken{
 		DocumentID:          input.DocumentID,
+		DocumentRevisionID:  input.DocumentRevisionID,
 		TenantApplicationID: input.TenantApplicationID,
 		LeaseID:             input.LeaseID,
 		LeaseTerminationID:  input.LeaseTerminationID,
+		LeaseAmendmentID:    input.LeaseAmendmentID,
+		TenantID:            input.TenantID,
+		GuarantorID:         input.GuarantorID,
 		Role:                input.Role,
 		SignerName:          input.SignerName,
 		SignerEmail:         input.SignerEmail,
 		SignerPhone:         input.SignerPhone,
 		CreatedByID:         input.CreatedByID,
-		ExpiresAt:           time.Now().Add(7 * 24 * time.Hour),
 	}
 
-	if err := s.repo.CreateSigningToken(ctx, token); err != nil {
+	return token, nil
+}
+
+// signingRevisionFor is the revision of a document its signers sign: the one
+// the lease's agreement was finalized on when the document is that
+// agreement, and the document's current revision otherwise.
+func (s *signingService) signingRevisionFor(
+	ctx context.Context,
+	documentID string,
+	leaseID *string,
+) (*string, error) {
+	if leaseID != nil {
+		lad, err := s.ladRepo.GetByLeaseID(ctx, *leaseID, nil)
+		if err == nil && lad.DocumentID != nil && *lad.DocumentID == documentID && lad.DocumentRevisionID != nil {
+			return lad.DocumentRevisionID, nil
+		}
+	}
+
+	revision, err := s.documentService.CurrentRevision(ctx, documentID)
+	if err != nil {
+		return nil, err
+	}
+	revisionID := revision.ID.String()
+	return &revisionID, nil
+}
+
+// markLeaseDocSigning advances the lease's LeaseAgreementDocument from
+// FINALIZED to SIGNING once its first signer has been invited or has signed.
+func (s *signingService) markLeaseDocSigning(leaseID string) {
+	doc, err := s.ladRepo.GetByLeaseID(context.Background(), leaseID, nil)
+	if err != nil || doc == nil || doc.Status != "FINALIZED" {
+		return
+	}
+	doc.Status = "SIGNING"
+	_ = s.ladRepo.Update(context.Background(), doc)
+}
+
+// guarantorForToken checks a GUARANTOR token names a guarantor of the lease
+// or application it is being issued for.
+func (s *signingService) guarantorForToken(ctx context.Context, input GenerateTokenInput) (*models.Guarantor, error) {
+	if input.Role != "GUARANTOR" || input.GuarantorID == nil {
+		return nil, pkg.BadRequestError("GuarantorTokenRequiresGuarantorRoleAndID", nil)
+	}
+
+	filter := repository.ListGuarantorsFilter{LeaseID: input.LeaseID}
+	if input.LeaseID == nil {
+		if input.TenantApplicationID == nil {
+			return nil, pkg.BadRequestError("GuarantorTokenRequiresLeaseOrApplication", nil)
+		}
+		filter.TenantApplicationID = input.TenantApplicationID
+	}
+
+	guarantor, err := s.guarantorRepo.GetOne(ctx, repository.GetGuarantorQuery{
+		ID:                   *input.GuarantorID,
+		ListGuarantorsFilter: filter,
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.BadRequestError("GuarantorNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
 		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
 			Err: err,
 			Metadata: map[string]string{
 				"function": "GenerateToken",
-				"action":   "creating signing token",
+				"action":   "fetching guarantor",
 			},
 		})
 	}
 
-	s.sendSigningTokenNotification(ctx, token, false)
+	return guarantor, nil
+}
 
-	// If this token is for a lease, advance LeaseAgreementDocument FINALIZED→SIGNING
-	if input.LeaseID != nil {
-		go func(leaseID string) {
-			doc, err := s.ladRepo.GetByLeaseID(context.Background(), leaseID, nil)
-			if err != nil || doc == nil || doc.Status != "FINALIZED" {
-				return
-			}
-			doc.Status = "SIGNING"
-			_ = s.ladRepo.Update(context.Background(), doc)
-		}(*input.LeaseID)
+// signingTokenUsableError explains why an unsigned token cannot be used to
+// sign yet, or returns nil if it can.
+func signingTokenUsableError(token *models.SigningToken) error {
+	switch {
+	case token.IsCancelled():
+		return pkg.BadRequestError("SigningTokenCancelled", nil)
+	case token.IsAwaitingTurn():
+		return pkg.BadRequestError("SigningTokenNotYetActive", nil)
+	case token.IsExpired():
+		return pkg.BadRequestError("SigningTokenExpired", nil)
 	}
-
-	return token, nil
+	return nil
 }
 
 func (s *signingService) VerifyToken(
@@ -140,8 +341,14 @@ func (s *signingService) VerifyToken(
 		})
 	}
 
-	if !token.IsValid() {
-		return nil, pkg.BadRequestError("SigningTokenExpired", nil)
+	if !token.IsUsed() {
+		if usableErr := signingTokenUsableError(token); usableErr != nil {
+			return nil, usableErr
+		}
+	}
+
+	if otpErr := s.applySigningOtpRequirement(ctx, token); otpErr != nil {
+		return nil, otpErr
 	}
 
 	// update last accessed at — non-fatal if it fails
@@ -158,6 +365,36 @@ func (s *signingService) VerifyToken(
 		})
 	}
 
+	// Until the signer confirms a code, the link only tells them one is
+	// needed: the document, who it is for and where the code goes stay
+	// hidden. SendSigningOtp shows the signer a masked receiver.
+	if token.OtpRequired && !signingOtpVerified(token, now) {
+		token.SignerEmail = nil
+		token.SignerPhone = nil
+		token.Document = models.Document{}
+		token.TenantApplication = nil
+		token.Lease = nil
+		token.LeaseTermination = nil
+		token.LeaseAmendment = nil
+		token.Tenant = nil
+		token.Guarantor = nil
+		token.DocumentSignature = nil
+	}
+
+	// The signer reviews the revision they are asked to sign, whatever the
+	// document has been edited to since.
+	if token.DocumentRevisionID != nil && token.Document.ID != uuid.Nil {
+		revision, revisionErr := s.documentService.GetRevision(ctx, repository.GetDocumentRevisionQuery{
+			ID:         *token.DocumentRevisionID,
+			DocumentID: token.DocumentID,
+		})
+		if revisionErr != nil {
+			return nil, revisionErr
+		}
+		token.Document.Title = revision.Title
+		token.Document.Content = revision.Content
+	}
+
 	return token, nil
 }
 
@@ -166,6 +403,7 @@ type SignDocumentInput struct {
 	SignatureUrl string
 	SignerName   *string
 	IPAddress    string
+	UserAgent    *string
 }
 
 func (s *signingService) SignDocument(
@@ -192,8 +430,16 @@ func (s *signingService) SignDocument(
 		return nil, pkg.BadRequestError("SigningTokenAlreadyUsed", nil)
 	}
 
-	if !token.IsValid() {
-		return nil, pkg.BadRequestError("SigningTokenExpired", nil)
+	if usableErr := signingTokenUsableError(token); usableErr != nil {
+		return nil, usableErr
+	}
+
+	if otpErr := s.applySigningOtpRequirement(ctx, token); otpErr != nil {
+		return nil, otpErr
+	}
+	now := time.Now()
+	if token.OtpRequired && !signingOtpVerified(token, now) {
+		return nil, pkg.BadRequestError("SigningOtpRequired", nil)
 	}
 
 	var ladID *string
@@ -209,13 +455,25 @@ func (s *signingService) SignDocument(
 
 	sig := &models.DocumentSignature{
 		DocumentID:               token.DocumentID,
+		DocumentRevisionID:       token.DocumentRevisionID,
 		TenantApplicationID:      token.TenantApplicationID,
 		LeaseID:                  token.LeaseID,
+		LeaseTerminationID:       token.LeaseTerminationID,
+		LeaseAmendmentID:         token.LeaseAmendmentID,
 		LeaseAgreementDocumentID: ladID,
+		TenantID:                 token.TenantID,
+		GuarantorID:              token.GuarantorID,
 		Role:                     token.Role,
 		SignatureUrl:             input.SignatureUrl,
 		SignedByName:             input.SignerName,
 		IPAddress:                input.IPAddress,
+		UserAgent:                input.UserAgent,
+		VerificationMethod:       signatureVerificationMethod(token, now),
+	}
+
+	if chainErr := s.chainDocumentSignature(transCtx, sig, now); chainErr != nil {
+		transaction.Rollback()
+		return nil, chainErr
 	}
 
 	if createErr := s.repo.CreateDocumentSignature(transCtx, sig); createErr != nil {
@@ -229,7 +487,6 @@ func (s *signingService) SignDocument(
 		})
 	}
 
-	now := time.Now()
 	token.SignedAt = &now
 	sigID := sig.ID.String()
 	token.DocumentSignatureID = &sigID
@@ -256,6 +513,10 @@ func (s *signingService) SignDocument(
 		})
 	}
 
+	if token.SigningEnvelopeID != nil {
+		s.advanceSigningEnvelope(ctx, *token.SigningEnvelopeID)
+	}
+
 	if token.LeaseID != nil {
 		s.maybeAdvanceLeaseDocToSigned(ctx, *token.LeaseID, token.DocumentID)
 	}
@@ -269,7 +530,10 @@ type SignDocumentPMInput struct {
 	TenantApplicationID *string
 	LeaseID             *string
 	LeaseTerminationID  *string
+	LeaseAmendmentID    *string
 	SignedByID          string
+	IPAddress           string
+	UserAgent           *string
 }
 
 func (s *signingService) SignDocumentByPM(
@@ -283,6 +547,7 @@ func (s *signingService) SignDocumentByPM(
 		"tenant_application_id": input.TenantApplicationID,
 		"lease_id":              input.LeaseID,
 		"lease_termination_id":  input.LeaseTerminationID,
+		"lease_amendment_id":    input.LeaseAmendmentID,
 	})
 	if err != nil {
 		if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -300,6 +565,16 @@ func (s *signingService) SignDocumentByPM(
 		return nil, pkg.BadRequestError("PMSignatureAlreadyExists", nil)
 	}
 
+	envelope, envelopeErr := s.inProgressEnvelopeForDocument(ctx, input.DocumentID)
+	if envelopeErr != nil {
+		return nil, envelopeErr
+	}
+	if envelope != nil && envelope.PMSigningOrder != nil {
+		if step, ok := signingEnvelopeStep(envelope); !ok || step != *envelope.PMSigningOrder {
+			return nil, pkg.BadRequestError("NotPropertyManagersTurnToSign", nil)
+		}
+	}
+
 	var ladID *string
 	if input.LeaseID != nil {
 		if lad, ladErr := s.ladRepo.GetByLeaseID(ctx, *input.LeaseID, nil); ladErr == nil && lad != nil {
@@ -308,18 +583,46 @@ func (s *signingService) SignDocumentByPM(
 		}
 	}
 
+	// The PM signs the revision the envelope was sent with.
+	var revisionID *string
+	if envelope != nil {
+		revisionID = envelope.DocumentRevisionID
+	}
+	if revisionID == nil {
+		var revisionErr error
+		revisionID, revisionErr = s.signingRevisionFor(ctx, input.DocumentID, input.LeaseID)
+		if revisionErr != nil {
+			return nil, revisionErr
+		}
+	}
+
 	sig := &models.DocumentSignature{
 		DocumentID:               input.DocumentID,
+		DocumentRevisionID:       revisionID,
 		TenantApplicationID:      input.TenantApplicationID,
 		LeaseID:                  input.LeaseID,
 		LeaseTerminationID:       input.LeaseTerminationID,
+		LeaseAmendmentID:         input.LeaseAmendmentID,
 		LeaseAgreementDocumentID: ladID,
 		Role:                     "PROPERTY_MANAGER",
 		SignatureUrl:             input.SignatureUrl,
 		SignedByID:               &input.SignedByID,
+		IPAddress:                input.IPAddress,
+		UserAgent:                input.UserAgent,
+		VerificationMethod:       "PORTAL",
 	}
 
-	if createErr := s.repo.CreateDocumentSignature(ctx, sig); createErr != nil {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	now := time.Now()
+	if chainErr := s.chainDocumentSignature(transCtx, sig, now); chainErr != nil {
+		transaction.Rollback()
+		return nil, chainErr
+	}
+
+	if createErr := s.repo.CreateDocumentSignature(transCtx, sig); createErr != nil {
+		transaction.Rollback()
 		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
 			Err: createErr,
 			Metadata: map[string]string{
@@ -329,16 +632,29 @@ func (s *signingService) SignDocumentByPM(
 		})
 	}
 
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err: commitErr,
+			Metadata: map[string]string{
+				"function": "SignDocumentByPM",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	if envelope != nil && envelope.PMSigningOrder != nil {
+		envelope.PMSignedAt = &now
+		if updateErr := s.envelopeRepo.Update(ctx, envelope); updateErr != nil {
+			log.WithError(updateErr).Error("failed to record PM signature on signing envelope")
+		} else {
+			s.advanceSigningEnvelope(ctx, envelope.ID.String())
+		}
+	}
+
 	if input.LeaseID != nil {
 		// Advance FINALIZED → SIGNING when PM signs directly (same as when a token is created).
-		go func(leaseID string) {
-			doc, err := s.ladRepo.GetByLeaseID(context.Background(), leaseID, nil)
-			if err != nil || doc == nil || doc.Status != "FINALIZED" {
-				return
-			}
-			doc.Status = "SIGNING"
-			_ = s.ladRepo.Update(context.Background(), doc)
-		}(*input.LeaseID)
+		go s.markLeaseDocSigning(*input.LeaseID)
 
 		s.maybeAdvanceLeaseDocToSigned(ctx, *input.LeaseID, input.DocumentID)
 	}
@@ -474,7 +790,17 @@ func (s *signingService) ResendSigningToken(
 		return nil, pkg.BadRequestError("SigningTokenAlreadyUsed", nil)
 	}
 
-	token.ExpiresAt = time.Now().Add(7 * 24 * time.Hour)
+	if token.IsCancelled() {
+		return nil, pkg.BadRequestError("SigningTokenCancelled", nil)
+	}
+
+	if token.IsAwaitingTurn() {
+		return nil, pkg.BadRequestError("SigningTokenNotYetActive", nil)
+	}
+
+	now := time.Now()
+	token.ExpiresAt = now.Add(7 * 24 * time.Hour)
+	token.LastRemindedAt = &now
 
 	if updateErr := s.repo.UpdateSigningToken(ctx, token); updateErr != nil {
 		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
@@ -586,27 +912,125 @@ func (s *signingService) maybeAdvanceLeaseDocToSigned(ctx context.Context, lease
 		return
 	}
 	for _, t := range *tokens {
-		if t.SignedAt == nil {
+		if t.SignedAt == nil && !t.IsCancelled() {
 			return
 		}
 	}
 
-	// Both PM and tenant must have signed.
-	for _, role := range []string{"PROPERTY_MANAGER", "TENANT"} {
-		r := role
-		count, countErr := s.repo.CountDocumentSignatures(
+	pmRole := "PROPERTY_MANAGER"
+	pmCount, countErr := s.repo.CountDocumentSignatures(
+		ctx,
+		lib.FilterQuery{Page: 1, PageSize: 1},
+		repository.ListDocumentSignaturesFilter{
+			LeaseID: &leaseID,
+			Role:    &pmRole,
+		},
+	)
+	if countErr != nil || pmCount == 0 {
+		return
+	}
+
+	// Every signatory on the lease — the primary and each co-tenant — must
+	// have signed, not just one tenant.
+	leaseTenants, ltErr := s.leaseTenantRepo.ListByLease(ctx, leaseID, nil)
+	if ltErr != nil || leaseTenants == nil {
+		return
+	}
+	tenantRole := "TENANT"
+	tenantSignatures, sigErr := s.repo.ListDocumentSignatures(
+		ctx,
+		lib.FilterQuery{Page: 1, PageSize: 100},
+		repository.ListDocumentSignaturesFilter{
+			LeaseID: &leaseID,
+			Role:    &tenantRole,
+		},
+	)
+	if sigErr != nil || tenantSignatures == nil {
+		return
+	}
+	if len(unsignedLeaseSignatories(*leaseTenants, *tenantSignatures)) > 0 {
+		return
+	}
+
+	// So must every guarantor of the lease.
+	guarantors, gErr := s.guarantorRepo.List(ctx, repository.ListGuarantorsFilter{LeaseID: &leaseID})
+	if gErr != nil || guarantors == nil {
+		return
+	}
+	if len(*guarantors) > 0 {
+		guarantorRole := "GUARANTOR"
+		guarantorSignatures, gSigErr := s.repo.ListDocumentSignatures(
 			ctx,
-			lib.FilterQuery{Page: 1, PageSize: 1},
+			lib.FilterQuery{Page: 1, PageSize: 100},
 			repository.ListDocumentSignaturesFilter{
 				LeaseID: &leaseID,
-				Role:    &r,
+				Role:    &guarantorRole,
 			},
 		)
-		if countErr != nil || count == 0 {
+		if gSigErr != nil || guarantorSignatures == nil {
+			return
+		}
+		if len(unsignedGuarantors(*guarantors, *guarantorSignatures)) > 0 {
 			return
 		}
 	}
 
 	doc.Status = "SIGNED"
-	_ = s.ladRepo.Update(ctx, doc)
+	if updateErr := s.ladRepo.Update(ctx, doc); updateErr != nil {
+		return
+	}
+
+	// Rendering fetches every signature image, so it happens after the last
+	// signer's response rather than before it.
+	go s.ladService.StoreSignedPdf(context.Background(), leaseID)
+}
+
+// unsignedLeaseSignatories returns the tenant IDs of the lease's signatories
+// who have no TENANT signature yet. A signature without a tenant predates
+// co-tenants and stands for the primary.
+func unsignedLeaseSignatories(
+	leaseTenants []models.LeaseTenant,
+	tenantSignatures []models.DocumentSignature,
+) []string {
+	signed := make(map[string]bool, len(tenantSignatures))
+	primarySigned := false
+	for _, sig := range tenantSignatures {
+		if sig.TenantID == nil {
+			primarySigned = true
+			continue
+		}
+		signed[*sig.TenantID] = true
+	}
+
+	unsigned := make([]string, 0)
+	for _, leaseTenant := range leaseTenants {
+		if !leaseTenant.IsSignatory() || signed[leaseTenant.TenantID] {
+			continue
+		}
+		if leaseTenant.Role == models.LeaseTenantRolePrimary && primarySigned {
+			continue
+		}
+		unsigned = append(unsigned, leaseTenant.TenantID)
+	}
+
+	return unsigned
+}
+
+// unsignedGuarantors returns the IDs of guarantors with no GUARANTOR signature.
+func unsignedGuarantors(guarantors []models.Guarantor, guarantorSignatures []models.DocumentSignature) []string {
+	signed := make(map[string]bool, len(guarantorSignatures))
+	for _, sig := range guarantorSignatures {
+		if sig.GuarantorID != nil {
+			signed[*sig.GuarantorID] = true
+		}
+	}
+
+	unsigned := make([]string, 0)
+	for _, guarantor := range guarantors {
+		if !signed[guarantor.ID.String()] {
+			unsigned = append(unsigned, guarantor.ID.String())
+		}
+	}
+
+	return unsigned
 }
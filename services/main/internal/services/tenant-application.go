@@ -63,6 +63,9 @@ type tenantApplicationService struct {
 	leaseService         LeaseService
 	tenantAccountService TenantAccountService
 	invoiceService       InvoiceService
+	guarantorService     GuarantorService
+	documentRepo         repository.DocumentRepository
+	waitlistEntryRepo    repository.WaitlistEntryRepository
 	financials           *financials.Financials
 }
 
@@ -75,6 +78,9 @@ type TenantApplicationServiceDeps struct {
 	LeaseService         LeaseService
 	TenantAccountService TenantAccountService
 	InvoiceService       InvoiceService
+	GuarantorService     GuarantorService
+	DocumentRepo         repository.DocumentRepository
+	WaitlistEntryRepo    repository.WaitlistEntryRepository
 	Financials           *financials.Financials
 }
 
@@ -88,6 +94,9 @@ func NewTenantApplicationService(deps TenantApplicationServiceDeps) TenantApplic
 		leaseService:         deps.LeaseService,
 		tenantAccountService: deps.TenantAccountService,
 		invoiceService:       deps.InvoiceService,
+		guarantorService:     deps.GuarantorService,
+		documentRepo:         deps.DocumentRepo,
+		waitlistEntryRepo:    deps.WaitlistEntryRepo,
 		financials:           deps.Financials,
 	}
 }
@@ -118,6 +127,8 @@ type CreateTenantApplicationInput struct {
 	OccupationAddress              *string
 	ProfilePhotoUrl                *string
 	CreatedById                    string
+	// WaitlistToken is the invite a waitlisted prospect applies through.
+	WaitlistToken *string
 }
 
 func (s *tenantApplicationService) CreateTenantApplication(
@@ -138,6 +149,27 @@ func (s *tenantApplicationService) CreateTenantApplication(
 		return nil, pkg.BadRequestError("UnitNotAvailable", nil)
 	}
 
+	var waitlistEntry *models.WaitlistEntry
+	if input.WaitlistToken != nil {
+		entry, entryErr := s.waitlistEntryRepo.GetByInviteToken(ctx, *input.WaitlistToken, nil)
+		if entryErr != nil {
+			if errors.Is(entryErr, gorm.ErrRecordNotFound) {
+				return nil, pkg.NotFoundError("WaitlistInviteNotFound", &pkg.RentLoopErrorParams{Err: entryErr})
+			}
+			return nil, pkg.InternalServerError(entryErr.Error(), &pkg.RentLoopErrorParams{
+				Err: entryErr,
+				Metadata: map[string]string{
+					"function": "CreateTenantApplication",
+					"action":   "fetching waitlist invite",
+				},
+			})
+		}
+		if usableErr := waitlistInviteUsable(entry, &input.DesiredUnitId, time.Now()); usableErr != nil {
+			return nil, usableErr
+		}
+		waitlistEntry = entry
+	}
+
 	source := "SELF"
 	tenantApplication := models.TenantApplication{
 		PropertyId:                     &unit.PropertyID,
@@ -184,6 +216,10 @@ func (s *tenantApplicationService) CreateTenantApplication(
 		})
 	}
 
+	if waitlistEntry != nil {
+		s.markWaitlistEntryApplied(ctx, waitlistEntry, &tenantApplication)
+	}
+
 	smsMessage := strings.NewReplacer(
 		"{{applicant_name}}", input.FirstName,
 		"{{unit_name}}", unit.Name,
@@ -225,6 +261,27 @@ func (s *tenantApplicationService) CreateTenantApplication(
 	return &tenantApplication, nil
 }
 
+// markWaitlistEntryApplied closes the waitlist invite an application was
+// started through. The application is saved by then, so a failure is logged
+// rather than returned; the invite runs out on its own.
+func (s *tenantApplicationService) markWaitlistEntryApplied(
+	ctx context.Context,
+	entry *models.WaitlistEntry,
+	tenantApplication *models.TenantApplication,
+) {
+	now := time.Now()
+	applicationID := tenantApplication.ID.String()
+	entry.Status = "APPLIED"
+	entry.TenantApplicationID = &applicationID
+	entry.AppliedAt = &now
+
+	if err := s.waitlistEntryRepo.Update(ctx, entry); err != nil {
+		log.WithError(err).
+			WithField("waitlist_entry_id", entry.ID.String()).
+			Error("failed to mark waitlist entry applied")
+	}
+}
+
 type InviteTenantInput struct {
 	Email    *string
 	Phone    *string
@@ -840,6 +897,11 @@ func (s *tenantApplicationService) UpdateTenantApplication(
 	}
 
 	if input.LeaseAgreementDocumentStatus.IsSet {
+		if input.LeaseAgreementDocumentStatus.GetOr("") == "FINALIZED" {
+			if fieldsErr := s.checkLeaseAgreementMergeFields(ctx, tenantApplication); fieldsErr != nil {
+				return nil, fieldsErr
+			}
+		}
 		tenantApplication.LeaseAgreementDocumentStatus = input.LeaseAgreementDocumentStatus.Ptr()
 	}
 
@@ -1307,6 +1369,13 @@ func (s *tenantApplicationService) ApproveTenantApplication(
 		return nil, createLeaseErr
 	}
 
+	if carryErr := s.guarantorService.CarryToLease(
+		transCtx, input.TenantApplicationID, lease.ID.String(),
+	); carryErr != nil {
+		transaction.Rollback()
+		return nil, carryErr
+	}
+
 	// The application -> lease financial transition. Not a single charge,
 	// invoice, payment or allocation moves, which is why paying before and
 	// after approval are the same operation.
@@ -1553,3 +1622,48 @@ func (s *tenantApplicationService) PrepareCharges(
 		SecurityDepositDue:    *tenantApplication.DesiredMoveInDate,
 	})
 }
+
+// checkLeaseAgreementMergeFields keeps an ONLINE lease agreement from being
+// finalized with merge fields the application cannot fill. Its own fields
+// are read from the update in progress, so values set alongside the status
+// count.
+func (s *tenantApplicationService) checkLeaseAgreementMergeFields(
+	ctx context.Context,
+	application *models.TenantApplication,
+) error {
+	if application.LeaseAgreementDocumentMode == nil || *application.LeaseAgreementDocumentMode != "ONLINE" ||
+		application.LeaseAgreementDocumentID == nil {
+		return nil
+	}
+
+	document, err := s.documentRepo.GetByID(ctx, *application.LeaseAgreementDocumentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return pkg.NotFoundError("DocumentNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "checkLeaseAgreementMergeFields", "action": "fetching document"},
+		})
+	}
+
+	related, relatedErr := s.repo.GetOneWithQuery(ctx, repository.GetTenantApplicationQuery{
+		TenantApplicationID: application.ID.String(),
+		Populate:            &tenantApplicationMergeFieldsPopulate,
+	})
+	if relatedErr != nil {
+		return pkg.InternalServerError(relatedErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      relatedErr,
+			Metadata: map[string]string{"function": "checkLeaseAgreementMergeFields", "action": "fetching application"},
+		})
+	}
+
+	// A copy, so the relations never reach the application being saved.
+	withRelations := *application
+	withRelations.CreatedBy = related.CreatedBy
+	withRelations.DesiredUnit = related.DesiredUnit
+
+	// The agreement is dated the day it is finalized.
+	now := time.Now()
+	return checkMergeFieldsResolved(document.Content, tenantApplicationMergeFields(&withRelations, &now))
+}
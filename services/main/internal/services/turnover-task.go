@@ -0,0 +1,683 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// TurnoverTaskService readies units for their next occupant. The end of a
+// booking or a lease generates cleaning, linen and inspection tasks for the
+// property's staff and blocks the unit until they are done or due.
+type TurnoverTaskService interface {
+	// GenerateForBooking and GenerateForLease create the turnover tasks for a
+	// stay that has just ended, and block its unit until they are done or
+	// due. They join the caller's transaction when there is one.
+	GenerateForBooking(ctx context.Context, booking *models.Booking) error
+	// GenerateForLease also holds the unit's occupancy: the lease keeps the
+	// unit out of Available, and its waitlist uninvited, until the tasks are
+	// settled and releaseUnitIfNoActiveLease runs.
+	GenerateForLease(ctx context.Context, lease *models.Lease) error
+	GetTurnoverTask(ctx context.Context, query repository.GetTurnoverTaskQuery) (*models.TurnoverTask, error)
+	ListTurnoverTasks(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListTurnoverTasksFilter,
+	) ([]models.TurnoverTask, error)
+	CountTurnoverTasks(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListTurnoverTasksFilter,
+	) (int64, error)
+	AssignTurnoverTask(ctx context.Context, input AssignTurnoverTaskInput) (*models.TurnoverTask, error)
+	CompleteTurnoverTask(ctx context.Context, input CompleteTurnoverTaskInput) (*models.TurnoverTask, error)
+	CancelTurnoverTask(ctx context.Context, input CancelTurnoverTaskInput) (*models.TurnoverTask, error)
+	// AlertOverdueTurnoverTasks emails the managers of each property the
+	// tasks still PENDING that fell due in [from, until). Their unit's
+	// TURNOVER block has lapsed by then, so it can be booked before it is
+	// ready.
+	AlertOverdueTurnoverTasks(ctx context.Context, from, until time.Time) error
+}
+
+type turnoverTaskService struct {
+	appCtx                 pkg.AppContext
+	repo                   repository.TurnoverTaskRepository
+	unitDateBlockRepo      repository.UnitDateBlockRepository
+	bookingRepo            repository.BookingRepository
+	leaseRepo              repository.LeaseRepository
+	unitRepo               repository.UnitRepository
+	clientUserPropertyRepo repository.ClientUserPropertyRepository
+	unitService            UnitService
+}
+
+type TurnoverTaskServiceDeps struct {
+	AppCtx                 pkg.AppContext
+	Repo                   repository.TurnoverTaskRepository
+	UnitDateBlockRepo      repository.UnitDateBlockRepository
+	BookingRepo            repository.BookingRepository
+	LeaseRepo              repository.LeaseRepository
+	UnitRepo               repository.UnitRepository
+	ClientUserPropertyRepo repository.ClientUserPropertyRepository
+	UnitService            UnitService
+}
+
+func NewTurnoverTaskService(deps TurnoverTaskServiceDeps) TurnoverTaskService {
+	return &turnoverTaskService{
+		appCtx:                 deps.AppCtx,
+		repo:                   deps.Repo,
+		unitDateBlockRepo:      deps.UnitDateBlockRepo,
+		bookingRepo:            deps.BookingRepo,
+		leaseRepo:              deps.LeaseRepo,
+		unitRepo:               deps.UnitRepo,
+		clientUserPropertyRepo: deps.ClientUserPropertyRepo,
+		unitService:            deps.UnitService,
+	}
+}
+
+const (
+	// turnoverWindow is how long a unit has to be turned over when no guest
+	// is due to check in sooner.
+	turnoverWindow = 24 * time.Hour
+	// sameDayTurnoverWindow is how long a unit has when the next guest
+	// arrives the day the last one left. Bookings carry a check-in date, not
+	// a time, so that arrival is taken to be this long after the checkout.
+	sameDayTurnoverWindow = 3 * time.Hour
+)
+
+var (
+	bookingTurnoverTaskTypes = []string{"CLEANING", "LINEN", "INSPECTION"}
+	// a tenant on a lease furnishes their own bedding, so there is no linen
+	// to change when they leave
+	leaseTurnoverTaskTypes = []string{"CLEANING", "INSPECTION"}
+)
+
+// turnoverStay is the stay whose end a turnover follows. Exactly one of
+// BookingID and LeaseID is set.
+type turnoverStay struct {
+	PropertyID string
+	UnitID     string
+	BookingID  *string
+	LeaseID    *string
+	EndedAt    time.Time
+}
+
+// turnoverDueAt is when a unit whose last stay ended at endedAt must be
+// ready: by the next check-in, and within the turnover window otherwise.
+func turnoverDueAt(endedAt time.Time, nextCheckIn *time.Time) time.Time {
+	dueAt := endedAt.Add(turnoverWindow)
+	if nextCheckIn == nil {
+		return dueAt
+	}
+
+	arrival := *nextCheckIn
+	if !arrival.After(endedAt) {
+		arrival = endedAt.Add(sameDayTurnoverWindow)
+	}
+	if arrival.Before(dueAt) {
+		return arrival
+	}
+	return dueAt
+}
+
+// turnoverBlockDates are the nights a TURNOVER block covers: from the day
+// the stay ended, so the unit cannot be taken for tonight before it is
+// ready, up to the day the tasks are due. A task left undone does not hold
+// the unit past then; AlertOverdueTurnoverTasks chases it instead. A
+// turnover due the day the stay ended, ahead of a guest arriving that day,
+// covers no nights at all.
+func turnoverBlockDates(endedAt, dueAt time.Time) (time.Time, time.Time) {
+	startDate := time.Date(endedAt.Year(), endedAt.Month(), endedAt.Day(), 0, 0, 0, 0, endedAt.Location())
+	dueAt = dueAt.In(endedAt.Location())
+	endDate := time.Date(dueAt.Year(), dueAt.Month(), dueAt.Day(), 0, 0, 0, 0, endedAt.Location())
+	return startDate, endDate
+}
+
+// pickTurnoverAssignee is the staff member with the fewest PENDING tasks,
+// the one linked to the property earliest on a tie, or nil for a property
+// without staff.
+func pickTurnoverAssignee(staffIDs []string, pending map[string]int64) *string {
+	var assignee *string
+	for i := range staffIDs {
+		if assignee == nil || pending[staffIDs[i]] < pending[*assignee] {
+			assignee = &staffIDs[i]
+		}
+	}
+	return assignee
+}
+
+func (s *turnoverTaskService) GenerateForBooking(ctx context.Context, booking *models.Booking) error {
+	bookingID := booking.ID.String()
+	endedAt := time.Now()
+	if booking.CheckedOutAt != nil {
+		endedAt = *booking.CheckedOutAt
+	}
+
+	return s.generate(ctx, turnoverStay{
+		PropertyID: booking.PropertyID,
+		UnitID:     booking.UnitID,
+		BookingID:  &bookingID,
+		EndedAt:    endedAt,
+	}, bookingTurnoverTaskTypes)
+}
+
+func (s *turnoverTaskService) GenerateForLease(ctx context.Context, lease *models.Lease) error {
+	leaseID := lease.ID.String()
+	endedAt := time.Now()
+	switch {
+	case lease.CompletedAt != nil:
+		endedAt = *lease.CompletedAt
+	case lease.TerminatedAt != nil:
+		endedAt = *lease.TerminatedAt
+	}
+
+	return s.generate(ctx, turnoverStay{
+		PropertyID: lease.Unit.PropertyID,
+		UnitID:     lease.UnitId,
+		LeaseID:    &leaseID,
+		EndedAt:    endedAt,
+	}, leaseTurnoverTaskTypes)
+}
+
+func (s *turnoverTaskService) generate(ctx context.Context, stay turnoverStay, taskTypes []string) error {
+	var nextCheckIn *time.Time
+	nextBooking, err := s.bookingRepo.GetNextCheckIn(ctx, stay.UnitID, stay.EndedAt)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GenerateTurnoverTasks",
+				"action":   "fetching next check-in",
+			},
+		})
+	}
+	if nextBooking != nil {
+		nextCheckIn = &nextBooking.CheckInDate
+	}
+	dueAt := turnoverDueAt(stay.EndedAt, nextCheckIn)
+
+	assignee, err := s.leastBusyStaff(ctx, stay.PropertyID)
+	if err != nil {
+		return err
+	}
+
+	tasks := make([]models.TurnoverTask, 0, len(taskTypes))
+	for _, taskType := range taskTypes {
+		tasks = append(tasks, models.TurnoverTask{
+			PropertyID:             stay.PropertyID,
+			UnitID:                 stay.UnitID,
+			BookingID:              stay.BookingID,
+			LeaseID:                stay.LeaseID,
+			Type:                   taskType,
+			Status:                 "PENDING",
+			DueAt:                  dueAt,
+			AssignedToClientUserID: assignee,
+		})
+	}
+
+	startDate, endDate := turnoverBlockDates(stay.EndedAt, dueAt)
+	block := models.UnitDateBlock{
+		UnitID:    stay.UnitID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		BlockType: "TURNOVER",
+		BookingID: stay.BookingID,
+		LeaseID:   stay.LeaseID,
+		Reason:    "Turnover after checkout",
+	}
+
+	// Use an existing outer transaction if provided, otherwise start our own
+	outerTx, hasOuterTx := lib.TransactionFromContext(ctx)
+	var transaction *gorm.DB
+	if hasOuterTx && outerTx != nil {
+		transaction = outerTx
+	} else {
+		transaction = s.appCtx.DB.Begin()
+	}
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.repo.BulkCreate(transCtx, &tasks); err != nil {
+		if !hasOuterTx {
+			transaction.Rollback()
+		}
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GenerateTurnoverTasks",
+				"action":   "creating turnover tasks",
+			},
+		})
+	}
+
+	if endDate.After(startDate) {
+		if err := s.unitDateBlockRepo.Create(transCtx, &block); err != nil {
+			if !hasOuterTx {
+				transaction.Rollback()
+			}
+			return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err: err,
+				Metadata: map[string]string{
+					"function": "GenerateTurnoverTasks",
+					"action":   "creating turnover block",
+				},
+			})
+		}
+	}
+
+	if hasOuterTx {
+		return nil
+	}
+
+	if err := transaction.Commit().Error; err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GenerateTurnoverTasks",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	return nil
+}
+
+func (s *turnoverTaskService) leastBusyStaff(ctx context.Context, propertyID string) (*string, error) {
+	staffIDs, err := s.clientUserPropertyRepo.ListClientUserIDs(ctx, propertyID, "STAFF")
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GenerateTurnoverTasks",
+				"action":   "listing property staff",
+			},
+		})
+	}
+	if len(staffIDs) == 0 {
+		return nil, nil
+	}
+
+	pending, err := s.repo.CountPendingByAssignee(ctx, staffIDs)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GenerateTurnoverTasks",
+				"action":   "counting staff workload",
+			},
+		})
+	}
+
+	return pickTurnoverAssignee(staffIDs, pending), nil
+}
+
+func (s *turnoverTaskService) GetTurnoverTask(
+	ctx context.Context,
+	query repository.GetTurnoverTaskQuery,
+) (*models.TurnoverTask, error) {
+	task, err := s.repo.GetByIDWithPopulate(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("TurnoverTaskNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GetTurnoverTask",
+				"action":   "fetching turnover task",
+			},
+		})
+	}
+
+	return task, nil
+}
+
+func (s *turnoverTaskService) ListTurnoverTasks(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListTurnoverTasksFilter,
+) ([]models.TurnoverTask, error) {
+	tasks, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ListTurnoverTasks",
+				"action":   "listing turnover tasks",
+			},
+		})
+	}
+
+	return tasks, nil
+}
+
+func (s *turnoverTaskService) CountTurnoverTasks(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListTurnoverTasksFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filterQuery, filters)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CountTurnoverTasks",
+				"action":   "counting turnover tasks",
+			},
+		})
+	}
+
+	return count, nil
+}
+
+// getPendingTurnoverTask fetches a task of the property that is still to be
+// done, which is the only kind that can be assigned, completed or cancelled.
+func (s *turnoverTaskService) getPendingTurnoverTask(
+	ctx context.Context,
+	taskID string,
+	propertyID string,
+) (*models.TurnoverTask, error) {
+	task, err := s.GetTurnoverTask(ctx, repository.GetTurnoverTaskQuery{ID: taskID, PropertyID: &propertyID})
+	if err != nil {
+		return nil, err
+	}
+
+	if task.Status != "PENDING" {
+		return nil, pkg.BadRequestError("only PENDING turnover tasks can be changed", &pkg.RentLoopErrorParams{
+			Err: errors.New("turnover task is not in PENDING status"),
+			Metadata: map[string]string{
+				"function": "getPendingTurnoverTask",
+				"action":   "validating turnover task status",
+			},
+		})
+	}
+
+	return task, nil
+}
+
+type AssignTurnoverTaskInput struct {
+	TaskID       string
+	PropertyID   string
+	ClientUserID string
+}
+
+// AssignTurnoverTask hands a task to another member of the property's team.
+func (s *turnoverTaskService) AssignTurnoverTask(
+	ctx context.Context,
+	input AssignTurnoverTaskInput,
+) (*models.TurnoverTask, error) {
+	task, err := s.getPendingTurnoverTask(ctx, input.TaskID, input.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked, err := s.clientUserPropertyRepo.Count(ctx, repository.ListClientUserPropertiesFilter{
+		ClientUserID: &input.ClientUserID,
+		PropertyID:   &input.PropertyID,
+	})
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "AssignTurnoverTask",
+				"action":   "checking assignee property access",
+			},
+		})
+	}
+	if linked == 0 {
+		return nil, pkg.BadRequestError("assignee is not a member of this property", &pkg.RentLoopErrorParams{
+			Err: errors.New("client user is not linked to the property"),
+			Metadata: map[string]string{
+				"function": "AssignTurnoverTask",
+				"action":   "validating assignee",
+			},
+		})
+	}
+
+	task.AssignedToClientUserID = &input.ClientUserID
+	if err := s.repo.Update(ctx, task); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "AssignTurnoverTask",
+				"action":   "updating turnover task",
+			},
+		})
+	}
+
+	return task, nil
+}
+
+type CompleteTurnoverTaskInput struct {
+	TaskID       string
+	PropertyID   string
+	ClientUserID string
+	Notes        *string
+}
+
+func (s *turnoverTaskService) CompleteTurnoverTask(
+	ctx context.Context,
+	input CompleteTurnoverTaskInput,
+) (*models.TurnoverTask, error) {
+	task, err := s.getPendingTurnoverTask(ctx, input.TaskID, input.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	task.Status = "DONE"
+	task.CompletedAt = &now
+	task.CompletedByClientUserID = &input.ClientUserID
+	if input.Notes != nil {
+		task.Notes = *input.Notes
+	}
+
+	if err := s.settleTurnoverTask(ctx, task, "CompleteTurnoverTask"); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+type CancelTurnoverTaskInput struct {
+	TaskID       string
+	PropertyID   string
+	ClientUserID string
+	Reason       string
+}
+
+// CancelTurnoverTask drops a task that does not need doing, such as linen
+// for a unit that was never slept in.
+func (s *turnoverTaskService) CancelTurnoverTask(
+	ctx context.Context,
+	input CancelTurnoverTaskInput,
+) (*models.TurnoverTask, error) {
+	task, err := s.getPendingTurnoverTask(ctx, input.TaskID, input.PropertyID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	task.Status = "CANCELLED"
+	task.CanceledAt = &now
+	task.CanceledByID = &input.ClientUserID
+	task.CancellationReason = input.Reason
+
+	if err := s.settleTurnoverTask(ctx, task, "CancelTurnoverTask"); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// settleTurnoverTask saves a task that is no longer PENDING and, when it was
+// the last one left for its stay, lifts the unit's TURNOVER block. For a
+// lease, that is also when the unit is released to its next tenant.
+func (s *turnoverTaskService) settleTurnoverTask(
+	ctx context.Context,
+	task *models.TurnoverTask,
+	function string,
+) error {
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if err := s.repo.Update(transCtx, task); err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": function,
+				"action":   "updating turnover task",
+			},
+		})
+	}
+
+	pending, err := s.repo.CountPendingForStay(transCtx, task.BookingID, task.LeaseID)
+	if err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": function,
+				"action":   "counting pending turnover tasks",
+			},
+		})
+	}
+
+	if pending == 0 {
+		if err := s.unitDateBlockRepo.DeleteTurnoverBlock(transCtx, task.BookingID, task.LeaseID); err != nil {
+			transaction.Rollback()
+			return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+				Err: err,
+				Metadata: map[string]string{
+					"function": function,
+					"action":   "lifting turnover block",
+				},
+			})
+		}
+
+		if task.LeaseID != nil {
+			if err := s.releaseUnit(transCtx, task.UnitID, task.PropertyID); err != nil {
+				transaction.Rollback()
+				return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+					Err: err,
+					Metadata: map[string]string{
+						"function": function,
+						"action":   "releasing unit",
+					},
+				})
+			}
+		}
+	}
+
+	if err := transaction.Commit().Error; err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": function,
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	return nil
+}
+
+func (s *turnoverTaskService) AlertOverdueTurnoverTasks(ctx context.Context, from, until time.Time) error {
+	tasks, err := s.repo.ListPendingDueBetween(ctx, from, until)
+	if err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "AlertOverdueTurnoverTasks",
+				"action":   "listing overdue turnover tasks",
+			},
+		})
+	}
+
+	// tasks come ordered by property, so each property's run is contiguous
+	for start := 0; start < len(tasks); {
+		end := start
+		for end < len(tasks) && tasks[end].PropertyID == tasks[start].PropertyID {
+			end++
+		}
+		s.alertPropertyManagers(ctx, tasks[start:end])
+		start = end
+	}
+
+	return nil
+}
+
+// alertPropertyManagers emails the overdue tasks of one property to each of
+// its managers. A manager who cannot be reached is logged and skipped.
+func (s *turnoverTaskService) alertPropertyManagers(ctx context.Context, tasks []models.TurnoverTask) {
+	property := tasks[0].Property
+	managers, err := s.clientUserPropertyRepo.List(ctx, repository.ListClientUserPropertiesFilter{
+		FilterQuery: lib.FilterQuery{Populate: &[]string{"ClientUser.User"}},
+		PropertyID:  &tasks[0].PropertyID,
+		Role:        lib.StringPointer("MANAGER"),
+	})
+	if err != nil {
+		log.WithError(err).WithField("property_id", tasks[0].PropertyID).
+			Error("failed to list property managers for overdue turnover tasks")
+		return
+	}
+
+	overdue := make([]emailtemplates.TurnoverOverdueTask, 0, len(tasks))
+	for _, task := range tasks {
+		var assigneeName string
+		if task.AssignedToClientUser != nil {
+			assigneeName = task.AssignedToClientUser.User.Name
+		}
+		overdue = append(overdue, emailtemplates.TurnoverOverdueTask{
+			UnitName:     task.Unit.Name,
+			Type:         task.Type,
+			DueAt:        task.DueAt.Format("January 2, 2006 3:04pm"),
+			AssigneeName: assigneeName,
+		})
+	}
+
+	for _, manager := range *managers {
+		if manager.ClientUser.User.Email == "" {
+			continue
+		}
+
+		htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+			"turnover/overdue-manager",
+			emailtemplates.TurnoverOverdueData{
+				ManagerName:  manager.ClientUser.User.Name,
+				PropertyName: property.Name,
+				Tasks:        overdue,
+			},
+		)
+		if renderErr != nil {
+			log.WithError(renderErr).Error("failed to render turnover/overdue-manager email template")
+			return
+		}
+
+		go pkg.SendEmail(s.appCtx.Config, pkg.SendEmailInput{
+			Recipient: manager.ClientUser.User.Email,
+			Subject:   lib.PM_TURNOVER_OVERDUE_SUBJECT,
+			HtmlBody:  htmlBody,
+			TextBody:  textBody,
+		})
+	}
+}
+
+// releaseUnit re-evaluates the occupancy of a unit a lease has been turned
+// over from, now that it is ready for someone else.
+func (s *turnoverTaskService) releaseUnit(ctx context.Context, unitID, propertyID string) error {
+	unit, err := s.unitRepo.GetOne(ctx, map[string]any{"id": unitID, "property_id": propertyID})
+	if err != nil {
+		return err
+	}
+
+	return releaseUnitIfNoActiveLease(ctx, s.leaseRepo, s.unitService, unit)
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+// A unit has a day to be turned over, but never longer than until the next
+// guest arrives. Bookings check in on a date, so a guest arriving the day the
+// last one left gets the same-day window rather than a due time already past.
+func TestTurnoverDueAt(t *testing.T) {
+	endedAt := time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)
+	sameDay := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	nextDay := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+	nextWeek := time.Date(2026, 10, 26, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		nextCheckIn *time.Time
+		want        time.Time
+	}{
+		{"no one due", nil, endedAt.Add(turnoverWindow)},
+		{"next guest arrives the same day", &sameDay, endedAt.Add(sameDayTurnoverWindow)},
+		{"next guest arrives the next day", &nextDay, nextDay},
+		{"next guest arrives next week", &nextWeek, endedAt.Add(turnoverWindow)},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := turnoverDueAt(endedAt, tc.nextCheckIn); !got.Equal(tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+// The block takes the night the stay ended, so the unit is not booked for
+// tonight while it is still being cleaned, and lapses on the day the tasks
+// are due rather than holding the unit for as long as one is left undone.
+func TestTurnoverBlockDates(t *testing.T) {
+	endedAt := time.Date(2026, 10, 19, 11, 0, 0, 0, time.UTC)
+	sameDay := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	nextDay := time.Date(2026, 10, 20, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name      string
+		dueAt     time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{"due within the day's window", endedAt.Add(turnoverWindow), sameDay, nextDay},
+		{"due at the next guest's check-in", nextDay, sameDay, nextDay},
+		{"next guest arrives the same day", endedAt.Add(sameDayTurnoverWindow), sameDay, sameDay},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, end := turnoverBlockDates(endedAt, tc.dueAt)
+			if !start.Equal(tc.wantStart) {
+				t.Errorf("start = %v, want %v", start, tc.wantStart)
+			}
+			if !end.Equal(tc.wantEnd) {
+				t.Errorf("end = %v, want %v", end, tc.wantEnd)
+			}
+		})
+	}
+}
+
+// Work goes to whoever has the least of it, so one staff member is not
+// handed every unit while another has none.
+func TestPickTurnoverAssignee(t *testing.T) {
+	cases := []struct {
+		name     string
+		staffIDs []string
+		pending  map[string]int64
+		want     string
+	}{
+		{"no staff", nil, nil, ""},
+		{"nobody busy picks the first linked", []string{"ama", "kofi"}, map[string]int64{}, "ama"},
+		{"least busy", []string{"ama", "kofi", "esi"}, map[string]int64{"ama": 4, "kofi": 1, "esi": 2}, "kofi"},
+		{"idle staff have no count", []string{"ama", "kofi"}, map[string]int64{"ama": 3}, "kofi"},
+		{"tie goes to the first linked", []string{"ama", "kofi"}, map[string]int64{"ama": 2, "kofi": 2}, "ama"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := pickTurnoverAssignee(tc.staffIDs, tc.pending)
+			switch {
+			case tc.want == "" && got != nil:
+				t.Errorf("got %s, want nobody", *got)
+			case tc.want != "" && (got == nil || *got != tc.want):
+				t.Errorf("got %v, want %s", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/ical"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+)
+
+// unitCalendarUIDSuffix ends the UID of every event in our own export feeds,
+// so a subscription pointed back at one of them does not import our blocks as
+// someone else's.
+const unitCalendarUIDSuffix = "@rentloop"
+
+// calendarSyncPlan is what one sync changes about a subscription's blocks.
+type calendarSyncPlan struct {
+	Create []models.UnitDateBlock
+	Update []models.UnitDateBlock
+	Delete []string // block IDs
+	// EventCount is how many current and upcoming events the feed holds.
+	EventCount int64
+}
+
+// planCalendarSync matches a feed's events to the EXTERNAL blocks an earlier
+// sync made from it, by UID. Events that are new get a block, events whose
+// dates or summary moved get their block changed, and blocks whose event has
+// left the feed are deleted. Feeds drop events once they are over, so past
+// blocks are left as they are rather than read as cancellations.
+func planCalendarSync(
+	subscription *models.UnitCalendarSubscription,
+	existing []models.UnitDateBlock,
+	events []ical.Event,
+	today time.Time,
+) calendarSyncPlan {
+	today = calendarDate(today)
+	subscriptionID := subscription.ID.String()
+
+	byUID := make(map[string]models.UnitDateBlock, len(existing))
+	for _, block := range existing {
+		if block.ExternalUID != nil {
+			byUID[*block.ExternalUID] = block
+		}
+	}
+
+	var plan calendarSyncPlan
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		if event.Status == "CANCELLED" || strings.HasSuffix(event.UID, unitCalendarUIDSuffix) || seen[event.UID] {
+			continue
+		}
+
+		startDate, endDate := externalEventDates(event)
+		if !endDate.After(today) {
+			continue
+		}
+		seen[event.UID] = true
+		plan.EventCount++
+
+		reason := strings.TrimSpace(event.Summary)
+		block, ok := byUID[event.UID]
+		if !ok {
+			uid := event.UID
+			plan.Create = append(plan.Create, models.UnitDateBlock{
+				UnitID:                 subscription.UnitID,
+				StartDate:              startDate,
+				EndDate:                endDate,
+				BlockType:              "EXTERNAL",
+				CalendarSubscriptionID: &subscriptionID,
+				ExternalUID:            &uid,
+				Reason:                 reason,
+			})
+			continue
+		}
+
+		if !calendarDate(block.StartDate).Equal(startDate) || !calendarDate(block.EndDate).Equal(endDate) ||
+			block.Reason != reason {
+			block.StartDate = startDate
+			block.EndDate = endDate
+			block.Reason = reason
+			plan.Update = append(plan.Update, block)
+		}
+	}
+
+	for _, block := range existing {
+		if block.ExternalUID != nil && seen[*block.ExternalUID] {
+			continue
+		}
+		if calendarDate(block.EndDate).After(today) {
+			plan.Delete = append(plan.Delete, block.ID.String())
+		}
+	}
+
+	return plan
+}
+
+// externalEventDates is the nights an event blocks, as a block's
+// [StartDate, EndDate). A timed event holds the nights before the day it
+// ends, as a stay checking out that morning would, and every block covers at
+// least one night.
+func externalEventDates(event ical.Event) (time.Time, time.Time) {
+	startDate := calendarDate(event.Start)
+	endDate := calendarDate(event.End)
+	if !endDate.After(startDate) {
+		endDate = startDate.AddDate(0, 0, 1)
+	}
+
+	return startDate, endDate
+}
+
+// unitCalendarFeed is the export feed for unit: one all-day event per block.
+// Channels only need to know a night is taken, so the feed says who holds it
+// no more precisely than "Reserved" and carries no guest details or reasons.
+func unitCalendarFeed(unit *models.Unit, blocks []models.UnitDateBlock) ical.Calendar {
+	calendar := ical.Calendar{
+		ProductID: "-//Rentloop//Unit Calendar//EN",
+		Name:      unit.Name,
+		Events:    make([]ical.Event, 0, len(blocks)),
+	}
+
+	for _, block := range blocks {
+		summary := "Not available"
+		if block.BlockType == "BOOKING" || block.BlockType == "LEASE" {
+			summary = "Reserved"
+		}
+
+		calendar.Events = append(calendar.Events, ical.Event{
+			UID:     block.ID.String() + unitCalendarUIDSuffix,
+			Summary: summary,
+			Start:   calendarDate(block.StartDate),
+			End:     calendarDate(block.EndDate),
+			AllDay:  true,
+			Stamp:   block.UpdatedAt,
+		})
+	}
+
+	return calendar
+}
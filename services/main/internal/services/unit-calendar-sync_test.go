@@ -0,0 +1,119 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/calendarfeed"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/ical"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+// A sync creates blocks for new events, moves blocks whose event moved and
+// deletes blocks whose event left the feed. Past events and past blocks are
+// left alone, since channels drop stays once they are over; cancelled events
+// and events from our own export feed are never imported.
+func TestPlanCalendarSync(t *testing.T) {
+	feed, err := calendarfeed.NewFileClient("testdata/calendars").
+		Fetch(context.Background(), "file:///airbnb.ics")
+	if err != nil {
+		t.Fatalf("Fetch: %v", err)
+	}
+	events, err := ical.Parse(bytes.NewReader(feed))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	subscription := &models.UnitCalendarSubscription{UnitID: "660e8400-e29b-41d4-a716-446655440000"}
+	subscription.ID = uuid.Must(uuid.NewV4())
+
+	block := func(uid, start, end string) models.UnitDateBlock {
+		b := models.UnitDateBlock{
+			UnitID:      subscription.UnitID,
+			StartDate:   date(start),
+			EndDate:     date(end),
+			BlockType:   "EXTERNAL",
+			ExternalUID: &uid,
+			Reason:      "Reserved",
+		}
+		b.ID = uuid.Must(uuid.NewV4())
+		return b
+	}
+	moved := block("moved-stay@airbnb.com", "2026-11-02", "2026-11-04")
+	unchanged := block("unchanged-stay@airbnb.com", "2026-11-10", "2026-11-12")
+	gone := block("gone-stay@airbnb.com", "2026-11-15", "2026-11-18")
+	goneLongAgo := block("old-stay@airbnb.com", "2026-10-01", "2026-10-03")
+
+	plan := planCalendarSync(
+		subscription,
+		[]models.UnitDateBlock{moved, unchanged, gone, goneLongAgo},
+		events,
+		time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC),
+	)
+
+	if len(plan.Create) != 1 || *plan.Create[0].ExternalUID != "new-stay@airbnb.com" ||
+		!plan.Create[0].StartDate.Equal(date("2026-11-20")) || !plan.Create[0].EndDate.Equal(date("2026-11-25")) ||
+		plan.Create[0].BlockType != "EXTERNAL" || *plan.Create[0].CalendarSubscriptionID != subscription.ID.String() ||
+		plan.Create[0].Reason != "Airbnb (Not available)" {
+		t.Errorf("create = %+v, want only new-stay for 20–25 Nov", plan.Create)
+	}
+	if len(plan.Update) != 1 || plan.Update[0].ID != moved.ID || !plan.Update[0].StartDate.Equal(date("2026-11-01")) {
+		t.Errorf("update = %+v, want moved-stay from 1 Nov", plan.Update)
+	}
+	if !slices.Equal(plan.Delete, []string{gone.ID.String()}) {
+		t.Errorf("delete = %v, want only gone-stay %s", plan.Delete, gone.ID)
+	}
+	if plan.EventCount != 3 {
+		t.Errorf("event count = %d, want 3", plan.EventCount)
+	}
+}
+
+// A unit's export feed describes its blocks without saying why they are
+// there, and a subscription pointed back at it imports nothing.
+func TestUnitCalendarFeed(t *testing.T) {
+	date := func(s string) time.Time {
+		d, _ := time.Parse(time.DateOnly, s)
+		return d
+	}
+	booking := models.UnitDateBlock{
+		StartDate: date("2026-11-01"), EndDate: date("2026-11-04"), BlockType: "BOOKING", Reason: "Guest: Ama Mensah",
+	}
+	booking.ID = uuid.Must(uuid.NewV4())
+	maintenance := models.UnitDateBlock{
+		StartDate: date("2026-11-10"), EndDate: date("2026-11-11"), BlockType: "MAINTENANCE", Reason: "Repainting",
+	}
+	maintenance.ID = uuid.Must(uuid.NewV4())
+
+	calendar := unitCalendarFeed(&models.Unit{Name: "Unit 4B"}, []models.UnitDateBlock{booking, maintenance})
+
+	var out bytes.Buffer
+	if err := ical.Render(&out, calendar); err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+	if bytes.Contains(out.Bytes(), []byte("Ama")) || bytes.Contains(out.Bytes(), []byte("Repainting")) {
+		t.Errorf("feed leaks block reasons:\n%s", out.String())
+	}
+
+	events, err := ical.Parse(&out)
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if len(events) != 2 || events[0].Summary != "Reserved" || events[1].Summary != "Not available" ||
+		!events[0].AllDay || !events[0].End.Equal(date("2026-11-04")) {
+		t.Errorf("events = %+v", events)
+	}
+
+	subscription := &models.UnitCalendarSubscription{}
+	plan := planCalendarSync(subscription, nil, events, date("2026-10-19"))
+	if len(plan.Create) != 0 || plan.EventCount != 0 {
+		t.Errorf("re-importing our own feed planned %+v", plan)
+	}
+}
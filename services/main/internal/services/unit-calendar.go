@@ -0,0 +1,645 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/calendarfeed"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/ical"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// UnitCalendarService keeps a unit's availability in step with the other
+// channels it is listed on: it imports their iCal feeds as EXTERNAL blocks and
+// publishes the unit's own bookings and blocks as a feed they can import.
+type UnitCalendarService interface {
+	CreateSubscription(
+		ctx context.Context,
+		input CreateUnitCalendarSubscriptionInput,
+	) (*models.UnitCalendarSubscription, error)
+	UpdateSubscription(
+		ctx context.Context,
+		input UpdateUnitCalendarSubscriptionInput,
+	) (*models.UnitCalendarSubscription, error)
+	// DeleteSubscription deletes the subscription and the blocks it imported.
+	DeleteSubscription(ctx context.Context, input repository.DeleteUnitCalendarSubscriptionInput) error
+	GetSubscription(
+		ctx context.Context,
+		query repository.GetUnitCalendarSubscriptionQuery,
+	) (*models.UnitCalendarSubscription, error)
+	ListSubscriptions(
+		ctx context.Context,
+		filter repository.ListUnitCalendarSubscriptionsFilter,
+	) ([]models.UnitCalendarSubscription, error)
+	CountSubscriptions(ctx context.Context, filter repository.ListUnitCalendarSubscriptionsFilter) (int64, error)
+	// SyncSubscription fetches the subscription's feed now instead of waiting
+	// for the hourly sync. A feed that cannot be fetched or read is recorded
+	// on the subscription as a FAILED sync, not returned as an error.
+	SyncSubscription(
+		ctx context.Context,
+		query repository.GetUnitCalendarSubscriptionQuery,
+	) (*models.UnitCalendarSubscription, error)
+	// SyncAllSubscriptions syncs every subscription, for the periodic sweep.
+	SyncAllSubscriptions(ctx context.Context) (UnitCalendarSyncSweepResult, error)
+
+	// EnableExport gives the unit a new export feed URL, revoking any old one.
+	EnableExport(ctx context.Context, query repository.GetUnitQuery) (*models.Unit, error)
+	DisableExport(ctx context.Context, query repository.GetUnitQuery) error
+	// RenderExport is the iCalendar document behind an export feed URL.
+	RenderExport(ctx context.Context, token string) ([]byte, error)
+}
+
+type unitCalendarService struct {
+	appCtx            pkg.AppContext
+	repo              repository.UnitCalendarSubscriptionRepository
+	unitDateBlockRepo repository.UnitDateBlockRepository
+	bookingRepo       repository.BookingRepository
+	unitRepo          repository.UnitRepository
+	unitService       UnitService
+	fetcher           calendarfeed.Client
+}
+
+type UnitCalendarServiceDeps struct {
+	AppCtx            pkg.AppContext
+	Repo              repository.UnitCalendarSubscriptionRepository
+	UnitDateBlockRepo repository.UnitDateBlockRepository
+	BookingRepo       repository.BookingRepository
+	UnitRepo          repository.UnitRepository
+	UnitService       UnitService
+	Fetcher           calendarfeed.Client
+}
+
+func NewUnitCalendarService(deps UnitCalendarServiceDeps) UnitCalendarService {
+	return &unitCalendarService{
+		appCtx:            deps.AppCtx,
+		repo:              deps.Repo,
+		unitDateBlockRepo: deps.UnitDateBlockRepo,
+		bookingRepo:       deps.BookingRepo,
+		unitRepo:          deps.UnitRepo,
+		unitService:       deps.UnitService,
+		fetcher:           deps.Fetcher,
+	}
+}
+
+// An export feed reaches this far either side of today. Channels only block
+// dates from today on; the month behind keeps a stay that is under way in it.
+const (
+	unitCalendarExportPast   = 30 * 24 * time.Hour
+	unitCalendarExportFuture = 2 * 365 * 24 * time.Hour
+)
+
+type CreateUnitCalendarSubscriptionInput struct {
+	PropertyID            string
+	UnitID                string
+	Name                  string
+	URL                   string
+	CreatedByClientUserID string
+}
+
+// CreateSubscription saves the subscription and syncs it straight away, so
+// the manager sees at once whether the feed can be read.
+func (s *unitCalendarService) CreateSubscription(
+	ctx context.Context,
+	input CreateUnitCalendarSubscriptionInput,
+) (*models.UnitCalendarSubscription, error) {
+	if _, err := s.unitService.GetUnit(ctx, repository.GetUnitQuery{
+		PropertyID: input.PropertyID,
+		UnitID:     input.UnitID,
+	}); err != nil {
+		return nil, err
+	}
+	if err := s.validateFeedURL(input.URL); err != nil {
+		return nil, err
+	}
+
+	subscription := &models.UnitCalendarSubscription{
+		UnitID:                input.UnitID,
+		PropertyID:            input.PropertyID,
+		Name:                  input.Name,
+		URL:                   input.URL,
+		LastSyncStatus:        "PENDING",
+		CreatedByClientUserID: input.CreatedByClientUserID,
+	}
+	if err := s.repo.Create(ctx, subscription); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CreateSubscription",
+				"action":   "creating unit calendar subscription",
+			},
+		})
+	}
+
+	return s.syncSubscription(ctx, subscription.ID.String())
+}
+
+type UpdateUnitCalendarSubscriptionInput struct {
+	ID         string
+	PropertyID string
+	UnitID     string
+	Name       *string
+	URL        *string
+}
+
+func (s *unitCalendarService) UpdateSubscription(
+	ctx context.Context,
+	input UpdateUnitCalendarSubscriptionInput,
+) (*models.UnitCalendarSubscription, error) {
+	subscription, err := s.GetSubscription(ctx, repository.GetUnitCalendarSubscriptionQuery{
+		ID:         input.ID,
+		PropertyID: &input.PropertyID,
+		UnitID:     &input.UnitID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	urlChanged := input.URL != nil && *input.URL != subscription.URL
+	if input.Name != nil {
+		subscription.Name = *input.Name
+	}
+	if urlChanged {
+		if urlErr := s.validateFeedURL(*input.URL); urlErr != nil {
+			return nil, urlErr
+		}
+		subscription.URL = *input.URL
+		subscription.LastSyncStatus = "PENDING"
+		subscription.LastSyncError = ""
+	}
+
+	if updateErr := s.repo.Update(ctx, subscription); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function": "UpdateSubscription",
+				"action":   "updating unit calendar subscription",
+			},
+		})
+	}
+
+	if urlChanged {
+		return s.syncSubscription(ctx, subscription.ID.String())
+	}
+
+	return subscription, nil
+}
+
+func (s *unitCalendarService) DeleteSubscription(
+	ctx context.Context,
+	input repository.DeleteUnitCalendarSubscriptionInput,
+) error {
+	if _, err := s.GetSubscription(ctx, repository.GetUnitCalendarSubscriptionQuery{
+		ID:         input.ID,
+		PropertyID: &input.PropertyID,
+		UnitID:     &input.UnitID,
+	}); err != nil {
+		return err
+	}
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	deleteErr := s.unitDateBlockRepo.DeleteBySubscriptionID(transCtx, input.ID)
+	if deleteErr == nil {
+		deleteErr = s.repo.Delete(transCtx, input)
+	}
+	if deleteErr == nil {
+		deleteErr = transaction.Commit().Error
+	}
+	if deleteErr != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(deleteErr.Error(), &pkg.RentLoopErrorParams{
+			Err: deleteErr,
+			Metadata: map[string]string{
+				"function": "DeleteSubscription",
+				"action":   "deleting unit calendar subscription and its blocks",
+			},
+		})
+	}
+
+	return nil
+}
+
+func (s *unitCalendarService) GetSubscription(
+	ctx context.Context,
+	query repository.GetUnitCalendarSubscriptionQuery,
+) (*models.UnitCalendarSubscription, error) {
+	subscription, err := s.repo.GetOne(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("UnitCalendarSubscriptionNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GetSubscription",
+				"action":   "fetching unit calendar subscription",
+			},
+		})
+	}
+
+	return subscription, nil
+}
+
+func (s *unitCalendarService) ListSubscriptions(
+	ctx context.Context,
+	filter repository.ListUnitCalendarSubscriptionsFilter,
+) ([]models.UnitCalendarSubscription, error) {
+	subscriptions, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ListSubscriptions",
+				"action":   "listing unit calendar subscriptions",
+			},
+		})
+	}
+
+	return *subscriptions, nil
+}
+
+func (s *unitCalendarService) CountSubscriptions(
+	ctx context.Context,
+	filter repository.ListUnitCalendarSubscriptionsFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filter)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CountSubscriptions",
+				"action":   "counting unit calendar subscriptions",
+			},
+		})
+	}
+
+	return count, nil
+}
+
+func (s *unitCalendarService) SyncSubscription(
+	ctx context.Context,
+	query repository.GetUnitCalendarSubscriptionQuery,
+) (*models.UnitCalendarSubscription, error) {
+	if _, err := s.GetSubscription(ctx, query); err != nil {
+		return nil, err
+	}
+
+	return s.syncSubscription(ctx, query.ID)
+}
+
+type UnitCalendarSyncSweepResult struct {
+	Synced int
+	Failed int
+}
+
+func (s *unitCalendarService) SyncAllSubscriptions(ctx context.Context) (UnitCalendarSyncSweepResult, error) {
+	var result UnitCalendarSyncSweepResult
+
+	ids, err := s.repo.ListIDs(ctx)
+	if err != nil {
+		return result, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "SyncAllSubscriptions",
+				"action":   "listing unit calendar subscriptions",
+			},
+		})
+	}
+
+	for _, id := range ids {
+		subscription, syncErr := s.syncSubscription(ctx, id)
+		if syncErr != nil {
+			log.WithError(syncErr).WithField("subscription_id", id).Error("failed to sync unit calendar subscription")
+			result.Failed++
+			continue
+		}
+		if subscription.LastSyncStatus != "SUCCEEDED" {
+			result.Failed++
+			continue
+		}
+		result.Synced++
+	}
+
+	return result, nil
+}
+
+// syncSubscription brings the subscription's EXTERNAL blocks into line with
+// its feed and alerts the manager to any that now overlap a confirmed stay.
+// The feed is fetched before the transaction opens, so a slow channel holds
+// no locks; the subscription row is then locked so two syncs of one feed
+// cannot both create its new blocks.
+func (s *unitCalendarService) syncSubscription(
+	ctx context.Context,
+	subscriptionID string,
+) (*models.UnitCalendarSubscription, error) {
+	subscription, err := s.GetSubscription(ctx, repository.GetUnitCalendarSubscriptionQuery{
+		ID:       subscriptionID,
+		Populate: &[]string{"Unit", "CreatedByClientUser.User"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	subscription.LastSyncedAt = &now
+
+	events, fetchErr := s.fetchEvents(ctx, subscription.URL)
+	if fetchErr != nil {
+		subscription.LastSyncStatus = "FAILED"
+		subscription.LastSyncError = fetchErr.Error()
+		if updateErr := s.repo.Update(ctx, subscription); updateErr != nil {
+			return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+				Err: updateErr,
+				Metadata: map[string]string{
+					"function": "syncSubscription",
+					"action":   "recording failed sync",
+				},
+			})
+		}
+		return subscription, nil
+	}
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	plan, applyErr := s.applyCalendarSync(transCtx, subscription, events, now)
+	if applyErr == nil {
+		subscription.LastSyncStatus = "SUCCEEDED"
+		subscription.LastSyncError = ""
+		subscription.LastEventCount = plan.EventCount
+		applyErr = s.repo.Update(transCtx, subscription)
+	}
+	if applyErr == nil {
+		applyErr = transaction.Commit().Error
+	}
+	if applyErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(applyErr.Error(), &pkg.RentLoopErrorParams{
+			Err: applyErr,
+			Metadata: map[string]string{
+				"function": "syncSubscription",
+				"action":   "applying calendar sync",
+			},
+		})
+	}
+
+	changed := append(plan.Create, plan.Update...)
+	s.alertCalendarConflicts(ctx, subscription, changed)
+
+	return subscription, nil
+}
+
+func (s *unitCalendarService) fetchEvents(ctx context.Context, feedURL string) ([]ical.Event, error) {
+	body, err := s.fetcher.Fetch(ctx, feedURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return ical.Parse(bytes.NewReader(body))
+}
+
+func (s *unitCalendarService) applyCalendarSync(
+	transCtx context.Context,
+	subscription *models.UnitCalendarSubscription,
+	events []ical.Event,
+	now time.Time,
+) (calendarSyncPlan, error) {
+	if _, err := s.repo.GetByIDForUpdate(transCtx, subscription.ID.String()); err != nil {
+		return calendarSyncPlan{}, err
+	}
+
+	existing, err := s.unitDateBlockRepo.ListBySubscriptionID(transCtx, subscription.ID.String())
+	if err != nil {
+		return calendarSyncPlan{}, err
+	}
+
+	plan := planCalendarSync(subscription, existing, events, now)
+	for i := range plan.Create {
+		if err := s.unitDateBlockRepo.Create(transCtx, &plan.Create[i]); err != nil {
+			return plan, err
+		}
+	}
+	for i := range plan.Update {
+		if err := s.unitDateBlockRepo.Update(transCtx, &plan.Update[i]); err != nil {
+			return plan, err
+		}
+	}
+	for _, blockID := range plan.Delete {
+		if err := s.unitDateBlockRepo.Delete(transCtx, blockID); err != nil {
+			return plan, err
+		}
+	}
+
+	return plan, nil
+}
+
+// alertCalendarConflicts emails the manager who added the subscription about
+// blocks that landed on nights a confirmed or checked-in booking holds. Only
+// new and moved blocks are checked, so a conflict is reported once, and
+// nothing is undone: which stay gives way is the manager's call.
+func (s *unitCalendarService) alertCalendarConflicts(
+	ctx context.Context,
+	subscription *models.UnitCalendarSubscription,
+	blocks []models.UnitDateBlock,
+) {
+	var conflicts []emailtemplates.BookingCalendarConflict
+	for _, block := range blocks {
+		bookings, err := s.bookingRepo.ListStayingOverlapping(ctx, subscription.UnitID, block.StartDate, block.EndDate)
+		if err != nil {
+			log.WithError(err).WithField("subscription_id", subscription.ID.String()).
+				Error("failed to check imported calendar block for booking conflicts")
+			continue
+		}
+
+		for _, booking := range bookings {
+			conflicts = append(conflicts, emailtemplates.BookingCalendarConflict{
+				EventDates:   formatStayDates(block.StartDate, block.EndDate),
+				EventSummary: block.Reason,
+				BookingCode:  booking.Code,
+				BookingDates: formatStayDates(booking.CheckInDate, booking.CheckOutDate),
+			})
+		}
+	}
+	if len(conflicts) == 0 {
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"subscription_id": subscription.ID.String(),
+		"unit_id":         subscription.UnitID,
+		"conflicts":       len(conflicts),
+	}).Warn("imported calendar events overlap confirmed bookings")
+
+	manager := subscription.CreatedByClientUser
+	if manager.User.Email == "" {
+		return
+	}
+
+	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+		"booking/calendar-conflict-manager",
+		emailtemplates.BookingCalendarConflictData{
+			ManagerName:  manager.User.Name,
+			UnitName:     subscription.Unit.Name,
+			CalendarName: subscription.Name,
+			Conflicts:    conflicts,
+		},
+	)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render booking/calendar-conflict-manager email template")
+		return
+	}
+
+	go pkg.SendEmail(s.appCtx.Config, pkg.SendEmailInput{
+		Recipient: manager.User.Email,
+		Subject:   lib.PM_BOOKING_CALENDAR_CONFLICT_SUBJECT,
+		HtmlBody:  htmlBody,
+		TextBody:  textBody,
+	})
+}
+
+func formatStayDates(start, end time.Time) string {
+	return fmt.Sprintf("%s – %s", start.Format("2 Jan 2006"), end.Format("2 Jan 2006"))
+}
+
+// validateFeedURL accepts the web addresses channels hand out for their
+// calendars. file:// URLs are only accepted when feeds are read from a local
+// directory, in development.
+func (s *unitCalendarService) validateFeedURL(feedURL string) error {
+	parsed, err := url.Parse(feedURL)
+	if err != nil {
+		return pkg.BadRequestError("CalendarFeedURLInvalid", &pkg.RentLoopErrorParams{Err: err})
+	}
+
+	switch parsed.Scheme {
+	case "http", "https", "webcal":
+		if parsed.Host != "" {
+			return nil
+		}
+	case "file":
+		if s.appCtx.Config.Clients.CalendarFeed.LocalDir != "" {
+			return nil
+		}
+	}
+
+	return pkg.BadRequestError("CalendarFeedURLInvalid", nil)
+}
+
+func (s *unitCalendarService) EnableExport(ctx context.Context, query repository.GetUnitQuery) (*models.Unit, error) {
+	unit, err := s.unitService.GetUnit(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	token, tokenErr := newCalendarExportToken()
+	if tokenErr != nil {
+		return nil, pkg.InternalServerError(tokenErr.Error(), &pkg.RentLoopErrorParams{
+			Err: tokenErr,
+			Metadata: map[string]string{
+				"function": "EnableExport",
+				"action":   "generating calendar export token",
+			},
+		})
+	}
+
+	unit.CalendarExportToken = &token
+	if updateErr := s.unitRepo.Update(ctx, unit); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function": "EnableExport",
+				"action":   "saving calendar export token",
+			},
+		})
+	}
+
+	return unit, nil
+}
+
+func (s *unitCalendarService) DisableExport(ctx context.Context, query repository.GetUnitQuery) error {
+	unit, err := s.unitService.GetUnit(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	unit.CalendarExportToken = nil
+	if updateErr := s.unitRepo.Update(ctx, unit); updateErr != nil {
+		return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function": "DisableExport",
+				"action":   "clearing calendar export token",
+			},
+		})
+	}
+
+	return nil
+}
+
+func (s *unitCalendarService) RenderExport(ctx context.Context, token string) ([]byte, error) {
+	unit, err := s.unitRepo.GetOne(ctx, map[string]any{"calendar_export_token": token})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("UnitCalendarNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "RenderExport",
+				"action":   "fetching unit by calendar export token",
+			},
+		})
+	}
+
+	now := time.Now()
+	blocks, err := s.unitDateBlockRepo.ListByUnit(
+		ctx,
+		unit.ID.String(),
+		now.Add(-unitCalendarExportPast),
+		now.Add(unitCalendarExportFuture),
+	)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "RenderExport",
+				"action":   "listing unit date blocks",
+			},
+		})
+	}
+
+	var feed bytes.Buffer
+	if renderErr := ical.Render(&feed, unitCalendarFeed(unit, *blocks)); renderErr != nil {
+		return nil, pkg.InternalServerError(renderErr.Error(), &pkg.RentLoopErrorParams{
+			Err: renderErr,
+			Metadata: map[string]string{
+				"function": "RenderExport",
+				"action":   "rendering unit calendar",
+			},
+		})
+	}
+
+	return feed.Bytes(), nil
+}
+
+// newCalendarExportToken returns 24 random bytes, base64url-encoded: long
+// enough that a feed URL cannot be guessed, with nothing in it to escape.
+func newCalendarExportToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
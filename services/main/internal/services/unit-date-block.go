@@ -108,6 +108,12 @@ func (s *unitDateBlockService) DeleteBlock(ctx context.Context, id string, reque
 	if block.BlockType == "BOOKING" || block.BlockType == "LEASE" {
 		return errors.New("cannot delete system-managed blocks directly; cancel the booking or lease instead")
 	}
+	if block.BlockType == "TURNOVER" {
+		return errors.New("cannot delete turnover blocks directly; complete or cancel the turnover tasks instead")
+	}
+	if block.BlockType == "EXTERNAL" {
+		return errors.New("cannot delete imported blocks directly; they follow their calendar subscription")
+	}
 	// TODO: verify requestingClientUserID owns the block's property before deleting
 	return s.repo.Delete(ctx, id)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/Bendomey/rent-loop/services/main/internal/repository"
 	"github.com/Bendomey/rent-loop/services/main/pkg"
 	"github.com/lib/pq"
+	log "github.com/sirupsen/logrus"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
 )
@@ -17,6 +18,8 @@ import (
 type UnitService interface {
 	ListUnits(context context.Context, filterQuery repository.ListUnitsFilter) ([]models.Unit, error)
 	CountUnits(context context.Context, filterQuery repository.ListUnitsFilter) (int64, error)
+	ListUnitListings(context context.Context, filterQuery repository.ListUnitListingsFilter) ([]models.Unit, error)
+	CountUnitListings(context context.Context, filterQuery repository.ListUnitListingsFilter) (int64, error)
 	CreateUnit(context context.Context, input CreateUnitInput) (*models.Unit, error)
 	GetUnit(context context.Context, query repository.GetUnitQuery) (*models.Unit, error)
 	GetUnitBySlugQuery(context context.Context, query repository.GetUnitQuerySlug) (*models.Unit, error)
@@ -35,6 +38,7 @@ type unitService struct {
 	repo                 repository.UnitRepository
 	propertyRepo         repository.PropertyRepository
 	propertyBlockService PropertyBlockService
+	queue                RentloopQueue
 }
 
 type UnitServiceDependencies struct {
@@ -42,6 +46,7 @@ type UnitServiceDependencies struct {
 	Repo                 repository.UnitRepository
 	PropertyRepo         repository.PropertyRepository
 	PropertyBlockService PropertyBlockService
+	RentloopQueue        RentloopQueue
 }
 
 func NewUnitService(deps UnitServiceDependencies) UnitService {
@@ -50,6 +55,7 @@ func NewUnitService(deps UnitServiceDependencies) UnitService {
 		repo:                 deps.Repo,
 		propertyRepo:         deps.PropertyRepo,
 		propertyBlockService: deps.PropertyBlockService,
+		queue:                deps.RentloopQueue,
 	}
 }
 
@@ -69,6 +75,8 @@ type CreateUnitInput struct {
 	CreatedByID         string
 	Features            *map[string]any
 	MaxOccupantsAllowed int
+	BaseOccupancy       int
+	ExtraGuestFee       int64
 }
 
 func (s *unitService) resolveUnitCurrency(ctx context.Context, currency, propertyID string) string {
@@ -132,6 +140,8 @@ func (s *unitService) CreateUnit(ctx context.Context, input CreateUnitInput) (*m
 		CreatedById:         input.CreatedByID,
 		Features:            features,
 		MaxOccupantsAllowed: input.MaxOccupantsAllowed,
+		BaseOccupancy:       input.BaseOccupancy,
+		ExtraGuestFee:       input.ExtraGuestFee,
 	}
 
 	transaction := s.appCtx.DB.Begin()
@@ -268,6 +278,8 @@ type UpdateUnitInput struct {
 	PaymentFrequency    *string
 	Features            *map[string]any
 	MaxOccupantsAllowed *int
+	BaseOccupancy       *int
+	ExtraGuestFee       *int64
 	Status              *string
 	PropertyBlockID     *string
 }
@@ -344,6 +356,18 @@ func (s *unitService) UpdateUnit(ctx context.Context, input UpdateUnitInput) (*m
 		unit.MaxOccupantsAllowed = *input.MaxOccupantsAllowed
 	}
 
+	if input.BaseOccupancy != nil {
+		unit.BaseOccupancy = *input.BaseOccupancy
+	}
+
+	if input.ExtraGuestFee != nil {
+		unit.ExtraGuestFee = *input.ExtraGuestFee
+	}
+
+	if unit.BaseOccupancy > unit.MaxOccupantsAllowed {
+		return nil, pkg.BadRequestError("UnitBaseOccupancyAboveMaxOccupants", nil)
+	}
+
 	if input.Features != nil {
 		unmarshalledFeatures, err := json.Marshal(input.Features)
 		if err != nil {
@@ -454,6 +478,7 @@ func (s *unitService) UpdateUnitStatus(ctx context.Context, input UpdateUnitStat
 		return pkg.ForbiddenError("UnitIsOccupied", nil)
 	}
 
+	previousStatus := unit.Status
 	unit.Status = input.Status
 
 	updateUnitErr := s.repo.Update(ctx, unit)
@@ -466,6 +491,8 @@ func (s *unitService) UpdateUnitStatus(ctx context.Context, input UpdateUnitStat
 			},
 		})
 	}
+
+	s.inviteWaitlistIfReleased(ctx, unit, previousStatus)
 	return nil
 }
 
@@ -491,6 +518,7 @@ func (s *unitService) SetSystemUnitStatus(ctx context.Context, input UpdateUnitS
 		})
 	}
 
+	previousStatus := unit.Status
 	unit.Status = input.Status
 
 	updateUnitErr := s.repo.Update(ctx, unit)
@@ -503,9 +531,34 @@ func (s *unitService) SetSystemUnitStatus(ctx context.Context, input UpdateUnitS
 			},
 		})
 	}
+
+	s.inviteWaitlistIfReleased(ctx, unit, previousStatus)
 	return nil
 }
 
+// unitReleased reports whether a unit moving from previousStatus to status
+// has just opened up to a new tenant: it is taking tenants now, and has more
+// room than before.
+func unitReleased(previousStatus, status string) bool {
+	if status != "Unit.Status.Available" && status != "Unit.Status.PartiallyOccupied" {
+		return false
+	}
+	return status != previousStatus && previousStatus != "Unit.Status.Available"
+}
+
+// inviteWaitlistIfReleased queues the unit's waitlist to be invited when a
+// status change opened it up. The status is saved by then, so a failure is
+// logged rather than returned.
+func (s *unitService) inviteWaitlistIfReleased(ctx context.Context, unit *models.Unit, previousStatus string) {
+	if !unitReleased(previousStatus, unit.Status) {
+		return
+	}
+
+	if err := s.queue.EnqueueWaitlistUnitReleased(ctx, unit.ID.String()); err != nil {
+		log.WithError(err).WithField("unit_id", unit.ID.String()).Error("failed to enqueue waitlist invite")
+	}
+}
+
 func (s *unitService) DeleteUnit(ctx context.Context, input repository.DeleteUnitInput) error {
 	unit, getUnitErr := s.repo.GetOne(ctx, map[string]any{
 		"id":          input.UnitID,
@@ -607,6 +660,46 @@ func (s *unitService) CountUnits(
 	return unitCount, nil
 }
 
+// ListUnitListings searches the units opted-in clients list publicly, with
+// each unit's property and client loaded for display.
+func (s *unitService) ListUnitListings(
+	ctx context.Context,
+	filterQuery repository.ListUnitListingsFilter,
+) ([]models.Unit, error) {
+	filterQuery.Populate = &[]string{"Property", "Property.Client"}
+
+	units, listErr := s.repo.ListListings(ctx, filterQuery)
+	if listErr != nil {
+		return nil, pkg.InternalServerError(listErr.Error(), &pkg.RentLoopErrorParams{
+			Err: listErr,
+			Metadata: map[string]string{
+				"function": "ListUnitListings",
+				"action":   "listing unit listings",
+			},
+		})
+	}
+
+	return *units, nil
+}
+
+func (s *unitService) CountUnitListings(
+	ctx context.Context,
+	filterQuery repository.ListUnitListingsFilter,
+) (int64, error) {
+	count, countErr := s.repo.CountListings(ctx, filterQuery)
+	if countErr != nil {
+		return 0, pkg.InternalServerError(countErr.Error(), &pkg.RentLoopErrorParams{
+			Err: countErr,
+			Metadata: map[string]string{
+				"function": "CountUnitListings",
+				"action":   "counting unit listings",
+			},
+		})
+	}
+
+	return count, nil
+}
+
 type UpdateUnitCountInput struct {
 	PropertyID      string
 	PropertyBlockID string
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	"gorm.io/gorm"
+)
+
+// viewingSlotMaxLength keeps a slot to a working day; a longer one is almost
+// always a mistyped date.
+const viewingSlotMaxLength = 12 * time.Hour
+
+type ViewingSlotService interface {
+	CreateViewingSlot(ctx context.Context, input CreateViewingSlotInput) (*models.ViewingSlot, error)
+	GetViewingSlot(ctx context.Context, query repository.GetViewingSlotQuery) (*models.ViewingSlot, error)
+	ListViewingSlots(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListViewingSlotsFilter,
+	) ([]models.ViewingSlot, error)
+	CountViewingSlots(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListViewingSlotsFilter,
+	) (int64, error)
+	CancelViewingSlot(ctx context.Context, input CancelViewingSlotInput) (*models.ViewingSlot, error)
+}
+
+type viewingSlotService struct {
+	appCtx                 pkg.AppContext
+	repo                   repository.ViewingSlotRepository
+	viewingRepo            repository.ViewingRepository
+	clientUserPropertyRepo repository.ClientUserPropertyRepository
+}
+
+type ViewingSlotServiceDeps struct {
+	AppCtx                 pkg.AppContext
+	Repo                   repository.ViewingSlotRepository
+	ViewingRepo            repository.ViewingRepository
+	ClientUserPropertyRepo repository.ClientUserPropertyRepository
+}
+
+func NewViewingSlotService(deps ViewingSlotServiceDeps) ViewingSlotService {
+	return &viewingSlotService{
+		appCtx:                 deps.AppCtx,
+		repo:                   deps.Repo,
+		viewingRepo:            deps.ViewingRepo,
+		clientUserPropertyRepo: deps.ClientUserPropertyRepo,
+	}
+}
+
+type CreateViewingSlotInput struct {
+	PropertyID   string
+	ClientUserID string
+	StartsAt     time.Time
+	EndsAt       time.Time
+	Capacity     int64
+	CreatedByID  string
+}
+
+// validateViewingSlotWindow checks a new slot is still ahead of now, ends
+// after it starts, and fits in a working day.
+func validateViewingSlotWindow(startsAt, endsAt, now time.Time) error {
+	if !startsAt.After(now) {
+		return pkg.BadRequestError("ViewingSlotInPast", nil)
+	}
+	if !endsAt.After(startsAt) {
+		return pkg.BadRequestError("InvalidViewingSlotWindow", nil)
+	}
+	if endsAt.Sub(startsAt) > viewingSlotMaxLength {
+		return pkg.BadRequestError("ViewingSlotTooLong", nil)
+	}
+
+	return nil
+}
+
+// CreateViewingSlot opens a window for a member of the property's team to
+// show units in. A staff member's open slots may not overlap.
+func (s *viewingSlotService) CreateViewingSlot(
+	ctx context.Context,
+	input CreateViewingSlotInput,
+) (*models.ViewingSlot, error) {
+	if err := validateViewingSlotWindow(input.StartsAt, input.EndsAt, time.Now()); err != nil {
+		return nil, err
+	}
+
+	linked, err := s.clientUserPropertyRepo.Count(ctx, repository.ListClientUserPropertiesFilter{
+		ClientUserID: &input.ClientUserID,
+		PropertyID:   &input.PropertyID,
+	})
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CreateViewingSlot",
+				"action":   "checking staff property access",
+			},
+		})
+	}
+	if linked == 0 {
+		return nil, pkg.BadRequestError("StaffNotOnProperty", nil)
+	}
+
+	overlapping, err := s.repo.CountOverlapping(ctx, input.ClientUserID, input.StartsAt, input.EndsAt)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CreateViewingSlot",
+				"action":   "checking overlapping slots",
+			},
+		})
+	}
+	if overlapping > 0 {
+		return nil, pkg.ConflictError("ViewingSlotOverlaps", nil)
+	}
+
+	slot := models.ViewingSlot{
+		PropertyID:   input.PropertyID,
+		ClientUserID: input.ClientUserID,
+		StartsAt:     input.StartsAt,
+		EndsAt:       input.EndsAt,
+		Capacity:     input.Capacity,
+		Status:       "OPEN",
+		CreatedByID:  input.CreatedByID,
+	}
+
+	if err := s.repo.Create(ctx, &slot); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CreateViewingSlot",
+				"action":   "creating viewing slot",
+			},
+		})
+	}
+
+	return &slot, nil
+}
+
+func (s *viewingSlotService) GetViewingSlot(
+	ctx context.Context,
+	query repository.GetViewingSlotQuery,
+) (*models.ViewingSlot, error) {
+	slot, err := s.repo.GetByIDWithPopulate(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("ViewingSlotNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "GetViewingSlot",
+				"action":   "fetching viewing slot",
+			},
+		})
+	}
+
+	return slot, nil
+}
+
+func (s *viewingSlotService) ListViewingSlots(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListViewingSlotsFilter,
+) ([]models.ViewingSlot, error) {
+	slots, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "ListViewingSlots",
+				"action":   "listing viewing slots",
+			},
+		})
+	}
+
+	return slots, nil
+}
+
+func (s *viewingSlotService) CountViewingSlots(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListViewingSlotsFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filterQuery, filters)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CountViewingSlots",
+				"action":   "counting viewing slots",
+			},
+		})
+	}
+
+	return count, nil
+}
+
+type CancelViewingSlotInput struct {
+	ID           string
+	PropertyID   string
+	ClientUserID string
+}
+
+// CancelViewingSlot withdraws an open slot. Viewings already booked into it
+// have to be cancelled first, so no prospect is dropped without being told.
+func (s *viewingSlotService) CancelViewingSlot(
+	ctx context.Context,
+	input CancelViewingSlotInput,
+) (*models.ViewingSlot, error) {
+	slot, err := s.GetViewingSlot(ctx, repository.GetViewingSlotQuery{ID: input.ID, PropertyID: &input.PropertyID})
+	if err != nil {
+		return nil, err
+	}
+
+	if slot.Status != "OPEN" {
+		return nil, pkg.BadRequestError("ViewingSlotNotOpen", nil)
+	}
+
+	booked, err := s.viewingRepo.CountScheduledForSlot(ctx, slot.ID.String())
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CancelViewingSlot",
+				"action":   "counting booked viewings",
+			},
+		})
+	}
+	if booked > 0 {
+		return nil, pkg.BadRequestError("ViewingSlotHasViewings", nil)
+	}
+
+	now := time.Now()
+	slot.Status = "CANCELLED"
+	slot.CanceledAt = &now
+	slot.CanceledByID = &input.ClientUserID
+
+	if err := s.repo.Update(ctx, slot); err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err: err,
+			Metadata: map[string]string{
+				"function": "CancelViewingSlot",
+				"action":   "updating viewing slot",
+			},
+		})
+	}
+
+	return slot, nil
+}
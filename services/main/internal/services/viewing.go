@@ -0,0 +1,849 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// viewingNoShowLimit is how many viewings at a property a phone number may
+// miss before it can no longer book there online.
+const viewingNoShowLimit = 2
+
+type ViewingService interface {
+	// ListBookableSlots returns the slots a prospect can still book a viewing
+	// of the unit in, starting between from and to.
+	ListBookableSlots(ctx context.Context, unitSlug string, from, to time.Time) ([]models.ViewingSlot, error)
+	BookViewing(ctx context.Context, input BookViewingInput) (*models.Viewing, error)
+	// GetViewingByCode returns a prospect's viewing once phone matches the
+	// one it was booked with.
+	GetViewingByCode(ctx context.Context, code, phone string) (*models.Viewing, error)
+	CancelViewingByCode(ctx context.Context, code, phone string) (*models.Viewing, error)
+
+	GetViewing(ctx context.Context, query repository.GetViewingQuery) (*models.Viewing, error)
+	ListViewings(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListViewingsFilter,
+	) ([]models.Viewing, error)
+	CountViewings(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListViewingsFilter,
+	) (int64, error)
+	CompleteViewing(ctx context.Context, input ViewingOutcomeInput) (*models.Viewing, error)
+	MarkViewingNoShow(ctx context.Context, input ViewingOutcomeInput) (*models.Viewing, error)
+	CancelViewing(ctx context.Context, input CancelViewingInput) (*models.Viewing, error)
+	ConvertViewingToApplication(ctx context.Context, input ConvertViewingToApplicationInput) (*models.Viewing, error)
+
+	// SendViewingConfirmation and RemindViewing run from the queue after a
+	// viewing is booked.
+	SendViewingConfirmation(ctx context.Context, viewingID string) error
+	RemindViewing(ctx context.Context, viewingID string, scheduledAt time.Time) error
+}
+
+type viewingService struct {
+	appCtx                pkg.AppContext
+	repo                  repository.ViewingRepository
+	slotRepo              repository.ViewingSlotRepository
+	unitRepo              repository.UnitRepository
+	propertyRepo          repository.PropertyRepository
+	tenantApplicationRepo repository.TenantApplicationRepository
+	queue                 RentloopQueue
+}
+
+type ViewingServiceDeps struct {
+	AppCtx                pkg.AppContext
+	Repo                  repository.ViewingRepository
+	SlotRepo              repository.ViewingSlotRepository
+	UnitRepo              repository.UnitRepository
+	PropertyRepo          repository.PropertyRepository
+	TenantApplicationRepo repository.TenantApplicationRepository
+	RentloopQueue         RentloopQueue
+}
+
+func NewViewingService(deps ViewingServiceDeps) ViewingService {
+	return &viewingService{
+		appCtx:                deps.AppCtx,
+		repo:                  deps.Repo,
+		slotRepo:              deps.SlotRepo,
+		unitRepo:              deps.UnitRepo,
+		propertyRepo:          deps.PropertyRepo,
+		tenantApplicationRepo: deps.TenantApplicationRepo,
+		queue:                 deps.RentloopQueue,
+	}
+}
+
+// getViewingUnit loads a unit with its property, checking both are open to
+// prospects.
+func (s *viewingService) getViewingUnit(
+	ctx context.Context,
+	query map[string]any,
+	function string,
+) (*models.Unit, error) {
+	unit, err := s.unitRepo.GetOne(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("UnitNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": function, "action": "fetching unit"},
+		})
+	}
+	if unit.Status != "Unit.Status.Available" && unit.Status != "Unit.Status.PartiallyOccupied" {
+		return nil, pkg.BadRequestError("UnitNotAvailable", nil)
+	}
+
+	property, err := s.propertyRepo.GetByID(ctx, repository.GetPropertyQuery{ID: unit.PropertyID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("PropertyNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": function, "action": "fetching property"},
+		})
+	}
+	if property.Status != "Property.Status.Active" {
+		return nil, pkg.BadRequestError("PropertyNotActive", nil)
+	}
+	unit.Property = *property
+
+	return unit, nil
+}
+
+func (s *viewingService) ListBookableSlots(
+	ctx context.Context,
+	unitSlug string,
+	from, to time.Time,
+) ([]models.ViewingSlot, error) {
+	unit, err := s.getViewingUnit(ctx, map[string]any{"slug": unitSlug}, "ListBookableSlots")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	slots, err := s.slotRepo.List(
+		ctx,
+		lib.FilterQuery{Page: 1, PageSize: 100},
+		repository.ListViewingSlotsFilter{
+			PropertyID: &unit.PropertyID,
+			StartsFrom: &from,
+			StartsTo:   &to,
+			BookableAt: &now,
+		},
+	)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "ListBookableSlots", "action": "listing slots"},
+		})
+	}
+
+	return slots, nil
+}
+
+type BookViewingInput struct {
+	UnitSlug      string
+	ViewingSlotID string
+	FirstName     string
+	LastName      string
+	Email         *string
+	Phone         string
+	Message       *string
+}
+
+// viewingSlotBookable checks a prospect can still take a place in slot, given
+// the places already taken.
+func viewingSlotBookable(slot *models.ViewingSlot, taken int64, now time.Time) error {
+	if slot.Status != "OPEN" {
+		return pkg.BadRequestError("ViewingSlotNotOpen", nil)
+	}
+	if !slot.StartsAt.After(now) {
+		return pkg.BadRequestError("ViewingSlotStarted", nil)
+	}
+	if taken >= slot.Capacity {
+		return pkg.ConflictError("ViewingSlotFull", nil)
+	}
+
+	return nil
+}
+
+// viewingReminderAt picks when to remind a prospect of a viewing: a day
+// before, or two hours before when it was booked at shorter notice. A zero
+// time means the viewing is too close to bother.
+func viewingReminderAt(now, scheduledAt time.Time) time.Time {
+	for _, lead := range []time.Duration{24 * time.Hour, 2 * time.Hour} {
+		if remindAt := scheduledAt.Add(-lead); remindAt.After(now) {
+			return remindAt
+		}
+	}
+
+	return time.Time{}
+}
+
+// BookViewing books a prospect into a slot at the unit's property. The slot
+// is locked while its places are counted so two prospects cannot both take
+// the last one. A phone number that has missed too many viewings at the
+// property is turned away.
+func (s *viewingService) BookViewing(ctx context.Context, input BookViewingInput) (*models.Viewing, error) {
+	unit, err := s.getViewingUnit(ctx, map[string]any{"slug": input.UnitSlug}, "BookViewing")
+	if err != nil {
+		return nil, err
+	}
+
+	noShows, err := s.repo.CountNoShowsByPhone(ctx, unit.PropertyID, input.Phone)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "BookViewing", "action": "counting no-shows"},
+		})
+	}
+	if noShows >= viewingNoShowLimit {
+		return nil, pkg.ForbiddenError("TooManyMissedViewings", nil)
+	}
+
+	booked, err := s.repo.CountScheduledForUnitByPhone(ctx, unit.ID.String(), input.Phone)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "BookViewing", "action": "counting booked viewings"},
+		})
+	}
+	if booked > 0 {
+		return nil, pkg.ConflictError("ViewingAlreadyBooked", nil)
+	}
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	slot, err := s.slotRepo.GetForUpdate(transCtx, input.ViewingSlotID)
+	if err != nil {
+		transaction.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("ViewingSlotNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "BookViewing", "action": "locking slot"},
+		})
+	}
+	if slot.PropertyID != unit.PropertyID {
+		transaction.Rollback()
+		return nil, pkg.NotFoundError("ViewingSlotNotFound", nil)
+	}
+
+	taken, err := s.repo.CountScheduledForSlot(transCtx, slot.ID.String())
+	if err != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "BookViewing", "action": "counting places taken"},
+		})
+	}
+
+	now := time.Now()
+	if bookableErr := viewingSlotBookable(slot, taken, now); bookableErr != nil {
+		transaction.Rollback()
+		return nil, bookableErr
+	}
+
+	viewing := models.Viewing{
+		PropertyID:    unit.PropertyID,
+		UnitID:        unit.ID.String(),
+		ViewingSlotID: slot.ID.String(),
+		ClientUserID:  slot.ClientUserID,
+		ScheduledAt:   slot.StartsAt,
+		EndsAt:        slot.EndsAt,
+		FirstName:     input.FirstName,
+		LastName:      input.LastName,
+		Email:         input.Email,
+		Phone:         input.Phone,
+		Status:        "SCHEDULED",
+	}
+	if input.Message != nil {
+		viewing.Message = *input.Message
+	}
+
+	if createErr := s.repo.Create(transCtx, &viewing); createErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      createErr,
+			Metadata: map[string]string{"function": "BookViewing", "action": "creating viewing"},
+		})
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      commitErr,
+			Metadata: map[string]string{"function": "BookViewing", "action": "committing transaction"},
+		})
+	}
+
+	if enqueueErr := s.queue.EnqueueViewingConfirmation(ctx, viewing.ID.String()); enqueueErr != nil {
+		log.WithError(enqueueErr).WithField("viewing_id", viewing.ID.String()).
+			Error("failed to enqueue viewing confirmation")
+	}
+	if remindAt := viewingReminderAt(now, viewing.ScheduledAt); !remindAt.IsZero() {
+		if enqueueErr := s.queue.ScheduleViewingReminder(
+			ctx,
+			viewing.ID.String(),
+			remindAt,
+			viewing.ScheduledAt,
+		); enqueueErr != nil {
+			log.WithError(enqueueErr).WithField("viewing_id", viewing.ID.String()).
+				Error("failed to schedule viewing reminder")
+		}
+	}
+
+	viewing.Unit = *unit
+	viewing.Property = unit.Property
+	return &viewing, nil
+}
+
+func (s *viewingService) GetViewingByCode(ctx context.Context, code, phone string) (*models.Viewing, error) {
+	viewing, err := s.repo.GetByCode(ctx, code, &[]string{"Unit", "Property"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("ViewingNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GetViewingByCode", "action": "fetching viewing"},
+		})
+	}
+
+	if viewing.Phone != phone {
+		return nil, pkg.ForbiddenError("PhoneDoesNotMatchViewing", nil)
+	}
+
+	return viewing, nil
+}
+
+// CancelViewingByCode lets a prospect call off a viewing that has not
+// started yet, freeing their place in the slot.
+func (s *viewingService) CancelViewingByCode(ctx context.Context, code, phone string) (*models.Viewing, error) {
+	viewing, err := s.GetViewingByCode(ctx, code, phone)
+	if err != nil {
+		return nil, err
+	}
+
+	if viewing.Status != "SCHEDULED" {
+		return nil, pkg.BadRequestError("ViewingNotScheduled", nil)
+	}
+	if !viewing.ScheduledAt.After(time.Now()) {
+		return nil, pkg.BadRequestError("ViewingAlreadyStarted", nil)
+	}
+
+	now := time.Now()
+	viewing.Status = "CANCELLED"
+	viewing.CanceledAt = &now
+
+	if updateErr := s.repo.Update(ctx, viewing); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "CancelViewingByCode", "action": "cancelling viewing"},
+		})
+	}
+
+	return viewing, nil
+}
+
+func (s *viewingService) GetViewing(ctx context.Context, query repository.GetViewingQuery) (*models.Viewing, error) {
+	viewing, err := s.repo.GetByIDWithPopulate(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("ViewingNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GetViewing", "action": "fetching viewing"},
+		})
+	}
+
+	return viewing, nil
+}
+
+func (s *viewingService) ListViewings(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListViewingsFilter,
+) ([]models.Viewing, error) {
+	viewings, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "ListViewings", "action": "listing viewings"},
+		})
+	}
+
+	return viewings, nil
+}
+
+func (s *viewingService) CountViewings(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListViewingsFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filterQuery, filters)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "CountViewings", "action": "counting viewings"},
+		})
+	}
+
+	return count, nil
+}
+
+type ViewingOutcomeInput struct {
+	ID           string
+	PropertyID   string
+	ClientUserID string
+	Notes        *string
+}
+
+// getStartedViewing loads a SCHEDULED viewing whose time has come, ready for
+// staff to record how it went.
+func (s *viewingService) getStartedViewing(ctx context.Context, input ViewingOutcomeInput) (*models.Viewing, error) {
+	viewing, err := s.GetViewing(ctx, repository.GetViewingQuery{ID: input.ID, PropertyID: &input.PropertyID})
+	if err != nil {
+		return nil, err
+	}
+
+	if viewing.Status != "SCHEDULED" {
+		return nil, pkg.BadRequestError("ViewingNotScheduled", nil)
+	}
+	if viewing.ScheduledAt.After(time.Now()) {
+		return nil, pkg.BadRequestError("ViewingNotStarted", nil)
+	}
+	if input.Notes != nil {
+		viewing.Notes = *input.Notes
+	}
+
+	return viewing, nil
+}
+
+func (s *viewingService) CompleteViewing(ctx context.Context, input ViewingOutcomeInput) (*models.Viewing, error) {
+	viewing, err := s.getStartedViewing(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	viewing.Status = "COMPLETED"
+	viewing.CompletedAt = &now
+	viewing.CompletedByID = &input.ClientUserID
+
+	if updateErr := s.repo.Update(ctx, viewing); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "CompleteViewing", "action": "completing viewing"},
+		})
+	}
+
+	return viewing, nil
+}
+
+// MarkViewingNoShow records that the prospect did not turn up. Enough of
+// these at a property stop the phone number booking there again.
+func (s *viewingService) MarkViewingNoShow(ctx context.Context, input ViewingOutcomeInput) (*models.Viewing, error) {
+	viewing, err := s.getStartedViewing(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	viewing.Status = "NO_SHOW"
+	viewing.NoShowAt = &now
+	viewing.NoShowMarkedByID = &input.ClientUserID
+
+	if updateErr := s.repo.Update(ctx, viewing); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "MarkViewingNoShow", "action": "marking no-show"},
+		})
+	}
+
+	return viewing, nil
+}
+
+type CancelViewingInput struct {
+	ID           string
+	PropertyID   string
+	ClientUserID string
+	Reason       string
+}
+
+// CancelViewing calls off a scheduled viewing on the property's side and
+// tells the prospect why.
+func (s *viewingService) CancelViewing(ctx context.Context, input CancelViewingInput) (*models.Viewing, error) {
+	viewing, err := s.GetViewing(ctx, repository.GetViewingQuery{
+		ID:         input.ID,
+		PropertyID: &input.PropertyID,
+		Populate:   &[]string{"Unit", "Property"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if viewing.Status != "SCHEDULED" {
+		return nil, pkg.BadRequestError("ViewingNotScheduled", nil)
+	}
+
+	now := time.Now()
+	viewing.Status = "CANCELLED"
+	viewing.CanceledAt = &now
+	viewing.CanceledByID = &input.ClientUserID
+	viewing.CancellationReason = input.Reason
+
+	if updateErr := s.repo.Update(ctx, viewing); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "CancelViewing", "action": "cancelling viewing"},
+		})
+	}
+
+	s.sendViewingCancelledNotification(*viewing)
+
+	return viewing, nil
+}
+
+type ConvertViewingToApplicationInput struct {
+	ID           string
+	PropertyID   string
+	ClientUserID string
+}
+
+// ConvertViewingToApplication starts a tenant application for the unit from
+// a completed viewing, prefilled with the prospect's details, and sends them
+// its code to finish it.
+func (s *viewingService) ConvertViewingToApplication(
+	ctx context.Context,
+	input ConvertViewingToApplicationInput,
+) (*models.Viewing, error) {
+	viewing, err := s.GetViewing(ctx, repository.GetViewingQuery{ID: input.ID, PropertyID: &input.PropertyID})
+	if err != nil {
+		return nil, err
+	}
+
+	if viewing.Status != "COMPLETED" {
+		return nil, pkg.BadRequestError("ViewingNotCompleted", nil)
+	}
+	if viewing.TenantApplicationID != nil {
+		return nil, pkg.ConflictError("ViewingAlreadyConverted", nil)
+	}
+
+	unit, err := s.getViewingUnit(ctx, map[string]any{"id": viewing.UnitID}, "ConvertViewingToApplication")
+	if err != nil {
+		return nil, err
+	}
+
+	source := "VIEWING"
+	application := models.TenantApplication{
+		PropertyId:            &unit.PropertyID,
+		DesiredUnitId:         &viewing.UnitID,
+		RentFeeCurrency:       &unit.RentFeeCurrency,
+		StayDurationFrequency: &unit.PaymentFrequency,
+		PaymentFrequency:      &unit.PaymentFrequency,
+		Source:                &source,
+		FirstName:             &viewing.FirstName,
+		LastName:              &viewing.LastName,
+		Email:                 viewing.Email,
+		Phone:                 viewing.Phone,
+		CreatedById:           input.ClientUserID,
+		Status:                "TenantApplication.Status.InProgress",
+	}
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	if createErr := s.tenantApplicationRepo.Create(transCtx, &application); createErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err: createErr,
+			Metadata: map[string]string{
+				"function": "ConvertViewingToApplication",
+				"action":   "creating tenant application",
+			},
+		})
+	}
+
+	applicationID := application.ID.String()
+	viewing.TenantApplicationID = &applicationID
+
+	if updateErr := s.repo.Update(transCtx, viewing); updateErr != nil {
+		transaction.Rollback()
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err: updateErr,
+			Metadata: map[string]string{
+				"function": "ConvertViewingToApplication",
+				"action":   "linking tenant application",
+			},
+		})
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return nil, pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err: commitErr,
+			Metadata: map[string]string{
+				"function": "ConvertViewingToApplication",
+				"action":   "committing transaction",
+			},
+		})
+	}
+
+	s.sendViewingConvertedNotification(*viewing, application.Code)
+
+	viewing.TenantApplication = &application
+	return viewing, nil
+}
+
+// getViewingForMessage loads a viewing with what its messages mention.
+func (s *viewingService) getViewingForMessage(ctx context.Context, viewingID string) (*models.Viewing, error) {
+	return s.GetViewing(ctx, repository.GetViewingQuery{
+		ID:       viewingID,
+		Populate: &[]string{"Unit", "Property", "ClientUser.User"},
+	})
+}
+
+// SendViewingConfirmation tells the prospect their viewing is booked, and the
+// staff member running the slot who is coming.
+func (s *viewingService) SendViewingConfirmation(ctx context.Context, viewingID string) error {
+	viewing, err := s.getViewingForMessage(ctx, viewingID)
+	if err != nil {
+		return err
+	}
+	if viewing.Status != "SCHEDULED" {
+		return nil
+	}
+
+	scheduledAt := viewing.ScheduledAt.Format("January 2, 2006 3:04pm")
+
+	if viewing.Email != nil {
+		htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+			"viewing/booked",
+			emailtemplates.ViewingBookedData{
+				FirstName:       viewing.FirstName,
+				UnitName:        viewing.Unit.Name,
+				PropertyName:    viewing.Property.Name,
+				PropertyAddress: viewing.Property.Address,
+				ScheduledAt:     scheduledAt,
+				ViewingCode:     viewing.Code,
+			},
+		)
+		if renderErr != nil {
+			log.WithError(renderErr).Error("failed to render viewing/booked email template")
+		} else {
+			go pkg.SendEmail(
+				s.appCtx.Config,
+				pkg.SendEmailInput{
+					Recipient: *viewing.Email,
+					Subject:   lib.VIEWING_BOOKED_SUBJECT,
+					HtmlBody:  htmlBody,
+					TextBody:  textBody,
+				},
+			)
+		}
+	}
+
+	smsBody := strings.NewReplacer(
+		"{{first_name}}", viewing.FirstName,
+		"{{unit_name}}", viewing.Unit.Name,
+		"{{property_name}}", viewing.Property.Name,
+		"{{scheduled_at}}", scheduledAt,
+		"{{property_address}}", viewing.Property.Address,
+		"{{viewing_code}}", viewing.Code,
+	).Replace(lib.VIEWING_BOOKED_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: viewing.Phone,
+			Message:   smsBody,
+		},
+	)
+
+	staffHTML, staffText, renderErr := s.appCtx.EmailEngine.Render(
+		"viewing/staff-booked",
+		emailtemplates.ViewingStaffBookedData{
+			StaffName:     viewing.ClientUser.User.Name,
+			ProspectName:  viewing.FirstName + " " + viewing.LastName,
+			ProspectPhone: viewing.Phone,
+			UnitName:      viewing.Unit.Name,
+			PropertyName:  viewing.Property.Name,
+			ScheduledAt:   scheduledAt,
+		},
+	)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render viewing/staff-booked email template")
+	} else {
+		go pkg.SendEmail(
+			s.appCtx.Config,
+			pkg.SendEmailInput{
+				Recipient: viewing.ClientUser.User.Email,
+				Subject:   lib.VIEWING_STAFF_BOOKED_SUBJECT,
+				HtmlBody:  staffHTML,
+				TextBody:  staffText,
+			},
+		)
+	}
+
+	return nil
+}
+
+// RemindViewing reminds the prospect of a viewing still going ahead at the
+// time the reminder was scheduled for.
+func (s *viewingService) RemindViewing(ctx context.Context, viewingID string, scheduledAt time.Time) error {
+	viewing, err := s.getViewingForMessage(ctx, viewingID)
+	if err != nil {
+		return err
+	}
+	if viewing.Status != "SCHEDULED" || !viewing.ScheduledAt.Equal(scheduledAt) {
+		return nil
+	}
+
+	when := viewing.ScheduledAt.Format("January 2, 2006 3:04pm")
+
+	if viewing.Email != nil {
+		htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+			"viewing/reminder",
+			emailtemplates.ViewingReminderData{
+				FirstName:       viewing.FirstName,
+				UnitName:        viewing.Unit.Name,
+				PropertyName:    viewing.Property.Name,
+				PropertyAddress: viewing.Property.Address,
+				ScheduledAt:     when,
+				ViewingCode:     viewing.Code,
+			},
+		)
+		if renderErr != nil {
+			log.WithError(renderErr).Error("failed to render viewing/reminder email template")
+		} else {
+			go pkg.SendEmail(
+				s.appCtx.Config,
+				pkg.SendEmailInput{
+					Recipient: *viewing.Email,
+					Subject:   lib.VIEWING_REMINDER_SUBJECT,
+					HtmlBody:  htmlBody,
+					TextBody:  textBody,
+				},
+			)
+		}
+	}
+
+	smsBody := strings.NewReplacer(
+		"{{first_name}}", viewing.FirstName,
+		"{{unit_name}}", viewing.Unit.Name,
+		"{{property_name}}", viewing.Property.Name,
+		"{{scheduled_at}}", when,
+		"{{property_address}}", viewing.Property.Address,
+		"{{viewing_code}}", viewing.Code,
+	).Replace(lib.VIEWING_REMINDER_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: viewing.Phone,
+			Message:   smsBody,
+		},
+	)
+
+	return nil
+}
+
+func (s *viewingService) sendViewingCancelledNotification(viewing models.Viewing) {
+	scheduledAt := viewing.ScheduledAt.Format("January 2, 2006 3:04pm")
+
+	if viewing.Email != nil {
+		htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+			"viewing/cancelled",
+			emailtemplates.ViewingCancelledData{
+				FirstName:    viewing.FirstName,
+				UnitName:     viewing.Unit.Name,
+				PropertyName: viewing.Property.Name,
+				ScheduledAt:  scheduledAt,
+				Reason:       viewing.CancellationReason,
+			},
+		)
+		if renderErr != nil {
+			log.WithError(renderErr).Error("failed to render viewing/cancelled email template")
+		} else {
+			go pkg.SendEmail(
+				s.appCtx.Config,
+				pkg.SendEmailInput{
+					Recipient: *viewing.Email,
+					Subject:   lib.VIEWING_CANCELLED_SUBJECT,
+					HtmlBody:  htmlBody,
+					TextBody:  textBody,
+				},
+			)
+		}
+	}
+
+	smsBody := strings.NewReplacer(
+		"{{first_name}}", viewing.FirstName,
+		"{{unit_name}}", viewing.Unit.Name,
+		"{{property_name}}", viewing.Property.Name,
+		"{{scheduled_at}}", scheduledAt,
+		"{{reason}}", viewing.CancellationReason,
+	).Replace(lib.VIEWING_CANCELLED_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: viewing.Phone,
+			Message:   smsBody,
+		},
+	)
+}
+
+// sendViewingConvertedNotification sends the prospect the code of the
+// application started for them, as for applications staff create in bulk.
+func (s *viewingService) sendViewingConvertedNotification(viewing models.Viewing, applicationCode string) {
+	smsBody := strings.NewReplacer("{{application_code}}", applicationCode).Replace(lib.TENANT_CSV_CREATED_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: viewing.Phone,
+			Message:   smsBody,
+		},
+	)
+
+	if viewing.Email == nil {
+		return
+	}
+
+	htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+		"tenant-application/csv-created",
+		emailtemplates.TenantCSVCreatedData{
+			ApplicationCode: applicationCode,
+		},
+	)
+	if renderErr != nil {
+		log.WithError(renderErr).Error("failed to render tenant-application/csv-created email template")
+		return
+	}
+
+	go pkg.SendEmail(
+		s.appCtx.Config,
+		pkg.SendEmailInput{
+			Recipient: *viewing.Email,
+			Subject:   lib.TENANT_CSV_CREATED_SUBJECT,
+			HtmlBody:  htmlBody,
+			TextBody:  textBody,
+		},
+	)
+}
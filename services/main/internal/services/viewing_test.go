@@ -0,0 +1,108 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+// wantRentLoopError fails the test unless err carries the want message, or is
+// nil when want is empty.
+func wantRentLoopError(t *testing.T, err error, want string) {
+	t.Helper()
+
+	var rentLoopErr *pkg.IRentLoopError
+	switch {
+	case want == "" && err != nil:
+		t.Errorf("got %v, want no error", err)
+	case want != "" && (!errors.As(err, &rentLoopErr) || rentLoopErr.Message != want):
+		t.Errorf("got %v, want %s", err, want)
+	}
+}
+
+// A slot is opened ahead of time, ends after it starts, and is no longer
+// than a working day.
+func TestValidateViewingSlotWindow(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	tomorrow := now.Add(24 * time.Hour)
+
+	cases := []struct {
+		name     string
+		startsAt time.Time
+		endsAt   time.Time
+		wantErr  string
+	}{
+		{"an hour tomorrow", tomorrow, tomorrow.Add(time.Hour), ""},
+		{"a full working day", tomorrow, tomorrow.Add(viewingSlotMaxLength), ""},
+		{"already started", now.Add(-time.Hour), now.Add(time.Hour), "ViewingSlotInPast"},
+		{"starts now", now, now.Add(time.Hour), "ViewingSlotInPast"},
+		{"ends when it starts", tomorrow, tomorrow, "InvalidViewingSlotWindow"},
+		{"ends before it starts", tomorrow, tomorrow.Add(-time.Hour), "InvalidViewingSlotWindow"},
+		{"runs over a day", tomorrow, tomorrow.Add(viewingSlotMaxLength + time.Minute), "ViewingSlotTooLong"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wantRentLoopError(t, validateViewingSlotWindow(tc.startsAt, tc.endsAt, now), tc.wantErr)
+		})
+	}
+}
+
+// A prospect can take a place in an open slot that has not started and still
+// has room.
+func TestViewingSlotBookable(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	slot := func(status string, startsAt time.Time) *models.ViewingSlot {
+		return &models.ViewingSlot{Status: status, StartsAt: startsAt, Capacity: 3}
+	}
+
+	cases := []struct {
+		name    string
+		slot    *models.ViewingSlot
+		taken   int64
+		wantErr string
+	}{
+		{"empty", slot("OPEN", now.Add(time.Hour)), 0, ""},
+		{"one place left", slot("OPEN", now.Add(time.Hour)), 2, ""},
+		{"full", slot("OPEN", now.Add(time.Hour)), 3, "ViewingSlotFull"},
+		{"starts now", slot("OPEN", now), 0, "ViewingSlotStarted"},
+		{"cancelled", slot("CANCELLED", now.Add(time.Hour)), 0, "ViewingSlotNotOpen"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			wantRentLoopError(t, viewingSlotBookable(tc.slot, tc.taken, now), tc.wantErr)
+		})
+	}
+}
+
+// Prospects are reminded a day before a viewing, or two hours before one
+// booked at shorter notice; a viewing closer than that gets no reminder.
+func TestViewingReminderAt(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		scheduledAt time.Time
+		want        time.Time
+	}{
+		{"in three days", now.Add(72 * time.Hour), now.Add(48 * time.Hour)},
+		{"in a day and a minute", now.Add(24*time.Hour + time.Minute), now.Add(time.Minute)},
+		{"in exactly a day", now.Add(24 * time.Hour), now.Add(22 * time.Hour)},
+		{"this afternoon", now.Add(5 * time.Hour), now.Add(3 * time.Hour)},
+		{"in two hours", now.Add(2 * time.Hour), time.Time{}},
+		{"in an hour", now.Add(time.Hour), time.Time{}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := viewingReminderAt(now, tc.scheduledAt); !got.Equal(tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,600 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/clients/gatekeeper"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib"
+	"github.com/Bendomey/rent-loop/services/main/internal/lib/emailtemplates"
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/repository"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+	log "github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+)
+
+// waitlistInviteTTL is how long an invited prospect has to start an
+// application before the unit is offered to the next entry.
+const waitlistInviteTTL = 48 * time.Hour
+
+// waitlistVacancyNotifyLimit caps how many waiting prospects are told a unit
+// is about to come free; the invite itself still goes one at a time.
+const waitlistVacancyNotifyLimit = 10
+
+type WaitlistService interface {
+	Join(ctx context.Context, input JoinWaitlistInput) (*models.WaitlistEntry, error)
+	GetByID(ctx context.Context, query repository.GetWaitlistEntryQuery) (*models.WaitlistEntry, error)
+	List(
+		ctx context.Context,
+		filterQuery lib.FilterQuery,
+		filters repository.ListWaitlistEntriesFilter,
+	) ([]models.WaitlistEntry, error)
+	Count(ctx context.Context, filterQuery lib.FilterQuery, filters repository.ListWaitlistEntriesFilter) (int64, error)
+	Update(ctx context.Context, input UpdateWaitlistEntryInput) (*models.WaitlistEntry, error)
+	Remove(ctx context.Context, input RemoveWaitlistEntryInput) (*models.WaitlistEntry, error)
+	// GetInvite returns the entry a live invite link was sent to.
+	GetInvite(ctx context.Context, token string) (*models.WaitlistEntry, error)
+
+	// InviteNextForUnit, NotifyUnitVacating and ExpireInvite run from the
+	// queue as units come free and invites run out.
+	InviteNextForUnit(ctx context.Context, unitID string) error
+	NotifyUnitVacating(ctx context.Context, unitID string, availableFrom time.Time) error
+	ExpireInvite(ctx context.Context, entryID string, expiresAt time.Time) error
+}
+
+type waitlistService struct {
+	appCtx       pkg.AppContext
+	repo         repository.WaitlistEntryRepository
+	unitRepo     repository.UnitRepository
+	propertyRepo repository.PropertyRepository
+	queue        RentloopQueue
+}
+
+type WaitlistServiceDeps struct {
+	AppCtx        pkg.AppContext
+	Repo          repository.WaitlistEntryRepository
+	UnitRepo      repository.UnitRepository
+	PropertyRepo  repository.PropertyRepository
+	RentloopQueue RentloopQueue
+}
+
+func NewWaitlistService(deps WaitlistServiceDeps) WaitlistService {
+	return &waitlistService{
+		appCtx:       deps.AppCtx,
+		repo:         deps.Repo,
+		unitRepo:     deps.UnitRepo,
+		propertyRepo: deps.PropertyRepo,
+		queue:        deps.RentloopQueue,
+	}
+}
+
+type JoinWaitlistInput struct {
+	PropertySlug      string
+	UnitID            *string
+	UnitType          *string
+	FirstName         string
+	LastName          string
+	Email             *string
+	Phone             string
+	DesiredMoveInFrom *time.Time
+	DesiredMoveInTo   *time.Time
+	MaxRentFee        *int64
+}
+
+// Join puts a prospect on an active property's waitlist. A phone number holds
+// one open entry per property.
+func (s *waitlistService) Join(ctx context.Context, input JoinWaitlistInput) (*models.WaitlistEntry, error) {
+	if input.DesiredMoveInFrom != nil && input.DesiredMoveInTo != nil &&
+		input.DesiredMoveInTo.Before(*input.DesiredMoveInFrom) {
+		return nil, pkg.BadRequestError("InvalidMoveInWindow", nil)
+	}
+
+	property, err := s.propertyRepo.GetBySlug(ctx, repository.GetPropertyBySlugQuery{Slug: input.PropertySlug})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("PropertyNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Join", "action": "fetching property"},
+		})
+	}
+	if property.Status != "Property.Status.Active" {
+		return nil, pkg.BadRequestError("PropertyNotActive", nil)
+	}
+
+	if input.UnitID != nil {
+		if _, unitErr := s.unitRepo.GetOne(ctx, map[string]any{
+			"id":          *input.UnitID,
+			"property_id": property.ID.String(),
+		}); unitErr != nil {
+			if errors.Is(unitErr, gorm.ErrRecordNotFound) {
+				return nil, pkg.NotFoundError("UnitNotFound", &pkg.RentLoopErrorParams{Err: unitErr})
+			}
+			return nil, pkg.InternalServerError(unitErr.Error(), &pkg.RentLoopErrorParams{
+				Err:      unitErr,
+				Metadata: map[string]string{"function": "Join", "action": "fetching unit"},
+			})
+		}
+	}
+
+	openCount, countErr := s.repo.CountOpenByPhone(ctx, property.ID.String(), input.Phone)
+	if countErr != nil {
+		return nil, pkg.InternalServerError(countErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      countErr,
+			Metadata: map[string]string{"function": "Join", "action": "counting open entries"},
+		})
+	}
+	if openCount > 0 {
+		return nil, pkg.ConflictError("AlreadyOnWaitlist", nil)
+	}
+
+	entry := models.WaitlistEntry{
+		PropertyID:        property.ID.String(),
+		UnitID:            input.UnitID,
+		UnitType:          input.UnitType,
+		FirstName:         input.FirstName,
+		LastName:          input.LastName,
+		Email:             input.Email,
+		Phone:             input.Phone,
+		DesiredMoveInFrom: input.DesiredMoveInFrom,
+		DesiredMoveInTo:   input.DesiredMoveInTo,
+		MaxRentFee:        input.MaxRentFee,
+		Status:            "WAITING",
+	}
+
+	if createErr := s.repo.Create(ctx, &entry); createErr != nil {
+		return nil, pkg.InternalServerError(createErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      createErr,
+			Metadata: map[string]string{"function": "Join", "action": "creating entry"},
+		})
+	}
+
+	entry.Property = *property
+	return &entry, nil
+}
+
+func (s *waitlistService) GetByID(
+	ctx context.Context,
+	query repository.GetWaitlistEntryQuery,
+) (*models.WaitlistEntry, error) {
+	entry, err := s.repo.GetByIDWithPopulate(ctx, query)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("WaitlistEntryNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GetByID", "action": "fetching entry"},
+		})
+	}
+
+	return entry, nil
+}
+
+func (s *waitlistService) List(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListWaitlistEntriesFilter,
+) ([]models.WaitlistEntry, error) {
+	entries, err := s.repo.List(ctx, filterQuery, filters)
+	if err != nil {
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "List", "action": "listing entries"},
+		})
+	}
+
+	return entries, nil
+}
+
+func (s *waitlistService) Count(
+	ctx context.Context,
+	filterQuery lib.FilterQuery,
+	filters repository.ListWaitlistEntriesFilter,
+) (int64, error) {
+	count, err := s.repo.Count(ctx, filterQuery, filters)
+	if err != nil {
+		return 0, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "Count", "action": "counting entries"},
+		})
+	}
+
+	return count, nil
+}
+
+type UpdateWaitlistEntryInput struct {
+	ID         string
+	PropertyID string
+	Priority   *int64
+	Notes      *string
+}
+
+// Update lets a manager move an entry up or down the waitlist and keep notes
+// on it.
+func (s *waitlistService) Update(ctx context.Context, input UpdateWaitlistEntryInput) (*models.WaitlistEntry, error) {
+	entry, err := s.GetByID(ctx, repository.GetWaitlistEntryQuery{ID: input.ID, PropertyID: &input.PropertyID})
+	if err != nil {
+		return nil, err
+	}
+
+	if input.Priority != nil {
+		entry.Priority = *input.Priority
+	}
+	if input.Notes != nil {
+		entry.Notes = *input.Notes
+	}
+
+	if updateErr := s.repo.Update(ctx, entry); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Update", "action": "updating entry"},
+		})
+	}
+
+	return entry, nil
+}
+
+type RemoveWaitlistEntryInput struct {
+	ID          string
+	PropertyID  string
+	RemovedByID string
+}
+
+// Remove takes an entry off the waitlist. Removing an entry holding an
+// invite passes the unit on to the next one.
+func (s *waitlistService) Remove(ctx context.Context, input RemoveWaitlistEntryInput) (*models.WaitlistEntry, error) {
+	entry, err := s.GetByID(ctx, repository.GetWaitlistEntryQuery{ID: input.ID, PropertyID: &input.PropertyID})
+	if err != nil {
+		return nil, err
+	}
+
+	if entry.Status != "WAITING" && entry.Status != "INVITED" {
+		return nil, pkg.BadRequestError("WaitlistEntryNotOpen", nil)
+	}
+
+	wasInvited := entry.Status == "INVITED"
+	now := time.Now()
+	entry.Status = "REMOVED"
+	entry.RemovedAt = &now
+	entry.RemovedByID = &input.RemovedByID
+
+	if updateErr := s.repo.Update(ctx, entry); updateErr != nil {
+		return nil, pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "Remove", "action": "removing entry"},
+		})
+	}
+
+	if wasInvited && entry.InvitedUnitID != nil {
+		s.enqueueUnitReleased(ctx, *entry.InvitedUnitID)
+	}
+
+	return entry, nil
+}
+
+// waitlistInviteUsable checks entry holds a live invite, and to unitID when
+// one is given.
+func waitlistInviteUsable(entry *models.WaitlistEntry, unitID *string, now time.Time) error {
+	if entry.Status != "INVITED" || entry.InviteExpiresAt == nil || !entry.InviteExpiresAt.After(now) {
+		return pkg.BadRequestError("WaitlistInviteExpired", nil)
+	}
+	if unitID != nil && (entry.InvitedUnitID == nil || *entry.InvitedUnitID != *unitID) {
+		return pkg.BadRequestError("WaitlistInviteNotForUnit", nil)
+	}
+
+	return nil
+}
+
+func (s *waitlistService) GetInvite(ctx context.Context, token string) (*models.WaitlistEntry, error) {
+	entry, err := s.repo.GetByInviteToken(ctx, token, &[]string{"Property", "InvitedUnit"})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("WaitlistInviteNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "GetInvite", "action": "fetching entry"},
+		})
+	}
+
+	if usableErr := waitlistInviteUsable(entry, nil, time.Now()); usableErr != nil {
+		return nil, usableErr
+	}
+
+	return entry, nil
+}
+
+// getWaitlistUnit loads a unit with its property for waitlist messages.
+func (s *waitlistService) getWaitlistUnit(ctx context.Context, unitID, function string) (*models.Unit, error) {
+	unit, err := s.unitRepo.GetOne(ctx, map[string]any{"id": unitID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("UnitNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": function, "action": "fetching unit"},
+		})
+	}
+
+	property, err := s.propertyRepo.GetByID(ctx, repository.GetPropertyQuery{ID: unit.PropertyID})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, pkg.NotFoundError("PropertyNotFound", &pkg.RentLoopErrorParams{Err: err})
+		}
+		return nil, pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": function, "action": "fetching property"},
+		})
+	}
+	unit.Property = *property
+
+	return unit, nil
+}
+
+// InviteNextForUnit offers an available unit to the first waiting entry it
+// suits, unless an earlier invite to it is still live. The unit's row is
+// locked from the live-invite check until the invite is saved, so two runs
+// for the same unit cannot both invite someone.
+func (s *waitlistService) InviteNextForUnit(ctx context.Context, unitID string) error {
+	unit, err := s.getWaitlistUnit(ctx, unitID, "InviteNextForUnit")
+	if err != nil {
+		return err
+	}
+
+	transaction := s.appCtx.DB.Begin()
+	transCtx := lib.WithTransaction(ctx, transaction)
+
+	locked, err := s.unitRepo.GetByIDForUpdate(transCtx, unitID)
+	if err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "InviteNextForUnit", "action": "locking unit"},
+		})
+	}
+	if locked.Status != "Unit.Status.Available" && locked.Status != "Unit.Status.PartiallyOccupied" {
+		transaction.Rollback()
+		return nil
+	}
+
+	now := time.Now()
+	hasLiveInvite, err := s.repo.HasLiveInvite(transCtx, unitID, now)
+	if err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "InviteNextForUnit", "action": "checking live invites"},
+		})
+	}
+	if hasLiveInvite {
+		transaction.Rollback()
+		return nil
+	}
+
+	entries, err := s.repo.ListWaitingForUnit(transCtx, unit, now, 1)
+	if err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "InviteNextForUnit", "action": "listing waiting entries"},
+		})
+	}
+	if len(entries) == 0 {
+		transaction.Rollback()
+		return nil
+	}
+
+	token, err := newWaitlistInviteToken()
+	if err != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "InviteNextForUnit", "action": "generating invite token"},
+		})
+	}
+
+	entry := entries[0]
+	expiresAt := now.Add(waitlistInviteTTL).Truncate(time.Second)
+	entry.Status = "INVITED"
+	entry.InvitedUnitID = &unitID
+	entry.InviteToken = &token
+	entry.InvitedAt = &now
+	entry.InviteExpiresAt = &expiresAt
+
+	if updateErr := s.repo.Update(transCtx, &entry); updateErr != nil {
+		transaction.Rollback()
+		return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "InviteNextForUnit", "action": "inviting entry"},
+		})
+	}
+
+	if commitErr := transaction.Commit().Error; commitErr != nil {
+		return pkg.InternalServerError(commitErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      commitErr,
+			Metadata: map[string]string{"function": "InviteNextForUnit", "action": "committing transaction"},
+		})
+	}
+
+	if queueErr := s.queue.ScheduleWaitlistInviteExpiry(ctx, entry.ID.String(), expiresAt); queueErr != nil {
+		log.WithError(queueErr).
+			WithField("waitlist_entry_id", entry.ID.String()).
+			Error("failed to schedule waitlist invite expiry")
+	}
+
+	s.sendWaitlistInviteNotification(entry, unit)
+
+	return nil
+}
+
+// NotifyUnitVacating tells the first waiting entries a unit suits that it
+// is expected to come free at availableFrom. The invite follows once it does.
+func (s *waitlistService) NotifyUnitVacating(ctx context.Context, unitID string, availableFrom time.Time) error {
+	unit, err := s.getWaitlistUnit(ctx, unitID, "NotifyUnitVacating")
+	if err != nil {
+		return err
+	}
+
+	entries, err := s.repo.ListWaitingForUnit(ctx, unit, availableFrom, waitlistVacancyNotifyLimit)
+	if err != nil {
+		return pkg.InternalServerError(err.Error(), &pkg.RentLoopErrorParams{
+			Err:      err,
+			Metadata: map[string]string{"function": "NotifyUnitVacating", "action": "listing waiting entries"},
+		})
+	}
+
+	now := time.Now()
+	for i := range entries {
+		entries[i].VacancyNotifiedAt = &now
+		if updateErr := s.repo.Update(ctx, &entries[i]); updateErr != nil {
+			return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+				Err:      updateErr,
+				Metadata: map[string]string{"function": "NotifyUnitVacating", "action": "marking entry notified"},
+			})
+		}
+		s.sendWaitlistVacancyNotification(entries[i], unit, availableFrom)
+	}
+
+	return nil
+}
+
+// ExpireInvite closes an invite that ran out at expiresAt unused and offers
+// the unit to the next entry.
+func (s *waitlistService) ExpireInvite(ctx context.Context, entryID string, expiresAt time.Time) error {
+	entry, err := s.GetByID(ctx, repository.GetWaitlistEntryQuery{ID: entryID})
+	if err != nil {
+		return err
+	}
+
+	if entry.Status != "INVITED" || entry.InviteExpiresAt == nil || !entry.InviteExpiresAt.Equal(expiresAt) {
+		return nil
+	}
+
+	entry.Status = "EXPIRED"
+	if updateErr := s.repo.Update(ctx, entry); updateErr != nil {
+		return pkg.InternalServerError(updateErr.Error(), &pkg.RentLoopErrorParams{
+			Err:      updateErr,
+			Metadata: map[string]string{"function": "ExpireInvite", "action": "expiring entry"},
+		})
+	}
+
+	return s.InviteNextForUnit(ctx, *entry.InvitedUnitID)
+}
+
+func (s *waitlistService) enqueueUnitReleased(ctx context.Context, unitID string) {
+	if err := s.queue.EnqueueWaitlistUnitReleased(ctx, unitID); err != nil {
+		log.WithError(err).WithField("unit_id", unitID).Error("failed to enqueue waitlist invite")
+	}
+}
+
+func newWaitlistInviteToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func (s *waitlistService) sendWaitlistInviteNotification(entry models.WaitlistEntry, unit *models.Unit) {
+	expiresAt := entry.InviteExpiresAt.Format("January 2, 2006 3:04pm")
+
+	if entry.Email != nil {
+		htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+			"waitlist/invited",
+			emailtemplates.WaitlistInvitedData{
+				FirstName:    entry.FirstName,
+				UnitID:       unit.ID.String(),
+				UnitName:     unit.Name,
+				PropertyName: unit.Property.Name,
+				InviteToken:  *entry.InviteToken,
+				ExpiresAt:    expiresAt,
+			},
+		)
+		if renderErr != nil {
+			log.WithError(renderErr).Error("failed to render waitlist/invited email template")
+		} else {
+			go pkg.SendEmail(
+				s.appCtx.Config,
+				pkg.SendEmailInput{
+					Recipient: *entry.Email,
+					Subject:   lib.WAITLIST_INVITED_SUBJECT,
+					HtmlBody:  htmlBody,
+					TextBody:  textBody,
+				},
+			)
+		}
+	}
+
+	smsBody := strings.NewReplacer(
+		"{{first_name}}", entry.FirstName,
+		"{{unit_name}}", unit.Name,
+		"{{property_name}}", unit.Property.Name,
+		"{{unit_id}}", unit.ID.String(),
+		"{{invite_token}}", *entry.InviteToken,
+		"{{expires_at}}", expiresAt,
+	).Replace(lib.WAITLIST_INVITED_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: entry.Phone,
+			Message:   smsBody,
+		},
+	)
+}
+
+func (s *waitlistService) sendWaitlistVacancyNotification(
+	entry models.WaitlistEntry,
+	unit *models.Unit,
+	availableFrom time.Time,
+) {
+	availableOn := availableFrom.Format("January 2, 2006")
+
+	if entry.Email != nil {
+		htmlBody, textBody, renderErr := s.appCtx.EmailEngine.Render(
+			"waitlist/vacancy",
+			emailtemplates.WaitlistVacancyData{
+				FirstName:    entry.FirstName,
+				UnitName:     unit.Name,
+				PropertyName: unit.Property.Name,
+				AvailableOn:  availableOn,
+			},
+		)
+		if renderErr != nil {
+			log.WithError(renderErr).Error("failed to render waitlist/vacancy email template")
+		} else {
+			go pkg.SendEmail(
+				s.appCtx.Config,
+				pkg.SendEmailInput{
+					Recipient: *entry.Email,
+					Subject:   lib.WAITLIST_VACANCY_SUBJECT,
+					HtmlBody:  htmlBody,
+					TextBody:  textBody,
+				},
+			)
+		}
+	}
+
+	smsBody := strings.NewReplacer(
+		"{{first_name}}", entry.FirstName,
+		"{{unit_name}}", unit.Name,
+		"{{property_name}}", unit.Property.Name,
+		"{{available_on}}", availableOn,
+	).Replace(lib.WAITLIST_VACANCY_SMS_BODY)
+
+	go s.appCtx.Clients.GatekeeperAPI.SendSMS(
+		context.Background(),
+		gatekeeper.SendSMSInput{
+			Recipient: entry.Phone,
+			Message:   smsBody,
+		},
+	)
+}
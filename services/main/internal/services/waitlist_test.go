@@ -0,0 +1,90 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/pkg"
+)
+
+// An invite link only starts an application while the entry still holds the
+// invite, and only for the unit it was sent for.
+func TestWaitlistInviteUsable(t *testing.T) {
+	now := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	unitID := "660e8400-e29b-41d4-a716-446655440000"
+	otherUnitID := "770e8400-e29b-41d4-a716-446655440000"
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	invited := func(expiresAt *time.Time) *models.WaitlistEntry {
+		return &models.WaitlistEntry{Status: "INVITED", InvitedUnitID: &unitID, InviteExpiresAt: expiresAt}
+	}
+	closed := func(status string) *models.WaitlistEntry {
+		return &models.WaitlistEntry{Status: status, InvitedUnitID: &unitID, InviteExpiresAt: &later}
+	}
+
+	cases := []struct {
+		name    string
+		entry   *models.WaitlistEntry
+		unitID  *string
+		wantErr string
+	}{
+		{"live invite", invited(&later), nil, ""},
+		{"live invite for the unit", invited(&later), &unitID, ""},
+		{"live invite for another unit", invited(&later), &otherUnitID, "WaitlistInviteNotForUnit"},
+		{"run out", invited(&earlier), nil, "WaitlistInviteExpired"},
+		{"runs out now", invited(&now), nil, "WaitlistInviteExpired"},
+		{"no expiry", invited(nil), nil, "WaitlistInviteExpired"},
+		{"already applied", closed("APPLIED"), nil, "WaitlistInviteExpired"},
+		{"removed", closed("REMOVED"), nil, "WaitlistInviteExpired"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := waitlistInviteUsable(tc.entry, tc.unitID, now)
+
+			var rentLoopErr *pkg.IRentLoopError
+			switch {
+			case tc.wantErr == "" && err != nil:
+				t.Errorf("got %v, want no error", err)
+			case tc.wantErr != "" && (!errors.As(err, &rentLoopErr) || rentLoopErr.Message != tc.wantErr):
+				t.Errorf("got %v, want %s", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// The waitlist is invited when a unit gains room for a new tenant, not on
+// every save of a unit that was already taking tenants.
+func TestUnitReleased(t *testing.T) {
+	const (
+		available = "Unit.Status.Available"
+		partially = "Unit.Status.PartiallyOccupied"
+		occupied  = "Unit.Status.Occupied"
+		draft     = "Unit.Status.Draft"
+	)
+
+	cases := []struct {
+		previous string
+		status   string
+		want     bool
+	}{
+		{occupied, available, true},
+		{occupied, partially, true},
+		{partially, available, true},
+		{draft, available, true},
+		{available, available, false},
+		{partially, partially, false},
+		{available, partially, false},
+		{available, occupied, false},
+		{partially, occupied, false},
+	}
+
+	for _, tc := range cases {
+		if got := unitReleased(tc.previous, tc.status); got != tc.want {
+			t.Errorf("%s → %s: got %t, want %t", tc.previous, tc.status, got, tc.want)
+		}
+	}
+}
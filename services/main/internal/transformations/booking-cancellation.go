@@ -0,0 +1,78 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/gofrs/uuid"
+)
+
+type OutputBookingCancellationTier struct {
+	MinDaysBeforeCheckIn int64 `json:"min_days_before_check_in" example:"5"`
+	RefundPercent        int64 `json:"refund_percent"           example:"100"`
+}
+
+type OutputBookingCancellationPolicy struct {
+	Name        string                          `json:"name"        example:"MODERATE"`
+	Description string                          `json:"description" example:"Full refund if cancelled at least 5 days before check-in. 50% refund if cancelled at least 1 day before check-in. No refund after that."`
+	Tiers       []OutputBookingCancellationTier `json:"tiers"`
+}
+
+func BookingCancellationPolicyToRest(name string) any {
+	policy := services.GetBookingCancellationPolicy(name)
+
+	tiers := make([]any, 0, len(policy.Tiers))
+	for _, tier := range policy.Tiers {
+		tiers = append(tiers, map[string]any{
+			"min_days_before_check_in": tier.MinDaysBeforeCheckIn,
+			"refund_percent":           tier.RefundPercent,
+		})
+	}
+
+	return map[string]any{
+		"name":        policy.Name,
+		"description": policy.Description(),
+		"tiers":       tiers,
+	}
+}
+
+type OutputBookingRefund struct {
+	ID                 string     `json:"id"                               example:"0b6f8a52-3c1d-4e7a-9f20-5d8c1e4b7a93"`
+	BookingID          string     `json:"booking_id"                       example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	InvoiceID          string     `json:"invoice_id"                       example:"b50874ee-1a70-436e-ba24-572078895982"`
+	CancellationPolicy string     `json:"cancellation_policy"              example:"MODERATE"`
+	DaysBeforeCheckIn  int64      `json:"days_before_check_in"             example:"3"`
+	RefundPercent      int64      `json:"refund_percent"                   example:"50"`
+	AmountPaid         int64      `json:"amount_paid"                      example:"240000"`
+	Amount             int64      `json:"amount"                           example:"120000"`
+	Currency           string     `json:"currency"                         example:"GHS"`
+	Status             string     `json:"status"                           example:"PENDING"`
+	PaidAt             *time.Time `json:"paid_at,omitempty"                example:"2026-10-20T10:00:00Z"`
+	PaidByClientUserID *string    `json:"paid_by_client_user_id,omitempty" example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	PaymentReference   *string    `json:"payment_reference,omitempty"      example:"MP261020.1000.A12345"`
+	CreatedAt          time.Time  `json:"created_at"                       example:"2026-10-19T00:00:00Z"`
+}
+
+func DBBookingRefundToRest(i *models.BookingRefund) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                     i.ID.String(),
+		"booking_id":             i.BookingID,
+		"invoice_id":             i.InvoiceID,
+		"cancellation_policy":    i.CancellationPolicy,
+		"days_before_check_in":   i.DaysBeforeCheckIn,
+		"refund_percent":         i.RefundPercent,
+		"amount_paid":            i.AmountPaid,
+		"amount":                 i.Amount,
+		"currency":               i.Currency,
+		"status":                 i.Status,
+		"paid_at":                i.PaidAt,
+		"paid_by_client_user_id": i.PaidByClientUserID,
+		"payment_reference":      i.PaymentReference,
+		"created_at":             i.CreatedAt,
+	}
+}
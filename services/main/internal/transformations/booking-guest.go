@@ -0,0 +1,51 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputBookingGuest struct {
+	ID          string    `json:"id"                    example:"2a7d9c4e-5b1f-4e8a-9c3d-6f2b8e1a0d4c"`
+	BookingID   string    `json:"booking_id"            example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	FirstName   string    `json:"first_name"            example:"Ama"`
+	LastName    string    `json:"last_name"             example:"Mensah"`
+	IsChild     bool      `json:"is_child"              example:"false"`
+	Nationality *string   `json:"nationality,omitempty" example:"Ghanaian"`
+	IDType      *string   `json:"id_type,omitempty"     example:"PASSPORT"                             enums:"GHANA_CARD,NATIONAL_ID,PASSPORT,DRIVER_LICENSE"`
+	IDNumber    *string   `json:"id_number,omitempty"   example:"G1234567"`
+	CreatedAt   time.Time `json:"created_at"            example:"2026-10-22T14:00:00Z"`
+}
+
+func DBBookingGuestToRest(i *models.BookingGuest) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":          i.ID.String(),
+		"booking_id":  i.BookingID,
+		"first_name":  i.FirstName,
+		"last_name":   i.LastName,
+		"is_child":    i.IsChild,
+		"nationality": i.Nationality,
+		"id_type":     i.IDType,
+		"id_number":   i.IDNumber,
+		"created_at":  i.CreatedAt,
+	}
+}
+
+// bookingGuestsToRest is nil unless a booking's guests were loaded.
+func bookingGuestsToRest(guests []models.BookingGuest) any {
+	if len(guests) == 0 {
+		return nil
+	}
+
+	data := make([]any, 0, len(guests))
+	for i := range guests {
+		data = append(data, DBBookingGuestToRest(&guests[i]))
+	}
+	return data
+}
@@ -0,0 +1,60 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputBookingModification struct {
+	ID                    string    `json:"id"                               example:"7c1e4b7a-0b6f-4a52-9f20-5d8c3c1d4e7a"`
+	BookingID             string    `json:"booking_id"                       example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Reason                string    `json:"reason,omitempty"                 example:"Guest extended their stay by two nights"`
+	PreviousUnitID        string    `json:"previous_unit_id"                 example:"660e8400-e29b-41d4-a716-446655440000"`
+	PreviousCheckInDate   time.Time `json:"previous_check_in_date"           example:"2026-10-22T14:00:00Z"`
+	PreviousCheckOutDate  time.Time `json:"previous_check_out_date"          example:"2026-10-25T11:00:00Z"`
+	UnitID                string    `json:"unit_id"                          example:"660e8400-e29b-41d4-a716-446655440000"`
+	Unit                  any       `json:"unit,omitempty"`
+	CheckInDate           time.Time `json:"check_in_date"                    example:"2026-10-22T14:00:00Z"`
+	CheckOutDate          time.Time `json:"check_out_date"                   example:"2026-10-27T11:00:00Z"`
+	PreviousTotal         int64     `json:"previous_total"                   example:"150000"                                  description:"What the stay was priced at before the change"`
+	Total                 int64     `json:"total"                            example:"250000"                                  description:"What the stay is priced at after the change"`
+	Currency              string    `json:"currency"                         example:"GHS"`
+	InvoiceID             *string   `json:"invoice_id,omitempty"             example:"b50874ee-1a70-436e-ba24-572078895982"    description:"Supplementary invoice billing a higher price"`
+	Invoice               any       `json:"invoice,omitempty"`
+	CreditAmount          int64     `json:"credit_amount"                    example:"0"                                       description:"What the guest is owed for a lower price"`
+	Refund                any       `json:"refund,omitempty"                                                                    description:"Refund paying the credit back"`
+	CreatedByClientUserID string    `json:"created_by_client_user_id"        example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CreatedByClientUser   any       `json:"created_by_client_user,omitempty"`
+	CreatedAt             time.Time `json:"created_at"                       example:"2026-10-23T09:00:00Z"`
+}
+
+func DBBookingModificationToRest(i *models.BookingModification) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                        i.ID.String(),
+		"booking_id":                i.BookingID,
+		"reason":                    i.Reason,
+		"previous_unit_id":          i.PreviousUnitID,
+		"previous_check_in_date":    i.PreviousCheckInDate,
+		"previous_check_out_date":   i.PreviousCheckOutDate,
+		"unit_id":                   i.UnitID,
+		"unit":                      DBUnitToRest(&i.Unit),
+		"check_in_date":             i.CheckInDate,
+		"check_out_date":            i.CheckOutDate,
+		"previous_total":            i.PreviousTotal,
+		"total":                     i.Total,
+		"currency":                  i.Currency,
+		"invoice_id":                i.InvoiceID,
+		"invoice":                   DBInvoiceToRest(i.Invoice),
+		"credit_amount":             i.CreditAmount,
+		"refund":                    DBBookingRefundToRest(i.Refund),
+		"created_by_client_user_id": i.CreatedByClientUserID,
+		"created_by_client_user":    DBClientUserToRest(&i.CreatedByClientUser),
+		"created_at":                i.CreatedAt,
+	}
+}
@@ -0,0 +1,125 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+	"github.com/gofrs/uuid"
+)
+
+type OutputBookingPricingRule struct {
+	ID                    string     `json:"id"                                  example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	PropertyID            string     `json:"property_id"                         example:"550e8400-e29b-41d4-a716-446655440000"`
+	UnitID                *string    `json:"unit_id,omitempty"                   example:"660e8400-e29b-41d4-a716-446655440000"`
+	Unit                  any        `json:"unit,omitempty"`
+	Name                  string     `json:"name"                                example:"Christmas peak"`
+	Type                  string     `json:"type"                                example:"RATE"`
+	Priority              int64      `json:"priority"                            example:"0"`
+	StartDate             *time.Time `json:"start_date,omitempty"                example:"2026-12-20T00:00:00Z"`
+	EndDate               *time.Time `json:"end_date,omitempty"                  example:"2027-01-02T00:00:00Z"`
+	DaysOfWeek            []string   `json:"days_of_week"                        example:"FRI,SAT"`
+	NightlyRate           *int64     `json:"nightly_rate,omitempty"              example:"75000"`
+	AdjustmentPercent     *int64     `json:"adjustment_percent,omitempty"        example:"20"`
+	MinNights             *int64     `json:"min_nights,omitempty"                example:"3"`
+	MaxNights             *int64     `json:"max_nights,omitempty"                example:"28"`
+	DiscountPercent       *int64     `json:"discount_percent,omitempty"          example:"10"`
+	CreatedByClientUserID *string    `json:"created_by_client_user_id,omitempty" example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CreatedAt             time.Time  `json:"created_at"                          example:"2026-10-19T00:00:00Z"`
+	UpdatedAt             time.Time  `json:"updated_at"                          example:"2026-10-19T00:00:00Z"`
+}
+
+func DBBookingPricingRuleToRest(i *models.BookingPricingRule) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                        i.ID.String(),
+		"property_id":               i.PropertyID,
+		"unit_id":                   i.UnitID,
+		"unit":                      DBUnitToRest(i.Unit),
+		"name":                      i.Name,
+		"type":                      i.Type,
+		"priority":                  i.Priority,
+		"start_date":                i.StartDate,
+		"end_date":                  i.EndDate,
+		"days_of_week":              i.DaysOfWeek,
+		"nightly_rate":              i.NightlyRate,
+		"adjustment_percent":        i.AdjustmentPercent,
+		"min_nights":                i.MinNights,
+		"max_nights":                i.MaxNights,
+		"discount_percent":          i.DiscountPercent,
+		"created_by_client_user_id": i.CreatedByClientUserID,
+		"created_at":                i.CreatedAt,
+		"updated_at":                i.UpdatedAt,
+	}
+}
+
+type OutputBookingQuoteNight struct {
+	Date string `json:"date" example:"2026-12-24"`
+	Rate int64  `json:"rate" example:"90000"`
+}
+
+type OutputBookingQuote struct {
+	Currency        string                    `json:"currency"          example:"GHS"`
+	Frequency       string                    `json:"frequency"         example:"DAILY"`
+	Quantity        int64                     `json:"quantity"          example:"3"`
+	PeriodLabel     string                    `json:"period_label"      example:"nights"`
+	Rate            int64                     `json:"rate"              example:"75000"`
+	Nights          []OutputBookingQuoteNight `json:"nights"`
+	SubTotal        int64                     `json:"sub_total"         example:"240000"`
+	DiscountPercent int64                     `json:"discount_percent"  example:"0"`
+	Discount        int64                     `json:"discount"          example:"0"`
+	ExtraGuests     int64                     `json:"extra_guests"      example:"1"      description:"Guests above the unit's base occupancy"`
+	ExtraGuestFee   int64                     `json:"extra_guest_fee"   example:"5000"   description:"Charged per extra guest per period"`
+	ExtraGuestTotal int64                     `json:"extra_guest_total" example:"15000"`
+	Total           int64                     `json:"total"             example:"240000"`
+}
+
+func BookingQuoteToRest(q *services.BookingQuote) any {
+	if q == nil {
+		return nil
+	}
+
+	nights := make([]any, 0, len(q.Nights))
+	for _, night := range q.Nights {
+		nights = append(nights, map[string]any{
+			"date": night.Date.Format(time.DateOnly),
+			"rate": night.Rate,
+		})
+	}
+
+	return map[string]any{
+		"currency":          q.Currency,
+		"frequency":         q.Frequency,
+		"quantity":          q.Quantity,
+		"period_label":      q.PeriodLabel,
+		"rate":              q.Rate,
+		"nights":            nights,
+		"sub_total":         q.SubTotal,
+		"discount_percent":  q.DiscountPercent,
+		"discount":          q.Discount,
+		"extra_guests":      q.ExtraGuests,
+		"extra_guest_fee":   q.ExtraGuestFee,
+		"extra_guest_total": q.ExtraGuestTotal,
+		"total":             q.Total,
+	}
+}
+
+type PublicOutputBookingQuote struct {
+	OutputBookingQuote
+	CancellationPolicy OutputBookingCancellationPolicy `json:"cancellation_policy"`
+}
+
+// PublicBookingQuoteToRest is a quote as a guest sees it before booking, with
+// the cancellation policy the booking would be taken under.
+func PublicBookingQuoteToRest(q *services.BookingQuote, property *models.Property) any {
+	data, ok := BookingQuoteToRest(q).(map[string]any)
+	if !ok {
+		return nil
+	}
+
+	data["cancellation_policy"] = BookingCancellationPolicyToRest(property.BookingCancellationPolicy)
+	return data
+}
@@ -5,6 +5,19 @@ import (
 	"github.com/gofrs/uuid"
 )
 
+// bookingInvoicesToRest renders a booking's supplementary invoices.
+func bookingInvoicesToRest(invoices []models.Invoice) any {
+	if len(invoices) == 0 {
+		return nil
+	}
+
+	data := make([]any, 0, len(invoices))
+	for i := range invoices {
+		data = append(data, DBInvoiceToRest(&invoices[i]))
+	}
+	return data
+}
+
 func bookingInvoiceID(b *models.Booking) *string {
 	if b.Invoice == nil {
 		return nil
@@ -25,6 +38,9 @@ type AdminOutputBooking struct {
 	Tenant                 any     `json:"tenant,omitempty"`
 	CheckInDate            string  `json:"check_in_date"`
 	CheckOutDate           string  `json:"check_out_date"`
+	Adults                 int64   `json:"adults"`
+	Children               int64   `json:"children"`
+	Guests                 any     `json:"guests,omitempty"`
 	ConfirmedAt            *string `json:"confirmed_at,omitempty"`
 	ConfirmedByID          *string `json:"confirmed_by_id,omitempty"`
 	ConfirmedBy            any     `json:"confirmed_by,omitempty"`
@@ -37,18 +53,23 @@ type AdminOutputBooking struct {
 	Rate                   int64   `json:"rate"`
 	Currency               string  `json:"currency"`
 	StayFrequency          string  `json:"stay_frequency"`
+	PricingMethod          string  `json:"pricing_method"`
 	Status                 string  `json:"status"`
 	CanceledAt             *string `json:"canceled_at,omitempty"`
 	CanceledByID           *string `json:"canceled_by_id,omitempty"`
 	CanceledBy             any     `json:"canceled_by,omitempty"`
 	CancellationReason     string  `json:"cancellation_reason,omitempty"`
+	CancellationPolicy     any     `json:"cancellation_policy"`
+	Refund                 any     `json:"refund,omitempty"`
 	Notes                  string  `json:"notes,omitempty"`
 	BookingSource          string  `json:"booking_source"`
 	RequiresUpfrontPayment bool    `json:"requires_upfront_payment"`
+	HoldExpiresAt          *string `json:"hold_expires_at,omitempty"`
 	CreatedByClientUserID  *string `json:"created_by_client_user_id,omitempty"`
 	CreatedByClientUser    any     `json:"created_by_client_user,omitempty"`
 	InvoiceID              *string `json:"invoice_id,omitempty"`
 	Invoice                any     `json:"invoice,omitempty"`
+	SupplementaryInvoices  any     `json:"supplementary_invoices,omitempty"`
 	Meta                   any     `json:"meta,omitempty"`
 	CreatedAt              string  `json:"created_at"`
 	UpdatedAt              string  `json:"updated_at"`
@@ -71,6 +92,9 @@ func DBBookingToRest(i *models.Booking) any {
 		"tenant":                    DBTenantToRest(&i.Tenant),
 		"check_in_date":             i.CheckInDate,
 		"check_out_date":            i.CheckOutDate,
+		"adults":                    i.Adults,
+		"children":                  i.Children,
+		"guests":                    bookingGuestsToRest(i.Guests),
 		"confirmed_at":              i.ConfirmedAt,
 		"confirmed_by_id":           i.ConfirmedByID,
 		"confirmed_by":              DBClientUserToRest(i.ConfirmedBy),
@@ -81,18 +105,23 @@ func DBBookingToRest(i *models.Booking) any {
 		"checked_out_by_id":         i.CheckedOutByID,
 		"checked_out_by":            DBClientUserToRest(i.CheckedOutBy),
 		"stay_frequency":            i.StayFrequency,
+		"pricing_method":            i.PricingMethod,
 		"status":                    i.Status,
 		"canceled_at":               i.CanceledAt,
 		"canceled_by_id":            i.CanceledByID,
 		"canceled_by":               DBClientUserToRest(i.CanceledBy),
 		"cancellation_reason":       i.CancellationReason,
+		"cancellation_policy":       BookingCancellationPolicyToRest(i.CancellationPolicy),
+		"refund":                    DBBookingRefundToRest(i.Refund),
 		"notes":                     i.Notes,
 		"booking_source":            i.BookingSource,
 		"requires_upfront_payment":  i.RequiresUpfrontPayment,
+		"hold_expires_at":           i.HoldExpiresAt,
 		"created_by_client_user_id": i.CreatedByClientUserID,
 		"created_by_client_user":    DBClientUserToRest(i.CreatedByClientUser),
 		"invoice_id":                bookingInvoiceID(i),
 		"invoice":                   DBInvoiceToRest(i.Invoice),
+		"supplementary_invoices":    bookingInvoicesToRest(i.SupplementaryInvoices),
 		"meta":                      i.Meta,
 		"created_at":                i.CreatedAt,
 		"updated_at":                i.UpdatedAt,
@@ -101,30 +130,36 @@ func DBBookingToRest(i *models.Booking) any {
 }
 
 type PublicOutputBooking struct {
-	ID                 string               `json:"id"`
-	Code               string               `json:"code"`
-	CheckInCode        *string              `json:"check_in_code,omitempty"`
-	CheckInDate        string               `json:"check_in_date"`
-	CheckOutDate       string               `json:"check_out_date"`
-	ConfirmedAt        *string              `json:"confirmed_at,omitempty"`
-	CheckedInAt        *string              `json:"checked_in_at,omitempty"`
-	CheckedOutAt       *string              `json:"checked_out_at,omitempty"`
-	Rate               int64                `json:"rate"`
-	Currency           string               `json:"currency"`
-	StayFrequency      string               `json:"stay_frequency"`
-	Status             string               `json:"status"`
-	UnitID             string               `json:"unit_id"`
-	Unit               OutputUnit           `json:"unit,omitempty"`
-	PropertyID         string               `json:"property_id"`
-	TenantID           string               `json:"tenant_id"`
-	Tenant             OutputTenant         `json:"tenant,omitempty"`
-	Property           PublicOutputProperty `json:"property,omitempty"`
-	CanceledAt         *string              `json:"canceled_at,omitempty"`
-	CancellationReason *string              `json:"cancellation_reason,omitempty"`
-	InvoiceID          *string              `json:"invoice_id,omitempty"`
-	Invoice            any                  `json:"invoice,omitempty"`
-	Meta               any                  `json:"meta,omitempty"`
-	CreatedAt          string               `json:"created_at"`
+	ID                    string                          `json:"id"`
+	Code                  string                          `json:"code"`
+	CheckInCode           *string                         `json:"check_in_code,omitempty"`
+	CheckInDate           string                          `json:"check_in_date"`
+	CheckOutDate          string                          `json:"check_out_date"`
+	Adults                int64                           `json:"adults"`
+	Children              int64                           `json:"children"`
+	ConfirmedAt           *string                         `json:"confirmed_at,omitempty"`
+	CheckedInAt           *string                         `json:"checked_in_at,omitempty"`
+	CheckedOutAt          *string                         `json:"checked_out_at,omitempty"`
+	Rate                  int64                           `json:"rate"`
+	Currency              string                          `json:"currency"`
+	StayFrequency         string                          `json:"stay_frequency"`
+	Status                string                          `json:"status"`
+	HoldExpiresAt         *string                         `json:"hold_expires_at,omitempty"`
+	UnitID                string                          `json:"unit_id"`
+	Unit                  OutputUnit                      `json:"unit,omitempty"`
+	PropertyID            string                          `json:"property_id"`
+	TenantID              string                          `json:"tenant_id"`
+	Tenant                OutputTenant                    `json:"tenant,omitempty"`
+	Property              PublicOutputProperty            `json:"property,omitempty"`
+	CanceledAt            *string                         `json:"canceled_at,omitempty"`
+	CancellationReason    *string                         `json:"cancellation_reason,omitempty"`
+	CancellationPolicy    OutputBookingCancellationPolicy `json:"cancellation_policy"`
+	Refund                OutputBookingRefund             `json:"refund,omitempty"`
+	InvoiceID             *string                         `json:"invoice_id,omitempty"`
+	Invoice               any                             `json:"invoice,omitempty"`
+	SupplementaryInvoices any                             `json:"supplementary_invoices,omitempty"`
+	Meta                  any                             `json:"meta,omitempty"`
+	CreatedAt             string                          `json:"created_at"`
 }
 
 // DBPublicBookingToRest is a reduced view for the public tracking page.
@@ -134,28 +169,34 @@ func DBPublicBookingToRest(i *models.Booking) any {
 	}
 
 	data := map[string]any{
-		"id":                  i.ID.String(),
-		"code":                i.Code,
-		"check_in_code":       i.CheckInCode,
-		"check_in_date":       i.CheckInDate,
-		"check_out_date":      i.CheckOutDate,
-		"confirmed_at":        i.ConfirmedAt,
-		"checked_in_at":       i.CheckedInAt,
-		"checked_out_at":      i.CheckedOutAt,
-		"stay_frequency":      i.StayFrequency,
-		"status":              i.Status,
-		"unit_id":             i.UnitID,
-		"unit":                DBUnitToRest(&i.Unit),
-		"property_id":         i.PropertyID,
-		"tenant_id":           i.TenantID,
-		"tenant":              DBTenantToRest(&i.Tenant),
-		"property":            DBPublicPropertyToRest(&i.Property),
-		"canceled_at":         i.CanceledAt,
-		"cancellation_reason": i.CancellationReason,
-		"invoice_id":          bookingInvoiceID(i),
-		"invoice":             DBInvoiceToRest(i.Invoice),
-		"meta":                i.Meta,
-		"created_at":          i.CreatedAt,
+		"id":                     i.ID.String(),
+		"code":                   i.Code,
+		"check_in_code":          i.CheckInCode,
+		"check_in_date":          i.CheckInDate,
+		"check_out_date":         i.CheckOutDate,
+		"adults":                 i.Adults,
+		"children":               i.Children,
+		"confirmed_at":           i.ConfirmedAt,
+		"checked_in_at":          i.CheckedInAt,
+		"checked_out_at":         i.CheckedOutAt,
+		"stay_frequency":         i.StayFrequency,
+		"status":                 i.Status,
+		"hold_expires_at":        i.HoldExpiresAt,
+		"unit_id":                i.UnitID,
+		"unit":                   DBUnitToRest(&i.Unit),
+		"property_id":            i.PropertyID,
+		"tenant_id":              i.TenantID,
+		"tenant":                 DBTenantToRest(&i.Tenant),
+		"property":               DBPublicPropertyToRest(&i.Property),
+		"canceled_at":            i.CanceledAt,
+		"cancellation_reason":    i.CancellationReason,
+		"cancellation_policy":    BookingCancellationPolicyToRest(i.CancellationPolicy),
+		"refund":                 DBBookingRefundToRest(i.Refund),
+		"invoice_id":             bookingInvoiceID(i),
+		"invoice":                DBInvoiceToRest(i.Invoice),
+		"supplementary_invoices": bookingInvoicesToRest(i.SupplementaryInvoices),
+		"meta":                   i.Meta,
+		"created_at":             i.CreatedAt,
 	}
 	return data
 }
@@ -0,0 +1,85 @@
+package transformations
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+)
+
+type OutputCashFlowForecastBucket struct {
+	StartDate         time.Time `json:"start_date"`
+	EndDate           time.Time `json:"end_date"`
+	ExpectedInflow    int64     `json:"expected_inflow"    example:"1200000"`
+	WeightedInflow    int64     `json:"weighted_inflow"    example:"1050000"`
+	ScheduledExpenses int64     `json:"scheduled_expenses" example:"150000"`
+	NetCashFlow       int64     `json:"net_cash_flow"      example:"900000"`
+}
+
+type OutputCashFlowForecast struct {
+	ReportingCurrency string                         `json:"reporting_currency" example:"GHS"`
+	Interval          string                         `json:"interval"           example:"MONTH"`
+	StartDate         time.Time                      `json:"start_date"`
+	EndDate           time.Time                      `json:"end_date"`
+	Buckets           []OutputCashFlowForecastBucket `json:"buckets"`
+}
+
+func CashFlowForecastToRest(f *services.CashFlowForecast) OutputCashFlowForecast {
+	buckets := make([]OutputCashFlowForecastBucket, 0, len(f.Buckets))
+	for _, bucket := range f.Buckets {
+		buckets = append(buckets, OutputCashFlowForecastBucket{
+			StartDate:         bucket.StartDate,
+			EndDate:           bucket.EndDate,
+			ExpectedInflow:    bucket.ExpectedInflow,
+			WeightedInflow:    bucket.WeightedInflow,
+			ScheduledExpenses: bucket.ScheduledExpenses,
+			NetCashFlow:       bucket.NetCashFlow,
+		})
+	}
+
+	return OutputCashFlowForecast{
+		ReportingCurrency: f.ReportingCurrency,
+		Interval:          f.Interval,
+		StartDate:         f.StartDate,
+		EndDate:           f.EndDate,
+		Buckets:           buckets,
+	}
+}
+
+// WriteCashFlowForecastCSV renders one row per bucket. Amounts stay in minor
+// units, as everywhere else in the API, so a spreadsheet total reconciles to
+// the JSON to the pesewa.
+func WriteCashFlowForecastCSV(w io.Writer, f *services.CashFlowForecast) error {
+	writer := csv.NewWriter(w)
+
+	if err := writer.Write([]string{
+		"bucket_start",
+		"bucket_end",
+		"currency",
+		"expected_inflow",
+		"weighted_inflow",
+		"scheduled_expenses",
+		"net_cash_flow",
+	}); err != nil {
+		return err
+	}
+
+	for _, bucket := range f.Buckets {
+		if err := writer.Write([]string{
+			bucket.StartDate.Format("2006-01-02"),
+			bucket.EndDate.Format("2006-01-02"),
+			f.ReportingCurrency,
+			strconv.FormatInt(bucket.ExpectedInflow, 10),
+			strconv.FormatInt(bucket.WeightedInflow, 10),
+			strconv.FormatInt(bucket.ScheduledExpenses, 10),
+			strconv.FormatInt(bucket.NetCashFlow, 10),
+		}); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
@@ -8,24 +8,27 @@ import (
 )
 
 type OutputClient struct {
-	ID                  string                   `json:"id"                    example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
-	Type                string                   `json:"type"                  example:"INDIVIDUAL"`
-	SubType             string                   `json:"sub_type"              example:"LANDLORD"`
-	Name                string                   `json:"name"                  example:"Acme Corp"`
-	Address             string                   `json:"address"               example:"123 Main St, Suite 100"`
-	Country             string                   `json:"country"               example:"US"`
-	Region              string                   `json:"region"                example:"California"`
-	City                string                   `json:"city"                  example:"San Francisco"`
-	Latitude            float64                  `json:"latitude"              example:"37.7749"`
-	Longitude           float64                  `json:"longitude"             example:"-122.4194"`
-	WebsiteUrl          *string                  `json:"website_url"           example:"https://www.somewebiste.com"`
-	LogoURL             *string                  `json:"logo_url"              example:"https://www.somewebiste.com/logo.png"`
-	SupportPhone        *string                  `json:"support_phone"         example:"+233551235555"`
-	SupportEmail        *string                  `json:"support_email"         example:"support@somewebiste.com"`
-	ClientApplicationId string                   `json:"client_application_id" example:"app-1234"`
-	ClientApplication   *OutputClientApplication `json:"client_application"`
-	CreatedAt           time.Time                `json:"created_at"            example:"2023-01-01T00:00:00Z"`
-	UpdatedAt           time.Time                `json:"updated_at"            example:"2023-01-01T00:00:00Z"`
+	ID                     string                   `json:"id"                        example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Type                   string                   `json:"type"                      example:"INDIVIDUAL"`
+	SubType                string                   `json:"sub_type"                  example:"LANDLORD"`
+	Name                   string                   `json:"name"                      example:"Acme Corp"`
+	Address                string                   `json:"address"                   example:"123 Main St, Suite 100"`
+	Country                string                   `json:"country"                   example:"US"`
+	Region                 string                   `json:"region"                    example:"California"`
+	City                   string                   `json:"city"                      example:"San Francisco"`
+	Latitude               float64                  `json:"latitude"                  example:"37.7749"`
+	Longitude              float64                  `json:"longitude"                 example:"-122.4194"`
+	WebsiteUrl             *string                  `json:"website_url"               example:"https://www.somewebiste.com"`
+	LogoURL                *string                  `json:"logo_url"                  example:"https://www.somewebiste.com/logo.png"`
+	SupportPhone           *string                  `json:"support_phone"             example:"+233551235555"`
+	SupportEmail           *string                  `json:"support_email"             example:"support@somewebiste.com"`
+	SigningOtpRequired     bool                     `json:"signing_otp_required"      example:"false"`
+	SigningOtpDocumentTags []string                 `json:"signing_otp_document_tags" example:"LEASE_AGREEMENT"`
+	PublicListingEnabled   bool                     `json:"public_listing_enabled"    example:"false"`
+	ClientApplicationId    string                   `json:"client_application_id"     example:"app-1234"`
+	ClientApplication      *OutputClientApplication `json:"client_application"`
+	CreatedAt              time.Time                `json:"created_at"                example:"2023-01-01T00:00:00Z"`
+	UpdatedAt              time.Time                `json:"updated_at"                example:"2023-01-01T00:00:00Z"`
 }
 
 func DBClientToRestClient(i *models.Client) interface{} {
@@ -34,31 +37,34 @@ func DBClientToRestClient(i *models.Client) interface{} {
 	}
 
 	data := map[string]interface{}{
-		"id":                    i.ID.String(),
-		"type":                  i.Type,
-		"sub_type":              i.SubType,
-		"name":                  i.Name,
-		"address":               i.Address,
-		"country":               i.Country,
-		"region":                i.Region,
-		"city":                  i.City,
-		"latitude":              i.Latitude,
-		"longitude":             i.Longitude,
-		"description":           i.Description,
-		"registration_number":   i.RegistrationNumber,
-		"logo_url":              i.LogoURL,
-		"website_url":           i.WebsiteUrl,
-		"support_phone":         i.SupportPhone,
-		"support_email":         i.SupportEmail,
-		"id_type":               i.IDType,
-		"id_number":             i.IDNumber,
-		"id_expiry":             i.IDExpiry,
-		"id_document_url":       i.IDDocumentURL,
-		"currency":              i.Currency,
-		"client_application_id": i.ClientApplicationId,
-		"client_application":    DBClientApplicationToRestClientApplication(&i.ClientApplication),
-		"created_at":            i.CreatedAt,
-		"updated_at":            i.UpdatedAt,
+		"id":                        i.ID.String(),
+		"type":                      i.Type,
+		"sub_type":                  i.SubType,
+		"name":                      i.Name,
+		"address":                   i.Address,
+		"country":                   i.Country,
+		"region":                    i.Region,
+		"city":                      i.City,
+		"latitude":                  i.Latitude,
+		"longitude":                 i.Longitude,
+		"description":               i.Description,
+		"registration_number":       i.RegistrationNumber,
+		"logo_url":                  i.LogoURL,
+		"website_url":               i.WebsiteUrl,
+		"support_phone":             i.SupportPhone,
+		"support_email":             i.SupportEmail,
+		"id_type":                   i.IDType,
+		"id_number":                 i.IDNumber,
+		"id_expiry":                 i.IDExpiry,
+		"id_document_url":           i.IDDocumentURL,
+		"currency":                  i.Currency,
+		"signing_otp_required":      i.SigningOtpRequired,
+		"signing_otp_document_tags": i.SigningOtpDocumentTags,
+		"public_listing_enabled":    i.PublicListingEnabled,
+		"client_application_id":     i.ClientApplicationId,
+		"client_application":        DBClientApplicationToRestClientApplication(&i.ClientApplication),
+		"created_at":                i.CreatedAt,
+		"updated_at":                i.UpdatedAt,
 	}
 	return data
 }
@@ -0,0 +1,84 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+)
+
+type OutputAdminDocumentRevision struct {
+	ID             string            `json:"id"                         example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	DocumentID     string            `json:"document_id"                example:"550e8400-e29b-41d4-a716-446655440000"`
+	Number         int64             `json:"number"                     example:"3"`
+	Title          string            `json:"title"                      example:"Lease Agreement"`
+	Content        string            `json:"content"`
+	ContentHash    string            `json:"content_hash"               example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Source         string            `json:"source"                     example:"UPDATED"`
+	RestoredFromID *string           `json:"restored_from_id,omitempty" example:"c290f1ee-6c54-4b01-90e6-d701748f0852"`
+	CreatedByID    *string           `json:"created_by_id,omitempty"    example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CreatedBy      *OutputClientUser `json:"created_by,omitempty"`
+	CreatedAt      time.Time         `json:"created_at"                 example:"2023-01-01T00:00:00Z"`
+}
+
+// DBDocumentRevisionToRest transforms the db document revision model to a rest model
+func DBDocumentRevisionToRest(i *models.DocumentRevision) any {
+	if i == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":               i.ID.String(),
+		"document_id":      i.DocumentID,
+		"number":           i.Number,
+		"title":            i.Title,
+		"content":          string(i.Content),
+		"content_hash":     i.ContentHash,
+		"source":           i.Source,
+		"restored_from_id": i.RestoredFromID,
+		"created_by_id":    i.CreatedByID,
+		"created_by":       DBClientUserToRest(i.CreatedBy),
+		"created_at":       i.CreatedAt,
+	}
+}
+
+type OutputDocumentNodeChange struct {
+	Op       string  `json:"op"                  example:"CHANGED"`
+	NodeType string  `json:"node_type"           example:"paragraph"`
+	FromPath []int   `json:"from_path,omitempty" example:"2,0"`
+	ToPath   []int   `json:"to_path,omitempty"   example:"3,0"`
+	FromText *string `json:"from_text,omitempty" example:"Rent is due on the 1st."`
+	ToText   *string `json:"to_text,omitempty"   example:"Rent is due on the 5th."`
+}
+
+type OutputDocumentRevisionDiff struct {
+	From         OutputAdminDocumentRevision `json:"from"`
+	To           OutputAdminDocumentRevision `json:"to"`
+	TitleChanged bool                        `json:"title_changed" example:"false"`
+	Changes      []OutputDocumentNodeChange  `json:"changes"`
+}
+
+func DocumentRevisionDiffToRest(d *services.DocumentRevisionDiff) any {
+	if d == nil {
+		return nil
+	}
+
+	changes := make([]any, 0, len(d.Changes))
+	for _, change := range d.Changes {
+		changes = append(changes, map[string]any{
+			"op":        change.Op,
+			"node_type": change.NodeType,
+			"from_path": change.FromPath,
+			"to_path":   change.ToPath,
+			"from_text": change.FromText,
+			"to_text":   change.ToText,
+		})
+	}
+
+	return map[string]any{
+		"from":          DBDocumentRevisionToRest(d.From),
+		"to":            DBDocumentRevisionToRest(d.To),
+		"title_changed": d.TitleChanged,
+		"changes":       changes,
+	}
+}
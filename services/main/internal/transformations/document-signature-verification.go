@@ -0,0 +1,52 @@
+package transformations
+
+import (
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+)
+
+type OutputDocumentSignatureCheck struct {
+	Signature      OutputAdminDocumentSignature `json:"signature"`
+	EventHashValid bool                         `json:"event_hash_valid" example:"true"`
+	ContentMatches bool                         `json:"content_matches"  example:"true"`
+}
+
+type OutputDocumentSignatureVerification struct {
+	DocumentID   string                         `json:"document_id"              example:"550e8400-e29b-41d4-a716-446655440000"`
+	ContentHash  string                         `json:"content_hash"             example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	ChainIntact  bool                           `json:"chain_intact"             example:"true"`
+	Signatures   []OutputDocumentSignatureCheck `json:"signatures"`
+	PdfHash      *string                        `json:"pdf_hash,omitempty"       example:"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"`
+	PdfMatches   *bool                          `json:"pdf_matches,omitempty"    example:"true"`
+	PdfMatchedBy *string                        `json:"pdf_matched_by,omitempty" example:"LEASE_AGREEMENT"`
+}
+
+func DocumentSignatureVerificationToRest(v *services.DocumentSignatureVerification) any {
+	if v == nil {
+		return nil
+	}
+
+	signatures := make([]any, 0, len(v.Signatures))
+	for _, check := range v.Signatures {
+		signatures = append(signatures, map[string]any{
+			"signature":        DBAdminDocumentSignatureToRest(&check.Signature),
+			"event_hash_valid": check.EventHashValid,
+			"content_matches":  check.ContentMatches,
+		})
+	}
+
+	var pdfMatches *bool
+	if v.PdfHash != nil {
+		matches := v.PdfMatchedBy != nil
+		pdfMatches = &matches
+	}
+
+	return map[string]any{
+		"document_id":    v.DocumentID,
+		"content_hash":   v.ContentHash,
+		"chain_intact":   v.ChainIntact,
+		"signatures":     signatures,
+		"pdf_hash":       v.PdfHash,
+		"pdf_matches":    pdfMatches,
+		"pdf_matched_by": v.PdfMatchedBy,
+	}
+}
@@ -10,19 +10,27 @@ import (
 type OutputAdminDocumentSignature struct {
 	ID                  string               `json:"id"                              example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	DocumentID          string               `json:"document_id"                     example:"550e8400-e29b-41d4-a716-446655440000"`
+	DocumentRevisionID  *string              `json:"document_revision_id,omitempty"  example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	Document            *OutputAdminDocument `json:"document,omitempty"`
 	TenantApplicationID *string              `json:"tenant_application_id,omitempty" example:"660e8400-e29b-41d4-a716-446655440000"`
 	// TenantApplication   *OutputAdminTenantApplication `json:"tenant_application,omitempty"`
 	LeaseID *string `json:"lease_id,omitempty"              example:"770e8400-e29b-41d4-a716-446655440000"`
 	// Lease               *OutputAdminLease             `json:"lease,omitempty"`
-	Role         string            `json:"role"                            example:"TENANT"`
-	SignatureUrl string            `json:"signature_url"                   example:"https://s3.amazonaws.com/signatures/sig.png"`
-	SignedByName *string           `json:"signed_by_name,omitempty"        example:"John Doe"`
-	SignedByID   *string           `json:"signed_by_id,omitempty"          example:"880e8400-e29b-41d4-a716-446655440000"`
-	SignedBy     *OutputClientUser `json:"signed_by,omitempty"`
-	IPAddress    string            `json:"ip_address"                      example:"192.168.1.1"`
-	CreatedAt    time.Time         `json:"created_at"                      example:"2024-06-01T09:00:00Z"`
-	UpdatedAt    time.Time         `json:"updated_at"                      example:"2024-06-10T09:00:00Z"`
+	TenantID           *string           `json:"tenant_id,omitempty"             example:"990e8400-e29b-41d4-a716-446655440000"`
+	GuarantorID        *string           `json:"guarantor_id,omitempty"          example:"aa1e8400-e29b-41d4-a716-446655440000"`
+	Role               string            `json:"role"                            example:"TENANT"`
+	SignatureUrl       string            `json:"signature_url"                   example:"https://s3.amazonaws.com/signatures/sig.png"`
+	SignedByName       *string           `json:"signed_by_name,omitempty"        example:"John Doe"`
+	SignedByID         *string           `json:"signed_by_id,omitempty"          example:"880e8400-e29b-41d4-a716-446655440000"`
+	SignedBy           *OutputClientUser `json:"signed_by,omitempty"`
+	IPAddress          string            `json:"ip_address"                      example:"192.168.1.1"`
+	UserAgent          *string           `json:"user_agent,omitempty"            example:"Mozilla/5.0"`
+	VerificationMethod string            `json:"verification_method"             example:"OTP_SMS"`
+	ContentHash        string            `json:"content_hash"                    example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	PreviousEventHash  *string           `json:"previous_event_hash,omitempty"   example:"2c26b46b68ffc68ff99b453c1d30413413422d706483bfa0f98a5e886266e7ae"`
+	EventHash          string            `json:"event_hash"                      example:"fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"`
+	CreatedAt          time.Time         `json:"created_at"                      example:"2024-06-01T09:00:00Z"`
+	UpdatedAt          time.Time         `json:"updated_at"                      example:"2024-06-10T09:00:00Z"`
 }
 
 func DBAdminDocumentSignatureToRest(i *models.DocumentSignature) any {
@@ -42,22 +50,31 @@ func DBAdminDocumentSignatureToRest(i *models.DocumentSignature) any {
 	data := map[string]any{
 		"id":                    i.ID.String(),
 		"document_id":           i.DocumentID,
+		"document_revision_id":  i.DocumentRevisionID,
 		"document":              DBAdminDocumentToRestDocument(&i.Document),
 		"tenant_application_id": i.TenantApplicationID,
 		// "tenant_application":    tenantApplication,
 		"lease_id": i.LeaseID,
 		// "lease":                 lease,
 		"lease_termination_id":        i.LeaseTerminationID,
+		"lease_amendment_id":          i.LeaseAmendmentID,
+		"tenant_id":                   i.TenantID,
+		"guarantor_id":                i.GuarantorID,
 		"lease_agreement_document_id": i.LeaseAgreementDocumentID,
 		// "lease_termination":     DBAdminLeaseTerminationToRest(i.LeaseTermination),
-		"role":           i.Role,
-		"signature_url":  i.SignatureUrl,
-		"signed_by_name": i.SignedByName,
-		"signed_by_id":   i.SignedByID,
-		"signed_by":      DBClientUserToRest(i.SignedBy),
-		"ip_address":     i.IPAddress,
-		"created_at":     i.CreatedAt,
-		"updated_at":     i.UpdatedAt,
+		"role":                i.Role,
+		"signature_url":       i.SignatureUrl,
+		"signed_by_name":      i.SignedByName,
+		"signed_by_id":        i.SignedByID,
+		"signed_by":           DBClientUserToRest(i.SignedBy),
+		"ip_address":          i.IPAddress,
+		"user_agent":          i.UserAgent,
+		"verification_method": i.VerificationMethod,
+		"content_hash":        i.ContentHash,
+		"previous_event_hash": i.PreviousEventHash,
+		"event_hash":          i.EventHash,
+		"created_at":          i.CreatedAt,
+		"updated_at":          i.UpdatedAt,
 	}
 
 	return data
@@ -79,6 +96,7 @@ func DBAdminDocumentSignaturesToRest(i *[]models.DocumentSignature) any {
 type OutputDocumentSignature struct {
 	ID                  string                   `json:"id"                              example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	DocumentID          string                   `json:"document_id"                     example:"550e8400-e29b-41d4-a716-446655440000"`
+	DocumentRevisionID  *string                  `json:"document_revision_id,omitempty"  example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	Document            *OutputDocument          `json:"document,omitempty"`
 	TenantApplicationID *string                  `json:"tenant_application_id,omitempty" example:"660e8400-e29b-41d4-a716-446655440000"`
 	TenantApplication   *OutputTenantApplication `json:"tenant_application,omitempty"`
@@ -86,10 +104,17 @@ type OutputDocumentSignature struct {
 	Lease               *OutputLease             `json:"lease,omitempty"`
 	LeaseTerminationID  *string                  `json:"lease_termination_id,omitempty"  example:"880e8400-e29b-41d4-a716-446655440000"`
 	LeaseTermination    *OutputLeaseTermination  `json:"lease_termination,omitempty"`
+	LeaseAmendmentID    *string                  `json:"lease_amendment_id,omitempty"    example:"aa0e8400-e29b-41d4-a716-446655440000"`
+	LeaseAmendment      *OutputLeaseAmendment    `json:"lease_amendment,omitempty"`
+	TenantID            *string                  `json:"tenant_id,omitempty"             example:"990e8400-e29b-41d4-a716-446655440000"`
+	GuarantorID         *string                  `json:"guarantor_id,omitempty"          example:"aa1e8400-e29b-41d4-a716-446655440000"`
 	Role                string                   `json:"role"                            example:"TENANT"`
 	SignatureUrl        string                   `json:"signature_url"                   example:"https://s3.amazonaws.com/signatures/sig.png"`
 	SignedByName        *string                  `json:"signed_by_name,omitempty"        example:"John Doe"`
 	IPAddress           string                   `json:"ip_address"                      example:"192.168.1.1"`
+	VerificationMethod  string                   `json:"verification_method"             example:"OTP_SMS"`
+	ContentHash         string                   `json:"content_hash"                    example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	EventHash           string                   `json:"event_hash"                      example:"fcde2b2edba56bf408601fb721fe9b5c338d10ee429ea04fae5511b68fbf8fb9"`
 	CreatedAt           time.Time                `json:"created_at"                      example:"2024-06-01T09:00:00Z"`
 	UpdatedAt           time.Time                `json:"updated_at"                      example:"2024-06-10T09:00:00Z"`
 }
@@ -102,6 +127,7 @@ func DBDocumentSignatureToRest(i *models.DocumentSignature) any {
 	data := map[string]any{
 		"id":                          i.ID.String(),
 		"document_id":                 i.DocumentID,
+		"document_revision_id":        i.DocumentRevisionID,
 		"document":                    DBDocumentToRestDocument(&i.Document),
 		"tenant_application_id":       i.TenantApplicationID,
 		"tenant_application":          DBTenantApplicationToRest(i.TenantApplication),
@@ -110,10 +136,17 @@ func DBDocumentSignatureToRest(i *models.DocumentSignature) any {
 		"lease_termination_id":        i.LeaseTerminationID,
 		"lease_agreement_document_id": i.LeaseAgreementDocumentID,
 		"lease_termination":           DBLeaseTerminationToRest(i.LeaseTermination),
+		"lease_amendment_id":          i.LeaseAmendmentID,
+		"tenant_id":                   i.TenantID,
+		"guarantor_id":                i.GuarantorID,
+		"lease_amendment":             DBLeaseAmendmentToRest(i.LeaseAmendment),
 		"role":                        i.Role,
 		"signature_url":               i.SignatureUrl,
 		"signed_by_name":              i.SignedByName,
 		"ip_address":                  i.IPAddress,
+		"verification_method":         i.VerificationMethod,
+		"content_hash":                i.ContentHash,
+		"event_hash":                  i.EventHash,
 		"created_at":                  i.CreatedAt,
 		"updated_at":                  i.UpdatedAt,
 	}
@@ -7,17 +7,19 @@ import (
 )
 
 type OutputExpense struct {
-	ID                          string    `json:"id"`
-	Code                        string    `json:"code"`
-	ContextType                 string    `json:"context_type"`
-	PropertyID                  string    `json:"property_id"`
-	ContextMaintenanceRequestID string    `json:"context_maintenance_request_id"`
-	Description                 string    `json:"description"`
-	Amount                      float64   `json:"amount"`
-	Currency                    string    `json:"currency"`
-	CreatedByClientUserID       *string   `json:"created_by_client_user_id,omitempty"`
-	CreatedAt                   time.Time `json:"created_at"`
-	UpdatedAt                   time.Time `json:"updated_at"`
+	ID                          string     `json:"id"`
+	Code                        string     `json:"code"`
+	ContextType                 string     `json:"context_type"`
+	PropertyID                  string     `json:"property_id"`
+	ContextMaintenanceRequestID string     `json:"context_maintenance_request_id"`
+	Description                 string     `json:"description"`
+	Amount                      float64    `json:"amount"`
+	Currency                    string     `json:"currency"`
+	ScheduledFor                *time.Time `json:"scheduled_for,omitempty"`
+	PaidAt                      *time.Time `json:"paid_at,omitempty"`
+	CreatedByClientUserID       *string    `json:"created_by_client_user_id,omitempty"`
+	CreatedAt                   time.Time  `json:"created_at"`
+	UpdatedAt                   time.Time  `json:"updated_at"`
 }
 
 // DBExpenseToRest transforms an Expense model to REST.
@@ -35,6 +37,8 @@ func DBExpenseToRest(e *models.Expense) any {
 		"description":                    e.Description,
 		"amount":                         e.Amount,
 		"currency":                       e.Currency,
+		"scheduled_for":                  e.ScheduledFor,
+		"paid_at":                        e.PaidAt,
 		"created_by_client_user_id":      e.CreatedByClientUserID,
 		"created_at":                     e.CreatedAt,
 		"updated_at":                     e.UpdatedAt,
@@ -130,6 +130,13 @@ func DBFinancialAccountToRest(m *models.FinancialAccount) *OutputFinancialAccoun
 	}
 }
 
+// OutputPayerPayment is what one tenant has paid into a shared account. A nil
+// payer_tenant_id is the total nobody was attributed to.
+type OutputPayerPayment struct {
+	PayerTenantID *string `json:"payer_tenant_id" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Amount        int64   `json:"amount"          example:"250000"`
+}
+
 // OutputTenantApplicationFinancials is the application's financial summary.
 //
 // It replaces the old `application_payment_invoice` field. An application now
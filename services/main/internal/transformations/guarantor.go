@@ -0,0 +1,81 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputGuarantor struct {
+	ID                  string  `json:"id"                              example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	TenantApplicationID *string `json:"tenant_application_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	LeaseID             *string `json:"lease_id,omitempty"              example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+
+	FirstName            string  `json:"first_name"                       example:"Kwame"`
+	LastName             string  `json:"last_name"                        example:"Mensah"`
+	Email                *string `json:"email,omitempty"                  example:"kwame@example.com"`
+	Phone                string  `json:"phone"                            example:"+233281234569"`
+	RelationshipToTenant *string `json:"relationship_to_tenant,omitempty" example:"Parent"`
+
+	IDType     *string `json:"id_type,omitempty"      example:"NationalID"`
+	IDNumber   *string `json:"id_number,omitempty"    example:"GHA-123456789-0"`
+	IDFrontUrl *string `json:"id_front_url,omitempty" example:"https://example.com/id-front.jpg"`
+	IDBackUrl  *string `json:"id_back_url,omitempty"  example:"https://example.com/id-back.jpg"`
+
+	Occupation       *string `json:"occupation,omitempty"          example:"Civil Servant"`
+	Employer         *string `json:"employer,omitempty"            example:"Ghana Education Service"`
+	ProofOfIncomeUrl *string `json:"proof_of_income_url,omitempty" example:"https://example.com/payslip.pdf"`
+
+	// LiabilityCap is absent for an unlimited guarantee.
+	LiabilityCap         *int64  `json:"liability_cap,omitempty"          example:"1200000"`
+	LiabilityCapCurrency *string `json:"liability_cap_currency,omitempty" example:"GHS"`
+	// ArrearsEscalationDays is absent when the guarantor is never chased.
+	ArrearsEscalationDays *int64 `json:"arrears_escalation_days,omitempty" example:"7"`
+
+	CreatedById string    `json:"created_by_id" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	CreatedAt   time.Time `json:"created_at"    example:"2024-06-01T09:00:00Z"`
+	UpdatedAt   time.Time `json:"updated_at"    example:"2024-06-10T09:00:00Z"`
+}
+
+func DBGuarantorToRest(i *models.Guarantor) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                      i.ID,
+		"tenant_application_id":   i.TenantApplicationID,
+		"lease_id":                i.LeaseID,
+		"first_name":              i.FirstName,
+		"last_name":               i.LastName,
+		"email":                   i.Email,
+		"phone":                   i.Phone,
+		"relationship_to_tenant":  i.RelationshipToTenant,
+		"id_type":                 i.IDType,
+		"id_number":               i.IDNumber,
+		"id_front_url":            i.IDFrontUrl,
+		"id_back_url":             i.IDBackUrl,
+		"occupation":              i.Occupation,
+		"employer":                i.Employer,
+		"proof_of_income_url":     i.ProofOfIncomeUrl,
+		"liability_cap":           i.LiabilityCap,
+		"liability_cap_currency":  i.LiabilityCapCurrency,
+		"arrears_escalation_days": i.ArrearsEscalationDays,
+		"created_by_id":           i.CreatedById,
+		"created_at":              i.CreatedAt,
+		"updated_at":              i.UpdatedAt,
+	}
+}
+
+func dbGuarantorsToRest(guarantors []models.Guarantor) []any {
+	if guarantors == nil {
+		return nil
+	}
+
+	rows := make([]any, 0, len(guarantors))
+	for i := range guarantors {
+		rows = append(rows, DBGuarantorToRest(&guarantors[i]))
+	}
+	return rows
+}
@@ -57,6 +57,11 @@ type OutputInvoice struct {
 	VoidedByClientUserID *string           `json:"voided_by_client_user_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	VoidedByClientUser   *OutputClientUser `json:"voided_by_client_user,omitempty"`
 
+	WrittenOffAmount         int64      `json:"written_off_amount"                      example:"0"`
+	WrittenOffAt             *time.Time `json:"written_off_at,omitempty"                example:"2024-06-25T00:00:00Z"`
+	WrittenOffReason         *string    `json:"written_off_reason,omitempty"            example:"Booking #BK123 was cancelled"`
+	WrittenOffByClientUserID *string    `json:"written_off_by_client_user_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+
 	AllowedPaymentRails []string `json:"allowed_payment_rails" example:"MOMO,BANK"`
 
 	LineItems []OutputInvoiceLineItem `json:"line_items"`
@@ -107,6 +112,10 @@ func DBInvoiceToRest(i *models.Invoice) any {
 		"voided_reason":                  i.VoidedReason,
 		"voided_by_client_user_id":       i.VoidedByClientUserID,
 		"voided_by_client_user":          DBClientUserToRest(i.VoidedByClientUser),
+		"written_off_amount":             i.WrittenOffAmount,
+		"written_off_at":                 i.WrittenOffAt,
+		"written_off_reason":             i.WrittenOffReason,
+		"written_off_by_client_user_id":  i.WrittenOffByClientUserID,
 		"allowed_payment_rails":          []string(i.AllowedPaymentRails),
 		"line_items":                     DBInvoiceLineItemsToRest(i.LineItems),
 		"payments":                       DBPaymentsToRest(&i.Payments),
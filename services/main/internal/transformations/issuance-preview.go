@@ -0,0 +1,69 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+)
+
+type OutputIssuancePreviewLine struct {
+	ChargeInstanceID string    `json:"charge_instance_id" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Label            string    `json:"label"              example:"Rent – February 2027"`
+	Category         string    `json:"category"           example:"RENT"`
+	Amount           int64     `json:"amount"             example:"100000"`
+	Currency         string    `json:"currency"           example:"GHS"`
+	DueDate          time.Time `json:"due_date"`
+}
+
+type OutputIssuancePreviewRecipient struct {
+	TenantID string   `json:"tenant_id"`
+	Name     string   `json:"name"            example:"Ama Mensah"`
+	Email    *string  `json:"email,omitempty" example:"ama@example.com"`
+	Phone    string   `json:"phone"           example:"+233201080802"`
+	Channels []string `json:"channels"        example:"PUSH,SMS,EMAIL"`
+}
+
+type OutputIssuancePreview struct {
+	FinancialAccount *OutputFinancialAccount         `json:"financial_account"`
+	PayerLeaseID     *string                         `json:"payer_lease_id,omitempty"`
+	DueDate          time.Time                       `json:"due_date"`
+	Currency         string                          `json:"currency"                 example:"GHS"`
+	TotalAmount      int64                           `json:"total_amount"             example:"100000"`
+	LineItems        []OutputIssuancePreviewLine     `json:"line_items"`
+	Recipient        *OutputIssuancePreviewRecipient `json:"recipient,omitempty"`
+}
+
+func IssuancePreviewToRest(p services.IssuancePreview) OutputIssuancePreview {
+	lines := make([]OutputIssuancePreviewLine, 0, len(p.LineItems))
+	for _, line := range p.LineItems {
+		lines = append(lines, OutputIssuancePreviewLine{
+			ChargeInstanceID: line.ChargeInstanceID,
+			Label:            line.Label,
+			Category:         line.Category,
+			Amount:           line.Amount,
+			Currency:         line.Currency,
+			DueDate:          line.DueDate,
+		})
+	}
+
+	var recipient *OutputIssuancePreviewRecipient
+	if p.Recipient != nil {
+		recipient = &OutputIssuancePreviewRecipient{
+			TenantID: p.Recipient.TenantID,
+			Name:     p.Recipient.Name,
+			Email:    p.Recipient.Email,
+			Phone:    p.Recipient.Phone,
+			Channels: p.Recipient.Channels,
+		}
+	}
+
+	return OutputIssuancePreview{
+		FinancialAccount: DBFinancialAccountToRest(p.Account),
+		PayerLeaseID:     p.PayerLeaseID,
+		DueDate:          p.DueDate,
+		Currency:         p.Currency,
+		TotalAmount:      p.TotalAmount,
+		LineItems:        lines,
+		Recipient:        recipient,
+	}
+}
@@ -0,0 +1,135 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputLeaseAmendmentCharge struct {
+	Name     string `json:"name"     example:"Parking"`
+	Category string `json:"category" example:"OTHER"`
+	Amount   int64  `json:"amount"   example:"15000"`
+}
+
+type OutputLeaseAmendment struct {
+	ID      string `json:"id"       example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Code    string `json:"code"     example:"2610ABC123"`
+	LeaseID string `json:"lease_id" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+
+	Reason        string    `json:"reason"         example:"Rent reduced while the kitchen is refitted"`
+	Status        string    `json:"status"         example:"LeaseAmendment.Status.Draft"`
+	EffectiveDate time.Time `json:"effective_date" example:"2026-11-01T00:00:00Z"`
+
+	RentFee               *int64                       `json:"rent_fee,omitempty"                example:"450000"`
+	StayDuration          *int64                       `json:"stay_duration,omitempty"           example:"18"`
+	StayDurationFrequency *string                      `json:"stay_duration_frequency,omitempty" example:"MONTHLY"`
+	AddedCharges          []OutputLeaseAmendmentCharge `json:"added_charges"`
+
+	DocumentMode *string `json:"document_mode,omitempty" example:"ONLINE"`
+	DocumentUrl  *string `json:"document_url,omitempty"  example:"https://example.com/amendment.pdf"`
+	DocumentID   *string `json:"document_id,omitempty"   example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+
+	Version                       *int64     `json:"version,omitempty"                          example:"2"`
+	PreviousRentFee               *int64     `json:"previous_rent_fee,omitempty"                example:"500000"`
+	PreviousStayDuration          *int64     `json:"previous_stay_duration,omitempty"           example:"12"`
+	PreviousStayDurationFrequency *string    `json:"previous_stay_duration_frequency,omitempty" example:"MONTHLY"`
+	PreviousMoveOutDate           *time.Time `json:"previous_move_out_date,omitempty"           example:"2027-01-01T00:00:00Z"`
+
+	CreatedAt time.Time `json:"created_at" example:"2024-06-01T09:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-06-10T09:00:00Z"`
+}
+
+type OutputAdminLeaseAmendment struct {
+	OutputLeaseAmendment
+
+	InitiatedById string            `json:"initiated_by_id"        example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
+	InitiatedBy   *OutputClientUser `json:"initiated_by,omitempty"`
+
+	ExecutedAt   *time.Time        `json:"executed_at,omitempty"    example:"2024-12-01T10:00:00Z"`
+	ExecutedById *string           `json:"executed_by_id,omitempty" example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
+	ExecutedBy   *OutputClientUser `json:"executed_by,omitempty"`
+
+	CancelledAt   *time.Time        `json:"cancelled_at,omitempty"    example:"2024-12-01T10:00:00Z"`
+	CancelledById *string           `json:"cancelled_by_id,omitempty" example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
+	CancelledBy   *OutputClientUser `json:"cancelled_by,omitempty"`
+}
+
+func DBAdminLeaseAmendmentToRest(a *models.LeaseAmendment) any {
+	if a == nil || a.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                               a.ID,
+		"code":                             a.Code,
+		"lease_id":                         a.LeaseID,
+		"reason":                           a.Reason,
+		"status":                           a.Status,
+		"effective_date":                   a.EffectiveDate,
+		"rent_fee":                         a.RentFee,
+		"stay_duration":                    a.StayDuration,
+		"stay_duration_frequency":          a.StayDurationFrequency,
+		"added_charges":                    leaseAmendmentChargesToRest(a.AddedCharges),
+		"document_mode":                    a.DocumentMode,
+		"document_url":                     a.DocumentUrl,
+		"document_id":                      a.DocumentID,
+		"version":                          a.Version,
+		"previous_rent_fee":                a.PreviousRentFee,
+		"previous_stay_duration":           a.PreviousStayDuration,
+		"previous_stay_duration_frequency": a.PreviousStayDurationFrequency,
+		"previous_move_out_date":           a.PreviousMoveOutDate,
+		"initiated_by_id":                  a.InitiatedById,
+		"initiated_by":                     DBClientUserToRest(&a.InitiatedBy),
+		"executed_at":                      a.ExecutedAt,
+		"executed_by_id":                   a.ExecutedById,
+		"executed_by":                      DBClientUserToRest(a.ExecutedBy),
+		"cancelled_at":                     a.CancelledAt,
+		"cancelled_by_id":                  a.CancelledById,
+		"cancelled_by":                     DBClientUserToRest(a.CancelledBy),
+		"created_at":                       a.CreatedAt,
+		"updated_at":                       a.UpdatedAt,
+	}
+}
+
+func DBLeaseAmendmentToRest(a *models.LeaseAmendment) any {
+	if a == nil || a.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                               a.ID,
+		"code":                             a.Code,
+		"lease_id":                         a.LeaseID,
+		"reason":                           a.Reason,
+		"status":                           a.Status,
+		"effective_date":                   a.EffectiveDate,
+		"rent_fee":                         a.RentFee,
+		"stay_duration":                    a.StayDuration,
+		"stay_duration_frequency":          a.StayDurationFrequency,
+		"added_charges":                    leaseAmendmentChargesToRest(a.AddedCharges),
+		"document_mode":                    a.DocumentMode,
+		"document_url":                     a.DocumentUrl,
+		"document_id":                      a.DocumentID,
+		"version":                          a.Version,
+		"previous_rent_fee":                a.PreviousRentFee,
+		"previous_stay_duration":           a.PreviousStayDuration,
+		"previous_stay_duration_frequency": a.PreviousStayDurationFrequency,
+		"previous_move_out_date":           a.PreviousMoveOutDate,
+		"created_at":                       a.CreatedAt,
+		"updated_at":                       a.UpdatedAt,
+	}
+}
+
+func leaseAmendmentChargesToRest(charges []models.LeaseAmendmentCharge) []map[string]any {
+	rows := make([]map[string]any, 0, len(charges))
+	for _, charge := range charges {
+		rows = append(rows, map[string]any{
+			"name":     charge.Name,
+			"category": charge.Category,
+			"amount":   charge.Amount,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,49 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputLeaseTenant struct {
+	ID       string             `json:"id"               example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	LeaseID  string             `json:"lease_id"         example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	TenantID string             `json:"tenant_id"        example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Tenant   *OutputAdminTenant `json:"tenant,omitempty"`
+	// Role is PRIMARY, CO_TENANT or OCCUPANT. PRIMARY and CO_TENANT sign the
+	// agreement; an OCCUPANT only lives in the unit.
+	Role string `json:"role" example:"CO_TENANT"`
+
+	CreatedAt time.Time `json:"created_at" example:"2024-06-01T09:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-06-10T09:00:00Z"`
+}
+
+func DBLeaseTenantToRest(i *models.LeaseTenant) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":         i.ID,
+		"lease_id":   i.LeaseID,
+		"tenant_id":  i.TenantID,
+		"tenant":     DBAdminTenantToRest(&i.Tenant),
+		"role":       i.Role,
+		"created_at": i.CreatedAt,
+		"updated_at": i.UpdatedAt,
+	}
+}
+
+func dbLeaseTenantsToRest(leaseTenants []models.LeaseTenant) []any {
+	if leaseTenants == nil {
+		return nil
+	}
+
+	rows := make([]any, 0, len(leaseTenants))
+	for i := range leaseTenants {
+		rows = append(rows, DBLeaseTenantToRest(&leaseTenants[i]))
+	}
+	return rows
+}
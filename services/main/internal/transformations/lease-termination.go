@@ -23,6 +23,13 @@ type OutputLeaseTermination struct {
 	DocumentUrl  *string `json:"document_url,omitempty"  example:"https://example.com/termination.pdf"`
 	DocumentID   *string `json:"document_id,omitempty"   example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 
+	InitiatedByTenantID *string    `json:"initiated_by_tenant_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	IntendedMoveOutDate *time.Time `json:"intended_move_out_date,omitempty" example:"2026-12-31T00:00:00Z"`
+	NoticePeriodDays    *int64     `json:"notice_period_days,omitempty"     example:"30"`
+
+	EarlyTerminationFee      int64   `json:"early_termination_fee"                 example:"900000"`
+	EarlyTerminationChargeID *string `json:"early_termination_charge_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+
 	CreatedAt time.Time `json:"created_at" example:"2024-06-01T09:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2024-06-10T09:00:00Z"`
 }
@@ -30,7 +37,7 @@ type OutputLeaseTermination struct {
 type OutputAdminLeaseTermination struct {
 	OutputLeaseTermination
 
-	InitiatedById string            `json:"initiated_by_id"        example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
+	InitiatedById *string           `json:"initiated_by_id,omitempty" example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
 	InitiatedBy   *OutputClientUser `json:"initiated_by,omitempty"`
 
 	CompletedAt   *time.Time        `json:"completed_at,omitempty"    example:"2024-12-01T10:00:00Z"`
@@ -48,27 +55,32 @@ func DBAdminLeaseTerminationToRest(t *models.LeaseTermination) any {
 	}
 
 	return map[string]any{
-		"id":                 t.ID,
-		"code":               t.Code,
-		"lease_id":           t.LeaseID,
-		"type":               t.Type,
-		"reason":             t.Reason,
-		"status":             t.Status,
-		"lease_checklist_id": t.LeaseChecklistID,
-		"lease_checklist":    DBLeaseChecklistToRest(t.LeaseChecklist),
-		"document_mode":      t.DocumentMode,
-		"document_url":       t.DocumentUrl,
-		"document_id":        t.DocumentID,
-		"initiated_by_id":    t.InitiatedById,
-		"initiated_by":       DBClientUserToRest(&t.InitiatedBy),
-		"completed_at":       t.CompletedAt,
-		"completed_by_id":    t.CompletedById,
-		"completed_by":       DBClientUserToRest(t.CompletedBy),
-		"cancelled_at":       t.CancelledAt,
-		"cancelled_by_id":    t.CancelledById,
-		"cancelled_by":       DBClientUserToRest(t.CancelledBy),
-		"created_at":         t.CreatedAt,
-		"updated_at":         t.UpdatedAt,
+		"id":                          t.ID,
+		"code":                        t.Code,
+		"lease_id":                    t.LeaseID,
+		"type":                        t.Type,
+		"reason":                      t.Reason,
+		"status":                      t.Status,
+		"lease_checklist_id":          t.LeaseChecklistID,
+		"lease_checklist":             DBLeaseChecklistToRest(t.LeaseChecklist),
+		"document_mode":               t.DocumentMode,
+		"document_url":                t.DocumentUrl,
+		"document_id":                 t.DocumentID,
+		"initiated_by_tenant_id":      t.InitiatedByTenantID,
+		"intended_move_out_date":      t.IntendedMoveOutDate,
+		"notice_period_days":          t.NoticePeriodDays,
+		"early_termination_fee":       t.EarlyTerminationFee,
+		"early_termination_charge_id": t.EarlyTerminationChargeID,
+		"initiated_by_id":             t.InitiatedById,
+		"initiated_by":                DBClientUserToRest(t.InitiatedBy),
+		"completed_at":                t.CompletedAt,
+		"completed_by_id":             t.CompletedById,
+		"completed_by":                DBClientUserToRest(t.CompletedBy),
+		"cancelled_at":                t.CancelledAt,
+		"cancelled_by_id":             t.CancelledById,
+		"cancelled_by":                DBClientUserToRest(t.CancelledBy),
+		"created_at":                  t.CreatedAt,
+		"updated_at":                  t.UpdatedAt,
 	}
 }
 
@@ -78,18 +90,23 @@ func DBLeaseTerminationToRest(t *models.LeaseTermination) any {
 	}
 
 	return map[string]any{
-		"id":                 t.ID,
-		"code":               t.Code,
-		"lease_id":           t.LeaseID,
-		"type":               t.Type,
-		"reason":             t.Reason,
-		"status":             t.Status,
-		"lease_checklist_id": t.LeaseChecklistID,
-		"lease_checklist":    DBLeaseChecklistToRest(t.LeaseChecklist),
-		"document_mode":      t.DocumentMode,
-		"document_url":       t.DocumentUrl,
-		"document_id":        t.DocumentID,
-		"created_at":         t.CreatedAt,
-		"updated_at":         t.UpdatedAt,
+		"id":                          t.ID,
+		"code":                        t.Code,
+		"lease_id":                    t.LeaseID,
+		"type":                        t.Type,
+		"reason":                      t.Reason,
+		"status":                      t.Status,
+		"lease_checklist_id":          t.LeaseChecklistID,
+		"lease_checklist":             DBLeaseChecklistToRest(t.LeaseChecklist),
+		"document_mode":               t.DocumentMode,
+		"document_url":                t.DocumentUrl,
+		"document_id":                 t.DocumentID,
+		"initiated_by_tenant_id":      t.InitiatedByTenantID,
+		"intended_move_out_date":      t.IntendedMoveOutDate,
+		"notice_period_days":          t.NoticePeriodDays,
+		"early_termination_fee":       t.EarlyTerminationFee,
+		"early_termination_charge_id": t.EarlyTerminationChargeID,
+		"created_at":                  t.CreatedAt,
+		"updated_at":                  t.UpdatedAt,
 	}
 }
@@ -22,10 +22,16 @@ type OutputAdminLease struct {
 	PaymentFrequency    *string        `json:"payment_frequency,omitempty" example:"Monthly"`
 	Meta                map[string]any `json:"meta"`
 
-	MoveInDate            time.Time  `json:"move_in_date"            example:"2024-07-01T00:00:00Z"`
-	StayDurationFrequency string     `json:"stay_duration_frequency" example:"Months"`
-	StayDuration          int64      `json:"stay_duration"           example:"12"`
-	MoveOutDate           *time.Time `json:"move_out_date"           example:"2025-07-01T00:00:00Z"`
+	MoveInDate            time.Time  `json:"move_in_date"                  example:"2024-07-01T00:00:00Z"`
+	StayDurationFrequency string     `json:"stay_duration_frequency"       example:"Months"`
+	StayDuration          int64      `json:"stay_duration"                 example:"12"`
+	MoveOutDate           *time.Time `json:"move_out_date"                 example:"2025-07-01T00:00:00Z"`
+	HoldoverStartedAt     *time.Time `json:"holdover_started_at,omitempty" example:"2025-07-01T00:00:00Z"`
+
+	NoticePeriodDays               *int64 `json:"notice_period_days,omitempty"       example:"30"`
+	EarlyTerminationFeePolicy      string `json:"early_termination_fee_policy"       example:"RENT_PERIODS"`
+	EarlyTerminationFeeAmount      int64  `json:"early_termination_fee_amount"       example:"0"`
+	EarlyTerminationFeeRentPeriods int64  `json:"early_termination_fee_rent_periods" example:"2"`
 
 	KeyHandoverDate        *time.Time `json:"key_handover_date"        example:"2024-07-01T09:00:00Z"`
 	UtilityTransfersDate   *time.Time `json:"utility_transfers_date"   example:"2024-07-02T10:00:00Z"`
@@ -65,6 +71,16 @@ type OutputAdminLease struct {
 	// code to print, and nesting whole leases would recurse up the chain.
 	ParentLease *OutputLeaseRef `json:"parent_lease,omitempty"`
 
+	// Version is bumped by every executed amendment. Amendments is present
+	// only when asked for with populate=Amendments.
+	Version    int64                  `json:"version"              example:"1"`
+	Amendments []OutputLeaseAmendment `json:"amendments,omitempty"`
+	// Tenants is everyone on the lease, primary first. Present only when
+	// asked for with populate=Tenants.
+	Tenants []OutputLeaseTenant `json:"tenants,omitempty"`
+	// Guarantors is present only when asked for with populate=Guarantors.
+	Guarantors []OutputGuarantor `json:"guarantors,omitempty"`
+
 	CreatedAt time.Time `json:"created_at" example:"2024-06-01T09:00:00Z"`
 	UpdatedAt time.Time `json:"updated_at" example:"2024-06-10T09:00:00Z"`
 }
@@ -108,6 +124,11 @@ func DBAdminLeaseToRest(i *models.Lease) any {
 		"stay_duration_frequency":            i.StayDurationFrequency,
 		"stay_duration":                      i.StayDuration,
 		"move_out_date":                      i.MoveOutDate,
+		"holdover_started_at":                i.HoldoverStartedAt,
+		"notice_period_days":                 i.NoticePeriodDays,
+		"early_termination_fee_policy":       i.EarlyTerminationFeePolicy,
+		"early_termination_fee_amount":       i.EarlyTerminationFeeAmount,
+		"early_termination_fee_rent_periods": i.EarlyTerminationFeeRentPeriods,
 		"key_handover_date":                  i.KeyHandoverDate,
 		"utility_transfers_date":             i.UtilityTransfersDate,
 		"property_inspection_date":           i.PropertyInspectionDate,
@@ -129,6 +150,10 @@ func DBAdminLeaseToRest(i *models.Lease) any {
 		"type":                               i.Type,
 		"parent_lease_id":                    i.ParentLeaseId,
 		"parent_lease":                       DBLeaseRefToRest(i.ParentLease),
+		"version":                            i.Version,
+		"amendments":                         dbLeaseAmendmentsToRest(i.Amendments),
+		"tenants":                            dbLeaseTenantsToRest(i.Tenants),
+		"guarantors":                         dbGuarantorsToRest(i.Guarantors),
 		"financial_account":                  DBTenantApplicationFinancialsToRest(i.Financials),
 		"created_at":                         i.CreatedAt,
 		"updated_at":                         i.UpdatedAt,
@@ -152,10 +177,16 @@ type OutputLease struct {
 	PaymentFrequency    *string                 `json:"payment_frequency,omitempty"  example:"Monthly"`
 	Meta                map[string]any          `json:"meta"`
 
-	MoveInDate            time.Time  `json:"move_in_date"            example:"2024-07-01T00:00:00Z"`
-	StayDurationFrequency string     `json:"stay_duration_frequency" example:"Months"`
-	StayDuration          int64      `json:"stay_duration"           example:"12"`
-	MoveOutDate           *time.Time `json:"move_out_date"           example:"2025-07-01T00:00:00Z"`
+	MoveInDate            time.Time  `json:"move_in_date"                  example:"2024-07-01T00:00:00Z"`
+	StayDurationFrequency string     `json:"stay_duration_frequency"       example:"Months"`
+	StayDuration          int64      `json:"stay_duration"                 example:"12"`
+	MoveOutDate           *time.Time `json:"move_out_date"                 example:"2025-07-01T00:00:00Z"`
+	HoldoverStartedAt     *time.Time `json:"holdover_started_at,omitempty" example:"2025-07-01T00:00:00Z"`
+
+	NoticePeriodDays               *int64 `json:"notice_period_days,omitempty"       example:"30"`
+	EarlyTerminationFeePolicy      string `json:"early_termination_fee_policy"       example:"RENT_PERIODS"`
+	EarlyTerminationFeeAmount      int64  `json:"early_termination_fee_amount"       example:"0"`
+	EarlyTerminationFeeRentPeriods int64  `json:"early_termination_fee_rent_periods" example:"2"`
 
 	KeyHandoverDate        *time.Time `json:"key_handover_date"        example:"2024-07-01T09:00:00Z"`
 	UtilityTransfersDate   *time.Time `json:"utility_transfers_date"   example:"2024-07-02T10:00:00Z"`
@@ -176,6 +207,14 @@ type OutputLease struct {
 	Type          string  `json:"type"                      example:"ORIGINAL"`
 	ParentLeaseId *string `json:"parent_lease_id,omitempty" example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
 
+	Version    int64                  `json:"version"              example:"1"`
+	Amendments []OutputLeaseAmendment `json:"amendments,omitempty"`
+	// Tenants is everyone on the lease, primary first. Present only when
+	// asked for with populate=Tenants.
+	Tenants []OutputLeaseTenant `json:"tenants,omitempty"`
+	// Guarantors is present only when asked for with populate=Guarantors.
+	Guarantors []OutputGuarantor `json:"guarantors,omitempty"`
+
 	FinancialAccount *OutputTenantApplicationFinancials `json:"financial_account,omitempty"`
 
 	CreatedAt time.Time `json:"created_at" example:"2024-06-01T09:00:00Z"`
@@ -205,6 +244,11 @@ func DBLeaseToRest(i *models.Lease) any {
 		"stay_duration_frequency":            i.StayDurationFrequency,
 		"stay_duration":                      i.StayDuration,
 		"move_out_date":                      i.MoveOutDate,
+		"holdover_started_at":                i.HoldoverStartedAt,
+		"notice_period_days":                 i.NoticePeriodDays,
+		"early_termination_fee_policy":       i.EarlyTerminationFeePolicy,
+		"early_termination_fee_amount":       i.EarlyTerminationFeeAmount,
+		"early_termination_fee_rent_periods": i.EarlyTerminationFeeRentPeriods,
 		"key_handover_date":                  i.KeyHandoverDate,
 		"utility_transfers_date":             i.UtilityTransfersDate,
 		"property_inspection_date":           i.PropertyInspectionDate,
@@ -217,6 +261,10 @@ func DBLeaseToRest(i *models.Lease) any {
 		"type":                               i.Type,
 		"parent_lease_id":                    i.ParentLeaseId,
 		"parent_lease":                       DBLeaseRefToRest(i.ParentLease),
+		"version":                            i.Version,
+		"amendments":                         dbLeaseAmendmentsToRest(i.Amendments),
+		"tenants":                            dbLeaseTenantsToRest(i.Tenants),
+		"guarantors":                         dbGuarantorsToRest(i.Guarantors),
 		"financial_account":                  DBTenantApplicationFinancialsToRest(i.Financials),
 		"created_at":                         i.CreatedAt,
 		"updated_at":                         i.UpdatedAt,
@@ -224,3 +272,15 @@ func DBLeaseToRest(i *models.Lease) any {
 
 	return data
 }
+
+func dbLeaseAmendmentsToRest(amendments []models.LeaseAmendment) []any {
+	if amendments == nil {
+		return nil
+	}
+
+	rows := make([]any, 0, len(amendments))
+	for i := range amendments {
+		rows = append(rows, DBLeaseAmendmentToRest(&amendments[i]))
+	}
+	return rows
+}
@@ -12,6 +12,8 @@ type OutputPayment struct {
 	InvoiceID string        `json:"invoice_id"        example:"b50874ee-1a70-436e-ba24-572078895982"               description:"The ID of the invoice"`
 	Invoice   OutputInvoice `json:"invoice,omitempty"`
 
+	PayerTenantID *string `json:"payer_tenant_id,omitempty" example:"c1d2e3f4-1a70-436e-ba24-572078895982" description:"The lease tenant who made the payment, when known"`
+
 	Rail     string  `json:"rail"               example:"OFFLINE" description:"Payment rail (MOMO, BANK_TRANSFER, CARD, OFFLINE)"`
 	Provider *string `json:"provider,omitempty" example:"CASH"    description:"Payment provider"`
 
@@ -36,20 +38,21 @@ func DBPaymentToRest(p *models.Payment) any {
 	}
 
 	data := map[string]interface{}{
-		"id":            p.ID.String(),
-		"invoice_id":    p.InvoiceID,
-		"invoice":       DBInvoiceToRest(&p.Invoice),
-		"rail":          p.Rail,
-		"provider":      p.Provider,
-		"amount":        p.Amount,
-		"currency":      p.Currency,
-		"reference":     p.Reference,
-		"status":        p.Status,
-		"successful_at": p.SuccessfulAt,
-		"failed_at":     p.FailedAt,
-		"metadata":      p.Metadata,
-		"created_at":    p.CreatedAt,
-		"updated_at":    p.UpdatedAt,
+		"id":              p.ID.String(),
+		"invoice_id":      p.InvoiceID,
+		"invoice":         DBInvoiceToRest(&p.Invoice),
+		"payer_tenant_id": p.PayerTenantID,
+		"rail":            p.Rail,
+		"provider":        p.Provider,
+		"amount":          p.Amount,
+		"currency":        p.Currency,
+		"reference":       p.Reference,
+		"status":          p.Status,
+		"successful_at":   p.SuccessfulAt,
+		"failed_at":       p.FailedAt,
+		"metadata":        p.Metadata,
+		"created_at":      p.CreatedAt,
+		"updated_at":      p.UpdatedAt,
 	}
 
 	return data
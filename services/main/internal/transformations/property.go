@@ -8,32 +8,39 @@ import (
 )
 
 type OutputProperty struct {
-	ID          string           `json:"id"                    example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" format:"uuid"      description:"Unique identifier for the property"`
-	Slug        string           `json:"slug"                  example:"my-property-abcde1876drkjy"                              description:"Slug for the property"`
-	Type        string           `json:"type"                  example:"SINGLE"                                                  description:"Type of the property (e.g., SINGLE, MULTI)"`
-	Status      string           `json:"status"                example:"Property.Status.Active"                                  description:"Current status of the property"`
-	Name        string           `json:"name"                  example:"My Property"                                             description:"Name of the property"`
-	Description *string          `json:"description"           example:"Very elegant place"                                      description:"Optional description of the property"`
-	Images      []string         `json:"images"                example:"http://www.images/hih.jpg"                               description:"List of image URLs for the property"`
-	Tags        []string         `json:"tags"                  example:"apartment,downtown"                                      description:"Tags associated with the property"`
-	Latitude    float64          `json:"latitude"              example:"5.6037"                                                  description:"Latitude coordinate of the property"`
-	Longitude   float64          `json:"longitude"             example:"-0.1870"                                                 description:"Longitude coordinate of the property"`
-	Address     string           `json:"address"               example:"123 Main St"                                             description:"Street address of the property"`
-	Country     string           `json:"country"               example:"Ghana"                                                   description:"Country where the property is located"`
-	Region      string           `json:"region"                example:"Greater Accra"                                           description:"Region or state of the property"`
-	City        string           `json:"city"                  example:"Accra"                                                   description:"City where the property is located"`
-	GPSAddress  *string          `json:"gps_address,omitempty" example:"GH-1234-5678"                                            description:"Optional GPS address or plus code"`
-	ClientID    string           `json:"client_id"             example:"b50874ee-1a70-436e-ba24-572078895982"                    description:"The ID of the client"`
-	Client      OutputClient     `json:"client"`
-	CreatedByID string           `json:"created_by_id"         example:"1e81fea0-5e8b-4535-b449-1a2133e94a7a"                    description:"The ID of the client user that created the property"`
-	CreatedBy   OutputClientUser `json:"created_by"`
-	DeletedByID *string          `json:"deleted_by_id"         example:"1e81fea0-5e8b-4535-b449-1a2133e94a7a"                    description:"The ID of the client user that deleted (archived) the property, if any"`
-	DeletedBy   OutputClientUser `json:"deleted_by"`
-	BlocksCount int              `json:"blocks_count"          example:"2"                                                       description:"Current number of blocks under this property"`
-	UnitsCount  int              `json:"units_count"           example:"6"                                                       description:"Current number of units under this property"`
-	DeletedAt   *time.Time       `json:"deleted_at"            example:"2026-07-01T00:00:00Z"                 format:"date-time" description:"When the property was archived, if it has been"`
-	CreatedAt   time.Time        `json:"created_at"            example:"2023-01-01T00:00:00Z"                 format:"date-time" description:"Timestamp when the property was created"`
-	UpdatedAt   time.Time        `json:"updated_at"            example:"2023-01-01T00:00:00Z"                 format:"date-time" description:"Timestamp when the property was last updated"`
+	ID                            string           `json:"id"                               example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" format:"uuid"      description:"Unique identifier for the property"`
+	Slug                          string           `json:"slug"                             example:"my-property-abcde1876drkjy"                              description:"Slug for the property"`
+	Type                          string           `json:"type"                             example:"SINGLE"                                                  description:"Type of the property (e.g., SINGLE, MULTI)"`
+	Status                        string           `json:"status"                           example:"Property.Status.Active"                                  description:"Current status of the property"`
+	Name                          string           `json:"name"                             example:"My Property"                                             description:"Name of the property"`
+	Description                   *string          `json:"description"                      example:"Very elegant place"                                      description:"Optional description of the property"`
+	Images                        []string         `json:"images"                           example:"http://www.images/hih.jpg"                               description:"List of image URLs for the property"`
+	Tags                          []string         `json:"tags"                             example:"apartment,downtown"                                      description:"Tags associated with the property"`
+	Latitude                      float64          `json:"latitude"                         example:"5.6037"                                                  description:"Latitude coordinate of the property"`
+	Longitude                     float64          `json:"longitude"                        example:"-0.1870"                                                 description:"Longitude coordinate of the property"`
+	Address                       string           `json:"address"                          example:"123 Main St"                                             description:"Street address of the property"`
+	Country                       string           `json:"country"                          example:"Ghana"                                                   description:"Country where the property is located"`
+	Region                        string           `json:"region"                           example:"Greater Accra"                                           description:"Region or state of the property"`
+	City                          string           `json:"city"                             example:"Accra"                                                   description:"City where the property is located"`
+	GPSAddress                    *string          `json:"gps_address,omitempty"            example:"GH-1234-5678"                                            description:"Optional GPS address or plus code"`
+	HoldoverPolicy                string           `json:"holdover_policy"                  example:"COMPLETE"                                                description:"What happens to an active lease whose move-out passes without a renewal (COMPLETE, MONTH_TO_MONTH)"`
+	HoldoverRentPremiumPercent    int64            `json:"holdover_rent_premium_percent"    example:"0"                                                       description:"Percentage added to the rent on the first month-to-month rollover"`
+	NoticePeriodDays              int64            `json:"notice_period_days"               example:"30"                                                      description:"Days of notice a tenant must give to vacate, unless their lease sets its own"`
+	BookingCancellationPolicy     string           `json:"booking_cancellation_policy"      example:"FLEXIBLE"                                                description:"Refund policy new bookings are taken under (FLEXIBLE, MODERATE, STRICT)"`
+	BookingRequiresUpfrontPayment bool             `json:"booking_requires_upfront_payment" example:"false"                                                   description:"Whether new bookings are held only until the guest pays"`
+	BookingHoldHours              int64            `json:"booking_hold_hours"               example:"24"                                                      description:"Hours a guest has to pay for a held booking; 0 holds it until a manager acts"`
+	BookingRequiresGuestList      bool             `json:"booking_requires_guest_list"      example:"false"                                                   description:"Whether bookings are checked in only once every guest is registered"`
+	ClientID                      string           `json:"client_id"                        example:"b50874ee-1a70-436e-ba24-572078895982"                    description:"The ID of the client"`
+	Client                        OutputClient     `json:"client"`
+	CreatedByID                   string           `json:"created_by_id"                    example:"1e81fea0-5e8b-4535-b449-1a2133e94a7a"                    description:"The ID of the client user that created the property"`
+	CreatedBy                     OutputClientUser `json:"created_by"`
+	DeletedByID                   *string          `json:"deleted_by_id"                    example:"1e81fea0-5e8b-4535-b449-1a2133e94a7a"                    description:"The ID of the client user that deleted (archived) the property, if any"`
+	DeletedBy                     OutputClientUser `json:"deleted_by"`
+	BlocksCount                   int              `json:"blocks_count"                     example:"2"                                                       description:"Current number of blocks under this property"`
+	UnitsCount                    int              `json:"units_count"                      example:"6"                                                       description:"Current number of units under this property"`
+	DeletedAt                     *time.Time       `json:"deleted_at"                       example:"2026-07-01T00:00:00Z"                 format:"date-time" description:"When the property was archived, if it has been"`
+	CreatedAt                     time.Time        `json:"created_at"                       example:"2023-01-01T00:00:00Z"                 format:"date-time" description:"Timestamp when the property was created"`
+	UpdatedAt                     time.Time        `json:"updated_at"                       example:"2023-01-01T00:00:00Z"                 format:"date-time" description:"Timestamp when the property was last updated"`
 }
 
 func DBPropertyToRest(i *models.Property) interface{} {
@@ -47,34 +54,41 @@ func DBPropertyToRest(i *models.Property) interface{} {
 	}
 
 	data := map[string]interface{}{
-		"id":            i.ID.String(),
-		"slug":          i.Slug,
-		"type":          i.Type,
-		"status":        i.Status,
-		"name":          i.Name,
-		"description":   i.Description,
-		"images":        i.Images,
-		"tags":          i.Tags,
-		"latitude":      i.Latitude,
-		"longitude":     i.Longitude,
-		"address":       i.Address,
-		"country":       i.Country,
-		"region":        i.Region,
-		"city":          i.City,
-		"gps_address":   i.GPSAddress,
-		"currency":      i.Currency,
-		"client_id":     i.ClientID,
-		"client":        DBClientToRestClient(&i.Client),
-		"created_by_id": i.CreatedByID,
-		"created_by":    DBClientUserToRest(&i.CreatedBy),
-		"deleted_by_id": i.DeletedByID,
-		"deleted_by":    DBClientUserToRest(i.DeletedBy),
-		"blocks_count":  i.BlocksCount,
-		"units_count":   i.UnitsCount,
-		"deleted_at":    deletedAt,
-		"modes":         i.Modes,
-		"created_at":    i.CreatedAt,
-		"updated_at":    i.UpdatedAt,
+		"id":                               i.ID.String(),
+		"slug":                             i.Slug,
+		"type":                             i.Type,
+		"status":                           i.Status,
+		"name":                             i.Name,
+		"description":                      i.Description,
+		"images":                           i.Images,
+		"tags":                             i.Tags,
+		"latitude":                         i.Latitude,
+		"longitude":                        i.Longitude,
+		"address":                          i.Address,
+		"country":                          i.Country,
+		"region":                           i.Region,
+		"city":                             i.City,
+		"gps_address":                      i.GPSAddress,
+		"currency":                         i.Currency,
+		"client_id":                        i.ClientID,
+		"client":                           DBClientToRestClient(&i.Client),
+		"created_by_id":                    i.CreatedByID,
+		"created_by":                       DBClientUserToRest(&i.CreatedBy),
+		"deleted_by_id":                    i.DeletedByID,
+		"deleted_by":                       DBClientUserToRest(i.DeletedBy),
+		"blocks_count":                     i.BlocksCount,
+		"units_count":                      i.UnitsCount,
+		"deleted_at":                       deletedAt,
+		"modes":                            i.Modes,
+		"holdover_policy":                  i.HoldoverPolicy,
+		"holdover_rent_premium_percent":    i.HoldoverRentPremiumPercent,
+		"notice_period_days":               i.NoticePeriodDays,
+		"booking_cancellation_policy":      i.BookingCancellationPolicy,
+		"booking_requires_upfront_payment": i.BookingRequiresUpfrontPayment,
+		"booking_hold_hours":               i.BookingHoldHours,
+		"booking_requires_guest_list":      i.BookingRequiresGuestList,
+		"created_at":                       i.CreatedAt,
+		"updated_at":                       i.UpdatedAt,
 	}
 
 	return data
@@ -82,21 +96,23 @@ func DBPropertyToRest(i *models.Property) interface{} {
 
 // for public
 type PublicOutputProperty struct {
-	ID          string             `json:"id"          example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" format:"uuid" description:"Unique identifier for the property"`
-	Slug        string             `json:"slug"        example:"my-property-abcde1876drkjy"                         description:"Slug for the property"`
-	Type        string             `json:"type"        example:"SINGLE"                                             description:"Type of the property (e.g., SINGLE, MULTI)"`
-	Status      string             `json:"status"      example:"Property.Status.Active"                             description:"Current status of the property"`
-	Name        string             `json:"name"        example:"My Property"                                        description:"Name of the property"`
-	Description *string            `json:"description" example:"Very elegant place"                                 description:"Optional description of the property"`
-	Images      []string           `json:"images"      example:"http://www.images/hih.jpg"                          description:"List of image URLs for the property"`
-	Tags        []string           `json:"tags"        example:"apartment,downtown"                                 description:"Tags associated with the property"`
-	Latitude    float64            `json:"latitude"    example:"5.6037"                                             description:"Latitude coordinate of the property"`
-	Longitude   float64            `json:"longitude"   example:"-0.1870"                                            description:"Longitude coordinate of the property"`
-	Address     string             `json:"address"     example:"123 Main St"                                        description:"Street address of the property"`
-	Country     string             `json:"country"     example:"Ghana"                                              description:"Country where the property is located"`
-	Region      string             `json:"region"      example:"Greater Accra"                                      description:"Region or state of the property"`
-	City        string             `json:"city"        example:"Accra"                                              description:"City where the property is located"`
-	Client      PublicOutputClient `json:"client"`
+	ID                        string                          `json:"id"                          example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" format:"uuid" description:"Unique identifier for the property"`
+	Slug                      string                          `json:"slug"                        example:"my-property-abcde1876drkjy"                         description:"Slug for the property"`
+	Type                      string                          `json:"type"                        example:"SINGLE"                                             description:"Type of the property (e.g., SINGLE, MULTI)"`
+	Status                    string                          `json:"status"                      example:"Property.Status.Active"                             description:"Current status of the property"`
+	Name                      string                          `json:"name"                        example:"My Property"                                        description:"Name of the property"`
+	Description               *string                         `json:"description"                 example:"Very elegant place"                                 description:"Optional description of the property"`
+	Images                    []string                        `json:"images"                      example:"http://www.images/hih.jpg"                          description:"List of image URLs for the property"`
+	Tags                      []string                        `json:"tags"                        example:"apartment,downtown"                                 description:"Tags associated with the property"`
+	Latitude                  float64                         `json:"latitude"                    example:"5.6037"                                             description:"Latitude coordinate of the property"`
+	Longitude                 float64                         `json:"longitude"                   example:"-0.1870"                                            description:"Longitude coordinate of the property"`
+	Address                   string                          `json:"address"                     example:"123 Main St"                                        description:"Street address of the property"`
+	Country                   string                          `json:"country"                     example:"Ghana"                                              description:"Country where the property is located"`
+	Region                    string                          `json:"region"                      example:"Greater Accra"                                      description:"Region or state of the property"`
+	City                      string                          `json:"city"                        example:"Accra"                                              description:"City where the property is located"`
+	Modes                     []string                        `json:"modes"                       example:"LEASE,BOOKING"                                      description:"Whether the property's units are leased, booked or both"`
+	BookingCancellationPolicy OutputBookingCancellationPolicy `json:"booking_cancellation_policy"                                                              description:"Refund policy bookings are taken under"`
+	Client                    PublicOutputClient              `json:"client"`
 }
 
 func DBPublicPropertyToRest(i *models.Property) interface{} {
@@ -105,21 +121,23 @@ func DBPublicPropertyToRest(i *models.Property) interface{} {
 	}
 
 	data := map[string]interface{}{
-		"id":          i.ID.String(),
-		"slug":        i.Slug,
-		"type":        i.Type,
-		"status":      i.Status,
-		"name":        i.Name,
-		"description": i.Description,
-		"images":      i.Images,
-		"tags":        i.Tags,
-		"latitude":    i.Latitude,
-		"longitude":   i.Longitude,
-		"address":     i.Address,
-		"country":     i.Country,
-		"region":      i.Region,
-		"city":        i.City,
-		"client":      DBClientToRestPublicClient(&i.Client),
+		"id":                          i.ID.String(),
+		"slug":                        i.Slug,
+		"type":                        i.Type,
+		"status":                      i.Status,
+		"name":                        i.Name,
+		"description":                 i.Description,
+		"images":                      i.Images,
+		"tags":                        i.Tags,
+		"latitude":                    i.Latitude,
+		"longitude":                   i.Longitude,
+		"address":                     i.Address,
+		"country":                     i.Country,
+		"region":                      i.Region,
+		"city":                        i.City,
+		"modes":                       i.Modes,
+		"booking_cancellation_policy": BookingCancellationPolicyToRest(i.BookingCancellationPolicy),
+		"client":                      DBClientToRestPublicClient(&i.Client),
 	}
 
 	return data
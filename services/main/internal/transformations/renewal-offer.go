@@ -0,0 +1,139 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputRenewalOfferFee struct {
+	Category string `json:"category" example:"SECURITY_DEPOSIT"`
+	Name     string `json:"name"     example:"Deposit top-up"`
+	Amount   int64  `json:"amount"   example:"15000"`
+}
+
+type OutputRenewalOfferOption struct {
+	RentFee               int64                   `json:"rent_fee"                example:"450000"`
+	StayDuration          int64                   `json:"stay_duration"           example:"12"`
+	StayDurationFrequency string                  `json:"stay_duration_frequency" example:"Months"`
+	Fees                  []OutputRenewalOfferFee `json:"fees"`
+}
+
+type OutputRenewalOffer struct {
+	ID      string `json:"id"       example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Code    string `json:"code"     example:"2610ABC123"`
+	LeaseID string `json:"lease_id" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+
+	Status    string                     `json:"status"            example:"RenewalOffer.Status.Sent"`
+	Message   *string                    `json:"message,omitempty" example:"We'd love to have you stay another year."`
+	Options   []OutputRenewalOfferOption `json:"options"`
+	ExpiresAt time.Time                  `json:"expires_at"        example:"2026-12-01T00:00:00Z"`
+
+	AcceptedOptionIndex *int    `json:"accepted_option_index,omitempty" example:"0"`
+	RenewalLeaseID      *string `json:"renewal_lease_id,omitempty"      example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	DeclineReason       *string `json:"decline_reason,omitempty"        example:"Moving to another city"`
+
+	CounterRentFee               *int64  `json:"counter_rent_fee,omitempty"                example:"420000"`
+	CounterStayDuration          *int64  `json:"counter_stay_duration,omitempty"           example:"12"`
+	CounterStayDurationFrequency *string `json:"counter_stay_duration_frequency,omitempty" example:"Months"`
+	CounterMessage               *string `json:"counter_message,omitempty"                 example:"Could we keep the current rent?"`
+
+	RespondedAt         *time.Time `json:"responded_at,omitempty"           example:"2026-11-10T09:00:00Z"`
+	RespondedByTenantID *string    `json:"responded_by_tenant_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+
+	CreatedAt time.Time `json:"created_at" example:"2024-06-01T09:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at" example:"2024-06-10T09:00:00Z"`
+}
+
+type OutputAdminRenewalOffer struct {
+	OutputRenewalOffer
+
+	CreatedById string            `json:"created_by_id"        example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
+	CreatedBy   *OutputClientUser `json:"created_by,omitempty"`
+
+	WithdrawnAt   *time.Time        `json:"withdrawn_at,omitempty"    example:"2024-12-01T10:00:00Z"`
+	WithdrawnById *string           `json:"withdrawn_by_id,omitempty" example:"b3b2c9d0-6c8a-4e8b-9e7a-abcdef123456"`
+	WithdrawnBy   *OutputClientUser `json:"withdrawn_by,omitempty"`
+}
+
+func DBAdminRenewalOfferToRest(o *models.RenewalOffer) any {
+	if o == nil || o.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                              o.ID,
+		"code":                            o.Code,
+		"lease_id":                        o.LeaseID,
+		"status":                          o.Status,
+		"message":                         o.Message,
+		"options":                         renewalOfferOptionsToRest(o.Options),
+		"expires_at":                      o.ExpiresAt,
+		"accepted_option_index":           o.AcceptedOptionIndex,
+		"renewal_lease_id":                o.RenewalLeaseID,
+		"decline_reason":                  o.DeclineReason,
+		"counter_rent_fee":                o.CounterRentFee,
+		"counter_stay_duration":           o.CounterStayDuration,
+		"counter_stay_duration_frequency": o.CounterStayDurationFrequency,
+		"counter_message":                 o.CounterMessage,
+		"responded_at":                    o.RespondedAt,
+		"responded_by_tenant_id":          o.RespondedByTenantID,
+		"created_by_id":                   o.CreatedById,
+		"created_by":                      DBClientUserToRest(&o.CreatedBy),
+		"withdrawn_at":                    o.WithdrawnAt,
+		"withdrawn_by_id":                 o.WithdrawnById,
+		"withdrawn_by":                    DBClientUserToRest(o.WithdrawnBy),
+		"created_at":                      o.CreatedAt,
+		"updated_at":                      o.UpdatedAt,
+	}
+}
+
+func DBRenewalOfferToRest(o *models.RenewalOffer) any {
+	if o == nil || o.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                              o.ID,
+		"code":                            o.Code,
+		"lease_id":                        o.LeaseID,
+		"status":                          o.Status,
+		"message":                         o.Message,
+		"options":                         renewalOfferOptionsToRest(o.Options),
+		"expires_at":                      o.ExpiresAt,
+		"accepted_option_index":           o.AcceptedOptionIndex,
+		"renewal_lease_id":                o.RenewalLeaseID,
+		"decline_reason":                  o.DeclineReason,
+		"counter_rent_fee":                o.CounterRentFee,
+		"counter_stay_duration":           o.CounterStayDuration,
+		"counter_stay_duration_frequency": o.CounterStayDurationFrequency,
+		"counter_message":                 o.CounterMessage,
+		"responded_at":                    o.RespondedAt,
+		"responded_by_tenant_id":          o.RespondedByTenantID,
+		"created_at":                      o.CreatedAt,
+		"updated_at":                      o.UpdatedAt,
+	}
+}
+
+func renewalOfferOptionsToRest(options []models.RenewalOfferOption) []map[string]any {
+	rows := make([]map[string]any, 0, len(options))
+	for _, option := range options {
+		fees := make([]map[string]any, 0, len(option.Fees))
+		for _, fee := range option.Fees {
+			fees = append(fees, map[string]any{
+				"category": fee.Category,
+				"name":     fee.Name,
+				"amount":   fee.Amount,
+			})
+		}
+
+		rows = append(rows, map[string]any{
+			"rent_fee":                option.RentFee,
+			"stay_duration":           option.StayDuration,
+			"stay_duration_frequency": option.StayDurationFrequency,
+			"fees":                    fees,
+		})
+	}
+	return rows
+}
@@ -0,0 +1,88 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputAdminSigningEnvelope struct {
+	ID                  string                    `json:"id"                               example:"bb0e8400-e29b-41d4-a716-446655440000"`
+	Status              string                    `json:"status"                           example:"SigningEnvelope.Status.InProgress"`
+	DocumentID          string                    `json:"document_id"                      example:"550e8400-e29b-41d4-a716-446655440000"`
+	DocumentRevisionID  *string                   `json:"document_revision_id,omitempty"   example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
+	Document            *OutputAdminDocument      `json:"document,omitempty"`
+	TenantApplicationID *string                   `json:"tenant_application_id,omitempty"  example:"660e8400-e29b-41d4-a716-446655440000"`
+	LeaseID             *string                   `json:"lease_id,omitempty"               example:"770e8400-e29b-41d4-a716-446655440000"`
+	LeaseTerminationID  *string                   `json:"lease_termination_id,omitempty"   example:"880e8400-e29b-41d4-a716-446655440000"`
+	LeaseAmendmentID    *string                   `json:"lease_amendment_id,omitempty"     example:"aa0e8400-e29b-41d4-a716-446655440000"`
+	CurrentStep         *int64                    `json:"current_step,omitempty"           example:"2"`
+	Tokens              []OutputAdminSigningToken `json:"tokens"`
+
+	PMSigningOrder   *int64     `json:"pm_signing_order,omitempty"       example:"3"`
+	PMNotifiedAt     *time.Time `json:"pm_notified_at,omitempty"         example:"2024-06-16T09:00:00Z"`
+	PMLastRemindedAt *time.Time `json:"pm_last_reminded_at,omitempty"    example:"2024-06-18T09:00:00Z"`
+	PMSignedAt       *time.Time `json:"pm_signed_at,omitempty"           example:"2024-06-18T12:00:00Z"`
+
+	ExpiryPolicy         string `json:"expiry_policy"                    example:"REGENERATE"`
+	TokenValidityDays    int64  `json:"token_validity_days"              example:"7"`
+	ReminderIntervalDays int64  `json:"reminder_interval_days"           example:"2"`
+
+	CreatedByID string            `json:"created_by_id"                    example:"880e8400-e29b-41d4-a716-446655440000"`
+	CreatedBy   *OutputClientUser `json:"created_by,omitempty"`
+
+	CompletedAt          *time.Time        `json:"completed_at,omitempty"           example:"2024-06-20T09:00:00Z"`
+	AuditCertificateUrl  *string           `json:"audit_certificate_url,omitempty"  example:"https://example.com/certificate.pdf"`
+	AuditCertificateHash *string           `json:"audit_certificate_hash,omitempty" example:"9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	CancelledAt          *time.Time        `json:"cancelled_at,omitempty"           example:"2024-06-20T09:00:00Z"`
+	CancelledByID        *string           `json:"cancelled_by_id,omitempty"        example:"880e8400-e29b-41d4-a716-446655440000"`
+	CancelledBy          *OutputClientUser `json:"cancelled_by,omitempty"`
+	CancellationReason   *string           `json:"cancellation_reason,omitempty"    example:"Tenant withdrew"`
+
+	CreatedAt time.Time `json:"created_at"                       example:"2024-06-01T09:00:00Z"`
+	UpdatedAt time.Time `json:"updated_at"                       example:"2024-06-10T09:00:00Z"`
+}
+
+func DBAdminSigningEnvelopeToRest(i *models.SigningEnvelope) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	tokens := make([]any, 0, len(i.Tokens))
+	for idx := range i.Tokens {
+		tokens = append(tokens, DBAdminSigningTokenToRest(&i.Tokens[idx]))
+	}
+
+	return map[string]any{
+		"id":                     i.ID.String(),
+		"status":                 i.Status,
+		"document_id":            i.DocumentID,
+		"document_revision_id":   i.DocumentRevisionID,
+		"document":               DBAdminDocumentToRestDocument(&i.Document),
+		"tenant_application_id":  i.TenantApplicationID,
+		"lease_id":               i.LeaseID,
+		"lease_termination_id":   i.LeaseTerminationID,
+		"lease_amendment_id":     i.LeaseAmendmentID,
+		"current_step":           i.CurrentStep,
+		"tokens":                 tokens,
+		"pm_signing_order":       i.PMSigningOrder,
+		"pm_notified_at":         i.PMNotifiedAt,
+		"pm_last_reminded_at":    i.PMLastRemindedAt,
+		"pm_signed_at":           i.PMSignedAt,
+		"expiry_policy":          i.ExpiryPolicy,
+		"token_validity_days":    i.TokenValidityDays,
+		"reminder_interval_days": i.ReminderIntervalDays,
+		"created_by_id":          i.CreatedByID,
+		"created_by":             DBClientUserToRest(&i.CreatedBy),
+		"completed_at":           i.CompletedAt,
+		"audit_certificate_url":  i.AuditCertificateUrl,
+		"audit_certificate_hash": i.AuditCertificateHash,
+		"cancelled_at":           i.CancelledAt,
+		"cancelled_by_id":        i.CancelledByID,
+		"cancelled_by":           DBClientUserToRest(i.CancelledBy),
+		"cancellation_reason":    i.CancellationReason,
+		"created_at":             i.CreatedAt,
+		"updated_at":             i.UpdatedAt,
+	}
+}
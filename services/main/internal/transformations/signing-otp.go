@@ -0,0 +1,21 @@
+package transformations
+
+import (
+	"github.com/Bendomey/rent-loop/services/main/internal/services"
+)
+
+type OutputSigningOtpSent struct {
+	Channel  string `json:"channel"  example:"SMS"`
+	Receiver string `json:"receiver" example:"**********802"`
+}
+
+func SigningOtpSentToRest(i *services.SigningOtpSent) any {
+	if i == nil {
+		return nil
+	}
+
+	return map[string]any{
+		"channel":  i.Channel,
+		"receiver": i.Receiver,
+	}
+}
@@ -11,6 +11,7 @@ type OutputAdminSigningToken struct {
 	ID                  string                        `json:"id"                              example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	Token               string                        `json:"token"                           example:"2602ABC123-a8f3b2c1d4e5"`
 	DocumentID          string                        `json:"document_id"                     example:"550e8400-e29b-41d4-a716-446655440000"`
+	DocumentRevisionID  *string                       `json:"document_revision_id,omitempty"  example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	Document            *OutputAdminDocument          `json:"document,omitempty"`
 	TenantApplicationID *string                       `json:"tenant_application_id,omitempty" example:"660e8400-e29b-41d4-a716-446655440000"`
 	TenantApplication   *OutputAdminTenantApplication `json:"tenant_application,omitempty"`
@@ -18,6 +19,13 @@ type OutputAdminSigningToken struct {
 	Lease               *OutputAdminLease             `json:"lease,omitempty"`
 	LeaseTerminationID  *string                       `json:"lease_termination_id,omitempty"  example:"880e8400-e29b-41d4-a716-446655440000"`
 	LeaseTermination    *OutputAdminLeaseTermination  `json:"lease_termination,omitempty"`
+	LeaseAmendmentID    *string                       `json:"lease_amendment_id,omitempty"    example:"aa0e8400-e29b-41d4-a716-446655440000"`
+	LeaseAmendment      *OutputAdminLeaseAmendment    `json:"lease_amendment,omitempty"`
+	SigningEnvelopeID   *string                       `json:"signing_envelope_id,omitempty"   example:"bb0e8400-e29b-41d4-a716-446655440000"`
+	SigningOrder        int64                         `json:"signing_order"                   example:"1"`
+	Status              string                        `json:"status"                          example:"PENDING"`
+	TenantID            *string                       `json:"tenant_id,omitempty"             example:"990e8400-e29b-41d4-a716-446655440000"`
+	GuarantorID         *string                       `json:"guarantor_id,omitempty"          example:"aa1e8400-e29b-41d4-a716-446655440000"`
 	Role                string                        `json:"role"                            example:"TENANT"`
 	SignerName          *string                       `json:"signer_name,omitempty"           example:"Jane Doe"`
 	SignerEmail         *string                       `json:"signer_email,omitempty"          example:"jane@example.com"`
@@ -27,6 +35,11 @@ type OutputAdminSigningToken struct {
 	SignedAt            *time.Time                    `json:"signed_at,omitempty"             example:"2024-06-15T14:30:00Z"`
 	LastAccessedAt      *time.Time                    `json:"last_accessed_at,omitempty"      example:"2024-06-14T10:00:00Z"`
 	ExpiresAt           time.Time                     `json:"expires_at"                      example:"2024-06-22T09:00:00Z"`
+	NotifiedAt          *time.Time                    `json:"notified_at,omitempty"           example:"2024-06-15T09:00:00Z"`
+	LastRemindedAt      *time.Time                    `json:"last_reminded_at,omitempty"      example:"2024-06-17T09:00:00Z"`
+	CancelledAt         *time.Time                    `json:"cancelled_at,omitempty"          example:"2024-06-20T09:00:00Z"`
+	OtpVerifiedAt       *time.Time                    `json:"otp_verified_at,omitempty"       example:"2024-06-15T14:20:00Z"`
+	OtpVerifiedVia      *string                       `json:"otp_verified_via,omitempty"      example:"SMS"`
 	DocumentSignatureID *string                       `json:"document_signature_id,omitempty" example:"990e8400-e29b-41d4-a716-446655440000"`
 	DocumentSignature   *OutputDocumentSignature      `json:"document_signature,omitempty"`
 	CreatedAt           time.Time                     `json:"created_at"                      example:"2024-06-01T09:00:00Z"`
@@ -42,6 +55,7 @@ func DBAdminSigningTokenToRest(i *models.SigningToken) any {
 		"id":                    i.ID.String(),
 		"token":                 i.Token,
 		"document_id":           i.DocumentID,
+		"document_revision_id":  i.DocumentRevisionID,
 		"document":              DBAdminDocumentToRestDocument(&i.Document),
 		"tenant_application_id": i.TenantApplicationID,
 		"tenant_application":    DBAdminTenantApplicationToRest(i.TenantApplication),
@@ -49,6 +63,13 @@ func DBAdminSigningTokenToRest(i *models.SigningToken) any {
 		"lease":                 DBAdminLeaseToRest(i.Lease),
 		"lease_termination_id":  i.LeaseTerminationID,
 		"lease_termination":     DBAdminLeaseTerminationToRest(i.LeaseTermination),
+		"lease_amendment_id":    i.LeaseAmendmentID,
+		"tenant_id":             i.TenantID,
+		"guarantor_id":          i.GuarantorID,
+		"lease_amendment":       DBAdminLeaseAmendmentToRest(i.LeaseAmendment),
+		"signing_envelope_id":   i.SigningEnvelopeID,
+		"signing_order":         i.SigningOrder,
+		"status":                i.SigningStatus(),
 		"role":                  i.Role,
 		"signer_name":           i.SignerName,
 		"signer_email":          i.SignerEmail,
@@ -58,6 +79,11 @@ func DBAdminSigningTokenToRest(i *models.SigningToken) any {
 		"signed_at":             i.SignedAt,
 		"last_accessed_at":      i.LastAccessedAt,
 		"expires_at":            i.ExpiresAt,
+		"notified_at":           i.NotifiedAt,
+		"last_reminded_at":      i.LastRemindedAt,
+		"cancelled_at":          i.CancelledAt,
+		"otp_verified_at":       i.OtpVerifiedAt,
+		"otp_verified_via":      i.OtpVerifiedVia,
 		"document_signature_id": i.DocumentSignatureID,
 		"document_signature":    DBDocumentSignatureToRest(i.DocumentSignature),
 		"created_at":            i.CreatedAt,
@@ -71,6 +97,7 @@ type OutputSigningToken struct {
 	ID                  string                   `json:"id"                              example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	Token               string                   `json:"token"                           example:"2602ABC123-a8f3b2c1d4e5"`
 	DocumentID          string                   `json:"document_id"                     example:"550e8400-e29b-41d4-a716-446655440000"`
+	DocumentRevisionID  *string                  `json:"document_revision_id,omitempty"  example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	Document            *OutputDocument          `json:"document,omitempty"`
 	TenantApplicationID *string                  `json:"tenant_application_id,omitempty" example:"660e8400-e29b-41d4-a716-446655440000"`
 	TenantApplication   *OutputTenantApplication `json:"tenant_application,omitempty"`
@@ -78,6 +105,10 @@ type OutputSigningToken struct {
 	Lease               *OutputLease             `json:"lease,omitempty"`
 	LeaseTerminationID  *string                  `json:"lease_termination_id,omitempty"  example:"880e8400-e29b-41d4-a716-446655440000"`
 	LeaseTermination    *OutputLeaseTermination  `json:"lease_termination,omitempty"`
+	LeaseAmendmentID    *string                  `json:"lease_amendment_id,omitempty"    example:"aa0e8400-e29b-41d4-a716-446655440000"`
+	LeaseAmendment      *OutputLeaseAmendment    `json:"lease_amendment,omitempty"`
+	TenantID            *string                  `json:"tenant_id,omitempty"             example:"990e8400-e29b-41d4-a716-446655440000"`
+	GuarantorID         *string                  `json:"guarantor_id,omitempty"          example:"aa1e8400-e29b-41d4-a716-446655440000"`
 	Role                string                   `json:"role"                            example:"TENANT"`
 	SignerName          *string                  `json:"signer_name,omitempty"           example:"Jane Doe"`
 	SignerEmail         *string                  `json:"signer_email,omitempty"          example:"jane@example.com"`
@@ -85,6 +116,9 @@ type OutputSigningToken struct {
 	SignedAt            *time.Time               `json:"signed_at,omitempty"             example:"2024-06-15T14:30:00Z"`
 	LastAccessedAt      *time.Time               `json:"last_accessed_at,omitempty"      example:"2024-06-14T10:00:00Z"`
 	ExpiresAt           time.Time                `json:"expires_at"                      example:"2024-06-22T09:00:00Z"`
+	OtpRequired         bool                     `json:"otp_required"                    example:"true"`
+	OtpVerifiedAt       *time.Time               `json:"otp_verified_at,omitempty"       example:"2024-06-15T14:20:00Z"`
+	OtpVerifiedVia      *string                  `json:"otp_verified_via,omitempty"      example:"SMS"`
 	DocumentSignatureID *string                  `json:"document_signature_id,omitempty" example:"990e8400-e29b-41d4-a716-446655440000"`
 	DocumentSignature   *OutputDocumentSignature `json:"document_signature,omitempty"`
 	CreatedAt           time.Time                `json:"created_at"                      example:"2024-06-01T09:00:00Z"`
@@ -100,6 +134,7 @@ func DBSigningTokenToRest(i *models.SigningToken) any {
 		"id":                    i.ID.String(),
 		"token":                 i.Token,
 		"document_id":           i.DocumentID,
+		"document_revision_id":  i.DocumentRevisionID,
 		"document":              DBDocumentToRestDocument(&i.Document),
 		"tenant_application_id": i.TenantApplicationID,
 		"tenant_application":    DBTenantApplicationToRest(i.TenantApplication),
@@ -107,6 +142,10 @@ func DBSigningTokenToRest(i *models.SigningToken) any {
 		"lease":                 DBLeaseToRest(i.Lease),
 		"lease_termination_id":  i.LeaseTerminationID,
 		"lease_termination":     DBLeaseTerminationToRest(i.LeaseTermination),
+		"lease_amendment_id":    i.LeaseAmendmentID,
+		"tenant_id":             i.TenantID,
+		"guarantor_id":          i.GuarantorID,
+		"lease_amendment":       DBLeaseAmendmentToRest(i.LeaseAmendment),
 		"role":                  i.Role,
 		"signer_name":           i.SignerName,
 		"signer_email":          i.SignerEmail,
@@ -114,6 +153,9 @@ func DBSigningTokenToRest(i *models.SigningToken) any {
 		"signed_at":             i.SignedAt,
 		"last_accessed_at":      i.LastAccessedAt,
 		"expires_at":            i.ExpiresAt,
+		"otp_required":          i.OtpRequired,
+		"otp_verified_at":       i.OtpVerifiedAt,
+		"otp_verified_via":      i.OtpVerifiedVia,
 		"document_signature_id": i.DocumentSignatureID,
 		"document_signature":    DBDocumentSignatureToRest(i.DocumentSignature),
 		"created_at":            i.CreatedAt,
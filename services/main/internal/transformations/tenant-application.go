@@ -22,7 +22,7 @@ type OutputAdminTenantApplication struct {
 	CancelledById *string           `json:"cancelled_by_id,omitempty" example:"user-456"`
 	CancelledBy   *OutputClientUser `json:"cancelled_by,omitempty"`
 
-	Source *string `json:"source,omitempty" example:"CSV_BULK"`
+	Source *string `json:"source,omitempty" example:"CSV_BULK" enums:"SELF,ADMIN,CSV_BULK,VIEWING"`
 
 	DesiredUnitId *string          `json:"desired_unit_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	DesiredUnit   *AdminOutputUnit `json:"desired_unit,omitempty"`
@@ -50,6 +50,9 @@ type OutputAdminTenantApplication struct {
 	LeaseAgreementDocumentStatus     *string                        `json:"lease_agreement_document_status,omitempty"     example:"DRAFT"`
 	LeaseAgreementDocumentSignatures []OutputAdminDocumentSignature `json:"lease_agreement_document_signatures,omitempty"`
 
+	// Guarantors is present only when asked for with populate=Guarantors.
+	Guarantors []OutputGuarantor `json:"guarantors,omitempty"`
+
 	FirstName       *string    `json:"first_name,omitempty"        example:"John"`
 	OtherNames      *string    `json:"other_names,omitempty"       example:"Michael"`
 	LastName        *string    `json:"last_name,omitempty"         example:"Doe"`
@@ -121,6 +124,7 @@ func DBAdminTenantApplicationToRest(i *models.TenantApplication) any {
 		"lease_agreement_document":            DBAdminDocumentToRestDocument(i.LeaseAgreementDocument),
 		"lease_agreement_document_status":     i.LeaseAgreementDocumentStatus,
 		"lease_agreement_document_signatures": DBAdminDocumentSignaturesToRest(&i.LeaseAgreementDocumentSignatures),
+		"guarantors":                          dbGuarantorsToRest(i.Guarantors),
 		"first_name":                          i.FirstName,
 		"other_names":                         i.OtherNames,
 		"last_name":                           i.LastName,
@@ -170,7 +174,7 @@ type OutputTenantApplication struct {
 	CompletedAt *time.Time `json:"completed_at,omitempty" example:"2024-06-01T12:00:00Z"`
 	CancelledAt *time.Time `json:"cancelled_at,omitempty" example:"2024-06-02T12:00:00Z"`
 
-	Source *string `json:"source,omitempty" example:"CSV_BULK"`
+	Source *string `json:"source,omitempty" example:"CSV_BULK" enums:"SELF,ADMIN,CSV_BULK,VIEWING"`
 
 	DesiredUnitId *string     `json:"desired_unit_id,omitempty" example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b"`
 	DesiredUnit   *OutputUnit `json:"desired_unit,omitempty"`
@@ -195,6 +199,9 @@ type OutputTenantApplication struct {
 	LeaseAgreementDocumentStatus     *string                   `json:"lease_agreement_document_status,omitempty"     example:"DRAFT"`
 	LeaseAgreementDocumentSignatures []OutputDocumentSignature `json:"lease_agreement_document_signatures,omitempty"`
 
+	// Guarantors is present only when asked for with populate=Guarantors.
+	Guarantors []OutputGuarantor `json:"guarantors,omitempty"`
+
 	FirstName       *string    `json:"first_name,omitempty"        example:"John"`
 	OtherNames      *string    `json:"other_names,omitempty"       example:"Michael"`
 	LastName        *string    `json:"last_name,omitempty"         example:"Doe"`
@@ -254,6 +261,7 @@ func DBTenantApplicationToRest(i *models.TenantApplication) any {
 		"lease_agreement_document_url":        i.LeaseAgreementDocumentUrl,
 		"lease_agreement_document_status":     i.LeaseAgreementDocumentStatus,
 		"lease_agreement_document_signatures": DBDocumentSignaturesToRest(&i.LeaseAgreementDocumentSignatures),
+		"guarantors":                          dbGuarantorsToRest(i.Guarantors),
 		"first_name":                          i.FirstName,
 		"other_names":                         i.OtherNames,
 		"last_name":                           i.LastName,
@@ -0,0 +1,60 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputTurnoverTask struct {
+	ID                      string     `json:"id"                                    example:"3f2b7c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c"`
+	PropertyID              string     `json:"property_id"                           example:"b4d0243c-6581-4104-8185-d83a45ebe41b"`
+	Property                any        `json:"property,omitempty"`
+	UnitID                  string     `json:"unit_id"                               example:"660e8400-e29b-41d4-a716-446655440000"`
+	Unit                    any        `json:"unit,omitempty"`
+	BookingID               *string    `json:"booking_id,omitempty"                  example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" description:"Booking whose checkout generated the task"`
+	LeaseID                 *string    `json:"lease_id,omitempty"                    example:"8d2f6a1b-3c4e-4f5a-9b6c-7d8e9f0a1b2c" description:"Lease whose completion generated the task"`
+	Type                    string     `json:"type"                                  example:"CLEANING"                                                                                     enums:"CLEANING,LINEN,INSPECTION"`
+	Status                  string     `json:"status"                                example:"PENDING"                                                                                      enums:"PENDING,DONE,CANCELLED"`
+	DueAt                   time.Time  `json:"due_at"                                example:"2026-10-20T11:00:00Z"`
+	AssignedToClientUserID  *string    `json:"assigned_to_client_user_id,omitempty"  example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	AssignedToClientUser    any        `json:"assigned_to_client_user,omitempty"`
+	Notes                   string     `json:"notes,omitempty"                       example:"Replaced a broken lamp shade"`
+	CompletedAt             *time.Time `json:"completed_at,omitempty"                example:"2026-10-19T15:30:00Z"`
+	CompletedByClientUserID *string    `json:"completed_by_client_user_id,omitempty" example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CanceledAt              *time.Time `json:"canceled_at,omitempty"                 example:"2026-10-19T15:30:00Z"`
+	CanceledByID            *string    `json:"canceled_by_id,omitempty"              example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CancellationReason      string     `json:"cancellation_reason,omitempty"         example:"Unit was not slept in"`
+	CreatedAt               time.Time  `json:"created_at"                            example:"2026-10-19T10:00:00Z"`
+	UpdatedAt               time.Time  `json:"updated_at"                            example:"2026-10-19T10:00:00Z"`
+}
+
+func DBTurnoverTaskToRest(i *models.TurnoverTask) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                          i.ID.String(),
+		"property_id":                 i.PropertyID,
+		"property":                    DBPropertyToRest(&i.Property),
+		"unit_id":                     i.UnitID,
+		"unit":                        DBUnitToRest(&i.Unit),
+		"booking_id":                  i.BookingID,
+		"lease_id":                    i.LeaseID,
+		"type":                        i.Type,
+		"status":                      i.Status,
+		"due_at":                      i.DueAt,
+		"assigned_to_client_user_id":  i.AssignedToClientUserID,
+		"assigned_to_client_user":     DBClientUserToRest(i.AssignedToClientUser),
+		"notes":                       i.Notes,
+		"completed_at":                i.CompletedAt,
+		"completed_by_client_user_id": i.CompletedByClientUserID,
+		"canceled_at":                 i.CanceledAt,
+		"canceled_by_id":              i.CanceledByID,
+		"cancellation_reason":         i.CancellationReason,
+		"created_at":                  i.CreatedAt,
+		"updated_at":                  i.UpdatedAt,
+	}
+}
@@ -0,0 +1,68 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputUnitCalendarSubscription struct {
+	ID                    string     `json:"id"                        example:"7b1c2f0e-3a5d-4e8b-9c61-0f2d4a6b8e10"`
+	UnitID                string     `json:"unit_id"                   example:"660e8400-e29b-41d4-a716-446655440000"`
+	PropertyID            string     `json:"property_id"               example:"550e8400-e29b-41d4-a716-446655440000"`
+	Name                  string     `json:"name"                      example:"Airbnb"`
+	URL                   string     `json:"url"                       example:"https://www.airbnb.com/calendar/ical/12345.ics?s=abc"`
+	LastSyncedAt          *time.Time `json:"last_synced_at,omitempty"  example:"2026-10-19T08:00:00Z"`
+	LastSyncStatus        string     `json:"last_sync_status"          example:"SUCCEEDED"`
+	LastSyncError         string     `json:"last_sync_error"           example:""`
+	LastEventCount        int64      `json:"last_event_count"          example:"4"`
+	CreatedByClientUserID string     `json:"created_by_client_user_id" example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CreatedAt             time.Time  `json:"created_at"                example:"2026-10-19T00:00:00Z"`
+	UpdatedAt             time.Time  `json:"updated_at"                example:"2026-10-19T00:00:00Z"`
+}
+
+func DBUnitCalendarSubscriptionToRest(i *models.UnitCalendarSubscription) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                        i.ID.String(),
+		"unit_id":                   i.UnitID,
+		"property_id":               i.PropertyID,
+		"name":                      i.Name,
+		"url":                       i.URL,
+		"last_synced_at":            i.LastSyncedAt,
+		"last_sync_status":          i.LastSyncStatus,
+		"last_sync_error":           i.LastSyncError,
+		"last_event_count":          i.LastEventCount,
+		"created_by_client_user_id": i.CreatedByClientUserID,
+		"created_at":                i.CreatedAt,
+		"updated_at":                i.UpdatedAt,
+	}
+}
+
+type OutputUnitCalendarExport struct {
+	Enabled bool `json:"enabled"             example:"true"`
+	// FeedPath is appended to the API's public address to give the URL
+	// channels import.
+	FeedPath *string `json:"feed_path,omitempty" example:"/api/v1/unit-calendars/q3T0yPq9b2m7x1VwZk4sLr8dE5nHfA6c.ics"`
+}
+
+func DBUnitToCalendarExportRest(i *models.Unit) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	var feedPath *string
+	if i.CalendarExportToken != nil {
+		path := "/api/v1/unit-calendars/" + *i.CalendarExportToken + ".ics"
+		feedPath = &path
+	}
+
+	return map[string]any{
+		"enabled":   i.CalendarExportToken != nil,
+		"feed_path": feedPath,
+	}
+}
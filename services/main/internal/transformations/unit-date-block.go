@@ -6,15 +6,17 @@ import (
 )
 
 type AdminOutputUnitDateBlock struct {
-	ID        string  `json:"id"`
-	UnitID    string  `json:"unit_id"`
-	StartDate string  `json:"start_date"`
-	EndDate   string  `json:"end_date"`
-	BlockType string  `json:"block_type"`
-	BookingID *string `json:"booking_id,omitempty"`
-	LeaseID   *string `json:"lease_id,omitempty"`
-	Reason    *string `json:"reason,omitempty"`
-	CreatedAt string  `json:"created_at"`
+	ID                     string  `json:"id"`
+	UnitID                 string  `json:"unit_id"`
+	StartDate              string  `json:"start_date"`
+	EndDate                string  `json:"end_date"`
+	BlockType              string  `json:"block_type"`
+	BookingID              *string `json:"booking_id,omitempty"`
+	LeaseID                *string `json:"lease_id,omitempty"`
+	CalendarSubscriptionID *string `json:"calendar_subscription_id,omitempty"`
+	ExternalUID            *string `json:"external_uid,omitempty"`
+	Reason                 *string `json:"reason,omitempty"`
+	CreatedAt              string  `json:"created_at"`
 }
 
 type PublicOutputUnitDateBlock struct {
@@ -31,15 +33,17 @@ func DBUnitDateBlockToRest(i *models.UnitDateBlock) any {
 	}
 
 	data := map[string]any{
-		"id":         i.ID.String(),
-		"unit_id":    i.UnitID,
-		"start_date": i.StartDate,
-		"end_date":   i.EndDate,
-		"block_type": i.BlockType,
-		"booking_id": i.BookingID,
-		"lease_id":   i.LeaseID,
-		"reason":     i.Reason,
-		"created_at": i.CreatedAt,
+		"id":                       i.ID.String(),
+		"unit_id":                  i.UnitID,
+		"start_date":               i.StartDate,
+		"end_date":                 i.EndDate,
+		"block_type":               i.BlockType,
+		"booking_id":               i.BookingID,
+		"lease_id":                 i.LeaseID,
+		"calendar_subscription_id": i.CalendarSubscriptionID,
+		"external_uid":             i.ExternalUID,
+		"reason":                   i.Reason,
+		"created_at":               i.CreatedAt,
 	}
 	return data
 }
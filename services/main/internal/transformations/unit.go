@@ -21,6 +21,8 @@ type AdminOutputUnit struct {
 	RentFeeCurrency     string              `json:"rent_fee_currency"     example:"USD"                                                     description:"Currency for the rent fee"`
 	PaymentFrequency    string              `json:"payment_frequency"     example:"MONTHLY"                                                 description:"Payment frequency (e.g., WEEKLY, DAILY, MONTHLY, Quarterly, BiAnnually, Annually)"`
 	MaxOccupantsAllowed int                 `json:"max_occupants_allowed" example:"4"                                                       description:"Maximum number of occupants allowed"`
+	BaseOccupancy       int                 `json:"base_occupancy"        example:"2"                                                       description:"Guests included in the rate; 0 charges no one extra"`
+	ExtraGuestFee       int64               `json:"extra_guest_fee"       example:"5000"                                                    description:"Charged per guest above the base occupancy per period of a booking"`
 	Features            map[string]any      `json:"features"                                                                                description:"Additional metadata in JSON format"`
 	PropertyID          string              `json:"property_id"           example:"b50874ee-1a70-436e-ba24-572078895982" format:"uuid"      description:"ID of the property this unit belongs to"`
 	Property            OutputProperty      `json:"property"`
@@ -51,6 +53,8 @@ func DBAdminUnitToRest(i *models.Unit) any {
 		"rent_fee_currency":     i.RentFeeCurrency,
 		"payment_frequency":     i.PaymentFrequency,
 		"max_occupants_allowed": i.MaxOccupantsAllowed,
+		"base_occupancy":        i.BaseOccupancy,
+		"extra_guest_fee":       i.ExtraGuestFee,
 		"features":              i.Features,
 		"property_id":           i.PropertyID,
 		"property":              DBPropertyToRest(&i.Property),
@@ -65,19 +69,22 @@ func DBAdminUnitToRest(i *models.Unit) any {
 }
 
 type OutputUnit struct {
-	ID               string         `json:"id"                example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" format:"uuid" description:"Unique identifier for the unit"`
-	Slug             string         `json:"slug"              example:"unit-101-abcde1876drkjy"                            description:"Slug for the unit"`
-	Name             string         `json:"name"              example:"Unit 101"                                           description:"Name of the unit"`
-	Description      *string        `json:"description"       example:"Spacious apartment with balcony"                    description:"Optional description of the unit"`
-	Images           []string       `json:"images"            example:"http://www.images/unit101.jpg"                      description:"List of image URLs for the unit"`
-	Tags             []string       `json:"tags"              example:"apartment,balcony"                                  description:"Tags associated with the unit"`
-	Type             string         `json:"type"              example:"APARTMENT"                                          description:"Type of the unit (e.g., APARTMENT, HOUSE, STUDIO, OFFICE, RETAIL)"`
-	Area             *float64       `json:"area"              example:"120.5"                                              description:"Area of the unit in square feet or square meters"`
-	RentFee          int64          `json:"rent_fee"          example:"1500"                                               description:"Monthly rent amount"`
-	RentFeeCurrency  string         `json:"rent_fee_currency" example:"USD"                                                description:"Currency for the rent fee"`
-	PaymentFrequency string         `json:"payment_frequency" example:"MONTHLY"                                            description:"Payment frequency (e.g., WEEKLY, DAILY, MONTHLY, Quarterly, BiAnnually, Annually)"`
-	Features         map[string]any `json:"features"                                                                       description:"Additional metadata in JSON format"`
-	Status           string         `json:"status"            example:"Unit.Status.Available"                              description:"Current status of the unit (e.g., Unit.Status.Available Unit.Status.Unavailable)"`
+	ID                  string         `json:"id"                    example:"4fce5dc8-8114-4ab2-a94b-b4536c27f43b" format:"uuid" description:"Unique identifier for the unit"`
+	Slug                string         `json:"slug"                  example:"unit-101-abcde1876drkjy"                            description:"Slug for the unit"`
+	Name                string         `json:"name"                  example:"Unit 101"                                           description:"Name of the unit"`
+	Description         *string        `json:"description"           example:"Spacious apartment with balcony"                    description:"Optional description of the unit"`
+	Images              []string       `json:"images"                example:"http://www.images/unit101.jpg"                      description:"List of image URLs for the unit"`
+	Tags                []string       `json:"tags"                  example:"apartment,balcony"                                  description:"Tags associated with the unit"`
+	Type                string         `json:"type"                  example:"APARTMENT"                                          description:"Type of the unit (e.g., APARTMENT, HOUSE, STUDIO, OFFICE, RETAIL)"`
+	Area                *float64       `json:"area"                  example:"120.5"                                              description:"Area of the unit in square feet or square meters"`
+	RentFee             int64          `json:"rent_fee"              example:"1500"                                               description:"Monthly rent amount"`
+	RentFeeCurrency     string         `json:"rent_fee_currency"     example:"USD"                                                description:"Currency for the rent fee"`
+	PaymentFrequency    string         `json:"payment_frequency"     example:"MONTHLY"                                            description:"Payment frequency (e.g., WEEKLY, DAILY, MONTHLY, Quarterly, BiAnnually, Annually)"`
+	MaxOccupantsAllowed int            `json:"max_occupants_allowed" example:"4"                                                  description:"Maximum number of occupants allowed"`
+	BaseOccupancy       int            `json:"base_occupancy"        example:"2"                                                  description:"Guests included in the rate; 0 charges no one extra"`
+	ExtraGuestFee       int64          `json:"extra_guest_fee"       example:"5000"                                               description:"Charged per guest above the base occupancy per period of a booking"`
+	Features            map[string]any `json:"features"                                                                           description:"Additional metadata in JSON format"`
+	Status              string         `json:"status"                example:"Unit.Status.Available"                              description:"Current status of the unit (e.g., Unit.Status.Available Unit.Status.Unavailable)"`
 }
 
 func DBUnitToRest(i *models.Unit) any {
@@ -93,19 +100,38 @@ func DBUnitToRest(i *models.Unit) any {
 	}
 
 	data := map[string]any{
-		"id":                i.ID.String(),
-		"slug":              i.Slug,
-		"name":              i.Name,
-		"description":       i.Description,
-		"images":            i.Images,
-		"tags":              i.Tags,
-		"type":              i.Type,
-		"area":              i.Area,
-		"rent_fee":          i.RentFee,
-		"rent_fee_currency": i.RentFeeCurrency,
-		"payment_frequency": i.PaymentFrequency,
-		"features":          i.Features,
-		"status":            status,
+		"id":                    i.ID.String(),
+		"slug":                  i.Slug,
+		"name":                  i.Name,
+		"description":           i.Description,
+		"images":                i.Images,
+		"tags":                  i.Tags,
+		"type":                  i.Type,
+		"area":                  i.Area,
+		"rent_fee":              i.RentFee,
+		"rent_fee_currency":     i.RentFeeCurrency,
+		"payment_frequency":     i.PaymentFrequency,
+		"max_occupants_allowed": i.MaxOccupantsAllowed,
+		"base_occupancy":        i.BaseOccupancy,
+		"extra_guest_fee":       i.ExtraGuestFee,
+		"features":              i.Features,
+		"status":                status,
+	}
+	return data
+}
+
+// for public search
+type OutputUnitListing struct {
+	OutputUnit
+	Property PublicOutputProperty `json:"property" description:"Property the unit is listed at"`
+}
+
+func DBUnitListingToRest(i *models.Unit) any {
+	data, ok := DBUnitToRest(i).(map[string]any)
+	if !ok {
+		return nil
 	}
+
+	data["property"] = DBPublicPropertyToRest(&i.Property)
 	return data
 }
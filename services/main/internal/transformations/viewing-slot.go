@@ -0,0 +1,68 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputViewingSlot struct {
+	ID           string     `json:"id"                       example:"3f2b7c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c"`
+	PropertyID   string     `json:"property_id"              example:"b4d0243c-6581-4104-8185-d83a45ebe41b"`
+	Property     any        `json:"property,omitempty"`
+	ClientUserID string     `json:"client_user_id"           example:"d290f1ee-6c54-4b01-90e6-d701748f0851" description:"Staff member who runs the viewings"`
+	ClientUser   any        `json:"client_user,omitempty"`
+	StartsAt     time.Time  `json:"starts_at"                example:"2026-10-24T10:00:00Z"`
+	EndsAt       time.Time  `json:"ends_at"                  example:"2026-10-24T11:00:00Z"`
+	Capacity     int64      `json:"capacity"                 example:"3"                                    description:"How many viewings can be booked into the slot"`
+	Status       string     `json:"status"                   example:"OPEN"                                                                                             enums:"OPEN,CANCELLED"`
+	CreatedByID  string     `json:"created_by_id"            example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CanceledAt   *time.Time `json:"canceled_at,omitempty"    example:"2026-10-20T09:00:00Z"`
+	CanceledByID *string    `json:"canceled_by_id,omitempty" example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CreatedAt    time.Time  `json:"created_at"               example:"2026-10-19T10:00:00Z"`
+	UpdatedAt    time.Time  `json:"updated_at"               example:"2026-10-19T10:00:00Z"`
+}
+
+func DBViewingSlotToRest(i *models.ViewingSlot) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":             i.ID.String(),
+		"property_id":    i.PropertyID,
+		"property":       DBPropertyToRest(&i.Property),
+		"client_user_id": i.ClientUserID,
+		"client_user":    DBClientUserToRest(&i.ClientUser),
+		"starts_at":      i.StartsAt,
+		"ends_at":        i.EndsAt,
+		"capacity":       i.Capacity,
+		"status":         i.Status,
+		"created_by_id":  i.CreatedByID,
+		"canceled_at":    i.CanceledAt,
+		"canceled_by_id": i.CanceledByID,
+		"created_at":     i.CreatedAt,
+		"updated_at":     i.UpdatedAt,
+	}
+}
+
+// PublicOutputViewingSlot is a slot as offered to prospects: just when it
+// runs. Who hosts it and how full it is stay private.
+type PublicOutputViewingSlot struct {
+	ID       string    `json:"id"        example:"3f2b7c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c"`
+	StartsAt time.Time `json:"starts_at" example:"2026-10-24T10:00:00Z"`
+	EndsAt   time.Time `json:"ends_at"   example:"2026-10-24T11:00:00Z"`
+}
+
+func DBPublicViewingSlotToRest(i *models.ViewingSlot) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":        i.ID.String(),
+		"starts_at": i.StartsAt,
+		"ends_at":   i.EndsAt,
+	}
+}
@@ -0,0 +1,122 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputViewing struct {
+	ID                  string     `json:"id"                              example:"3f2b7c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c"`
+	Code                string     `json:"code"                            example:"VW8K2Q"`
+	PropertyID          string     `json:"property_id"                     example:"b4d0243c-6581-4104-8185-d83a45ebe41b"`
+	Property            any        `json:"property,omitempty"`
+	UnitID              string     `json:"unit_id"                         example:"660e8400-e29b-41d4-a716-446655440000"`
+	Unit                any        `json:"unit,omitempty"`
+	ViewingSlotID       string     `json:"viewing_slot_id"                 example:"9c1d2e3f-4a5b-4c6d-8e7f-0a1b2c3d4e5f"`
+	ViewingSlot         any        `json:"viewing_slot,omitempty"`
+	ClientUserID        string     `json:"client_user_id"                  example:"d290f1ee-6c54-4b01-90e6-d701748f0851"       description:"Staff member who runs the viewing"`
+	ClientUser          any        `json:"client_user,omitempty"`
+	ScheduledAt         time.Time  `json:"scheduled_at"                    example:"2026-10-24T10:00:00Z"`
+	EndsAt              time.Time  `json:"ends_at"                         example:"2026-10-24T11:00:00Z"`
+	FirstName           string     `json:"first_name"                      example:"Ama"`
+	LastName            string     `json:"last_name"                       example:"Mensah"`
+	Email               *string    `json:"email,omitempty"                 example:"ama@example.com"`
+	Phone               string     `json:"phone"                           example:"+233241234567"`
+	Message             string     `json:"message,omitempty"               example:"Is parking included?"`
+	Status              string     `json:"status"                          example:"SCHEDULED"                                                                                  enums:"SCHEDULED,COMPLETED,NO_SHOW,CANCELLED"`
+	Notes               string     `json:"notes,omitempty"                 example:"Liked the unit, asked about a longer lease"`
+	CompletedAt         *time.Time `json:"completed_at,omitempty"          example:"2026-10-24T10:30:00Z"`
+	CompletedByID       *string    `json:"completed_by_id,omitempty"       example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	NoShowAt            *time.Time `json:"no_show_at,omitempty"            example:"2026-10-24T10:30:00Z"`
+	NoShowMarkedByID    *string    `json:"no_show_marked_by_id,omitempty"  example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CanceledAt          *time.Time `json:"canceled_at,omitempty"           example:"2026-10-20T09:00:00Z"`
+	CanceledByID        *string    `json:"canceled_by_id,omitempty"        example:"d290f1ee-6c54-4b01-90e6-d701748f0851"       description:"Empty when the prospect cancelled"`
+	CancellationReason  string     `json:"cancellation_reason,omitempty"   example:"The unit has been let"`
+	TenantApplicationID *string    `json:"tenant_application_id,omitempty" example:"8d2f6a1b-3c4e-4f5a-9b6c-7d8e9f0a1b2c"`
+	TenantApplication   any        `json:"tenant_application,omitempty"`
+	CreatedAt           time.Time  `json:"created_at"                      example:"2026-10-19T10:00:00Z"`
+	UpdatedAt           time.Time  `json:"updated_at"                      example:"2026-10-19T10:00:00Z"`
+}
+
+func DBViewingToRest(i *models.Viewing) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                    i.ID.String(),
+		"code":                  i.Code,
+		"property_id":           i.PropertyID,
+		"property":              DBPropertyToRest(&i.Property),
+		"unit_id":               i.UnitID,
+		"unit":                  DBUnitToRest(&i.Unit),
+		"viewing_slot_id":       i.ViewingSlotID,
+		"viewing_slot":          DBViewingSlotToRest(&i.ViewingSlot),
+		"client_user_id":        i.ClientUserID,
+		"client_user":           DBClientUserToRest(&i.ClientUser),
+		"scheduled_at":          i.ScheduledAt,
+		"ends_at":               i.EndsAt,
+		"first_name":            i.FirstName,
+		"last_name":             i.LastName,
+		"email":                 i.Email,
+		"phone":                 i.Phone,
+		"message":               i.Message,
+		"status":                i.Status,
+		"notes":                 i.Notes,
+		"completed_at":          i.CompletedAt,
+		"completed_by_id":       i.CompletedByID,
+		"no_show_at":            i.NoShowAt,
+		"no_show_marked_by_id":  i.NoShowMarkedByID,
+		"canceled_at":           i.CanceledAt,
+		"canceled_by_id":        i.CanceledByID,
+		"cancellation_reason":   i.CancellationReason,
+		"tenant_application_id": i.TenantApplicationID,
+		"tenant_application":    DBTenantApplicationToRest(i.TenantApplication),
+		"created_at":            i.CreatedAt,
+		"updated_at":            i.UpdatedAt,
+	}
+}
+
+// PublicOutputViewing is what a prospect sees of their own viewing. Staff
+// notes and who handled it stay private.
+type PublicOutputViewing struct {
+	ID                 string               `json:"id"                            example:"3f2b7c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c"`
+	Code               string               `json:"code"                          example:"VW8K2Q"`
+	Property           PublicOutputProperty `json:"property,omitempty"`
+	UnitID             string               `json:"unit_id"                       example:"660e8400-e29b-41d4-a716-446655440000"`
+	Unit               OutputUnit           `json:"unit,omitempty"`
+	ScheduledAt        time.Time            `json:"scheduled_at"                  example:"2026-10-24T10:00:00Z"`
+	EndsAt             time.Time            `json:"ends_at"                       example:"2026-10-24T11:00:00Z"`
+	FirstName          string               `json:"first_name"                    example:"Ama"`
+	LastName           string               `json:"last_name"                     example:"Mensah"`
+	Email              *string              `json:"email,omitempty"               example:"ama@example.com"`
+	Phone              string               `json:"phone"                         example:"+233241234567"`
+	Status             string               `json:"status"                        example:"SCHEDULED"                            enums:"SCHEDULED,COMPLETED,NO_SHOW,CANCELLED"`
+	CancellationReason string               `json:"cancellation_reason,omitempty" example:"The unit has been let"`
+	CreatedAt          time.Time            `json:"created_at"                    example:"2026-10-19T10:00:00Z"`
+}
+
+func DBPublicViewingToRest(i *models.Viewing) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                  i.ID.String(),
+		"code":                i.Code,
+		"property":            DBPublicPropertyToRest(&i.Property),
+		"unit_id":             i.UnitID,
+		"unit":                DBUnitToRest(&i.Unit),
+		"scheduled_at":        i.ScheduledAt,
+		"ends_at":             i.EndsAt,
+		"first_name":          i.FirstName,
+		"last_name":           i.LastName,
+		"email":               i.Email,
+		"phone":               i.Phone,
+		"status":              i.Status,
+		"cancellation_reason": i.CancellationReason,
+		"created_at":          i.CreatedAt,
+	}
+}
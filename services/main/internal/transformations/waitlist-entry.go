@@ -0,0 +1,119 @@
+package transformations
+
+import (
+	"time"
+
+	"github.com/Bendomey/rent-loop/services/main/internal/models"
+	"github.com/gofrs/uuid"
+)
+
+type OutputWaitlistEntry struct {
+	ID                  string     `json:"id"                              example:"3f2b7c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c"`
+	PropertyID          string     `json:"property_id"                     example:"b4d0243c-6581-4104-8185-d83a45ebe41b"`
+	Property            any        `json:"property,omitempty"`
+	UnitType            *string    `json:"unit_type,omitempty"             example:"APARTMENT"                            enums:"APARTMENT,HOUSE,STUDIO,OFFICE,RETAIL"`
+	UnitID              *string    `json:"unit_id,omitempty"               example:"660e8400-e29b-41d4-a716-446655440000"`
+	Unit                any        `json:"unit,omitempty"`
+	FirstName           string     `json:"first_name"                      example:"Ama"`
+	LastName            string     `json:"last_name"                       example:"Mensah"`
+	Email               *string    `json:"email,omitempty"                 example:"ama@example.com"`
+	Phone               string     `json:"phone"                           example:"+233241234567"`
+	DesiredMoveInFrom   *time.Time `json:"desired_move_in_from,omitempty"  example:"2026-11-01T00:00:00Z"`
+	DesiredMoveInTo     *time.Time `json:"desired_move_in_to,omitempty"    example:"2026-12-31T00:00:00Z"`
+	MaxRentFee          *int64     `json:"max_rent_fee,omitempty"          example:"300000"`
+	Priority            int64      `json:"priority"                        example:"0"                                                                                    description:"Higher goes first; ties go to whoever joined first"`
+	Status              string     `json:"status"                          example:"WAITING"                              enums:"WAITING,INVITED,APPLIED,EXPIRED,REMOVED"`
+	Notes               string     `json:"notes,omitempty"                 example:"Prefers a ground floor unit"`
+	VacancyNotifiedAt   *time.Time `json:"vacancy_notified_at,omitempty"   example:"2026-10-19T10:00:00Z"`
+	InvitedUnitID       *string    `json:"invited_unit_id,omitempty"       example:"660e8400-e29b-41d4-a716-446655440000"`
+	InvitedUnit         any        `json:"invited_unit,omitempty"`
+	InvitedAt           *time.Time `json:"invited_at,omitempty"            example:"2026-10-19T10:00:00Z"`
+	InviteExpiresAt     *time.Time `json:"invite_expires_at,omitempty"     example:"2026-10-21T10:00:00Z"`
+	TenantApplicationID *string    `json:"tenant_application_id,omitempty" example:"8d2f6a1b-3c4e-4f5a-9b6c-7d8e9f0a1b2c"`
+	TenantApplication   any        `json:"tenant_application,omitempty"`
+	AppliedAt           *time.Time `json:"applied_at,omitempty"            example:"2026-10-20T09:00:00Z"`
+	RemovedAt           *time.Time `json:"removed_at,omitempty"            example:"2026-10-20T09:00:00Z"`
+	RemovedByID         *string    `json:"removed_by_id,omitempty"         example:"d290f1ee-6c54-4b01-90e6-d701748f0851"`
+	CreatedAt           time.Time  `json:"created_at"                      example:"2026-10-19T10:00:00Z"`
+	UpdatedAt           time.Time  `json:"updated_at"                      example:"2026-10-19T10:00:00Z"`
+}
+
+func DBWaitlistEntryToRest(i *models.WaitlistEntry) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                    i.ID.String(),
+		"property_id":           i.PropertyID,
+		"property":              DBPropertyToRest(&i.Property),
+		"unit_type":             i.UnitType,
+		"unit_id":               i.UnitID,
+		"unit":                  DBUnitToRest(i.Unit),
+		"first_name":            i.FirstName,
+		"last_name":             i.LastName,
+		"email":                 i.Email,
+		"phone":                 i.Phone,
+		"desired_move_in_from":  i.DesiredMoveInFrom,
+		"desired_move_in_to":    i.DesiredMoveInTo,
+		"max_rent_fee":          i.MaxRentFee,
+		"priority":              i.Priority,
+		"status":                i.Status,
+		"notes":                 i.Notes,
+		"vacancy_notified_at":   i.VacancyNotifiedAt,
+		"invited_unit_id":       i.InvitedUnitID,
+		"invited_unit":          DBUnitToRest(i.InvitedUnit),
+		"invited_at":            i.InvitedAt,
+		"invite_expires_at":     i.InviteExpiresAt,
+		"tenant_application_id": i.TenantApplicationID,
+		"tenant_application":    DBTenantApplicationToRest(i.TenantApplication),
+		"applied_at":            i.AppliedAt,
+		"removed_at":            i.RemovedAt,
+		"removed_by_id":         i.RemovedByID,
+		"created_at":            i.CreatedAt,
+		"updated_at":            i.UpdatedAt,
+	}
+}
+
+// PublicOutputWaitlistEntry is what a prospect sees of their own entry: the
+// place they joined and, once invited, the unit and when the invite runs out.
+// The manager's priority and notes stay private.
+type PublicOutputWaitlistEntry struct {
+	ID              string               `json:"id"                          example:"3f2b7c1e-9a4d-4e6b-8c2a-1d5e7f9a0b3c"`
+	PropertyID      string               `json:"property_id"                 example:"b4d0243c-6581-4104-8185-d83a45ebe41b"`
+	Property        PublicOutputProperty `json:"property,omitempty"`
+	UnitType        *string              `json:"unit_type,omitempty"         example:"APARTMENT"`
+	UnitID          *string              `json:"unit_id,omitempty"           example:"660e8400-e29b-41d4-a716-446655440000"`
+	FirstName       string               `json:"first_name"                  example:"Ama"`
+	LastName        string               `json:"last_name"                   example:"Mensah"`
+	Email           *string              `json:"email,omitempty"             example:"ama@example.com"`
+	Phone           string               `json:"phone"                       example:"+233241234567"`
+	Status          string               `json:"status"                      example:"INVITED"                              enums:"WAITING,INVITED,APPLIED,EXPIRED,REMOVED"`
+	InvitedUnitID   *string              `json:"invited_unit_id,omitempty"   example:"660e8400-e29b-41d4-a716-446655440000"`
+	InvitedUnit     OutputUnit           `json:"invited_unit,omitempty"`
+	InviteExpiresAt *time.Time           `json:"invite_expires_at,omitempty" example:"2026-10-21T10:00:00Z"`
+	CreatedAt       time.Time            `json:"created_at"                  example:"2026-10-19T10:00:00Z"`
+}
+
+func DBPublicWaitlistEntryToRest(i *models.WaitlistEntry) any {
+	if i == nil || i.ID == uuid.Nil {
+		return nil
+	}
+
+	return map[string]any{
+		"id":                i.ID.String(),
+		"property_id":       i.PropertyID,
+		"property":          DBPublicPropertyToRest(&i.Property),
+		"unit_type":         i.UnitType,
+		"unit_id":           i.UnitID,
+		"first_name":        i.FirstName,
+		"last_name":         i.LastName,
+		"email":             i.Email,
+		"phone":             i.Phone,
+		"status":            i.Status,
+		"invited_unit_id":   i.InvitedUnitID,
+		"invited_unit":      DBUnitToRest(i.InvitedUnit),
+		"invite_expires_at": i.InviteExpiresAt,
+		"created_at":        i.CreatedAt,
+	}
+}